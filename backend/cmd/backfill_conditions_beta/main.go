@@ -0,0 +1,67 @@
+// Command backfill_conditions_beta extracts conditions beta (seeps,
+// morning_sun, ...) for Mountain Project areas and routes whose comments
+// were stored before migration 000045_add_conditions_beta. The comment sync
+// only extracts beta for targets whose comments it re-syncs, so without this
+// tool older targets stay untagged until their comments change.
+//
+// It reads comments already in the database and makes no Mountain Project
+// requests. Targets that already have beta are skipped, so it is safe to
+// re-run after an interruption.
+//
+// See README.md in this directory for usage.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/service"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Count the areas and routes missing conditions beta without writing")
+	flag.Parse()
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Conditions Beta Backfill Tool ===")
+	if *dryRun {
+		log.Println("DRY RUN MODE: no rows will be modified")
+	}
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// SIGINT handling — stop after the current target.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// The client is never called: beta comes from stored comments only
+	climbService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mountainproject.NewClient(), nil, nil)
+
+	start := time.Now()
+	n, err := climbService.BackfillConditionsBeta(ctx, *dryRun)
+	if err != nil {
+		log.Fatalf("Backfill stopped after %d target(s): %v", n, err)
+	}
+
+	if *dryRun {
+		log.Printf("✓ %d target(s) would be backfilled", n)
+		return
+	}
+	log.Printf("✓ Backfilled %d target(s) in %s", n, time.Since(start).Round(time.Millisecond))
+}
@@ -20,12 +20,11 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/geo"
 )
 
@@ -45,11 +44,10 @@ func main() {
 	force := flag.Bool("force", false, "Re-derive every row, including those whose current timezone is not the migration default")
 	flag.Parse()
 
-	// Load .env (try cwd then parent, mirroring backfill_radiation_dewpoint).
-	if err := godotenv.Load(); err != nil {
-		if err2 := godotenv.Load("../.env"); err2 != nil {
-			log.Printf("Warning: .env file not found: %v", err)
-		}
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	log.Println("=== Location Timezone Backfill Tool ===")
@@ -66,17 +64,7 @@ func main() {
 	}
 	log.Println()
 
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("DB_HOST"),
-		getEnvOrDefault("DB_PORT", "5432"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		getEnvOrDefault("DB_SSLMODE", "require"),
-	)
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -179,10 +167,3 @@ func loadLocations(db *sql.DB, onlyID int) ([]locationRow, error) {
 	}
 	return out, rs.Err()
 }
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return defaultValue
-}
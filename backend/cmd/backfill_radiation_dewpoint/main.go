@@ -14,10 +14,8 @@ package main
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"os"
@@ -27,8 +25,10 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 )
 
 const (
@@ -73,11 +73,10 @@ func main() {
 	rateLimitMs := flag.Int("rate-limit-ms", 1100, "Sleep between Open-Meteo calls (free tier ~600 req/min)")
 	flag.Parse()
 
-	// Load .env (try cwd then parent, mirroring sync_tree_cover/sync_kaya).
-	if err := godotenv.Load(); err != nil {
-		if err2 := godotenv.Load("../.env"); err2 != nil {
-			log.Printf("Warning: .env file not found: %v", err)
-		}
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	log.Println("=== Radiation & Dewpoint Backfill Tool ===")
@@ -91,18 +90,7 @@ func main() {
 	log.Printf("Batch size: %d (informational)", *batchSize)
 	log.Println()
 
-	// DB connection (same env-driven pattern as sync_tree_cover/main.go).
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("DB_HOST"),
-		getEnvOrDefault("DB_PORT", "5432"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		getEnvOrDefault("DB_SSLMODE", "require"),
-	)
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -354,29 +342,10 @@ func fetchArchive(client *http.Client, lat, lon float64, start, end time.Time) (
 		end.Format("2006-01-02"),
 	)
 
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Do(req)
+	ar, err := httpx.GetJSON[archiveResponse](context.Background(), client, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("http: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("read body: %w", err)
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var ar archiveResponse
-	if err := json.Unmarshal(body, &ar); err != nil {
-		return nil, fmt.Errorf("decode: %w (body: %s)", err, string(body))
-	}
 	if ar.Error {
 		return nil, fmt.Errorf("api error: %s", ar.Reason)
 	}
@@ -490,10 +459,3 @@ func applyUpdates(ctx context.Context, db *sql.DB, locationID int, resp *archive
 func celsiusToFahrenheit(c float64) float64 {
 	return c*9.0/5.0 + 32.0
 }
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return defaultValue
-}
@@ -0,0 +1,178 @@
+// Command dedupe_areas finds Mountain Project areas whose hierarchy links are
+// inconsistent and, with -fix, repairs them.
+//
+// mp_areas.mp_area_id is unique, so re-syncing with overlapping configs
+// cannot create a second row for an area. What it does leave behind is
+// broken links: the last sync to touch an area wins its parent and
+// location_id, so overlapping roots can leave areas pointing at themselves,
+// at parents that were never stored, or at a different location than their
+// parent. This tool reports:
+//
+//   - self_parent: the area is its own parent
+//   - orphan: the parent area is not stored
+//   - cycle: following parents leads back to the area
+//   - location_mismatch: location_id differs from the parent's
+//
+// By default it only reports. With -fix, in a single transaction, it detaches
+// self-parented and orphaned areas (making them roots; a later sync that
+// reaches them from a stored parent links them again) and copies each
+// parent's location_id down the hierarchy. Cycles are reported but not
+// changed; re-sync the affected location to repair them. Location mismatches
+// are left alone while cycles exist, since copying locations around a cycle
+// never settles.
+//
+// Usage:
+//
+//	go run ./cmd/dedupe_areas
+//	go run ./cmd/dedupe_areas -fix
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+)
+
+// maxLocationPasses bounds the location reconciliation loop. Each pass fixes
+// one level of the hierarchy, and real MP hierarchies are far shallower.
+const maxLocationPasses = 64
+
+func main() {
+	fix := flag.Bool("fix", false, "Repair the reported areas (default is a dry run)")
+	sampleSize := flag.Int("sample", 20, "Number of areas to print per problem")
+	flag.Parse()
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Mountain Project Area Link Check ===")
+	if !*fix {
+		log.Println("DRY RUN MODE: no rows will be modified (pass -fix to repair)")
+	}
+	log.Println()
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	problems, err := db.MountainProject().Areas().GetAreaLinkProblems(ctx)
+	if err != nil {
+		log.Fatalf("Failed to check area links: %v", err)
+	}
+
+	byProblem := make(map[string][]mountainproject.AreaLinkProblem)
+	for _, p := range problems {
+		byProblem[p.Problem] = append(byProblem[p.Problem], p)
+	}
+
+	kinds := []string{
+		mountainproject.AreaProblemSelfParent,
+		mountainproject.AreaProblemOrphan,
+		mountainproject.AreaProblemCycle,
+		mountainproject.AreaProblemLocationMismatch,
+	}
+	for _, kind := range kinds {
+		report(kind, byProblem[kind], *sampleSize)
+	}
+
+	if len(problems) == 0 {
+		log.Println("No inconsistent areas found")
+		return
+	}
+	if !*fix {
+		return
+	}
+
+	var detach []int64
+	for _, kind := range []string{mountainproject.AreaProblemSelfParent, mountainproject.AreaProblemOrphan} {
+		for _, p := range byProblem[kind] {
+			detach = append(detach, p.MPAreaID)
+		}
+	}
+	reconcileLocations := len(byProblem[mountainproject.AreaProblemCycle]) == 0
+
+	var detached, relocated int64
+	err = database.WithTransaction(ctx, db.Conn(), func(tx *sql.Tx) error {
+		areas := mountainproject.NewPostgresRepository(tx).Areas()
+
+		var err error
+		detached, err = areas.DetachAreaParents(ctx, detach)
+		if err != nil {
+			return fmt.Errorf("detach parents: %w", err)
+		}
+
+		if !reconcileLocations {
+			return nil
+		}
+		for pass := 1; ; pass++ {
+			if pass > maxLocationPasses {
+				return fmt.Errorf("location reconciliation did not settle after %d passes", maxLocationPasses)
+			}
+			n, err := areas.ReconcileAreaLocations(ctx)
+			if err != nil {
+				return fmt.Errorf("reconcile locations (pass %d): %w", pass, err)
+			}
+			if n == 0 {
+				return nil
+			}
+			relocated += n
+		}
+	})
+	if err != nil {
+		log.Fatalf("Fix failed, no changes were made: %v", err)
+	}
+
+	log.Println()
+	log.Printf("Detached %d area(s) from missing or self-referencing parents", detached)
+	if reconcileLocations {
+		log.Printf("Updated location_id on %d area(s)", relocated)
+	} else {
+		log.Printf("Skipped location fixes: repair the %d area(s) in parent cycles first by re-syncing their location",
+			len(byProblem[mountainproject.AreaProblemCycle]))
+	}
+}
+
+// report logs how many areas have a problem and a sample of them.
+func report(kind string, problems []mountainproject.AreaLinkProblem, sampleSize int) {
+	log.Printf("%s: %d area(s)", kind, len(problems))
+	for i, p := range problems {
+		if i == sampleSize {
+			log.Printf("  ... and %d more", len(problems)-sampleSize)
+			break
+		}
+		line := fmt.Sprintf("  area %d %q: parent %s, location_id %s",
+			p.MPAreaID, p.Name, formatAreaID(p.ParentMPAreaID), formatLocationID(p.LocationID))
+		if kind == mountainproject.AreaProblemLocationMismatch {
+			line += fmt.Sprintf(" (parent's %s)", formatLocationID(p.ParentLocationID))
+		}
+		log.Print(line)
+	}
+}
+
+func formatAreaID(id *int64) string {
+	if id == nil {
+		return "NULL"
+	}
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatLocationID(id *int) string {
+	if id == nil {
+		return "NULL"
+	}
+	return strconv.Itoa(*id)
+}
@@ -0,0 +1,85 @@
+// Command export_location writes one location's climbing data to a JSON file
+// that import_location can load into another database.
+//
+// The dump holds the location, its sun exposure profile, its Mountain
+// Project areas and routes, their comments, ticks from the last -tick-days
+// days, and the routes' boulder drying profiles. Weather is not included;
+// run sync_weather after importing.
+//
+// Usage:
+//
+//	go run ./cmd/export_location -id 3 -file gold-bar.json
+//	go run ./cmd/export_location -id 3 -file gold-bar.json -tick-days 30
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/locationdump"
+)
+
+func main() {
+	locationID := flag.Int("id", 0, "Location ID to export (required)")
+	file := flag.String("file", "", "Output JSON file (required)")
+	tickDays := flag.Int("tick-days", 365, "Include ticks climbed within this many days")
+	flag.Parse()
+
+	if *locationID <= 0 {
+		log.Fatal("Error: -id is required")
+	}
+	if *file == "" {
+		log.Fatal("Error: -file is required")
+	}
+	if *tickDays < 0 {
+		log.Fatal("Error: -tick-days must not be negative")
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Location Export Tool ===")
+	log.Println()
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	dump, err := locationdump.Export(ctx, locationdump.NewPostgresRepos(db.Conn()), *locationID, now.AddDate(0, 0, -*tickDays), now)
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+
+	f, err := os.Create(*file)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", *file, err)
+	}
+	if err := locationdump.Write(f, dump); err != nil {
+		f.Close()
+		log.Fatalf("Failed to write %s: %v", *file, err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalf("Failed to write %s: %v", *file, err)
+	}
+
+	log.Printf("Exported %q (id=%d) to %s:", dump.Location.Name, dump.Location.ID, *file)
+	log.Printf("  %d area(s), %d route(s), %d tick(s) since %s, %d comment(s), %d drying profile(s)",
+		len(dump.MPAreas), len(dump.Routes), len(dump.Ticks), dump.TicksSince.Format("2006-01-02"),
+		len(dump.Comments), len(dump.DryingProfiles))
+	if dump.SunExposure == nil {
+		log.Println("  no sun exposure profile")
+	}
+}
@@ -0,0 +1,97 @@
+// Command import_location loads a JSON file written by export_location into
+// this database, in a single transaction: either everything is upserted or
+// nothing is.
+//
+// The location is upserted by name and placed in the woulder area with the
+// exported area's name, which must already exist. Areas, routes, ticks,
+// comments and drying profiles are upserted by their Mountain Project IDs, so
+// importing the same file twice is safe. Route sync timestamps are left
+// alone, so the background sync still refreshes imported routes.
+//
+// Usage:
+//
+//	go run ./cmd/import_location -file gold-bar.json -dry-run
+//	go run ./cmd/import_location -file gold-bar.json
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/locationdump"
+)
+
+func main() {
+	file := flag.String("file", "", "JSON file written by export_location (required)")
+	dryRun := flag.Bool("dry-run", false, "Validate the file and report its contents without writing")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Error: -file is required")
+	}
+
+	log.Println("=== Location Import Tool ===")
+	if *dryRun {
+		log.Println("DRY RUN MODE: nothing will be written")
+	}
+	log.Println()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	dump, err := locationdump.Read(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Invalid dump %s: %v", *file, err)
+	}
+
+	log.Printf("%s: %q in area %q, exported %s", *file, dump.Location.Name, dump.AreaName,
+		dump.ExportedAt.Format("2006-01-02 15:04"))
+	log.Printf("  %d area(s), %d route(s), %d tick(s), %d comment(s), %d drying profile(s)",
+		len(dump.MPAreas), len(dump.Routes), len(dump.Ticks), len(dump.Comments), len(dump.DryingProfiles))
+	if *dryRun {
+		return
+	}
+	log.Println()
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	var res *locationdump.Result
+	err = database.WithTransaction(ctx, db.Conn(), func(tx *sql.Tx) error {
+		var err error
+		res, err = locationdump.Import(ctx, locationdump.NewPostgresRepos(tx), dump)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("Import failed, no changes were made: %v", err)
+	}
+
+	action := "Updated"
+	if res.LocationCreated {
+		action = "Created"
+	}
+	log.Printf("%s location %q (id=%d)", action, dump.Location.Name, res.LocationID)
+	log.Printf("Upserted %d area(s), %d route(s), %d tick(s), %d comment(s), %d drying profile(s)",
+		res.MPAreas, res.Routes, res.Ticks, res.Comments, res.DryingProfiles)
+	if res.LocationCreated {
+		log.Printf("Run sync_weather -location-id %d to fetch its weather", res.LocationID)
+	}
+}
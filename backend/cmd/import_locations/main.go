@@ -0,0 +1,243 @@
+// Command import_locations adds or updates woulder locations in bulk from a
+// CSV file, instead of hand-written SQL.
+//
+// The file needs a header row naming at least name, lat, lon and area
+// (area is an area ID or name from woulder.areas); elevation_ft is optional.
+// Rows are upserted by name: a new name creates a location, an existing one
+// is moved to the row's coordinates and area. Each location's timezone is
+// derived from its coordinates, the same as LocationService.CreateLocation.
+// Malformed rows (bad or out-of-range coordinates, unknown areas, duplicate
+// names) are reported and skipped; the rest are still imported.
+//
+// With -weather, each newly created location gets an immediate weather
+// refresh (history, forecast and daily aggregates) instead of waiting for the
+// next background refresh. With -tree-cover, each new location's tree
+// coverage is fetched from Google Earth Engine and stored on its sun exposure
+// profile; without Earth Engine credentials this is skipped, since the
+// drying calculations already fall back to GPS-based estimates.
+//
+// Usage:
+//
+//	go run ./cmd/import_locations -file locations.csv -dry-run
+//	go run ./cmd/import_locations -file locations.csv -weather -tree-cover
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/service"
+	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
+)
+
+// importedLocation is a location written by this run.
+type importedLocation struct {
+	row locationRow
+	id  int
+}
+
+func main() {
+	file := flag.String("file", "", "CSV file of locations to import (required)")
+	dryRun := flag.Bool("dry-run", false, "Validate the file and report what would be imported without writing")
+	fetchWeather := flag.Bool("weather", false, "Refresh weather for each newly created location")
+	treeCover := flag.Bool("tree-cover", false, "Fetch and store tree coverage for each newly created location")
+	rateLimitMs := flag.Int("rate-limit-ms", 1100, "Sleep between weather refreshes (Open-Meteo free tier ~600 req/min)")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("Error: -file is required")
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Location Import Tool ===")
+	if *dryRun {
+		log.Println("DRY RUN MODE: no rows will be written and nothing will be fetched")
+	}
+	log.Println()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	rows, rowErrs, err := readLocationRows(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	areas, err := db.Areas().GetAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load areas: %v", err)
+	}
+	areaIDs := resolveAreas(rows, areas, &rowErrs)
+
+	log.Printf("Read %d valid row(s) from %s", len(areaIDs), *file)
+	for _, e := range rowErrs {
+		log.Printf("  ✗ skipped %v", e)
+	}
+	log.Println()
+
+	locationService := service.NewLocationService(db.Locations(), db.Areas())
+
+	var created []importedLocation
+	updated, failed := 0, 0
+	for _, row := range rows {
+		areaID, ok := areaIDs[row.Line]
+		if !ok {
+			continue
+		}
+
+		if *dryRun {
+			log.Printf("WOULD upsert %q at (%.6f, %.6f) in area %d", row.Name, row.Latitude, row.Longitude, areaID)
+			continue
+		}
+
+		id, isNew, err := locationService.UpsertLocation(ctx, models.Location{
+			Name:        row.Name,
+			Latitude:    row.Latitude,
+			Longitude:   row.Longitude,
+			ElevationFt: row.ElevationFt,
+			AreaID:      areaID,
+		})
+		if err != nil {
+			log.Printf("✗ line %d %q: %v", row.Line, row.Name, err)
+			failed++
+			continue
+		}
+		if isNew {
+			log.Printf("✓ created location %d %q", id, row.Name)
+			created = append(created, importedLocation{row: row, id: id})
+		} else {
+			log.Printf("✓ updated location %d %q", id, row.Name)
+			updated++
+		}
+	}
+
+	if !*dryRun && len(created) > 0 {
+		if *fetchWeather {
+			weatherService := service.NewWeatherService(db.Weather(), db.Locations(), db.Rocks(),
+				weather.NewWeatherService(cfg.Weather.OpenWeatherMapAPIKey), nil)
+			failed += refreshWeather(ctx, weatherService, created, time.Duration(*rateLimitMs)*time.Millisecond)
+		}
+		if *treeCover {
+			failed += syncTreeCover(ctx, db, created)
+		}
+	}
+
+	log.Println()
+	log.Println("=== Import Complete ===")
+	if *dryRun {
+		log.Printf("Rows that would be upserted: %d", len(areaIDs))
+	} else {
+		log.Printf("Created: %d", len(created))
+		log.Printf("Updated: %d", updated)
+		log.Printf("Failures: %d", failed)
+	}
+	log.Printf("Skipped rows: %d", len(rowErrs))
+
+	if failed > 0 || len(rowErrs) > 0 {
+		os.Exit(1)
+	}
+}
+
+// resolveAreas maps each row's line to the ID of the area it names, by ID or
+// case-insensitive name among active areas. Rows naming no known area are
+// appended to rowErrs and left out of the result.
+func resolveAreas(rows []locationRow, areas []models.Area, rowErrs *[]rowError) map[int]int {
+	byID := make(map[int]bool, len(areas))
+	byName := make(map[string]int, len(areas))
+	for _, a := range areas {
+		byID[a.ID] = true
+		byName[strings.ToLower(a.Name)] = a.ID
+	}
+
+	resolved := make(map[int]int, len(rows))
+	for _, row := range rows {
+		if id, err := strconv.Atoi(row.Area); err == nil {
+			if byID[id] {
+				resolved[row.Line] = id
+				continue
+			}
+		} else if id, ok := byName[strings.ToLower(row.Area)]; ok {
+			resolved[row.Line] = id
+			continue
+		}
+		*rowErrs = append(*rowErrs, rowError{Line: row.Line, Err: fmt.Errorf("unknown area %q", row.Area)})
+	}
+	return resolved
+}
+
+// refreshWeather refreshes weather for each new location, pausing between
+// locations to stay under the Open-Meteo rate limit. Returns the number of
+// locations whose refresh failed.
+func refreshWeather(ctx context.Context, weatherService *service.WeatherService, locations []importedLocation, pause time.Duration) int {
+	log.Println()
+	log.Printf("Refreshing weather for %d new location(s)...", len(locations))
+
+	failed := 0
+	for i, loc := range locations {
+		if err := weatherService.RefreshLocationWeather(ctx, loc.id); err != nil {
+			log.Printf("[%d/%d] ✗ weather for %q: %v", i+1, len(locations), loc.row.Name, err)
+			failed++
+		} else {
+			log.Printf("[%d/%d] ✓ weather for %q", i+1, len(locations), loc.row.Name)
+		}
+
+		if i < len(locations)-1 {
+			time.Sleep(pause)
+		}
+	}
+	return failed
+}
+
+// syncTreeCover stores tree coverage for each new location. Returns the
+// number of locations that failed.
+func syncTreeCover(ctx context.Context, db *database.Database, locations []importedLocation) int {
+	log.Println()
+	treeClient := boulder_drying.NewTreeCoverClient()
+	if !treeClient.IsEnabled() {
+		log.Println("Skipping tree coverage: Google Earth Engine is not configured (drying uses GPS-based estimates)")
+		return 0
+	}
+	log.Printf("Fetching tree coverage for %d new location(s)...", len(locations))
+
+	failed := 0
+	for i, loc := range locations {
+		fetchCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		coverage, err := treeClient.GetTreeCoverage(fetchCtx, loc.row.Latitude, loc.row.Longitude)
+		cancel()
+		if err == nil {
+			err = db.Rocks().SetLocationTreeCoverage(ctx, loc.id, coverage)
+		}
+		if err != nil {
+			log.Printf("[%d/%d] ✗ tree coverage for %q: %v", i+1, len(locations), loc.row.Name, err)
+			failed++
+			continue
+		}
+		log.Printf("[%d/%d] ✓ %q: %.1f%% tree coverage", i+1, len(locations), loc.row.Name, coverage)
+	}
+	return failed
+}
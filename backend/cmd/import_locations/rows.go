@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// locationRow is one valid row of the import file.
+type locationRow struct {
+	Line        int // 1-based line in the file, for reporting
+	Name        string
+	Latitude    float64
+	Longitude   float64
+	Area        string // area ID or name, resolved against woulder.areas
+	ElevationFt int
+}
+
+// rowError reports why a line of the import file was skipped.
+type rowError struct {
+	Line int
+	Err  error
+}
+
+func (e rowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// headerAliases maps accepted header names to their column.
+var headerAliases = map[string]string{
+	"name":         "name",
+	"lat":          "lat",
+	"latitude":     "lat",
+	"lon":          "lon",
+	"lng":          "lon",
+	"longitude":    "lon",
+	"area":         "area",
+	"area_id":      "area",
+	"elevation_ft": "elevation_ft",
+	"elevation":    "elevation_ft",
+}
+
+// requiredColumns must all be present in the header.
+var requiredColumns = []string{"name", "lat", "lon", "area"}
+
+// readLocationRows parses an import CSV with a header row naming at least
+// name, lat, lon and area (elevation_ft is optional; see headerAliases for
+// accepted spellings). Valid rows are returned in file order. Malformed rows
+// are returned as rowErrors and do not stop the parse; a missing or
+// unreadable header is a hard error.
+func readLocationRows(r io.Reader) ([]locationRow, []rowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // row length is checked per row below
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read header: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if col, ok := headerAliases[h]; ok {
+			if _, dup := columns[col]; dup {
+				return nil, nil, fmt.Errorf("header names column %q more than once", col)
+			}
+			columns[col] = i
+		}
+	}
+	for _, col := range requiredColumns {
+		if _, ok := columns[col]; !ok {
+			return nil, nil, fmt.Errorf("header is missing required column %q", col)
+		}
+	}
+
+	var rows []locationRow
+	var rowErrs []rowError
+	seenNames := make(map[string]int)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrs = append(rowErrs, rowError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, nil, fmt.Errorf("read line %d: %w", line, err)
+		}
+		if isBlank(record) {
+			continue
+		}
+
+		row, err := parseLocationRow(record, columns)
+		if err != nil {
+			rowErrs = append(rowErrs, rowError{Line: line, Err: err})
+			continue
+		}
+		row.Line = line
+
+		key := strings.ToLower(row.Name)
+		if first, dup := seenNames[key]; dup {
+			rowErrs = append(rowErrs, rowError{Line: line, Err: fmt.Errorf("duplicate name %q (first on line %d)", row.Name, first)})
+			continue
+		}
+		seenNames[key] = line
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrs, nil
+}
+
+// parseLocationRow validates one record.
+func parseLocationRow(record []string, columns map[string]int) (locationRow, error) {
+	field := func(col string) string {
+		i, ok := columns[col]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	row := locationRow{Name: field("name"), Area: field("area")}
+	if row.Name == "" {
+		return row, errors.New("name is empty")
+	}
+	if len(row.Name) > 255 {
+		return row, errors.New("name is longer than 255 characters")
+	}
+	if row.Area == "" {
+		return row, errors.New("area is empty")
+	}
+
+	var err error
+	if row.Latitude, err = parseCoordinate(field("lat"), "latitude", 90); err != nil {
+		return row, err
+	}
+	if row.Longitude, err = parseCoordinate(field("lon"), "longitude", 180); err != nil {
+		return row, err
+	}
+	if row.Latitude == 0 && row.Longitude == 0 {
+		return row, errors.New("coordinates are 0,0")
+	}
+
+	if s := field("elevation_ft"); s != "" {
+		elevation, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(elevation) {
+			return row, fmt.Errorf("elevation_ft %q is not a number", s)
+		}
+		if elevation < -1000 || elevation > 30000 {
+			return row, fmt.Errorf("elevation_ft %s is outside -1000..30000", s)
+		}
+		row.ElevationFt = int(math.Round(elevation))
+	}
+
+	return row, nil
+}
+
+// parseCoordinate parses a decimal-degree coordinate within ±limit.
+func parseCoordinate(s, name string, limit float64) (float64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%s is empty", name)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%s %q is not a number", name, s)
+	}
+	if v < -limit || v > limit {
+		return 0, fmt.Errorf("%s %s is outside ±%g", name, s, limit)
+	}
+	return v, nil
+}
+
+func isBlank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestReadLocationRows(t *testing.T) {
+	input := "\ufeffName, Latitude, lng, Area_ID, elevation\n" +
+		"Gold Bar, 47.8566, -121.6401, 1, 1200.4\n" +
+		"\n" +
+		"Bad Lat, 95, -121.0, 1,\n" +
+		"Bad Lon, 47.0, abc, 1,\n" +
+		"Null Island, 0, 0, 1,\n" +
+		", 47.0, -121.0, 1,\n" +
+		"No Area, 47.0, -121.0, ,\n" +
+		"gold bar, 47.9, -121.7, 1,\n" +
+		"Too High, 47.0, -121.0, 1, 40000\n" +
+		"Index, 47.82, -121.55, Skykomish\n"
+
+	rows, rowErrs, err := readLocationRows(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readLocationRows() error = %v", err)
+	}
+
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2: %+v", len(rows), rows)
+	}
+	want := locationRow{Line: 2, Name: "Gold Bar", Latitude: 47.8566, Longitude: -121.6401, Area: "1", ElevationFt: 1200}
+	if rows[0] != want {
+		t.Errorf("rows[0] = %+v, want %+v", rows[0], want)
+	}
+	if rows[1].Name != "Index" || rows[1].Area != "Skykomish" || rows[1].Line != 11 {
+		t.Errorf("rows[1] = %+v, want Index on line 11 in area Skykomish", rows[1])
+	}
+
+	wantErrs := map[int]string{
+		4:  "latitude 95 is outside",
+		5:  `longitude "abc" is not a number`,
+		6:  "coordinates are 0,0",
+		7:  "name is empty",
+		8:  "area is empty",
+		9:  `duplicate name "gold bar" (first on line 2)`,
+		10: "elevation_ft 40000 is outside",
+	}
+	if len(rowErrs) != len(wantErrs) {
+		t.Fatalf("got %d row errors, want %d: %v", len(rowErrs), len(wantErrs), rowErrs)
+	}
+	for _, e := range rowErrs {
+		want, ok := wantErrs[e.Line]
+		if !ok {
+			t.Errorf("unexpected error on line %d: %v", e.Line, e.Err)
+			continue
+		}
+		if !strings.Contains(e.Err.Error(), want) {
+			t.Errorf("line %d error = %q, want it to contain %q", e.Line, e.Err, want)
+		}
+	}
+}
+
+func TestReadLocationRows_HeaderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty file", "", "read header"},
+		{"missing area", "name,lat,lon\nA,1,2\n", `missing required column "area"`},
+		{"duplicate column", "name,lat,latitude,lon,area\n", `column "lat" more than once`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := readLocationRows(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("readLocationRows() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAreas(t *testing.T) {
+	areas := []models.Area{{ID: 1, Name: "Skykomish"}, {ID: 2, Name: "Leavenworth"}}
+	rows := []locationRow{
+		{Line: 2, Area: "2"},
+		{Line: 3, Area: "skykomish"},
+		{Line: 4, Area: "99"},
+		{Line: 5, Area: "Squamish"},
+	}
+
+	var rowErrs []rowError
+	got := resolveAreas(rows, areas, &rowErrs)
+
+	if len(got) != 2 || got[2] != 2 || got[3] != 1 {
+		t.Errorf("resolveAreas() = %v, want map[2:2 3:1]", got)
+	}
+	if len(rowErrs) != 2 || rowErrs[0].Line != 4 || rowErrs[1].Line != 5 {
+		t.Errorf("row errors = %v, want unknown areas on lines 4 and 5", rowErrs)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/database"
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/alexscott64/woulder/backend/internal/service"
@@ -16,7 +17,12 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
-	db, err := database.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("load configuration: %v", err)
+	}
+
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		log.Fatalf("connect database: %v", err)
 	}
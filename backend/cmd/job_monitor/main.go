@@ -1,22 +1,48 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+	"github.com/alexscott64/woulder/backend/internal/progress"
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
 // MonitorClient handles API communication
 type MonitorClient struct {
-	baseURL string
-	client  *http.Client
+	baseURL    string
+	client     *http.Client
+	jsonOutput bool   // print raw API structs as JSON instead of tables (--json)
+	apiToken   string // admin access token for commands that change jobs (cancel)
+}
+
+// supportedAPIVersion is the server API version (GET /api/version) whose
+// response formats this CLI was written against.
+const supportedAPIVersion = 1
+
+// errUnexpectedFormat is returned when a response doesn't have the shape the
+// CLI expects, usually because the server runs a different API version.
+var errUnexpectedFormat = errors.New("server returned unexpected format (version mismatch?)")
+
+// versionResponse is the body of GET /api/version
+type versionResponse struct {
+	APIVersion    int    `json:"api_version"`
+	Commit        string `json:"commit"`
+	BuildTime     string `json:"build_time"`
+	Modified      bool   `json:"modified"`
+	SchemaVersion *int   `json:"schema_version"`
 }
 
 // JobExecution represents a job execution from the API
@@ -39,11 +65,55 @@ type JobExecution struct {
 	Metadata                  map[string]interface{} `json:"metadata"`
 }
 
+// pruneResponse is the body of POST /api/monitoring/jobs/prune
+type pruneResponse struct {
+	Deleted int64     `json:"deleted"`
+	Cutoff  time.Time `json:"cutoff"`
+}
+
+// jobsResponse is the envelope returned by the active and history endpoints
+type jobsResponse struct {
+	Jobs []*JobExecution `json:"jobs"`
+}
+
+func (r *jobsResponse) validate() error {
+	for _, job := range r.Jobs {
+		if job == nil {
+			return errors.New("null entry in jobs")
+		}
+	}
+	return nil
+}
+
 // JobsSummary represents summary response
 type JobsSummary struct {
 	Summary map[string]*JobSummaryItem `json:"summary"`
 }
 
+func (s *JobsSummary) validate() error {
+	for name, item := range s.Summary {
+		if item == nil {
+			return fmt.Errorf("null summary for job %q", name)
+		}
+	}
+	return nil
+}
+
+// validator is implemented by response types that check their decoded shape
+// beyond what json.Unmarshal does.
+type validator interface {
+	validate() error
+}
+
+// Summary sort orders accepted by `summary --sort`
+const (
+	sortByName    = "name"
+	sortByLastRun = "last-run"
+)
+
+// validStatuses are the job statuses accepted by --status
+var validStatuses = []string{"running", "completed", "failed", "cancelled", "paused"}
+
 // JobSummaryItem contains summary for a job
 type JobSummaryItem struct {
 	LastRun         *time.Time `json:"last_run"`
@@ -61,55 +131,88 @@ func main() {
 	}
 
 	client := &MonitorClient{
-		baseURL: baseURL,
-		client:  &http.Client{Timeout: 10 * time.Second},
+		baseURL:  baseURL,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		apiToken: os.Getenv("WOULDER_API_TOKEN"),
 	}
 
+	var apiVersion int
 	rootCmd := &cobra.Command{
 		Use:   "job_monitor",
 		Short: "Monitor Woulder background jobs",
 		Long:  "A CLI tool to monitor Woulder's background sync jobs on remote servers",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// `version` reports a mismatch rather than failing on it
+			if apiVersion == 0 || cmd.Name() == "version" {
+				return nil
+			}
+			return client.checkAPIVersion(apiVersion)
+		},
 	}
+	rootCmd.PersistentFlags().IntVar(&apiVersion, "api-version", supportedAPIVersion, "API version the server must report at /api/version (0 skips the check)")
+	rootCmd.PersistentFlags().BoolVar(&client.jsonOutput, "json", false, "Print results as JSON instead of tables (watch prints one JSON object per line per update)")
 
 	// Active jobs command
+	var activeStatus string
 	activeCmd := &cobra.Command{
 		Use:   "active",
 		Short: "Show all active (running) jobs",
-		Run: func(cmd *cobra.Command, args []string) {
-			client.showActiveJobs()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStatus(activeStatus); err != nil {
+				return err
+			}
+			client.showActiveJobs(activeStatus)
+			return nil
 		},
 	}
+	activeCmd.Flags().StringVar(&activeStatus, "status", "", "Only show jobs with this status ("+strings.Join(validStatuses, ", ")+")")
 
 	// Watch command (real-time updates)
+	var watchJobName string
+	var watchUntilDone bool
 	watchCmd := &cobra.Command{
 		Use:   "watch",
 		Short: "Watch active jobs in real-time (updates every 2 seconds)",
 		Run: func(cmd *cobra.Command, args []string) {
-			client.watchJobs()
+			client.watchJobs(watchJobName, watchUntilDone)
 		},
 	}
+	watchCmd.Flags().StringVar(&watchJobName, "job", "", "Only watch jobs with this name")
+	watchCmd.Flags().BoolVar(&watchUntilDone, "until-done", false, "Stop once no jobs are active, exiting non-zero unless every watched job completed")
 
 	// History command
 	var historyJobName string
 	var historyLimit int
+	var historyStatus string
 	historyCmd := &cobra.Command{
 		Use:   "history",
 		Short: "Show job execution history",
-		Run: func(cmd *cobra.Command, args []string) {
-			client.showHistory(historyJobName, historyLimit)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateStatus(historyStatus); err != nil {
+				return err
+			}
+			client.showHistory(historyJobName, historyLimit, historyStatus)
+			return nil
 		},
 	}
 	historyCmd.Flags().StringVar(&historyJobName, "job", "", "Filter by job name")
-	historyCmd.Flags().IntVar(&historyLimit, "limit", 10, "Number of recent jobs to show")
+	historyCmd.Flags().IntVar(&historyLimit, "limit", 10, "Number of recent jobs to fetch (applied before --status)")
+	historyCmd.Flags().StringVar(&historyStatus, "status", "", "Only show jobs with this status ("+strings.Join(validStatuses, ", ")+")")
 
 	// Summary command
+	var summarySort string
 	summaryCmd := &cobra.Command{
 		Use:   "summary",
 		Short: "Show summary of all job types",
-		Run: func(cmd *cobra.Command, args []string) {
-			client.showSummary()
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if summarySort != sortByName && summarySort != sortByLastRun {
+				return fmt.Errorf("invalid --sort %q (must be %s or %s)", summarySort, sortByName, sortByLastRun)
+			}
+			client.showSummary(summarySort)
+			return nil
 		},
 	}
+	summaryCmd.Flags().StringVar(&summarySort, "sort", sortByName, "Sort rows by job name (name) or most recent run first (last-run)")
 
 	// Status command for specific job
 	var statusJobID int64
@@ -123,7 +226,44 @@ func main() {
 	statusCmd.Flags().Int64Var(&statusJobID, "id", 0, "Job execution ID")
 	statusCmd.MarkFlagRequired("id")
 
-	rootCmd.AddCommand(activeCmd, watchCmd, historyCmd, summaryCmd, statusCmd)
+	// Cancel command
+	var cancelJobID int64
+	cancelCmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "Cancel a running job execution (needs an admin token in WOULDER_API_TOKEN)",
+		Run: func(cmd *cobra.Command, args []string) {
+			client.showCancel(cancelJobID)
+		},
+	}
+	cancelCmd.Flags().Int64Var(&cancelJobID, "id", 0, "Job execution ID")
+	cancelCmd.MarkFlagRequired("id")
+
+	// Prune command
+	var pruneDays int
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete finished job executions older than --days (needs an admin token in WOULDER_API_TOKEN)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pruneDays < 1 {
+				return fmt.Errorf("invalid --days %d (must be at least 1)", pruneDays)
+			}
+			client.showPrune(pruneDays)
+			return nil
+		},
+	}
+	pruneCmd.Flags().IntVar(&pruneDays, "days", 0, "Delete jobs that finished more than this many days ago")
+	pruneCmd.MarkFlagRequired("days")
+
+	// Version command
+	versionCmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show the server's build and database schema version",
+		Run: func(cmd *cobra.Command, args []string) {
+			client.showVersion()
+		},
+	}
+
+	rootCmd.AddCommand(activeCmd, watchCmd, historyCmd, summaryCmd, statusCmd, cancelCmd, pruneCmd, versionCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -131,13 +271,18 @@ func main() {
 	}
 }
 
-func (c *MonitorClient) showActiveJobs() {
+func (c *MonitorClient) showActiveJobs(status string) {
 	jobs, err := c.getActiveJobs()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	jobs = filterJobsByStatus(jobs, status)
 
+	if c.jsonOutput {
+		printJSON(jobsForJSON(jobs))
+		return
+	}
 	if len(jobs) == 0 {
 		fmt.Println("No active jobs running")
 		return
@@ -146,59 +291,121 @@ func (c *MonitorClient) showActiveJobs() {
 	c.printJobs(jobs)
 }
 
-func (c *MonitorClient) watchJobs() {
-	fmt.Println("Watching active jobs (press Ctrl+C to stop)...")
-	fmt.Println()
+// watchJobs redraws the active jobs every 2 seconds, or with --json prints
+// one watchTick per update. With untilDone it stops once no jobs are active
+// and exits with exitForWatchedJobs.
+func (c *MonitorClient) watchJobs(jobName string, untilDone bool) {
+	if !c.jsonOutput {
+		fmt.Println("Watching active jobs (press Ctrl+C to stop)...")
+		fmt.Println()
+	}
 
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
-	for {
-		// Clear screen
-		fmt.Print("\033[H\033[2J")
+	// IDs of every job seen active, to check how each one ended
+	watched := make(map[int64]bool)
 
-		jobs, err := c.getActiveJobs()
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			time.Sleep(2 * time.Second)
-			continue
+	for {
+		jobs, fetchErr := c.getActiveJobs()
+		jobs = filterJobsByName(jobs, jobName)
+		for _, job := range jobs {
+			watched[job.ID] = true
 		}
 
-		fmt.Printf("Active Jobs (updated %s)\n", time.Now().Format("15:04:05"))
-		fmt.Println(strings.Repeat("=", 80))
-
-		if len(jobs) == 0 {
-			fmt.Println("No active jobs running")
+		if c.jsonOutput {
+			if err := writeWatchTick(os.Stdout, time.Now(), jobs, fetchErr); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
 		} else {
-			c.printJobs(jobs)
+			// Clear screen
+			fmt.Print("\033[H\033[2J")
+
+			if fetchErr != nil {
+				fmt.Printf("Error: %v\n", fetchErr)
+			} else {
+				fmt.Printf("Active Jobs (updated %s)\n", time.Now().Format("15:04:05"))
+				fmt.Println(strings.Repeat("=", 80))
+
+				if len(jobs) == 0 {
+					fmt.Println("No active jobs running")
+				} else {
+					c.printJobs(jobs)
+				}
+			}
+		}
+
+		if untilDone && fetchErr == nil && len(jobs) == 0 {
+			break
 		}
 
 		<-ticker.C
 	}
+
+	c.exitForWatchedJobs(watched, jobName)
 }
 
-func (c *MonitorClient) showHistory(jobName string, limit int) {
-	url := fmt.Sprintf("%s/api/monitoring/jobs/history?limit=%d", c.baseURL, limit)
-	if jobName != "" {
-		url += fmt.Sprintf("&job_name=%s", jobName)
+// exitForWatchedJobs exits 0 if every watched job completed, 1 otherwise.
+func (c *MonitorClient) exitForWatchedJobs(watched map[int64]bool, jobName string) {
+	// The result goes to stderr with --json, keeping stdout one tick per line
+	out := os.Stdout
+	if c.jsonOutput {
+		out = os.Stderr
 	}
 
-	resp, err := c.client.Get(url)
+	finished, err := c.getFinishedJobs(watched, jobName)
+	if err == nil {
+		err = checkFinishedJobs(finished)
+	}
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		fmt.Fprintf(out, "%v\n", err)
 		os.Exit(1)
 	}
-	defer resp.Body.Close()
+	fmt.Fprintf(out, "All %d watched job(s) completed\n", len(finished))
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	var result struct {
-		Jobs []*JobExecution `json:"jobs"`
+// getFinishedJobs fetches the current state of each watched job, in ID
+// order. If no job was active during the watch, it returns the most recent
+// execution (of jobName, if set) instead.
+func (c *MonitorClient) getFinishedJobs(watched map[int64]bool, jobName string) ([]*JobExecution, error) {
+	if len(watched) == 0 {
+		result, err := getJSON[jobsResponse](c, historyPath(jobName, 1), "jobs")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the most recent job: %w", err)
+		}
+		return result.Jobs, nil
 	}
-	if err := json.Unmarshal(body, &result); err != nil {
-		fmt.Printf("Error parsing response: %v\n", err)
+
+	ids := make([]int64, 0, len(watched))
+	for id := range watched {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	finished := make([]*JobExecution, 0, len(ids))
+	for _, id := range ids {
+		job, err := getJSON[JobExecution](c, fmt.Sprintf("/api/monitoring/jobs/%d", id), "id")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %d: %w", id, err)
+		}
+		finished = append(finished, &job)
+	}
+	return finished, nil
+}
+
+func (c *MonitorClient) showHistory(jobName string, limit int, status string) {
+	result, err := getJSON[jobsResponse](c, historyPath(jobName, limit), "jobs")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	result.Jobs = filterJobsByStatus(result.Jobs, status)
 
+	if c.jsonOutput {
+		printJSON(jobsForJSON(result.Jobs))
+		return
+	}
 	if len(result.Jobs) == 0 {
 		fmt.Println("No job history found")
 		return
@@ -208,7 +415,7 @@ func (c *MonitorClient) showHistory(jobName string, limit int) {
 	table.Append([]string{"ID", "Job Name", "Status", "Started", "Duration", "Items"})
 
 	for _, job := range result.Jobs {
-		duration := formatDuration(job.ElapsedSeconds)
+		duration := progress.FormatDuration(job.ElapsedSeconds)
 		items := fmt.Sprintf("%d/%d", job.ItemsProcessed, job.TotalItems)
 
 		table.Append([]string{
@@ -224,27 +431,23 @@ func (c *MonitorClient) showHistory(jobName string, limit int) {
 	table.Render()
 }
 
-func (c *MonitorClient) showSummary() {
-	url := fmt.Sprintf("%s/api/monitoring/jobs/summary", c.baseURL)
-
-	resp, err := c.client.Get(url)
+func (c *MonitorClient) showSummary(sortBy string) {
+	summary, err := getJSON[JobsSummary](c, "/api/monitoring/jobs/summary", "summary")
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
-	var summary JobsSummary
-	if err := json.Unmarshal(body, &summary); err != nil {
-		fmt.Printf("Error parsing response: %v\n", err)
-		os.Exit(1)
+	if c.jsonOutput {
+		printJSON(summary)
+		return
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
 	table.Append([]string{"Job Name", "Status", "Last Run", "Duration", "Next Run"})
 
-	for jobName, item := range summary.Summary {
+	for _, jobName := range sortedJobNames(summary.Summary, sortBy) {
+		item := summary.Summary[jobName]
 		lastRun := "Never"
 		if item.LastRun != nil {
 			lastRun = item.LastRun.Format("01-02 15:04")
@@ -252,7 +455,7 @@ func (c *MonitorClient) showSummary() {
 
 		duration := "-"
 		if item.DurationSeconds != nil {
-			duration = formatDuration(*item.DurationSeconds)
+			duration = progress.FormatDuration(*item.DurationSeconds)
 		}
 
 		nextRun := "-"
@@ -278,25 +481,19 @@ func (c *MonitorClient) showSummary() {
 }
 
 func (c *MonitorClient) showStatus(jobID int64) {
-	url := fmt.Sprintf("%s/api/monitoring/jobs/%d", c.baseURL, jobID)
-
-	resp, err := c.client.Get(url)
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+	job, err := getJSON[JobExecution](c, fmt.Sprintf("/api/monitoring/jobs/%d", jobID), "id")
+	if httpx.HasStatus(err, http.StatusNotFound) {
+		fmt.Printf("Job not found (ID: %d)\n", jobID)
 		os.Exit(1)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Job not found (ID: %d)\n", jobID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	body, _ := io.ReadAll(resp.Body)
-	var job JobExecution
-	if err := json.Unmarshal(body, &job); err != nil {
-		fmt.Printf("Error parsing response: %v\n", err)
-		os.Exit(1)
+	if c.jsonOutput {
+		printJSON(job)
+		return
 	}
 
 	fmt.Printf("Job Execution #%d\n", job.ID)
@@ -312,10 +509,10 @@ func (c *MonitorClient) showStatus(jobID int64) {
 		fmt.Printf("Completed:       %s\n", job.CompletedAt.Format("2006-01-02 15:04:05"))
 	}
 
-	fmt.Printf("Elapsed:         %s\n", formatDuration(job.ElapsedSeconds))
+	fmt.Printf("Elapsed:         %s\n", progress.FormatDuration(job.ElapsedSeconds))
 
 	if job.EstimatedRemainingSeconds != nil {
-		fmt.Printf("Est. Remaining:  %s\n", formatDuration(*job.EstimatedRemainingSeconds))
+		fmt.Printf("Est. Remaining:  %s\n", progress.FormatDuration(*job.EstimatedRemainingSeconds))
 	}
 
 	if job.ItemsPerSecond != nil {
@@ -325,26 +522,188 @@ func (c *MonitorClient) showStatus(jobID int64) {
 	if job.ErrorMessage != nil {
 		fmt.Printf("\nError: %s\n", *job.ErrorMessage)
 	}
+
+	if len(job.Metadata) > 0 {
+		fmt.Println("\nMetadata:")
+		writeMetadata(os.Stdout, job.Metadata, "  ")
+	}
+}
+
+func (c *MonitorClient) showCancel(jobID int64) {
+	job, err := c.cancelJob(jobID)
+	switch {
+	case httpx.HasStatus(err, http.StatusNotFound):
+		fmt.Printf("Job not found (ID: %d)\n", jobID)
+		os.Exit(1)
+	case httpx.HasStatus(err, http.StatusConflict):
+		fmt.Printf("Job %d is not running or paused, so it can't be cancelled\n", jobID)
+		os.Exit(1)
+	case httpx.HasStatus(err, http.StatusUnauthorized, http.StatusForbidden):
+		fmt.Println("Cancelling jobs needs an admin access token in WOULDER_API_TOKEN")
+		os.Exit(1)
+	case err != nil:
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if c.jsonOutput {
+		printJSON(job)
+		return
+	}
+
+	fmt.Printf("Cancelled job #%d (%s) at %d/%d items\n", job.ID, job.JobName, job.ItemsProcessed, job.TotalItems)
+	fmt.Println("It stops at its next status check, within about 10 seconds.")
+}
+
+func (c *MonitorClient) showPrune(days int) {
+	result, err := c.pruneJobs(days)
+	if httpx.HasStatus(err, http.StatusUnauthorized, http.StatusForbidden) {
+		fmt.Println("Pruning jobs needs an admin access token in WOULDER_API_TOKEN")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if c.jsonOutput {
+		printJSON(result)
+		return
+	}
+
+	fmt.Printf("Deleted %d finished job executions from before %s\n", result.Deleted, result.Cutoff.Local().Format("2006-01-02 15:04"))
+}
+
+func (c *MonitorClient) showVersion() {
+	version, err := getJSON[versionResponse](c, "/api/version", "api_version")
+	if httpx.HasStatus(err, http.StatusNotFound) {
+		fmt.Println("Server does not report a version (older server?)")
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if c.jsonOutput {
+		printJSON(version)
+		return
+	}
+
+	commit := version.Commit
+	if version.Modified {
+		commit += " (modified)"
+	}
+	schema := "unknown"
+	if version.SchemaVersion != nil {
+		schema = fmt.Sprintf("%d", *version.SchemaVersion)
+	}
+
+	fmt.Printf("API Version:     %d (CLI supports %d)\n", version.APIVersion, supportedAPIVersion)
+	fmt.Printf("Commit:          %s\n", commit)
+	fmt.Printf("Built:           %s\n", version.BuildTime)
+	fmt.Printf("Schema Version:  %s\n", schema)
 }
 
 func (c *MonitorClient) getActiveJobs() ([]*JobExecution, error) {
-	url := fmt.Sprintf("%s/api/monitoring/jobs/active", c.baseURL)
+	result, err := getJSON[jobsResponse](c, "/api/monitoring/jobs/active", "jobs")
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Jobs, nil
+}
+
+// checkAPIVersion fails unless the server reports API version want. Servers
+// that predate /api/version only get a warning, since most commands still
+// work against them.
+func (c *MonitorClient) checkAPIVersion(want int) error {
+	version, err := getJSON[versionResponse](c, "/api/version", "api_version")
+	if httpx.HasStatus(err, http.StatusNotFound) {
+		fmt.Fprintln(os.Stderr, "Warning: server does not report an API version (older server?); some fields may be missing")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check API version: %w", err)
+	}
+	if version.APIVersion != want {
+		return fmt.Errorf("server API version is %d, expected %d (update job_monitor, or pass --api-version 0 to skip the check)",
+			version.APIVersion, want)
+	}
+	return nil
+}
 
-	resp, err := c.client.Get(url)
+// cancelJob asks the server to cancel a job execution and returns the job as
+// it was after cancelling.
+func (c *MonitorClient) cancelJob(jobID int64) (*JobExecution, error) {
+	job, err := doJSON[JobExecution](c, http.MethodPost, fmt.Sprintf("/api/monitoring/jobs/%d/cancel", jobID), "id")
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &job, nil
+}
+
+// pruneJobs asks the server to delete finished job executions older than
+// days days.
+func (c *MonitorClient) pruneJobs(days int) (pruneResponse, error) {
+	return doJSON[pruneResponse](c, http.MethodPost, fmt.Sprintf("/api/monitoring/jobs/prune?days=%d", days), "deleted")
+}
+
+// getJSON fetches an API path and decodes the JSON object it returns with
+// decodeResponse.
+func getJSON[T any](c *MonitorClient, path string, required ...string) (T, error) {
+	return doJSON[T](c, http.MethodGet, path, required...)
+}
+
+// doJSON sends a bodyless request to an API path and decodes the JSON object
+// it returns with decodeResponse. The access token, if set, is sent as a
+// bearer token.
+func doJSON[T any](c *MonitorClient, method, path string, required ...string) (T, error) {
+	var zero T
+	req, err := http.NewRequestWithContext(context.Background(), method, c.baseURL+path, nil)
+	if err != nil {
+		return zero, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	}
+
+	body, err := httpx.Do(c.client, req)
+	if err != nil {
+		return zero, err
+	}
+	return decodeResponse[T](body, required...)
+}
 
-	body, _ := io.ReadAll(resp.Body)
-	var result struct {
-		Jobs []*JobExecution `json:"jobs"`
+// decodeResponse decodes a JSON object into T. Unknown fields are ignored
+// and missing ones left zero, so small API changes don't break the CLI, but
+// the body must be an object with every key in required, and must pass T's
+// validate method if it has one. Any other shape is reported as
+// errUnexpectedFormat rather than a raw parse error.
+func decodeResponse[T any](body []byte, required ...string) (T, error) {
+	var result T
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return result, fmt.Errorf("%w: response is not a JSON object", errUnexpectedFormat)
+	}
+	for _, key := range required {
+		if _, ok := fields[key]; !ok {
+			return result, fmt.Errorf("%w: missing %q field", errUnexpectedFormat, key)
+		}
 	}
+
 	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+		return result, fmt.Errorf("%w: %v", errUnexpectedFormat, err)
+	}
+	if v, ok := any(&result).(validator); ok {
+		if err := v.validate(); err != nil {
+			return result, fmt.Errorf("%w: %v", errUnexpectedFormat, err)
+		}
 	}
 
-	return result.Jobs, nil
+	return result, nil
 }
 
 func (c *MonitorClient) printJobs(jobs []*JobExecution) {
@@ -356,22 +715,25 @@ func (c *MonitorClient) printJobs(jobs []*JobExecution) {
 		fmt.Printf("╔══════════════════════════════════════════════════════════════════╗\n")
 		fmt.Printf("║ Job: %-59s ║\n", job.JobName)
 		fmt.Printf("║ Type: %-58s ║\n", job.JobType)
+		if current := currentItemName(job); current != "" {
+			fmt.Printf("║ Current: %-55s ║\n", truncate(current, 55))
+		}
 		fmt.Printf("║ Progress: %d/%d (%.1f%%)%*s║\n",
 			job.ItemsProcessed, job.TotalItems, job.ProgressPercent,
 			40-len(fmt.Sprintf("%d/%d (%.1f%%)", job.ItemsProcessed, job.TotalItems, job.ProgressPercent)), "")
 
 		// Progress bar
-		progressBar := makeProgressBar(job.ProgressPercent, 60)
+		progressBar := progress.Bar(job.ProgressPercent, 60)
 		fmt.Printf("║ %s ║\n", progressBar)
 
 		fmt.Printf("║ Success: %-4d | Failed: %-4d%*s║\n",
 			job.ItemsSucceeded, job.ItemsFailed,
 			33, "")
 
-		elapsed := formatDuration(job.ElapsedSeconds)
+		elapsed := progress.FormatDuration(job.ElapsedSeconds)
 		remaining := "-"
 		if job.EstimatedRemainingSeconds != nil {
-			remaining = formatDuration(*job.EstimatedRemainingSeconds)
+			remaining = progress.FormatDuration(*job.EstimatedRemainingSeconds)
 		}
 		fmt.Printf("║ Elapsed: %-10s | Remaining: ~%-10s%*s║\n",
 			elapsed, remaining, 18, "")
@@ -386,25 +748,225 @@ func (c *MonitorClient) printJobs(jobs []*JobExecution) {
 	}
 }
 
-func makeProgressBar(percent float64, width int) string {
-	filled := int(percent / 100 * float64(width))
-	if filled > width {
-		filled = width
+// printJSON writes v to stdout as indented JSON for --json.
+func printJSON(v any) {
+	if err := writeJSON(os.Stdout, v); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
 	}
-	empty := width - filled
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
 
-	bar := "[" + strings.Repeat("█", filled) + strings.Repeat("░", empty) + "]"
-	return fmt.Sprintf("%-*s", width+2, bar)
+// jobsForJSON returns jobs, or an empty slice for nil, so --json prints []
+// rather than null when there are no jobs.
+func jobsForJSON(jobs []*JobExecution) []*JobExecution {
+	if jobs == nil {
+		return []*JobExecution{}
+	}
+	return jobs
 }
 
-func formatDuration(seconds int64) string {
-	if seconds < 60 {
-		return fmt.Sprintf("%ds", seconds)
-	} else if seconds < 3600 {
-		return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
-	} else {
-		hours := seconds / 3600
-		minutes := (seconds % 3600) / 60
-		return fmt.Sprintf("%dh %dm", hours, minutes)
+// historyPath is the job history endpoint for the limit most recent
+// executions, of jobName if set.
+func historyPath(jobName string, limit int) string {
+	path := fmt.Sprintf("/api/monitoring/jobs/history?limit=%d", limit)
+	if jobName != "" {
+		path += "&job_name=" + url.QueryEscape(jobName)
+	}
+	return path
+}
+
+// checkFinishedJobs returns an error listing every job that didn't complete,
+// or if there are no jobs to check.
+func checkFinishedJobs(jobs []*JobExecution) error {
+	if len(jobs) == 0 {
+		return errors.New("no job executions found to check")
+	}
+
+	var failed []string
+	for _, job := range jobs {
+		if job.Status == "completed" {
+			continue
+		}
+		line := fmt.Sprintf("  %s #%d: %s", job.JobName, job.ID, job.Status)
+		if job.ErrorMessage != nil {
+			line += " (" + *job.ErrorMessage + ")"
+		}
+		failed = append(failed, line)
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d watched job(s) did not complete:\n%s", len(failed), len(jobs), strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// watchTick is one line of watch --json output: the active jobs at Time, or
+// the error fetching them.
+type watchTick struct {
+	Time  time.Time       `json:"time"`
+	Jobs  []*JobExecution `json:"jobs"`
+	Error string          `json:"error,omitempty"`
+}
+
+// maxInlineListItems is how many items a metadata list can have before
+// writeMetadata shows only its length.
+const maxInlineListItems = 5
+
+// writeMetadata writes a job's metadata to w as key: value lines sorted by
+// key, with values aligned. Nested objects are written the same way,
+// indented under their key. Checkpoints can hold thousands of route IDs, so
+// longer lists are shown as a count.
+func writeMetadata(w io.Writer, metadata map[string]interface{}, indent string) {
+	keys := make([]string, 0, len(metadata))
+	width := 0
+	for key := range metadata {
+		keys = append(keys, key)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if nested, ok := metadata[key].(map[string]interface{}); ok && len(nested) > 0 {
+			fmt.Fprintf(w, "%s%s:\n", indent, key)
+			writeMetadata(w, nested, indent+"  ")
+			continue
+		}
+		fmt.Fprintf(w, "%s%-*s %s\n", indent, width+1, key+":", formatMetadataValue(metadata[key]))
+	}
+}
+
+// formatMetadataValue formats a decoded JSON value for writeMetadata. Whole
+// numbers print without a decimal point.
+func formatMetadataValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		if len(v) > maxInlineListItems {
+			return fmt.Sprintf("[%d items]", len(v))
+		}
+		items := make([]string, len(v))
+		for i, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				return fmt.Sprintf("[%d items]", len(v))
+			}
+			items[i] = formatMetadataValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// currentItemName returns the route a job is working on, from the
+// current_route_name metadata key, with its rating when known. It's empty for
+// jobs that don't report one.
+func currentItemName(job *JobExecution) string {
+	name, _ := job.Metadata["current_route_name"].(string)
+	if name == "" {
+		return ""
+	}
+	if rating, _ := job.Metadata["current_rating"].(string); rating != "" {
+		return fmt.Sprintf("%s (%s)", name, rating)
+	}
+	return name
+}
+
+// truncate shortens s to at most n runes, ending in "…" when cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// writeWatchTick writes one watchTick to w as a single line of JSON.
+func writeWatchTick(w io.Writer, now time.Time, jobs []*JobExecution, fetchErr error) error {
+	tick := watchTick{Time: now, Jobs: jobsForJSON(jobs)}
+	if fetchErr != nil {
+		tick.Error = fetchErr.Error()
+	}
+	return json.NewEncoder(w).Encode(tick)
+}
+
+// sortedJobNames returns the summary's job names in a stable order: by name,
+// or by most recent run first (jobs that never ran last, ties by name).
+func sortedJobNames(summary map[string]*JobSummaryItem, sortBy string) []string {
+	names := make([]string, 0, len(summary))
+	for name := range summary {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if sortBy == sortByLastRun {
+			a, b := summary[names[i]].LastRun, summary[names[j]].LastRun
+			switch {
+			case a != nil && b == nil:
+				return true
+			case a == nil && b != nil:
+				return false
+			case a != nil && b != nil && !a.Equal(*b):
+				return a.After(*b)
+			}
+		}
+		return names[i] < names[j]
+	})
+
+	return names
+}
+
+// validateStatus rejects --status values that no job can have. An empty
+// status disables filtering.
+func validateStatus(status string) error {
+	if status == "" {
+		return nil
+	}
+	for _, s := range validStatuses {
+		if status == s {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid --status %q (must be one of %s)", status, strings.Join(validStatuses, ", "))
+}
+
+// filterJobsByName keeps only jobs with the given name. An empty name
+// returns jobs unchanged.
+func filterJobsByName(jobs []*JobExecution, name string) []*JobExecution {
+	if name == "" {
+		return jobs
+	}
+	filtered := make([]*JobExecution, 0, len(jobs))
+	for _, job := range jobs {
+		if job.JobName == name {
+			filtered = append(filtered, job)
+		}
+	}
+	return filtered
+}
+
+// filterJobsByStatus keeps only jobs with the given status. An empty status
+// returns jobs unchanged.
+func filterJobsByStatus(jobs []*JobExecution, status string) []*JobExecution {
+	if status == "" {
+		return jobs
+	}
+	filtered := make([]*JobExecution, 0, len(jobs))
+	for _, job := range jobs {
+		if job.Status == status {
+			filtered = append(filtered, job)
+		}
 	}
+	return filtered
 }
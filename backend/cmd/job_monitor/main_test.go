@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+)
+
+func TestSortedJobNames(t *testing.T) {
+	older := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	newer := older.Add(2 * time.Hour)
+
+	summary := map[string]*JobSummaryItem{
+		"weather_sync":            {LastRun: &older},
+		"high_priority_tick_sync": {LastRun: &newer},
+		"kaya_sync":               {},
+		"area_rank_sync":          {LastRun: &older},
+	}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		want   []string
+	}{
+		{
+			name:   "by name",
+			sortBy: sortByName,
+			want:   []string{"area_rank_sync", "high_priority_tick_sync", "kaya_sync", "weather_sync"},
+		},
+		{
+			name:   "by last run, never-run last, ties by name",
+			sortBy: sortByLastRun,
+			want:   []string{"high_priority_tick_sync", "area_rank_sync", "weather_sync", "kaya_sync"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Repeat to catch any dependence on map iteration order.
+			for i := 0; i < 20; i++ {
+				if got := sortedJobNames(summary, tt.sortBy); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("sortedJobNames() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterJobsByStatus(t *testing.T) {
+	jobs := []*JobExecution{
+		{ID: 1, Status: "running"},
+		{ID: 2, Status: "failed"},
+		{ID: 3, Status: "completed"},
+		{ID: 4, Status: "failed"},
+	}
+
+	if got := filterJobsByStatus(jobs, ""); len(got) != len(jobs) {
+		t.Errorf("empty status should not filter, got %d jobs", len(got))
+	}
+
+	got := filterJobsByStatus(jobs, "failed")
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 4 {
+		t.Errorf("filterJobsByStatus(failed) returned unexpected jobs: %+v", got)
+	}
+}
+
+func TestValidateStatus(t *testing.T) {
+	for _, status := range []string{"", "running", "failed", "completed"} {
+		if err := validateStatus(status); err != nil {
+			t.Errorf("validateStatus(%q) unexpected error: %v", status, err)
+		}
+	}
+	if err := validateStatus("done"); err == nil {
+		t.Error("validateStatus(done) expected error")
+	}
+}
+
+func TestDecodeResponse(t *testing.T) {
+	// Extra fields and missing optional fields are fine.
+	got, err := decodeResponse[jobsResponse]([]byte(`{"jobs":[{"id":7,"job_name":"kaya_sync","new_field":true}],"next":null}`), "jobs")
+	if err != nil {
+		t.Fatalf("decodeResponse() unexpected error: %v", err)
+	}
+	if len(got.Jobs) != 1 || got.Jobs[0].ID != 7 || got.Jobs[0].EstimatedRemainingSeconds != nil {
+		t.Errorf("decodeResponse() = %+v", got.Jobs)
+	}
+
+	bad := map[string]string{
+		"not an object":  `[{"id":7}]`,
+		"html":           `<html>Bad Gateway</html>`,
+		"missing jobs":   `{"items":[]}`,
+		"retyped field":  `{"jobs":{"id":7}}`,
+		"null job entry": `{"jobs":[null]}`,
+		"null body":      `null`,
+	}
+	for name, body := range bad {
+		if _, err := decodeResponse[jobsResponse]([]byte(body), "jobs"); !errors.Is(err, errUnexpectedFormat) {
+			t.Errorf("%s: decodeResponse() error = %v, want errUnexpectedFormat", name, err)
+		}
+	}
+
+	if _, err := decodeResponse[JobsSummary]([]byte(`{"summary":{"kaya_sync":null}}`), "summary"); !errors.Is(err, errUnexpectedFormat) {
+		t.Errorf("null summary item: decodeResponse() error = %v, want errUnexpectedFormat", err)
+	}
+}
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name: "matching version",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"service":"woulder-api","api_version":1}`))
+			},
+		},
+		{
+			name: "mismatched version",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(`{"service":"woulder-api","api_version":2}`))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "older server without the endpoint",
+			handler: http.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			client := &MonitorClient{baseURL: server.URL, client: server.Client()}
+			err := client.checkAPIVersion(supportedAPIVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkAPIVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWriteJSON_EmptyJobsIsArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, jobsForJSON(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("writeJSON(no jobs) = %q, want []", got)
+	}
+}
+
+func TestWriteJSON_RoundTripsJob(t *testing.T) {
+	started := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	job := &JobExecution{ID: 7, JobName: "kaya_sync", Status: "running", TotalItems: 10, ItemsProcessed: 4, StartedAt: started}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, jobsForJSON([]*JobExecution{job})); err != nil {
+		t.Fatal(err)
+	}
+	var got []*JobExecution
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output isn't JSON: %v", err)
+	}
+	if len(got) != 1 || !reflect.DeepEqual(got[0], job) {
+		t.Errorf("round trip = %+v, want %+v", got, job)
+	}
+}
+
+func TestWriteWatchTick(t *testing.T) {
+	now := time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+
+	if err := writeWatchTick(&buf, now, []*JobExecution{{ID: 1, JobName: "kaya_sync"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeWatchTick(&buf, now.Add(2*time.Second), nil, errors.New("connection refused")); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one JSON object per tick:\n%s", len(lines), buf.String())
+	}
+	var first, second watchTick
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(lines[1], &second); err != nil {
+		t.Fatal(err)
+	}
+	if !first.Time.Equal(now) || len(first.Jobs) != 1 || first.Error != "" {
+		t.Errorf("first tick = %+v", first)
+	}
+	if second.Error != "connection refused" || second.Jobs == nil || len(second.Jobs) != 0 {
+		t.Errorf("second tick = %+v, want the error and no jobs", second)
+	}
+}
+
+func TestCheckFinishedJobs(t *testing.T) {
+	msg := "MP API timeout"
+	tests := []struct {
+		name    string
+		jobs    []*JobExecution
+		wantErr string
+	}{
+		{
+			name: "all completed",
+			jobs: []*JobExecution{{ID: 1, JobName: "kaya_sync", Status: "completed"}, {ID: 2, JobName: "kaya_sync", Status: "completed"}},
+		},
+		{
+			name:    "one failed",
+			jobs:    []*JobExecution{{ID: 1, JobName: "kaya_sync", Status: "completed"}, {ID: 2, JobName: "location_tick_sync", Status: "failed", ErrorMessage: &msg}},
+			wantErr: "location_tick_sync #2: failed (MP API timeout)",
+		},
+		{
+			name:    "cancelled",
+			jobs:    []*JobExecution{{ID: 3, JobName: "kaya_sync", Status: "cancelled"}},
+			wantErr: "kaya_sync #3: cancelled",
+		},
+		{
+			name:    "nothing to check",
+			wantErr: "no job executions found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFinishedJobs(tt.jobs)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkFinishedJobs() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkFinishedJobs() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterJobsByName(t *testing.T) {
+	jobs := []*JobExecution{{ID: 1, JobName: "kaya_sync"}, {ID: 2, JobName: "weather_sync"}}
+
+	if got := filterJobsByName(jobs, ""); len(got) != 2 {
+		t.Errorf("empty name kept %d jobs, want 2", len(got))
+	}
+	if got := filterJobsByName(jobs, "weather_sync"); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("filterJobsByName(weather_sync) = %+v", got)
+	}
+}
+
+func TestGetFinishedJobs(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/api/monitoring/jobs/history":
+			w.Write([]byte(`{"jobs":[{"id":9,"job_name":"kaya sync","status":"failed"}]}`))
+		case "/api/monitoring/jobs/4":
+			w.Write([]byte(`{"id":4,"job_name":"kaya_sync","status":"completed"}`))
+		case "/api/monitoring/jobs/7":
+			w.Write([]byte(`{"id":7,"job_name":"kaya_sync","status":"failed"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := &MonitorClient{baseURL: server.URL, client: server.Client()}
+
+	t.Run("watched jobs by ID", func(t *testing.T) {
+		jobs, err := client.getFinishedJobs(map[int64]bool{7: true, 4: true}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != 2 || jobs[0].ID != 4 || jobs[1].Status != "failed" {
+			t.Errorf("getFinishedJobs() = %+v, want jobs 4 and 7 with their final status", jobs)
+		}
+	})
+
+	t.Run("most recent run when nothing was active", func(t *testing.T) {
+		paths = nil
+		jobs, err := client.getFinishedJobs(map[int64]bool{}, "kaya sync")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != 1 || jobs[0].ID != 9 {
+			t.Errorf("getFinishedJobs() = %+v, want the latest history entry", jobs)
+		}
+		if len(paths) != 1 || paths[0] != "/api/monitoring/jobs/history?limit=1&job_name=kaya+sync" {
+			t.Errorf("requested %v, want the latest kaya sync run", paths)
+		}
+	})
+}
+
+func TestCancelJob(t *testing.T) {
+	var method, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, auth = r.Method, r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/monitoring/jobs/12/cancel":
+			w.Write([]byte(`{"id":12,"job_name":"high_priority_tick_sync","status":"cancelled","items_processed":40,"total_items":900}`))
+		case "/api/monitoring/jobs/13/cancel":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"Job is completed, not running"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := &MonitorClient{baseURL: server.URL, client: server.Client(), apiToken: "secret"}
+
+	job, err := client.cancelJob(12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.ID != 12 || job.Status != "cancelled" {
+		t.Errorf("cancelJob() = %+v, want job 12 cancelled", job)
+	}
+	if method != http.MethodPost || auth != "Bearer secret" {
+		t.Errorf("request was %s with Authorization %q, want POST with the bearer token", method, auth)
+	}
+
+	if _, err := client.cancelJob(13); !httpx.HasStatus(err, http.StatusConflict) {
+		t.Errorf("cancelJob(finished job) error = %v, want 409", err)
+	}
+}
+
+func TestPruneJobs(t *testing.T) {
+	var method, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, query = r.Method, r.URL.RawQuery
+		if r.URL.Path != "/api/monitoring/jobs/prune" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"deleted":17,"cutoff":"2026-07-20T00:00:00Z"}`))
+	}))
+	defer server.Close()
+	client := &MonitorClient{baseURL: server.URL, client: server.Client()}
+
+	result, err := client.pruneJobs(90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPost || query != "days=90" {
+		t.Errorf("request was %s ?%s, want POST ?days=90", method, query)
+	}
+	if result.Deleted != 17 || !result.Cutoff.Equal(time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("pruneJobs() = %+v", result)
+	}
+}
+
+func TestWriteMetadata(t *testing.T) {
+	var metadata map[string]interface{}
+	err := json.Unmarshal([]byte(`{
+		"priority": "high",
+		"incremental": true,
+		"current_route_id": 105717310,
+		"checkpoint": {"current_route_index": 120, "completed_route_ids": [1, 2, 3, 4, 5, 6, 7]},
+		"states": ["WA", "OR"],
+		"note": null
+	}`), &metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	writeMetadata(&buf, metadata, "  ")
+
+	want := `  checkpoint:
+    completed_route_ids: [7 items]
+    current_route_index: 120
+  current_route_id: 105717310
+  incremental:      true
+  note:             -
+  priority:         high
+  states:           [WA, OR]
+`
+	if got := buf.String(); got != want {
+		t.Errorf("writeMetadata() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCurrentItemName(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]interface{}
+		want     string
+	}{
+		{"no current route", map[string]interface{}{"priority": "high"}, ""},
+		{"route only", map[string]interface{}{"current_route_name": "Midnight Lightning"}, "Midnight Lightning"},
+		{"route and rating", map[string]interface{}{"current_route_name": "Midnight Lightning", "current_rating": "V8"}, "Midnight Lightning (V8)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := currentItemName(&JobExecution{Metadata: tt.metadata}); got != tt.want {
+				t.Errorf("currentItemName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("Öyster Crack", 20); got != "Öyster Crack" {
+		t.Errorf("truncate() = %q, want it unchanged", got)
+	}
+	if got := truncate("Öyster Crack", 6); got != "Öyste…" {
+		t.Errorf("truncate() = %q, want Öyste…", got)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Formats for the --output file.
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+func isValidExportFormat(format string) bool {
+	return format == formatCSV || format == formatJSON
+}
+
+// exportedMatch is one match in an --output file, with the review status it
+// was (or, without saving, would have been) saved with.
+type exportedMatch struct {
+	KayaClimbID       string   `json:"kaya_climb_id"`
+	KayaClimbName     string   `json:"kaya_climb_name"`
+	KayaLocationName  string   `json:"kaya_location_name"`
+	KayaGrade         string   `json:"kaya_grade"`
+	MPRouteID         int64    `json:"mp_route_id"`
+	MPRouteName       string   `json:"mp_route_name"`
+	MPAreaName        string   `json:"mp_area_name"`
+	MPRating          string   `json:"mp_rating"`
+	Confidence        float64  `json:"confidence"`
+	MatchType         string   `json:"match_type"`
+	NameSimilarity    float64  `json:"name_similarity"`
+	DistanceKM        *float64 `json:"distance_km"`
+	LocationNameMatch bool     `json:"location_name_match"`
+	GradeAgreement    string   `json:"grade_agreement"`
+	Status            string   `json:"status"`
+}
+
+func newExportedMatch(match RouteMatch, status string) exportedMatch {
+	return exportedMatch{
+		KayaClimbID:       match.KayaClimbID,
+		KayaClimbName:     match.KayaClimbName,
+		KayaLocationName:  match.KayaLocationName,
+		KayaGrade:         match.KayaGrade,
+		MPRouteID:         match.MPRouteID,
+		MPRouteName:       match.MPRouteName,
+		MPAreaName:        match.MPAreaName,
+		MPRating:          match.MPRating,
+		Confidence:        match.Confidence,
+		MatchType:         match.MatchType,
+		NameSimilarity:    match.NameSimilarity,
+		DistanceKM:        match.DistanceKM,
+		LocationNameMatch: match.LocationNameMatch,
+		GradeAgreement:    match.GradeAgreement,
+		Status:            status,
+	}
+}
+
+// matchCSVHeader names the CSV columns, in the order of
+// exportedMatch.csvRecord.
+var matchCSVHeader = []string{
+	"kaya_climb_id", "kaya_climb_name", "kaya_location_name", "kaya_grade",
+	"mp_route_id", "mp_route_name", "mp_area_name", "mp_rating",
+	"confidence", "match_type", "name_similarity", "distance_km",
+	"location_name_match", "grade_agreement", "status",
+}
+
+// csvRecord formats m as a CSV row. An unknown distance is left empty.
+func (m exportedMatch) csvRecord() []string {
+	distance := ""
+	if m.DistanceKM != nil {
+		distance = strconv.FormatFloat(*m.DistanceKM, 'f', 3, 64)
+	}
+	return []string{
+		m.KayaClimbID, m.KayaClimbName, m.KayaLocationName, m.KayaGrade,
+		strconv.FormatInt(m.MPRouteID, 10), m.MPRouteName, m.MPAreaName, m.MPRating,
+		strconv.FormatFloat(m.Confidence, 'f', 4, 64), m.MatchType,
+		strconv.FormatFloat(m.NameSimilarity, 'f', 4, 64), distance,
+		strconv.FormatBool(m.LocationNameMatch), m.GradeAgreement, m.Status,
+	}
+}
+
+// exportRow is a row of an --output file. JSON rows are the row marshaled
+// as-is.
+type exportRow interface {
+	csvRecord() []string
+}
+
+// rowWriter streams rows to an --output file as they're found, so a large run
+// never holds every row in memory. Close finishes the file but doesn't close
+// the underlying writer.
+type rowWriter interface {
+	WriteRow(row exportRow) error
+	Close() error
+}
+
+// newRowWriter returns a rowWriter for format that writes to w. header names
+// the CSV columns.
+func newRowWriter(w io.Writer, format string, header []string) (rowWriter, error) {
+	switch format {
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvRowWriter{w: cw}, nil
+	case formatJSON:
+		return &jsonRowWriter{w: bufio.NewWriter(w)}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q: must be %s or %s", format, formatCSV, formatJSON)
+	}
+}
+
+// csvRowWriter writes a header row, then one row per WriteRow.
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(row exportRow) error {
+	return c.w.Write(row.csvRecord())
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonRowWriter writes a JSON array, one row per line.
+type jsonRowWriter struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (j *jsonRowWriter) WriteRow(row exportRow) error {
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.count == 0 {
+		sep = "[\n"
+	}
+	j.count++
+	if _, err := j.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonRowWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	if _, err := j.w.WriteString(end); err != nil {
+		return err
+	}
+	return j.w.Flush()
+}
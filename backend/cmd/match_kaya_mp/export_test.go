@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
+)
+
+func exportTestMatches() []RouteMatch {
+	dist := 0.25
+	return []RouteMatch{
+		{
+			KayaClimbID: "the-egg", KayaClimbName: "The Egg", KayaLocationName: "Leavenworth", KayaGrade: "V3",
+			MPRouteID: 105, MPRouteName: "Egg, The", MPAreaName: "Forestland", MPRating: "V3",
+			Confidence: 0.95, MatchType: "fuzzy_name", NameSimilarity: 0.9, DistanceKM: &dist,
+			LocationNameMatch: true, GradeAgreement: gradeAgreementMatch,
+		},
+		{
+			KayaClimbID: "mandala", KayaClimbName: "The Mandala", KayaLocationName: "Bishop", KayaGrade: "V12",
+			MPRouteID: 106, MPRouteName: "The Mandala", MPAreaName: "Buttermilks", MPRating: "V12",
+			Confidence: 0.8, MatchType: "exact_name", NameSimilarity: 1.0,
+			GradeAgreement: gradeAgreementMatch,
+		},
+	}
+}
+
+func TestRowWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRowWriter(&buf, formatCSV, matchCSVHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range exportTestMatches() {
+		if err := w.WriteRow(newExportedMatch(m, kayamatch.StatusApproved)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output isn't valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d rows, want header and 2 matches", len(records))
+	}
+	if records[0][0] != "kaya_climb_id" || len(records[0]) != len(records[1]) {
+		t.Errorf("header = %v", records[0])
+	}
+	want := []string{"the-egg", "The Egg", "Leavenworth", "V3", "105", "Egg, The", "Forestland", "V3",
+		"0.9500", "fuzzy_name", "0.9000", "0.250", "true", "match", "approved"}
+	for i := range want {
+		if records[1][i] != want[i] {
+			t.Errorf("column %s = %q, want %q", matchCSVHeader[i], records[1][i], want[i])
+		}
+	}
+	if got := records[2][11]; got != "" {
+		t.Errorf("distance_km = %q, want empty for an unknown distance", got)
+	}
+}
+
+func TestRowWriter_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRowWriter(&buf, formatJSON, matchCSVHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range exportTestMatches() {
+		if err := w.WriteRow(newExportedMatch(m, kayamatch.StatusPending)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []exportedMatch
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output isn't a JSON array: %v\n%s", err, buf.String())
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d matches, want 2", len(got))
+	}
+	if got[0].MPRouteID != 105 || got[0].DistanceKM == nil || *got[0].DistanceKM != 0.25 || got[0].Status != kayamatch.StatusPending {
+		t.Errorf("first match = %+v", got[0])
+	}
+	if got[1].DistanceKM != nil {
+		t.Errorf("distance_km = %v, want null for an unknown distance", *got[1].DistanceKM)
+	}
+}
+
+func TestRowWriter_JSONWithoutMatches(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newRowWriter(&buf, formatJSON, matchCSVHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("output = %q, want an empty array", buf.String())
+	}
+}
+
+func TestNewRowWriter_RejectsUnknownFormat(t *testing.T) {
+	if _, err := newRowWriter(&bytes.Buffer{}, "xlsx", matchCSVHeader); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
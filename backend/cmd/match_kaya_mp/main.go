@@ -5,14 +5,18 @@ import (
 	"database/sql"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"math"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
 )
 
 // KayaClimb represents a simplified climb for matching
@@ -48,6 +52,9 @@ type RouteMatch struct {
 	NameSimilarity    float64
 	DistanceKM        *float64
 	LocationNameMatch bool
+	KayaGrade         string
+	MPRating          string
+	GradeAgreement    string // see gradeAgreement
 }
 
 func main() {
@@ -58,17 +65,65 @@ func main() {
 	minConfidenceFlag := flag.Float64("min-confidence", 0.75, "Minimum confidence score (0.0-1.0)")
 	dryRunFlag := flag.Bool("dry-run", false, "Show matches without saving to database")
 	limitFlag := flag.Int("limit", 0, "Limit number of climbs to process (0 = all)")
+	thresholdsFlag := flag.String("thresholds", "", "JSON file overriding match thresholds (see deployment/SYSTEMD_SETUP.md)")
+	autoApproveFlag := flag.Float64("auto-approve-threshold", kayamatch.DefaultAutoApproveThreshold, "Confidence at or above which matches are saved as approved; lower matches are queued for review")
+	useTrigramFlag := flag.Bool("use-trigram", false, "Find candidates with pg_trgm similarity instead of a name substring match (requires the pg_trgm extension)")
+	scorerFlag := flag.String("scorer", scorerMax, "Route name scorer: levenshtein, jaccard (shared words), or max of the two; ignored with --use-trigram")
+	outputFlag := flag.String("output", "", "Also write every match to this file, for review outside the database")
+	formatFlag := flag.String("format", formatCSV, "Format of the --output file: csv or json")
+	noSaveFlag := flag.Bool("no-save", false, "Don't save matches to the database, only write them to --output")
+	unmatchedMPFlag := flag.Bool("unmatched-mp", false, "Instead of matching, list the MP routes in --location with no Kaya match, to --output or stdout")
+	kayamatch.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	// Load environment variables - try current directory first, then parent
-	if err := godotenv.Load(".env"); err != nil {
-		if err := godotenv.Load("../.env"); err != nil {
-			log.Printf("Warning: .env file not found in . or .., using system environment variables")
+	if err := kayamatch.ValidateAutoApproveThreshold(*autoApproveFlag); err != nil {
+		log.Fatalf("Invalid -auto-approve-threshold: %v", err)
+	}
+	if !isValidScorer(*scorerFlag) {
+		log.Fatalf("Invalid -scorer %q: must be %s, %s or %s", *scorerFlag, scorerLevenshtein, scorerJaccard, scorerMax)
+	}
+
+	if !isValidExportFormat(*formatFlag) {
+		log.Fatalf("Invalid -format %q: must be %s or %s", *formatFlag, formatCSV, formatJSON)
+	}
+	if *noSaveFlag && *outputFlag == "" {
+		log.Fatal("-no-save requires -output (use -dry-run to only log matches)")
+	}
+	saveToDB := !*dryRunFlag && !*noSaveFlag
+
+	thresholds, err := kayamatch.ResolveThresholds(flag.CommandLine, *thresholdsFlag)
+	if err != nil {
+		log.Fatalf("Invalid match thresholds: %v", err)
+	}
+
+	var output rowWriter
+	if *outputFlag != "" || *unmatchedMPFlag {
+		var w io.Writer = os.Stdout
+		if *outputFlag != "" {
+			f, err := os.Create(*outputFlag)
+			if err != nil {
+				log.Fatalf("Failed to create output file: %v", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		header := matchCSVHeader
+		if *unmatchedMPFlag {
+			header = unmatchedCSVHeader
+		}
+		if output, err = newRowWriter(w, *formatFlag, header); err != nil {
+			log.Fatalf("Failed to write %s: %v", *outputFlag, err)
 		}
 	}
 
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	// Create raw SQL connection for matching queries
-	sqlDB, err := createSQLConnection()
+	sqlDB, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to create SQL connection: %v", err)
 	}
@@ -76,6 +131,24 @@ func main() {
 
 	ctx := context.Background()
 
+	if *unmatchedMPFlag {
+		count, err := writeUnmatchedMPRoutes(ctx, sqlDB, *locationFlag, output)
+		if err == nil {
+			err = output.Close()
+		}
+		if err != nil {
+			log.Fatalf("Failed to list unmatched MP routes: %v", err)
+		}
+		log.Printf("Found %d MP routes with no Kaya match", count)
+		return
+	}
+
+	if *useTrigramFlag {
+		if err := enableTrigram(ctx, sqlDB); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	log.Printf("Configuration:")
 	log.Printf("  - Location filter: %s", func() string {
 		if *locationFlag != "" {
@@ -84,8 +157,25 @@ func main() {
 		return "All locations"
 	}())
 	log.Printf("  - Min confidence: %.2f", *minConfidenceFlag)
+	log.Printf("  - Auto-approve threshold: %.2f", *autoApproveFlag)
 	log.Printf("  - Dry run: %v", *dryRunFlag)
+	log.Printf("  - Save to database: %v", saveToDB)
+	log.Printf("  - Output file: %s", func() string {
+		if *outputFlag != "" {
+			return *outputFlag + " (" + *formatFlag + ")"
+		}
+		return "none"
+	}())
 	log.Printf("  - Limit: %d", *limitFlag)
+	log.Printf("  - Candidate search: %s", func() string {
+		if *useTrigramFlag {
+			return "pg_trgm similarity"
+		}
+		return "name substring, " + *scorerFlag + " scorer"
+	}())
+	log.Printf("  - Confidence weights: name %.2f, location %.2f, proximity %.2f",
+		thresholds.NameWeight, thresholds.LocationBonus, thresholds.ProximityBonus)
+	thresholds.Log()
 	log.Println()
 
 	// Get Kaya climbs to match
@@ -98,11 +188,28 @@ func main() {
 
 	if len(climbs) == 0 {
 		log.Println("No climbs found. Ensure you've run the Kaya sync first.")
+		if output != nil {
+			output.Close()
+		}
 		return
 	}
 
+	opts := matchOptions{
+		MinConfidence: *minConfidenceFlag,
+		Thresholds:    thresholds,
+		UseTrigram:    *useTrigramFlag,
+		Scorer:        *scorerFlag,
+	}
+
+	// Matches are upserted in batches as they're found
+	var saver *matchSaver
+	if saveToDB {
+		saver = newMatchSaver(sqlDB, defaultSaveBatchSize)
+	}
+
 	matchCount := 0
-	highConfidenceCount := 0
+	approvedCount := 0
+	pendingCount := 0
 
 	// Process each climb
 	for i, climb := range climbs {
@@ -111,7 +218,7 @@ func main() {
 		}
 
 		// Find potential MP matches
-		matches := findMPMatches(ctx, sqlDB, climb, *minConfidenceFlag)
+		matches := findMPMatches(ctx, sqlDB, climb, opts)
 
 		if len(matches) == 0 {
 			continue
@@ -125,22 +232,41 @@ func main() {
 			log.Printf("  Confidence: %.2f | Type: %s", match.Confidence, match.MatchType)
 			log.Printf("  Name similarity: %.2f | Distance: %s",
 				match.NameSimilarity, formatDistance(match.DistanceKM))
+			log.Printf("  Grade: %s vs %s (%s)", match.KayaGrade, match.MPRating, match.GradeAgreement)
 
-			if match.Confidence >= 0.90 {
-				highConfidenceCount++
-			}
+			status := kayamatch.StatusFor(match.Confidence, *autoApproveFlag)
+			log.Printf("  Status: %s", status)
 
 			matchCount++
 
-			// Save match if not dry run
-			if !*dryRunFlag {
-				if err := saveMatch(ctx, sqlDB, match); err != nil {
-					log.Printf("  ERROR saving match: %v", err)
-				} else {
-					log.Printf("  ✓ Saved to database")
+			if output != nil {
+				if err := output.WriteRow(newExportedMatch(match, status)); err != nil {
+					log.Fatalf("Failed to write match to %s: %v", *outputFlag, err)
+				}
+			}
+
+			// Queue match to save unless this is a dry run or -no-save
+			if saver != nil {
+				if err := saver.Add(ctx, match, status); err != nil {
+					log.Printf("  ERROR saving matches: %v", err)
 				}
 			}
+
+			if status == kayamatch.StatusApproved {
+				approvedCount++
+			} else {
+				pendingCount++
+			}
+		}
+	}
+
+	if saver != nil {
+		if err := saver.Flush(ctx); err != nil {
+			log.Printf("ERROR saving matches: %v", err)
 		}
+		// Report only the matches that made it into the table
+		approvedCount = saver.SavedWithStatus(kayamatch.StatusApproved)
+		pendingCount = saver.SavedWithStatus(kayamatch.StatusPending)
 	}
 
 	log.Printf("\n========================================")
@@ -148,12 +274,22 @@ func main() {
 	log.Printf("========================================")
 	log.Printf("Climbs processed: %d", len(climbs))
 	log.Printf("Total matches: %d", matchCount)
-	log.Printf("High confidence (≥0.90): %d", highConfidenceCount)
+	log.Printf("Auto-approved (≥%.2f): %d", *autoApproveFlag, approvedCount)
+	log.Printf("Queued for review: %d", pendingCount)
+	thresholds.Log()
 
+	if output != nil {
+		if err := output.Close(); err != nil {
+			log.Fatalf("Failed to write %s: %v", *outputFlag, err)
+		}
+		log.Printf("Matches written to %s", *outputFlag)
+	}
 	if *dryRunFlag {
 		log.Printf("DRY RUN: No matches were saved to database")
+	} else if *noSaveFlag {
+		log.Printf("-no-save: No matches were saved to database")
 	} else {
-		log.Printf("Matches saved to kaya_mp_route_matches table")
+		log.Printf("Saved %d of %d matches to kaya_mp_route_matches table", saver.Saved(), matchCount)
 	}
 	log.Printf("========================================")
 }
@@ -226,24 +362,73 @@ func getKayaClimbs(ctx context.Context, db *sql.DB, location string, limit int)
 	return climbs, rows.Err()
 }
 
-func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfidence float64) []RouteMatch {
-	// Query MP routes with similar names, joining with areas to get area name
-	query := `
-		SELECT
-			r.mp_route_id,
-			r.name,
-			COALESCE(a.name, 'Unknown') as area_name,
-			a.latitude,
-			a.longitude,
-			r.route_type,
-			r.rating
-		FROM woulder.mp_routes r
-		LEFT JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
-		WHERE LOWER(r.name) LIKE LOWER($1)
-		LIMIT 20
-	`
+// likeCandidatesQuery finds MP routes whose name contains the Kaya climb's
+// name. Similarity is scored in Go, so the last column is NULL.
+const likeCandidatesQuery = `
+	SELECT
+		r.mp_route_id,
+		r.name,
+		COALESCE(a.name, 'Unknown') as area_name,
+		a.latitude,
+		a.longitude,
+		r.route_type,
+		r.rating,
+		NULL::float8 as similarity
+	FROM woulder.mp_routes r
+	LEFT JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+	WHERE LOWER(r.name) LIKE LOWER($1)
+	LIMIT 20
+`
+
+// trigramCandidatesQuery finds the MP routes whose names are most similar to
+// the Kaya climb's by pg_trgm, which also catches names that aren't
+// substrings of each other ("The Egg" vs "Egg"). % uses pg_trgm's
+// similarity_threshold (default 0.3).
+const trigramCandidatesQuery = `
+	SELECT
+		r.mp_route_id,
+		r.name,
+		COALESCE(a.name, 'Unknown') as area_name,
+		a.latitude,
+		a.longitude,
+		r.route_type,
+		r.rating,
+		similarity(r.name, $1)::float8 as similarity
+	FROM woulder.mp_routes r
+	LEFT JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+	WHERE r.name % $1
+	ORDER BY similarity(r.name, $1) DESC
+	LIMIT 20
+`
+
+// enableTrigram makes sure pg_trgm is available for --use-trigram.
+func enableTrigram(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pg_trgm"); err != nil {
+		return fmt.Errorf("--use-trigram requires the pg_trgm extension, which could not be enabled (%v). "+
+			"Have a superuser run CREATE EXTENSION pg_trgm; in this database, or run without --use-trigram", err)
+	}
+	return nil
+}
 
-	rows, err := db.QueryContext(ctx, query, "%"+climb.Name+"%")
+// matchOptions control how findMPMatches finds and scores candidates.
+type matchOptions struct {
+	MinConfidence float64
+	Thresholds    kayamatch.Thresholds
+	UseTrigram    bool   // pg_trgm candidates and similarity
+	Scorer        string // name scorer without UseTrigram; see calculateNameSimilarity
+}
+
+// findMPMatches scores candidate MP routes for climb. With UseTrigram,
+// candidates and their name similarity come from pg_trgm; otherwise from a
+// substring match scored by calculateNameSimilarity.
+func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, opts matchOptions) []RouteMatch {
+	thresholds := opts.Thresholds
+	query, arg := likeCandidatesQuery, "%"+climb.Name+"%"
+	if opts.UseTrigram {
+		query, arg = trigramCandidatesQuery, climb.Name
+	}
+
+	rows, err := db.QueryContext(ctx, query, arg)
 	if err != nil {
 		log.Printf("  Error querying MP routes: %v", err)
 		return []RouteMatch{}
@@ -254,9 +439,9 @@ func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfiden
 	for rows.Next() {
 		var mpID string
 		var mpName, mpArea, mpRouteType, mpRating string
-		var mpLat, mpLon sql.NullFloat64
+		var mpLat, mpLon, dbSim sql.NullFloat64
 
-		if err := rows.Scan(&mpID, &mpName, &mpArea, &mpLat, &mpLon, &mpRouteType, &mpRating); err != nil {
+		if err := rows.Scan(&mpID, &mpName, &mpArea, &mpLat, &mpLon, &mpRouteType, &mpRating, &dbSim); err != nil {
 			continue
 		}
 
@@ -266,8 +451,11 @@ func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfiden
 			continue
 		}
 
-		// Calculate name similarity
-		nameSim := calculateNameSimilarity(climb.Name, mpName)
+		// Calculate name similarity, unless the database already did
+		nameSim := dbSim.Float64
+		if !dbSim.Valid {
+			nameSim = calculateNameSimilarity(climb.Name, mpName, opts.Scorer)
+		}
 
 		// Check location name match
 		locationMatch := matchLocationNames(climb.Location, mpArea)
@@ -284,13 +472,14 @@ func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfiden
 			continue
 		}
 
-		// Calculate overall confidence
-		confidence := calculateMatchConfidence(nameSim, locationMatch, distKM)
+		// Calculate overall confidence, adjusted for how well the grades agree
+		agreement := gradeAgreement(climb.Grade, mpRating)
+		confidence := applyGradeAgreement(thresholds.Confidence(nameSim, locationMatch, distKM), agreement)
 
 		// Determine match type
-		matchType := determineMatchType(nameSim, locationMatch, distKM)
+		matchType := thresholds.MatchType(nameSim, locationMatch, distKM)
 
-		if confidence >= minConfidence {
+		if confidence >= opts.MinConfidence {
 			matches = append(matches, RouteMatch{
 				KayaClimbID:       climb.ID,
 				KayaClimbName:     climb.Name,
@@ -303,6 +492,9 @@ func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfiden
 				NameSimilarity:    nameSim,
 				DistanceKM:        distKM,
 				LocationNameMatch: locationMatch,
+				KayaGrade:         climb.Grade,
+				MPRating:          mpRating,
+				GradeAgreement:    agreement,
 			})
 		}
 	}
@@ -310,42 +502,34 @@ func findMPMatches(ctx context.Context, db *sql.DB, climb KayaClimb, minConfiden
 	return matches
 }
 
-func saveMatch(ctx context.Context, db *sql.DB, match RouteMatch) error {
-	query := `
-		INSERT INTO kaya_mp_route_matches (
-			kaya_climb_id, mp_route_id, match_confidence, match_type,
-			kaya_climb_name, kaya_location_name,
-			mp_route_name, mp_area_name,
-			name_similarity, location_name_match, location_distance_km
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-		ON CONFLICT (kaya_climb_id, mp_route_id) DO UPDATE SET
-			match_confidence = EXCLUDED.match_confidence,
-			match_type = EXCLUDED.match_type,
-			name_similarity = EXCLUDED.name_similarity,
-			location_name_match = EXCLUDED.location_name_match,
-			location_distance_km = EXCLUDED.location_distance_km,
-			updated_at = CURRENT_TIMESTAMP
-	`
+// Route name scorers for calculateNameSimilarity.
+const (
+	scorerLevenshtein = "levenshtein"
+	scorerJaccard     = "jaccard"
+	scorerMax         = "max"
+)
 
-	_, err := db.ExecContext(ctx, query,
-		match.KayaClimbID,
-		match.MPRouteID,
-		match.Confidence,
-		match.MatchType,
-		match.KayaClimbName,
-		match.KayaLocationName,
-		match.MPRouteName,
-		match.MPAreaName,
-		match.NameSimilarity,
-		match.LocationNameMatch,
-		match.DistanceKM,
-	)
-
-	return err
+func isValidScorer(scorer string) bool {
+	return scorer == scorerLevenshtein || scorer == scorerJaccard || scorer == scorerMax
 }
 
-// calculateNameSimilarity computes similarity between two route names using Levenshtein distance
-func calculateNameSimilarity(name1, name2 string) float64 {
+// calculateNameSimilarity computes similarity between two route names with
+// scorer: Levenshtein distance, shared words (tokenSetSimilarity), or the
+// higher of the two, which forgives both typos and reordered words.
+func calculateNameSimilarity(name1, name2, scorer string) float64 {
+	switch scorer {
+	case scorerLevenshtein:
+		return levenshteinSimilarity(name1, name2)
+	case scorerJaccard:
+		return tokenSetSimilarity(name1, name2)
+	default:
+		return math.Max(levenshteinSimilarity(name1, name2), tokenSetSimilarity(name1, name2))
+	}
+}
+
+// levenshteinSimilarity scores two route names by edit distance, relative to
+// the longer normalized name.
+func levenshteinSimilarity(name1, name2 string) float64 {
 	// Normalize names
 	n1 := normalizeRouteName(name1)
 	n2 := normalizeRouteName(name2)
@@ -365,9 +549,40 @@ func calculateNameSimilarity(name1, name2 string) float64 {
 	return 1.0 - (float64(distance) / maxLen)
 }
 
+// tokenSetSimilarity is the Jaccard index of two route names' word sets,
+// ignoring articles, so word order and a missing "The" don't count against
+// a match ("Evilution Direct" vs "Direct Evilution" scores 1).
+func tokenSetSimilarity(a, b string) float64 {
+	setA, setB := routeNameTokens(a), routeNameTokens(b)
+	if len(setA) == 0 || len(setB) == 0 {
+		return 0.0
+	}
+
+	shared := 0
+	for token := range setA {
+		if setB[token] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(setA)+len(setB)-shared)
+}
+
+// routeNameTokens returns the set of words in a normalized route name,
+// without articles.
+func routeNameTokens(name string) map[string]bool {
+	tokens := make(map[string]bool)
+	for _, word := range strings.Fields(normalizeRouteName(name)) {
+		if word == "the" || word == "a" || word == "an" {
+			continue
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
 // normalizeRouteName standardizes route names for comparison
 func normalizeRouteName(name string) string {
-	name = strings.ToLower(name)
+	name = kayamatch.FoldDiacritics(strings.ToLower(name))
 	name = strings.TrimPrefix(name, "the ")
 	name = strings.TrimPrefix(name, "a ")
 
@@ -440,46 +655,6 @@ func calculateGPSDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	return earthRadiusKm * c
 }
 
-// calculateMatchConfidence computes overall match confidence score
-func calculateMatchConfidence(nameSim float64, locationMatch bool, distanceKM *float64) float64 {
-	confidence := nameSim * 0.7 // Name similarity weighted 70%
-
-	if locationMatch {
-		confidence += 0.2 // Location match adds 20%
-	}
-
-	if distanceKM != nil && *distanceKM < 5.0 {
-		proximityBonus := 0.1 * (1.0 - (*distanceKM / 5.0))
-		confidence += proximityBonus
-	}
-
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-
-	return confidence
-}
-
-// determineMatchType classifies the match
-func determineMatchType(nameSim float64, locationMatch bool, distanceKM *float64) string {
-	if nameSim == 1.0 {
-		return "exact_name"
-	}
-	if nameSim >= 0.9 && locationMatch {
-		return "fuzzy_name_location"
-	}
-	if nameSim >= 0.85 {
-		return "fuzzy_name"
-	}
-	if locationMatch && distanceKM != nil && *distanceKM < 1.0 {
-		return "location_gps_proximity"
-	}
-	if locationMatch {
-		return "location_name"
-	}
-	return "low_confidence"
-}
-
 func isCompatibleMatch(kayaClimbType, kayaGrade, mpRouteType, mpRating string) bool {
 	kayaDiscipline := classifyKayaDiscipline(kayaClimbType, kayaGrade)
 	mpDiscipline := classifyMPDiscipline(mpRouteType, mpRating)
@@ -580,6 +755,116 @@ func gradeFamily(grade string) string {
 	}
 }
 
+// Grade agreement between a Kaya climb and an MP route, saved with each match.
+const (
+	gradeAgreementMatch    = "match"    // at most gradeMatchSpread apart
+	gradeAgreementNear     = "near"     // close, but not a match; no adjustment
+	gradeAgreementConflict = "conflict" // different scales, or gradeConflictSpread or more apart
+	gradeAgreementUnknown  = "unknown"  // either grade missing or unrecognized
+)
+
+const (
+	gradeMatchSpread     = 1    // V-grades apart that still agree ("V4" vs "V4-5" or "V5")
+	gradeConflictSpread  = 3    // V-grades apart that clearly conflict
+	gradeMatchBonus      = 0.05 // added to confidence when grades agree
+	gradeConflictPenalty = 0.2  // subtracted from confidence when grades conflict
+)
+
+// fontToV maps Font boulder grades to V-scale ordinals (see normalizeGrade).
+var fontToV = map[string]int{
+	"6A": 3, "6A+": 3, "6B": 4, "6B+": 4, "6C": 5, "6C+": 5,
+	"7A": 6, "7A+": 7, "7B": 8, "7B+": 8, "7C": 9, "7C+": 10,
+	"8A": 11, "8A+": 12, "8B": 13, "8B+": 14, "8C": 15, "8C+": 16,
+	"9A": 17,
+}
+
+// normalizeGrade maps a V-scale or Font boulder grade to a comparable
+// ordinal: the V number, with VB as -1. Font grades convert to their usual V
+// equivalent ("7A" is 6); Font 3, 4 and 5 grades map to VB, V0 and V1.
+// Modifiers and ranges use the lower grade ("V4+", "V4-5" and "V4/5" are all
+// 4), and anything after the grade ("V5 PG13") is ignored.
+func normalizeGrade(grade string) (int, bool) {
+	fields := strings.Fields(strings.ToUpper(grade))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	g := fields[0]
+
+	if fontGradePattern.MatchString(g) {
+		if v, ok := fontToV[g]; ok {
+			return v, true
+		}
+		if g[0] < '6' {
+			return int(g[0]-'0') - 4, true
+		}
+		return 0, false
+	}
+
+	if !strings.HasPrefix(g, "V") {
+		return 0, false
+	}
+	g = g[1:]
+	if strings.HasPrefix(g, "B") {
+		return -1, true
+	}
+	end := 0
+	for end < len(g) && g[end] >= '0' && g[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+	v, err := strconv.Atoi(g[:end])
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// gradeAgreement compares a Kaya grade with an MP rating: grades on
+// different scales (V-scale vs YDS) conflict outright, and boulder grades
+// are compared by normalizeGrade.
+func gradeAgreement(kayaGrade, mpRating string) string {
+	kayaFamily, mpFamily := gradeFamily(kayaGrade), gradeFamily(mpRating)
+	if kayaFamily != "" && mpFamily != "" && kayaFamily != mpFamily {
+		return gradeAgreementConflict
+	}
+
+	kaya, ok := normalizeGrade(kayaGrade)
+	if !ok {
+		return gradeAgreementUnknown
+	}
+	mp, ok := normalizeGrade(mpRating)
+	if !ok {
+		return gradeAgreementUnknown
+	}
+
+	diff := kaya - mp
+	if diff < 0 {
+		diff = -diff
+	}
+	switch {
+	case diff <= gradeMatchSpread:
+		return gradeAgreementMatch
+	case diff >= gradeConflictSpread:
+		return gradeAgreementConflict
+	default:
+		return gradeAgreementNear
+	}
+}
+
+// applyGradeAgreement adjusts a match confidence for grade agreement, keeping
+// it within 0-1.
+func applyGradeAgreement(confidence float64, agreement string) float64 {
+	switch agreement {
+	case gradeAgreementMatch:
+		confidence += gradeMatchBonus
+	case gradeAgreementConflict:
+		confidence -= gradeConflictPenalty
+	}
+	return math.Max(0, math.Min(1, confidence))
+}
+
 func containsToken(value, token string) bool {
 	v := strings.ToLower(value)
 	t := strings.ToLower(token)
@@ -607,29 +892,6 @@ func toRadians(degrees float64) float64 {
 	return degrees * math.Pi / 180.0
 }
 
-func createSQLConnection() (*sql.DB, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
-
-	if port == "" {
-		port = "5432"
-	}
-	if sslmode == "" {
-		sslmode = "require"
-	}
-
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode,
-	)
-
-	return sql.Open("postgres", connStr)
-}
-
 func formatDistance(distKM *float64) string {
 	if distKM == nil {
 		return "N/A"
@@ -1,6 +1,16 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
+)
 
 func TestIsCompatibleMatch_HardRejectsBoulderToIceRouteType(t *testing.T) {
 	ok := isCompatibleMatch("Bouldering", "V4", "Ice", "WI2")
@@ -118,3 +128,251 @@ func TestGradeFamily(t *testing.T) {
 		})
 	}
 }
+
+func TestFindMPMatches_TrigramUsesDatabaseSimilarity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	// "The Egg" vs "Egg" isn't a substring match either way round, and
+	// Levenshtein would score it well below the trigram similarity.
+	columns := []string{"mp_route_id", "name", "area_name", "latitude", "longitude", "route_type", "rating", "similarity"}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE r.name % $1")).
+		WithArgs("The Egg").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("105", "Egg", "Leavenworth", nil, nil, "Boulder", "V3", 0.97))
+
+	climb := KayaClimb{ID: "k1", Name: "The Egg", Location: "Leavenworth", Grade: "V3", ClimbType: "Bouldering"}
+	matches := findMPMatches(context.Background(), db, climb, matchOptions{Thresholds: kayamatch.DefaultThresholds(), UseTrigram: true, Scorer: scorerLevenshtein})
+
+	if len(matches) != 1 {
+		t.Fatalf("findMPMatches() returned %d matches, want 1", len(matches))
+	}
+	if matches[0].NameSimilarity != 0.97 {
+		t.Errorf("NameSimilarity = %v, want the database's 0.97", matches[0].NameSimilarity)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFindMPMatches_DefaultScoresInGo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"mp_route_id", "name", "area_name", "latitude", "longitude", "route_type", "rating", "similarity"}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE LOWER(r.name) LIKE LOWER($1)")).
+		WithArgs("%Egg%").
+		WillReturnRows(sqlmock.NewRows(columns).AddRow("105", "The Egg", "Leavenworth", nil, nil, "Boulder", "V3", nil))
+
+	climb := KayaClimb{ID: "k1", Name: "Egg", Location: "Leavenworth", Grade: "V3", ClimbType: "Bouldering"}
+	matches := findMPMatches(context.Background(), db, climb, matchOptions{Thresholds: kayamatch.DefaultThresholds(), Scorer: scorerLevenshtein})
+
+	if len(matches) != 1 {
+		t.Fatalf("findMPMatches() returned %d matches, want 1", len(matches))
+	}
+	if want := calculateNameSimilarity("Egg", "The Egg", scorerLevenshtein); matches[0].NameSimilarity != want {
+		t.Errorf("NameSimilarity = %v, want Levenshtein score %v", matches[0].NameSimilarity, want)
+	}
+}
+
+func TestEnableTrigram_ExplainsMissingExtension(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE EXTENSION IF NOT EXISTS pg_trgm")).
+		WillReturnError(errors.New("permission denied to create extension"))
+
+	err = enableTrigram(context.Background(), db)
+	if err == nil || !strings.Contains(err.Error(), "CREATE EXTENSION pg_trgm") {
+		t.Errorf("enableTrigram() error = %v, want setup instructions", err)
+	}
+}
+
+func TestTokenSetSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Evilution Direct", "Direct Evilution", 1},
+		{"The Egg", "Egg", 1},
+		{"Egg", "an egg!", 1},
+		{"Evilution", "Evilution Direct", 0.5},
+		{"Mandala Sit", "Mandala", 0.5},
+		{"Hobbit Hole", "Dark Roof", 0},
+		{"", "Egg", 0},
+		{"The", "A", 0},
+	}
+	for _, tt := range tests {
+		if got := tokenSetSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("tokenSetSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateNameSimilarity_Scorers(t *testing.T) {
+	reordered := [2]string{"Evilution Direct", "Direct Evilution"}
+	lev := calculateNameSimilarity(reordered[0], reordered[1], scorerLevenshtein)
+	if lev >= 0.5 {
+		t.Errorf("levenshtein score for reordered words = %v, want it to penalize reordering", lev)
+	}
+	if got := calculateNameSimilarity(reordered[0], reordered[1], scorerJaccard); got != 1 {
+		t.Errorf("jaccard score for reordered words = %v, want 1", got)
+	}
+	if got := calculateNameSimilarity(reordered[0], reordered[1], scorerMax); got != 1 {
+		t.Errorf("max score for reordered words = %v, want 1", got)
+	}
+
+	// A one-letter typo shares no words, so max falls back to Levenshtein.
+	typo := [2]string{"Evilution", "Evilutoin"}
+	if got := calculateNameSimilarity(typo[0], typo[1], scorerJaccard); got != 0 {
+		t.Errorf("jaccard score for typo = %v, want 0", got)
+	}
+	want := calculateNameSimilarity(typo[0], typo[1], scorerLevenshtein)
+	if got := calculateNameSimilarity(typo[0], typo[1], scorerMax); got != want || want == 0 {
+		t.Errorf("max score for typo = %v, want levenshtein %v", got, want)
+	}
+}
+
+func TestIsValidScorer(t *testing.T) {
+	for _, scorer := range []string{scorerLevenshtein, scorerJaccard, scorerMax} {
+		if !isValidScorer(scorer) {
+			t.Errorf("isValidScorer(%q) = false", scorer)
+		}
+	}
+	if isValidScorer("cosine") {
+		t.Error("isValidScorer(cosine) = true")
+	}
+}
+
+func TestNormalizeGrade(t *testing.T) {
+	tests := []struct {
+		grade  string
+		want   int
+		wantOK bool
+	}{
+		{"V5", 5, true},
+		{"v12", 12, true},
+		{"V4+", 4, true},
+		{"V4-5", 4, true},
+		{"V4/5", 4, true},
+		{"V5 PG13", 5, true},
+		{"VB", -1, true},
+		{"7A", 6, true},
+		{"6C+", 5, true},
+		{"8B+", 14, true},
+		{"5C", 1, true},
+		{"5.11a", 0, false},
+		{"V", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := normalizeGrade(tt.grade)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("normalizeGrade(%q) = %d, %v, want %d, %v", tt.grade, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGradeAgreement(t *testing.T) {
+	tests := []struct {
+		kaya, mp string
+		want     string
+	}{
+		{"V5", "V5", gradeAgreementMatch},
+		{"V5", "V4-5", gradeAgreementMatch},
+		{"7A", "V6", gradeAgreementMatch},
+		{"V5", "V7", gradeAgreementNear},
+		{"V12", "V5", gradeAgreementConflict},
+		{"V12", "5.10a", gradeAgreementConflict},
+		{"", "V5", gradeAgreementUnknown},
+		{"V5", "", gradeAgreementUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := gradeAgreement(tt.kaya, tt.mp); got != tt.want {
+			t.Errorf("gradeAgreement(%q, %q) = %q, want %q", tt.kaya, tt.mp, got, tt.want)
+		}
+	}
+}
+
+func TestApplyGradeAgreement(t *testing.T) {
+	if got := applyGradeAgreement(0.8, gradeAgreementMatch); got < 0.849 || got > 0.851 {
+		t.Errorf("match: got %v, want 0.85", got)
+	}
+	if got := applyGradeAgreement(0.98, gradeAgreementMatch); got != 1.0 {
+		t.Errorf("match: got %v, want capped at 1.0", got)
+	}
+	if got := applyGradeAgreement(0.9, gradeAgreementConflict); got < 0.699 || got > 0.701 {
+		t.Errorf("conflict: got %v, want 0.7", got)
+	}
+	if got := applyGradeAgreement(0.8, gradeAgreementNear); got != 0.8 {
+		t.Errorf("near: got %v, want unchanged 0.8", got)
+	}
+}
+
+func TestFindMPMatches_GradeConflictLowersConfidence(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	// Same name and area, but a V12 and a V3 are different problems.
+	columns := []string{"mp_route_id", "name", "area_name", "latitude", "longitude", "route_type", "rating", "similarity"}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE LOWER(r.name) LIKE LOWER($1)")).
+		WithArgs("%The Mandala%").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow("105", "The Mandala", "Buttermilks", nil, nil, "Boulder", "V12", nil).
+			AddRow("106", "The Mandala", "Buttermilks", nil, nil, "Boulder", "V3", nil))
+
+	climb := KayaClimb{ID: "k1", Name: "The Mandala", Location: "Buttermilks", Grade: "V12", ClimbType: "Bouldering"}
+	matches := findMPMatches(context.Background(), db, climb, matchOptions{Thresholds: kayamatch.DefaultThresholds(), Scorer: scorerMax})
+
+	if len(matches) != 2 {
+		t.Fatalf("findMPMatches() returned %d matches, want 2", len(matches))
+	}
+	if matches[0].GradeAgreement != gradeAgreementMatch || matches[1].GradeAgreement != gradeAgreementConflict {
+		t.Errorf("GradeAgreement = %q, %q, want match, conflict", matches[0].GradeAgreement, matches[1].GradeAgreement)
+	}
+	if matches[0].Confidence <= matches[1].Confidence {
+		t.Errorf("Confidence = %v, %v, want the agreeing grade to score higher", matches[0].Confidence, matches[1].Confidence)
+	}
+	if matches[1].MPRating != "V3" || matches[1].KayaGrade != "V12" {
+		t.Errorf("grades = %q vs %q, want V12 vs V3 saved for review", matches[1].KayaGrade, matches[1].MPRating)
+	}
+}
+
+func TestNormalizeRouteName_FoldsDiacriticsAndQuotes(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Café", "cafe"},
+		{"Théorie du Complot", "theorie du complot"},
+		{"La Pequeña", "la pequena"},
+		{"Über Crimp", "uber crimp"},
+		{"L’Arête de l’Étoile", "larete de letoile"},
+		{"Bob’s “Big” Øyster", "bobs big oyster"},
+		{"Große Welle", "grosse welle"},
+		{"Le Toit du Cul de Chien", "le toit du cul de chien"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeRouteName(tt.name); got != tt.want {
+			t.Errorf("normalizeRouteName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := calculateNameSimilarity("Café Crème", "Cafe Creme", scorerLevenshtein); got != 1.0 {
+		t.Errorf("calculateNameSimilarity(Café Crème, Cafe Creme) = %v, want 1.0", got)
+	}
+}
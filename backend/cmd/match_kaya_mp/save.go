@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultSaveBatchSize is how many matches matchSaver upserts per INSERT.
+// At 15 parameters a row, 500 rows stay well under Postgres's 65535
+// parameter limit.
+const defaultSaveBatchSize = 500
+
+// upsertMatchesColumns are the kaya_mp_route_matches columns a saved match
+// sets, in the order of matchSaver's query arguments.
+var upsertMatchesColumns = []string{
+	"kaya_climb_id", "mp_route_id", "match_confidence", "match_type",
+	"kaya_climb_name", "kaya_location_name",
+	"mp_route_name", "mp_area_name",
+	"name_similarity", "location_name_match", "location_distance_km",
+	"kaya_grade", "mp_rating", "grade_agreement",
+	"match_status",
+}
+
+// upsertMatchesConflict updates an existing match in place. Matches a
+// reviewer has verified or rejected keep their existing status.
+const upsertMatchesConflict = `
+	ON CONFLICT (kaya_climb_id, mp_route_id) DO UPDATE SET
+		match_confidence = EXCLUDED.match_confidence,
+		match_type = EXCLUDED.match_type,
+		name_similarity = EXCLUDED.name_similarity,
+		location_name_match = EXCLUDED.location_name_match,
+		location_distance_km = EXCLUDED.location_distance_km,
+		kaya_grade = EXCLUDED.kaya_grade,
+		mp_rating = EXCLUDED.mp_rating,
+		grade_agreement = EXCLUDED.grade_agreement,
+		match_status = CASE
+			WHEN kaya_mp_route_matches.is_verified OR kaya_mp_route_matches.match_status = 'rejected'
+				THEN kaya_mp_route_matches.match_status
+			ELSE EXCLUDED.match_status
+		END,
+		updated_at = CURRENT_TIMESTAMP
+`
+
+// buildUpsertMatchesQuery returns a multi-row upsert of rows matches.
+func buildUpsertMatchesQuery(rows int) string {
+	var b strings.Builder
+	b.WriteString("INSERT INTO kaya_mp_route_matches (")
+	b.WriteString(strings.Join(upsertMatchesColumns, ", "))
+	b.WriteString(") VALUES ")
+
+	cols := len(upsertMatchesColumns)
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("(")
+		for c := 0; c < cols; c++ {
+			if c > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", r*cols+c+1)
+		}
+		b.WriteString(")")
+	}
+
+	b.WriteString(upsertMatchesConflict)
+	return b.String()
+}
+
+// pendingMatch is a match waiting in matchSaver, with its review status.
+type pendingMatch struct {
+	match  RouteMatch
+	status string
+}
+
+// matchSaver buffers matches and upserts them in batches, one multi-row
+// INSERT per transaction, instead of a round trip per match. Call Flush once
+// the last match is added.
+type matchSaver struct {
+	db            *sql.DB
+	batchSize     int
+	pending       []pendingMatch
+	saved         int
+	savedByStatus map[string]int
+}
+
+func newMatchSaver(db *sql.DB, batchSize int) *matchSaver {
+	return &matchSaver{db: db, batchSize: batchSize, savedByStatus: make(map[string]int)}
+}
+
+// Add queues a match, flushing the batch once it's full. An error is the
+// failed flush's (see Flush).
+func (s *matchSaver) Add(ctx context.Context, match RouteMatch, status string) error {
+	s.pending = append(s.pending, pendingMatch{match: match, status: status})
+	if len(s.pending) >= s.batchSize {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush upserts the queued matches. A pair queued more than once is saved
+// once, with its last values, since Postgres rejects an upsert that touches
+// the same row twice. If the batch's INSERT fails, its halves are retried
+// separately, down to single rows, so one bad row doesn't cost the rest of
+// the batch. The queue is emptied either way; the error names the rows that
+// couldn't be saved.
+func (s *matchSaver) Flush(ctx context.Context) error {
+	batch := dedupePendingMatches(s.pending)
+	s.pending = s.pending[:0]
+	if len(batch) == 0 {
+		return nil
+	}
+
+	failed, err := s.saveBatch(ctx, batch)
+	if err != nil {
+		return fmt.Errorf("failed to save %d of %d matches: %w", failed, len(batch), err)
+	}
+	return nil
+}
+
+// saveBatch upserts batch, splitting it in half on an INSERT error. It
+// returns how many matches weren't saved and the first error.
+func (s *matchSaver) saveBatch(ctx context.Context, batch []pendingMatch) (int, error) {
+	err := s.upsert(ctx, batch)
+	if err == nil {
+		s.saved += len(batch)
+		for _, p := range batch {
+			s.savedByStatus[p.status]++
+		}
+		return 0, nil
+	}
+
+	var insertErr *upsertError
+	if len(batch) == 1 || !errors.As(err, &insertErr) {
+		// A single bad row, or a failure that isn't the rows' fault (the
+		// connection or commit), which smaller batches won't fix.
+		return len(batch), err
+	}
+
+	mid := len(batch) / 2
+	failedFirst, errFirst := s.saveBatch(ctx, batch[:mid])
+	failedSecond, errSecond := s.saveBatch(ctx, batch[mid:])
+	if errFirst == nil {
+		errFirst = errSecond
+	}
+	return failedFirst + failedSecond, errFirst
+}
+
+// upsertError is an INSERT rejected because of the rows in it, e.g. a value
+// too long for its column.
+type upsertError struct {
+	batch []pendingMatch
+	err   error
+}
+
+func (e *upsertError) Error() string {
+	if len(e.batch) == 1 {
+		m := e.batch[0].match
+		return fmt.Sprintf("match %s -> %d: %v", m.KayaClimbID, m.MPRouteID, e.err)
+	}
+	return fmt.Sprintf("%d matches: %v", len(e.batch), e.err)
+}
+
+func (e *upsertError) Unwrap() error { return e.err }
+
+// upsert saves batch in one transaction.
+func (s *matchSaver) upsert(ctx context.Context, batch []pendingMatch) error {
+	args := make([]interface{}, 0, len(batch)*len(upsertMatchesColumns))
+	for _, p := range batch {
+		m := p.match
+		args = append(args,
+			m.KayaClimbID,
+			m.MPRouteID,
+			m.Confidence,
+			m.MatchType,
+			m.KayaClimbName,
+			m.KayaLocationName,
+			m.MPRouteName,
+			m.MPAreaName,
+			m.NameSimilarity,
+			m.LocationNameMatch,
+			m.DistanceKM,
+			m.KayaGrade,
+			m.MPRating,
+			m.GradeAgreement,
+			p.status,
+		)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, buildUpsertMatchesQuery(len(batch)), args...); err != nil {
+		return &upsertError{batch: batch, err: err}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %d matches: %w", len(batch), err)
+	}
+	return nil
+}
+
+// Saved returns how many matches have been saved so far.
+func (s *matchSaver) Saved() int {
+	return s.saved
+}
+
+// SavedWithStatus returns how many of the saved matches were queued with
+// status.
+func (s *matchSaver) SavedWithStatus(status string) int {
+	return s.savedByStatus[status]
+}
+
+// dedupePendingMatches keeps the last of each (Kaya climb, MP route) pair,
+// in the order pairs were first queued.
+func dedupePendingMatches(pending []pendingMatch) []pendingMatch {
+	type pair struct {
+		climbID string
+		routeID int64
+	}
+	index := make(map[pair]int, len(pending))
+	batch := make([]pendingMatch, 0, len(pending))
+	for _, p := range pending {
+		key := pair{p.match.KayaClimbID, p.match.MPRouteID}
+		if i, ok := index[key]; ok {
+			batch[i] = p
+			continue
+		}
+		index[key] = len(batch)
+		batch = append(batch, p)
+	}
+	return batch
+}
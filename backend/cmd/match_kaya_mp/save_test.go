@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
+)
+
+func saveTestMatch(climbID string, routeID int64, confidence float64) RouteMatch {
+	return RouteMatch{
+		KayaClimbID: climbID, KayaClimbName: climbID, MPRouteID: routeID, MPRouteName: "Route",
+		Confidence: confidence, MatchType: "exact_name", NameSimilarity: 1.0, GradeAgreement: gradeAgreementMatch,
+	}
+}
+
+func TestBuildUpsertMatchesQuery(t *testing.T) {
+	query := buildUpsertMatchesQuery(2)
+	cols := len(upsertMatchesColumns)
+
+	if !strings.Contains(query, "($1, $2,") || !strings.Contains(query, "($16, $17,") {
+		t.Errorf("query placeholders don't number the second row from $%d:\n%s", cols+1, query)
+	}
+	if strings.Contains(query, "$31") {
+		t.Errorf("query has more than %d placeholders:\n%s", 2*cols, query)
+	}
+	if !strings.Contains(query, "ON CONFLICT (kaya_climb_id, mp_route_id) DO UPDATE") {
+		t.Errorf("query isn't an upsert:\n%s", query)
+	}
+}
+
+func TestMatchSaver_FlushesInBatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	anyArgs := func(n int) []driver.Value {
+		args := make([]driver.Value, n)
+		for i := range args {
+			args[i] = sqlmock.AnyArg()
+		}
+		return args
+	}
+	cols := len(upsertMatchesColumns)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO kaya_mp_route_matches")).
+		WithArgs(anyArgs(2 * cols)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO kaya_mp_route_matches")).
+		WithArgs(anyArgs(cols)...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx := context.Background()
+	saver := newMatchSaver(db, 2)
+	for i, m := range []RouteMatch{saveTestMatch("a", 1, 0.9), saveTestMatch("b", 2, 0.8), saveTestMatch("c", 3, 0.95)} {
+		if err := saver.Add(ctx, m, kayamatch.StatusApproved); err != nil {
+			t.Fatalf("Add(%d) error = %v", i, err)
+		}
+	}
+	if saver.Saved() != 2 {
+		t.Errorf("Saved() = %d after the first batch, want 2", saver.Saved())
+	}
+	if err := saver.Flush(ctx); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if saver.Saved() != 3 {
+		t.Errorf("Saved() = %d, want 3", saver.Saved())
+	}
+	if err := saver.Flush(ctx); err != nil {
+		t.Errorf("Flush() with nothing queued error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMatchSaver_RollsBackFailedBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO kaya_mp_route_matches")).
+		WillReturnError(errors.New("value too long"))
+	mock.ExpectRollback()
+
+	ctx := context.Background()
+	saver := newMatchSaver(db, 10)
+	saver.Add(ctx, saveTestMatch("a", 1, 0.9), kayamatch.StatusApproved)
+	if err := saver.Flush(ctx); err == nil || !strings.Contains(err.Error(), "value too long") {
+		t.Errorf("Flush() error = %v, want the insert error", err)
+	}
+	if saver.Saved() != 0 || len(saver.pending) != 0 {
+		t.Errorf("Saved() = %d, pending = %d, want the failed batch dropped", saver.Saved(), len(saver.pending))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMatchSaver_SplitsFailedBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	insert := regexp.QuoteMeta("INSERT INTO kaya_mp_route_matches")
+	badRow := errors.New("value too long")
+	expectInsert := func(err error) {
+		mock.ExpectBegin()
+		if err != nil {
+			mock.ExpectExec(insert).WillReturnError(err)
+			mock.ExpectRollback()
+			return
+		}
+		mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+	expectInsert(badRow) // a, b, c
+	expectInsert(nil)    // a
+	expectInsert(badRow) // b, c
+	expectInsert(badRow) // b
+	expectInsert(nil)    // c
+
+	ctx := context.Background()
+	saver := newMatchSaver(db, 10)
+	saver.Add(ctx, saveTestMatch("a", 1, 0.95), kayamatch.StatusApproved)
+	saver.Add(ctx, saveTestMatch("b", 2, 0.95), kayamatch.StatusApproved)
+	saver.Add(ctx, saveTestMatch("c", 3, 0.8), kayamatch.StatusPending)
+
+	err = saver.Flush(ctx)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") || !strings.Contains(err.Error(), "b -> 2") {
+		t.Errorf("Flush() error = %v, want the one bad row named", err)
+	}
+	if saver.Saved() != 2 {
+		t.Errorf("Saved() = %d, want 2", saver.Saved())
+	}
+	if got := saver.SavedWithStatus(kayamatch.StatusApproved); got != 1 {
+		t.Errorf("SavedWithStatus(approved) = %d, want 1", got)
+	}
+	if got := saver.SavedWithStatus(kayamatch.StatusPending); got != 1 {
+		t.Errorf("SavedWithStatus(pending) = %d, want 1", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDedupePendingMatches(t *testing.T) {
+	pending := []pendingMatch{
+		{saveTestMatch("a", 1, 0.8), kayamatch.StatusPending},
+		{saveTestMatch("b", 2, 0.9), kayamatch.StatusApproved},
+		{saveTestMatch("a", 1, 0.95), kayamatch.StatusApproved},
+	}
+
+	got := dedupePendingMatches(pending)
+	if len(got) != 2 {
+		t.Fatalf("got %d matches, want 2", len(got))
+	}
+	if got[0].match.KayaClimbID != "a" || got[0].match.Confidence != 0.95 || got[0].status != kayamatch.StatusApproved {
+		t.Errorf("first = %+v, want the last values queued for a/1", got[0])
+	}
+	if got[1].match.KayaClimbID != "b" {
+		t.Errorf("second = %+v, want b/2", got[1])
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// unmatchedMPRoutesQuery lists MP routes with no Kaya match, optionally in
+// locations or areas whose name contains $1. A route whose only matches were
+// rejected by a reviewer counts as unmatched.
+const unmatchedMPRoutesQuery = `
+	SELECT
+		r.mp_route_id,
+		r.name,
+		COALESCE(a.name, 'Unknown') as area_name,
+		COALESCE(r.rating, '') as rating,
+		COALESCE(r.route_type, '') as route_type,
+		COALESCE(l.name, '') as location_name
+	FROM woulder.mp_routes r
+	LEFT JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+	LEFT JOIN woulder.locations l ON r.location_id = l.id
+	WHERE NOT EXISTS (
+			SELECT 1 FROM kaya_mp_route_matches m
+			WHERE m.mp_route_id = r.mp_route_id
+				AND m.match_status != 'rejected'
+		)
+		AND ($1 = '' OR LOWER(l.name) LIKE LOWER('%' || $1 || '%') OR LOWER(a.name) LIKE LOWER('%' || $1 || '%'))
+	ORDER BY l.name, a.name, r.name
+`
+
+// unmatchedRoute is one row of the --unmatched-mp report.
+type unmatchedRoute struct {
+	MPRouteID    int64  `json:"mp_route_id"`
+	MPRouteName  string `json:"mp_route_name"`
+	MPAreaName   string `json:"mp_area_name"`
+	MPRating     string `json:"mp_rating"`
+	RouteType    string `json:"route_type"`
+	LocationName string `json:"location_name"`
+}
+
+// unmatchedCSVHeader names the CSV columns, in the order of
+// unmatchedRoute.csvRecord.
+var unmatchedCSVHeader = []string{
+	"mp_route_id", "mp_route_name", "mp_area_name", "mp_rating", "route_type", "location_name",
+}
+
+func (r unmatchedRoute) csvRecord() []string {
+	return []string{
+		strconv.FormatInt(r.MPRouteID, 10), r.MPRouteName, r.MPAreaName, r.MPRating, r.RouteType, r.LocationName,
+	}
+}
+
+// writeUnmatchedMPRoutes streams the MP routes with no Kaya match to out and
+// returns how many it wrote. An empty location lists every location.
+func writeUnmatchedMPRoutes(ctx context.Context, db *sql.DB, location string, out rowWriter) (int, error) {
+	rows, err := db.QueryContext(ctx, unmatchedMPRoutesQuery, location)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query unmatched MP routes: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var r unmatchedRoute
+		if err := rows.Scan(&r.MPRouteID, &r.MPRouteName, &r.MPAreaName, &r.MPRating, &r.RouteType, &r.LocationName); err != nil {
+			return count, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := out.WriteRow(r); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, rows.Err()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestWriteUnmatchedMPRoutes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	columns := []string{"mp_route_id", "name", "area_name", "rating", "route_type", "location_name"}
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE NOT EXISTS")).
+		WithArgs("Leavenworth").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(105, "Egg, The", "Forestland", "V3", "Boulder", "Leavenworth").
+			AddRow(106, "Carbide Finger", "Forestland", "V5", "Boulder", "Leavenworth"))
+
+	var buf bytes.Buffer
+	out, err := newRowWriter(&buf, formatCSV, unmatchedCSVHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := writeUnmatchedMPRoutes(context.Background(), db, "Leavenworth", out)
+	if err != nil {
+		t.Fatalf("writeUnmatchedMPRoutes() error = %v", err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output isn't valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d rows, want header and 2 routes", len(records))
+	}
+	want := []string{"105", "Egg, The", "Forestland", "V3", "Boulder", "Leavenworth"}
+	for i := range want {
+		if records[1][i] != want[i] {
+			t.Errorf("column %s = %q, want %q", unmatchedCSVHeader[i], records[1][i], want[i])
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,17 +1,28 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database/migrations"
 )
 
 type Migration struct {
@@ -22,45 +33,58 @@ type Migration struct {
 }
 
 func main() {
-	// Load .env file if it exists
-	// Try multiple paths depending on working directory
-	envPaths := []string{
-		".env",                            // current directory
-		filepath.Join("..", ".env"),       // one level up (e.g., from cmd/)
-		filepath.Join("..", "..", ".env"), // two levels up (e.g., from cmd/migrate/)
-	}
-	envLoaded := false
-	for _, envPath := range envPaths {
-		if err := godotenv.Load(envPath); err == nil {
-			envLoaded = true
-			break
-		}
-	}
-	if !envLoaded {
-		log.Println("Warning: .env file not found, using environment variables")
-	}
+	// Flags
+	//   -dir: read migrations from this directory instead of the ones built
+	//     into the binary, e.g. to apply a file just added with create.
+	//   -lock-timeout: how long up/down/step/force wait for another run of
+	//     this tool to finish before giving up.
+	dir := flag.String("dir", "", "Read migrations from this directory instead of the embedded files")
+	lockTimeout := flag.Duration("lock-timeout", time.Minute, "How long to wait for another migration run to finish")
+	flag.Usage = printHelp
+	flag.Parse()
 
-	// Get database connection details from environment
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
+	// Parse command
+	command := "up"
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+	}
 
-	if port == "" {
-		port = "5432"
+	if command == "help" {
+		printHelp()
+		return
 	}
-	if sslmode == "" {
-		sslmode = "require"
+
+	// create only writes files, so it runs without a database connection.
+	// It needs the source directory, since embedded files are read-only.
+	if command == "create" {
+		if flag.NArg() < 2 {
+			log.Fatal("Usage: migrate [-dir path] create <name>")
+		}
+		migrationsPath := *dir
+		if migrationsPath == "" {
+			migrationsPath = os.Getenv("MIGRATIONS_PATH")
+		}
+		if migrationsPath == "" {
+			migrationsPath = defaultMigrationsPath()
+		}
+		upPath, downPath, err := createMigration(migrationsPath, flag.Arg(1))
+		if err != nil {
+			log.Fatalf("Create migration failed: %v", err)
+		}
+		log.Printf("✓ Created %s", upPath)
+		log.Printf("✓ Created %s", downPath)
+		return
 	}
 
-	// Construct connection string
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode)
+	// Load configuration (also reads .env from the working directory or
+	// up to two levels above it, e.g. when run from cmd/migrate/)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 
 	// Open database connection
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -71,52 +95,75 @@ func main() {
 		log.Fatalf("Failed to ping database: %v", err)
 	}
 
+	// Commands that change the schema hold the migration lock until exit,
+	// so concurrent deploys can't apply the same migration twice. If a
+	// command exits via log.Fatal, closing the session releases it.
+	switch command {
+	case "up", "down", "step", "force":
+		release, err := acquireMigrationLock(context.Background(), db, *lockTimeout, time.Second)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer release()
+	}
+
 	// Create schema_migrations table if it doesn't exist
 	if err := createMigrationsTable(db); err != nil {
 		log.Fatalf("Failed to create migrations table: %v", err)
 	}
 
-	// Parse command
-	command := "up"
-	if len(os.Args) > 1 {
-		command = os.Args[1]
-	}
-
-	// Get migrations directory. MIGRATIONS_PATH is used by deployment where the
-	// migrate binary runs outside the source tree.
-	migrationsPath := os.Getenv("MIGRATIONS_PATH")
+	// Migrations are compiled into the binary, so it works from any
+	// directory. -dir, or MIGRATIONS_PATH as set by older deploy scripts,
+	// reads them from disk instead.
+	var fsys fs.FS = migrations.FS
+	migrationsPath := *dir
 	if migrationsPath == "" {
-		migrationsPath = defaultMigrationsPath()
+		migrationsPath = cfg.Database.MigrationsPath
+	}
+	if migrationsPath != "" {
+		if info, err := os.Stat(migrationsPath); err != nil || !info.IsDir() {
+			log.Fatalf("Migrations directory %s not found", migrationsPath)
+		}
+		log.Printf("Reading migrations from %s", migrationsPath)
+		fsys = os.DirFS(migrationsPath)
 	}
 
 	// Execute command
 	switch command {
 	case "up":
-		if err := migrateUp(db, migrationsPath); err != nil {
+		if err := migrateUp(db, fsys); err != nil {
 			log.Fatalf("Migration up failed: %v", err)
 		}
 
 	case "down":
-		if err := migrateDown(db, migrationsPath); err != nil {
+		if err := migrateDown(db, fsys); err != nil {
 			log.Fatalf("Migration down failed: %v", err)
 		}
 
 	case "version":
-		version, err := getCurrentVersion(db)
+		version, dirty, err := getCurrentVersion(db)
 		if err != nil {
 			log.Fatalf("Failed to get version: %v", err)
 		}
+		if dirty {
+			log.Fatal(dirtyError(version))
+		}
 		if version == 0 {
 			log.Println("No migrations have been run yet")
 		} else {
 			log.Printf("Current version: %d\n", version)
 		}
 
+	case "status":
+		if err := showStatus(db, fsys, os.Stdout); err != nil {
+			log.Fatalf("Failed to get status: %v", err)
+		}
+
 	case "force":
-		if len(os.Args) < 3 {
+		if flag.NArg() < 2 {
 			log.Fatal("Usage: migrate force <version>")
 		}
-		version, err := strconv.Atoi(os.Args[2])
+		version, err := strconv.Atoi(flag.Arg(1))
 		if err != nil {
 			log.Fatalf("Invalid version number: %v", err)
 		}
@@ -126,20 +173,17 @@ func main() {
 		log.Printf("✓ Forced version to %d\n", version)
 
 	case "step":
-		if len(os.Args) < 3 {
+		if flag.NArg() < 2 {
 			log.Fatal("Usage: migrate step <n>")
 		}
-		steps, err := strconv.Atoi(os.Args[2])
+		steps, err := strconv.Atoi(flag.Arg(1))
 		if err != nil {
 			log.Fatalf("Invalid step number: %v", err)
 		}
-		if err := migrateSteps(db, migrationsPath, steps); err != nil {
+		if err := migrateSteps(db, fsys, steps); err != nil {
 			log.Fatalf("Migration step failed: %v", err)
 		}
 
-	case "help":
-		printHelp()
-
 	default:
 		fmt.Printf("Unknown command: %s\n\n", command)
 		printHelp()
@@ -147,13 +191,68 @@ func main() {
 	}
 }
 
+// migrationLockKey is the pg_advisory_lock key held while migrations run.
+// It is arbitrary but fixed, so every run of this tool contends for it.
+const migrationLockKey int64 = 8_314_270_115
+
+// acquireMigrationLock takes the migration advisory lock on a dedicated
+// connection, since session-level locks belong to one connection, polling
+// every interval until timeout if another run holds it. release unlocks it
+// and returns the connection to the pool.
+func acquireMigrationLock(ctx context.Context, db *sql.DB, timeout, interval time.Duration) (release func(), err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for migration lock: %v", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	waiting := false
+	for {
+		var locked bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrationLockKey).Scan(&locked); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to take migration lock: %v", err)
+		}
+		if locked {
+			break
+		}
+		if !time.Now().Before(deadline) {
+			conn.Close()
+			return nil, fmt.Errorf("another migration is in progress (waited %s for its lock); try again once it finishes", timeout)
+		}
+		if !waiting {
+			log.Printf("Another migration is in progress; waiting up to %s...", timeout)
+			waiting = true
+		}
+		time.Sleep(interval)
+	}
+
+	return func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+		conn.Close()
+	}, nil
+}
+
 func createMigrationsTable(db *sql.DB) error {
 	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			version INTEGER PRIMARY KEY,
+			dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			checksum TEXT,
 			applied_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
+	if err != nil {
+		return err
+	}
+	// Tables created before dirty tracking and checksums lack the columns.
+	_, err = db.Exec(`
+		ALTER TABLE schema_migrations
+			ADD COLUMN IF NOT EXISTS dirty BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS checksum TEXT
+	`)
 	return err
 }
 
@@ -171,33 +270,62 @@ func defaultMigrationsPath() string {
 	return candidates[0]
 }
 
-func getCurrentVersion(db *sql.DB) (int, error) {
-	var version int
-	err := db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+// getCurrentVersion returns the latest recorded migration version (0 if none)
+// and whether it is dirty: a migration at that version started but did not
+// finish, so the schema may be half-migrated.
+func getCurrentVersion(db *sql.DB) (version int, dirty bool, err error) {
+	err = db.QueryRow("SELECT version, dirty FROM schema_migrations ORDER BY version DESC LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
+
+// getCleanVersion is getCurrentVersion for commands that change the schema:
+// it refuses to go on from a dirty version.
+func getCleanVersion(db *sql.DB) (int, error) {
+	version, dirty, err := getCurrentVersion(db)
 	if err != nil {
 		return 0, err
 	}
+	if dirty {
+		return 0, dirtyError(version)
+	}
 	return version, nil
 }
 
+// dirtyError explains how to recover from a dirty version.
+func dirtyError(version int) error {
+	return fmt.Errorf("database is dirty at version %d: a migration failed partway and may have left partial changes. "+
+		"Fix the schema by hand, then run `migrate force <version>` with the last version that is fully applied", version)
+}
+
+// forceVersion records version as the only applied migration, and clean.
 func forceVersion(db *sql.DB, version int) error {
 	_, err := db.Exec("DELETE FROM schema_migrations")
 	if err != nil {
 		return err
 	}
 	if version > 0 {
-		_, err = db.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", version)
+		_, err = db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)", version)
 	}
 	return err
 }
 
-func loadMigrations(migrationsPath string) ([]Migration, error) {
-	files, err := os.ReadDir(migrationsPath)
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	files, err := fs.ReadDir(fsys, ".")
 	if err != nil {
 		return nil, err
 	}
 
 	migrationsMap := make(map[int]*Migration)
+	// filesByName groups each version's up/down files by migration name, so
+	// two migrations sharing a version number can be reported instead of one
+	// silently replacing the other.
+	filesByName := make(map[int]map[string][]string)
 
 	for _, file := range files {
 		if file.IsDir() {
@@ -226,17 +354,29 @@ func loadMigrations(migrationsPath string) ([]Migration, error) {
 			migrationsMap[version] = migration
 		}
 
-		fullPath := filepath.Join(migrationsPath, name)
-
+		var migrationName string
 		if strings.HasSuffix(name, ".up.sql") {
-			migration.UpPath = fullPath
-			migration.Name = strings.TrimSuffix(parts[1], ".up.sql")
+			migrationName = strings.TrimSuffix(parts[1], ".up.sql")
+			migration.UpPath = name
+			migration.Name = migrationName
 		} else if strings.HasSuffix(name, ".down.sql") {
-			migration.DownPath = fullPath
+			migrationName = strings.TrimSuffix(parts[1], ".down.sql")
+			migration.DownPath = name
 			if migration.Name == "" {
-				migration.Name = strings.TrimSuffix(parts[1], ".down.sql")
+				migration.Name = migrationName
 			}
+		} else {
+			continue
+		}
+
+		if filesByName[version] == nil {
+			filesByName[version] = make(map[string][]string)
 		}
+		filesByName[version][migrationName] = append(filesByName[version][migrationName], name)
+	}
+
+	if err := checkDuplicateVersions(filesByName); err != nil {
+		return nil, err
 	}
 
 	// Convert map to sorted slice
@@ -252,75 +392,229 @@ func loadMigrations(migrationsPath string) ([]Migration, error) {
 	return migrations, nil
 }
 
-func migrateUp(db *sql.DB, migrationsPath string) error {
-	log.Println("Running migrations up...")
+// checkDuplicateVersions returns an error naming every file involved when more
+// than one migration uses the same version number.
+func checkDuplicateVersions(filesByName map[int]map[string][]string) error {
+	var versions []int
+	for version, byName := range filesByName {
+		if len(byName) > 1 {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil
+	}
+	sort.Ints(versions)
 
-	currentVersion, err := getCurrentVersion(db)
-	if err != nil {
-		return err
+	conflicts := make([]string, 0, len(versions))
+	for _, version := range versions {
+		var files []string
+		for _, names := range filesByName[version] {
+			files = append(files, names...)
+		}
+		sort.Strings(files)
+		conflicts = append(conflicts, fmt.Sprintf("version %d: %s", version, strings.Join(files, ", ")))
 	}
+	return fmt.Errorf("duplicate migration versions (%s)", strings.Join(conflicts, "; "))
+}
 
-	migrations, err := loadMigrations(migrationsPath)
+// appliedMigration is a schema_migrations row.
+type appliedMigration struct {
+	Dirty     bool
+	AppliedAt sql.NullTime
+}
+
+// statusRow is one line of the status table.
+type statusRow struct {
+	Version   int
+	Name      string
+	State     string // "applied", "dirty", "pending" or "missing file"
+	AppliedAt string // "-" if unknown
+}
+
+// loadAppliedMigrations returns the schema_migrations rows by version.
+func loadAppliedMigrations(db *sql.DB) (map[int]appliedMigration, error) {
+	rows, err := db.Query("SELECT version, dirty, applied_at FROM schema_migrations")
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var a appliedMigration
+		if err := rows.Scan(&version, &a.Dirty, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = a
+	}
+	return applied, rows.Err()
+}
+
+// migrationStatus lists every migration on disk and every recorded version
+// without a file, in version order. Versions at or below the latest recorded
+// one count as applied even without their own row, as they do for up (force
+// records only the forced version).
+func migrationStatus(migrations []Migration, applied map[int]appliedMigration) []statusRow {
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
 	}
 
-	appliedCount := 0
-	for _, migration := range migrations {
-		if migration.Version <= currentVersion {
-			continue
+	rows := make([]statusRow, 0, len(migrations))
+	onDisk := make(map[int]bool, len(migrations))
+	for _, m := range migrations {
+		onDisk[m.Version] = true
+		row := statusRow{Version: m.Version, Name: m.Name, State: "pending", AppliedAt: "-"}
+		if a, ok := applied[m.Version]; ok {
+			row.State = "applied"
+			if a.Dirty {
+				row.State = "dirty"
+			}
+			if a.AppliedAt.Valid {
+				row.AppliedAt = a.AppliedAt.Time.Local().Format("2006-01-02 15:04:05")
+			}
+		} else if m.Version <= current {
+			row.State = "applied"
 		}
+		rows = append(rows, row)
+	}
 
-		if migration.UpPath == "" {
-			return fmt.Errorf("missing up migration for version %d", migration.Version)
+	for version, a := range applied {
+		if onDisk[version] {
+			continue
 		}
+		row := statusRow{Version: version, Name: "-", State: "missing file", AppliedAt: "-"}
+		if a.AppliedAt.Valid {
+			row.AppliedAt = a.AppliedAt.Time.Local().Format("2006-01-02 15:04:05")
+		}
+		rows = append(rows, row)
+	}
 
-		log.Printf("Applying migration %d: %s...", migration.Version, migration.Name)
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Version < rows[j].Version
+	})
+	return rows
+}
 
-		sqlContent, err := os.ReadFile(migration.UpPath)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file: %v", err)
+// showStatus prints a table of every migration's state to w, followed by how
+// many are pending.
+func showStatus(db *sql.DB, fsys fs.FS, w io.Writer) error {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	applied, err := loadAppliedMigrations(db)
+	if err != nil {
+		return err
+	}
+
+	printStatus(w, migrationStatus(migrations, applied))
+	return nil
+}
+
+// printStatus writes the status table and summary for rows.
+func printStatus(w io.Writer, rows []statusRow) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATE\tAPPLIED AT")
+	pending, dirty, missing := 0, 0, 0
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%06d\t%s\t%s\t%s\n", r.Version, r.Name, r.State, r.AppliedAt)
+		switch r.State {
+		case "pending":
+			pending++
+		case "dirty":
+			dirty++
+		case "missing file":
+			missing++
 		}
+	}
+	tw.Flush()
 
-		sqlString := string(sqlContent)
+	fmt.Fprintln(w)
+	if pending == 0 {
+		fmt.Fprintln(w, "✓ No pending migrations")
+	} else {
+		fmt.Fprintf(w, "%d pending migration(s)\n", pending)
+	}
+	if dirty > 0 {
+		fmt.Fprintf(w, "%d dirty migration(s): see `migrate force`\n", dirty)
+	}
+	if missing > 0 {
+		fmt.Fprintf(w, "%d applied migration(s) have no file on disk\n", missing)
+	}
+}
 
-		// Check if migration contains CONCURRENTLY (cannot run in transaction)
-		usesConcurrently := strings.Contains(strings.ToUpper(sqlString), "CONCURRENTLY")
+// createMigration writes empty up and down files for a new migration called
+// name, numbered one past the highest existing version, and returns their
+// paths.
+func createMigration(migrationsPath, name string) (upPath, downPath string, err error) {
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is empty")
+	}
+	if strings.IndexFunc(name, unicode.IsSpace) >= 0 || strings.ContainsAny(name, `/\`) {
+		return "", "", fmt.Errorf("invalid migration name %q: must not contain spaces or path separators", name)
+	}
 
-		if usesConcurrently {
-			// Execute without transaction for CONCURRENT operations
-			log.Printf("  (running without transaction due to CONCURRENTLY)")
+	migrations, err := loadMigrations(os.DirFS(migrationsPath))
+	if err != nil {
+		return "", "", fmt.Errorf("read %s: %w", migrationsPath, err)
+	}
+	version := 1
+	if len(migrations) > 0 {
+		version = migrations[len(migrations)-1].Version + 1
+	}
 
-			if _, err := db.Exec(sqlString); err != nil {
-				return fmt.Errorf("migration %d failed: %v", migration.Version, err)
-			}
+	base := filepath.Join(migrationsPath, fmt.Sprintf("%06d_%s", version, name))
+	upPath, downPath = base+".up.sql", base+".down.sql"
 
-			if _, err := db.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version); err != nil {
-				return fmt.Errorf("failed to record migration: %v", err)
-			}
-		} else {
-			// Execute migration in a transaction (normal case)
-			tx, err := db.Begin()
-			if err != nil {
-				return err
-			}
+	if err := createEmptyFile(upPath); err != nil {
+		return "", "", err
+	}
+	if err := createEmptyFile(downPath); err != nil {
+		os.Remove(upPath)
+		return "", "", err
+	}
+	return upPath, downPath, nil
+}
 
-			if _, err := tx.Exec(sqlString); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("migration %d failed: %v", migration.Version, err)
-			}
+// createEmptyFile creates an empty file at path, failing if it exists.
+func createEmptyFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
 
-			if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to record migration: %v", err)
-			}
+func migrateUp(db *sql.DB, fsys fs.FS) error {
+	log.Println("Running migrations up...")
 
-			if err := tx.Commit(); err != nil {
-				return fmt.Errorf("failed to commit migration: %v", err)
-			}
-		}
+	currentVersion, err := getCleanVersion(db)
+	if err != nil {
+		return err
+	}
 
-		log.Printf("✓ Applied migration %d", migration.Version)
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyChecksums(db, fsys, migrations); err != nil {
+		return err
+	}
+
+	appliedCount := 0
+	for _, migration := range migrations {
+		if migration.Version <= currentVersion {
+			continue
+		}
+		if err := applyUp(db, fsys, migration); err != nil {
+			return err
+		}
 		appliedCount++
 	}
 
@@ -333,10 +627,10 @@ func migrateUp(db *sql.DB, migrationsPath string) error {
 	return nil
 }
 
-func migrateDown(db *sql.DB, migrationsPath string) error {
+func migrateDown(db *sql.DB, fsys fs.FS) error {
 	log.Println("Rolling back migrations...")
 
-	currentVersion, err := getCurrentVersion(db)
+	currentVersion, err := getCleanVersion(db)
 	if err != nil {
 		return err
 	}
@@ -346,7 +640,7 @@ func migrateDown(db *sql.DB, migrationsPath string) error {
 		return nil
 	}
 
-	migrations, err := loadMigrations(migrationsPath)
+	migrations, err := loadMigrations(fsys)
 	if err != nil {
 		return err
 	}
@@ -357,80 +651,36 @@ func migrateDown(db *sql.DB, migrationsPath string) error {
 		if migration.Version > currentVersion {
 			continue
 		}
-
-		if migration.DownPath == "" {
-			return fmt.Errorf("missing down migration for version %d", migration.Version)
-		}
-
-		log.Printf("Rolling back migration %d: %s...", migration.Version, migration.Name)
-
-		sqlContent, err := os.ReadFile(migration.DownPath)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file: %v", err)
-		}
-
-		sqlString := string(sqlContent)
-
-		// Check if rollback contains CONCURRENTLY (cannot run in transaction)
-		usesConcurrently := strings.Contains(strings.ToUpper(sqlString), "CONCURRENTLY")
-
-		if usesConcurrently {
-			// Execute without transaction for CONCURRENT operations
-			log.Printf("  (running without transaction due to CONCURRENTLY)")
-
-			if _, err := db.Exec(sqlString); err != nil {
-				return fmt.Errorf("rollback %d failed: %v", migration.Version, err)
-			}
-
-			if _, err := db.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
-				return fmt.Errorf("failed to remove migration record: %v", err)
-			}
-		} else {
-			// Execute rollback in a transaction (normal case)
-			tx, err := db.Begin()
-			if err != nil {
-				return err
-			}
-
-			if _, err := tx.Exec(sqlString); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("rollback %d failed: %v", migration.Version, err)
-			}
-
-			if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to remove migration record: %v", err)
-			}
-
-			if err := tx.Commit(); err != nil {
-				return fmt.Errorf("failed to commit rollback: %v", err)
-			}
+		if err := applyDown(db, fsys, migration); err != nil {
+			return err
 		}
-
-		log.Printf("✓ Rolled back migration %d", migration.Version)
 	}
 
 	log.Println("✓ Rollback completed successfully!")
 	return nil
 }
 
-func migrateSteps(db *sql.DB, migrationsPath string, steps int) error {
+func migrateSteps(db *sql.DB, fsys fs.FS, steps int) error {
 	if steps == 0 {
 		log.Println("✓ No migrations to run")
 		return nil
 	}
 
-	currentVersion, err := getCurrentVersion(db)
+	currentVersion, err := getCleanVersion(db)
 	if err != nil {
 		return err
 	}
 
-	migrations, err := loadMigrations(migrationsPath)
+	migrations, err := loadMigrations(fsys)
 	if err != nil {
 		return err
 	}
 
 	if steps > 0 {
+		if err := verifyChecksums(db, fsys, migrations); err != nil {
+			return err
+		}
+
 		// Step up
 		log.Printf("Stepping up %d migration(s)...", steps)
 		count := 0
@@ -441,38 +691,9 @@ func migrateSteps(db *sql.DB, migrationsPath string, steps int) error {
 			if count >= steps {
 				break
 			}
-
-			if migration.UpPath == "" {
-				return fmt.Errorf("missing up migration for version %d", migration.Version)
-			}
-
-			log.Printf("Applying migration %d: %s...", migration.Version, migration.Name)
-
-			sqlContent, err := os.ReadFile(migration.UpPath)
-			if err != nil {
-				return fmt.Errorf("failed to read migration file: %v", err)
-			}
-
-			tx, err := db.Begin()
-			if err != nil {
+			if err := applyUp(db, fsys, migration); err != nil {
 				return err
 			}
-
-			if _, err := tx.Exec(string(sqlContent)); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("migration %d failed: %v", migration.Version, err)
-			}
-
-			if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to record migration: %v", err)
-			}
-
-			if err := tx.Commit(); err != nil {
-				return fmt.Errorf("failed to commit migration: %v", err)
-			}
-
-			log.Printf("✓ Applied migration %d", migration.Version)
 			count++
 		}
 		log.Printf("✓ Stepped up %d migration(s)", count)
@@ -489,65 +710,223 @@ func migrateSteps(db *sql.DB, migrationsPath string, steps int) error {
 			if count >= steps {
 				break
 			}
-
-			if migration.DownPath == "" {
-				return fmt.Errorf("missing down migration for version %d", migration.Version)
+			if err := applyDown(db, fsys, migration); err != nil {
+				return err
 			}
+			count++
+		}
+		log.Printf("✓ Stepped down %d migration(s)", count)
+	}
 
-			log.Printf("Rolling back migration %d: %s...", migration.Version, migration.Name)
+	return nil
+}
 
-			sqlContent, err := os.ReadFile(migration.DownPath)
-			if err != nil {
-				return fmt.Errorf("failed to read migration file: %v", err)
-			}
+// applyUp runs a migration's up file and records its version, with the
+// file's checksum for verifyChecksums.
+//
+// The version is recorded as dirty before the SQL runs and marked clean once
+// it succeeds. A migration that runs in a transaction rolls back entirely on
+// failure, so its dirty record is removed again. A CONCURRENTLY migration
+// can't run in a transaction and may fail partway, so its version is left
+// dirty and later commands refuse to run until it is forced.
+func applyUp(db *sql.DB, fsys fs.FS, migration Migration) error {
+	if migration.UpPath == "" {
+		return fmt.Errorf("missing up migration for version %d", migration.Version)
+	}
 
-			tx, err := db.Begin()
-			if err != nil {
-				return err
-			}
+	log.Printf("Applying migration %d: %s...", migration.Version, migration.Name)
 
-			if _, err := tx.Exec(string(sqlContent)); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("rollback %d failed: %v", migration.Version, err)
-			}
+	sqlContent, err := fs.ReadFile(fsys, migration.UpPath)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %v", err)
+	}
+
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, dirty, checksum) VALUES ($1, TRUE, $2)",
+		migration.Version, checksum(sqlContent)); err != nil {
+		return fmt.Errorf("failed to record migration: %v", err)
+	}
 
-			if _, err := tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
-				tx.Rollback()
-				return fmt.Errorf("failed to remove migration record: %v", err)
+	transactional, err := execMigration(db, string(sqlContent))
+	if err != nil {
+		if transactional {
+			if _, cleanupErr := db.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version); cleanupErr != nil {
+				log.Printf("  failed to clear dirty record for version %d: %v", migration.Version, cleanupErr)
 			}
+		} else {
+			log.Printf("  version %d is now marked dirty", migration.Version)
+		}
+		return fmt.Errorf("migration %d failed: %v", migration.Version, err)
+	}
+
+	if _, err := db.Exec("UPDATE schema_migrations SET dirty = FALSE WHERE version = $1", migration.Version); err != nil {
+		return fmt.Errorf("failed to record migration: %v", err)
+	}
+
+	log.Printf("✓ Applied migration %d", migration.Version)
+	return nil
+}
 
-			if err := tx.Commit(); err != nil {
-				return fmt.Errorf("failed to commit rollback: %v", err)
+// applyDown runs a migration's down file and removes its version record,
+// marking the version dirty while the SQL runs, as applyUp does.
+func applyDown(db *sql.DB, fsys fs.FS, migration Migration) error {
+	if migration.DownPath == "" {
+		return fmt.Errorf("missing down migration for version %d", migration.Version)
+	}
+
+	log.Printf("Rolling back migration %d: %s...", migration.Version, migration.Name)
+
+	sqlContent, err := fs.ReadFile(fsys, migration.DownPath)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %v", err)
+	}
+
+	if _, err := db.Exec("UPDATE schema_migrations SET dirty = TRUE WHERE version = $1", migration.Version); err != nil {
+		return fmt.Errorf("failed to mark migration dirty: %v", err)
+	}
+
+	transactional, err := execMigration(db, string(sqlContent))
+	if err != nil {
+		if transactional {
+			if _, cleanupErr := db.Exec("UPDATE schema_migrations SET dirty = FALSE WHERE version = $1", migration.Version); cleanupErr != nil {
+				log.Printf("  failed to clear dirty flag for version %d: %v", migration.Version, cleanupErr)
 			}
+		} else {
+			log.Printf("  version %d is now marked dirty", migration.Version)
+		}
+		return fmt.Errorf("rollback %d failed: %v", migration.Version, err)
+	}
 
-			log.Printf("✓ Rolled back migration %d", migration.Version)
-			count++
+	if _, err := db.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+		return fmt.Errorf("failed to remove migration record: %v", err)
+	}
+
+	log.Printf("✓ Rolled back migration %d", migration.Version)
+	return nil
+}
+
+// checksum returns the hex SHA-256 of a migration file's contents.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyChecksums checks that the up file of every applied migration still
+// has the checksum recorded when it was applied, and returns an error listing
+// every version whose file was edited since. Applied versions recorded before
+// checksums were tracked (or by force) get their current file's checksum, so
+// later edits are caught.
+func verifyChecksums(db *sql.DB, fsys fs.FS, migrations []Migration) error {
+	byVersion := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+
+	rows, err := db.Query("SELECT version, checksum FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return err
+	}
+	type applied struct {
+		version  int
+		checksum sql.NullString
+	}
+	var recorded []applied
+	for rows.Next() {
+		var a applied
+		if err := rows.Scan(&a.version, &a.checksum); err != nil {
+			rows.Close()
+			return err
+		}
+		recorded = append(recorded, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var changed []string
+	backfilled := 0
+	for _, a := range recorded {
+		m, ok := byVersion[a.version]
+		if !ok || m.UpPath == "" {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, m.UpPath)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file: %v", err)
+		}
+		current := checksum(content)
+
+		if !a.checksum.Valid {
+			if _, err := db.Exec("UPDATE schema_migrations SET checksum = $1 WHERE version = $2", current, a.version); err != nil {
+				return fmt.Errorf("failed to record checksum for version %d: %v", a.version, err)
+			}
+			backfilled++
+			continue
+		}
+		if a.checksum.String != current {
+			changed = append(changed, fmt.Sprintf("%d (%s)", a.version, m.UpPath))
 		}
-		log.Printf("✓ Stepped down %d migration(s)", count)
 	}
 
+	if backfilled > 0 {
+		log.Printf("Recorded checksums for %d previously applied migration(s)", backfilled)
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("applied migrations were edited after they ran: version %s. "+
+			"Restore the original files and add a new migration for the change", strings.Join(changed, ", version "))
+	}
 	return nil
 }
 
+// execMigration runs a migration's SQL, in a transaction unless it uses
+// CONCURRENTLY (which can't run in one). It reports whether it used a
+// transaction, in which case a failure left no changes behind.
+func execMigration(db *sql.DB, sqlString string) (transactional bool, err error) {
+	if strings.Contains(strings.ToUpper(sqlString), "CONCURRENTLY") {
+		log.Printf("  (running without transaction due to CONCURRENTLY)")
+		_, err := db.Exec(sqlString)
+		return false, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return true, err
+	}
+	if _, err := tx.Exec(sqlString); err != nil {
+		tx.Rollback()
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
 func printHelp() {
 	fmt.Println("Woulder Database Migration Tool")
 	fmt.Println()
 	fmt.Println("Usage:")
-	fmt.Println("  go run cmd/migrate/main.go [command]")
+	fmt.Println("  go run cmd/migrate/main.go [-dir path] [command]")
+	fmt.Println()
+	fmt.Println("Migrations are built into the binary. -dir reads them from a directory")
+	fmt.Println("instead, e.g. internal/database/migrations after adding a file.")
+	fmt.Println("up, down, step and force wait up to -lock-timeout (default 1m) for any")
+	fmt.Println("other migration run to finish.")
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  up               Apply all pending migrations (default)")
 	fmt.Println("  down             Rollback all migrations")
 	fmt.Println("  version          Show current migration version")
+	fmt.Println("  status           List every migration with its state and when it was applied")
 	fmt.Println("  step <n>         Apply next n migrations (or rollback if negative)")
-	fmt.Println("  force <version>  Force database to specific version (use with caution)")
+	fmt.Println("  force <version>  Force database to specific version and clear the dirty flag (use with caution)")
+	fmt.Println("  create <name>    Create empty up/down files for the next migration version")
 	fmt.Println("  help             Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run cmd/migrate/main.go up")
 	fmt.Println("  go run cmd/migrate/main.go down")
 	fmt.Println("  go run cmd/migrate/main.go version")
+	fmt.Println("  go run cmd/migrate/main.go status")
 	fmt.Println("  go run cmd/migrate/main.go step 1")
 	fmt.Println("  go run cmd/migrate/main.go step -1")
 	fmt.Println("  go run cmd/migrate/main.go force 2")
+	fmt.Println("  go run cmd/migrate/main.go create add_route_photos")
 }
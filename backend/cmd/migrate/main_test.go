@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexscott64/woulder/backend/internal/database/migrations"
+)
+
+func writeMigrationFiles(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestLoadMigrations(t *testing.T) {
+	dir := writeMigrationFiles(t,
+		"000002_add_routes.up.sql",
+		"000002_add_routes.down.sql",
+		"000001_initial_schema.up.sql",
+		"000001_initial_schema.down.sql",
+		"README.md",
+		"backfill_route_counts.sql",
+	)
+
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("loadMigrations() returned %d migrations, want 2", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "initial_schema" {
+		t.Errorf("first migration = %+v, want version 1 initial_schema", migrations[0])
+	}
+	if migrations[1].UpPath == "" || migrations[1].DownPath == "" {
+		t.Errorf("second migration = %+v, want both up and down paths", migrations[1])
+	}
+}
+
+func TestLoadMigrations_DuplicateVersion(t *testing.T) {
+	dir := writeMigrationFiles(t,
+		"000001_initial_schema.up.sql",
+		"000001_initial_schema.down.sql",
+		"000002_add_routes.up.sql",
+		"000002_add_routes.down.sql",
+		"000002_add_ticks.up.sql",
+		"000002_add_ticks.down.sql",
+	)
+
+	_, err := loadMigrations(os.DirFS(dir))
+	if err == nil {
+		t.Fatal("loadMigrations() error = nil, want duplicate version error")
+	}
+	for _, name := range []string{"000002_add_routes.up.sql", "000002_add_ticks.up.sql", "000002_add_ticks.down.sql"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not mention %s", err, name)
+		}
+	}
+	if strings.Contains(err.Error(), "initial_schema") {
+		t.Errorf("error %q should not mention non-conflicting migrations", err)
+	}
+}
+
+func TestLoadMigrations_RepoMigrationsHaveUniqueVersions(t *testing.T) {
+	if _, err := loadMigrations(migrations.FS); err != nil {
+		t.Fatalf("loadMigrations() on repo migrations: %v", err)
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := writeMigrationFiles(t,
+		"000001_initial_schema.up.sql",
+		"000001_initial_schema.down.sql",
+		"000009_add_routes.up.sql",
+		"000009_add_routes.down.sql",
+	)
+
+	upPath, downPath, err := createMigration(dir, "add_route_photos")
+	if err != nil {
+		t.Fatalf("createMigration() error = %v", err)
+	}
+	if want := filepath.Join(dir, "000010_add_route_photos.up.sql"); upPath != want {
+		t.Errorf("up path = %s, want %s", upPath, want)
+	}
+	if want := filepath.Join(dir, "000010_add_route_photos.down.sql"); downPath != want {
+		t.Errorf("down path = %s, want %s", downPath, want)
+	}
+	for _, path := range []string{upPath, downPath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", path, err)
+		}
+		if info.Size() != 0 {
+			t.Errorf("%s is %d bytes, want empty", path, info.Size())
+		}
+	}
+
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("loadMigrations() after create: %v", err)
+	}
+	if last := migrations[len(migrations)-1]; last.Version != 10 || last.Name != "add_route_photos" {
+		t.Errorf("last migration = %+v, want version 10 add_route_photos", last)
+	}
+}
+
+func TestCreateMigration_EmptyDirectory(t *testing.T) {
+	upPath, _, err := createMigration(t.TempDir(), "initial_schema")
+	if err != nil {
+		t.Fatalf("createMigration() error = %v", err)
+	}
+	if filepath.Base(upPath) != "000001_initial_schema.up.sql" {
+		t.Errorf("up path = %s, want 000001_initial_schema.up.sql", upPath)
+	}
+}
+
+func TestCreateMigration_InvalidName(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"", "add routes", "add\troutes", "../escape", `sub\dir`} {
+		if _, _, err := createMigration(dir, name); err == nil {
+			t.Errorf("createMigration(%q) error = nil, want an error", name)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("invalid names created %d files, want none", len(entries))
+	}
+}
+
+func TestGetCleanVersion_RefusesDirty(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, dirty FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}).AddRow(52, true))
+
+	_, err = getCleanVersion(db)
+	if err == nil || !strings.Contains(err.Error(), "dirty at version 52") || !strings.Contains(err.Error(), "force") {
+		t.Errorf("getCleanVersion() error = %v, want dirty error pointing at force", err)
+	}
+}
+
+func TestGetCurrentVersion_NoMigrations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, dirty FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "dirty"}))
+
+	version, dirty, err := getCurrentVersion(db)
+	if err != nil || version != 0 || dirty {
+		t.Errorf("getCurrentVersion() = %d, %v, %v, want 0, false, nil", version, dirty, err)
+	}
+}
+
+func TestApplyUp_ConcurrentFailureLeavesDirty(t *testing.T) {
+	fsys := fstest.MapFS{"000053_add_index.up.sql": {Data: []byte("CREATE INDEX CONCURRENTLY idx ON t(c);")}}
+	upPath := "000053_add_index.up.sql"
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, dirty, checksum) VALUES ($1, TRUE, $2)")).
+		WithArgs(53, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("CREATE INDEX CONCURRENTLY").WillReturnError(errors.New("deadlock detected"))
+
+	err = applyUp(db, fsys, Migration{Version: 53, Name: "add_index", UpPath: upPath})
+	if err == nil {
+		t.Fatal("applyUp() error = nil, want failure")
+	}
+	// No cleanup: the version must stay dirty.
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApplyUp_TransactionalFailureClearsDirty(t *testing.T) {
+	fsys := fstest.MapFS{"000053_add_column.up.sql": {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")}}
+	upPath := "000053_add_column.up.sql"
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, dirty, checksum) VALUES ($1, TRUE, $2)")).
+		WithArgs(53, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE t").WillReturnError(errors.New("column already exists"))
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(53).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := applyUp(db, fsys, Migration{Version: 53, Name: "add_column", UpPath: upPath}); err == nil {
+		t.Fatal("applyUp() error = nil, want failure")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestApplyUp_Success(t *testing.T) {
+	fsys := fstest.MapFS{"000053_add_column.up.sql": {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")}}
+	upPath := "000053_add_column.up.sql"
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO schema_migrations (version, dirty, checksum) VALUES ($1, TRUE, $2)")).
+		WithArgs(53, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE t").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE schema_migrations SET dirty = FALSE WHERE version = $1")).
+		WithArgs(53).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := applyUp(db, fsys, Migration{Version: 53, Name: "add_column", UpPath: upPath}); err != nil {
+		t.Fatalf("applyUp() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	dir := writeMigrationFiles(t,
+		"000001_initial_schema.up.sql",
+		"000002_add_routes.up.sql",
+		"000003_add_ticks.up.sql",
+		"000004_add_comments.up.sql",
+	)
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+	original := checksum([]byte("SELECT 1;"))
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	// Version 1 matches, 2 and 3 were edited, 4 predates checksums.
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, checksum FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum"}).
+			AddRow(1, original).
+			AddRow(2, checksum([]byte("SELECT 2;"))).
+			AddRow(3, checksum([]byte("SELECT 3;"))).
+			AddRow(4, nil))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE schema_migrations SET checksum = $1 WHERE version = $2")).
+		WithArgs(original, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = verifyChecksums(db, os.DirFS(dir), migrations)
+	if err == nil {
+		t.Fatal("verifyChecksums() error = nil, want edited migrations reported")
+	}
+	for _, want := range []string{"version 2 (000002_add_routes.up.sql)", "version 3 (000003_add_ticks.up.sql)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "initial_schema") || strings.Contains(err.Error(), "add_comments") {
+		t.Errorf("error %q mentions an unchanged migration", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyChecksums_Unchanged(t *testing.T) {
+	dir := writeMigrationFiles(t, "000001_initial_schema.up.sql")
+	migrations, err := loadMigrations(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("loadMigrations() error = %v", err)
+	}
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT version, checksum FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "checksum"}).AddRow(1, checksum([]byte("SELECT 1;"))))
+
+	if err := verifyChecksums(db, os.DirFS(dir), migrations); err != nil {
+		t.Errorf("verifyChecksums() error = %v, want nil", err)
+	}
+}
+
+func TestMigrationStatus(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "initial_schema"},
+		{Version: 2, Name: "add_routes"},
+		{Version: 3, Name: "add_ticks"},
+		{Version: 4, Name: "add_comments"},
+	}
+	appliedAt := sql.NullTime{Time: time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local), Valid: true}
+	// Forced to 2 (no row for 1), 3 failed partway, 7 has no file.
+	applied := map[int]appliedMigration{
+		2: {AppliedAt: appliedAt},
+		3: {Dirty: true, AppliedAt: appliedAt},
+		7: {AppliedAt: appliedAt},
+	}
+
+	rows := migrationStatus(migrations, applied)
+
+	want := []statusRow{
+		{1, "initial_schema", "applied", "-"},
+		{2, "add_routes", "applied", "2026-03-01 12:00:00"},
+		{3, "add_ticks", "dirty", "2026-03-01 12:00:00"},
+		{4, "add_comments", "applied", "-"},
+		{7, "-", "missing file", "2026-03-01 12:00:00"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("migrationStatus() returned %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestPrintStatus(t *testing.T) {
+	var out bytes.Buffer
+	printStatus(&out, []statusRow{
+		{1, "initial_schema", "applied", "2026-03-01 12:00:00"},
+		{2, "add_routes", "pending", "-"},
+		{3, "add_ticks", "pending", "-"},
+	})
+
+	got := out.String()
+	for _, want := range []string{"VERSION", "000001   initial_schema  applied", "000002   add_routes      pending", "2 pending migration(s)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("status output missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestAcquireMigrationLock_WaitsForOtherRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	lockQuery := regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")
+	mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	mock.ExpectQuery(lockQuery).WithArgs(migrationLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).WithArgs(migrationLockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	release, err := acquireMigrationLock(context.Background(), db, time.Second, time.Millisecond)
+	if err != nil {
+		t.Fatalf("acquireMigrationLock() error = %v", err)
+	}
+	release()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAcquireMigrationLock_Timeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT pg_try_advisory_lock($1)")).WithArgs(migrationLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	_, err = acquireMigrationLock(context.Background(), db, 0, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "another migration is in progress") {
+		t.Errorf("acquireMigrationLock() error = %v, want another migration in progress", err)
+	}
+}
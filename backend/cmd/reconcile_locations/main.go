@@ -0,0 +1,115 @@
+// Command reconcile_locations fixes Mountain Project routes whose location_id
+// no longer matches their parent area's location_id.
+//
+// Routes pick up location_id when they are synced, so a route can drift from
+// its area when an area is re-assigned to a different location or a route is
+// moved between areas on Mountain Project. This tool copies the area's
+// location_id onto every mismatched route, batch by batch, each batch in its
+// own transaction. Areas without a location are skipped so a route is never
+// detached from a location.
+//
+// Usage:
+//
+//	go run ./cmd/reconcile_locations -dry-run
+//	go run ./cmd/reconcile_locations -batch-size 500
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Report mismatched routes without updating them")
+	batchSize := flag.Int("batch-size", 500, "Number of routes to update per transaction")
+	sampleSize := flag.Int("sample", 20, "Number of mismatched routes to print in dry-run mode")
+	flag.Parse()
+
+	if *batchSize <= 0 {
+		log.Fatalf("-batch-size must be positive, got %d", *batchSize)
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Route Location Reconciliation Tool ===")
+	if *dryRun {
+		log.Println("DRY RUN MODE: no rows will be modified")
+	}
+	log.Println()
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	routes := db.MountainProject().Routes()
+
+	total, err := routes.CountLocationMismatches(ctx)
+	if err != nil {
+		log.Fatalf("Failed to count mismatched routes: %v", err)
+	}
+	log.Printf("Found %d route(s) whose location_id differs from their area's", total)
+	if total == 0 {
+		return
+	}
+
+	if *dryRun {
+		sample, err := routes.GetLocationMismatches(ctx, *sampleSize)
+		if err != nil {
+			log.Fatalf("Failed to load mismatched routes: %v", err)
+		}
+		log.Printf("Sample (first %d by route ID):", len(sample))
+		for _, m := range sample {
+			log.Printf("  route %d %q (area %d): location_id %s -> %d",
+				m.MPRouteID, m.RouteName, m.MPAreaID, formatLocationID(m.RouteLocationID), m.AreaLocationID)
+		}
+		return
+	}
+
+	start := time.Now()
+	var updated int64
+	for batch := 1; ; batch++ {
+		var n int64
+		err := database.WithTransaction(ctx, db.Conn(), func(tx *sql.Tx) error {
+			var err error
+			n, err = mountainproject.NewPostgresRepository(tx).Routes().ReconcileLocationMismatches(ctx, *batchSize)
+			return err
+		})
+		if err != nil {
+			log.Fatalf("Batch %d failed after %d route(s) updated: %v", batch, updated, err)
+		}
+		if n == 0 {
+			break
+		}
+		updated += n
+		log.Printf("Batch %d: updated %d route(s) (%d/%d)", batch, n, updated, total)
+		if n < int64(*batchSize) {
+			break
+		}
+	}
+
+	log.Println()
+	log.Printf("Done: updated %d route(s) in %s", updated, time.Since(start).Round(time.Millisecond))
+}
+
+func formatLocationID(id *int) string {
+	if id == nil {
+		return "NULL"
+	}
+	return strconv.Itoa(*id)
+}
@@ -0,0 +1,61 @@
+// Command seed loads the fixed development dataset in internal/seed into the
+// database, in a single transaction: a few locations with their Mountain
+// Project areas, routes, ticks and comments, and a day of weather for each.
+// It makes no network calls, and everything is upserted, so it is safe to run
+// again.
+//
+// It refuses to run with GIN_MODE=release, the production default.
+//
+// Usage:
+//
+//	GIN_MODE=debug go run ./cmd/seed
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/seed"
+)
+
+func main() {
+	log.Println("=== Development Seed ===")
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := seed.CheckEnvironment(cfg.Server.GinMode); err != nil {
+		log.Fatal(err)
+	}
+
+	fixtures, err := seed.LoadFixtures()
+	if err != nil {
+		log.Fatalf("Invalid seed fixtures: %v", err)
+	}
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	var res *seed.Result
+	err = database.WithTransaction(ctx, db.Conn(), func(tx *sql.Tx) error {
+		var err error
+		res, err = seed.Run(ctx, seed.NewPostgresRepos(tx), fixtures)
+		return err
+	})
+	if err != nil {
+		log.Fatalf("Seed failed, no changes were made: %v", err)
+	}
+
+	log.Printf("Seeded %d location(s) (%d new), %d area(s), %d route(s), %d tick(s), %d comment(s), %d weather hour(s)",
+		res.Locations, res.LocationsCreated, res.MPAreas, res.Routes, res.Ticks, res.Comments, res.WeatherHours)
+}
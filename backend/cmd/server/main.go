@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"os"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/alexscott64/woulder/backend/internal/service"
 	"github.com/alexscott64/woulder/backend/internal/storage"
 	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
 )
 
 func main() {
@@ -32,7 +34,7 @@ func main() {
 	}
 
 	// Initialize database
-	db, err := database.New()
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -40,6 +42,9 @@ func main() {
 
 	// Initialize external API clients
 	weatherClient := weather.NewWeatherService(cfg.Weather.OpenWeatherMapAPIKey)
+	if cfg.Weather.AlertsEnabled {
+		weatherClient.EnableAlerts(cfg.Weather.AlertsUserAgent)
+	}
 	riverClient := rivers.NewUSGSClient()
 	mpClient := mountainproject.NewClient()
 
@@ -48,7 +53,10 @@ func main() {
 
 	// Initialize services with dependency injection
 	locationService := service.NewLocationService(db.Locations(), db.Areas())
-	climbTrackingService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mpClient, jobMonitor)
+	mpBudget := mountainproject.NewRequestBudget(cfg.Sync.MountainProjectRequestsPerSecond)
+	climbTrackingService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mpClient, jobMonitor, mpBudget)
+	climbTrackingService.SetTickEarlyStop(cfg.Sync.MountainProjectTicksNewestFirst)
+	climbTrackingService.SetStateSyncWorkers(cfg.Sync.StateSyncWorkers)
 
 	// Recover any interrupted jobs from previous run (before starting new background jobs)
 	log.Println("Checking for interrupted jobs from previous run...")
@@ -56,8 +64,11 @@ func main() {
 
 	weatherServiceLayer := service.NewWeatherService(db.Weather(), db.Locations(), db.Rocks(), weatherClient, climbTrackingService)
 	weatherServiceLayer.SetOfflineMode(cfg.Weather.OfflineMode)
+	weatherServiceLayer.SetRainThreshold(cfg.Weather.RainThresholdInches)
 	riverServiceLayer := service.NewRiverService(db.Rivers(), riverClient)
 	boulderDryingService := service.NewBoulderDryingService(db.Boulders(), db.Weather(), db.Locations(), db.Rocks(), db.MountainProject(), weatherClient)
+	treeCoverClient := boulder_drying.NewTreeCoverClient()
+	boulderDryingService.SetTreeCoverRefresher(treeCoverClient, jobMonitor)
 	heatMapService := service.NewHeatMapService(db.HeatMap())
 	analyticsService := service.NewAnalyticsService(db.Analytics())
 	authService := service.NewAuthService(db.Auth(), cfg.Auth)
@@ -83,7 +94,7 @@ func main() {
 	moneyService := service.NewMoneyServiceWithOptions(db.Money(), uploadStorage, cfg.Upload.MaxBytes, service.MoneyServiceOptions{StorageBackend: cfg.Upload.StorageDriver, StorageBucket: cfg.Upload.R2Bucket, StorageRegion: cfg.Upload.R2Region, KeyPrefix: cfg.Upload.AssetKeyPrefix, SignedURLTTL: cfg.Upload.R2SignedURLTTL})
 
 	// Initialize API handler with services
-	handler := api.NewHandler(locationService, weatherServiceLayer, riverServiceLayer, climbTrackingService, boulderDryingService, heatMapService, analyticsService, authService, moneyService, db.Kaya(), jobMonitor)
+	handler := api.NewHandler(locationService, weatherServiceLayer, riverServiceLayer, climbTrackingService, boulderDryingService, heatMapService, analyticsService, authService, moneyService, db.Kaya(), jobMonitor, db)
 
 	// Start background syncs only if not disabled (e.g., in development)
 	if cfg.Server.DisableBackgroundSyncs {
@@ -114,13 +125,32 @@ func main() {
 
 		// Start background route sync (every 24 hours)
 		handler.StartBackgroundRouteSync(24 * time.Hour)
+
+		// Tree coverage refresh runs weekly, re-fetching coverage for profiles
+		// older than TREE_COVER_REFRESH_MAX_AGE_DAYS. Skipped without Earth
+		// Engine credentials since there is nothing better than the stored value.
+		if treeCoverClient.IsEnabled() {
+			handler.StartTreeCoverRefresh(7*24*time.Hour, service.TreeCoverRefreshOptions{
+				MaxAge:       cfg.TreeCover.RefreshMaxAge,
+				BatchSize:    cfg.TreeCover.RefreshBatchSize,
+				RequestDelay: cfg.TreeCover.RefreshRequestDelay,
+			})
+		}
+
+		// Job history prune runs daily when JOB_HISTORY_RETENTION_DAYS is set
+		if cfg.Sync.JobHistoryRetention > 0 {
+			handler.StartJobHistoryPrune(24*time.Hour, cfg.Sync.JobHistoryRetention)
+		}
 	}
 
 	// Set Gin mode
 	gin.SetMode(cfg.Server.GinMode)
 
-	// Create Gin router
-	router := gin.Default()
+	// Create Gin router. Request logging replaces gin's default logger with
+	// structured JSON lines that carry the request ID.
+	router := gin.New()
+	requestLogger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	router.Use(gin.Recovery(), middleware.RequestID(), middleware.Logger(requestLogger))
 
 	// Configure CORS
 	router.Use(cors.New(cors.Config{
@@ -137,21 +167,35 @@ func main() {
 	// receive uncompressed responses. Level 6 (DefaultCompression).
 	router.Use(gzip.Gzip(gzip.DefaultCompression))
 
+	// Idempotency-Key support for manual refresh triggers, so a double-click
+	// doesn't start two overlapping runs
+	idempotencyStore := middleware.NewIdempotencyStore(10 * time.Minute)
+
 	// API routes
 	apiGroup := router.Group("/api")
 	{
 		apiGroup.GET("/health", handler.HealthCheck)
+		apiGroup.GET("/version", handler.GetVersion)
 		apiGroup.GET("/locations", handler.GetAllLocations)
+		apiGroup.GET("/locations/nearby", handler.GetNearbyLocations)
+		apiGroup.GET("/locations/now", handler.GetLocationsNow)
+		apiGroup.GET("/locations/ranked", handler.GetRankedLocations)
+		apiGroup.GET("/locations/:id", handler.GetLocation)
+		apiGroup.GET("/locations/:id/now", handler.GetLocationNow)
+		apiGroup.GET("/locations/:id/suntimes", handler.GetLocationSunTimes)
+		apiGroup.GET("/locations/:id/conditions-history", handler.GetConditionsHistory)
+		apiGroup.GET("/locations/:id/areas/tree", handler.GetAreaTree)
+		apiGroup.GET("/locations/:id/grade-distribution", handler.GetLocationGradeDistribution)
 		apiGroup.GET("/areas", handler.GetAllAreas)
 		apiGroup.GET("/areas/:id/locations", handler.GetLocationsByArea)
 		apiGroup.GET("/weather/all", handler.GetAllWeather)
 		apiGroup.GET("/weather/:id", handler.GetWeatherForLocation)
 		apiGroup.GET("/weather/coordinates", handler.GetWeatherByCoordinates)
-		apiGroup.POST("/weather/refresh", handler.RefreshWeather)
-		apiGroup.POST("/routes/refresh", handler.RefreshRoutes)
+		apiGroup.POST("/weather/refresh", middleware.Idempotency(idempotencyStore), handler.RefreshWeather)
+		apiGroup.POST("/routes/refresh", middleware.Idempotency(idempotencyStore), handler.RefreshRoutes)
 		apiGroup.GET("/rivers/location/:id", handler.GetRiverDataForLocation)
 		apiGroup.GET("/rivers/:id", handler.GetRiverDataByID)
-		apiGroup.POST("/climbs/refresh", handler.RefreshClimbData)
+		apiGroup.POST("/climbs/refresh", middleware.Idempotency(idempotencyStore), handler.RefreshClimbData)
 		apiGroup.GET("/climbs/location/:id", handler.GetLastClimbedForLocation)
 		apiGroup.GET("/climbs/location/:id/areas", handler.GetAreasOrderedByActivity)
 		apiGroup.GET("/climbs/location/:id/areas/:area_id/subareas", handler.GetSubareasOrderedByActivity)
@@ -160,10 +204,19 @@ func main() {
 		apiGroup.GET("/climbs/location/:id/areas/:area_id/drying-stats", handler.GetAreaDryingStats)
 		apiGroup.GET("/climbs/location/:id/batch-area-drying-stats", handler.GetBatchAreaDryingStats)
 		apiGroup.GET("/climbs/routes/:route_id/ticks", handler.GetRecentTicksForRoute)
+		apiGroup.POST("/climbs/user-ticks/sync", middleware.Auth(authService), middleware.Idempotency(idempotencyStore), handler.SyncUserTicks)
+		apiGroup.GET("/climbs/users/:username/ticked-routes", handler.GetUserTickedRoutes)
 		apiGroup.GET("/climbs/routes/:route_id/drying-status", handler.GetBoulderDryingStatus)
 		apiGroup.GET("/climbs/routes/batch-drying-status", handler.GetBatchBoulderDryingStatus)
 		apiGroup.GET("/climbs/location/:id/search-all", handler.SearchInLocation)
 		apiGroup.GET("/climbs/location/:id/search", handler.SearchRoutesInLocation)
+		apiGroup.GET("/trending/routes", handler.GetTrendingRoutes)
+		apiGroup.GET("/routes/new", handler.GetNewRoutes)
+		apiGroup.GET("/routes/featured", handler.GetFeaturedRoute)
+		apiGroup.GET("/routes/:id/similar", handler.GetSimilarRoutes)
+		apiGroup.GET("/routes/:id/activity-series", handler.GetRouteActivitySeries)
+		apiGroup.GET("/areas/:id/activity-series", handler.GetAreaActivitySeries)
+		apiGroup.GET("/areas/:id/grade-distribution", handler.GetAreaGradeDistribution)
 
 		// Heat map routes
 		apiGroup.GET("/heat-map/activity", handler.GetHeatMapActivity)
@@ -174,12 +227,18 @@ func main() {
 
 		// Kaya routes
 		apiGroup.GET("/kaya/location/:id/ascents", handler.GetKayaAscentsForLocation)
+		apiGroup.GET("/kaya/posts/recent", handler.GetRecentKayaPosts)
+		apiGroup.GET("/routes/:id/kaya-matches", handler.GetKayaMatchesForRoute)
 
 		// Job monitoring routes
 		apiGroup.GET("/monitoring/jobs/active", handler.GetActiveJobs)
 		apiGroup.GET("/monitoring/jobs/history", handler.GetJobHistory)
 		apiGroup.GET("/monitoring/jobs/summary", handler.GetJobsSummary)
 		apiGroup.GET("/monitoring/jobs/:job_id", handler.GetJobStatus)
+		apiGroup.POST("/monitoring/jobs/prune", middleware.Auth(authService), middleware.RequireAdmin(), handler.PruneJobs)
+		apiGroup.POST("/monitoring/jobs/:job_id/cancel", middleware.Auth(authService), middleware.RequireAdmin(), handler.CancelJob)
+		apiGroup.GET("/monitoring/trends", handler.GetSyncTrends)
+		apiGroup.GET("/sync/status", handler.GetSyncStatus)
 		// General app auth routes
 		authGroup := apiGroup.Group("/auth")
 		{
@@ -189,6 +248,13 @@ func main() {
 			authGroup.GET("/me", middleware.Auth(authService), handler.AuthMe)
 		}
 
+		// Admin maintenance routes (admin auth required)
+		adminGroup := apiGroup.Group("/admin")
+		adminGroup.Use(middleware.Auth(authService), middleware.RequireAdmin())
+		{
+			adminGroup.POST("/priorities/recalculate", handler.RecalculatePriorities)
+		}
+
 		// Money Creek toolkit routes (auth required)
 		moneyGroup := apiGroup.Group("/money")
 		moneyGroup.Use(middleware.Auth(authService))
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/progress"
+	"github.com/alexscott64/woulder/backend/internal/service"
+)
+
+func main() {
+	// Flags
+	//   --file: JSON list of {location_id, mp_area_id} roots to crawl with
+	//     SyncAreaRecursive, for ad-hoc syncs of areas not in LocationRoots().
+	file := flag.String("file", "", "JSON file listing {location_id, mp_area_id} area roots to sync (required)")
+	noProgress := flag.Bool("no-progress", false,
+		"Disable the live progress bar (progress is logged periodically instead)")
+	flag.Parse()
+
+	if *file == "" {
+		log.Fatal("--file is required")
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open areas file: %v", err)
+	}
+	roots, err := readAreaRoots(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s:\n%v", *file, err)
+	}
+
+	log.Printf("Starting Mountain Project sync of %d area roots from %s...", len(roots), *file)
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	// Check every location exists before crawling, so a typo doesn't fail
+	// on the first save hours into the run.
+	checked := make(map[int]bool)
+	for _, root := range roots {
+		if root.LocationID == nil || checked[*root.LocationID] {
+			continue
+		}
+		if _, err := db.Locations().GetByID(ctx, *root.LocationID); err != nil {
+			log.Fatalf("Location %d (for area %d) not found: %v", *root.LocationID, root.MPAreaID, err)
+		}
+		checked[*root.LocationID] = true
+	}
+
+	// Initialize climb tracking service (no job monitor for manual sync)
+	mpClient := mountainproject.NewClient()
+	mpBudget := mountainproject.NewRequestBudget(cfg.Sync.MountainProjectRequestsPerSecond)
+	climbService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mpClient, nil, mpBudget)
+
+	tracker := progress.New("roots", len(roots), !*noProgress && progress.IsTerminal(os.Stdout))
+	log.SetOutput(tracker.LogWriter(os.Stderr))
+
+	syncedCount := 0
+	skippedCount := 0
+	startTime := time.Now()
+
+	err = climbService.SyncAreaRoots(ctx, roots, func(root service.AreaSyncRoot, skipped bool) {
+		if skipped {
+			skippedCount++
+		} else {
+			syncedCount++
+			log.Printf("✓ Synced area %d", root.MPAreaID)
+		}
+		tracker.Increment()
+	})
+
+	tracker.Finish()
+	elapsed := time.Since(startTime)
+
+	log.Printf("\n========================================")
+	log.Printf("Sync Complete!")
+	log.Printf("========================================")
+	log.Printf("Area roots in file: %d", len(roots))
+	log.Printf("Synced: %d", syncedCount)
+	log.Printf("Skipped (already covered by an earlier root): %d", skippedCount)
+	log.Printf("Time elapsed: %s", elapsed.Round(time.Second))
+	log.Printf("========================================")
+
+	if err != nil {
+		log.Printf("Sync stopped early: %v", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/alexscott64/woulder/backend/internal/service"
+)
+
+// readAreaRoots parses a JSON array of {location_id, mp_area_id} entries.
+// location_id may be omitted or null to sync areas without a location. All
+// invalid entries are reported together, numbered from 1.
+func readAreaRoots(r io.Reader) ([]service.AreaSyncRoot, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var roots []service.AreaSyncRoot
+	if err := dec.Decode(&roots); err != nil {
+		return nil, fmt.Errorf("invalid areas file: %w", err)
+	}
+	if len(roots) == 0 {
+		return nil, errors.New("areas file lists no areas")
+	}
+
+	var errs []error
+	for i, root := range roots {
+		if root.MPAreaID <= 0 {
+			errs = append(errs, fmt.Errorf("entry %d: mp_area_id must be a positive MP area ID", i+1))
+		}
+		if root.LocationID != nil && *root.LocationID <= 0 {
+			errs = append(errs, fmt.Errorf("entry %d: location_id %d must be positive (omit it for no location)", i+1, *root.LocationID))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return roots, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadAreaRoots(t *testing.T) {
+	input := `[
+		{"location_id": 5, "mp_area_id": 105790237},
+		{"mp_area_id": 120379690},
+		{"location_id": null, "mp_area_id": 105805788}
+	]`
+
+	roots, err := readAreaRoots(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readAreaRoots() error = %v", err)
+	}
+	if len(roots) != 3 {
+		t.Fatalf("got %d roots, want 3", len(roots))
+	}
+	if roots[0].LocationID == nil || *roots[0].LocationID != 5 || roots[0].MPAreaID != 105790237 {
+		t.Errorf("roots[0] = %+v, want location 5, area 105790237", roots[0])
+	}
+	if roots[1].LocationID != nil || roots[2].LocationID != nil {
+		t.Errorf("roots without location_id got locations: %+v, %+v", roots[1], roots[2])
+	}
+}
+
+func TestReadAreaRoots_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"not json", `location_id,mp_area_id`, []string{"invalid areas file"}},
+		{"empty", `[]`, []string{"lists no areas"}},
+		{"unknown field", `[{"location": 5, "mp_area_id": 1}]`, []string{`unknown field "location"`}},
+		{
+			name:  "bad entries",
+			input: `[{"location_id": 5, "mp_area_id": 1}, {"location_id": 5}, {"location_id": 0, "mp_area_id": 2}]`,
+			want:  []string{"entry 2: mp_area_id", "entry 3: location_id 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := readAreaRoots(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("readAreaRoots() error = nil, want error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("readAreaRoots() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"os"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/database"
 	"github.com/alexscott64/woulder/backend/internal/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/progress"
 	"github.com/alexscott64/woulder/backend/internal/service"
-	"github.com/joho/godotenv"
+	"github.com/alexscott64/woulder/backend/internal/syncretry"
 )
 
 func main() {
@@ -24,17 +26,34 @@ func main() {
 	//     waiting for the weekly schedule.
 	discoverAreas := flag.Bool("discover-areas", false,
 		"Run only the location_area_discovery job (crawl configured roots to pick up new MP sub-areas) and exit")
+	//   --new-ticks <location id>: skip the seed and run one incremental
+	//     tick sync (SyncNewTicksForLocation) for that location, printing
+	//     its SyncResult.
+	newTicksLocation := flag.Int("new-ticks", 0,
+		"Run only an incremental tick sync for this location ID and exit")
+	//   --no-progress: log periodic progress lines instead of drawing a live
+	//     progress bar, even when stdout is a terminal.
+	noProgress := flag.Bool("no-progress", false,
+		"Disable the live progress bar (progress is logged periodically instead)")
+	//   --retry-attempts, --retry-delay: retry an area that fails with a
+	//     transient error, backing off exponentially.
+	retryOpts := syncretry.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
+	if err := retryOpts.Validate(); err != nil {
+		log.Fatalf("Invalid retry flags: %v", err)
+	}
+
 	log.Println("Starting Mountain Project climb data sync...")
 
-	// Load environment variables from backend directory
-	if err := godotenv.Load(".env"); err != nil {
-		log.Printf("Warning: .env file not found, using system environment variables")
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Initialize database
-	db, err := database.New()
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -44,7 +63,8 @@ func main() {
 	mpClient := mountainproject.NewClient()
 
 	// Initialize climb tracking service (no job monitor for manual sync)
-	climbService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mpClient, nil)
+	mpBudget := mountainproject.NewRequestBudget(cfg.Sync.MountainProjectRequestsPerSecond)
+	climbService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mpClient, nil, mpBudget)
 
 	ctx := context.Background()
 
@@ -60,6 +80,23 @@ func main() {
 		return
 	}
 
+	if *newTicksLocation > 0 {
+		log.Printf("Running incremental tick sync for location %d...", *newTicksLocation)
+		result, err := climbService.SyncNewTicksForLocation(ctx, *newTicksLocation)
+		log.Printf("Routes processed: %d", result.RoutesProcessed)
+		log.Printf("New ticks: %d", result.ItemsAdded)
+		log.Printf("Failed routes: %d", result.Failures)
+		log.Printf("Time elapsed: %s", result.Duration.Round(time.Second))
+		if err != nil {
+			log.Printf("Incremental tick sync returned error: %v", err)
+			os.Exit(1)
+		}
+		if result.Failures > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Default mode: full per-root recursive seed using the shared
 	// LocationRoots() registry (single source of truth — also consumed by
 	// the scheduled SyncLocationAreaDiscovery job).
@@ -69,6 +106,13 @@ func main() {
 	successCount := 0
 	failCount := 0
 
+	areaCount := 0
+	for _, config := range areaConfigs {
+		areaCount += len(config.MPAreaIDs)
+	}
+	tracker := progress.New("areas", areaCount, !*noProgress && progress.IsTerminal(os.Stdout))
+	log.SetOutput(tracker.LogWriter(os.Stderr))
+
 	startTime := time.Now()
 
 	// Process each location's areas
@@ -86,7 +130,10 @@ func main() {
 
 			// Convert int64 to string for API call
 			areaIDStr := fmt.Sprintf("%d", areaID)
-			err := climbService.SyncAreaRecursive(ctx, areaIDStr, &locationID)
+			err := retryOpts.Do(ctx, "area "+areaIDStr, func() error {
+				return climbService.SyncAreaRecursive(ctx, areaIDStr, &locationID)
+			})
+			tracker.Increment()
 			if err != nil {
 				log.Printf("ERROR syncing area %d: %v", areaID, err)
 				failCount++
@@ -98,6 +145,7 @@ func main() {
 		}
 	}
 
+	tracker.Finish()
 	elapsed := time.Since(startTime)
 
 	log.Printf("\n========================================")
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// defaultCheckpointFile is where --all records its progress for --resume,
+// relative to the working directory.
+const defaultCheckpointFile = ".sync_kaya_checkpoint.json"
+
+// syncCheckpoint records how far an --all run got, so an interrupted run can
+// be resumed without re-syncing the destinations it already finished.
+type syncCheckpoint struct {
+	// LastSlug is the last destination, in list order, that finished
+	// (synced or failed).
+	LastSlug string `json:"last_slug"`
+	// Failed lists destinations before LastSlug that failed; --resume
+	// retries them.
+	Failed    []string  `json:"failed,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// record notes that slug finished. A retried destination (an earlier failure
+// being synced again) doesn't move LastSlug, since it comes before it.
+func (c *syncCheckpoint) record(slug string, failed, retried bool) {
+	if !retried {
+		c.LastSlug = slug
+	}
+	c.Failed = slices.DeleteFunc(c.Failed, func(s string) bool { return s == slug })
+	if failed {
+		c.Failed = append(c.Failed, slug)
+	}
+	c.UpdatedAt = time.Now()
+}
+
+// remainingLocations returns the destinations a resumed run still has to
+// sync: the earlier failures, then everything after cp.LastSlug, in list
+// order. ok is false if cp.LastSlug isn't in the list (e.g. the destinations
+// file changed), in which case every destination is returned.
+func remainingLocations(configs []LocationConfig, cp *syncCheckpoint) (remaining []LocationConfig, ok bool) {
+	last := slices.IndexFunc(configs, func(c LocationConfig) bool { return c.Slug == cp.LastSlug })
+	if last < 0 {
+		return configs, false
+	}
+
+	for i, config := range configs {
+		if i > last || slices.Contains(cp.Failed, config.Slug) {
+			remaining = append(remaining, config)
+		}
+	}
+	return remaining, true
+}
+
+// loadCheckpoint reads the checkpoint at path. It returns nil if there is
+// none.
+func loadCheckpoint(path string) (*syncCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp syncCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", path, err)
+	}
+	return &cp, nil
+}
+
+// saveCheckpoint writes cp to path, replacing it atomically so a crash
+// mid-write never leaves a truncated checkpoint.
+func saveCheckpoint(path string, cp *syncCheckpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// clearCheckpoint removes the checkpoint at path, if any.
+func clearCheckpoint(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func slugsOf(configs []LocationConfig) []string {
+	slugs := make([]string, len(configs))
+	for i, c := range configs {
+		slugs[i] = c.Slug
+	}
+	return slugs
+}
+
+func TestRemainingLocations(t *testing.T) {
+	configs := []LocationConfig{
+		{Slug: "Leavenworth-344933"},
+		{Slug: "Bishop-316882"},
+		{Slug: "Red-Rocks-331387"},
+		{Slug: "Squamish-331280"},
+	}
+
+	t.Run("skips up to and including the checkpoint", func(t *testing.T) {
+		remaining, ok := remainingLocations(configs, &syncCheckpoint{LastSlug: "Bishop-316882"})
+		if !ok {
+			t.Fatal("remainingLocations() ok = false, want true")
+		}
+		want := []string{"Red-Rocks-331387", "Squamish-331280"}
+		if got := slugsOf(remaining); !reflect.DeepEqual(got, want) {
+			t.Errorf("remainingLocations() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("retries earlier failures", func(t *testing.T) {
+		remaining, _ := remainingLocations(configs, &syncCheckpoint{
+			LastSlug: "Red-Rocks-331387",
+			Failed:   []string{"Leavenworth-344933"},
+		})
+		want := []string{"Leavenworth-344933", "Squamish-331280"}
+		if got := slugsOf(remaining); !reflect.DeepEqual(got, want) {
+			t.Errorf("remainingLocations() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown checkpoint syncs everything", func(t *testing.T) {
+		remaining, ok := remainingLocations(configs, &syncCheckpoint{LastSlug: "Hueco-Tanks-316857"})
+		if ok {
+			t.Error("remainingLocations() ok = true, want false")
+		}
+		if len(remaining) != len(configs) {
+			t.Errorf("remainingLocations() returned %d destinations, want all %d", len(remaining), len(configs))
+		}
+	})
+}
+
+func TestSyncCheckpointRecord(t *testing.T) {
+	cp := &syncCheckpoint{LastSlug: "Red-Rocks-331387", Failed: []string{"Leavenworth-344933", "Bishop-316882"}}
+
+	// Retried failures don't move the checkpoint; a retry that succeeds is
+	// no longer failed
+	cp.record("Leavenworth-344933", false, true)
+	cp.record("Bishop-316882", true, true)
+	cp.record("Squamish-331280", true, false)
+
+	if cp.LastSlug != "Squamish-331280" {
+		t.Errorf("LastSlug = %q, want Squamish-331280", cp.LastSlug)
+	}
+	want := []string{"Bishop-316882", "Squamish-331280"}
+	if !reflect.DeepEqual(cp.Failed, want) {
+		t.Errorf("Failed = %v, want %v", cp.Failed, want)
+	}
+}
+
+func TestCheckpointFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultCheckpointFile)
+
+	if cp, err := loadCheckpoint(path); err != nil || cp != nil {
+		t.Fatalf("loadCheckpoint(missing) = %v, %v; want nil, nil", cp, err)
+	}
+
+	saved := &syncCheckpoint{LastSlug: "Bishop-316882", Failed: []string{"Leavenworth-344933"}}
+	if err := saveCheckpoint(path, saved); err != nil {
+		t.Fatalf("saveCheckpoint() error = %v", err)
+	}
+	loaded, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("loadCheckpoint() = %+v, want %+v", loaded, saved)
+	}
+
+	if err := clearCheckpoint(path); err != nil {
+		t.Fatalf("clearCheckpoint() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("checkpoint still exists after clearCheckpoint(): %v", err)
+	}
+	if err := clearCheckpoint(path); err != nil {
+		t.Errorf("clearCheckpoint(missing) error = %v", err)
+	}
+}
@@ -1,18 +1,21 @@
 package main
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"log"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/database"
 	kayaClient "github.com/alexscott64/woulder/backend/internal/kaya"
+	"github.com/alexscott64/woulder/backend/internal/progress"
 	"github.com/alexscott64/woulder/backend/internal/service"
-	"github.com/joho/godotenv"
+	"github.com/alexscott64/woulder/backend/internal/syncretry"
 )
 
 // LocationConfig defines locations to sync from Kaya
@@ -29,29 +32,51 @@ func main() {
 	slugFlag := flag.String("slug", "", "Specific location slug to sync (e.g., 'Leavenworth-344933')")
 	recursiveFlag := flag.Bool("recursive", true, "Sync sub-locations recursively")
 	testFlag := flag.Bool("test", false, "Test mode: only sync Leavenworth")
-	allFlag := flag.Bool("all", false, "Sync all 105 official Kaya destinations from docs/kaya-destinations.txt")
+	allFlag := flag.Bool("all", false, "Sync all official Kaya destinations from docs/kaya-destinations.txt")
 	tokenFlag := flag.String("token", "", "Kaya API JWT token (or set KAYA_AUTH_TOKEN env var)")
 	delayFlag := flag.Int("delay", 2, "Delay in seconds between syncing destinations (for --all mode)")
+	noProgressFlag := flag.Bool("no-progress", false, "Disable the live progress bar (progress is logged periodically instead)")
+	resumeFlag := flag.Bool("resume", false, "In --all mode, skip destinations finished by the last interrupted run (retrying the ones that failed)")
+	checkpointFlag := flag.String("checkpoint", defaultCheckpointFile, "File where --all records its progress for --resume")
+	skipClosedFlag := flag.Bool("skip-closed", false, "Skip locations stored as closed (default true with --all)")
+	retryOpts := syncretry.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	// Load environment variables from backend directory
-	if err := godotenv.Load(".env"); err != nil {
-		log.Printf("Warning: .env file not found, using system environment variables")
+	if *resumeFlag && !*allFlag {
+		log.Fatal("--resume only works with --all")
+	}
+	if err := retryOpts.Validate(); err != nil {
+		log.Fatalf("Invalid retry flags: %v", err)
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Get auth token from flag or environment variable
 	authToken := *tokenFlag
 	if authToken == "" {
-		authToken = os.Getenv("KAYA_AUTH_TOKEN")
+		authToken = cfg.Kaya.AuthToken
 	}
 
-	if authToken == "" {
+	if authToken == "" && cfg.Kaya.AuthTokenFile == "" {
 		log.Println("WARNING: No auth token provided. Set KAYA_AUTH_TOKEN env var or use -token flag.")
 		log.Println("Without authentication, API calls may fail or return limited data.")
+	} else if authToken != "" {
+		if exp, ok, err := kayaClient.ParseTokenExpiry(authToken); err != nil {
+			log.Printf("WARNING: Could not read auth token expiry: %v", err)
+		} else if ok {
+			if time.Until(exp) <= 0 && cfg.Kaya.AuthTokenFile == "" {
+				log.Fatalf("Auth token expired at %s; provide a fresh token or set KAYA_AUTH_TOKEN_FILE", exp.Format(time.RFC3339))
+			}
+			log.Printf("Auth token expires at %s (in %s)", exp.Format(time.RFC3339), time.Until(exp).Round(time.Minute))
+		}
 	}
 
 	// Initialize database
-	db, err := database.New()
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -60,17 +85,29 @@ func main() {
 	// Initialize Kaya HTTP client (Cloudflare doesn't block normal HTTP requests!)
 	log.Println("Initializing Kaya API client...")
 	client := kayaClient.NewClient()
+	client.SetAuthToken(authToken)
+	client.SetRetry(retryOpts.MaxAttempts, retryOpts.BaseDelay)
+	if cfg.Kaya.AuthTokenFile != "" {
+		log.Printf("Auth token will be refreshed from %s", cfg.Kaya.AuthTokenFile)
+		client.SetTokenRefresher(kayaClient.FileTokenRefresher(cfg.Kaya.AuthTokenFile))
+	}
 	log.Println("✓ Client initialized successfully")
 
 	// Initialize Kaya sync service (no job monitor for manual sync)
 	kayaService := service.NewKayaSyncService(db.Kaya(), client, nil)
+	kayaService.SetSkipClosed(skipClosed(flag.CommandLine, *skipClosedFlag, *allFlag))
 
 	ctx := context.Background()
 
 	// Handle specific slug sync
 	if *slugFlag != "" {
 		log.Printf("Syncing specific location: %s (recursive: %v)", *slugFlag, *recursiveFlag)
-		if err := syncLocation(ctx, kayaService, *slugFlag, *recursiveFlag); err != nil {
+		err := syncLocation(ctx, kayaService, *slugFlag, *recursiveFlag)
+		if errors.Is(err, service.ErrLocationClosed) {
+			log.Printf("Skipped %s: %v (rerun without -skip-closed to sync it anyway)", *slugFlag, err)
+			return
+		}
+		if err != nil {
 			log.Fatalf("Failed to sync location %s: %v", *slugFlag, err)
 		}
 		log.Println("✓ Sync completed successfully!")
@@ -80,10 +117,10 @@ func main() {
 	// Define location mappings
 	var locationConfigs []LocationConfig
 
-	// --all flag: load all 105 destinations
+	// --all flag: load all destinations
 	if *allFlag {
-		log.Println("ALL MODE: Loading all 105 official Kaya destinations...")
-		slugs, err := loadDestinationSlugs("../docs/kaya-destinations.txt")
+		log.Println("ALL MODE: Loading all official Kaya destinations...")
+		slugs, err := kayaClient.LoadDestinationSlugs(kayaClient.DestinationsFile)
 		if err != nil {
 			log.Fatalf("Failed to load destinations file: %v", err)
 		}
@@ -116,10 +153,35 @@ func main() {
 		}
 	}
 
+	// In --all mode, record progress after each destination so an
+	// interrupted run can be resumed
+	checkpoint := &syncCheckpoint{}
+	var retrying []string
+	if *allFlag && *resumeFlag {
+		previous, err := loadCheckpoint(*checkpointFlag)
+		if err != nil {
+			log.Fatalf("Failed to load checkpoint: %v", err)
+		}
+		if previous == nil {
+			log.Printf("No checkpoint found at %s; starting from the first destination", *checkpointFlag)
+		} else if remaining, ok := remainingLocations(locationConfigs, previous); !ok {
+			log.Printf("WARNING: checkpoint destination %s is not in the destinations file; starting from the first destination", previous.LastSlug)
+		} else {
+			log.Printf("Resuming after %s (checkpoint from %s): %d of %d destinations left, including %d failed ones to retry",
+				previous.LastSlug, previous.UpdatedAt.Format(time.RFC3339), len(remaining), len(locationConfigs), len(previous.Failed))
+			locationConfigs = remaining
+			checkpoint = previous
+			retrying = slices.Clone(previous.Failed)
+		}
+	}
+
 	totalLocations := len(locationConfigs)
 	successCount := 0
 	failCount := 0
+	skippedCount := 0
 	startTime := time.Now()
+	tracker := progress.New("locations", totalLocations, !*noProgressFlag && progress.IsTerminal(os.Stdout))
+	log.SetOutput(tracker.LogWriter(os.Stderr))
 
 	// Process each location
 	for i, config := range locationConfigs {
@@ -128,7 +190,31 @@ func main() {
 		log.Printf("Slug: %s (recursive: %v)", config.Slug, config.Recursive)
 		log.Printf("========================================")
 
-		if err := syncLocation(ctx, kayaService, config.Slug, config.Recursive); err != nil {
+		err := syncLocation(ctx, kayaService, config.Slug, config.Recursive)
+		tracker.Increment()
+		if errors.Is(err, service.ErrLocationClosed) {
+			log.Printf("Skipping %s: %v", config.Name, err)
+			skippedCount++
+			if *allFlag {
+				checkpoint.record(config.Slug, false, slices.Contains(retrying, config.Slug))
+				if err := saveCheckpoint(*checkpointFlag, checkpoint); err != nil {
+					log.Printf("WARNING: %v", err)
+				}
+			}
+			continue
+		}
+		if errors.Is(err, kayaClient.ErrTokenExpired) {
+			// Every remaining location would fail the same way. The
+			// checkpoint is left before this destination for --resume.
+			log.Fatalf("ERROR syncing %s: %v; aborting after %d/%d locations", config.Name, err, successCount, totalLocations)
+		}
+		if *allFlag {
+			checkpoint.record(config.Slug, err != nil, slices.Contains(retrying, config.Slug))
+			if err := saveCheckpoint(*checkpointFlag, checkpoint); err != nil {
+				log.Printf("WARNING: %v", err)
+			}
+		}
+		if err != nil {
 			log.Printf("ERROR syncing %s: %v", config.Name, err)
 			failCount++
 
@@ -151,6 +237,7 @@ func main() {
 		}
 	}
 
+	tracker.Finish()
 	elapsed := time.Since(startTime)
 
 	log.Printf("\n========================================")
@@ -159,84 +246,48 @@ func main() {
 	log.Printf("Total locations processed: %d", totalLocations)
 	log.Printf("Successful: %d", successCount)
 	log.Printf("Failed: %d", failCount)
+	log.Printf("Skipped (closed): %d", skippedCount)
 	log.Printf("Time elapsed: %s", elapsed.Round(time.Second))
 	log.Printf("========================================")
 
+	if *allFlag {
+		if len(checkpoint.Failed) == 0 {
+			if err := clearCheckpoint(*checkpointFlag); err != nil {
+				log.Printf("WARNING: %v", err)
+			}
+		} else {
+			log.Printf("%d destinations failed; rerun with --resume to retry only those", len(checkpoint.Failed))
+		}
+	}
+
 	if failCount > 0 {
 		os.Exit(1)
 	}
 }
 
-// syncLocation syncs a single location with error handling
+// syncLocation syncs a single location. The client retries transient
+// request failures.
 func syncLocation(ctx context.Context, service *service.KayaSyncService, slug string, recursive bool) error {
 	log.Printf("Starting sync for slug: %s", slug)
 
-	err := service.SyncLocationBySlug(ctx, slug, recursive)
-	if err != nil {
-		// Check if it's a transient error
-		if isTransientError(err) {
-			log.Printf("Transient error detected, retrying in 5 seconds...")
-			time.Sleep(5 * time.Second)
-			err = service.SyncLocationBySlug(ctx, slug, recursive)
-		}
-	}
-
-	return err
+	return service.SyncLocationBySlug(ctx, slug, recursive)
 }
 
-// isTransientError checks if an error is likely transient and worth retrying
-func isTransientError(err error) bool {
-	if err == nil {
-		return false
-	}
-	errMsg := strings.ToLower(err.Error())
-	transientKeywords := []string{
-		"timeout",
-		"connection refused",
-		"temporary failure",
-		"502",
-		"503",
-		"504",
-	}
-
-	for _, keyword := range transientKeywords {
-		if strings.Contains(errMsg, keyword) {
-			return true
+// skipClosed reports whether closed locations are skipped: as set by
+// -skip-closed, or by default with --all, where most closed destinations are
+// synced for nothing. Syncing one slug doesn't skip it unless asked, so a
+// closed location can still be refreshed by hand.
+func skipClosed(fs *flag.FlagSet, flagValue, all bool) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "skip-closed" {
+			set = true
 		}
+	})
+	if set {
+		return flagValue
 	}
-	return false
-}
-
-// loadDestinationSlugs loads destination slugs from the destinations file
-func loadDestinationSlugs(filepath string) ([]string, error) {
-	file, err := os.Open(filepath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	var slugs []string
-	scanner := bufio.NewScanner(file)
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		// Skip empty lines, comments, and header lines
-		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Total:") {
-			continue
-		}
-
-		// Valid slug format: "Name-ID"
-		if strings.Contains(line, "-") && !strings.HasPrefix(line, "Format:") {
-			slugs = append(slugs, line)
-		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	return slugs, nil
+	return all
 }
 
 // extractLocationName extracts human-readable name from slug
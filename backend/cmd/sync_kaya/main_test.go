@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSkipClosed(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		all  bool
+		want bool
+	}{
+		{"default for one slug", nil, false, false},
+		{"default with --all", nil, true, true},
+		{"enabled for one slug", []string{"-skip-closed"}, false, true},
+		{"disabled with --all", []string{"-skip-closed=false"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			value := fs.Bool("skip-closed", false, "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := skipClosed(fs, *value, tt.all); got != tt.want {
+				t.Errorf("skipClosed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -3,19 +3,24 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"math"
-	"os"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/database"
 	kayaClient "github.com/alexscott64/woulder/backend/internal/kaya"
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
+	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/alexscott64/woulder/backend/internal/monitoring"
+	"github.com/alexscott64/woulder/backend/internal/mountainproject"
 	"github.com/alexscott64/woulder/backend/internal/service"
-	"github.com/joho/godotenv"
+	"github.com/alexscott64/woulder/backend/internal/syncretry"
 	_ "github.com/lib/pq"
 )
 
@@ -26,28 +31,60 @@ func main() {
 	// Command-line flags
 	incrementalFlag := flag.Bool("incremental", true, "Only sync new data since last sync")
 	forceFlag := flag.Bool("force", false, "Force a full sync even when --incremental is enabled")
+	maxAgeFlag := flag.Duration("max-age", 24*time.Hour, "With --incremental, skip locations whose last successful sync is newer than this")
 	testFlag := flag.Bool("test", false, "Test mode: only sync 3 destinations")
 	delayFlag := flag.Int("delay", 3, "Delay in seconds between destinations")
+	concurrencyFlag := flag.Int("concurrency", 1, "Number of destinations to sync at once; all of them share the --requests-per-second budget")
+	requestsPerSecondFlag := flag.Float64("requests-per-second", 1, "Maximum Kaya API requests per second across all concurrent syncs")
 	matchAfterSyncFlag := flag.Bool("match-after-sync", true, "Run Kaya↔MP matching after each successful location sync")
 	matchMinConfidenceFlag := flag.Float64("match-min-confidence", 0.75, "Minimum confidence for Kaya↔MP route matching")
+	destinationsFlag := flag.String("destinations", kayaClient.DestinationsFile, "File listing the destination slugs to sync; the built-in list is used if it can't be read")
+	queueFlag := flag.Bool("queue", false, "Sync the next batch of locations due in kaya_sync_progress instead of the destination list")
+	batchFlag := flag.Int("batch", 25, "Number of due locations to sync per run in --queue mode")
+	matchThresholdsFlag := flag.String("match-thresholds", "", "JSON file overriding Kaya↔MP match thresholds")
+	matchAutoApproveFlag := flag.Float64("match-auto-approve-threshold", kayamatch.DefaultAutoApproveThreshold, "Confidence at or above which Kaya↔MP matches are saved as approved; lower matches are queued for review")
+	kayamatch.RegisterFlags(flag.CommandLine)
+	skipClosedFlag := flag.Bool("skip-closed", true, "Skip locations stored as closed; new locations are synced once to learn whether they are")
+	retryOpts := syncretry.RegisterFlags(flag.CommandLine)
 	flag.Parse()
 
-	// Load environment variables - try current directory first, then parent
-	if err := godotenv.Load(".env"); err != nil {
-		if err := godotenv.Load("../.env"); err != nil {
-			log.Printf("Warning: .env file not found in . or .., using system environment variables")
-		}
+	if *maxAgeFlag <= 0 {
+		log.Fatalf("Invalid -max-age %v: must be positive", *maxAgeFlag)
+	}
+	if *concurrencyFlag < 1 {
+		log.Fatalf("Invalid -concurrency %d: must be at least 1", *concurrencyFlag)
+	}
+	if *requestsPerSecondFlag <= 0 {
+		log.Fatalf("Invalid -requests-per-second %v: must be positive", *requestsPerSecondFlag)
+	}
+	if err := retryOpts.Validate(); err != nil {
+		log.Fatalf("Invalid retry flags: %v", err)
+	}
+
+	if err := kayamatch.ValidateAutoApproveThreshold(*matchAutoApproveFlag); err != nil {
+		log.Fatalf("Invalid -match-auto-approve-threshold: %v", err)
+	}
+
+	thresholds, err := kayamatch.ResolveThresholds(flag.CommandLine, *matchThresholdsFlag)
+	if err != nil {
+		log.Fatalf("Invalid match thresholds: %v", err)
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	// Initialize database
-	db, err := database.New()
+	db, err := database.New(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 	defer db.Close()
 
 	// Create a separate SQL connection for job monitoring
-	monitorDB, err := createMonitoringDB()
+	monitorDB, err := createMonitoringDB(cfg.Database)
 	if err != nil {
 		log.Fatalf("Failed to create monitoring database connection: %v", err)
 	}
@@ -56,34 +93,68 @@ func main() {
 	// Initialize job monitor
 	jobMonitor := monitoring.NewJobMonitor(monitorDB)
 
-	// Load destinations to determine total items
-	destinations, err := loadDestinations()
-	if err != nil {
-		log.Fatalf("Failed to load destinations: %v", err)
+	// One service per worker, since a service runs one sync at a time. They
+	// share a client, so the request budget covers all of them, and a sync
+	// run, so content shared between locations is saved and counted once.
+	client := newKayaClient(cfg.Kaya, *requestsPerSecondFlag, *retryOpts)
+	syncRun := service.NewKayaSyncRun()
+	kayaServices := make([]*service.KayaSyncService, *concurrencyFlag)
+	for i := range kayaServices {
+		kayaServices[i] = service.NewKayaSyncService(db.Kaya(), client, nil)
+		kayaServices[i].SetSyncRun(syncRun)
+		kayaServices[i].SetSkipClosed(*skipClosedFlag)
+	}
+	kayaService := kayaServices[0]
+
+	// Load targets to determine total items. Queue mode only sees locations
+	// that already have a kaya_sync_progress row, i.e. destinations synced at
+	// least once by a list run.
+	var targets []syncTarget
+	if *queueFlag {
+		due, err := kayaService.GetLocationsDueForSync(context.Background(), *batchFlag)
+		if err != nil {
+			log.Fatalf("Failed to load locations due for sync: %v", err)
+		}
+		targets = queueTargets(context.Background(), due, db.Kaya().Locations().GetLocationByID)
+		log.Printf("Queue mode: %d location(s) due for sync", len(targets))
+	} else {
+		targets = destinationTargets(loadDestinations(*destinationsFlag))
 	}
 
 	// Test mode: only sync first 3
 	if *testFlag {
 		log.Println("TEST MODE: Only syncing first 3 destinations")
-		if len(destinations) > 3 {
-			destinations = destinations[:3]
+		if len(targets) > 3 {
+			targets = targets[:3]
 		}
 	}
 
 	// Start job run
 	jobName := "kaya_sync"
 	jobType := "full"
-	if *incrementalFlag && !*forceFlag {
+	if *queueFlag {
+		jobType = "queue"
+	} else if *incrementalFlag && !*forceFlag {
 		jobType = "incremental"
 	}
 
-	jobExec, err := jobMonitor.StartJob(context.Background(), jobName, jobType, len(destinations), map[string]interface{}{
+	jobExec, err := jobMonitor.StartJob(context.Background(), jobName, jobType, len(targets), map[string]interface{}{
 		"incremental":          *incrementalFlag,
+		"max_age":              maxAgeFlag.String(),
 		"force":                *forceFlag,
+		"queue":                *queueFlag,
+		"batch":                *batchFlag,
 		"test_mode":            *testFlag,
 		"delay":                *delayFlag,
+		"concurrency":          *concurrencyFlag,
+		"requests_per_second":  *requestsPerSecondFlag,
+		"retry_attempts":       retryOpts.MaxAttempts,
+		"retry_delay":          retryOpts.BaseDelay.String(),
+		"skip_closed":          *skipClosedFlag,
 		"match_after_sync":     *matchAfterSyncFlag,
 		"match_min_confidence": *matchMinConfidenceFlag,
+		"match_thresholds":     thresholds,
+		"match_auto_approve":   *matchAutoApproveFlag,
 	})
 	if err != nil {
 		log.Fatalf("Failed to start job tracking: %v", err)
@@ -93,22 +164,53 @@ func main() {
 	startTime := time.Now()
 
 	// Run sync
-	successCount, failCount := runSync(
+	successCount, failCount, abortErr := runSync(
 		db,
 		monitorDB,
 		jobMonitor,
 		jobExec.ID,
-		destinations,
-		*incrementalFlag && !*forceFlag,
+		kayaServices,
+		targets,
+		*incrementalFlag && !*forceFlag && !*queueFlag,
+		*maxAgeFlag,
 		*delayFlag,
 		*matchAfterSyncFlag,
 		*matchMinConfidenceFlag,
+		thresholds,
+		*matchAutoApproveFlag,
 	)
 
 	// Complete job tracking
 	duration := time.Since(startTime)
+	allFailed := (failCount > 0 && successCount == 0) || abortErr != nil
+
+	// Persist a per-run summary for trend analysis (GET /api/monitoring/trends)
+	jobID := jobExec.ID
+	summaryStatus := monitoring.StatusCompleted
+	if allFailed {
+		summaryStatus = monitoring.StatusFailed
+	}
+	if err := jobMonitor.RecordSyncSummary(context.Background(), &monitoring.SyncSummary{
+		JobExecutionID: &jobID,
+		JobName:        jobName,
+		JobType:        jobType,
+		Status:         summaryStatus,
+		StartedAt:      startTime,
+		ItemsProcessed: len(targets),
+		ItemsSucceeded: successCount,
+		ItemsFailed:    failCount,
+	}); err != nil {
+		log.Printf("Warning: failed to record sync summary: %v", err)
+	}
+
+	if abortErr != nil {
+		// Aborted (e.g. expired auth token): remaining destinations were not synced
+		errMsg := fmt.Sprintf("Sync aborted after %d destination(s): %v", successCount, abortErr)
+		jobMonitor.FailJob(context.Background(), jobExec.ID, errMsg)
+		log.Fatalf("Kaya sync job failed after %s: %s", duration, errMsg)
+	}
 
-	if failCount > 0 && successCount == 0 {
+	if allFailed {
 		// Complete failure
 		errMsg := "All destinations failed to sync"
 		jobMonitor.FailJob(context.Background(), jobExec.ID, errMsg)
@@ -120,263 +222,351 @@ func main() {
 	log.Printf("✓ Kaya sync job completed in %s (success: %d, failed: %d)", duration, successCount, failCount)
 }
 
+// syncTarget is one location to sync: a destination slug from the embedded
+// list, or an entry from the sync queue (Progress set).
+type syncTarget struct {
+	Slug     string
+	Progress *models.KayaSyncProgress
+}
+
+func (t syncTarget) label() string {
+	if t.Slug != "" {
+		return t.Slug
+	}
+	return t.Progress.LocationName
+}
+
+func destinationTargets(slugs []string) []syncTarget {
+	targets := make([]syncTarget, len(slugs))
+	for i, slug := range slugs {
+		targets[i] = syncTarget{Slug: slug}
+	}
+	return targets
+}
+
+// queueTargets resolves the slug of each due location for logging and route
+// matching. A location missing from kaya_locations is kept without a slug;
+// the sync records the failure on its queue entry.
+func queueTargets(
+	ctx context.Context,
+	due []*models.KayaSyncProgress,
+	getLocation func(ctx context.Context, kayaLocationID string) (*models.KayaLocation, error),
+) []syncTarget {
+	targets := make([]syncTarget, 0, len(due))
+	for _, progress := range due {
+		target := syncTarget{Progress: progress}
+		loc, err := getLocation(ctx, progress.KayaLocationID)
+		if err != nil {
+			log.Printf("Warning: failed to look up location %s: %v", progress.KayaLocationID, err)
+		} else if loc != nil {
+			target.Slug = loc.Slug
+		}
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// newKayaClient returns a client limited to requestsPerSecond in total,
+// however many syncs share it, that retries transient request failures as
+// retryOpts says.
+func newKayaClient(kayaCfg config.KayaConfig, requestsPerSecond float64, retryOpts syncretry.Options) *kayaClient.Client {
+	client := kayaClient.NewClient()
+	client.SetAuthToken(kayaCfg.AuthToken)
+	client.SetRetry(retryOpts.MaxAttempts, retryOpts.BaseDelay)
+	if kayaCfg.AuthTokenFile != "" {
+		client.SetTokenRefresher(kayaClient.FileTokenRefresher(kayaCfg.AuthTokenFile))
+	}
+	client.SetRequestLimiter(mountainproject.NewRequestBudget(requestsPerSecond))
+	return client
+}
+
+// runSync syncs targets on up to len(kayaServices) workers, each using its
+// own service. Progress and counters are shared under a mutex, so the job's
+// progress stays accurate whichever worker finishes first. An expired auth
+// token stops new targets from starting and counts them as failed.
 func runSync(
 	db *database.Database,
 	sqlDB *sql.DB,
 	jobMonitor *monitoring.JobMonitor,
 	jobID int64,
-	destinations []string,
+	kayaServices []*service.KayaSyncService,
+	targets []syncTarget,
 	incremental bool,
+	maxAge time.Duration,
 	delay int,
 	matchAfterSync bool,
 	matchMinConfidence float64,
-) (int, int) {
+	thresholds kayamatch.Thresholds,
+	autoApproveThreshold float64,
+) (int, int, error) {
 	ctx := context.Background()
 
-	// Initialize Kaya client
-	client := kayaClient.NewClient()
-
-	// Initialize Kaya sync service
-	kayaService := service.NewKayaSyncService(db.Kaya(), client, nil)
-
+	// mu guards the counters and keeps progress updates in order.
+	var mu sync.Mutex
 	successCount := 0
 	failCount := 0
 	processed := 0
 
 	matchedCount := 0
+	approvedCount := 0
 	rejectedCount := 0
 
-	for i, slug := range destinations {
-		log.Printf("\n[%d/%d] Syncing %s...", i+1, len(destinations), slug)
+	// finish records a finished target and reports progress.
+	finish := func(succeeded, failed bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		processed++
+		if succeeded {
+			successCount++
+		}
+		if failed {
+			failCount++
+		}
+		jobMonitor.UpdateProgress(ctx, jobID, processed, successCount, failCount)
+	}
+
+	if len(kayaServices) > 1 {
+		log.Printf("Syncing with %d workers", len(kayaServices))
+	}
+
+	abortErr := runPool(len(kayaServices), len(targets), func(worker, i int) error {
+		target := targets[i]
+		kayaService := kayaServices[worker]
+		slug := target.label()
+		log.Printf("\n[%d/%d] Syncing %s...", i+1, len(targets), slug)
 
 		// For incremental sync, check if we need to sync this location
 		if incremental {
-			shouldSync, err := shouldSyncLocation(ctx, db, slug)
+			shouldSync, err := shouldSyncLocation(ctx, db.Kaya().Sync().GetLastSyncedAt, slug, maxAge, time.Now())
 			if err != nil {
 				log.Printf("Error checking sync status for %s: %v", slug, err)
 			} else if !shouldSync {
-				log.Printf("Skipping %s (recently synced)", slug)
-				processed++
-				jobMonitor.UpdateProgress(ctx, jobID, processed, successCount, failCount)
-				continue
+				log.Printf("Skipping %s (synced within the last %v)", slug, maxAge)
+				finish(false, false)
+				return nil
 			}
 		}
 
-		// Sync location
-		err := kayaService.SyncLocationBySlug(ctx, slug, true)
-		processed++
+		// Sync location; the client retries transient request failures
+		var err error
+		if target.Progress != nil {
+			err = kayaService.SyncDueLocation(ctx, target.Progress, true)
+		} else {
+			err = kayaService.SyncLocationBySlug(ctx, slug, true)
+		}
+
+		if errors.Is(err, service.ErrLocationClosed) {
+			log.Printf("Skipping %s: %v", slug, err)
+			finish(false, false)
+			return nil
+		}
+
+		if errors.Is(err, kayaClient.ErrTokenExpired) {
+			// Every remaining destination would fail the same way
+			log.Printf("ERROR syncing %s: %v; aborting remaining destinations", slug, err)
+			finish(false, true)
+			return err
+		}
 
 		if err != nil {
 			log.Printf("ERROR syncing %s: %v", slug, err)
-			failCount++
+			finish(false, true)
 		} else {
-			successCount++
 			log.Printf("✓ Synced %s", slug)
 
-			if matchAfterSync {
-				newMatches, rejected, matchErr := matchRoutesForDestinationSlug(ctx, sqlDB, slug, matchMinConfidence)
+			if matchAfterSync && target.Slug != "" {
+				newMatches, approved, rejected, matchErr := matchRoutesForDestinationSlug(ctx, sqlDB, slug, matchMinConfidence, thresholds, autoApproveThreshold)
 				if matchErr != nil {
 					log.Printf("WARNING matching failed for %s: %v", slug, matchErr)
 				} else {
+					log.Printf("  ↳ Matching: %d saved (%d auto-approved, %d queued for review), %d rejected (discipline/grade mismatch)",
+						newMatches, approved, newMatches-approved, rejected)
+					mu.Lock()
 					matchedCount += newMatches
+					approvedCount += approved
 					rejectedCount += rejected
-					log.Printf("  ↳ Matching: %d saved, %d rejected (discipline/grade mismatch)", newMatches, rejected)
 					_ = jobMonitor.UpdateCurrentItem(ctx, jobID, map[string]interface{}{
-						"current_destination_slug": slug,
-						"matching_saved":           newMatches,
-						"matching_rejected":        rejected,
-						"matching_total_saved":     matchedCount,
-						"matching_total_rejected":  rejectedCount,
+						"current_destination_slug":     slug,
+						"matching_saved":               newMatches,
+						"matching_auto_approved":       approved,
+						"matching_rejected":            rejected,
+						"matching_total_saved":         matchedCount,
+						"matching_total_auto_approved": approvedCount,
+						"matching_total_rejected":      rejectedCount,
 					})
+					mu.Unlock()
 				}
 			}
+			finish(true, false)
 		}
 
-		// Update progress
-		jobMonitor.UpdateProgress(ctx, jobID, processed, successCount, failCount)
-
 		// Rate limiting
-		if i < len(destinations)-1 {
+		if i < len(targets)-1 {
 			time.Sleep(time.Duration(delay) * time.Second)
 		}
+		return nil
+	})
+
+	if abortErr != nil {
+		// Targets that never started count as failed
+		failCount += len(targets) - processed
+		jobMonitor.UpdateProgress(ctx, jobID, len(targets), successCount, failCount)
+		return successCount, failCount, abortErr
 	}
 
 	log.Printf("\n========================================")
 	log.Printf("Sync Summary:")
-	log.Printf("Total: %d, Success: %d, Failed: %d", len(destinations), successCount, failCount)
+	log.Printf("Total: %d, Success: %d, Failed: %d", len(targets), successCount, failCount)
 	if matchAfterSync {
 		log.Printf("Matching saved: %d, rejected: %d", matchedCount, rejectedCount)
+		log.Printf("Auto-approved (≥%.2f): %d, queued for review: %d", autoApproveThreshold, approvedCount, matchedCount-approvedCount)
+		thresholds.Log()
 	}
 	log.Printf("========================================")
 
-	return successCount, failCount
-}
-
-func loadDestinations() ([]string, error) {
-	// Embedded list of all 105 Kaya official destinations
-	// Source: https://kayaclimb.com/explore (extracted 2026-02-18)
-	destinations := []string{
-		"Squamish-295658",
-		"Red-Rocks-331387",
-		"Bishop-316882",
-		"Joshua-Tree-317008",
-		"Hueco-Tanks-339538",
-		"Joes-Valley-340826",
-		"Vancouver-Island-295813",
-		"Clear-Creek-Canyon-323872",
-		"Ogden-1153006",
-		"Lincoln-Lake-5272477",
-		"Guanella-Pass-323792",
-		"Tahoe-317136",
-		"Little-Cottonwood-Canyon-986245",
-		"New-River-Gorge-347179",
-		"Coopers-Rock-347182",
-		"Smith-Rock-336540",
-		"Black-Mountain-317072",
-		"Leavenworth-344933",
-		"Kelowna-296013",
-		"Hatcher-Pass-314961",
-		"Devils-Lake-348323",
-		"Lake-Ramona-10400507",
-		"RMNP-323755",
-		"Tramway-317070",
-		"Vancouver-296037",
-		"Ibex-341212",
-		"Stone-Fort-999671",
-		"Mount-Woodson-2192166",
-		"Red-Feather-324534",
-		"Flagstaff-Mountain-323839",
-		"Big-Cottonwood-Canyon-BCC-341957",
-		"Fraser-Valley-3340725",
-		"Reimers-Ranch-339808",
-		"Horseshoe-Canyon-Ranch-316278",
-		"Tulsa-OK-10116402",
-		"Mineral-King-15161231",
-		"Rumbling-Bald-335837",
-		"Rocktown-327484",
-		"Horse-Pens-40-983782",
-		"Malibu-838425",
-		"Santa-Barbara-317853",
-		"Doyle-322152",
-		"Comox-Valley-Vancouver-Island-BC-7882675",
-		"NYC-Bouldering-8736175",
-		"Moes-Valley-340851",
-		"Gold-Bar-344983",
-		"The-Nooks-3899367",
-		"Adirondacks-335103",
-		"Stoney-Point-317772",
-		"Treasury-2106513",
-		"Eldorado-Canyon-323915",
-		"Uintas-1394571",
-		"holy-boulders-1016922",
-		"Gunpowder-Falls-1395399",
-		"Boat-Rock-327557",
-		"Reynolds-Creek-328023",
-		"Triassic-341357",
-		"Needle-Peak-658063",
-		"Box-Springs-Mountain-Reserve-5727203",
-		"Horse-Flats-317843",
-		"Mt-Evans-323773",
-		"Smugglers-Notch-344705",
-		"Rock-shop-348813",
-		"Morpheus-345195",
-		"Berkeley-316984",
-		"Mount-Rubidoux-321790",
-		"Index-345070",
-		"purgatory-851804",
-		"Vernon-4132330",
-		"Exit-38-345299",
-		"Castle-Rock-State-Park-328014",
-		"Sams-Throne-316415",
-		"Patapsco-Valley-State-Park-8555804",
-		"Porcupine-Hills-6234426",
-		"Cowell-316321",
-		"Dixon-School-Road-335964",
-		"Barton-Creek-Greenbelt-339852",
-		"Utah-Hills-341651",
-		"Price-1361664",
-		"Big-Rock-291216",
-		"Rogers-Park-339768",
-		"Salt-Point-317575",
-		"The-Citadel-295573",
-		"Sierra-Buttes-318225",
-		"Hammond-Pond-330274",
-		"Nut-Tree-990859",
-		"Santee-Boulders-2376083",
-		"Indian-Rock-3199690",
-		"Juan-De-Fuca-7846367",
-		"Richland-Creek-15036518",
-		"Lost-Ledges-345403",
-		"Lions-Den-334501",
-		"Conejo-Mountain-9502266",
-		"Mckinney-Falls-1493881",
-		"Wadi-Rum-389777",
-		"Rocks-State-Park-330207",
-		"Sawmill-330569",
-		"Mt-Tamalpais-318183",
-		"Rock-Creek-317075",
-		"Sugarloaf-Ridge-State-Park-1770584",
-	}
-
-	return destinations, nil
-}
-
-type kayaSyncStatus struct {
-	Status     sql.NullString
-	LastSyncAt sql.NullTime
-	NextSyncAt sql.NullTime
+	return successCount, failCount, nil
 }
 
-func shouldSyncLocation(ctx context.Context, db *database.Database, slug string) (bool, error) {
-	progress, err := getKayaSyncStatusBySlug(ctx, db.Conn(), slug)
+// loadDestinations reads the destination slugs from path, the same file
+// sync_kaya --all uses. If it can't be read or lists nothing, the built-in
+// fallbackDestinations are used instead so a missing file doesn't stop the job.
+func loadDestinations(path string) []string {
+	slugs, err := kayaClient.LoadDestinationSlugs(path)
 	if err != nil {
-		return true, err
+		log.Printf("Warning: failed to load destinations from %s, using the built-in list: %v", path, err)
+		return fallbackDestinations
 	}
-	return shouldSyncKayaProgress(progress, time.Now()), nil
-}
-
-func getKayaSyncStatusBySlug(ctx context.Context, db *sql.DB, slug string) (*kayaSyncStatus, error) {
-	const query = `
-		SELECT p.status, p.last_sync_at, p.next_sync_at
-		FROM woulder.kaya_locations l
-		LEFT JOIN woulder.kaya_sync_progress p ON p.kaya_location_id = l.kaya_location_id
-		WHERE l.slug = $1
-		LIMIT 1
-	`
-
-	var progress kayaSyncStatus
-	err := db.QueryRowContext(ctx, query, slug).Scan(
-		&progress.Status,
-		&progress.LastSyncAt,
-		&progress.NextSyncAt,
-	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
+	if len(slugs) == 0 {
+		log.Printf("Warning: %s lists no destinations, using the built-in list", path)
+		return fallbackDestinations
 	}
-	return &progress, nil
+	log.Printf("Loaded %d destinations from %s", len(slugs), path)
+	return slugs
 }
 
-func shouldSyncKayaProgress(progress *kayaSyncStatus, now time.Time) bool {
-	if progress == nil || !progress.Status.Valid {
-		return true
-	}
-
-	if progress.Status.String != "completed" {
-		return true
-	}
-
-	if !progress.LastSyncAt.Valid {
-		return true
-	}
+// fallbackDestinations is used when the destinations file can't be read. It
+// mirrors docs/kaya-destinations.txt (a test keeps them in sync); add new
+// destinations to the file, not here.
+// Source: https://kayaclimb.com/explore (extracted 2026-02-18)
+var fallbackDestinations = []string{
+	"Squamish-295658",
+	"Red-Rocks-331387",
+	"Bishop-316882",
+	"Joshua-Tree-317008",
+	"Hueco-Tanks-339538",
+	"Joes-Valley-340826",
+	"Vancouver-Island-295813",
+	"Clear-Creek-Canyon-323872",
+	"Ogden-1153006",
+	"Lincoln-Lake-5272477",
+	"Guanella-Pass-323792",
+	"Tahoe-317136",
+	"Little-Cottonwood-Canyon-986245",
+	"New-River-Gorge-347179",
+	"Coopers-Rock-347182",
+	"Smith-Rock-336540",
+	"Black-Mountain-317072",
+	"Leavenworth-344933",
+	"Kelowna-296013",
+	"Hatcher-Pass-314961",
+	"Devils-Lake-348323",
+	"Lake-Ramona-10400507",
+	"RMNP-323755",
+	"Tramway-317070",
+	"Vancouver-296037",
+	"Ibex-341212",
+	"Stone-Fort-999671",
+	"Mount-Woodson-2192166",
+	"Red-Feather-324534",
+	"Flagstaff-Mountain-323839",
+	"Big-Cottonwood-Canyon-BCC-341957",
+	"Fraser-Valley-3340725",
+	"Reimers-Ranch-339808",
+	"Horseshoe-Canyon-Ranch-316278",
+	"Tulsa-OK-10116402",
+	"Mineral-King-15161231",
+	"Rumbling-Bald-335837",
+	"Rocktown-327484",
+	"Horse-Pens-40-983782",
+	"Malibu-838425",
+	"Santa-Barbara-317853",
+	"Doyle-322152",
+	"Comox-Valley-Vancouver-Island-BC-7882675",
+	"NYC-Bouldering-8736175",
+	"Moes-Valley-340851",
+	"Gold-Bar-344983",
+	"The-Nooks-3899367",
+	"Adirondacks-335103",
+	"Stoney-Point-317772",
+	"Treasury-2106513",
+	"Eldorado-Canyon-323915",
+	"Uintas-1394571",
+	"holy-boulders-1016922",
+	"Gunpowder-Falls-1395399",
+	"Boat-Rock-327557",
+	"Reynolds-Creek-328023",
+	"Triassic-341357",
+	"Needle-Peak-658063",
+	"Box-Springs-Mountain-Reserve-5727203",
+	"Horse-Flats-317843",
+	"Mt-Evans-323773",
+	"Smugglers-Notch-344705",
+	"Rock-shop-348813",
+	"Morpheus-345195",
+	"Berkeley-316984",
+	"Mount-Rubidoux-321790",
+	"Index-345070",
+	"purgatory-851804",
+	"Vernon-4132330",
+	"Exit-38-345299",
+	"Castle-Rock-State-Park-328014",
+	"Sams-Throne-316415",
+	"Patapsco-Valley-State-Park-8555804",
+	"Porcupine-Hills-6234426",
+	"Cowell-316321",
+	"Dixon-School-Road-335964",
+	"Barton-Creek-Greenbelt-339852",
+	"Utah-Hills-341651",
+	"Price-1361664",
+	"Big-Rock-291216",
+	"Rogers-Park-339768",
+	"Salt-Point-317575",
+	"The-Citadel-295573",
+	"Sierra-Buttes-318225",
+	"Hammond-Pond-330274",
+	"Nut-Tree-990859",
+	"Santee-Boulders-2376083",
+	"Indian-Rock-3199690",
+	"Juan-De-Fuca-7846367",
+	"Richland-Creek-15036518",
+	"Lost-Ledges-345403",
+	"Lions-Den-334501",
+	"Conejo-Mountain-9502266",
+	"Mckinney-Falls-1493881",
+	"Wadi-Rum-389777",
+	"Rocks-State-Park-330207",
+	"Sawmill-330569",
+	"Mt-Tamalpais-318183",
+	"Rock-Creek-317075",
+	"Sugarloaf-Ridge-State-Park-1770584",
+}
 
-	if progress.NextSyncAt.Valid {
-		return !progress.NextSyncAt.Time.After(now)
+// shouldSyncLocation reports whether an incremental run should sync the
+// location with this slug: it has never synced successfully, or its last
+// successful sync is at least maxAge old. On error it still returns true, so
+// a failed lookup never skips a location.
+func shouldSyncLocation(
+	ctx context.Context,
+	getLastSyncedAt func(ctx context.Context, slug string) (*time.Time, error),
+	slug string,
+	maxAge time.Duration,
+	now time.Time,
+) (bool, error) {
+	lastSyncedAt, err := getLastSyncedAt(ctx, slug)
+	if err != nil {
+		return true, err
 	}
-
-	return !progress.LastSyncAt.Time.Add(24 * time.Hour).After(now)
+	return lastSyncedAt == nil || now.Sub(*lastSyncedAt) >= maxAge, nil
 }
 
 type kayaClimbForMatching struct {
@@ -403,29 +593,37 @@ type routeMatchForSync struct {
 	LocationNameMatch bool
 }
 
-func matchRoutesForDestinationSlug(ctx context.Context, db *sql.DB, destinationSlug string, minConfidence float64) (int, int, error) {
+// matchRoutesForDestinationSlug matches and saves the climbs of a destination,
+// returning how many matches were saved, how many of those were auto-approved,
+// and how many candidates were rejected as incompatible.
+func matchRoutesForDestinationSlug(ctx context.Context, db *sql.DB, destinationSlug string, minConfidence float64, thresholds kayamatch.Thresholds, autoApproveThreshold float64) (int, int, int, error) {
 	climbs, err := getKayaClimbsForDestinationSlug(ctx, db, destinationSlug)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 
 	saved := 0
+	approved := 0
 	rejected := 0
 	for _, climb := range climbs {
-		matches, rejectedForClimb, err := findMPMatchesForSync(ctx, db, climb, minConfidence)
+		matches, rejectedForClimb, err := findMPMatchesForSync(ctx, db, climb, minConfidence, thresholds)
 		rejected += rejectedForClimb
 		if err != nil {
-			return saved, rejected, err
+			return saved, approved, rejected, err
 		}
 		for _, match := range matches {
-			if err := saveRouteMatch(ctx, db, match); err != nil {
-				return saved, rejected, err
+			status := kayamatch.StatusFor(match.Confidence, autoApproveThreshold)
+			if err := saveRouteMatch(ctx, db, match, status); err != nil {
+				return saved, approved, rejected, err
 			}
 			saved++
+			if status == kayamatch.StatusApproved {
+				approved++
+			}
 		}
 	}
 
-	return saved, rejected, nil
+	return saved, approved, rejected, nil
 }
 
 func getKayaClimbsForDestinationSlug(ctx context.Context, db *sql.DB, destinationSlug string) ([]kayaClimbForMatching, error) {
@@ -481,7 +679,7 @@ func getKayaClimbsForDestinationSlug(ctx context.Context, db *sql.DB, destinatio
 	return climbs, rows.Err()
 }
 
-func findMPMatchesForSync(ctx context.Context, db *sql.DB, climb kayaClimbForMatching, minConfidence float64) ([]routeMatchForSync, int, error) {
+func findMPMatchesForSync(ctx context.Context, db *sql.DB, climb kayaClimbForMatching, minConfidence float64, thresholds kayamatch.Thresholds) ([]routeMatchForSync, int, error) {
 	query := `
 		SELECT
 			r.mp_route_id,
@@ -529,7 +727,7 @@ func findMPMatchesForSync(ctx context.Context, db *sql.DB, climb kayaClimbForMat
 			continue
 		}
 
-		confidence := calculateMatchConfidence(nameSim, locationMatch, distKM)
+		confidence := thresholds.Confidence(nameSim, locationMatch, distKM)
 		if confidence < minConfidence {
 			continue
 		}
@@ -542,7 +740,7 @@ func findMPMatchesForSync(ctx context.Context, db *sql.DB, climb kayaClimbForMat
 			MPRouteName:       mpName,
 			MPAreaName:        mpArea,
 			Confidence:        confidence,
-			MatchType:         determineMatchType(nameSim, locationMatch, distKM),
+			MatchType:         thresholds.MatchType(nameSim, locationMatch, distKM),
 			NameSimilarity:    nameSim,
 			DistanceKM:        distKM,
 			LocationNameMatch: locationMatch,
@@ -556,20 +754,28 @@ func findMPMatchesForSync(ctx context.Context, db *sql.DB, climb kayaClimbForMat
 	return matches, rejected, nil
 }
 
-func saveRouteMatch(ctx context.Context, db *sql.DB, match routeMatchForSync) error {
+// saveRouteMatch upserts a match with the given review status. Matches a
+// reviewer has verified or rejected keep their existing status.
+func saveRouteMatch(ctx context.Context, db *sql.DB, match routeMatchForSync, status string) error {
 	query := `
 		INSERT INTO kaya_mp_route_matches (
 			kaya_climb_id, mp_route_id, match_confidence, match_type,
 			kaya_climb_name, kaya_location_name,
 			mp_route_name, mp_area_name,
-			name_similarity, location_name_match, location_distance_km
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			name_similarity, location_name_match, location_distance_km,
+			match_status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		ON CONFLICT (kaya_climb_id, mp_route_id) DO UPDATE SET
 			match_confidence = EXCLUDED.match_confidence,
 			match_type = EXCLUDED.match_type,
 			name_similarity = EXCLUDED.name_similarity,
 			location_name_match = EXCLUDED.location_name_match,
 			location_distance_km = EXCLUDED.location_distance_km,
+			match_status = CASE
+				WHEN kaya_mp_route_matches.is_verified OR kaya_mp_route_matches.match_status = 'rejected'
+					THEN kaya_mp_route_matches.match_status
+				ELSE EXCLUDED.match_status
+			END,
 			updated_at = CURRENT_TIMESTAMP
 	`
 
@@ -585,6 +791,7 @@ func saveRouteMatch(ctx context.Context, db *sql.DB, match routeMatchForSync) er
 		match.NameSimilarity,
 		match.LocationNameMatch,
 		match.DistanceKM,
+		status,
 	)
 	return err
 }
@@ -604,7 +811,7 @@ func calculateNameSimilarity(name1, name2 string) float64 {
 }
 
 func normalizeRouteName(name string) string {
-	name = strings.ToLower(name)
+	name = kayamatch.FoldDiacritics(strings.ToLower(name))
 	name = strings.TrimPrefix(name, "the ")
 	name = strings.TrimPrefix(name, "a ")
 	name = strings.Map(func(r rune) rune {
@@ -658,39 +865,6 @@ func calculateGPSDistance(lat1, lon1, lat2, lon2 float64) float64 {
 	return earthRadiusKm * c
 }
 
-func calculateMatchConfidence(nameSim float64, locationMatch bool, distanceKM *float64) float64 {
-	confidence := nameSim * 0.7
-	if locationMatch {
-		confidence += 0.2
-	}
-	if distanceKM != nil && *distanceKM < 5.0 {
-		confidence += 0.1 * (1.0 - (*distanceKM / 5.0))
-	}
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-	return confidence
-}
-
-func determineMatchType(nameSim float64, locationMatch bool, distanceKM *float64) string {
-	if nameSim == 1.0 {
-		return "exact_name"
-	}
-	if nameSim >= 0.9 && locationMatch {
-		return "fuzzy_name_location"
-	}
-	if nameSim >= 0.85 {
-		return "fuzzy_name"
-	}
-	if locationMatch && distanceKM != nil && *distanceKM < 1.0 {
-		return "location_gps_proximity"
-	}
-	if locationMatch {
-		return "location_name"
-	}
-	return "low_confidence"
-}
-
 func isCompatibleMatch(kayaClimbType, kayaGrade, mpRouteType, mpRating string) bool {
 	kayaDiscipline := classifyKayaDiscipline(kayaClimbType, kayaGrade)
 	mpDiscipline := classifyMPDiscipline(mpRouteType, mpRating)
@@ -818,25 +992,6 @@ func max(a, b int) int {
 	return b
 }
 
-func createMonitoringDB() (*sql.DB, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
-
-	if port == "" {
-		port = "5432"
-	}
-	if sslmode == "" {
-		sslmode = "require"
-	}
-
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode,
-	)
-
-	return sql.Open("postgres", connStr)
+func createMonitoringDB(cfg config.DatabaseConfig) (*sql.DB, error) {
+	return sql.Open("postgres", cfg.ConnectionString())
 }
@@ -1,94 +1,243 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	kayaClient "github.com/alexscott64/woulder/backend/internal/kaya"
+	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
-func TestShouldSyncKayaProgress(t *testing.T) {
+func TestShouldSyncLocation(t *testing.T) {
 	now := time.Date(2026, 6, 24, 12, 0, 0, 0, time.UTC)
 	recentLastSync := now.Add(-2 * time.Hour)
 	oldLastSync := now.Add(-26 * time.Hour)
-	futureNextSync := now.Add(2 * time.Hour)
-	pastNextSync := now.Add(-1 * time.Hour)
 
 	tests := []struct {
-		name     string
-		progress *kayaSyncStatus
-		want     bool
+		name         string
+		lastSyncedAt *time.Time
+		lookupErr    error
+		maxAge       time.Duration
+		want         bool
 	}{
 		{
-			name:     "syncs when no progress exists",
-			progress: nil,
-			want:     true,
-		},
-		{
-			name: "syncs incomplete progress",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "in_progress", Valid: true},
-				LastSyncAt: sql.NullTime{Time: recentLastSync, Valid: true},
-				NextSyncAt: sql.NullTime{Time: futureNextSync, Valid: true},
-			},
-			want: true,
+			name:   "syncs a location that never synced successfully",
+			maxAge: 24 * time.Hour,
+			want:   true,
 		},
 		{
-			name: "syncs failed progress",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "failed", Valid: true},
-				LastSyncAt: sql.NullTime{Time: recentLastSync, Valid: true},
-				NextSyncAt: sql.NullTime{Time: futureNextSync, Valid: true},
-			},
-			want: true,
+			name:         "skips a location synced within max age",
+			lastSyncedAt: &recentLastSync,
+			maxAge:       24 * time.Hour,
+			want:         false,
 		},
 		{
-			name: "syncs completed progress with no last sync",
-			progress: &kayaSyncStatus{
-				Status: sql.NullString{String: "completed", Valid: true},
-			},
-			want: true,
+			name:         "syncs a location synced before max age",
+			lastSyncedAt: &oldLastSync,
+			maxAge:       24 * time.Hour,
+			want:         true,
 		},
 		{
-			name: "skips completed progress before next sync",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "completed", Valid: true},
-				LastSyncAt: sql.NullTime{Time: recentLastSync, Valid: true},
-				NextSyncAt: sql.NullTime{Time: futureNextSync, Valid: true},
-			},
-			want: false,
+			name:         "shorter max age resyncs recent locations",
+			lastSyncedAt: &recentLastSync,
+			maxAge:       time.Hour,
+			want:         true,
 		},
 		{
-			name: "syncs completed progress after next sync",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "completed", Valid: true},
-				LastSyncAt: sql.NullTime{Time: recentLastSync, Valid: true},
-				NextSyncAt: sql.NullTime{Time: pastNextSync, Valid: true},
-			},
-			want: true,
+			name:         "longer max age skips older locations",
+			lastSyncedAt: &oldLastSync,
+			maxAge:       72 * time.Hour,
+			want:         false,
 		},
 		{
-			name: "skips completed progress within fallback 24 hour window",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "completed", Valid: true},
-				LastSyncAt: sql.NullTime{Time: recentLastSync, Valid: true},
-			},
-			want: false,
-		},
-		{
-			name: "syncs completed progress outside fallback 24 hour window",
-			progress: &kayaSyncStatus{
-				Status:     sql.NullString{String: "completed", Valid: true},
-				LastSyncAt: sql.NullTime{Time: oldLastSync, Valid: true},
-			},
-			want: true,
+			name:         "syncs when the lookup fails",
+			lastSyncedAt: &recentLastSync,
+			lookupErr:    errors.New("connection refused"),
+			maxAge:       24 * time.Hour,
+			want:         true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := shouldSyncKayaProgress(tt.progress, now); got != tt.want {
-				t.Fatalf("shouldSyncKayaProgress() = %v, want %v", got, tt.want)
+			var gotSlug string
+			getLastSyncedAt := func(ctx context.Context, slug string) (*time.Time, error) {
+				gotSlug = slug
+				if tt.lookupErr != nil {
+					return nil, tt.lookupErr
+				}
+				return tt.lastSyncedAt, nil
+			}
+
+			got, err := shouldSyncLocation(context.Background(), getLastSyncedAt, "Gold-Bar-344983", tt.maxAge, now)
+			if !errors.Is(err, tt.lookupErr) {
+				t.Fatalf("shouldSyncLocation() error = %v, want %v", err, tt.lookupErr)
+			}
+			if got != tt.want {
+				t.Fatalf("shouldSyncLocation() = %v, want %v", got, tt.want)
+			}
+			if gotSlug != "Gold-Bar-344983" {
+				t.Errorf("looked up slug %q, want Gold-Bar-344983", gotSlug)
 			}
 		})
 	}
 }
+
+func TestQueueTargets(t *testing.T) {
+	due := []*models.KayaSyncProgress{
+		{KayaLocationID: "1", LocationName: "Gold Bar", Status: "completed"},
+		{KayaLocationID: "2", LocationName: "Index", Status: "failed"},
+		{KayaLocationID: "3", LocationName: "Deleted", Status: "pending"},
+	}
+	getLocation := func(ctx context.Context, id string) (*models.KayaLocation, error) {
+		switch id {
+		case "1":
+			return &models.KayaLocation{KayaLocationID: id, Slug: "Gold-Bar-344983"}, nil
+		case "2":
+			return nil, errors.New("connection reset")
+		default:
+			return nil, nil
+		}
+	}
+
+	targets := queueTargets(context.Background(), due, getLocation)
+
+	if len(targets) != len(due) {
+		t.Fatalf("got %d targets, want %d", len(targets), len(due))
+	}
+	wantSlugs := []string{"Gold-Bar-344983", "", ""}
+	wantLabels := []string{"Gold-Bar-344983", "Index", "Deleted"}
+	for i, target := range targets {
+		if target.Progress != due[i] {
+			t.Errorf("target %d: progress not carried through", i)
+		}
+		if target.Slug != wantSlugs[i] {
+			t.Errorf("target %d: slug = %q, want %q", i, target.Slug, wantSlugs[i])
+		}
+		if got := target.label(); got != wantLabels[i] {
+			t.Errorf("target %d: label = %q, want %q", i, got, wantLabels[i])
+		}
+	}
+}
+
+// TestFallbackDestinationsMatchFile keeps the built-in fallback list in step
+// with docs/kaya-destinations.txt, the shared source of truth.
+func TestRunPool_RunsEachIndexOnceWithinLimit(t *testing.T) {
+	const workers, n = 3, 20
+
+	var mu sync.Mutex
+	seen := make(map[int]int)
+	running, peak := 0, 0
+	err := runPool(workers, n, func(worker, i int) error {
+		if worker < 0 || worker >= workers {
+			t.Errorf("worker = %d, want 0-%d", worker, workers-1)
+		}
+		mu.Lock()
+		seen[i]++
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("runPool() error = %v", err)
+	}
+
+	for i := range n {
+		if seen[i] != 1 {
+			t.Errorf("index %d ran %d times, want 1", i, seen[i])
+		}
+	}
+	if peak > workers {
+		t.Errorf("peak concurrency = %d, want at most %d", peak, workers)
+	}
+}
+
+func TestRunPool_StopsStartingAfterError(t *testing.T) {
+	errStop := errors.New("stop")
+
+	var started atomic.Int32
+	err := runPool(1, 10, func(worker, i int) error {
+		started.Add(1)
+		if i == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("runPool() error = %v, want %v", err, errStop)
+	}
+	if got := started.Load(); got != 4 {
+		t.Errorf("started = %d, want 4 (nothing after the failing index)", got)
+	}
+}
+
+func TestRunPool_NoItems(t *testing.T) {
+	if err := runPool(4, 0, func(worker, i int) error {
+		t.Errorf("work called for index %d with no items", i)
+		return nil
+	}); err != nil {
+		t.Errorf("runPool() error = %v", err)
+	}
+}
+
+func TestFallbackDestinationsMatchFile(t *testing.T) {
+	slugs, err := kayaClient.LoadDestinationSlugs("../../../docs/kaya-destinations.txt")
+	if err != nil {
+		t.Fatalf("failed to load destinations file: %v", err)
+	}
+	if !reflect.DeepEqual(slugs, fallbackDestinations) {
+		t.Errorf("fallbackDestinations (%d) differs from docs/kaya-destinations.txt (%d); update the fallback to match the file",
+			len(fallbackDestinations), len(slugs))
+	}
+}
+
+func TestLoadDestinations(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("reads the file", func(t *testing.T) {
+		path := filepath.Join(dir, "destinations.txt")
+		if err := os.WriteFile(path, []byte("# test\nIndex-345070\nGold-Bar-344983\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"Index-345070", "Gold-Bar-344983"}
+		if got := loadDestinations(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("loadDestinations() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("falls back when the file is missing", func(t *testing.T) {
+		if got := loadDestinations(filepath.Join(dir, "missing.txt")); len(got) != len(fallbackDestinations) {
+			t.Errorf("loadDestinations() returned %d slugs, want the %d built-in ones", len(got), len(fallbackDestinations))
+		}
+	})
+
+	t.Run("falls back when the file is empty", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.txt")
+		if err := os.WriteFile(path, []byte("# nothing yet\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := loadDestinations(path); len(got) != len(fallbackDestinations) {
+			t.Errorf("loadDestinations() returned %d slugs, want the %d built-in ones", len(got), len(fallbackDestinations))
+		}
+	})
+}
+
+func TestNormalizeRouteName_FoldsDiacritics(t *testing.T) {
+	if got := normalizeRouteName("L’Arête du Café"); got != "larete du cafe" {
+		t.Errorf("normalizeRouteName() = %q, want %q", got, "larete du cafe")
+	}
+}
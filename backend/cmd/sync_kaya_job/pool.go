@@ -0,0 +1,50 @@
+package main
+
+import "sync"
+
+// runPool calls work for each index in [0, n), in order, on up to workers
+// goroutines. worker (0 to workers-1) identifies the goroutine making the
+// call, so each one can use its own resources. Once work returns an error no
+// further indexes are started; the first error is returned after the calls
+// already running have finished.
+func runPool(workers, n int, work func(worker, i int) error) error {
+	workers = max(1, min(workers, n))
+
+	var (
+		mu       sync.Mutex
+		next     int
+		firstErr error
+	)
+	take := func() (int, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr != nil || next >= n {
+			return 0, false
+		}
+		next++
+		return next - 1, true
+	}
+
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i, ok := take()
+				if !ok {
+					return
+				}
+				if err := work(worker, i); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
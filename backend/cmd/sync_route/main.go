@@ -0,0 +1,66 @@
+// Command sync_route re-syncs the ticks and comments for a single Mountain
+// Project route, without traversing its area. Useful for spot-fixing one
+// route's data. The route must already exist in the database.
+//
+// Usage:
+//
+//	go run ./cmd/sync_route -id 105717310
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/service"
+	"github.com/alexscott64/woulder/backend/internal/syncretry"
+)
+
+func main() {
+	routeID := flag.Int64("id", 0, "Mountain Project route ID to sync")
+	retryOpts := syncretry.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	if *routeID <= 0 {
+		log.Fatal("-id is required and must be a positive Mountain Project route ID")
+	}
+	if err := retryOpts.Validate(); err != nil {
+		log.Fatalf("Invalid retry flags: %v", err)
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// No job monitor for manual sync, and no request budget: one route is
+	// only a handful of requests
+	climbService := service.NewClimbTrackingService(db.MountainProject(), db.Climbing(), mountainproject.NewClient(), nil, nil)
+
+	start := time.Now()
+	ctx := context.Background()
+	err = retryOpts.Do(ctx, fmt.Sprintf("route %d", *routeID), func() error {
+		return climbService.SyncSingleRoute(ctx, *routeID)
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrRouteNotFound) {
+			log.Fatalf("Route %d is not in the database; sync its area first (go run ./cmd/sync_climbs)", *routeID)
+		}
+		log.Fatalf("Sync failed: %v", err)
+	}
+
+	log.Printf("Route %d synced in %s", *routeID, time.Since(start).Round(time.Millisecond))
+}
@@ -9,8 +9,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
@@ -25,32 +25,30 @@ type Route struct {
 func main() {
 	// Parse command-line flags
 	force := flag.Bool("force", false, "Force re-sync even if tree coverage already exists")
+	locationID := flag.Int("location-id", 0, "Restrict to routes in a single location_id (0 = all locations)")
+	areaID := flag.Int64("area-id", 0, "Restrict to routes in a Mountain Project area and its subareas (0 = all areas)")
 	flag.Parse()
 
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Printf("Warning: .env file not found: %v", err)
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	log.Println("=== Boulder Tree Coverage Sync Tool ===")
 	log.Println("This tool fetches and stores tree coverage data for all boulders with GPS coordinates")
+	if *locationID != 0 {
+		log.Printf("Restricting to location_id=%d", *locationID)
+	}
+	if *areaID != 0 {
+		log.Printf("Restricting to mp_area_id=%d and its subareas", *areaID)
+	}
 	if *force {
 		log.Println("FORCE MODE: Will re-sync all routes regardless of existing data")
 	}
 	log.Println()
 
-	// Connect to database
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("DB_HOST"),
-		getEnvOrDefault("DB_PORT", "5432"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		getEnvOrDefault("DB_SSLMODE", "require"),
-	)
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -72,14 +70,18 @@ func main() {
 	// Get all routes with GPS coordinates
 	log.Println()
 	log.Println("Fetching routes with GPS coordinates...")
-	routes, err := getRoutesWithGPS(db)
+	routes, err := getRoutesWithGPS(db, *locationID, *areaID)
 	if err != nil {
 		log.Fatalf("Failed to fetch routes: %v", err)
 	}
 
 	log.Printf("Found %d routes with GPS coordinates", len(routes))
 	if len(routes) == 0 {
-		log.Println("No routes found. Make sure Mountain Project sync has run with GPS distribution.")
+		if *locationID != 0 || *areaID != 0 {
+			log.Println("No routes found for the given filters. Check the IDs and that the area has been synced.")
+		} else {
+			log.Println("No routes found. Make sure Mountain Project sync has run with GPS distribution.")
+		}
 		return
 	}
 
@@ -167,16 +169,32 @@ func main() {
 	}
 }
 
-func getRoutesWithGPS(db *sql.DB) ([]Route, error) {
+// getRoutesWithGPS returns routes with GPS coordinates, optionally restricted
+// to a location and/or a Mountain Project area and its subareas. A zero ID
+// disables that filter.
+func getRoutesWithGPS(db *sql.DB, locationID int, areaID int64) ([]Route, error) {
 	query := `
-		SELECT mp_route_id, name, latitude, longitude, location_id
+		WITH RECURSIVE area_tree AS (
+			SELECT mp_area_id
+			FROM woulder.mp_areas
+			WHERE mp_area_id = $2
+
+			UNION ALL
+
+			SELECT a.mp_area_id
+			FROM woulder.mp_areas a
+			INNER JOIN area_tree at ON a.parent_mp_area_id = at.mp_area_id
+		)
+		SELECT mp_route_id, name, latitude, longitude, COALESCE(location_id, 0)
 		FROM woulder.mp_routes
 		WHERE latitude IS NOT NULL
 		  AND longitude IS NOT NULL
+		  AND ($1 = 0 OR location_id = $1)
+		  AND ($2 = 0 OR mp_area_id IN (SELECT mp_area_id FROM area_tree))
 		ORDER BY location_id, name
 	`
 
-	rows, err := db.Query(query)
+	rows, err := db.Query(query, locationID, areaID)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -193,10 +211,3 @@ func getRoutesWithGPS(db *sql.DB) ([]Route, error) {
 
 	return routes, rows.Err()
 }
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
@@ -25,9 +25,9 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	weatherRepo "github.com/alexscott64/woulder/backend/internal/database/weather"
 	"github.com/alexscott64/woulder/backend/internal/weather/client"
 )
@@ -65,11 +65,10 @@ func main() {
 		log.Fatal("Error: must pass either --all or --location-id <ID>")
 	}
 
-	// Load .env (try cwd then parent), mirroring sibling commands.
-	if err := godotenv.Load(); err != nil {
-		if err2 := godotenv.Load("../.env"); err2 != nil {
-			log.Printf("Warning: .env file not found: %v", err)
-		}
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
 	log.Println("=== Weather Sync Tool ===")
@@ -84,18 +83,7 @@ func main() {
 	log.Printf("Rate limit: %dms between Open-Meteo calls", *rateLimitMs)
 	log.Println()
 
-	// DB connection (same env-driven pattern as cmd/backfill_radiation_dewpoint).
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		os.Getenv("DB_HOST"),
-		getEnvOrDefault("DB_PORT", "5432"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		getEnvOrDefault("DB_SSLMODE", "require"),
-	)
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := sql.Open("postgres", cfg.Database.ConnectionString())
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -187,7 +175,7 @@ func main() {
 // and persists every row via the standard weather repository. Returns the count
 // of rows saved (current + each hourly forecast).
 func syncLocation(ctx context.Context, repo *weatherRepo.PostgresRepository, om *client.OpenMeteoClient, loc LocationInfo) (int, error) {
-	current, forecast, _, err := om.GetCurrentAndForecast(loc.Latitude, loc.Longitude)
+	current, forecast, _, err := om.GetCurrentAndForecast(loc.Latitude, loc.Longitude, client.DefaultForecastDays, 0)
 	if err != nil {
 		return 0, fmt.Errorf("open-meteo: %w", err)
 	}
@@ -268,10 +256,3 @@ func loadLocations(db *sql.DB, all bool, onlyID int) ([]LocationInfo, error) {
 	}
 	return out, rows.Err()
 }
-
-func getEnvOrDefault(key, defaultValue string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return defaultValue
-}
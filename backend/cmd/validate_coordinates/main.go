@@ -0,0 +1,146 @@
+// Command validate_coordinates finds Mountain Project areas and routes whose
+// stored GPS coordinates are implausible and, with -fix, clears them.
+//
+// Upstream data occasionally carries coordinates that break the map and
+// distance math. This tool reports:
+//
+//   - out_of_range: latitude or longitude outside the valid range
+//   - null_island: at (0, 0), which upstream sends for a missing position
+//   - far_from_area: more than -max-distance-km from the route's area, or
+//     from the area's parent (root parents such as states are not used)
+//
+// By default it only reports. With -fix, in a single transaction, it nulls
+// latitude and longitude on every reported area and route, so they drop off
+// the map and out of distance queries instead of showing in the wrong place.
+// The next sync fills them in again if Mountain Project sends usable values;
+// sync rejects the implausible ones (see geo.IsPlausibleCoord).
+//
+// Usage:
+//
+//	go run ./cmd/validate_coordinates
+//	go run ./cmd/validate_coordinates -max-distance-km 50
+//	go run ./cmd/validate_coordinates -fix
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/alexscott64/woulder/backend/internal/config"
+	"github.com/alexscott64/woulder/backend/internal/database"
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+)
+
+var problemKinds = []string{
+	mountainproject.CoordProblemOutOfRange,
+	mountainproject.CoordProblemNullIsland,
+	mountainproject.CoordProblemFarFromArea,
+}
+
+func main() {
+	fix := flag.Bool("fix", false, "Clear the reported coordinates (default is a dry run)")
+	maxDistanceKm := flag.Float64("max-distance-km", 100, "Flag coordinates farther than this from their area")
+	sampleSize := flag.Int("sample", 20, "Number of rows to print per problem")
+	flag.Parse()
+
+	if *maxDistanceKm <= 0 {
+		log.Fatal("Error: -max-distance-km must be positive")
+	}
+
+	// Load configuration (also reads .env)
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	log.Println("=== Mountain Project Coordinate Check ===")
+	if !*fix {
+		log.Println("DRY RUN MODE: no rows will be modified (pass -fix to clear coordinates)")
+	}
+	log.Println()
+
+	ctx := context.Background()
+
+	db, err := database.New(cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	mp := db.MountainProject()
+	areaProblems, err := mp.Areas().GetAreaCoordinateProblems(ctx, *maxDistanceKm)
+	if err != nil {
+		log.Fatalf("Failed to check area coordinates: %v", err)
+	}
+	routeProblems, err := mp.Routes().GetRouteCoordinateProblems(ctx, *maxDistanceKm)
+	if err != nil {
+		log.Fatalf("Failed to check route coordinates: %v", err)
+	}
+
+	report("area", areaProblems, *sampleSize)
+	report("route", routeProblems, *sampleSize)
+
+	if len(areaProblems) == 0 && len(routeProblems) == 0 {
+		log.Println("No implausible coordinates found")
+		return
+	}
+	if !*fix {
+		return
+	}
+
+	var clearedAreas, clearedRoutes int64
+	err = database.WithTransaction(ctx, db.Conn(), func(tx *sql.Tx) error {
+		repo := mountainproject.NewPostgresRepository(tx)
+
+		var err error
+		clearedAreas, err = repo.Areas().ClearAreaCoordinates(ctx, problemIDs(areaProblems))
+		if err != nil {
+			return fmt.Errorf("clear area coordinates: %w", err)
+		}
+		clearedRoutes, err = repo.Routes().ClearRouteCoordinates(ctx, problemIDs(routeProblems))
+		if err != nil {
+			return fmt.Errorf("clear route coordinates: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("Fix failed, no changes were made: %v", err)
+	}
+
+	log.Println()
+	log.Printf("Cleared coordinates on %d area(s) and %d route(s)", clearedAreas, clearedRoutes)
+}
+
+// report logs how many areas or routes have each problem and a sample of them.
+func report(kind string, problems []mountainproject.CoordinateProblem, sampleSize int) {
+	byProblem := make(map[string][]mountainproject.CoordinateProblem)
+	for _, p := range problems {
+		byProblem[p.Problem] = append(byProblem[p.Problem], p)
+	}
+
+	for _, problem := range problemKinds {
+		log.Printf("%s %s: %d", kind, problem, len(byProblem[problem]))
+		for i, p := range byProblem[problem] {
+			if i == sampleSize {
+				log.Printf("  ... and %d more", len(byProblem[problem])-sampleSize)
+				break
+			}
+			line := fmt.Sprintf("  %s %d %q: %.5f, %.5f", kind, p.ID, p.Name, p.Latitude, p.Longitude)
+			if p.DistanceKm != nil && p.ReferenceAreaID != nil {
+				line += fmt.Sprintf(" (%.0f km from area %d)", *p.DistanceKm, *p.ReferenceAreaID)
+			}
+			log.Print(line)
+		}
+	}
+}
+
+func problemIDs(problems []mountainproject.CoordinateProblem) []int64 {
+	ids := make([]int64, 0, len(problems))
+	for _, p := range problems {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
@@ -7,9 +7,14 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/buildinfo"
+	"github.com/alexscott64/woulder/backend/internal/database/dberrors"
 	"github.com/alexscott64/woulder/backend/internal/database/kaya"
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/alexscott64/woulder/backend/internal/monitoring"
 	"github.com/alexscott64/woulder/backend/internal/service"
+	"github.com/alexscott64/woulder/backend/internal/weather/client"
 	"github.com/gin-gonic/gin"
 )
 
@@ -25,6 +30,12 @@ type Handler struct {
 	moneyService         *service.MoneyService
 	kayaRepo             kaya.Repository
 	jobMonitor           *monitoring.JobMonitor
+	schema               SchemaVersionReader
+}
+
+// SchemaVersionReader reads the database migration version for GetVersion.
+type SchemaVersionReader interface {
+	SchemaVersion(ctx context.Context) (int, error)
 }
 
 func NewHandler(
@@ -39,6 +50,7 @@ func NewHandler(
 	moneyService *service.MoneyService,
 	kayaRepo kaya.Repository,
 	jobMonitor *monitoring.JobMonitor,
+	schema SchemaVersionReader,
 ) *Handler {
 	return &Handler{
 		locationService:      locationService,
@@ -52,6 +64,7 @@ func NewHandler(
 		moneyService:         moneyService,
 		kayaRepo:             kayaRepo,
 		jobMonitor:           jobMonitor,
+		schema:               schema,
 	}
 }
 
@@ -206,8 +219,10 @@ func (h *Handler) runLocationRouteSync() {
 	ctx := context.Background()
 
 	// Sync ticks for location routes
-	if err := h.climbTrackingService.SyncLocationRouteTicks(ctx); err != nil {
-		log.Printf("Error in location route tick sync: %v", err)
+	if result, err := h.climbTrackingService.SyncLocationRouteTicks(ctx); err != nil {
+		log.Printf("Error in location route tick sync (%v): %v", result, err)
+	} else {
+		log.Printf("Location route tick sync: %v", result)
 	}
 
 	// Sync comments for location routes
@@ -257,6 +272,59 @@ func (h *Handler) runLocationAreaDiscovery() {
 	}
 }
 
+// StartTreeCoverRefresh starts a scheduler that re-fetches tree coverage for
+// boulder drying profiles older than opts.MaxAge, so shade estimates follow
+// wildfires and logging. Like StartLocationAreaDiscovery it does NOT run
+// immediately on startup; the first run is at +interval.
+func (h *Handler) StartTreeCoverRefresh(interval time.Duration, opts service.TreeCoverRefreshOptions) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		log.Printf("Starting tree cover refresh scheduler (every %v, first run at +%v)", interval, interval)
+
+		for range ticker.C {
+			h.runTreeCoverRefresh(opts)
+		}
+	}()
+}
+
+func (h *Handler) runTreeCoverRefresh(opts service.TreeCoverRefreshOptions) {
+	log.Println("Starting tree cover refresh...")
+	ctx := context.Background()
+	if _, err := h.boulderDryingService.RefreshStaleTreeCoverage(ctx, opts); err != nil {
+		log.Printf("Error in tree cover refresh: %v", err)
+	}
+}
+
+// StartJobHistoryPrune starts a scheduler that deletes finished job
+// executions older than retention, so the monitoring table doesn't grow
+// forever. It also runs immediately, so servers restarted more often than
+// interval still prune.
+func (h *Handler) StartJobHistoryPrune(interval, retention time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		log.Printf("Starting job history prune (every %v, keeping %v)", interval, retention)
+
+		h.runJobHistoryPrune(retention)
+		for range ticker.C {
+			h.runJobHistoryPrune(retention)
+		}
+	}()
+}
+
+func (h *Handler) runJobHistoryPrune(retention time.Duration) {
+	ctx := context.Background()
+	deleted, err := h.jobMonitor.DeleteOlderThan(ctx, time.Now().Add(-retention))
+	if err != nil {
+		log.Printf("Error pruning job history: %v", err)
+		return
+	}
+	log.Printf("Pruned %d job executions older than %v", deleted, retention)
+}
+
 // StartHighPrioritySync starts a background job that syncs high-priority non-location routes daily
 func (h *Handler) StartHighPrioritySync(interval time.Duration) {
 	go func() {
@@ -283,12 +351,14 @@ func (h *Handler) runHighPrioritySync() {
 	ctx := context.Background()
 
 	// Sync ticks for high-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncTicksByPriority(ctx, "high"); err != nil {
-		log.Printf("Error in high-priority tick sync: %v", err)
+	if result, err := h.climbTrackingService.SyncTicksByPriority(ctx, mountainproject.PriorityHigh); err != nil {
+		log.Printf("Error in high-priority tick sync (%v): %v", result, err)
+	} else {
+		log.Printf("High-priority tick sync: %v", result)
 	}
 
 	// Sync comments for high-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, "high"); err != nil {
+	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, mountainproject.PriorityHigh); err != nil {
 		log.Printf("Error in high-priority comment sync: %v", err)
 	}
 
@@ -321,12 +391,14 @@ func (h *Handler) runMediumPrioritySync() {
 	ctx := context.Background()
 
 	// Sync ticks for medium-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncTicksByPriority(ctx, "medium"); err != nil {
-		log.Printf("Error in medium-priority tick sync: %v", err)
+	if result, err := h.climbTrackingService.SyncTicksByPriority(ctx, mountainproject.PriorityMedium); err != nil {
+		log.Printf("Error in medium-priority tick sync (%v): %v", result, err)
+	} else {
+		log.Printf("Medium-priority tick sync: %v", result)
 	}
 
 	// Sync comments for medium-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, "medium"); err != nil {
+	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, mountainproject.PriorityMedium); err != nil {
 		log.Printf("Error in medium-priority comment sync: %v", err)
 	}
 
@@ -359,18 +431,48 @@ func (h *Handler) runLowPrioritySync() {
 	ctx := context.Background()
 
 	// Sync ticks for low-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncTicksByPriority(ctx, "low"); err != nil {
-		log.Printf("Error in low-priority tick sync: %v", err)
+	if result, err := h.climbTrackingService.SyncTicksByPriority(ctx, mountainproject.PriorityLow); err != nil {
+		log.Printf("Error in low-priority tick sync (%v): %v", result, err)
+	} else {
+		log.Printf("Low-priority tick sync: %v", result)
 	}
 
 	// Sync comments for low-priority NON-LOCATION routes
-	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, "low"); err != nil {
+	if err := h.climbTrackingService.SyncCommentsByPriority(ctx, mountainproject.PriorityLow); err != nil {
 		log.Printf("Error in low-priority comment sync: %v", err)
 	}
 
 	log.Println("Low-priority sync complete")
 }
 
+// APIVersion is the version of the API's response formats. Bump it when a
+// response changes in a way clients such as job_monitor can't tolerate
+// (removed or retyped fields, new envelopes); added fields don't need it.
+const APIVersion = 1
+
+// GetVersion reports the API version, the running build and the database
+// migration version, so clients and deploy checks can detect a mismatch.
+// schema_version is null if it can't be read.
+// GET /api/version
+func (h *Handler) GetVersion(c *gin.Context) {
+	var schemaVersion *int
+	if version, err := h.schema.SchemaVersion(c.Request.Context()); err != nil {
+		log.Printf("Error reading schema version: %v", err)
+	} else {
+		schemaVersion = &version
+	}
+
+	build := buildinfo.Get()
+	c.JSON(http.StatusOK, gin.H{
+		"service":        "woulder-api",
+		"api_version":    APIVersion,
+		"commit":         build.Commit,
+		"build_time":     build.BuildTime,
+		"modified":       build.Modified,
+		"schema_version": schemaVersion,
+	})
+}
+
 // HealthCheck returns service health status
 func (h *Handler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
@@ -396,7 +498,55 @@ func (h *Handler) GetAllLocations(c *gin.Context) {
 	})
 }
 
+// GetLocation returns a single location with approach/access beta from its
+// matched Kaya destination, when one exists, and its upcoming dry streaks
+// GET /api/locations/:id
+func (h *Handler) GetLocation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	location, err := h.locationService.GetLocation(ctx, locationID)
+	if err != nil {
+		if dberrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+			return
+		}
+		log.Printf("Error fetching location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch location"})
+		return
+	}
+
+	// Access beta is optional; a Kaya lookup failure shouldn't fail the request
+	if h.kayaRepo != nil {
+		kayaLoc, err := h.kayaRepo.Locations().GetLocationForWoulderLocation(ctx, locationID)
+		if err != nil {
+			log.Printf("Error fetching Kaya access info for location %d: %v", locationID, err)
+		} else {
+			location.Access = service.BuildLocationAccessInfo(kayaLoc)
+		}
+	}
+
+	// So are the upcoming dry streaks, which come from the cached forecast
+	dryStreaks, err := h.weatherService.GetDryStreaks(ctx, locationID)
+	if err != nil {
+		log.Printf("Error computing dry streaks for location %d: %v", locationID, err)
+	} else {
+		location.DryStreaks = dryStreaks
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
 // GetWeatherForLocation returns complete weather forecast for a location
+// GET /api/weather/:id?forecast_days=16&past_days=7
+//
+// forecast_days (1-16) and past_days (0-92) are clamped to Open-Meteo's
+// limits; without either, the full cached window is returned.
 func (h *Handler) GetWeatherForLocation(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -406,7 +556,17 @@ func (h *Handler) GetWeatherForLocation(c *gin.Context) {
 		return
 	}
 
-	forecast, err := h.weatherService.GetLocationWeather(ctx, locationID)
+	forecastDays, pastDays, ok := parseWeatherDays(c)
+	if !ok {
+		return
+	}
+
+	var forecast *models.WeatherForecast
+	if c.Query("forecast_days") == "" && c.Query("past_days") == "" {
+		forecast, err = h.weatherService.GetLocationWeather(ctx, locationID)
+	} else {
+		forecast, err = h.weatherService.GetLocationWeatherForDays(ctx, locationID, forecastDays, pastDays)
+	}
 	if err != nil {
 		log.Printf("Error fetching weather for location %d: %v", locationID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch weather data"})
@@ -416,7 +576,209 @@ func (h *Handler) GetWeatherForLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, forecast)
 }
 
+// GetLocationNow returns a compact "is it climbable now" snapshot for a
+// location, intended for widgets and notifications that poll frequently.
+func (h *Handler) GetLocationNow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	now, err := h.weatherService.GetLocationNow(ctx, locationID)
+	if err != nil {
+		log.Printf("Error building now snapshot for location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current conditions"})
+		return
+	}
+
+	// Short client/CDN cache: the server already caches between weather refreshes.
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, now)
+}
+
+// GetLocationsNow returns the "is it climbable now" snapshot of every
+// location, best climbability score first. Optional area_id narrows it to an
+// area and climbable=true keeps only locations with a "go" verdict.
+func (h *Handler) GetLocationsNow(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var areaID *int
+	if areaIDStr := c.Query("area_id"); areaIDStr != "" {
+		parsedID, err := strconv.Atoi(areaIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area_id"})
+			return
+		}
+		areaID = &parsedID
+	}
+
+	climbableOnly := false
+	if v := c.Query("climbable"); v != "" {
+		var err error
+		climbableOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid climbable parameter"})
+			return
+		}
+	}
+
+	locations, err := h.weatherService.GetLocationsNow(ctx, areaID, climbableOnly)
+	if err != nil {
+		log.Printf("Error building now snapshots: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch current conditions"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, gin.H{
+		"locations": locations,
+		"count":     len(locations),
+	})
+}
+
+// GetRankedLocations returns locations best current climbability first,
+// from the cached conditions snapshots. Locations whose Kaya destination is
+// closed, or that are out of season (snowbound), are left out.
+// GET /api/locations/ranked?limit=10 (1-100)
+func (h *Handler) GetRankedLocations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = parsed
+	}
+
+	var closed map[int]bool
+	if h.kayaRepo != nil {
+		var err error
+		closed, err = h.kayaRepo.Locations().GetClosedWoulderLocationIDs(ctx)
+		if err != nil {
+			log.Printf("Error fetching closed locations: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rank locations"})
+			return
+		}
+	}
+
+	locations, err := h.weatherService.GetRankedLocations(ctx, closed, limit)
+	if err != nil {
+		log.Printf("Error ranking locations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rank locations"})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=60")
+	c.JSON(http.StatusOK, gin.H{
+		"locations": locations,
+		"count":     len(locations),
+	})
+}
+
+// GetLocationSunTimes returns daily sunrise/sunset for a location without
+// the rest of the forecast
+// GET /api/locations/:id/suntimes?days=1 (1-16)
+func (h *Handler) GetLocationSunTimes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	days := 1
+	if daysStr := c.Query("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > 16 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 16"})
+			return
+		}
+	}
+
+	sunTimes, err := h.weatherService.GetLocationSunTimes(ctx, locationID, days)
+	if err != nil {
+		log.Printf("Error fetching sun times for location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sun times"})
+		return
+	}
+
+	// Sun times only change daily; let clients and CDNs hold them for an hour.
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, sunTimes)
+}
+
+// GetConditionsHistory returns a location's daily weather paired with its
+// daily tick counts, for comparing activity with past conditions
+// GET /api/locations/:id/conditions-history?days=30 (1-365)
+func (h *Handler) GetConditionsHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 365"})
+			return
+		}
+	}
+
+	history, err := h.weatherService.GetConditionsHistory(ctx, locationID, days)
+	if err != nil {
+		if dberrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+			return
+		}
+		log.Printf("Error building conditions history for location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch conditions history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// parseWeatherDays reads the forecast_days and past_days query parameters,
+// defaulting to the full forecast and a week of history and clamping them to
+// Open-Meteo's limits. It writes a 400 response and returns false when either
+// is not a number.
+func parseWeatherDays(c *gin.Context) (forecastDays, pastDays int, ok bool) {
+	forecastDays, pastDays = client.DefaultForecastDays, client.DefaultPastDays
+	if s := c.Query("forecast_days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "forecast_days must be a number"})
+			return 0, 0, false
+		}
+		forecastDays = client.ClampForecastDays(days)
+	}
+	if s := c.Query("past_days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "past_days must be a number"})
+			return 0, 0, false
+		}
+		pastDays = client.ClampPastDays(days)
+	}
+	return forecastDays, pastDays, true
+}
+
 // GetWeatherByCoordinates returns weather for arbitrary coordinates
+// GET /api/weather/coordinates?lat=47.6&lon=-122.3&forecast_days=3&past_days=0
+//
+// forecast_days defaults to 16 and past_days to 0 (just the 12 hours of
+// spin-up at the start of hourly); both are clamped to Open-Meteo's limits.
 func (h *Handler) GetWeatherByCoordinates(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -440,7 +802,15 @@ func (h *Handler) GetWeatherByCoordinates(c *gin.Context) {
 		return
 	}
 
-	forecast, err := h.weatherService.GetWeatherByCoordinates(ctx, lat, lon)
+	forecastDays, pastDays, ok := parseWeatherDays(c)
+	if !ok {
+		return
+	}
+	if c.Query("past_days") == "" {
+		pastDays = 0
+	}
+
+	forecast, err := h.weatherService.GetWeatherByCoordinates(ctx, lat, lon, forecastDays, pastDays)
 	if err != nil {
 		log.Printf("Error fetching weather for coordinates (%.2f, %.2f): %v", lat, lon, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch weather data"})
@@ -561,6 +931,48 @@ func (h *Handler) GetRiverDataByID(c *gin.Context) {
 	c.JSON(http.StatusOK, riverData)
 }
 
+// GetNearbyLocations returns locations near a point, nearest first
+// GET /api/locations/nearby?lat=47.6&lon=-122.3&radius_km=50&limit=10
+func (h *Handler) GetNearbyLocations(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	lat, err := strconv.ParseFloat(c.Query("lat"), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid latitude"})
+		return
+	}
+
+	lon, err := strconv.ParseFloat(c.Query("lon"), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid longitude"})
+		return
+	}
+
+	radiusKm, err := strconv.ParseFloat(c.DefaultQuery("radius_km", "50"), 64)
+	if err != nil || radiusKm <= 0 || radiusKm > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid radius_km parameter (must be 0-500)"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be 1-100)"})
+		return
+	}
+
+	locations, err := h.locationService.GetNearbyLocations(ctx, lat, lon, radiusKm, limit)
+	if err != nil {
+		log.Printf("Error fetching locations near (%.4f, %.4f): %v", lat, lon, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch nearby locations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"locations": locations,
+		"count":     len(locations),
+	})
+}
+
 // GetAllAreas returns all climbing areas with location counts
 func (h *Handler) GetAllAreas(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -1,13 +1,18 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/database/dberrors"
 	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -157,6 +162,30 @@ func (h *Handler) GetSubareasOrderedByActivity(c *gin.Context) {
 	c.JSON(http.StatusOK, subareas)
 }
 
+// GetAreaTree returns a location's full area/subarea hierarchy with per-area
+// activity in one response, so clients don't need a request per level.
+// Activity is Mountain Project only; the per-level endpoints also merge Kaya.
+// GET /api/locations/:id/areas/tree
+func (h *Handler) GetAreaTree(c *gin.Context) {
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	tree, err := h.climbTrackingService.GetAreaTree(c.Request.Context(), locationID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve area tree"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"location_id": locationID,
+		"areas":       tree,
+		"count":       len(tree),
+	})
+}
+
 // GetRoutesOrderedByActivity retrieves routes in an area ordered by recent climb activity
 // GET /api/climbs/location/:id/areas/:area_id/routes?limit=50
 func (h *Handler) GetRoutesOrderedByActivity(c *gin.Context) {
@@ -277,6 +306,7 @@ func (h *Handler) GetUnifiedRoutesOrderedByActivity(c *gin.Context) {
 			MPRouteID:      &mpRouteID,
 			MPAreaID:       &mpAreaID,
 			MostRecentTick: route.MostRecentTick,
+			ConditionsBeta: route.ConditionsBeta,
 		}
 		if route.MostRecentTick != nil {
 			unified.AreaName = route.MostRecentTick.AreaName
@@ -343,15 +373,12 @@ func (h *Handler) GetUnifiedRoutesOrderedByActivity(c *gin.Context) {
 		}
 	}
 
-	// Sort all results by most recent activity
-	// Sort in place by LastClimbAt descending
-	for i := 0; i < len(unifiedRoutes); i++ {
-		for j := i + 1; j < len(unifiedRoutes); j++ {
-			if unifiedRoutes[j].LastClimbAt.After(unifiedRoutes[i].LastClimbAt) {
-				unifiedRoutes[i], unifiedRoutes[j] = unifiedRoutes[j], unifiedRoutes[i]
-			}
-		}
-	}
+	// Sort all results by most recent activity. The sort is stable so routes
+	// with equal recency keep the repository's deterministic order and
+	// paging does not show or skip duplicates.
+	sort.SliceStable(unifiedRoutes, func(i, j int) bool {
+		return unifiedRoutes[i].LastClimbAt.After(unifiedRoutes[j].LastClimbAt)
+	})
 
 	// Trim to limit
 	if len(unifiedRoutes) > limit {
@@ -361,6 +388,381 @@ func (h *Handler) GetUnifiedRoutesOrderedByActivity(c *gin.Context) {
 	c.JSON(http.StatusOK, unifiedRoutes)
 }
 
+// GetTrendingRoutes ranks routes by recent tick volume
+// GET /api/trending/routes?window=week|month&location_id=7&limit=20
+func (h *Handler) GetTrendingRoutes(c *gin.Context) {
+	window := c.DefaultQuery("window", service.TrendingWindowWeek)
+
+	// Parse optional location scope
+	var locationID *int
+	if locationIDStr := c.Query("location_id"); locationIDStr != "" {
+		parsed, err := strconv.Atoi(locationIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+			return
+		}
+		locationID = &parsed
+	}
+
+	// Parse optional limit query parameter (default 20, max 100)
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		if parsedLimit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be at least 1"})
+			return
+		}
+		if parsedLimit > 100 {
+			parsedLimit = 100
+		}
+		limit = parsedLimit
+	}
+
+	routes, err := h.climbTrackingService.GetTrendingRoutes(c.Request.Context(), window, locationID, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTrendingWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window must be 'week' or 'month'"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve trending routes"})
+		return
+	}
+
+	// Return empty array if no data found
+	if routes == nil {
+		routes = []models.TrendingRoute{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"window": window,
+		"routes": routes,
+		"count":  len(routes),
+	})
+}
+
+// GetNewRoutes returns routes recently found by the new-route sweep
+// GET /api/routes/new?days=7&limit=20
+func (h *Handler) GetNewRoutes(c *gin.Context) {
+	// Parse optional days query parameter (default 7, max 90)
+	days := 7
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsedDays, err := strconv.Atoi(daysStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter"})
+			return
+		}
+		if parsedDays < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Days must be at least 1"})
+			return
+		}
+		if parsedDays > 90 {
+			parsedDays = 90
+		}
+		days = parsedDays
+	}
+
+	// Parse optional limit query parameter (default 20, max 100)
+	limit := 20
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		if parsedLimit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be at least 1"})
+			return
+		}
+		if parsedLimit > 100 {
+			parsedLimit = 100
+		}
+		limit = parsedLimit
+	}
+
+	routes, err := h.climbTrackingService.GetRecentlyDiscoveredRoutes(c.Request.Context(), days, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve new routes"})
+		return
+	}
+
+	// Return empty array if no data found
+	if routes == nil {
+		routes = []models.DiscoveredRoute{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"days":   days,
+		"routes": routes,
+		"count":  len(routes),
+	})
+}
+
+// GetFeaturedRoute returns the route of the day, the same pick for every
+// request that (Pacific) day, with its current drying estimate
+// GET /api/routes/featured?location_id=7
+func (h *Handler) GetFeaturedRoute(c *gin.Context) {
+	// Parse optional location scope
+	var locationID *int
+	if locationIDStr := c.Query("location_id"); locationIDStr != "" {
+		parsed, err := strconv.Atoi(locationIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+			return
+		}
+		locationID = &parsed
+	}
+
+	featured, err := h.climbTrackingService.GetFeaturedRoute(c.Request.Context(), locationID, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrNoFeaturedRoute) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No featured route available"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve featured route"})
+		return
+	}
+
+	// Drying is best-effort: the pick is still useful without it
+	dryingStatus, err := h.boulderDryingService.GetBoulderDryingStatus(c.Request.Context(), featured.Route.MPRouteID)
+	if err != nil {
+		dryingStatus = nil
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"date":          featured.Date,
+		"location_id":   featured.LocationID,
+		"route":         featured.Route,
+		"recent_ticks":  featured.RecentTicks,
+		"drying_status": dryingStatus,
+	})
+}
+
+// GetSimilarRoutes recommends routes similar to a route: close in grade,
+// nearby, of a similar type and popularity
+// GET /api/routes/:id/similar?limit=10
+func (h *Handler) GetSimilarRoutes(c *gin.Context) {
+	routeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid route ID"})
+		return
+	}
+
+	// Parse optional limit query parameter (default 10, max 50)
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter"})
+			return
+		}
+		if parsedLimit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Limit must be at least 1"})
+			return
+		}
+		if parsedLimit > 50 {
+			parsedLimit = 50
+		}
+		limit = parsedLimit
+	}
+
+	routes, err := h.climbTrackingService.GetSimilarRoutes(c.Request.Context(), routeID, limit, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrRouteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Route not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve similar routes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"route_id": routeID,
+		"routes":   routes,
+		"count":    len(routes),
+	})
+}
+
+// GetRouteActivitySeries returns a route's tick counts per day or week over
+// recent days, zero-filled, for activity sparklines
+// GET /api/routes/:id/activity-series?bucket=day&days=90
+func (h *Handler) GetRouteActivitySeries(c *gin.Context) {
+	routeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid route ID"})
+		return
+	}
+
+	bucket, days, ok := parseActivitySeriesParams(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.climbTrackingService.GetRouteActivitySeries(c.Request.Context(), routeID, bucket, days, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrRouteNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Route not found"})
+			return
+		}
+		log.Printf("Error getting activity series for route %d: %v", routeID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve activity series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"route_id": routeID,
+		"bucket":   bucket,
+		"days":     days,
+		"series":   series,
+		"count":    len(series),
+	})
+}
+
+// GetAreaActivitySeries returns tick counts per day or week across an area
+// and its subareas over recent days, zero-filled, for activity sparklines
+// GET /api/areas/:id/activity-series?bucket=week&days=365
+func (h *Handler) GetAreaActivitySeries(c *gin.Context) {
+	areaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		return
+	}
+
+	bucket, days, ok := parseActivitySeriesParams(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.climbTrackingService.GetAreaActivitySeries(c.Request.Context(), areaID, bucket, days, time.Now())
+	if err != nil {
+		if errors.Is(err, service.ErrAreaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Area not found"})
+			return
+		}
+		log.Printf("Error getting activity series for area %d: %v", areaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve activity series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"area_id": areaID,
+		"bucket":  bucket,
+		"days":    days,
+		"series":  series,
+		"count":   len(series),
+	})
+}
+
+// parseActivitySeriesParams reads the bucket (default day) and days
+// (default 90, max 730) query parameters, writing a 400 response and
+// returning ok=false if either is invalid.
+func parseActivitySeriesParams(c *gin.Context) (bucket string, days int, ok bool) {
+	bucket = c.DefaultQuery("bucket", service.ActivityBucketDay)
+	if !service.IsValidActivityBucket(bucket) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bucket must be day or week"})
+		return "", 0, false
+	}
+
+	days = 90
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > 730 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 730"})
+			return "", 0, false
+		}
+		days = parsed
+	}
+
+	return bucket, days, true
+}
+
+// GetLocationGradeDistribution returns how many of a location's routes fall
+// in each grade band, optionally only routes of one type
+// GET /api/locations/:id/grade-distribution?route_type=Boulder
+func (h *Handler) GetLocationGradeDistribution(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	locationID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return
+	}
+
+	routeType, ok := parseRouteTypeFilter(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.locationService.GetLocation(ctx, locationID); err != nil {
+		if dberrors.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+			return
+		}
+		log.Printf("Error fetching location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve location"})
+		return
+	}
+
+	dist, err := h.climbTrackingService.GetLocationGradeDistribution(ctx, locationID, routeType)
+	if err != nil {
+		log.Printf("Error getting grade distribution for location %d: %v", locationID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve grade distribution"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"location_id":  locationID,
+		"distribution": dist,
+	})
+}
+
+// GetAreaGradeDistribution returns how many routes in an area and its
+// subareas fall in each grade band, optionally only routes of one type
+// GET /api/areas/:id/grade-distribution?route_type=Sport
+func (h *Handler) GetAreaGradeDistribution(c *gin.Context) {
+	areaID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid area ID"})
+		return
+	}
+
+	routeType, ok := parseRouteTypeFilter(c)
+	if !ok {
+		return
+	}
+
+	dist, err := h.climbTrackingService.GetAreaGradeDistribution(c.Request.Context(), areaID, routeType)
+	if err != nil {
+		if errors.Is(err, service.ErrAreaNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Area not found"})
+			return
+		}
+		log.Printf("Error getting grade distribution for area %d: %v", areaID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve grade distribution"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"area_id":      areaID,
+		"distribution": dist,
+	})
+}
+
+// parseRouteTypeFilter reads the optional route_type query parameter,
+// writing a 400 response and returning ok=false if it isn't a Mountain
+// Project route type.
+func parseRouteTypeFilter(c *gin.Context) (routeType string, ok bool) {
+	routeType, ok = service.NormalizeRouteTypeFilter(c.Query("route_type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "route_type must be one of Boulder, Sport, Trad, TR, Ice, Mixed, Alpine, Aid, Snow"})
+		return "", false
+	}
+	return routeType, true
+}
+
 // GetRecentTicksForRoute retrieves recent ticks for a specific route
 // GET /api/climbs/routes/:route_id/ticks?limit=5
 func (h *Handler) GetRecentTicksForRoute(c *gin.Context) {
@@ -566,18 +968,10 @@ func (h *Handler) GetBatchBoulderDryingStatus(c *gin.Context) {
 		return
 	}
 
-	// Split comma-separated route IDs and convert to int64
-	routeIDs := []int64{}
-	for _, id := range strings.Split(routeIDsStr, ",") {
-		trimmed := strings.TrimSpace(id)
-		if trimmed != "" {
-			routeID, err := strconv.ParseInt(trimmed, 10, 64)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid route ID: %s", trimmed)})
-				return
-			}
-			routeIDs = append(routeIDs, routeID)
-		}
+	routeIDs, err := parseRouteIDList(routeIDsStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
 	if len(routeIDs) == 0 {
@@ -719,3 +1113,82 @@ func (h *Handler) GetBatchAreaDryingStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// parseRouteIDList parses a comma-separated list of route IDs, ignoring
+// empty entries.
+func parseRouteIDList(s string) ([]int64, error) {
+	routeIDs := []int64{}
+	for _, id := range strings.Split(s, ",") {
+		trimmed := strings.TrimSpace(id)
+		if trimmed != "" {
+			routeID, err := strconv.ParseInt(trimmed, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid route ID: %s", trimmed)
+			}
+			routeIDs = append(routeIDs, routeID)
+		}
+	}
+	return routeIDs, nil
+}
+
+// SyncUserTicks imports a Mountain Project user's public tick list under the
+// username MP reports for the user. Requires authentication.
+// POST /api/climbs/user-ticks/sync
+// Body: {"mp_user_id": 200123456}
+func (h *Handler) SyncUserTicks(c *gin.Context) {
+	var req struct {
+		MPUserID int64 `json:"mp_user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	result, err := h.climbTrackingService.SyncUserTicks(c.Request.Context(), req.MPUserID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidMPUser) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to import ticks", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetUserTickedRoutes reports which of the given routes a user has ticked
+// GET /api/climbs/users/:username/ticked-routes?route_ids=1,2,3
+func (h *Handler) GetUserTickedRoutes(c *gin.Context) {
+	username := strings.TrimSpace(c.Param("username"))
+	if username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+
+	routeIDsStr := c.Query("route_ids")
+	if routeIDsStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "route_ids query parameter is required"})
+		return
+	}
+	routeIDs, err := parseRouteIDList(routeIDsStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(routeIDs) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Maximum 200 route IDs allowed per request"})
+		return
+	}
+
+	ticked, err := h.climbTrackingService.GetTickedRouteIDs(c.Request.Context(), username, routeIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve ticked routes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_name":        username,
+		"ticked_route_ids": ticked,
+	})
+}
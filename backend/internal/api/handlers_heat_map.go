@@ -12,7 +12,7 @@ import (
 )
 
 // GetHeatMapActivity returns aggregated climbing activity for the heat map
-// GET /api/heat-map/activity?start_date=2024-01-01&end_date=2024-12-31&min_lat=...&max_lat=...&min_lon=...&max_lon=...&min_activity=5&limit=500
+// GET /api/heat-map/activity?start_date=2024-01-01&end_date=2024-12-31&min_lat=...&max_lat=...&min_lon=...&max_lon=...&min_activity=5&limit=500&exclude_closed=true
 func (h *Handler) GetHeatMapActivity(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -107,8 +107,11 @@ func (h *Handler) GetHeatMapActivity(c *gin.Context) {
 	// Parse grade orders filter (comma-separated integer grade_order values)
 	gradeOrders := grades.ParseGradeOrders(c.Query("grade_orders"))
 
+	// Optionally leave out routes Kaya marks closed
+	excludeClosed := parseExcludeClosed(c)
+
 	// Fetch heat map data
-	points, err := h.heatMapService.GetHeatMapData(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders)
+	points, err := h.heatMapService.GetHeatMapData(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders, excludeClosed)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to fetch heat map data",
@@ -121,13 +124,14 @@ func (h *Handler) GetHeatMapActivity(c *gin.Context) {
 		"points": points,
 		"count":  len(points),
 		"filters": gin.H{
-			"start_date":   startDate.Format("2006-01-02"),
-			"end_date":     endDate.Format("2006-01-02"),
-			"min_activity": minActivity,
-			"limit":        limit,
-			"route_types":  routeTypes,
-			"lightweight":  lightweight,
-			"grade_orders": c.Query("grade_orders"),
+			"start_date":     startDate.Format("2006-01-02"),
+			"end_date":       endDate.Format("2006-01-02"),
+			"min_activity":   minActivity,
+			"limit":          limit,
+			"route_types":    routeTypes,
+			"lightweight":    lightweight,
+			"grade_orders":   c.Query("grade_orders"),
+			"exclude_closed": excludeClosed,
 		},
 	})
 }
@@ -210,7 +214,7 @@ func (h *Handler) GetHeatMapAreaDetail(c *gin.Context) {
 }
 
 // GetHeatMapRoutes returns routes within bounds with activity
-// GET /api/heat-map/routes?min_lat=...&max_lat=...&min_lon=...&max_lon=...&start_date=...&end_date=...&limit=100
+// GET /api/heat-map/routes?min_lat=...&max_lat=...&min_lon=...&max_lon=...&start_date=...&end_date=...&limit=100&exclude_closed=true
 func (h *Handler) GetHeatMapRoutes(c *gin.Context) {
 	ctx := c.Request.Context()
 
@@ -266,7 +270,7 @@ func (h *Handler) GetHeatMapRoutes(c *gin.Context) {
 	}
 
 	// Fetch routes
-	routes, err := h.heatMapService.GetRoutesByBounds(ctx, bounds, startDate, endDate, limit)
+	routes, err := h.heatMapService.GetRoutesByBounds(ctx, bounds, startDate, endDate, limit, parseExcludeClosed(c))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch routes",
@@ -427,3 +431,10 @@ func (h *Handler) GetRouteTicksInDateRange(c *gin.Context) {
 		"count": len(ticks),
 	})
 }
+
+// parseExcludeClosed reads the exclude_closed flag, which drops routes Kaya
+// marks closed (raptor closures, access disputes) from heat map feeds.
+func parseExcludeClosed(c *gin.Context) bool {
+	val := c.Query("exclude_closed")
+	return val == "true" || val == "1"
+}
@@ -42,6 +42,8 @@ type KayaClimbResponse struct {
 type KayaMatchResponse struct {
 	KayaClimbID    string   `json:"kaya_climb_id"`
 	KayaClimbName  string   `json:"kaya_climb_name"`
+	KayaGrade      *string  `json:"kaya_grade,omitempty"`
+	KayaAreaName   string   `json:"kaya_area_name,omitempty"`
 	MPRouteID      int64    `json:"mp_route_id"`
 	MPRouteName    string   `json:"mp_route_name"`
 	Confidence     float64  `json:"confidence"`
@@ -65,6 +67,25 @@ type KayaAscentResponse struct {
 	Source         string  `json:"source"` // "kaya" to distinguish from MP
 }
 
+// KayaPostResponse represents a Kaya post and its media for API responses
+type KayaPostResponse struct {
+	KayaPostID  string                 `json:"kaya_post_id"`
+	PostedBy    string                 `json:"posted_by,omitempty"`
+	DateCreated string                 `json:"date_created"` // ISO 8601 timestamp
+	Items       []KayaPostItemResponse `json:"items"`
+}
+
+// KayaPostItemResponse represents a photo or video within a Kaya post
+type KayaPostItemResponse struct {
+	KayaPostItemID    string  `json:"kaya_post_item_id"`
+	KayaClimbSlug     *string `json:"kaya_climb_slug,omitempty"`
+	ClimbName         *string `json:"climb_name,omitempty"`
+	PhotoURL          *string `json:"photo_url,omitempty"`
+	VideoURL          *string `json:"video_url,omitempty"`
+	VideoThumbnailURL *string `json:"video_thumbnail_url,omitempty"`
+	Caption           *string `json:"caption,omitempty"`
+}
+
 // GetKayaLocations returns a list of Kaya locations
 // GET /api/kaya/locations?limit=50&offset=0
 func (h *Handler) GetKayaLocations(c *gin.Context) {
@@ -190,6 +211,54 @@ func (h *Handler) GetKayaMPMatches(c *gin.Context) {
 	})
 }
 
+// GetKayaMatchesForRoute returns the approved Kaya climbs matched to an MP route
+// GET /api/routes/:id/kaya-matches?verified_only=true
+func (h *Handler) GetKayaMatchesForRoute(c *gin.Context) {
+	routeID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid route ID"})
+		return
+	}
+
+	verifiedOnly := false
+	if v := c.Query("verified_only"); v != "" {
+		verifiedOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid verified_only parameter"})
+			return
+		}
+	}
+
+	routeMatches, err := h.kayaRepo.Climbs().GetMatchedClimbsForRoute(c.Request.Context(), routeID, verifiedOnly)
+	if err != nil {
+		log.Printf("Error fetching Kaya matches for route %d: %v", routeID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve Kaya matches"})
+		return
+	}
+
+	matches := make([]KayaMatchResponse, 0, len(routeMatches))
+	for _, m := range routeMatches {
+		matches = append(matches, KayaMatchResponse{
+			KayaClimbID:    m.KayaClimbSlug,
+			KayaClimbName:  m.KayaClimbName,
+			KayaGrade:      m.ClimbGrade,
+			KayaAreaName:   m.AreaName,
+			MPRouteID:      m.MPRouteID,
+			MPRouteName:    m.MPRouteName,
+			Confidence:     m.Confidence,
+			MatchType:      m.MatchType,
+			NameSimilarity: m.NameSimilarity,
+			DistanceKM:     m.DistanceKM,
+			IsVerified:     m.IsVerified,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
+
 // GetKayaSyncStatus returns sync progress for all locations
 // GET /api/kaya/sync/status
 func (h *Handler) GetKayaSyncStatus(c *gin.Context) {
@@ -271,6 +340,59 @@ func (h *Handler) GetKayaAscentsForLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, ascents)
 }
 
+// GetRecentKayaPosts returns recent Kaya posts with their photos and videos
+// GET /api/kaya/posts/recent?limit=20&offset=0
+func (h *Handler) GetRecentKayaPosts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	recentPosts, err := h.kayaRepo.Posts().GetRecentPostsWithMedia(c.Request.Context(), limit, offset)
+	if err != nil {
+		log.Printf("Error fetching recent Kaya posts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve Kaya posts"})
+		return
+	}
+
+	posts := make([]KayaPostResponse, 0, len(recentPosts))
+	for _, p := range recentPosts {
+		post := KayaPostResponse{
+			KayaPostID:  p.KayaPostID,
+			DateCreated: p.DateCreated.Format(time.RFC3339),
+			Items:       make([]KayaPostItemResponse, 0, len(p.Items)),
+		}
+		if p.Username != nil {
+			post.PostedBy = *p.Username
+		}
+		for _, item := range p.Items {
+			post.Items = append(post.Items, KayaPostItemResponse{
+				KayaPostItemID:    item.KayaPostItemID,
+				KayaClimbSlug:     item.KayaClimbSlug,
+				ClimbName:         item.ClimbName,
+				PhotoURL:          item.PhotoURL,
+				VideoURL:          item.VideoURL,
+				VideoThumbnailURL: item.VideoThumbnailURL,
+				Caption:           item.Caption,
+			})
+		}
+		posts = append(posts, post)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":  posts,
+		"count":  len(posts),
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
 func strPtr(s string) *string {
 	return &s
 }
@@ -1,12 +1,17 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/monitoring"
+	"github.com/alexscott64/woulder/backend/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -34,6 +39,105 @@ type JobSummaryItem struct {
 	ErrorMessage    *string    `json:"error_message,omitempty"`
 }
 
+// SyncStatusResponse reports whether climb data syncs are running and when
+// each sync type last finished.
+type SyncStatusResponse struct {
+	IsSyncing     bool                           `json:"is_syncing"`
+	LastSyncTime  *time.Time                     `json:"last_sync_time,omitempty"`
+	RecentHours   int                            `json:"recent_hours"`
+	ActiveJobs    []*SyncStatusActiveJob         `json:"active_jobs"`
+	LastCompleted map[string]*SyncStatusFinished `json:"last_completed"`
+}
+
+// SyncStatusActiveJob is a running job plus the item it is currently processing
+type SyncStatusActiveJob struct {
+	*JobExecutionResponse
+	CurrentItem map[string]interface{} `json:"current_item,omitempty"`
+}
+
+// SyncStatusFinished is the latest finished run for a sync type
+type SyncStatusFinished struct {
+	JobID        int64      `json:"job_id"`
+	JobName      string     `json:"job_name"`
+	Status       string     `json:"status"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+}
+
+// GetSyncStatus returns whether a sync is running and when each sync type last finished
+// GET /api/sync/status?recent_hours=24
+func (h *Handler) GetSyncStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	recentHoursStr := c.DefaultQuery("recent_hours", "24")
+	recentHours, err := strconv.Atoi(recentHoursStr)
+	if err != nil || recentHours < 1 || recentHours > 720 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recent_hours parameter (must be 1-720)"})
+		return
+	}
+
+	response := &SyncStatusResponse{
+		RecentHours:   recentHours,
+		ActiveJobs:    []*SyncStatusActiveJob{},
+		LastCompleted: make(map[string]*SyncStatusFinished),
+	}
+
+	// In-process syncs (e.g. manual refresh) that may not have a job record
+	isSyncing, lastSync := h.climbTrackingService.GetSyncStatus()
+	response.IsSyncing = isSyncing
+	if !lastSync.IsZero() {
+		response.LastSyncTime = &lastSync
+	}
+
+	activeJobs, err := h.jobMonitor.GetActiveJobs(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get active jobs: %v", err)})
+		return
+	}
+	for _, job := range activeJobs {
+		response.ActiveJobs = append(response.ActiveJobs, &SyncStatusActiveJob{
+			JobExecutionResponse: enhanceJobExecution(job),
+			CurrentItem:          currentItemFromMetadata(job.Metadata),
+		})
+	}
+	if len(activeJobs) > 0 {
+		response.IsSyncing = true
+	}
+
+	finished, err := h.jobMonitor.GetLatestFinishedByJobType(ctx, time.Duration(recentHours)*time.Hour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get finished jobs: %v", err)})
+		return
+	}
+	for _, job := range finished {
+		response.LastCompleted[job.JobType] = &SyncStatusFinished{
+			JobID:        job.ID,
+			JobName:      job.JobName,
+			Status:       job.Status,
+			CompletedAt:  job.CompletedAt,
+			ErrorMessage: job.ErrorMessage,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// currentItemFromMetadata extracts the "current_*" keys written by
+// JobMonitor.UpdateCurrentItem. Returns nil when the job has not reported one.
+func currentItemFromMetadata(metadata map[string]interface{}) map[string]interface{} {
+	var item map[string]interface{}
+	for k, v := range metadata {
+		if !strings.HasPrefix(k, "current_") {
+			continue
+		}
+		if item == nil {
+			item = make(map[string]interface{})
+		}
+		item[k] = v
+	}
+	return item
+}
+
 // GetActiveJobs returns all currently running jobs
 // GET /api/monitoring/jobs/active
 func (h *Handler) GetActiveJobs(c *gin.Context) {
@@ -111,6 +215,71 @@ func (h *Handler) GetJobStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// CancelJob cancels a running or paused job execution. The job stops at its
+// next status check, so it may still process a few items after this returns.
+// POST /api/monitoring/jobs/:job_id/cancel
+func (h *Handler) CancelJob(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobIDStr := c.Param("job_id")
+	jobID, err := strconv.ParseInt(jobIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.jobMonitor.GetJobStatus(ctx, jobID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Job not found: %v", err)})
+		return
+	}
+
+	if err := h.jobMonitor.CancelJob(ctx, jobID); err != nil {
+		if errors.Is(err, monitoring.ErrJobNotActive) {
+			c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Job is %s, not running", job.Status)})
+			return
+		}
+		log.Printf("Error cancelling job %d: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel job"})
+		return
+	}
+
+	job, err = h.jobMonitor.GetJobStatus(ctx, jobID)
+	if err != nil {
+		log.Printf("Error reading cancelled job %d: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Job cancelled but failed to read its status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, enhanceJobExecution(job))
+}
+
+// PruneJobs deletes finished job executions older than ?days=N (at least 1).
+// Running and paused jobs are kept.
+// POST /api/monitoring/jobs/prune?days=N
+func (h *Handler) PruneJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	days, err := strconv.Atoi(c.Query("days"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a whole number of at least 1"})
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	deleted, err := h.jobMonitor.DeleteOlderThan(ctx, cutoff)
+	if err != nil {
+		log.Printf("Error pruning job history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to prune job history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deleted": deleted,
+		"cutoff":  cutoff,
+	})
+}
+
 // GetJobsSummary returns summary of all job types with latest status
 // GET /api/monitoring/jobs/summary
 func (h *Handler) GetJobsSummary(c *gin.Context) {
@@ -187,6 +356,59 @@ func (h *Handler) GetJobsSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, JobsSummaryResponse{Summary: summary})
 }
 
+// GetSyncTrends returns per-day sync throughput built from persisted sync summaries
+// GET /api/monitoring/trends?job=location_tick_sync&days=30
+func (h *Handler) GetSyncTrends(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	jobName := c.Query("job")
+	daysStr := c.DefaultQuery("days", "30")
+
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid days parameter (must be 1-365)"})
+		return
+	}
+
+	points, err := h.jobMonitor.GetSyncTrends(ctx, jobName, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to get sync trends: %v", err)})
+		return
+	}
+
+	if points == nil {
+		points = []*monitoring.SyncTrendPoint{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job":    jobName,
+		"days":   days,
+		"trends": points,
+	})
+}
+
+// RecalculatePriorities recomputes route sync priorities on demand as a
+// tracked job and returns the new priority distribution
+// POST /api/admin/priorities/recalculate
+func (h *Handler) RecalculatePriorities(c *gin.Context) {
+	// Let the recalculation finish even if the client disconnects, so the
+	// tracked job is never left half-done.
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	result, err := h.climbTrackingService.RunPriorityRecalculationJob(ctx)
+	if err != nil {
+		if errors.Is(err, service.ErrPriorityRecalculationRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Priority recalculation already running"})
+			return
+		}
+		log.Printf("Error recalculating priorities: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recalculate priorities"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // enhanceJobExecution adds calculated fields to job execution
 func enhanceJobExecution(job *monitoring.JobExecution) *JobExecutionResponse {
 	response := &JobExecutionResponse{
@@ -49,6 +49,19 @@ func RequireMoneyWrite() gin.HandlerFunc {
 	}
 }
 
+// RequireAdmin restricts a route to admins. Must run after Auth.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get(ContextUserRole)
+		if !models.CanRunMaintenance(roleString(role)) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func CurrentUser(c *gin.Context) models.CurrentUser {
 	return models.CurrentUser{ID: roleString(mustGet(c, ContextUserID)), Email: roleString(mustGet(c, ContextUserEmail)), Role: roleString(mustGet(c, ContextUserRole))}
 }
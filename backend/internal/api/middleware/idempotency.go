@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a trigger
+// endpoint safe to retry or double-submit.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyStore remembers responses by idempotency key for a short TTL.
+// It is in-memory only, so keys do not survive restarts and are not shared
+// between instances - enough to absorb double-clicks and flaky UI retries.
+type IdempotencyStore struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	done        bool
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// NewIdempotencyStore creates a store that keeps keys for ttl after the
+// original request finishes.
+func NewIdempotencyStore(ttl time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// Idempotency returns middleware that honours the Idempotency-Key header.
+// The first request with a key runs normally and its response is stored.
+// Repeats within the TTL get the stored response back (with an
+// Idempotent-Replayed: true header) instead of running the handler again;
+// repeats while the first is still running get 409 Conflict. Server errors
+// (5xx) are not stored, so a retry with the same key runs the handler again.
+// Requests without the header are not affected. Keys are scoped per
+// method + path.
+func Idempotency(store *IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		scopedKey := c.Request.Method + " " + c.FullPath() + " " + key
+
+		entry, isNew := store.begin(scopedKey)
+		if !isNew {
+			if !entry.done {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"error": "A request with this Idempotency-Key is still in progress",
+				})
+				return
+			}
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(entry.status, entry.contentType, entry.body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		// Release the key if the handler panics so a retry can run
+		defer func() {
+			if r := recover(); r != nil {
+				store.release(scopedKey)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			store.release(scopedKey)
+			return
+		}
+		store.finish(scopedKey, c.Writer.Status(), c.Writer.Header().Get("Content-Type"), recorder.body.Bytes())
+	}
+}
+
+// begin returns a copy of the live entry for key, or reserves a new
+// in-progress entry and reports isNew=true. The copy is taken under the lock
+// so callers can read it while finish updates the stored entry. Expired
+// entries are pruned on the way.
+func (s *IdempotencyStore) begin(key string) (entry idempotencyEntry, isNew bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range s.entries {
+		if e.done && now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+
+	if e, ok := s.entries[key]; ok {
+		return *e, false
+	}
+
+	s.entries[key] = &idempotencyEntry{}
+	return idempotencyEntry{}, true
+}
+
+// finish stores the response for key and starts its TTL.
+func (s *IdempotencyStore) finish(key string, status int, contentType string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return
+	}
+	e.done = true
+	e.status = status
+	e.contentType = contentType
+	e.body = append([]byte(nil), body...)
+	e.expiresAt = time.Now().Add(s.ttl)
+}
+
+// release forgets key without storing a response.
+func (s *IdempotencyStore) release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
+
+// responseRecorder copies the response body while passing it through.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
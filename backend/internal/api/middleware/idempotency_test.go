@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newIdempotencyRouter(store *IdempotencyStore, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/refresh", Idempotency(store), func(c *gin.Context) {
+		*calls++
+		c.JSON(http.StatusOK, gin.H{"run": *calls})
+	})
+	return router
+}
+
+func doRefresh(router *gin.Engine, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency_ReplaysSameKey(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(NewIdempotencyStore(time.Minute), &calls)
+
+	first := doRefresh(router, "abc")
+	second := doRefresh(router, "abc")
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("replayed body = %q, want %q", second.Body.String(), first.Body.String())
+	}
+	if second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("expected Idempotent-Replayed header on replay")
+	}
+}
+
+func TestIdempotency_DifferentOrMissingKeyRunsAgain(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(NewIdempotencyStore(time.Minute), &calls)
+
+	doRefresh(router, "abc")
+	doRefresh(router, "def")
+	doRefresh(router, "")
+	doRefresh(router, "")
+
+	if calls != 4 {
+		t.Errorf("handler ran %d times, want 4", calls)
+	}
+}
+
+func TestIdempotency_ExpiredKeyRunsAgain(t *testing.T) {
+	calls := 0
+	router := newIdempotencyRouter(NewIdempotencyStore(time.Millisecond), &calls)
+
+	doRefresh(router, "abc")
+	time.Sleep(5 * time.Millisecond)
+	doRefresh(router, "abc")
+
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
+
+func TestIdempotency_InProgressConflict(t *testing.T) {
+	store := NewIdempotencyStore(time.Minute)
+	if _, isNew := store.begin("POST /refresh abc"); !isNew {
+		t.Fatal("expected first begin to reserve the key")
+	}
+
+	calls := 0
+	router := newIdempotencyRouter(store, &calls)
+	w := doRefresh(router, "abc")
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	if calls != 0 {
+		t.Errorf("handler ran %d times, want 0", calls)
+	}
+}
+
+func TestIdempotency_ConcurrentSameKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := NewIdempotencyStore(time.Minute)
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+
+	router := gin.New()
+	router.POST("/refresh", Idempotency(store), func(c *gin.Context) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		close(started)
+		<-unblock
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	var first *httptest.ResponseRecorder
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = doRefresh(router, "abc")
+	}()
+	<-started
+
+	// Double-click while the first request is still running
+	var conflicts sync.WaitGroup
+	codes := make([]int, 4)
+	for i := range codes {
+		conflicts.Add(1)
+		go func(i int) {
+			defer conflicts.Done()
+			codes[i] = doRefresh(router, "abc").Code
+		}(i)
+	}
+	conflicts.Wait()
+	close(unblock)
+	wg.Wait()
+
+	for i, code := range codes {
+		if code != http.StatusConflict {
+			t.Errorf("concurrent request %d status = %d, want %d", i, code, http.StatusConflict)
+		}
+	}
+	if first.Code != http.StatusOK {
+		t.Errorf("first status = %d, want %d", first.Code, http.StatusOK)
+	}
+
+	// Replays after completion race with nothing but must still read the
+	// stored response under the lock
+	var replays sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		replays.Add(1)
+		go func() {
+			defer replays.Done()
+			w := doRefresh(router, "abc")
+			if w.Code != http.StatusOK || w.Header().Get("Idempotent-Replayed") != "true" {
+				t.Errorf("replay status = %d replayed = %q", w.Code, w.Header().Get("Idempotent-Replayed"))
+			}
+		}()
+	}
+	replays.Wait()
+
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestIdempotency_ServerErrorNotCached(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := NewIdempotencyStore(time.Minute)
+	calls := 0
+
+	router := gin.New()
+	router.POST("/refresh", Idempotency(store), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "refresh failed"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"run": calls})
+	})
+
+	first := doRefresh(router, "abc")
+	second := doRefresh(router, "abc")
+
+	if first.Code != http.StatusInternalServerError {
+		t.Errorf("first status = %d, want %d", first.Code, http.StatusInternalServerError)
+	}
+	if second.Code != http.StatusOK {
+		t.Errorf("retry status = %d, want %d", second.Code, http.StatusOK)
+	}
+	if second.Header().Get("Idempotent-Replayed") == "true" {
+		t.Error("retry after 5xx should not be a replay")
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
@@ -1,26 +1,53 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Logger logs HTTP requests with structured information
-func Logger() gin.HandlerFunc {
+// Logger emits one structured log line per request with the method, path,
+// matched route, status, duration and request ID. route is the registered
+// pattern (e.g. /api/weather/:id) so latency can be aggregated per endpoint.
+// 5xx responses log at error level and 4xx at warn. A nil logger uses
+// slog.Default(). Register after RequestID so the ID is available.
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		method := c.Request.Method
 
 		c.Next()
 
 		duration := time.Since(start)
 		status := c.Writer.Status()
-		requestID := c.GetString("request_id")
 
-		log.Printf("[%s] %s %s - %d (%dms) request_id=%s",
-			method, path, c.ClientIP(), status, duration.Milliseconds(), requestID)
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+			slog.String("request_id", c.GetString("request_id")),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		logger.LogAttrs(c.Request.Context(), level, "http request", attrs...)
 	}
 }
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogger_EmitsStructuredFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	router := gin.New()
+	router.Use(RequestID(), Logger(logger))
+	router.GET("/api/weather/:id", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/weather/7", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v (%q)", err, buf.String())
+	}
+
+	want := map[string]any{
+		"level":      "WARN",
+		"method":     "GET",
+		"path":       "/api/weather/7",
+		"route":      "/api/weather/:id",
+		"status":     float64(http.StatusNotFound),
+		"request_id": "abc-123",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["duration_ms"].(float64); !ok {
+		t.Errorf("duration_ms missing or not numeric: %v", entry["duration_ms"])
+	}
+}
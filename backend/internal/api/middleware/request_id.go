@@ -1,20 +1,60 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-// RequestID adds a unique ID to each request
+// RequestIDHeader carries the request ID on both the request and the response.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps client-supplied IDs so a caller cannot flood the logs.
+const maxRequestIDLength = 128
+
+type requestIDContextKey struct{}
+
+// RequestID adds a unique ID to each request. A well-formed X-Request-ID from
+// the client is reused so callers can correlate their own logs; otherwise a
+// new UUID is generated. The ID is stored on the gin context, the request
+// context (see RequestIDFromContext) and the response header.
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
 			requestID = uuid.New().String()
 		}
 
 		c.Set("request_id", requestID)
-		c.Header("X-Request-ID", requestID)
+		c.Request = c.Request.WithContext(ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
 		c.Next()
 	}
 }
+
+// ContextWithRequestID returns a copy of ctx carrying requestID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by RequestID, or "" when
+// ctx did not come from an HTTP request.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// validRequestID accepts non-empty IDs of printable ASCII without spaces, so
+// client-supplied values cannot inject line breaks or fields into log output.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRequestIDRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/ping", func(c *gin.Context) {
+		*seen = RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequestID_GeneratesAndPropagates(t *testing.T) {
+	var seen string
+	router := newRequestIDRouter(&seen)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	header := w.Header().Get(RequestIDHeader)
+	if header == "" {
+		t.Fatal("expected X-Request-ID response header")
+	}
+	if seen != header {
+		t.Errorf("context request ID = %q, want %q", seen, header)
+	}
+}
+
+func TestRequestID_ReusesClientID(t *testing.T) {
+	var seen string
+	router := newRequestIDRouter(&seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "sync-run-42")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "sync-run-42" {
+		t.Errorf("response request ID = %q, want sync-run-42", got)
+	}
+	if seen != "sync-run-42" {
+		t.Errorf("context request ID = %q, want sync-run-42", seen)
+	}
+}
+
+func TestRequestID_ReplacesMalformedClientID(t *testing.T) {
+	for _, id := range []string{"has space", "tab\tid", strings.Repeat("a", maxRequestIDLength+1)} {
+		var seen string
+		router := newRequestIDRouter(&seen)
+
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get(RequestIDHeader); got == id || got == "" {
+			t.Errorf("malformed ID %q was not replaced (got %q)", id, got)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package beta
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AspectHint is a route aspect read from explicit sun beta in its comments.
+type AspectHint struct {
+	Aspect   string   // N, NE, E, SE, S, SW, W, NW
+	Evidence []string // Matched phrases, in comment order, for auditing
+}
+
+// aspectDirection matches a compass direction. Compound directions come
+// first so "northeast" is not read as "north".
+const aspectDirection = `(north[- ]?east|north[- ]?west|south[- ]?east|south[- ]?west|north|south|east|west|ne|nw|se|sw)`
+
+// facingPatterns only accept phrases that state which way the rock faces.
+// "The north side", "north face", or "walk north" say where something is,
+// not which way it faces, and are ignored.
+var facingPatterns = compileAll(
+	`\bfac(?:es|ing) (?:due )?`+aspectDirection+`\b`,
+	`\b`+aspectDirection+`[- ]facing\b`,
+)
+
+// Sun timing cues. They never set an aspect on their own, but one that
+// contradicts the stated direction (an east face with afternoon sun) makes
+// the hint unreliable.
+var (
+	eastCues = compileAll(
+		`\bmorning sun\b`,
+		`\bsun in the morning\b`,
+		`\bafternoon shade\b`,
+		`\bshaded? in the afternoon\b`,
+	)
+	westCues = compileAll(
+		`\bafternoon sun\b`,
+		`\bsun in the afternoon\b`,
+		`\bevening sun\b`,
+		`\bmorning shade\b`,
+		`\bshaded? in the morning\b`,
+	)
+)
+
+// ExtractAspect returns the aspect stated by comments, or nil when they
+// don't state one confidently. It is deliberately conservative: every
+// non-negated facing phrase must name the same direction, and no sun timing
+// cue may contradict it.
+func ExtractAspect(comments []string) *AspectHint {
+	var hint *AspectHint
+	hasEastCue, hasWestCue := false, false
+
+	for _, comment := range comments {
+		text := normalize(comment)
+		for _, pattern := range facingPatterns {
+			for _, m := range pattern.FindAllStringSubmatchIndex(text, -1) {
+				if isNegated(text[:m[0]]) {
+					continue
+				}
+				aspect := directionToAspect(text[m[2]:m[3]])
+				if hint == nil {
+					hint = &AspectHint{Aspect: aspect}
+				} else if hint.Aspect != aspect {
+					return nil
+				}
+				hint.Evidence = append(hint.Evidence, text[m[0]:m[1]])
+			}
+		}
+		hasEastCue = hasEastCue || matchesAny(text, eastCues)
+		hasWestCue = hasWestCue || matchesAny(text, westCues)
+	}
+
+	if hint == nil {
+		return nil
+	}
+	if hasWestCue && strings.Contains(hint.Aspect, "E") {
+		return nil
+	}
+	if hasEastCue && strings.Contains(hint.Aspect, "W") {
+		return nil
+	}
+	return hint
+}
+
+// directionAspects maps a matched direction, with spaces and hyphens
+// removed, to its aspect code.
+var directionAspects = map[string]string{
+	"north": "N", "northeast": "NE", "east": "E", "southeast": "SE",
+	"south": "S", "southwest": "SW", "west": "W", "northwest": "NW",
+	"ne": "NE", "se": "SE", "sw": "SW", "nw": "NW",
+}
+
+// directionToAspect converts a matched direction ("south-east", "se") to an
+// aspect code ("SE").
+func directionToAspect(direction string) string {
+	return directionAspects[strings.NewReplacer("-", "", " ", "").Replace(direction)]
+}
+
+func matchesAny(text string, patterns []*regexp.Regexp) bool {
+	for _, pattern := range patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if !isNegated(text[:loc[0]]) {
+				return true
+			}
+		}
+	}
+	return false
+}
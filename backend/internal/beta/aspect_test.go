@@ -0,0 +1,73 @@
+package beta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractAspect(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		want     *AspectHint
+	}{
+		{
+			name:     "no comments",
+			comments: nil,
+			want:     nil,
+		},
+		{
+			name:     "sun beta without a direction",
+			comments: []string{"Morning shade, gets baked by noon."},
+			want:     nil,
+		},
+		{
+			name:     "faces direction",
+			comments: []string{"Faces south so it dries fast."},
+			want:     &AspectHint{Aspect: "S", Evidence: []string{"faces south"}},
+		},
+		{
+			name:     "hyphenated facing and abbreviation agree",
+			comments: []string{"North-east facing, stays cool.", "Facing NE, go in the afternoon."},
+			want:     &AspectHint{Aspect: "NE", Evidence: []string{"north-east facing", "facing ne"}},
+		},
+		{
+			name:     "compound direction is not read as its first word",
+			comments: []string{"Faces southwest and gets evening sun."},
+			want:     &AspectHint{Aspect: "SW", Evidence: []string{"faces southwest"}},
+		},
+		{
+			name:     "disagreeing comments are ignored",
+			comments: []string{"Faces south.", "This is north facing, bring a jacket."},
+			want:     nil,
+		},
+		{
+			name:     "contradicting sun timing is ignored",
+			comments: []string{"East facing.", "Great afternoon sun on this one."},
+			want:     nil,
+		},
+		{
+			name:     "agreeing sun timing is kept",
+			comments: []string{"Faces west.", "Morning shade, afternoon sun."},
+			want:     &AspectHint{Aspect: "W", Evidence: []string{"faces west"}},
+		},
+		{
+			name:     "negated facing phrase is ignored",
+			comments: []string{"Despite the topo it is not south facing."},
+			want:     nil,
+		},
+		{
+			name:     "location phrases are not aspects",
+			comments: []string{"On the north side of the boulder, walk west from the trail to the north face."},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractAspect(tt.comments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractAspect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Package beta extracts climbing conditions beta ("seeps for days after
+// rain", "gets sun by 10am") from free-text Mountain Project comments.
+package beta
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Conditions beta tags, in the order Extract reports them.
+const (
+	TagSeeps          = "seeps"
+	TagDriesFast      = "dries_fast"
+	TagStaysDryInRain = "stays_dry_in_rain"
+	TagMorningSun     = "morning_sun"
+	TagAfternoonSun   = "afternoon_sun"
+	TagShady          = "shady"
+	TagHotInSummer    = "hot_in_summer"
+	TagGoodInWinter   = "good_in_winter"
+)
+
+// conditionsRule maps a tag to the phrases that imply it. Patterns are
+// deliberately narrow: a missed tag is cheaper than a wrong one.
+type conditionsRule struct {
+	tag      string
+	patterns []*regexp.Regexp
+}
+
+var conditionsRules = []conditionsRule{
+	{TagSeeps, compileAll(
+		`\bseep(s|ing|age|y)?\b`,
+		`\bstays? (wet|damp)\b`,
+		`\b(wet|damp) for (days|a week|weeks)\b`,
+		`\btakes? (days|forever|a week|a long time) to dry\b`,
+	)},
+	{TagDriesFast, compileAll(
+		`\bdr(y|ies) (out )?(fast|quickly)\b`,
+		`\bquick to dry\b`,
+	)},
+	{TagStaysDryInRain, compileAll(
+		`\b(stays?|remains?) dry (in|during) (the )?rain\b`,
+		`\bclimbable in (the )?rain\b`,
+		`\brainy[- ]day (crag|option|spot|boulder)\b`,
+	)},
+	{TagMorningSun, compileAll(
+		`\bmorning sun\b`,
+		`\bsun in the morning\b`,
+		`\bsun (by|until|till) \d{1,2}(:\d{2})? ?am\b`,
+	)},
+	{TagAfternoonSun, compileAll(
+		`\bafternoon sun\b`,
+		`\bsun in the afternoon\b`,
+		`\bsun (after|from) \d{1,2}(:\d{2})? ?pm\b`,
+	)},
+	{TagShady, compileAll(
+		`\bshady\b`,
+		`\bstays? shaded\b`,
+		`\b(in the )?shade all day\b`,
+		`\b(always|mostly) (in the )?shade\b`,
+	)},
+	{TagHotInSummer, compileAll(
+		`\b(brutal|miserable|unbearable|too hot|scorching|an oven|baking) (in|during) (the )?summer\b`,
+		`\bbakes in (the )?summer\b`,
+		`\bavoid (it )?(in|during) (the )?summer\b`,
+	)},
+	{TagGoodInWinter, compileAll(
+		`\b(great|good|perfect|best) (in|during) (the )?winter\b`,
+		`\bwinter (crag|destination|spot|area)\b`,
+	)},
+}
+
+// negationWords cancel a match when they appear in the few words before it
+// ("doesn't seep", "never stays wet").
+var negationWords = map[string]bool{
+	"not": true, "no": true, "never": true, "rarely": true, "hardly": true,
+	"doesn't": true, "don't": true, "isn't": true, "won't": true, "didn't": true,
+}
+
+// negationWindow is how many words before a match are checked for negation.
+const negationWindow = 3
+
+func compileAll(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		compiled[i] = regexp.MustCompile(p)
+	}
+	return compiled
+}
+
+// Extract returns the conditions beta tags found across comments, without
+// duplicates and in the order the tags are declared. A tag is reported when
+// at least one comment contains a non-negated phrase for it.
+func Extract(comments []string) []string {
+	found := make(map[string]bool)
+	for _, comment := range comments {
+		text := normalize(comment)
+		for _, rule := range conditionsRules {
+			if !found[rule.tag] && matchesRule(text, rule) {
+				found[rule.tag] = true
+			}
+		}
+	}
+
+	var tags []string
+	for _, rule := range conditionsRules {
+		if found[rule.tag] {
+			tags = append(tags, rule.tag)
+		}
+	}
+	return tags
+}
+
+func matchesRule(text string, rule conditionsRule) bool {
+	for _, pattern := range rule.patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			if !isNegated(text[:loc[0]]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNegated reports whether the end of prefix, within the same sentence,
+// contains a negation word.
+func isNegated(prefix string) bool {
+	if i := strings.LastIndexAny(prefix, ".!?;\n"); i >= 0 {
+		prefix = prefix[i+1:]
+	}
+	words := strings.Fields(prefix)
+	if len(words) > negationWindow {
+		words = words[len(words)-negationWindow:]
+	}
+	for _, w := range words {
+		if negationWords[strings.Trim(w, ",()\"")] {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	s = strings.ToLower(s)
+	return strings.NewReplacer("’", "'", "‘", "'").Replace(s)
+}
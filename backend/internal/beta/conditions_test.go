@@ -0,0 +1,93 @@
+package beta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		comments []string
+		want     []string
+	}{
+		{
+			name:     "no comments",
+			comments: nil,
+			want:     nil,
+		},
+		{
+			name:     "unrelated comment",
+			comments: []string{"Classic line, great movement on perfect stone. Bring two pads."},
+			want:     nil,
+		},
+		{
+			name:     "seepage",
+			comments: []string{"Seeps for days after rain, check before you hike in."},
+			want:     []string{TagSeeps},
+		},
+		{
+			name:     "takes days to dry",
+			comments: []string{"The landing takes days to dry out."},
+			want:     []string{TagSeeps},
+		},
+		{
+			name:     "morning sun with time",
+			comments: []string{"Gets sun by 10am so go early in summer."},
+			want:     []string{TagMorningSun},
+		},
+		{
+			name:     "afternoon sun",
+			comments: []string{"Faces west and gets afternoon sun."},
+			want:     []string{TagAfternoonSun},
+		},
+		{
+			name:     "hot in summer",
+			comments: []string{"Brutal in summer, this thing is a solar oven."},
+			want:     []string{TagHotInSummer},
+		},
+		{
+			name:     "stays dry in rain",
+			comments: []string{"Steep enough that it stays dry in the rain."},
+			want:     []string{TagStaysDryInRain},
+		},
+		{
+			name:     "shade and winter",
+			comments: []string{"Shady all afternoon.", "Perfect in winter when everything else is wet."},
+			want:     []string{TagShady, TagGoodInWinter},
+		},
+		{
+			name:     "negated seepage is ignored",
+			comments: []string{"Doesn't seep like the rest of the wall.", "Never stays wet for long."},
+			want:     nil,
+		},
+		{
+			name:     "curly apostrophe negation",
+			comments: []string{"It doesn’t seep much."},
+			want:     nil,
+		},
+		{
+			name:     "negation does not cross sentences",
+			comments: []string{"Not a warmup. Seeps after storms."},
+			want:     []string{TagSeeps},
+		},
+		{
+			name:     "tags deduplicated and in declaration order",
+			comments: []string{"Dries fast.", "Gets afternoon sun.", "Seepage in spring.", "Seepy start."},
+			want:     []string{TagSeeps, TagDriesFast, TagAfternoonSun},
+		},
+		{
+			name:     "words containing a phrase do not match",
+			comments: []string{"Great for shadyside sessions and the seepless arete."},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Extract(tt.comments); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package buildinfo reports which build of the backend is running.
+//
+// Release builds inject the commit and build time with -ldflags:
+//
+//	go build -ldflags "-X github.com/alexscott64/woulder/backend/internal/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/alexscott64/woulder/backend/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+//
+// Builds without them fall back to the VCS information the Go toolchain
+// stamps into binaries built inside a git checkout, and otherwise report
+// "unknown".
+package buildinfo
+
+import "runtime/debug"
+
+// Set via -ldflags -X at build time; see the package comment.
+var (
+	Commit    = ""
+	BuildTime = ""
+)
+
+const unknown = "unknown"
+
+// Info describes the running build.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"` // RFC 3339, UTC
+	Modified  bool   `json:"modified"`   // Built from a checkout with uncommitted changes (VCS fallback only)
+}
+
+// Get returns the running build's info.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime}
+	if info.Commit == "" || info.BuildTime == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			applyVCSSettings(&info, bi.Settings)
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = unknown
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = unknown
+	}
+	return info
+}
+
+// applyVCSSettings fills the fields the linker flags left empty from the
+// toolchain's vcs.* build settings.
+func applyVCSSettings(info *Info, settings []debug.BuildSetting) {
+	fromVCS := false
+	for _, s := range settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+				fromVCS = true
+			}
+		case "vcs.time":
+			if info.BuildTime == "" {
+				info.BuildTime = s.Value
+			}
+		}
+	}
+	for _, s := range settings {
+		if fromVCS && s.Key == "vcs.modified" {
+			info.Modified = s.Value == "true"
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestApplyVCSSettings(t *testing.T) {
+	settings := []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "abc123"},
+		{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+
+	var info Info
+	applyVCSSettings(&info, settings)
+	if info.Commit != "abc123" || info.BuildTime != "2026-10-01T12:00:00Z" || !info.Modified {
+		t.Errorf("applyVCSSettings() = %+v", info)
+	}
+
+	// Linker-injected values win over the VCS stamp.
+	info = Info{Commit: "release", BuildTime: "2026-10-02T00:00:00Z"}
+	applyVCSSettings(&info, settings)
+	if info.Commit != "release" || info.BuildTime != "2026-10-02T00:00:00Z" || info.Modified {
+		t.Errorf("applyVCSSettings() overrode injected values: %+v", info)
+	}
+}
+
+func TestGet_NeverEmpty(t *testing.T) {
+	// Test binaries carry no VCS stamp, so this exercises the "unknown" fallback.
+	info := Get()
+	if info.Commit == "" || info.BuildTime == "" {
+		t.Errorf("Get() left empty fields: %+v", info)
+	}
+}
@@ -3,6 +3,7 @@ package boulders
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/lib/pq"
@@ -93,3 +94,39 @@ func (r *PostgresRepository) SaveProfile(ctx context.Context, profile *models.Bo
 
 	return err
 }
+
+// GetStaleTreeCoverProfiles returns profiles due for a tree coverage refresh.
+func (r *PostgresRepository) GetStaleTreeCoverProfiles(ctx context.Context, olderThan time.Time, limit int) ([]TreeCoverTarget, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetStaleTreeCoverProfiles, olderThan, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []TreeCoverTarget
+	for rows.Next() {
+		var t TreeCoverTarget
+		if err := rows.Scan(
+			&t.MPRouteID,
+			&t.Latitude,
+			&t.Longitude,
+			&t.TreeCoveragePercent,
+			&t.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// UpdateTreeCoverage sets a profile's tree coverage.
+func (r *PostgresRepository) UpdateTreeCoverage(ctx context.Context, mpRouteID int64, treeCoveragePercent float64) error {
+	_, err := r.db.ExecContext(ctx, queryUpdateTreeCoverage, mpRouteID, treeCoveragePercent)
+	return err
+}
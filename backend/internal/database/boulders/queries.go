@@ -37,4 +37,26 @@ const (
 			sun_exposure_hours_cache = EXCLUDED.sun_exposure_hours_cache,
 			updated_at = NOW()
 	`
+
+	// queryGetStaleTreeCoverProfiles lists profiles last updated before $1,
+	// oldest first, joined to their route's GPS. Capped at $2 per call.
+	queryGetStaleTreeCoverProfiles = `
+		SELECT p.mp_route_id, r.latitude, r.longitude,
+		       p.tree_coverage_percent, p.updated_at
+		FROM woulder.boulder_drying_profiles p
+		JOIN woulder.mp_routes r ON r.mp_route_id = p.mp_route_id
+		WHERE p.updated_at < $1
+		  AND r.latitude IS NOT NULL
+		  AND r.longitude IS NOT NULL
+		ORDER BY p.updated_at ASC
+		LIMIT $2
+	`
+
+	// queryUpdateTreeCoverage overwrites a profile's tree coverage only.
+	queryUpdateTreeCoverage = `
+		UPDATE woulder.boulder_drying_profiles
+		SET tree_coverage_percent = $2,
+		    updated_at = NOW()
+		WHERE mp_route_id = $1
+	`
 )
@@ -5,6 +5,7 @@ package boulders
 
 import (
 	"context"
+	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
@@ -24,4 +25,24 @@ type Repository interface {
 	// SaveProfile creates or updates a boulder drying profile.
 	// Uses upsert logic based on mp_route_id.
 	SaveProfile(ctx context.Context, profile *models.BoulderDryingProfile) error
+
+	// GetStaleTreeCoverProfiles returns up to limit profiles last updated
+	// before olderThan, oldest first, with their route's GPS position.
+	// Profiles whose route has no GPS are skipped since coverage can't be
+	// looked up for them.
+	GetStaleTreeCoverProfiles(ctx context.Context, olderThan time.Time, limit int) ([]TreeCoverTarget, error)
+
+	// UpdateTreeCoverage sets a profile's tree coverage, leaving its other
+	// fields untouched, and bumps updated_at.
+	UpdateTreeCoverage(ctx context.Context, mpRouteID int64, treeCoveragePercent float64) error
+}
+
+// TreeCoverTarget is a boulder drying profile due for a tree coverage
+// refresh.
+type TreeCoverTarget struct {
+	MPRouteID           int64
+	Latitude            float64
+	Longitude           float64
+	TreeCoveragePercent *float64 // current value, nil if never set
+	UpdatedAt           time.Time
 }
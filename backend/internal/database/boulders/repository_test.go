@@ -250,3 +250,73 @@ func TestPostgresRepository_SaveProfile_Update(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
 }
+
+func TestPostgresRepository_GetStaleTreeCoverProfiles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	cutoff := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	updated := cutoff.AddDate(-1, 0, 0)
+	treeCoverage := 40.0
+
+	rows := sqlmock.NewRows([]string{
+		"mp_route_id", "latitude", "longitude", "tree_coverage_percent", "updated_at",
+	}).
+		AddRow(int64(111), 47.6, -120.9, &treeCoverage, updated).
+		AddRow(int64(222), 47.5, -121.0, nil, updated.AddDate(0, 1, 0))
+
+	mock.ExpectQuery("SELECT (.+) FROM woulder.boulder_drying_profiles p JOIN woulder.mp_routes r").
+		WithArgs(cutoff, 50).
+		WillReturnRows(rows)
+
+	repo := boulders.NewPostgresRepository(db)
+	targets, err := repo.GetStaleTreeCoverProfiles(context.Background(), cutoff, 50)
+
+	if err != nil {
+		t.Fatalf("GetStaleTreeCoverProfiles() error = %v", err)
+	}
+
+	if len(targets) != 2 {
+		t.Fatalf("GetStaleTreeCoverProfiles() returned %d targets, want 2", len(targets))
+	}
+
+	if targets[0].MPRouteID != 111 || targets[0].Latitude != 47.6 || targets[0].Longitude != -120.9 {
+		t.Errorf("GetStaleTreeCoverProfiles() first target = %+v", targets[0])
+	}
+
+	if targets[0].TreeCoveragePercent == nil || *targets[0].TreeCoveragePercent != 40.0 {
+		t.Errorf("GetStaleTreeCoverProfiles() tree coverage = %v, want 40", targets[0].TreeCoveragePercent)
+	}
+
+	if targets[1].TreeCoveragePercent != nil {
+		t.Errorf("GetStaleTreeCoverProfiles() second tree coverage = %v, want nil", *targets[1].TreeCoveragePercent)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_UpdateTreeCoverage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec("UPDATE woulder.boulder_drying_profiles SET tree_coverage_percent").
+		WithArgs(int64(12345), 12.5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := boulders.NewPostgresRepository(db)
+	if err := repo.UpdateTreeCoverage(context.Background(), 12345, 12.5); err != nil {
+		t.Errorf("UpdateTreeCoverage() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
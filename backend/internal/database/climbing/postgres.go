@@ -3,6 +3,7 @@ package climbing
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/lib/pq"
@@ -237,6 +238,41 @@ func (r *PostgresRepository) GetSubareasOrderedByActivity(ctx context.Context, p
 	return areas, nil
 }
 
+// GetAreaTreeWithActivity retrieves all areas in a location with subtree activity.
+func (r *PostgresRepository) GetAreaTreeWithActivity(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetAreaTreeWithActivity, locationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var areas []models.AreaActivitySummary
+	for rows.Next() {
+		var area models.AreaActivitySummary
+		err := rows.Scan(
+			&area.MPAreaID,
+			&area.Name,
+			&area.ParentMPAreaID,
+			&area.LastClimbAt,
+			&area.UniqueRoutes,
+			&area.TotalTicks,
+			&area.DaysSinceClimb,
+			&area.HasSubareas,
+			&area.SubareaCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return areas, nil
+}
+
 // GetRoutesOrderedByActivity retrieves routes in an area ordered by activity.
 func (r *PostgresRepository) GetRoutesOrderedByActivity(ctx context.Context, areaID int64, locationID int, limit int) ([]models.RouteActivitySummary, error) {
 	rows, err := r.db.QueryContext(ctx, queryGetRoutesOrderedByActivity, areaID, locationID, limit)
@@ -265,6 +301,8 @@ func (r *PostgresRepository) GetRoutesOrderedByActivity(ctx context.Context, are
 			&comment,
 			&areaName,
 			&noTicks,
+			pq.Array(&route.ConditionsBeta),
+			&route.LastTickSyncAt,
 		)
 		if err != nil {
 			return nil, err
@@ -346,6 +384,285 @@ func (r *PostgresRepository) GetRecentTicksForRoute(ctx context.Context, routeID
 	return ticks, nil
 }
 
+// GetTrendingRoutes ranks routes by tick count within a recent window.
+func (r *PostgresRepository) GetTrendingRoutes(ctx context.Context, since time.Time, locationID *int, limit int) ([]models.TrendingRoute, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetTrendingRoutes, since, locationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []models.TrendingRoute
+	for rows.Next() {
+		var route models.TrendingRoute
+		var locID sql.NullInt64
+
+		err := rows.Scan(
+			&route.MPRouteID,
+			&route.Name,
+			&route.Rating,
+			&route.MPAreaID,
+			&route.AreaName,
+			&locID,
+			&route.TickCount,
+			&route.LastClimbAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if locID.Valid {
+			id := int(locID.Int64)
+			route.LocationID = &id
+		}
+
+		routes = append(routes, route)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// GetFeaturedRouteCandidates lists routes eligible to be the featured route.
+func (r *PostgresRepository) GetFeaturedRouteCandidates(ctx context.Context, locationID *int, since, until time.Time, limit int) ([]models.FeaturedRouteCandidate, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetFeaturedRouteCandidates, locationID, since, until, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []models.FeaturedRouteCandidate
+	for rows.Next() {
+		var route models.FeaturedRouteCandidate
+		var mpRating sql.NullFloat64
+
+		err := rows.Scan(
+			&route.MPRouteID,
+			&route.Name,
+			&route.Rating,
+			&route.RouteType,
+			&route.MPAreaID,
+			&route.AreaName,
+			&route.LocationID,
+			&mpRating,
+			&route.RecentTicks,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if mpRating.Valid {
+			route.MPRating = &mpRating.Float64
+		}
+
+		routes = append(routes, route)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// GetSimilarRouteCandidates lists routes to rank against routeID, including routeID itself.
+func (r *PostgresRepository) GetSimilarRouteCandidates(ctx context.Context, routeID int64, radiusMeters float64, since time.Time, limit int) ([]models.SimilarRouteCandidate, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetSimilarRouteCandidates, routeID, radiusMeters, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []models.SimilarRouteCandidate
+	for rows.Next() {
+		var route models.SimilarRouteCandidate
+		var locationID sql.NullInt64
+		var mpRating, distanceKm sql.NullFloat64
+
+		err := rows.Scan(
+			&route.MPRouteID,
+			&route.Name,
+			&route.Rating,
+			&route.RouteType,
+			&route.MPAreaID,
+			&route.AreaName,
+			&locationID,
+			&mpRating,
+			&distanceKm,
+			&route.TickCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if locationID.Valid {
+			id := int(locationID.Int64)
+			route.LocationID = &id
+		}
+		if mpRating.Valid {
+			route.MPRating = &mpRating.Float64
+		}
+		if distanceKm.Valid {
+			route.DistanceKm = &distanceKm.Float64
+		}
+
+		routes = append(routes, route)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// GetRecentlyDiscoveredRoutes lists routes found by the new-route sweep since the given time.
+func (r *PostgresRepository) GetRecentlyDiscoveredRoutes(ctx context.Context, since time.Time, limit int) ([]models.DiscoveredRoute, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetRecentlyDiscoveredRoutes, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []models.DiscoveredRoute
+	for rows.Next() {
+		var route models.DiscoveredRoute
+		var locID sql.NullInt64
+		var locName sql.NullString
+
+		err := rows.Scan(
+			&route.MPRouteID,
+			&route.Name,
+			&route.Rating,
+			&route.RouteType,
+			&route.MPAreaID,
+			&route.AreaName,
+			&locID,
+			&locName,
+			&route.DiscoveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if locID.Valid {
+			id := int(locID.Int64)
+			route.LocationID = &id
+		}
+		if locName.Valid {
+			route.LocationName = &locName.String
+		}
+
+		routes = append(routes, route)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+// GetDailyTickCounts counts a location's ticks per Pacific local day in a date range.
+func (r *PostgresRepository) GetDailyTickCounts(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetDailyTickCounts, locationID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.DailyTickCount
+	for rows.Next() {
+		var count models.DailyTickCount
+		if err := rows.Scan(&count.Date, &count.TickCount, &count.RouteCount); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetRouteActivitySeries counts a route's ticks per day or week bucket in a date range.
+func (r *PostgresRepository) GetRouteActivitySeries(ctx context.Context, routeID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+	return r.queryActivitySeries(ctx, queryGetRouteActivitySeries, routeID, bucket, startDate, endDate)
+}
+
+// GetAreaActivitySeries counts an area subtree's ticks per day or week bucket in a date range.
+func (r *PostgresRepository) GetAreaActivitySeries(ctx context.Context, areaID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+	return r.queryActivitySeries(ctx, queryGetAreaActivitySeries, areaID, bucket, startDate, endDate)
+}
+
+// queryActivitySeries runs an activity series query for one route or area.
+func (r *PostgresRepository) queryActivitySeries(ctx context.Context, query string, id int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+	rows, err := r.db.QueryContext(ctx, query, id, bucket, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.ActivitySeriesPoint{}
+	for rows.Next() {
+		var point models.ActivitySeriesPoint
+		if err := rows.Scan(&point.BucketStart, &point.TickCount); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+// GetRouteClosures returns the closure notes of the closed routes among routeIDs.
+func (r *PostgresRepository) GetRouteClosures(ctx context.Context, routeIDs []int64) (map[int64]string, error) {
+	return r.queryClosures(ctx, queryGetRouteClosures, routeIDs)
+}
+
+// GetAreaClosures returns the closure notes of the closed areas among areaIDs.
+func (r *PostgresRepository) GetAreaClosures(ctx context.Context, areaIDs []int64) (map[int64]string, error) {
+	return r.queryClosures(ctx, queryGetAreaClosures, areaIDs)
+}
+
+// queryClosures runs a closure query over ids and collects id -> note rows.
+func (r *PostgresRepository) queryClosures(ctx context.Context, query string, ids []int64) (map[int64]string, error) {
+	closures := make(map[int64]string)
+	if len(ids) == 0 {
+		return closures, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var note sql.NullString
+		if err := rows.Scan(&id, &note); err != nil {
+			return nil, err
+		}
+		closures[id] = note.String
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return closures, nil
+}
+
 // ====================
 // Search Repository
 // ====================
@@ -146,7 +146,8 @@ const (
 	// 1. Finds virtual root areas for the location
 	// 2. Determines top-level areas (root children or roots if multiple)
 	// 3. Recursively aggregates activity from all descendant areas
-	// 4. Orders by most recent activity
+	// 4. Orders by most recent activity, then total ticks and area ID so rows
+	//    with equal recency keep a stable order across requests
 	queryGetAreasOrderedByActivity = `
 		WITH RECURSIVE adjusted_ticks AS (
 			SELECT
@@ -211,12 +212,13 @@ const (
 		INNER JOIN adjusted_ticks adj ON r.mp_route_id = adj.mp_route_id
 		GROUP BY tla.mp_area_id, tla.name, tla.parent_mp_area_id
 		HAVING MAX(adj.adjusted_climbed_at) IS NOT NULL
-		ORDER BY MAX(adj.adjusted_climbed_at) DESC
+		ORDER BY MAX(adj.adjusted_climbed_at) DESC, total_ticks DESC, tla.mp_area_id ASC
 	`
 
 	// queryGetSubareasOrderedByActivity retrieves subareas with aggregated activity.
 	// Recursively aggregates activity from all descendant areas.
 	// Shows subareas even if they have no activity (uses LEFT JOIN with COALESCE).
+	// Ties on recency are broken by total ticks, then area ID, for stable ordering.
 	queryGetSubareasOrderedByActivity = `
 		WITH RECURSIVE adjusted_ticks AS (
 			SELECT
@@ -266,18 +268,75 @@ const (
 		LEFT JOIN woulder.mp_routes r ON atree.mp_area_id = r.mp_area_id
 		LEFT JOIN adjusted_ticks adj ON r.mp_route_id = adj.mp_route_id
 		GROUP BY sa.mp_area_id, sa.name, sa.parent_mp_area_id
-		ORDER BY MAX(adj.adjusted_climbed_at) DESC NULLS LAST
+		ORDER BY MAX(adj.adjusted_climbed_at) DESC NULLS LAST, total_ticks DESC, sa.mp_area_id ASC
+	`
+
+	// queryGetAreaTreeWithActivity retrieves all areas in a location with
+	// activity aggregated over each area's subtree in a single pass:
+	// area_closure pairs every area with itself and all of its descendants,
+	// so grouping ticks by ancestor rolls them up at every level.
+	// Subarea counts only include areas in the location, matching the tree.
+	queryGetAreaTreeWithActivity = `
+		WITH RECURSIVE adjusted_ticks AS (
+			SELECT
+				t.mp_route_id,
+				CASE
+					WHEN t.climbed_at > NOW() + INTERVAL '350 days'
+					     AND t.climbed_at < NOW() + INTERVAL '380 days'
+					THEN t.climbed_at - INTERVAL '1 year'
+					ELSE t.climbed_at
+				END AS adjusted_climbed_at
+			FROM woulder.mp_ticks t
+			WHERE
+				t.climbed_at <= NOW() + INTERVAL '30 days'
+				AND t.climbed_at >= NOW() - INTERVAL '2 years'
+		),
+		location_areas AS (
+			SELECT mp_area_id, name, parent_mp_area_id
+			FROM woulder.mp_areas
+			WHERE location_id = $1
+		),
+		area_closure AS (
+			SELECT mp_area_id AS ancestor_id, mp_area_id AS descendant_id
+			FROM location_areas
+
+			UNION ALL
+
+			SELECT ac.ancestor_id, a.mp_area_id
+			FROM area_closure ac
+			INNER JOIN location_areas a ON a.parent_mp_area_id = ac.descendant_id
+		)
+		SELECT
+			la.mp_area_id,
+			la.name,
+			la.parent_mp_area_id,
+			COALESCE(MAX(adj.adjusted_climbed_at), NOW() - INTERVAL '10 years') AS last_climb_at,
+			COUNT(DISTINCT r.mp_route_id) AS unique_routes,
+			COALESCE(COUNT(adj.mp_route_id), 0)::int AS total_ticks,
+			COALESCE(EXTRACT(DAY FROM (NOW() - MAX(adj.adjusted_climbed_at)))::int, 3650) AS days_since_climb,
+			EXISTS(SELECT 1 FROM location_areas sub WHERE sub.parent_mp_area_id = la.mp_area_id) AS has_subareas,
+			(SELECT COUNT(*)::int FROM location_areas sub WHERE sub.parent_mp_area_id = la.mp_area_id) AS subarea_count
+		FROM location_areas la
+		INNER JOIN area_closure ac ON ac.ancestor_id = la.mp_area_id
+		LEFT JOIN woulder.mp_routes r ON r.mp_area_id = ac.descendant_id
+		LEFT JOIN adjusted_ticks adj ON r.mp_route_id = adj.mp_route_id
+		GROUP BY la.mp_area_id, la.name, la.parent_mp_area_id
+		ORDER BY MAX(adj.adjusted_climbed_at) DESC NULLS LAST, total_ticks DESC, la.mp_area_id ASC
 	`
 
 	// queryGetRoutesOrderedByActivity retrieves ALL routes in an area by activity.
 	// Shows routes with ticks first (by recency), then routes without ticks (alphabetically).
+	// Route ID is the final tie-break so ordering is stable across requests.
 	// Includes most recent tick for each route using ROW_NUMBER() window function.
 	queryGetRoutesOrderedByActivity = `
 		WITH area_routes AS (
 			-- Filter routes by area and location first
-			SELECT r.mp_route_id, r.name, COALESCE(r.difficulty, r.rating, '') AS rating, r.mp_area_id, a.name AS area_name
+			SELECT r.mp_route_id, r.name, COALESCE(r.difficulty, r.rating, '') AS rating, r.mp_area_id, a.name AS area_name,
+			       cb.tags AS conditions_beta, r.last_tick_sync_at
 			FROM woulder.mp_routes r
 			INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+			LEFT JOIN woulder.mp_conditions_beta cb
+				ON cb.target_type = 'route' AND cb.mp_id = r.mp_route_id
 			WHERE r.mp_area_id = $1
 			  AND r.location_id = $2
 		),
@@ -319,14 +378,251 @@ const (
 			at.style,
 			at.comment,
 			ar.area_name,
-			CASE WHEN MAX(at.adjusted_climbed_at) IS NULL THEN 1 ELSE 0 END AS no_ticks
+			CASE WHEN MAX(at.adjusted_climbed_at) IS NULL THEN 1 ELSE 0 END AS no_ticks,
+			ar.conditions_beta,
+			ar.last_tick_sync_at
 		FROM area_routes ar
 		LEFT JOIN adjusted_ticks at ON ar.mp_route_id = at.mp_route_id AND at.tick_rank = 1
-		GROUP BY ar.mp_route_id, ar.name, ar.rating, ar.mp_area_id, ar.area_name, at.user_name, at.adjusted_climbed_at, at.style, at.comment
-		ORDER BY no_ticks ASC, MAX(at.adjusted_climbed_at) DESC NULLS LAST, ar.name ASC
+		GROUP BY ar.mp_route_id, ar.name, ar.rating, ar.mp_area_id, ar.area_name, ar.conditions_beta, ar.last_tick_sync_at, at.user_name, at.adjusted_climbed_at, at.style, at.comment
+		ORDER BY no_ticks ASC, MAX(at.adjusted_climbed_at) DESC NULLS LAST, ar.name ASC, ar.mp_route_id ASC
+		LIMIT $3
+	`
+
+	// queryGetTrendingRoutes ranks routes by tick count since $1, optionally
+	// scoped to location $2 (NULL for all locations). Future-dated ticks are
+	// excluded rather than year-adjusted: an adjusted tick would fall a year
+	// back, outside any trending window. Ties break on recency, then route ID.
+	queryGetTrendingRoutes = `
+		SELECT
+			r.mp_route_id,
+			r.name,
+			COALESCE(r.difficulty, r.rating, '') AS rating,
+			r.mp_area_id,
+			a.name AS area_name,
+			r.location_id,
+			COUNT(*)::int AS tick_count,
+			MAX(t.climbed_at) AS last_climb_at
+		FROM woulder.mp_ticks t
+		INNER JOIN woulder.mp_routes r ON t.mp_route_id = r.mp_route_id
+		INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+		WHERE t.climbed_at >= $1
+		  AND t.climbed_at <= NOW()
+		  AND ($2::int IS NULL OR r.location_id = $2)
+		GROUP BY r.mp_route_id, r.name, r.difficulty, r.rating, r.mp_area_id, a.name, r.location_id
+		ORDER BY tick_count DESC, last_climb_at DESC, r.mp_route_id ASC
 		LIMIT $3
 	`
 
+	// queryGetFeaturedRouteCandidates lists routes eligible to be featured:
+	// classics (3+ stars) and routes ticked in [$2, $3), optionally scoped to
+	// location $1 (NULL for all locations). Routes without their own location
+	// fall back to their area's; routes with neither are excluded. The best
+	// $4 by stars, then recent ticks, are returned in route ID order.
+	queryGetFeaturedRouteCandidates = `
+		SELECT * FROM (
+			SELECT
+				r.mp_route_id,
+				r.name,
+				COALESCE(r.difficulty, r.rating, '') AS rating,
+				COALESCE(r.route_type, '') AS route_type,
+				r.mp_area_id,
+				a.name AS area_name,
+				COALESCE(r.location_id, a.location_id) AS location_id,
+				r.mp_rating,
+				COUNT(t.id)::int AS recent_ticks
+			FROM woulder.mp_routes r
+			INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+			LEFT JOIN woulder.mp_ticks t ON t.mp_route_id = r.mp_route_id
+				AND t.climbed_at >= $2
+				AND t.climbed_at < $3
+			WHERE COALESCE(r.location_id, a.location_id) IS NOT NULL
+			  AND ($1::int IS NULL OR COALESCE(r.location_id, a.location_id) = $1)
+			GROUP BY r.mp_route_id, r.name, r.difficulty, r.rating, r.route_type,
+				r.mp_area_id, a.name, r.location_id, a.location_id, r.mp_rating
+			HAVING COALESCE(r.mp_rating, 0) >= 3 OR COUNT(t.id) > 0
+			ORDER BY COALESCE(r.mp_rating, 0) DESC, recent_ticks DESC, r.mp_route_id ASC
+			LIMIT $4
+		) candidates
+		ORDER BY mp_route_id ASC
+	`
+
+	// queryGetSimilarRouteCandidates lists routes to rank against route $1:
+	// the route itself plus routes of the same kind (boulder or roped) in its
+	// location or within $2 meters of it. Routes without GPS use their area's
+	// point, and routes without their own location their area's. The $4
+	// nearest are kept (the route itself first), then ticks since $3 are
+	// counted for just those, so the count never scans a whole location.
+	// Index: mp_ticks(mp_route_id, climbed_at) serves the tick counts.
+	queryGetSimilarRouteCandidates = `
+		WITH src AS (
+			SELECT
+				r.mp_route_id,
+				COALESCE(r.geog, a.geog) AS geog,
+				COALESCE(r.location_id, a.location_id) AS location_id,
+				COALESCE(r.route_type, '') ILIKE '%boulder%' AS is_boulder
+			FROM woulder.mp_routes r
+			INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+			WHERE r.mp_route_id = $1
+		),
+		candidates AS (
+			SELECT
+				r.mp_route_id,
+				r.name,
+				COALESCE(r.difficulty, r.rating, '') AS rating,
+				COALESCE(r.route_type, '') AS route_type,
+				r.mp_area_id,
+				a.name AS area_name,
+				COALESCE(r.location_id, a.location_id) AS location_id,
+				r.mp_rating,
+				ST_Distance(COALESCE(r.geog, a.geog), src.geog) / 1000.0 AS distance_km
+			FROM woulder.mp_routes r
+			INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+			CROSS JOIN src
+			WHERE r.mp_route_id = src.mp_route_id
+			   OR ((COALESCE(r.route_type, '') ILIKE '%boulder%') = src.is_boulder
+			       AND (COALESCE(r.location_id, a.location_id) = src.location_id
+			            OR ST_DWithin(COALESCE(r.geog, a.geog), src.geog, $2)))
+			ORDER BY r.mp_route_id = src.mp_route_id DESC, distance_km ASC NULLS LAST, r.mp_route_id ASC
+			LIMIT $4
+		)
+		SELECT
+			c.mp_route_id, c.name, c.rating, c.route_type, c.mp_area_id, c.area_name,
+			c.location_id, c.mp_rating, c.distance_km,
+			(SELECT COUNT(*)::int FROM woulder.mp_ticks t
+			 WHERE t.mp_route_id = c.mp_route_id AND t.climbed_at >= $3) AS tick_count
+		FROM candidates c
+		ORDER BY c.mp_route_id ASC
+	`
+
+	// queryGetRecentlyDiscoveredRoutes lists routes discovered since $1, newest
+	// first. Routes without their own location fall back to their area's.
+	queryGetRecentlyDiscoveredRoutes = `
+		SELECT
+			r.mp_route_id,
+			r.name,
+			COALESCE(r.difficulty, r.rating, '') AS rating,
+			COALESCE(r.route_type, '') AS route_type,
+			r.mp_area_id,
+			a.name AS area_name,
+			COALESCE(r.location_id, a.location_id) AS location_id,
+			l.name AS location_name,
+			r.discovered_at
+		FROM woulder.mp_routes r
+		INNER JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
+		LEFT JOIN woulder.locations l ON l.id = COALESCE(r.location_id, a.location_id)
+		WHERE r.discovered_at >= $1
+		ORDER BY r.discovered_at DESC, r.mp_route_id ASC
+		LIMIT $2
+	`
+
+	// queryGetDailyTickCounts counts ticks per Pacific local date for a
+	// location between $2 and $3 inclusive. Tick times are stored from
+	// Pacific dates, so this matches the days in weather_daily_aggregates.
+	// The raw climbed_at bounds (widened a day each way) let the tick index
+	// narrow the scan before the date conversion.
+	queryGetDailyTickCounts = `
+		SELECT
+			(t.climbed_at AT TIME ZONE 'America/Los_Angeles')::date::text AS local_date,
+			COUNT(*) AS tick_count,
+			COUNT(DISTINCT t.mp_route_id) AS route_count
+		FROM woulder.mp_ticks t
+		JOIN woulder.mp_routes r ON t.mp_route_id = r.mp_route_id
+		WHERE r.location_id = $1
+		  AND t.climbed_at >= $2::date - INTERVAL '1 day'
+		  AND t.climbed_at < $3::date + INTERVAL '2 days'
+		  AND (t.climbed_at AT TIME ZONE 'America/Los_Angeles')::date BETWEEN $2::date AND $3::date
+		GROUP BY local_date
+		ORDER BY local_date
+	`
+
+	// queryActivitySeriesBuckets finishes an activity series query from a
+	// ticks(climbed_at) CTE: it buckets the ticks by Pacific local date,
+	// truncated to $2 ('day' or 'week'), between $3 and $4 inclusive, and
+	// zero-fills empty buckets from generate_series.
+	queryActivitySeriesBuckets = `
+		buckets AS (
+			SELECT generate_series(
+				date_trunc($2::text, $3::date),
+				date_trunc($2::text, $4::date),
+				('1 ' || $2::text)::interval
+			)::date AS bucket_start
+		),
+		counts AS (
+			SELECT
+				date_trunc($2::text, (climbed_at AT TIME ZONE 'America/Los_Angeles')::date)::date AS bucket_start,
+				COUNT(*) AS tick_count
+			FROM ticks
+			WHERE climbed_at >= $3::date - INTERVAL '1 day'
+			  AND climbed_at < $4::date + INTERVAL '2 days'
+			  AND (climbed_at AT TIME ZONE 'America/Los_Angeles')::date BETWEEN $3::date AND $4::date
+			GROUP BY 1
+		)
+		SELECT b.bucket_start::text, COALESCE(c.tick_count, 0) AS tick_count
+		FROM buckets b
+		LEFT JOIN counts c ON c.bucket_start = b.bucket_start
+		ORDER BY b.bucket_start
+	`
+
+	// queryGetRouteActivitySeries buckets route $1's ticks.
+	queryGetRouteActivitySeries = `
+		WITH ticks AS (
+			SELECT climbed_at
+			FROM woulder.mp_ticks
+			WHERE mp_route_id = $1
+		),
+	` + queryActivitySeriesBuckets
+
+	// queryGetAreaActivitySeries buckets the ticks of every route in area
+	// $1's subtree.
+	queryGetAreaActivitySeries = `
+		WITH RECURSIVE subtree AS (
+			SELECT mp_area_id
+			FROM woulder.mp_areas
+			WHERE mp_area_id = $1
+			UNION ALL
+			SELECT a.mp_area_id
+			FROM woulder.mp_areas a
+			INNER JOIN subtree s ON a.parent_mp_area_id = s.mp_area_id
+		),
+		ticks AS (
+			SELECT t.climbed_at
+			FROM woulder.mp_ticks t
+			INNER JOIN woulder.mp_routes r ON t.mp_route_id = r.mp_route_id
+			INNER JOIN subtree s ON r.mp_area_id = s.mp_area_id
+		),
+	` + queryActivitySeriesBuckets
+
+	// queryGetRouteClosures returns the closure note of the closed routes
+	// among $1.
+	queryGetRouteClosures = `
+		SELECT mp_route_id, closure_note
+		FROM woulder.mp_route_closures
+		WHERE mp_route_id = ANY($1)
+		  AND is_closed
+	`
+
+	// queryGetAreaClosures returns the closure note of the areas among $1 in
+	// which every Kaya-matched route, across the area's subtree, is closed.
+	queryGetAreaClosures = `
+		WITH RECURSIVE subtree AS (
+			SELECT mp_area_id AS root_id, mp_area_id
+			FROM woulder.mp_areas
+			WHERE mp_area_id = ANY($1)
+
+			UNION ALL
+
+			SELECT s.root_id, a.mp_area_id
+			FROM woulder.mp_areas a
+			INNER JOIN subtree s ON a.parent_mp_area_id = s.mp_area_id
+		)
+		SELECT s.root_id, MIN(c.closure_note)
+		FROM subtree s
+		INNER JOIN woulder.mp_routes r ON r.mp_area_id = s.mp_area_id
+		INNER JOIN woulder.mp_route_closures c ON c.mp_route_id = r.mp_route_id
+		GROUP BY s.root_id
+		HAVING bool_and(c.is_closed)
+	`
+
 	// queryGetRecentTicksForRoute retrieves the most recent ticks for a specific route.
 	queryGetRecentTicksForRoute = `
 		WITH adjusted_ticks AS (
@@ -6,6 +6,7 @@ package climbing
 
 import (
 	"context"
+	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
@@ -56,6 +57,12 @@ type ActivityRepository interface {
 	// Uses smart date filtering. Results ordered by most recent activity.
 	GetSubareasOrderedByActivity(ctx context.Context, parentAreaID int64, locationID int) ([]models.AreaActivitySummary, error)
 
+	// GetAreaTreeWithActivity retrieves every area in a location with activity
+	// aggregated over its whole subtree, as flat rows linked by
+	// ParentMPAreaID. Includes areas without activity.
+	// Uses smart date filtering. Results ordered by most recent activity.
+	GetAreaTreeWithActivity(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error)
+
 	// GetRoutesOrderedByActivity retrieves ALL routes in an area ordered by activity.
 	// Shows routes with ticks first (by recency), then routes without ticks (alphabetically).
 	// Includes the most recent tick for each route if it has any.
@@ -65,6 +72,53 @@ type ActivityRepository interface {
 	// GetRecentTicksForRoute retrieves the most recent ticks for a specific route.
 	// Uses smart date filtering. Results ordered by climbed_at descending.
 	GetRecentTicksForRoute(ctx context.Context, routeID int64, limit int) ([]models.ClimbHistoryEntry, error)
+
+	// GetTrendingRoutes ranks routes by the number of ticks since the given
+	// time, optionally scoped to a location (nil for all locations).
+	// Results ordered by tick count descending, then most recent tick.
+	GetTrendingRoutes(ctx context.Context, since time.Time, locationID *int, limit int) ([]models.TrendingRoute, error)
+
+	// GetFeaturedRouteCandidates returns routes eligible to be featured:
+	// 3+ star classics and routes ticked in [since, until), optionally
+	// scoped to a location (nil for all locations). At most limit routes,
+	// preferring stars then recent ticks. Results ordered by route ID.
+	GetFeaturedRouteCandidates(ctx context.Context, locationID *int, since, until time.Time, limit int) ([]models.FeaturedRouteCandidate, error)
+
+	// GetSimilarRouteCandidates returns routes that could be recommended
+	// alongside routeID: those in the same location or within radiusMeters of
+	// it, of the same kind (boulder or roped), with ticks counted since the
+	// given time. At most limit routes, nearest first. The route itself is
+	// included so its signals come from the same query; an empty result
+	// means it does not exist. Results ordered by route ID.
+	GetSimilarRouteCandidates(ctx context.Context, routeID int64, radiusMeters float64, since time.Time, limit int) ([]models.SimilarRouteCandidate, error)
+
+	// GetRecentlyDiscoveredRoutes returns routes the new-route sweep found
+	// since the given time, newest first.
+	GetRecentlyDiscoveredRoutes(ctx context.Context, since time.Time, limit int) ([]models.DiscoveredRoute, error)
+
+	// GetDailyTickCounts counts a location's ticks per local (Pacific) day in
+	// an inclusive YYYY-MM-DD range. Days without ticks are omitted.
+	// Results ordered by date.
+	GetDailyTickCounts(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error)
+
+	// GetRouteActivitySeries counts a route's ticks per Pacific local day or
+	// week ("day" or "week") in an inclusive YYYY-MM-DD range. Every bucket
+	// in the range is returned, zero-filled. Results ordered by bucket.
+	GetRouteActivitySeries(ctx context.Context, routeID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error)
+
+	// GetAreaActivitySeries is GetRouteActivitySeries over every route in an
+	// area's subtree.
+	GetAreaActivitySeries(ctx context.Context, areaID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error)
+
+	// GetRouteClosures returns the closure note of each of the given routes
+	// that an approved Kaya match marks closed (see the mp_route_closures
+	// view). Open and unmatched routes are left out.
+	GetRouteClosures(ctx context.Context, routeIDs []int64) (map[int64]string, error)
+
+	// GetAreaClosures returns the closure note of each of the given areas
+	// whose Kaya-matched routes, across the area's whole subtree, are all
+	// closed. Areas with an open or no matched route are left out.
+	GetAreaClosures(ctx context.Context, areaIDs []int64) (map[int64]string, error)
 }
 
 // SearchRepository handles search operations for routes and areas.
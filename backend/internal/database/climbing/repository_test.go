@@ -8,6 +8,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/alexscott64/woulder/backend/internal/database/climbing"
+	"github.com/lib/pq"
 )
 
 // ====================
@@ -243,6 +244,54 @@ func TestPostgresRepository_GetSubareasOrderedByActivity(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_GetAreaTreeWithActivity(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{
+		"mp_area_id", "name", "parent_mp_area_id", "last_climb_at",
+		"unique_routes", "total_ticks", "days_since_climb", "has_subareas", "subarea_count",
+	}).AddRow(
+		int64(200), "Index Lower Town Wall", nil,
+		time.Date(2024, 6, 12, 9, 0, 0, 0, time.UTC),
+		30, 120, 8, true, 1,
+	).AddRow(
+		int64(300), "Morning Glory Wall", sql.NullInt64{Int64: 200, Valid: true},
+		time.Date(2024, 6, 12, 9, 0, 0, 0, time.UTC),
+		12, 48, 8, false, 0,
+	)
+
+	mock.ExpectQuery(`WITH RECURSIVE adjusted_ticks AS .* area_closure AS`).
+		WithArgs(10).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetAreaTreeWithActivity(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("GetAreaTreeWithActivity() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetAreaTreeWithActivity() returned %d areas, want 2", len(result))
+	}
+	if result[0].ParentMPAreaID != nil {
+		t.Errorf("root parent = %v, want nil", *result[0].ParentMPAreaID)
+	}
+	if result[1].ParentMPAreaID == nil || *result[1].ParentMPAreaID != 200 {
+		t.Errorf("child parent = %v, want 200", result[1].ParentMPAreaID)
+	}
+	if !result[0].HasSubareas || result[0].SubareaCount != 1 {
+		t.Errorf("root subareas = %v/%d, want true/1", result[0].HasSubareas, result[0].SubareaCount)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPostgresRepository_GetRoutesOrderedByActivity(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -253,6 +302,7 @@ func TestPostgresRepository_GetRoutesOrderedByActivity(t *testing.T) {
 	rows := sqlmock.NewRows([]string{
 		"mp_route_id", "name", "rating", "mp_area_id", "last_climb_at",
 		"days_since_climb", "user_name", "adjusted_climbed_at", "style", "comment", "area_name", "no_ticks",
+		"conditions_beta", "last_tick_sync_at",
 	}).AddRow(
 		int64(1001), "Monkey Face", "5.13a", int64(200),
 		time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC), 5,
@@ -262,6 +312,8 @@ func TestPostgresRepository_GetRoutesOrderedByActivity(t *testing.T) {
 		sql.NullString{String: "Amazing!", Valid: true},
 		sql.NullString{String: "Dihedrals", Valid: true},
 		0,
+		"{seeps,afternoon_sun}",
+		time.Date(2024, 6, 16, 8, 0, 0, 0, time.UTC),
 	)
 
 	mock.ExpectQuery(`WITH area_routes AS`).
@@ -283,6 +335,15 @@ func TestPostgresRepository_GetRoutesOrderedByActivity(t *testing.T) {
 		t.Errorf("GetRoutesOrderedByActivity() route name = %v, want Monkey Face", result[0].Name)
 	}
 
+	if got := result[0].ConditionsBeta; len(got) != 2 || got[0] != "seeps" || got[1] != "afternoon_sun" {
+		t.Errorf("GetRoutesOrderedByActivity() conditions beta = %v, want [seeps afternoon_sun]", got)
+	}
+
+	wantSync := time.Date(2024, 6, 16, 8, 0, 0, 0, time.UTC)
+	if got := result[0].LastTickSyncAt; got == nil || !got.Equal(wantSync) {
+		t.Errorf("GetRoutesOrderedByActivity() last tick sync = %v, want %v", got, wantSync)
+	}
+
 	if result[0].MostRecentTick == nil {
 		t.Fatal("GetRoutesOrderedByActivity() most recent tick should not be nil")
 	}
@@ -296,6 +357,417 @@ func TestPostgresRepository_GetRoutesOrderedByActivity(t *testing.T) {
 	}
 }
 
+// TestPostgresRepository_ActivityOrderingTieBreak verifies that the activity
+// queries break ties on recency deterministically, and that rows with equal
+// recency come back in the order the database returned them.
+func TestPostgresRepository_ActivityOrderingTieBreak(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	sameDay := time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC)
+	areaColumns := []string{
+		"mp_area_id", "name", "parent_mp_area_id", "last_climb_at",
+		"unique_routes", "total_ticks", "days_since_climb", "has_subareas", "subarea_count",
+	}
+	parent := sql.NullInt64{Int64: 100, Valid: true}
+
+	mock.ExpectQuery(`ORDER BY MAX\(adj\.adjusted_climbed_at\) DESC, total_ticks DESC, tla\.mp_area_id ASC`).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows(areaColumns).
+			AddRow(int64(202), "Zoo Wall", parent, sameDay, 10, 80, 5, false, 0).
+			AddRow(int64(201), "Alpha Wall", parent, sameDay, 5, 40, 5, false, 0).
+			AddRow(int64(203), "Beta Wall", parent, sameDay, 5, 40, 5, false, 0))
+
+	mock.ExpectQuery(`ORDER BY MAX\(adj\.adjusted_climbed_at\) DESC NULLS LAST, total_ticks DESC, sa\.mp_area_id ASC`).
+		WithArgs(int64(200), 10).
+		WillReturnRows(sqlmock.NewRows(areaColumns).
+			AddRow(int64(301), "Left", parent, sameDay, 2, 9, 5, false, 0).
+			AddRow(int64(302), "Right", parent, sameDay, 2, 9, 5, false, 0))
+
+	mock.ExpectQuery(`ORDER BY no_ticks ASC, MAX\(at\.adjusted_climbed_at\) DESC NULLS LAST, ar\.name ASC, ar\.mp_route_id ASC`).
+		WithArgs(int64(200), 10, 50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"mp_route_id", "name", "rating", "mp_area_id", "last_climb_at",
+			"days_since_climb", "user_name", "adjusted_climbed_at", "style", "comment", "area_name", "no_ticks",
+			"conditions_beta", "last_tick_sync_at",
+		}).
+			AddRow(int64(1001), "Arete", "V3", int64(200), sameDay, 5, nil, nil, nil, nil, nil, 0, nil, nil).
+			AddRow(int64(1002), "Arete", "V5", int64(200), sameDay, 5, nil, nil, nil, nil, nil, 0, nil, nil))
+
+	repo := climbing.NewPostgresRepository(db)
+	ctx := context.Background()
+
+	areas, err := repo.Activity().GetAreasOrderedByActivity(ctx, 10)
+	if err != nil {
+		t.Fatalf("GetAreasOrderedByActivity() error = %v", err)
+	}
+	if areas[0].MPAreaID != 202 || areas[1].MPAreaID != 201 || areas[2].MPAreaID != 203 {
+		t.Errorf("GetAreasOrderedByActivity() order = [%d %d %d], want [202 201 203]",
+			areas[0].MPAreaID, areas[1].MPAreaID, areas[2].MPAreaID)
+	}
+
+	subareas, err := repo.Activity().GetSubareasOrderedByActivity(ctx, int64(200), 10)
+	if err != nil {
+		t.Fatalf("GetSubareasOrderedByActivity() error = %v", err)
+	}
+	if subareas[0].MPAreaID != 301 || subareas[1].MPAreaID != 302 {
+		t.Errorf("GetSubareasOrderedByActivity() order = [%d %d], want [301 302]", subareas[0].MPAreaID, subareas[1].MPAreaID)
+	}
+
+	routes, err := repo.Activity().GetRoutesOrderedByActivity(ctx, int64(200), 10, 50)
+	if err != nil {
+		t.Fatalf("GetRoutesOrderedByActivity() error = %v", err)
+	}
+	if routes[0].MPRouteID != 1001 || routes[1].MPRouteID != 1002 {
+		t.Errorf("GetRoutesOrderedByActivity() order = [%d %d], want [1001 1002]", routes[0].MPRouteID, routes[1].MPRouteID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetTrendingRoutes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC)
+	locationID := 10
+
+	rows := sqlmock.NewRows([]string{
+		"mp_route_id", "name", "rating", "mp_area_id", "area_name", "location_id", "tick_count", "last_climb_at",
+	}).AddRow(
+		int64(1001), "Monkey Face", "5.13a", int64(200), "Dihedrals", 10, 14,
+		time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC),
+	).AddRow(
+		int64(1002), "Chain Reaction", "5.12c", int64(201), "Monkey Face Area", nil, 9,
+		time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC),
+	)
+
+	mock.ExpectQuery(`ORDER BY tick_count DESC, last_climb_at DESC, r\.mp_route_id ASC`).
+		WithArgs(since, &locationID, 20).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetTrendingRoutes(context.Background(), since, &locationID, 20)
+
+	if err != nil {
+		t.Fatalf("GetTrendingRoutes() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetTrendingRoutes() returned %d routes, want 2", len(result))
+	}
+
+	if result[0].TickCount != 14 || result[0].AreaName != "Dihedrals" {
+		t.Errorf("GetTrendingRoutes() first route = %+v, want 14 ticks in Dihedrals", result[0])
+	}
+
+	if result[0].LocationID == nil || *result[0].LocationID != 10 {
+		t.Errorf("GetTrendingRoutes() first route location = %v, want 10", result[0].LocationID)
+	}
+
+	if result[1].LocationID != nil {
+		t.Errorf("GetTrendingRoutes() second route location = %v, want nil", *result[1].LocationID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetFeaturedRouteCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2024, 5, 16, 7, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 6, 15, 7, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"mp_route_id", "name", "rating", "route_type", "mp_area_id", "area_name", "location_id", "mp_rating", "recent_ticks",
+	}).AddRow(
+		int64(1001), "Monkey Face", "5.13a", "Sport", int64(200), "Dihedrals", 10, 3.8, 0,
+	).AddRow(
+		int64(1002), "Chain Reaction", "5.12c", "Sport", int64(201), "Monkey Face Area", 10, nil, 6,
+	)
+
+	mock.ExpectQuery(`HAVING COALESCE\(r\.mp_rating, 0\) >= 3 OR COUNT\(t\.id\) > 0`).
+		WithArgs(nil, since, until, 200).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetFeaturedRouteCandidates(context.Background(), nil, since, until, 200)
+
+	if err != nil {
+		t.Fatalf("GetFeaturedRouteCandidates() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetFeaturedRouteCandidates() returned %d routes, want 2", len(result))
+	}
+
+	if result[0].MPRating == nil || *result[0].MPRating != 3.8 || result[0].LocationID != 10 {
+		t.Errorf("GetFeaturedRouteCandidates() first route = %+v, want 3.8 stars at location 10", result[0])
+	}
+
+	if result[1].MPRating != nil || result[1].RecentTicks != 6 {
+		t.Errorf("GetFeaturedRouteCandidates() second route = %+v, want no stars and 6 recent ticks", result[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetSimilarRouteCandidates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"mp_route_id", "name", "rating", "route_type", "mp_area_id", "area_name", "location_id", "mp_rating", "distance_km", "tick_count",
+	}).AddRow(
+		int64(1001), "Hobbit Hole", "V4", "Boulder", int64(200), "Gold Bar", 10, 3.2, 0.0, 14,
+	).AddRow(
+		int64(1002), "Trailside", "V5", "Boulder", int64(201), "Index Boulders", nil, nil, nil, 0,
+	)
+
+	mock.ExpectQuery(`WITH src AS`).
+		WithArgs(int64(1001), 25000.0, since, 500).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetSimilarRouteCandidates(context.Background(), 1001, 25000, since, 500)
+
+	if err != nil {
+		t.Fatalf("GetSimilarRouteCandidates() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetSimilarRouteCandidates() returned %d routes, want 2", len(result))
+	}
+
+	first := result[0]
+	if first.LocationID == nil || *first.LocationID != 10 || first.DistanceKm == nil || first.TickCount != 14 {
+		t.Errorf("GetSimilarRouteCandidates() first route = %+v, want location 10, distance 0 and 14 ticks", first)
+	}
+
+	second := result[1]
+	if second.LocationID != nil || second.MPRating != nil || second.DistanceKm != nil {
+		t.Errorf("GetSimilarRouteCandidates() second route = %+v, want no location, stars or distance", second)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+func TestPostgresRepository_GetRecentlyDiscoveredRoutes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{
+		"mp_route_id", "name", "rating", "route_type", "mp_area_id", "area_name", "location_id", "location_name", "discovered_at",
+	}).AddRow(
+		int64(3001), "Fresh Prince", "V5", "Boulder", int64(200), "Riverside", 10, "Gold Bar",
+		time.Date(2024, 6, 15, 10, 30, 0, 0, time.UTC),
+	).AddRow(
+		int64(3002), "Unknown Line", "", "", int64(201), "Far Away Area", nil, nil,
+		time.Date(2024, 6, 14, 9, 0, 0, 0, time.UTC),
+	)
+
+	mock.ExpectQuery(`WHERE r\.discovered_at >= \$1`).
+		WithArgs(since, 20).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetRecentlyDiscoveredRoutes(context.Background(), since, 20)
+
+	if err != nil {
+		t.Fatalf("GetRecentlyDiscoveredRoutes() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetRecentlyDiscoveredRoutes() returned %d routes, want 2", len(result))
+	}
+
+	if result[0].LocationName == nil || *result[0].LocationName != "Gold Bar" || result[0].Rating != "V5" {
+		t.Errorf("GetRecentlyDiscoveredRoutes() first route = %+v, want V5 at Gold Bar", result[0])
+	}
+
+	if result[1].LocationID != nil || result[1].LocationName != nil {
+		t.Errorf("GetRecentlyDiscoveredRoutes() second route should have no location, got %+v", result[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetDailyTickCounts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"local_date", "tick_count", "route_count"}).
+		AddRow("2025-03-09", 14, 9).
+		AddRow("2025-03-11", 2, 1)
+
+	mock.ExpectQuery(`AT TIME ZONE 'America/Los_Angeles'\)::date BETWEEN \$2::date AND \$3::date`).
+		WithArgs(2, "2025-03-01", "2025-03-31").
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetDailyTickCounts(context.Background(), 2, "2025-03-01", "2025-03-31")
+
+	if err != nil {
+		t.Fatalf("GetDailyTickCounts() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetDailyTickCounts() returned %d days, want 2", len(result))
+	}
+
+	if result[0].Date != "2025-03-09" || result[0].TickCount != 14 || result[0].RouteCount != 9 {
+		t.Errorf("GetDailyTickCounts() first day = %+v, want 2025-03-09 with 14 ticks on 9 routes", result[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetAreaActivitySeries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"bucket_start", "tick_count"}).
+		AddRow("2025-03-03", 0).
+		AddRow("2025-03-10", 7)
+
+	mock.ExpectQuery(`WITH RECURSIVE subtree AS .* generate_series\(`).
+		WithArgs(int64(105), "week", "2025-03-03", "2025-03-16").
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetAreaActivitySeries(context.Background(), 105, "week", "2025-03-03", "2025-03-16")
+
+	if err != nil {
+		t.Fatalf("GetAreaActivitySeries() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetAreaActivitySeries() returned %d buckets, want 2", len(result))
+	}
+
+	if result[0].BucketStart != "2025-03-03" || result[0].TickCount != 0 {
+		t.Errorf("GetAreaActivitySeries() first bucket = %+v, want 2025-03-03 with 0 ticks", result[0])
+	}
+	if result[1].TickCount != 7 {
+		t.Errorf("GetAreaActivitySeries() second bucket tick count = %d, want 7", result[1].TickCount)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetRouteClosures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mp_route_id", "closure_note"}).
+		AddRow(int64(101), "Raptor closure Feb 1 - Jul 31").
+		AddRow(int64(102), nil)
+
+	mock.ExpectQuery(`FROM woulder.mp_route_closures\s+WHERE mp_route_id = ANY\(\$1\)\s+AND is_closed`).
+		WithArgs(pq.Array([]int64{101, 102, 103})).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetRouteClosures(context.Background(), []int64{101, 102, 103})
+
+	if err != nil {
+		t.Fatalf("GetRouteClosures() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetRouteClosures() returned %d routes, want 2", len(result))
+	}
+
+	if result[101] != "Raptor closure Feb 1 - Jul 31" {
+		t.Errorf("GetRouteClosures()[101] = %q, want the Kaya note", result[101])
+	}
+
+	if note, ok := result[102]; !ok || note != "" {
+		t.Errorf("GetRouteClosures()[102] = %q, %v; want closed with empty note", note, ok)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetAreaClosures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"root_id", "closure_note"}).
+		AddRow(int64(201), "Closed on Kaya: Upper Wall")
+
+	mock.ExpectQuery(`HAVING bool_and\(c.is_closed\)`).
+		WithArgs(pq.Array([]int64{201, 202})).
+		WillReturnRows(rows)
+
+	repo := climbing.NewPostgresRepository(db)
+	result, err := repo.Activity().GetAreaClosures(context.Background(), []int64{201, 202})
+
+	if err != nil {
+		t.Fatalf("GetAreaClosures() error = %v", err)
+	}
+
+	if len(result) != 1 || result[201] != "Closed on Kaya: Upper Wall" {
+		t.Errorf("GetAreaClosures() = %v, want only area 201 closed", result)
+	}
+
+	// No IDs: no query.
+	if result, err := repo.Activity().GetAreaClosures(context.Background(), nil); err != nil || len(result) != 0 {
+		t.Errorf("GetAreaClosures(nil) = %v, %v; want empty, nil", result, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPostgresRepository_GetRecentTicksForRoute(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -6,10 +6,9 @@ import (
 	_ "embed"
 	"fmt"
 	"log"
-	"os"
-	"strconv"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/config"
 	"github.com/alexscott64/woulder/backend/internal/database/analytics"
 	"github.com/alexscott64/woulder/backend/internal/database/areas"
 	"github.com/alexscott64/woulder/backend/internal/database/auth"
@@ -34,37 +33,15 @@ type Database struct {
 	conn *sql.DB
 }
 
-func New() (*Database, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
-	sslmode := os.Getenv("DB_SSLMODE")
-
-	// Connection pool settings with sensible defaults
-	maxOpenConns := getEnvAsInt("DB_MAX_OPEN_CONNS", 25)
-	maxIdleConns := getEnvAsInt("DB_MAX_IDLE_CONNS", 5)
-	connMaxLifetimeMin := getEnvAsInt("DB_CONN_MAX_LIFETIME_MINUTES", 5)
-	connMaxIdleTimeMin := getEnvAsInt("DB_CONN_MAX_IDLE_TIME_MINUTES", 5)
-
-	if port == "" {
-		port = "5432"
-	}
-	if sslmode == "" {
-		sslmode = "require"
-	}
-
-	if host == "" || user == "" || password == "" || dbname == "" {
-		return nil, fmt.Errorf("missing required database configuration")
+// New opens the database described by cfg, configures the connection pool,
+// and runs the initial schema setup if the database is empty. cfg is
+// normally config.Load().Database, which has already been validated.
+func New(cfg config.DatabaseConfig) (*Database, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("missing required database configuration: %w", err)
 	}
 
-	// Build connection string with pool_mode support for PgBouncer
-	connStr := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		host, port, user, password, dbname, sslmode,
-	)
-
+	connStr := cfg.ConnectionString()
 	db, err := sql.Open("postgres", connStr)
 	if err != nil {
 		return nil, err
@@ -79,27 +56,27 @@ func New() (*Database, error) {
 	// - Default: 25 (suitable for most applications)
 	// - Increase for high-concurrency workloads
 	// - If using PgBouncer, this should match or be less than PgBouncer's pool size
-	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
 
 	// MaxIdleConns: Maximum number of connections in the idle connection pool
 	// - Default: 5 (keeps connections ready for reuse)
 	// - Should be <= MaxOpenConns
 	// - Higher values reduce connection establishment overhead
-	db.SetMaxIdleConns(maxIdleConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
 
 	// ConnMaxLifetime: Maximum amount of time a connection may be reused
 	// - Default: 5 minutes (prevents stale connections)
 	// - Important when using load balancers or connection poolers
 	// - Set lower if firewall/proxy has aggressive timeouts
-	db.SetConnMaxLifetime(time.Duration(connMaxLifetimeMin) * time.Minute)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
 	// ConnMaxIdleTime: Maximum amount of time a connection may be idle
 	// - Default: 5 minutes (closes unused connections)
 	// - Helps free up database resources when load is low
-	db.SetConnMaxIdleTime(time.Duration(connMaxIdleTimeMin) * time.Minute)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	log.Printf("Database connection pool configured: MaxOpen=%d, MaxIdle=%d, MaxLifetime=%dm, MaxIdleTime=%dm",
-		maxOpenConns, maxIdleConns, connMaxLifetimeMin, connMaxIdleTimeMin)
+	log.Printf("Database connection pool configured: MaxOpen=%d, MaxIdle=%d, MaxLifetime=%v, MaxIdleTime=%v",
+		cfg.MaxOpenConns, cfg.MaxIdleConns, cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime)
 
 	database := &Database{conn: db}
 
@@ -118,20 +95,6 @@ func New() (*Database, error) {
 	return database, nil
 }
 
-// getEnvAsInt retrieves an environment variable as an integer with a default fallback
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := os.Getenv(key)
-	if valueStr == "" {
-		return defaultValue
-	}
-	value, err := strconv.Atoi(valueStr)
-	if err != nil {
-		log.Printf("Warning: Invalid value for %s, using default %d", key, defaultValue)
-		return defaultValue
-	}
-	return value
-}
-
 func (db *Database) needsInitialization() (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM information_schema.schemata WHERE schema_name = 'woulder')`
@@ -152,6 +115,17 @@ func (db *Database) Ping(ctx context.Context) error {
 	return db.conn.PingContext(ctx)
 }
 
+// SchemaVersion returns the latest migration applied by cmd/migrate, or 0 if
+// none has been.
+func (db *Database) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := db.conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
 // Conn returns the underlying database connection for direct SQL access.
 // Used by monitoring and other utilities that need raw database access.
 func (db *Database) Conn() *sql.DB {
@@ -493,12 +467,12 @@ func (db *Database) GetLocationRoutesDueForSync(ctx context.Context, syncType st
 }
 
 // GetRoutesDueForTickSync delegates to MountainProject().Sync().GetRoutesDueForTickSync()
-func (db *Database) GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error) {
+func (db *Database) GetRoutesDueForTickSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	return db.MountainProject().Sync().GetRoutesDueForTickSync(ctx, priority)
 }
 
 // GetRoutesDueForCommentSync delegates to MountainProject().Sync().GetRoutesDueForCommentSync()
-func (db *Database) GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error) {
+func (db *Database) GetRoutesDueForCommentSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	return db.MountainProject().Sync().GetRoutesDueForCommentSync(ctx, priority)
 }
 
@@ -569,7 +543,7 @@ func (db *Database) SearchRoutesInLocation(ctx context.Context, locationID int,
 // Heat map wrapper methods
 
 // GetHeatMapData delegates to HeatMap().GetData()
-func (db *Database) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+func (db *Database) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 	var heatmapBounds *heatmap.GeoBounds
 	if bounds != nil {
 		heatmapBounds = &heatmap.GeoBounds{
@@ -579,7 +553,7 @@ func (db *Database) GetHeatMapData(ctx context.Context, startDate, endDate time.
 			MaxLon: bounds.MaxLon,
 		}
 	}
-	return db.HeatMap().GetHeatMapData(ctx, startDate, endDate, heatmapBounds, minActivity, limit, routeTypes, lightweight, gradeOrders)
+	return db.HeatMap().GetHeatMapData(ctx, startDate, endDate, heatmapBounds, minActivity, limit, routeTypes, lightweight, gradeOrders, excludeClosed)
 }
 
 // GetAreaActivityDetail delegates to HeatMap().GetAreaActivityDetail()
@@ -588,14 +562,14 @@ func (db *Database) GetAreaActivityDetail(ctx context.Context, areaID int64, sta
 }
 
 // GetRoutesByBounds delegates to HeatMap().GetRoutesByBounds()
-func (db *Database) GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error) {
+func (db *Database) GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error) {
 	heatmapBounds := heatmap.GeoBounds{
 		MinLat: bounds.MinLat,
 		MaxLat: bounds.MaxLat,
 		MinLon: bounds.MinLon,
 		MaxLon: bounds.MaxLon,
 	}
-	return db.HeatMap().GetRoutesByBounds(ctx, heatmapBounds, startDate, endDate, limit)
+	return db.HeatMap().GetRoutesByBounds(ctx, heatmapBounds, startDate, endDate, limit, excludeClosed)
 }
 
 // GetRouteTicksInDateRange delegates to HeatMap().GetRouteTicksInDateRange()
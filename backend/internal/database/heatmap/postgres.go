@@ -47,6 +47,7 @@ func (r *PostgresRepository) GetHeatMapData(
 	routeTypes []string,
 	lightweight bool,
 	gradeOrders []int,
+	excludeClosed bool,
 ) ([]models.HeatMapPoint, error) {
 	// Validate bounds if provided
 	if bounds != nil {
@@ -87,6 +88,7 @@ func (r *PostgresRepository) GetHeatMapData(
 		routeTypesParam,
 		minActivity, limit,
 		gradeOrdersParam,
+		excludeClosed,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query heat map data: %w", err)
@@ -131,6 +133,8 @@ func (r *PostgresRepository) GetAreaActivityDetail(
 		&detail.ParentMPAreaID,
 		&detail.Latitude,
 		&detail.Longitude,
+		pq.Array(&detail.ConditionsBeta),
+		&detail.LastSyncedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("area not found: %w", dberrors.WrapNotFound(err))
@@ -297,6 +301,7 @@ func (r *PostgresRepository) GetRoutesByBounds(
 	bounds GeoBounds,
 	startDate, endDate time.Time,
 	limit int,
+	excludeClosed bool,
 ) ([]models.RouteActivity, error) {
 	if err := bounds.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid bounds: %w", err)
@@ -307,6 +312,7 @@ func (r *PostgresRepository) GetRoutesByBounds(
 		bounds.MinLon, bounds.MaxLon,
 		startDate, endDate,
 		limit,
+		excludeClosed,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query routes by bounds: %w", err)
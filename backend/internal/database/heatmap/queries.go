@@ -9,6 +9,7 @@ const (
 	// Used for initial map loads and low zoom levels where speed is critical.
 	// UPDATED: Now includes both MP ticks and Kaya ascents for comprehensive activity data.
 	// Supports grade_order array filtering via $10 (allowed order values).
+	// $11 drops routes Kaya marks closed (see the mp_route_closures view).
 	queryHeatMapDataLightweight = `
 		WITH combined_activity AS (
 			-- MP ticks
@@ -23,14 +24,16 @@ const (
 			JOIN woulder.mp_ticks t ON t.mp_route_id = r.mp_route_id
 			WHERE t.climbed_at >= $1
 				AND t.climbed_at <= $2
-				AND a.latitude IS NOT NULL
-				AND a.longitude IS NOT NULL
-				AND ($3::float IS NULL OR (
-					a.latitude BETWEEN $3 AND $4
-					AND a.longitude BETWEEN $5 AND $6
-				))
+				AND a.geog IS NOT NULL
+				AND ($3::float IS NULL OR
+					a.geog::geometry && ST_MakeEnvelope($5, $3, $6, $4, 4326)
+				)
 				AND ($7::text[] IS NULL OR r.route_type = ANY($7))
 					AND ($10::int[] IS NULL OR r.grade_order = ANY($10))
+					AND NOT ($11::boolean AND EXISTS (
+						SELECT 1 FROM woulder.mp_route_closures c
+						WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+					))
 				
 				UNION ALL
 				
@@ -49,13 +52,12 @@ const (
 				JOIN woulder.kaya_users ku ON ku.kaya_user_id = ka.kaya_user_id
 				WHERE ka.date >= $1
 					AND ka.date <= $2
-					AND a.latitude IS NOT NULL
-					AND a.longitude IS NOT NULL
-					AND ($3::float IS NULL OR (
-						a.latitude BETWEEN $3 AND $4
-						AND a.longitude BETWEEN $5 AND $6
-					))
+					AND a.geog IS NOT NULL
+					AND ($3::float IS NULL OR
+						a.geog::geometry && ST_MakeEnvelope($5, $3, $6, $4, 4326)
+					)
 					AND mr.match_confidence >= 0.75
+					AND mr.match_status = 'approved'
 					AND r.route_type ILIKE '%boulder%'
 					AND r.route_type NOT ILIKE '%ice%'
 					AND r.route_type NOT ILIKE '%mixed%'
@@ -63,6 +65,10 @@ const (
 					AND r.route_type NOT ILIKE '%alpine%'
 					AND ($7::text[] IS NULL OR r.route_type = ANY($7))
 					AND ($10::int[] IS NULL OR r.grade_order = ANY($10))
+					AND NOT ($11::boolean AND EXISTS (
+						SELECT 1 FROM woulder.mp_route_closures c
+						WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+					))
 		)
 		SELECT
 			a.mp_area_id,
@@ -87,6 +93,7 @@ const (
 	// Used for high zoom levels and detailed area views.
 	// UPDATED: Now includes both MP ticks and Kaya ascents for comprehensive activity data.
 	// Supports grade_order array filtering via $10 (allowed order values).
+	// $11 drops routes Kaya marks closed (see the mp_route_closures view).
 	queryHeatMapDataFull = `
 		WITH combined_activity AS (
 			-- MP ticks
@@ -101,14 +108,16 @@ const (
 			JOIN woulder.mp_ticks t ON t.mp_route_id = r.mp_route_id
 			WHERE t.climbed_at >= $1
 				AND t.climbed_at <= $2
-				AND a.latitude IS NOT NULL
-				AND a.longitude IS NOT NULL
-				AND ($3::float IS NULL OR (
-					a.latitude BETWEEN $3 AND $4
-					AND a.longitude BETWEEN $5 AND $6
-				))
+				AND a.geog IS NOT NULL
+				AND ($3::float IS NULL OR
+					a.geog::geometry && ST_MakeEnvelope($5, $3, $6, $4, 4326)
+				)
 				AND ($7::text[] IS NULL OR r.route_type = ANY($7))
 					AND ($10::int[] IS NULL OR r.grade_order = ANY($10))
+					AND NOT ($11::boolean AND EXISTS (
+						SELECT 1 FROM woulder.mp_route_closures c
+						WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+					))
 				
 				UNION ALL
 				
@@ -127,13 +136,12 @@ const (
 				JOIN woulder.kaya_users ku ON ku.kaya_user_id = ka.kaya_user_id
 				WHERE ka.date >= $1
 					AND ka.date <= $2
-					AND a.latitude IS NOT NULL
-					AND a.longitude IS NOT NULL
-					AND ($3::float IS NULL OR (
-						a.latitude BETWEEN $3 AND $4
-						AND a.longitude BETWEEN $5 AND $6
-					))
+					AND a.geog IS NOT NULL
+					AND ($3::float IS NULL OR
+						a.geog::geometry && ST_MakeEnvelope($5, $3, $6, $4, 4326)
+					)
 					AND mr.match_confidence >= 0.75
+					AND mr.match_status = 'approved'
 					AND r.route_type ILIKE '%boulder%'
 					AND r.route_type NOT ILIKE '%ice%'
 					AND r.route_type NOT ILIKE '%mixed%'
@@ -141,6 +149,10 @@ const (
 					AND r.route_type NOT ILIKE '%alpine%'
 					AND ($7::text[] IS NULL OR r.route_type = ANY($7))
 					AND ($10::int[] IS NULL OR r.grade_order = ANY($10))
+					AND NOT ($11::boolean AND EXISTS (
+						SELECT 1 FROM woulder.mp_route_closures c
+						WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+					))
 		)
 		SELECT
 			a.mp_area_id,
@@ -171,8 +183,12 @@ const (
 			a.name,
 			a.parent_mp_area_id,
 			a.latitude,
-			a.longitude
+			a.longitude,
+			cb.tags AS conditions_beta,
+			a.last_synced_at
 		FROM woulder.mp_areas a
+		LEFT JOIN woulder.mp_conditions_beta cb
+			ON cb.target_type = 'area' AND cb.mp_id = a.mp_area_id
 		WHERE a.mp_area_id = $1
 	`
 
@@ -211,6 +227,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 				AND ($4::text[] IS NULL OR $4::text[] = '{}' OR r.route_type = ANY($4))
 		)
 		SELECT
@@ -262,6 +279,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 				AND ($4::text[] IS NULL OR $4::text[] = '{}' OR r.route_type = ANY($4))
 		)
 		SELECT * FROM combined_ticks
@@ -325,6 +343,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 				AND ($4::text[] IS NULL OR $4::text[] = '{}' OR r.route_type = ANY($4))
 		)
 		SELECT
@@ -372,6 +391,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 				AND ($4::text[] IS NULL OR $4::text[] = '{}' OR r.route_type = ANY($4))
 		)
 		SELECT
@@ -389,6 +409,7 @@ const (
 	// queryRoutesByBounds retrieves routes within geographic bounds with activity.
 	// Used for precision route-level clustering at high zoom levels.
 	// Includes both MP ticks and Kaya ascents.
+	// $8 drops routes Kaya marks closed (see the mp_route_closures view).
 	queryRoutesByBounds = `
 		WITH combined_activity AS (
 			-- MP ticks
@@ -405,12 +426,14 @@ const (
 			FROM woulder.mp_routes r
 			JOIN woulder.mp_ticks t ON r.mp_route_id = t.mp_route_id
 			JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
-			WHERE r.latitude IS NOT NULL
-				AND r.longitude IS NOT NULL
-				AND r.latitude BETWEEN $1 AND $2
-				AND r.longitude BETWEEN $3 AND $4
+			WHERE r.geog IS NOT NULL
+				AND r.geog::geometry && ST_MakeEnvelope($3, $1, $4, $2, 4326)
 				AND t.climbed_at >= $5
 				AND t.climbed_at <= $6
+				AND NOT ($8::boolean AND EXISTS (
+					SELECT 1 FROM woulder.mp_route_closures c
+					WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+				))
 			
 			UNION ALL
 			
@@ -430,13 +453,16 @@ const (
 			JOIN woulder.kaya_climbs kc ON kc.slug = mr.kaya_climb_id
 			JOIN woulder.kaya_ascents ka ON ka.kaya_climb_slug = kc.slug
 			JOIN woulder.mp_areas a ON r.mp_area_id = a.mp_area_id
-			WHERE r.latitude IS NOT NULL
-				AND r.longitude IS NOT NULL
-				AND r.latitude BETWEEN $1 AND $2
-				AND r.longitude BETWEEN $3 AND $4
+			WHERE r.geog IS NOT NULL
+				AND r.geog::geometry && ST_MakeEnvelope($3, $1, $4, $2, 4326)
 				AND ka.date >= $5
 				AND ka.date <= $6
+				AND NOT ($8::boolean AND EXISTS (
+					SELECT 1 FROM woulder.mp_route_closures c
+					WHERE c.mp_route_id = r.mp_route_id AND c.is_closed
+				))
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 		)
 		SELECT
 			mp_route_id,
@@ -495,6 +521,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 				AND ($5::text[] IS NULL OR $5::text[] = '{}' OR r.route_type = ANY($5))
 		)
 		SELECT * FROM combined_ticks
@@ -535,6 +562,7 @@ const (
 				AND ka.date >= $2
 				AND ka.date <= $3
 				AND mr.match_confidence >= 0.60
+				AND mr.match_status = 'approved'
 		)
 		SELECT
 			r.mp_route_id,
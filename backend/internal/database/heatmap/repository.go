@@ -25,6 +25,7 @@ type Repository interface {
 	// Supports lightweight mode for clustering performance with minimal data.
 	// Route type filtering allows boulder/sport/trad specific visualizations.
 	// Grade order filtering allows an array of allowed grade_order values (nil = no filter).
+	// excludeClosed leaves out activity on routes Kaya marks closed.
 	// Results are ordered by activity (tick count) descending.
 	GetHeatMapData(
 		ctx context.Context,
@@ -34,6 +35,7 @@ type Repository interface {
 		routeTypes []string,
 		lightweight bool,
 		gradeOrders []int,
+		excludeClosed bool,
 	) ([]models.HeatMapPoint, error)
 
 	// GetAreaActivityDetail returns comprehensive activity data for a specific area.
@@ -48,12 +50,14 @@ type Repository interface {
 
 	// GetRoutesByBounds returns routes within geographic bounds with activity.
 	// Used for precision route-level clustering at high zoom levels.
+	// excludeClosed leaves out routes Kaya marks closed.
 	// Results are ordered by tick count descending.
 	GetRoutesByBounds(
 		ctx context.Context,
 		bounds GeoBounds,
 		startDate, endDate time.Time,
 		limit int,
+		excludeClosed bool,
 	) ([]models.RouteActivity, error)
 
 	// GetRouteTicksInDateRange returns all ticks for a specific route within a date range.
@@ -36,11 +36,11 @@ func TestPostgresRepository_GetHeatMapData_Lightweight(t *testing.T) {
 
 	// Use regex that matches the query structure regardless of comments/whitespace
 	mock.ExpectQuery(`SELECT\s+a\.mp_area_id`).
-		WithArgs(startDate, endDate, nil, nil, nil, nil, nil, 10, 100, nil).
+		WithArgs(startDate, endDate, nil, nil, nil, nil, nil, 10, 100, nil, false).
 		WillReturnRows(rows)
 
 	repo := heatmap.NewPostgresRepository(db)
-	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, nil, 10, 100, nil, true, nil)
+	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, nil, 10, 100, nil, true, nil, false)
 
 	if err != nil {
 		t.Errorf("GetHeatMapData() error = %v", err)
@@ -93,11 +93,11 @@ func TestPostgresRepository_GetHeatMapData_Full(t *testing.T) {
 
 	// Use regex that matches the query structure
 	mock.ExpectQuery(`SELECT\s+a\.mp_area_id`).
-		WithArgs(startDate, endDate, nil, nil, nil, nil, nil, 5, 50, nil).
+		WithArgs(startDate, endDate, nil, nil, nil, nil, nil, 5, 50, nil, false).
 		WillReturnRows(rows)
 
 	repo := heatmap.NewPostgresRepository(db)
-	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, nil, 5, 50, nil, false, nil)
+	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, nil, 5, 50, nil, false, nil, false)
 
 	if err != nil {
 		t.Errorf("GetHeatMapData() error = %v", err)
@@ -148,11 +148,11 @@ func TestPostgresRepository_GetHeatMapData_WithBounds(t *testing.T) {
 	)
 
 	mock.ExpectQuery(`SELECT\s+a\.mp_area_id`).
-		WithArgs(startDate, endDate, 36.0, 37.0, -116.0, -115.0, nil, 1, 100, nil).
+		WithArgs(startDate, endDate, 36.0, 37.0, -116.0, -115.0, nil, 1, 100, nil, false).
 		WillReturnRows(rows)
 
 	repo := heatmap.NewPostgresRepository(db)
-	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, bounds, 1, 100, nil, true, nil)
+	result, err := repo.GetHeatMapData(context.Background(), startDate, endDate, bounds, 1, 100, nil, true, nil, false)
 
 	if err != nil {
 		t.Errorf("GetHeatMapData() error = %v", err)
@@ -190,7 +190,7 @@ func TestPostgresRepository_GetHeatMapData_InvalidBounds(t *testing.T) {
 	}
 
 	repo := heatmap.NewPostgresRepository(db)
-	_, err = repo.GetHeatMapData(context.Background(), startDate, endDate, bounds, 1, 100, nil, true, nil)
+	_, err = repo.GetHeatMapData(context.Background(), startDate, endDate, bounds, 1, 100, nil, true, nil, false)
 
 	if err == nil {
 		t.Error("GetHeatMapData() expected error for invalid bounds, got nil")
@@ -214,14 +214,16 @@ func TestPostgresRepository_GetAreaActivityDetail(t *testing.T) {
 
 	// Mock area info query
 	areaRows := sqlmock.NewRows([]string{
-		"mp_area_id", "name", "parent_mp_area_id", "latitude", "longitude",
+		"mp_area_id", "name", "parent_mp_area_id", "latitude", "longitude", "conditions_beta", "last_synced_at",
 	}).AddRow(
 		areaID, "Smith Rock", sql.NullInt64{Int64: 100, Valid: true},
 		sql.NullFloat64{Float64: 44.3672, Valid: true},
 		sql.NullFloat64{Float64: -121.1408, Valid: true},
+		"{dries_fast,hot_in_summer}",
+		time.Date(2024, 6, 14, 3, 0, 0, 0, time.UTC),
 	)
 
-	mock.ExpectQuery(`SELECT\s+a\.mp_area_id(.+)FROM woulder\.mp_areas a\s+WHERE`).
+	mock.ExpectQuery(`SELECT\s+a\.mp_area_id(.+)FROM woulder\.mp_areas a\s+LEFT JOIN woulder\.mp_conditions_beta cb(.+)WHERE`).
 		WithArgs(areaID).
 		WillReturnRows(areaRows)
 
@@ -324,6 +326,15 @@ func TestPostgresRepository_GetAreaActivityDetail(t *testing.T) {
 		t.Errorf("GetAreaActivityDetail() top routes = %d, want 1", len(result.TopRoutes))
 	}
 
+	if got := result.ConditionsBeta; len(got) != 2 || got[0] != "dries_fast" || got[1] != "hot_in_summer" {
+		t.Errorf("GetAreaActivityDetail() conditions beta = %v, want [dries_fast hot_in_summer]", got)
+	}
+
+	wantSync := time.Date(2024, 6, 14, 3, 0, 0, 0, time.UTC)
+	if got := result.LastSyncedAt; got == nil || !got.Equal(wantSync) {
+		t.Errorf("GetAreaActivityDetail() last synced = %v, want %v", got, wantSync)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
@@ -342,10 +353,10 @@ func TestPostgresRepository_GetAreaActivityDetail_NotFound(t *testing.T) {
 
 	// Area not found
 	areaRows := sqlmock.NewRows([]string{
-		"mp_area_id", "name", "parent_mp_area_id", "latitude", "longitude",
+		"mp_area_id", "name", "parent_mp_area_id", "latitude", "longitude", "conditions_beta", "last_synced_at",
 	})
 
-	mock.ExpectQuery(`SELECT\s+a\.mp_area_id(.+)FROM woulder\.mp_areas a\s+WHERE`).
+	mock.ExpectQuery(`SELECT\s+a\.mp_area_id(.+)FROM woulder\.mp_areas a\s+LEFT JOIN woulder\.mp_conditions_beta cb(.+)WHERE`).
 		WithArgs(areaID).
 		WillReturnRows(areaRows)
 
@@ -388,12 +399,12 @@ func TestPostgresRepository_GetRoutesByBounds(t *testing.T) {
 		int64(123), "Smith Rock",
 	)
 
-	mock.ExpectQuery(`SELECT\s+r\.mp_route_id(.+)r\.latitude BETWEEN`).
-		WithArgs(44.0, 45.0, -122.0, -121.0, startDate, endDate, 50).
+	mock.ExpectQuery(`SELECT\s+r\.mp_route_id(.+)r\.geog::geometry && ST_MakeEnvelope`).
+		WithArgs(44.0, 45.0, -122.0, -121.0, startDate, endDate, 50, true).
 		WillReturnRows(rows)
 
 	repo := heatmap.NewPostgresRepository(db)
-	result, err := repo.GetRoutesByBounds(context.Background(), bounds, startDate, endDate, 50)
+	result, err := repo.GetRoutesByBounds(context.Background(), bounds, startDate, endDate, 50, true)
 
 	if err != nil {
 		t.Errorf("GetRoutesByBounds() error = %v", err)
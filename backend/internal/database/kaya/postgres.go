@@ -255,6 +255,80 @@ func (r *PostgresRepository) GetLocationBySlug(ctx context.Context, slug string)
 	return &loc, nil
 }
 
+func (r *PostgresRepository) GetLocationForWoulderLocation(ctx context.Context, woulderLocationID int) (*models.KayaLocation, error) {
+	var loc models.KayaLocation
+	err := r.db.QueryRowContext(ctx, queryGetLocationForWoulderLocation, woulderLocationID).Scan(
+		&loc.ID,
+		&loc.KayaLocationID,
+		&loc.Slug,
+		&loc.Name,
+		&loc.Latitude,
+		&loc.Longitude,
+		&loc.PhotoURL,
+		&loc.Description,
+		&loc.LocationTypeID,
+		&loc.LocationTypeName,
+		&loc.ParentLocationID,
+		&loc.ParentLocationSlug,
+		&loc.ParentLocationName,
+		&loc.ClimbCount,
+		&loc.BoulderCount,
+		&loc.RouteCount,
+		&loc.AscentCount,
+		&loc.IsGBModeratedBouldering,
+		&loc.IsGBModeratedRoutes,
+		&loc.IsAccessSensitive,
+		&loc.IsClosed,
+		&loc.HasMapsDisabled,
+		&loc.ClosedDate,
+		&loc.DescriptionBouldering,
+		&loc.DescriptionRoutes,
+		&loc.DescriptionShortBouldering,
+		&loc.DescriptionShortRoutes,
+		&loc.AccessDescriptionBouldering,
+		&loc.AccessDescriptionRoutes,
+		&loc.AccessIssuesDescriptionBouldering,
+		&loc.AccessIssuesDescriptionRoutes,
+		&loc.ClimbTypeID,
+		&loc.WoulderLocationID,
+		&loc.LastSyncedAt,
+		&loc.CreatedAt,
+		&loc.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &loc, nil
+}
+
+func (r *PostgresRepository) GetClosedWoulderLocationIDs(ctx context.Context) (map[int]bool, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetClosedWoulderLocationIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	closed := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		closed[id] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return closed, nil
+}
+
 func (r *PostgresRepository) GetSubLocations(ctx context.Context, parentKayaLocationID string) ([]*models.KayaLocation, error) {
 	rows, err := r.db.QueryContext(ctx, queryGetSubLocations, parentKayaLocationID)
 	if err != nil {
@@ -606,6 +680,38 @@ func (r *PostgresRepository) GetMatchedClimbsForArea(ctx context.Context, mpArea
 	return results, rows.Err()
 }
 
+// GetMatchedClimbsForRoute retrieves approved Kaya climbs matched to a specific MP route
+func (r *PostgresRepository) GetMatchedClimbsForRoute(ctx context.Context, mpRouteID int64, verifiedOnly bool) ([]KayaRouteMatch, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetMatchedClimbsForRoute, mpRouteID, verifiedOnly)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []KayaRouteMatch
+	for rows.Next() {
+		var result KayaRouteMatch
+		if err := rows.Scan(
+			&result.KayaClimbSlug,
+			&result.KayaClimbName,
+			&result.ClimbGrade,
+			&result.AreaName,
+			&result.MPRouteID,
+			&result.MPRouteName,
+			&result.Confidence,
+			&result.MatchType,
+			&result.NameSimilarity,
+			&result.DistanceKM,
+			&result.IsVerified,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
 func (r *PostgresRepository) scanClimbs(rows *sql.Rows) ([]*models.KayaClimb, error) {
 	var climbs []*models.KayaClimb
 	for rows.Next() {
@@ -930,6 +1036,45 @@ func (r *PostgresRepository) GetRecentPosts(ctx context.Context, limit int) ([]*
 	return posts, rows.Err()
 }
 
+func (r *PostgresRepository) GetRecentPostsWithMedia(ctx context.Context, limit, offset int) ([]KayaPostWithMedia, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetRecentPostsWithMedia, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []KayaPostWithMedia
+	for rows.Next() {
+		var post KayaPostWithMedia
+		var item KayaPostMediaItem
+		if err := rows.Scan(
+			&post.KayaPostID,
+			&post.KayaUserID,
+			&post.Username,
+			&post.DateCreated,
+			&item.KayaPostItemID,
+			&item.KayaClimbSlug,
+			&item.ClimbName,
+			&item.PhotoURL,
+			&item.VideoURL,
+			&item.VideoThumbnailURL,
+			&item.Caption,
+		); err != nil {
+			return nil, err
+		}
+
+		// Rows are grouped by post, so a new post starts whenever the ID changes
+		if n := len(posts); n > 0 && posts[n-1].KayaPostID == post.KayaPostID {
+			posts[n-1].Items = append(posts[n-1].Items, item)
+			continue
+		}
+		post.Items = []KayaPostMediaItem{item}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
 // SyncRepository implementation
 
 func (r *PostgresRepository) SaveSyncProgress(ctx context.Context, progress *models.KayaSyncProgress) error {
@@ -1015,3 +1160,20 @@ func (r *PostgresRepository) IncrementSyncCounters(ctx context.Context, kayaLoca
 	_, err := r.db.ExecContext(ctx, queryIncrementSyncCounters, kayaLocationID, climbs, ascents, subLocations)
 	return err
 }
+
+func (r *PostgresRepository) ScheduleNextSync(ctx context.Context, kayaLocationID string, nextSyncAt time.Time, synced bool) error {
+	_, err := r.db.ExecContext(ctx, queryScheduleNextSync, kayaLocationID, nextSyncAt, synced)
+	return err
+}
+
+func (r *PostgresRepository) GetLastSyncedAt(ctx context.Context, slug string) (*time.Time, error) {
+	var lastSyncAt *time.Time
+	err := r.db.QueryRowContext(ctx, queryGetLastSyncedAt, slug).Scan(&lastSyncAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lastSyncAt, nil
+}
@@ -181,6 +181,36 @@ const (
 		WHERE slug = $1
 	`
 
+	queryGetLocationForWoulderLocation = `
+		SELECT id, kaya_location_id, slug, name, latitude, longitude, photo_url, description,
+			location_type_id, location_type_name, parent_location_id, parent_location_slug,
+			parent_location_name, climb_count, boulder_count, route_count, ascent_count,
+			is_gb_moderated_bouldering, is_gb_moderated_routes, is_access_sensitive,
+			is_closed, has_maps_disabled, closed_date, description_bouldering,
+			description_routes, description_short_bouldering, description_short_routes,
+			access_description_bouldering, access_description_routes,
+			access_issues_description_bouldering, access_issues_description_routes,
+			climb_type_id, woulder_location_id, last_synced_at, created_at, updated_at
+		FROM woulder.kaya_locations
+		WHERE woulder_location_id = $1
+		ORDER BY climb_count DESC, id
+		LIMIT 1
+	`
+
+	// queryGetClosedWoulderLocationIDs picks each Woulder location's Kaya
+	// destination the same way as queryGetLocationForWoulderLocation and
+	// keeps the closed ones.
+	queryGetClosedWoulderLocationIDs = `
+		SELECT woulder_location_id
+		FROM (
+			SELECT DISTINCT ON (woulder_location_id) woulder_location_id, is_closed
+			FROM woulder.kaya_locations
+			WHERE woulder_location_id IS NOT NULL
+			ORDER BY woulder_location_id, climb_count DESC, id
+		) mapped
+		WHERE is_closed
+	`
+
 	queryGetSubLocations = `
 		SELECT id, kaya_location_id, slug, name, latitude, longitude, photo_url, description,
 			location_type_id, location_type_name, parent_location_id, parent_location_slug,
@@ -374,6 +404,7 @@ const (
 			JOIN woulder.mp_routes r ON m.mp_route_id = r.mp_route_id
 			WHERE r.mp_area_id = $1
 				AND m.match_confidence >= 0.75
+				AND m.match_status = 'approved'
 				AND r.route_type ILIKE '%boulder%'
 				AND r.route_type NOT ILIKE '%ice%'
 				AND r.route_type NOT ILIKE '%mixed%'
@@ -420,6 +451,31 @@ const (
 		LIMIT $2
 	`
 
+	// queryGetMatchedClimbsForRoute retrieves the approved Kaya climbs matched to an MP route,
+	// either auto-approved by the matcher or verified by a reviewer. $2 (verified only)
+	// drops auto-approved matches.
+	// NOTE: kaya_climb_id holds the Kaya climb SLUG (see queryGetMatchedClimbsForArea)
+	queryGetMatchedClimbsForRoute = `
+		SELECT
+			m.kaya_climb_id AS kaya_slug,
+			COALESCE(c.name, m.kaya_climb_name) AS kaya_climb_name,
+			c.grade_name,
+			COALESCE(c.kaya_area_name, c.kaya_destination_name, m.kaya_location_name, 'Unknown') AS area_name,
+			m.mp_route_id,
+			m.mp_route_name,
+			m.match_confidence,
+			m.match_type,
+			m.name_similarity,
+			m.location_distance_km,
+			COALESCE(m.is_verified, false) AS is_verified
+		FROM kaya_mp_route_matches m
+		LEFT JOIN woulder.kaya_climbs c ON c.slug = m.kaya_climb_id
+		WHERE m.mp_route_id = $1
+			AND m.match_status = 'approved'
+			AND (NOT $2 OR m.is_verified = true)
+		ORDER BY COALESCE(m.is_verified, false) DESC, m.match_confidence DESC, kaya_climb_name
+	`
+
 	// queryGetAscentsForMatchedRoute retrieves Kaya ascents for climbs matched to a specific MP route
 	queryGetAscentsForMatchedRoute = `
 		SELECT
@@ -438,6 +494,7 @@ const (
 		LEFT JOIN woulder.kaya_users ku ON ka.kaya_user_id = ku.kaya_user_id
 		WHERE m.mp_route_id = $1
 			AND m.match_confidence >= 0.75
+			AND m.match_status = 'approved'
 			AND r.route_type ILIKE '%boulder%'
 			AND r.route_type NOT ILIKE '%ice%'
 			AND r.route_type NOT ILIKE '%mixed%'
@@ -601,6 +658,41 @@ const (
 		ORDER BY date_created DESC
 		LIMIT $1
 	`
+
+	// queryGetRecentPostsWithMedia pages over posts with at least one photo or
+	// video ($1 limit, $2 offset) and returns one row per media item, so a
+	// page is fetched in a single round trip. Rows come grouped by post.
+	queryGetRecentPostsWithMedia = `
+		WITH page AS (
+			SELECT p.kaya_post_id, p.kaya_user_id, p.date_created
+			FROM woulder.kaya_posts p
+			WHERE EXISTS (
+				SELECT 1 FROM woulder.kaya_post_items i
+				WHERE i.kaya_post_id = p.kaya_post_id
+				  AND (NULLIF(i.photo_url, '') IS NOT NULL OR NULLIF(i.video_url, '') IS NOT NULL)
+			)
+			ORDER BY p.date_created DESC, p.kaya_post_id
+			LIMIT $1 OFFSET $2
+		)
+		SELECT
+			page.kaya_post_id,
+			page.kaya_user_id,
+			u.username,
+			page.date_created,
+			i.kaya_post_item_id,
+			i.kaya_climb_slug,
+			c.name,
+			NULLIF(i.photo_url, ''),
+			NULLIF(i.video_url, ''),
+			NULLIF(i.video_thumbnail_url, ''),
+			i.caption
+		FROM page
+		INNER JOIN woulder.kaya_post_items i ON i.kaya_post_id = page.kaya_post_id
+		LEFT JOIN woulder.kaya_users u ON u.kaya_user_id = page.kaya_user_id
+		LEFT JOIN woulder.kaya_climbs c ON c.slug = i.kaya_climb_slug
+		WHERE NULLIF(i.photo_url, '') IS NOT NULL OR NULLIF(i.video_url, '') IS NOT NULL
+		ORDER BY page.date_created DESC, page.kaya_post_id, i.id
+	`
 )
 
 // Sync queries
@@ -643,13 +735,20 @@ const (
 			sync_error, climbs_synced, ascents_synced, sub_locations_synced,
 			created_at, updated_at
 		FROM woulder.kaya_sync_progress
-		WHERE status IN ('pending', 'failed')
-			OR (status = 'completed' AND next_sync_at <= NOW())
+		WHERE status = 'pending'
+			OR (status IN ('failed', 'completed') AND (next_sync_at IS NULL OR next_sync_at <= NOW()))
+			-- Closed locations are rechecked once their skip runs out.
+			OR (status = 'skipped' AND next_sync_at <= NOW())
+			-- A run that died mid-sync leaves its rows in_progress; reclaim them
+			-- once they are clearly abandoned.
+			OR (status = 'in_progress' AND updated_at < NOW() - INTERVAL '6 hours')
 		ORDER BY
 			CASE status
 				WHEN 'pending' THEN 1
 				WHEN 'failed' THEN 2
-				WHEN 'completed' THEN 3
+				WHEN 'in_progress' THEN 3
+				WHEN 'completed' THEN 4
+				WHEN 'skipped' THEN 5
 			END,
 			next_sync_at ASC NULLS FIRST
 		LIMIT $1
@@ -670,4 +769,21 @@ const (
 			updated_at = NOW()
 		WHERE kaya_location_id = $1
 	`
+
+	queryScheduleNextSync = `
+		UPDATE woulder.kaya_sync_progress
+		SET
+			last_sync_at = CASE WHEN $3 THEN NOW() ELSE last_sync_at END,
+			next_sync_at = $2,
+			updated_at = NOW()
+		WHERE kaya_location_id = $1
+	`
+
+	queryGetLastSyncedAt = `
+		SELECT p.last_sync_at
+		FROM woulder.kaya_locations l
+		JOIN woulder.kaya_sync_progress p ON p.kaya_location_id = l.kaya_location_id
+		WHERE l.slug = $1 AND p.status = 'completed'
+		LIMIT 1
+	`
 )
@@ -42,3 +42,15 @@ func TestWriteAmplificationGuards(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchedClimbsForRouteFiltersApproved(t *testing.T) {
+	for _, want := range []string{
+		"WHERE m.mp_route_id = $1",
+		"m.match_status = 'approved'",
+		"NOT $2 OR m.is_verified = true",
+	} {
+		if !strings.Contains(queryGetMatchedClimbsForRoute, want) {
+			t.Fatalf("query missing %q", want)
+		}
+	}
+}
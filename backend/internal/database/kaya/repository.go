@@ -22,6 +22,44 @@ type KayaAscentWithDetails struct {
 	Username      string
 }
 
+// KayaRouteMatch is a Kaya climb matched to an MP route, with the match
+// scoring stored by the Kaya↔MP matcher.
+type KayaRouteMatch struct {
+	KayaClimbSlug  string
+	KayaClimbName  string
+	ClimbGrade     *string
+	AreaName       string
+	MPRouteID      int64
+	MPRouteName    string
+	Confidence     float64
+	MatchType      string
+	NameSimilarity *float64
+	DistanceKM     *float64
+	IsVerified     bool
+}
+
+// KayaPostWithMedia is a Kaya post with its author and the items that carry
+// a photo or video. This is returned by GetRecentPostsWithMedia.
+type KayaPostWithMedia struct {
+	KayaPostID  string
+	KayaUserID  string
+	Username    *string
+	DateCreated time.Time
+	Items       []KayaPostMediaItem
+}
+
+// KayaPostMediaItem is a post item with a photo or video, and the climb it
+// is linked to when there is one.
+type KayaPostMediaItem struct {
+	KayaPostItemID    string
+	KayaClimbSlug     *string
+	ClimbName         *string
+	PhotoURL          *string
+	VideoURL          *string
+	VideoThumbnailURL *string
+	Caption           *string
+}
+
 // Repository is a composite of all Kaya sub-repositories.
 // It provides a unified interface for accessing Kaya data operations.
 type Repository interface {
@@ -60,6 +98,16 @@ type LocationsRepository interface {
 	// Returns nil if not found.
 	GetLocationBySlug(ctx context.Context, slug string) (*models.KayaLocation, error)
 
+	// GetLocationForWoulderLocation retrieves the Kaya destination mapped to a
+	// Woulder location. When several are mapped, the one with the most climbs
+	// wins. Returns nil if none is mapped.
+	GetLocationForWoulderLocation(ctx context.Context, woulderLocationID int) (*models.KayaLocation, error)
+
+	// GetClosedWoulderLocationIDs returns the Woulder locations whose mapped
+	// Kaya destination (chosen as in GetLocationForWoulderLocation) is
+	// closed.
+	GetClosedWoulderLocationIDs(ctx context.Context) (map[int]bool, error)
+
 	// GetSubLocations retrieves all direct children of a location.
 	GetSubLocations(ctx context.Context, parentKayaLocationID string) ([]*models.KayaLocation, error)
 
@@ -87,6 +135,10 @@ type ClimbsRepository interface {
 
 	// GetMatchedClimbsForArea retrieves Kaya climbs that have been matched to MP routes in a specific area
 	GetMatchedClimbsForArea(ctx context.Context, mpAreaID int64, limit int) ([]models.UnifiedRouteActivitySummary, error)
+
+	// GetMatchedClimbsForRoute retrieves the approved Kaya climbs matched to a single MP route.
+	// With verifiedOnly, auto-approved matches no reviewer has verified are left out.
+	GetMatchedClimbsForRoute(ctx context.Context, mpRouteID int64, verifiedOnly bool) ([]KayaRouteMatch, error)
 }
 
 // AscentsRepository handles Kaya ascent operations.
@@ -135,6 +187,11 @@ type PostsRepository interface {
 
 	// GetRecentPosts retrieves recent posts.
 	GetRecentPosts(ctx context.Context, limit int) ([]*models.KayaPost, error)
+
+	// GetRecentPostsWithMedia retrieves a page of posts that have at least one
+	// photo or video, newest first, each with its media items. Items without
+	// media are left out.
+	GetRecentPostsWithMedia(ctx context.Context, limit, offset int) ([]KayaPostWithMedia, error)
 }
 
 // SyncRepository handles sync progress and scheduling operations.
@@ -146,7 +203,9 @@ type SyncRepository interface {
 	// Returns nil if not found.
 	GetSyncProgress(ctx context.Context, kayaLocationID string) (*models.KayaSyncProgress, error)
 
-	// GetLocationsDueForSync retrieves locations that need syncing.
+	// GetLocationsDueForSync retrieves locations that need syncing: pending
+	// ones, failed, completed or skipped (closed) ones whose next sync is
+	// due, and ones left in_progress by a run that stopped mid-sync hours ago.
 	GetLocationsDueForSync(ctx context.Context, limit int) ([]*models.KayaSyncProgress, error)
 
 	// UpdateSyncStatus updates the sync status for a location.
@@ -154,4 +213,13 @@ type SyncRepository interface {
 
 	// IncrementSyncCounters increments the sync counters for a location.
 	IncrementSyncCounters(ctx context.Context, kayaLocationID string, climbs, ascents, subLocations int) error
+
+	// ScheduleNextSync sets when a location is next due. last_sync_at is set
+	// to now only if synced is true, i.e. the sync just finished succeeded.
+	ScheduleNextSync(ctx context.Context, kayaLocationID string, nextSyncAt time.Time, synced bool) error
+
+	// GetLastSyncedAt returns when the location with this slug last synced
+	// successfully. Returns nil if it never has, or its latest sync is still
+	// running or failed.
+	GetLastSyncedAt(ctx context.Context, slug string) (*time.Time, error)
 }
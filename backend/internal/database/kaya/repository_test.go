@@ -0,0 +1,125 @@
+package kaya_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexscott64/woulder/backend/internal/database/kaya"
+)
+
+func TestPostgresRepository_GetRecentPostsWithMedia(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	newer := time.Date(2026, 5, 2, 18, 0, 0, 0, time.UTC)
+	older := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{
+		"kaya_post_id", "kaya_user_id", "username", "date_created",
+		"kaya_post_item_id", "kaya_climb_slug", "name",
+		"photo_url", "video_url", "video_thumbnail_url", "caption",
+	}).
+		AddRow("p2", "u1", "sender", newer, "i1", "the-prism-v9", "The Prism",
+			"https://img/1.jpg", nil, nil, "Finally!").
+		AddRow("p2", "u1", "sender", newer, "i2", nil, nil,
+			nil, "https://vid/2.mp4", "https://img/2.jpg", nil).
+		AddRow("p1", "u2", nil, older, "i3", nil, nil,
+			"https://img/3.jpg", nil, nil, nil)
+
+	mock.ExpectQuery(`WITH page AS`).
+		WithArgs(20, 40).
+		WillReturnRows(rows)
+
+	repo := kaya.NewPostgresRepository(db)
+	posts, err := repo.Posts().GetRecentPostsWithMedia(context.Background(), 20, 40)
+	if err != nil {
+		t.Fatalf("GetRecentPostsWithMedia() error = %v", err)
+	}
+
+	if len(posts) != 2 {
+		t.Fatalf("GetRecentPostsWithMedia() returned %d posts, want 2", len(posts))
+	}
+	if posts[0].KayaPostID != "p2" || len(posts[0].Items) != 2 {
+		t.Errorf("first post = %s with %d items, want p2 with 2", posts[0].KayaPostID, len(posts[0].Items))
+	}
+	if got := posts[0].Items[0].ClimbName; got == nil || *got != "The Prism" {
+		t.Errorf("first item climb name = %v, want The Prism", got)
+	}
+	if got := posts[0].Items[1].VideoURL; got == nil || *got != "https://vid/2.mp4" {
+		t.Errorf("second item video URL = %v, want https://vid/2.mp4", got)
+	}
+	if posts[1].KayaPostID != "p1" || len(posts[1].Items) != 1 || posts[1].Username != nil {
+		t.Errorf("second post = %+v, want p1 with 1 item and no username", posts[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetClosedWoulderLocationIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT DISTINCT ON \(woulder_location_id\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"woulder_location_id"}).AddRow(3).AddRow(9))
+
+	repo := kaya.NewPostgresRepository(db)
+	closed, err := repo.Locations().GetClosedWoulderLocationIDs(context.Background())
+	if err != nil {
+		t.Fatalf("GetClosedWoulderLocationIDs() error = %v", err)
+	}
+
+	if len(closed) != 2 || !closed[3] || !closed[9] {
+		t.Errorf("GetClosedWoulderLocationIDs() = %v, want locations 3 and 9", closed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetLastSyncedAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	lastSync := time.Date(2026, 6, 24, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`p.status = 'completed'`).
+		WithArgs("Gold-Bar-344983").
+		WillReturnRows(sqlmock.NewRows([]string{"last_sync_at"}).AddRow(lastSync))
+	mock.ExpectQuery(`FROM woulder.kaya_locations l`).
+		WithArgs("Index-344982").
+		WillReturnRows(sqlmock.NewRows([]string{"last_sync_at"}))
+
+	repo := kaya.NewPostgresRepository(db)
+
+	got, err := repo.Sync().GetLastSyncedAt(context.Background(), "Gold-Bar-344983")
+	if err != nil {
+		t.Fatalf("GetLastSyncedAt() error = %v", err)
+	}
+	if got == nil || !got.Equal(lastSync) {
+		t.Errorf("GetLastSyncedAt() = %v, want %v", got, lastSync)
+	}
+
+	got, err = repo.Sync().GetLastSyncedAt(context.Background(), "Index-344982")
+	if err != nil {
+		t.Fatalf("GetLastSyncedAt(never synced) error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("GetLastSyncedAt(never synced) = %v, want nil", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
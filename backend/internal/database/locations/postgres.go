@@ -5,6 +5,7 @@ import (
 
 	"github.com/alexscott64/woulder/backend/internal/database/dberrors"
 	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/lib/pq"
 )
 
 // PostgresRepository implements Repository using PostgreSQL.
@@ -133,3 +134,88 @@ func (r *PostgresRepository) Create(ctx context.Context, loc models.Location) (i
 	}
 	return id, nil
 }
+
+// Upsert inserts a location or updates the existing one with the same name.
+func (r *PostgresRepository) Upsert(ctx context.Context, loc models.Location) (int, bool, error) {
+	var id int
+	var created bool
+	err := r.db.QueryRowContext(
+		ctx,
+		queryUpsert,
+		loc.Name,
+		loc.Latitude,
+		loc.Longitude,
+		loc.ElevationFt,
+		loc.AreaID,
+		loc.HasSeepageRisk,
+		loc.Timezone,
+	).Scan(&id, &created)
+	if err != nil {
+		return 0, false, err
+	}
+	return id, created, nil
+}
+
+// GetNearby retrieves locations within radiusKm of a point, nearest first.
+func (r *PostgresRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetNearby, lat, lon, radiusKm*1000, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []models.NearbyLocation{}
+	for rows.Next() {
+		var loc models.NearbyLocation
+		if err := rows.Scan(
+			&loc.ID,
+			&loc.Name,
+			&loc.Latitude,
+			&loc.Longitude,
+			&loc.ElevationFt,
+			&loc.AreaID,
+			&loc.HasSeepageRisk,
+			&loc.Timezone,
+			&loc.CreatedAt,
+			&loc.UpdatedAt,
+			&loc.DistanceKm,
+		); err != nil {
+			return nil, err
+		}
+		locations = append(locations, loc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// GetSyncInfo retrieves route counts and latest tick sync times by location.
+func (r *PostgresRepository) GetSyncInfo(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error) {
+	infos := make(map[int]models.LocationSyncInfo, len(locationIDs))
+	if len(locationIDs) == 0 {
+		return infos, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryGetSyncInfo, pq.Array(locationIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var info models.LocationSyncInfo
+		if err := rows.Scan(&info.LocationID, &info.RouteCount, &info.LastSyncedAt); err != nil {
+			return nil, err
+		}
+		infos[info.LocationID] = info
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
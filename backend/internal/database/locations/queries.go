@@ -31,6 +31,23 @@ const (
 		ORDER BY name
 	`
 
+	// queryGetNearby retrieves locations within a radius of a point, nearest first.
+	// $1 = latitude, $2 = longitude, $3 = radius in meters, $4 = limit.
+	// Index: locations(geog) GiST (migration 000044) serves both ST_DWithin
+	// and the <-> KNN ordering.
+	queryGetNearby = `
+		WITH origin AS (
+			SELECT ST_SetSRID(ST_MakePoint($2, $1), 4326)::geography AS geog
+		)
+		SELECT l.id, l.name, l.latitude, l.longitude, l.elevation_ft, l.area_id,
+		       l.has_seepage_risk, l.timezone, l.created_at, l.updated_at,
+		       ST_Distance(l.geog, o.geog) / 1000.0 AS distance_km
+		FROM woulder.locations l, origin o
+		WHERE ST_DWithin(l.geog, o.geog, $3)
+		ORDER BY l.geog <-> o.geog
+		LIMIT $4
+	`
+
 	// queryInsert inserts a new location and returns the generated id.
 	// timezone is required; the service layer is responsible for
 	// derivation/validation (see LocationService.CreateLocation).
@@ -40,4 +57,32 @@ const (
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 	`
+
+	// queryUpsert inserts a location or, when one with the same name exists,
+	// moves it to the given coordinates, area and timezone. elevation_ft and
+	// has_seepage_risk are only set on insert. xmax = 0 holds only for a
+	// freshly inserted row, which is how inserted is reported.
+	// Indexes: locations(name) UNIQUE
+	queryUpsert = `
+		INSERT INTO woulder.locations
+			(name, latitude, longitude, elevation_ft, area_id, has_seepage_risk, timezone)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE SET
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			area_id = EXCLUDED.area_id,
+			timezone = EXCLUDED.timezone,
+			updated_at = CURRENT_TIMESTAMP
+		RETURNING id, (xmax = 0) AS inserted
+	`
+
+	// queryGetSyncInfo counts each location's Mountain Project routes and
+	// finds their latest tick sync. $1 = location IDs.
+	// Index: mp_routes(location_id)
+	queryGetSyncInfo = `
+		SELECT location_id, COUNT(*) AS route_count, MAX(last_tick_sync_at) AS last_synced_at
+		FROM woulder.mp_routes
+		WHERE location_id = ANY($1)
+		GROUP BY location_id
+	`
 )
@@ -30,4 +30,20 @@ type Repository interface {
 	// validate it. The service layer (LocationService.CreateLocation) is the
 	// authoritative validation/derivation point.
 	Create(ctx context.Context, loc models.Location) (int, error)
+
+	// Upsert inserts loc, or updates the coordinates, area and timezone of
+	// the existing location with the same name. It returns the location's ID
+	// and whether it was newly created. The same timezone rules as Create
+	// apply.
+	Upsert(ctx context.Context, loc models.Location) (id int, created bool, err error)
+
+	// GetNearby retrieves locations within radiusKm of (lat, lon), nearest
+	// first, limited to limit results. Distances are geodesic (PostGIS
+	// geography). Returns an empty slice if none are in range.
+	GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error)
+
+	// GetSyncInfo retrieves Mountain Project route counts and latest tick
+	// sync times for the given locations, keyed by location ID. Locations
+	// with no linked routes are absent from the map.
+	GetSyncInfo(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error)
 }
@@ -302,3 +302,142 @@ func TestPostgresRepository_Create(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
 }
+
+func TestPostgresRepository_Upsert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	loc := models.Location{
+		Name:      "Squamish",
+		Latitude:  49.7016,
+		Longitude: -123.1558,
+		AreaID:    5,
+		Timezone:  "America/Vancouver",
+	}
+
+	mock.ExpectQuery(`INSERT INTO woulder.locations.*ON CONFLICT \(name\) DO UPDATE`).
+		WithArgs(loc.Name, loc.Latitude, loc.Longitude, loc.ElevationFt, loc.AreaID, loc.HasSeepageRisk, loc.Timezone).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inserted"}).AddRow(42, false))
+
+	repo := locations.NewPostgresRepository(db)
+	id, created, err := repo.Upsert(context.Background(), loc)
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if id != 42 || created {
+		t.Errorf("Upsert() = (%d, %v), want (42, false) for an existing name", id, created)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetNearby(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "name", "latitude", "longitude", "elevation_ft",
+		"area_id", "has_seepage_risk", "timezone", "created_at", "updated_at",
+		"distance_km",
+	}).AddRow(
+		2, "Index Town Wall", 47.8203, -121.5565, 1500,
+		1, true, "America/Los_Angeles", now, now,
+		12.5,
+	)
+
+	// Radius is passed to ST_DWithin in meters
+	mock.ExpectQuery("SELECT (.+) FROM woulder.locations l, origin o\\s+WHERE ST_DWithin").
+		WithArgs(47.7, -121.6, 50000.0, 5).
+		WillReturnRows(rows)
+
+	repo := locations.NewPostgresRepository(db)
+	result, err := repo.GetNearby(context.Background(), 47.7, -121.6, 50, 5)
+
+	if err != nil {
+		t.Errorf("GetNearby() error = %v", err)
+	}
+
+	if len(result) != 1 {
+		t.Fatalf("GetNearby() returned %d locations, want 1", len(result))
+	}
+
+	if result[0].Name != "Index Town Wall" || result[0].DistanceKm != 12.5 {
+		t.Errorf("GetNearby() = %+v, want Index Town Wall at 12.5km", result[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetSyncInfo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	synced := time.Now()
+	rows := sqlmock.NewRows([]string{"location_id", "route_count", "last_synced_at"}).
+		AddRow(1, 120, synced).
+		AddRow(2, 8, nil)
+
+	mock.ExpectQuery("SELECT (.+) FROM woulder.mp_routes\\s+WHERE location_id = ANY").
+		WithArgs("{1,2,3}").
+		WillReturnRows(rows)
+
+	repo := locations.NewPostgresRepository(db)
+	result, err := repo.GetSyncInfo(context.Background(), []int{1, 2, 3})
+
+	if err != nil {
+		t.Fatalf("GetSyncInfo() error = %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("GetSyncInfo() returned %d locations, want 2", len(result))
+	}
+
+	if got := result[1]; got.RouteCount != 120 || got.LastSyncedAt == nil || !got.LastSyncedAt.Equal(synced) {
+		t.Errorf("GetSyncInfo()[1] = %+v, want 120 routes synced at %v", got, synced)
+	}
+
+	if got := result[2]; got.RouteCount != 8 || got.LastSyncedAt != nil {
+		t.Errorf("GetSyncInfo()[2] = %+v, want 8 routes never synced", got)
+	}
+
+	if _, ok := result[3]; ok {
+		t.Error("GetSyncInfo() should omit locations without routes")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetSyncInfo_NoIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	repo := locations.NewPostgresRepository(db)
+	result, err := repo.GetSyncInfo(context.Background(), nil)
+
+	if err != nil || len(result) != 0 {
+		t.Errorf("GetSyncInfo(nil) = (%v, %v), want empty map", result, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
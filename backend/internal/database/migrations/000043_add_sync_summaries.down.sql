@@ -0,0 +1,5 @@
+-- Migration 000043 rollback: Remove sync summaries
+
+DROP INDEX IF EXISTS woulder.idx_sync_summaries_completed;
+DROP INDEX IF EXISTS woulder.idx_sync_summaries_job_completed;
+DROP TABLE IF EXISTS woulder.sync_summaries;
@@ -0,0 +1,31 @@
+-- Migration 000043: Add sync summaries for historical throughput trends
+-- One row per completed sync run, written alongside the job_executions record
+
+CREATE TABLE IF NOT EXISTS woulder.sync_summaries (
+    id SERIAL PRIMARY KEY,
+    job_execution_id INTEGER REFERENCES woulder.job_executions(id) ON DELETE SET NULL,
+    job_name VARCHAR(100) NOT NULL,          -- Matches job_executions.job_name
+    job_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,              -- 'completed' or 'failed'
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    duration_seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    items_processed INTEGER NOT NULL DEFAULT 0,
+    items_succeeded INTEGER NOT NULL DEFAULT 0,
+    items_failed INTEGER NOT NULL DEFAULT 0,
+    new_ticks INTEGER NOT NULL DEFAULT 0,
+    new_comments INTEGER NOT NULL DEFAULT 0,
+    new_routes INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Trend queries filter by job and time window
+CREATE INDEX IF NOT EXISTS idx_sync_summaries_job_completed
+    ON woulder.sync_summaries(job_name, completed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sync_summaries_completed
+    ON woulder.sync_summaries(completed_at DESC);
+
+COMMENT ON TABLE woulder.sync_summaries IS 'Per-run sync summaries kept for historical throughput trend analysis';
+COMMENT ON COLUMN woulder.sync_summaries.new_ticks IS 'Ticks inserted during the run (0 for non-tick syncs)';
+COMMENT ON COLUMN woulder.sync_summaries.new_comments IS 'Comments saved during the run (0 for non-comment syncs)';
+COMMENT ON COLUMN woulder.sync_summaries.new_routes IS 'Routes discovered during the run (0 for non-route syncs)';
@@ -0,0 +1,11 @@
+-- Migration 000044 rollback: Remove geography columns
+--
+-- The postgis extension is left installed; other objects may depend on it.
+
+DROP INDEX IF EXISTS woulder.idx_mp_routes_geog_geom;
+DROP INDEX IF EXISTS woulder.idx_mp_areas_geog_geom;
+DROP INDEX IF EXISTS woulder.idx_locations_geog;
+
+ALTER TABLE woulder.mp_routes DROP COLUMN IF EXISTS geog;
+ALTER TABLE woulder.mp_areas DROP COLUMN IF EXISTS geog;
+ALTER TABLE woulder.locations DROP COLUMN IF EXISTS geog;
@@ -0,0 +1,54 @@
+-- Migration 000044: Enable PostGIS and add indexed geography points
+--
+-- Adds a geography(Point, 4326) column to locations, mp_areas and mp_routes,
+-- generated from the existing latitude/longitude columns so it can never drift
+-- from them.
+--
+-- Indexes:
+--   * locations: GiST on geog, backing ST_DWithin/ST_Distance nearest queries.
+--   * mp_areas/mp_routes: GiST on (geog::geometry), backing the heat map
+--     bounding-box queries (`geog::geometry && ST_MakeEnvelope(...)`). A
+--     planar envelope matches the previous lat/lon BETWEEN semantics exactly;
+--     a geography envelope would use great-circle edges instead.
+--
+-- Requires the PostGIS extension to be available on the server (RDS ships it;
+-- the CREATE EXTENSION needs rds_superuser or equivalent).
+
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+ALTER TABLE woulder.locations
+    ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+             THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+        END
+    ) STORED;
+
+ALTER TABLE woulder.mp_areas
+    ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+             THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+        END
+    ) STORED;
+
+ALTER TABLE woulder.mp_routes
+    ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        CASE WHEN latitude IS NOT NULL AND longitude IS NOT NULL
+             THEN ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography
+        END
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_locations_geog
+    ON woulder.locations USING GIST (geog);
+
+CREATE INDEX IF NOT EXISTS idx_mp_areas_geog_geom
+    ON woulder.mp_areas USING GIST ((geog::geometry));
+
+CREATE INDEX IF NOT EXISTS idx_mp_routes_geog_geom
+    ON woulder.mp_routes USING GIST ((geog::geometry));
+
+COMMENT ON COLUMN woulder.locations.geog IS 'Generated from latitude/longitude for PostGIS spatial queries';
+COMMENT ON COLUMN woulder.mp_areas.geog IS 'Generated from latitude/longitude for PostGIS spatial queries';
+COMMENT ON COLUMN woulder.mp_routes.geog IS 'Generated from latitude/longitude for PostGIS spatial queries';
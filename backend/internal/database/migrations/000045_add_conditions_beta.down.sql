@@ -0,0 +1,3 @@
+-- Migration 000045 rollback: Remove conditions beta
+
+DROP TABLE IF EXISTS woulder.mp_conditions_beta;
@@ -0,0 +1,15 @@
+-- Migration 000045: Add conditions beta extracted from MP comments
+-- One row per area or route with comments; tags are recomputed whenever that
+-- target's comments are synced so detail requests never re-scan comments.
+
+CREATE TABLE IF NOT EXISTS woulder.mp_conditions_beta (
+    target_type VARCHAR(10) NOT NULL CHECK (target_type IN ('area', 'route')),
+    mp_id BIGINT NOT NULL,                    -- mp_area_id or mp_route_id, per target_type
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    comment_count INTEGER NOT NULL DEFAULT 0, -- Comments scanned to produce tags
+    extracted_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (target_type, mp_id)
+);
+
+COMMENT ON TABLE woulder.mp_conditions_beta IS 'Rule-based conditions tags (seeps, morning_sun, ...) extracted from MP area/route comments';
+COMMENT ON COLUMN woulder.mp_conditions_beta.tags IS 'Tags from internal/beta, in declaration order';
@@ -0,0 +1,4 @@
+-- Migration 000046 rollback: Remove Kaya <-> MP match review status
+
+DROP INDEX IF EXISTS idx_kaya_mp_matches_status;
+ALTER TABLE kaya_mp_route_matches DROP COLUMN IF EXISTS match_status;
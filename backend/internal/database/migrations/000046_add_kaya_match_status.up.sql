@@ -0,0 +1,18 @@
+-- Migration 000046: Add review status to Kaya <-> MP route matches
+-- The matcher saves matches at or above its auto-approve threshold as
+-- 'approved' and the rest as 'pending', so reviewers only see ambiguous pairs.
+-- Matches a reviewer has verified keep their status across re-matching.
+
+ALTER TABLE kaya_mp_route_matches
+    ADD COLUMN IF NOT EXISTS match_status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (match_status IN ('pending', 'approved', 'rejected'));
+
+-- Backfill: verified matches and those above the default auto-approve
+-- threshold (0.90) are approved; everything else waits for review.
+UPDATE kaya_mp_route_matches
+SET match_status = 'approved'
+WHERE is_verified = true OR match_confidence >= 0.90;
+
+CREATE INDEX IF NOT EXISTS idx_kaya_mp_matches_status ON kaya_mp_route_matches(match_status);
+
+COMMENT ON COLUMN kaya_mp_route_matches.match_status IS 'Review status: approved (auto-approved or verified), pending (needs review), rejected';
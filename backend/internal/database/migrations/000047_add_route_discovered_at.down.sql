@@ -0,0 +1,4 @@
+-- Migration 000047 rollback: Remove route discovery timestamp
+
+DROP INDEX IF EXISTS woulder.idx_mp_routes_discovered_at;
+ALTER TABLE woulder.mp_routes DROP COLUMN IF EXISTS discovered_at;
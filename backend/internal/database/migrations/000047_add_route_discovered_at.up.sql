@@ -0,0 +1,13 @@
+-- Migration 000047: Record when the new-route sweep discovers a route
+-- syncNewRoutesInArea stamps discovered_at when it inserts a route that was
+-- not in the database before. Routes loaded by full area syncs stay NULL so
+-- the "new routes" feed only shows genuinely new additions.
+
+ALTER TABLE woulder.mp_routes
+    ADD COLUMN IF NOT EXISTS discovered_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_mp_routes_discovered_at
+    ON woulder.mp_routes(discovered_at DESC)
+    WHERE discovered_at IS NOT NULL;
+
+COMMENT ON COLUMN woulder.mp_routes.discovered_at IS 'When the new-route sweep first found this route (NULL for routes from full syncs)';
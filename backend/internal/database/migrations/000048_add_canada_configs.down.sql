@@ -1,4 +1,4 @@
--- Migration 000015 Down: Remove Canadian provinces and territories
+-- Migration 000048 Down: Remove Canadian provinces and territories
 
 DELETE FROM woulder.mp_state_configs
 WHERE region = 'Canada';
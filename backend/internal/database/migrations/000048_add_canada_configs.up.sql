@@ -1,5 +1,7 @@
--- Migration 000015: Add Canadian provinces and territories to mp_state_configs
--- Adds all 12 Canadian provinces/territories as individual areas to sync
+-- Migration 000048: Add Canadian provinces and territories to mp_state_configs
+-- Adds all 12 Canadian provinces/territories as individual areas to sync.
+-- Originally numbered 000015, which clashed with 000015_add_mp_comments and was
+-- skipped by the migration runner; rows that already exist are left alone.
 
 INSERT INTO woulder.mp_state_configs (state_name, mp_area_id, region, display_order, is_active) VALUES
   -- Canada (display_order 200-211)
@@ -14,4 +16,5 @@ INSERT INTO woulder.mp_state_configs (state_name, mp_area_id, region, display_or
   ('Ontario', '105948616', 'Canada', 208, FALSE),
   ('Quebec', '106142016', 'Canada', 209, FALSE),
   ('Saskatchewan', '113243903', 'Canada', 210, FALSE),
-  ('Yukon Territory', '106998806', 'Canada', 211, FALSE);
+  ('Yukon Territory', '106998806', 'Canada', 211, FALSE)
+ON CONFLICT DO NOTHING;
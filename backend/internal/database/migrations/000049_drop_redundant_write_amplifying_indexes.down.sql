@@ -1,4 +1,4 @@
--- Recreate indexes dropped by 000038_drop_redundant_write_amplifying_indexes.
+-- Recreate indexes dropped by 000049_drop_redundant_write_amplifying_indexes.
 --
 -- This rollback uses CONCURRENTLY. The custom migration runner detects
 -- CONCURRENTLY and runs the rollback outside a transaction for `down`.
@@ -1,5 +1,9 @@
 -- Drop redundant write-amplifying indexes identified during PostgreSQL diagnostics.
 --
+-- Originally numbered 000038, which clashed with 000038_add_money_toolkit.
+-- Every statement uses IF EXISTS, so re-running it on databases that already
+-- applied it under the old number is a no-op.
+--
 -- These indexes duplicate unique constraints/indexes that already support the
 -- same equality lookups and ON CONFLICT arbiters. Dropping them reduces WAL,
 -- vacuum, checkpoint, and buffer churn during high-volume Kaya/weather syncs.
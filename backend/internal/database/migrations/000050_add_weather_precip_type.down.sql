@@ -0,0 +1,3 @@
+-- Migration 000050 rollback: Remove hourly precipitation type
+
+ALTER TABLE woulder.weather_data DROP COLUMN IF EXISTS precip_type;
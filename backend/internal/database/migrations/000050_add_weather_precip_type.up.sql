@@ -0,0 +1,24 @@
+-- Migration 000050: Record what kind of precipitation fell each hour
+-- The weather clients classify each hour as none/rain/snow/mixed from the
+-- provider's rain/snow split (or air temperature when there is no split), so
+-- snow at an alpine crag is no longer treated like rain.
+--
+-- Performance: ADD COLUMN ... DEFAULT is metadata-only on Postgres 11+. The
+-- backfill only touches hours with measurable precipitation, using the
+-- temperature fallback; the next refresh overwrites recent hours with the
+-- provider's split.
+
+ALTER TABLE woulder.weather_data
+    ADD COLUMN IF NOT EXISTS precip_type VARCHAR(10) NOT NULL DEFAULT 'none'
+        CONSTRAINT weather_data_precip_type_check
+            CHECK (precip_type IN ('none', 'rain', 'snow', 'mixed'));
+
+UPDATE woulder.weather_data
+SET precip_type = CASE
+        WHEN temperature <= 30 THEN 'snow'
+        WHEN temperature >= 34 THEN 'rain'
+        ELSE 'mixed'
+    END
+WHERE precipitation >= 0.01;
+
+COMMENT ON COLUMN woulder.weather_data.precip_type IS 'Precipitation type for the hour: none, rain, snow or mixed';
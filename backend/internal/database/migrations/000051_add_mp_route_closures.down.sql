@@ -0,0 +1,4 @@
+-- Migration 000051 rollback: Remove Kaya-derived MP route closures
+
+DROP VIEW IF EXISTS woulder.mp_route_closures;
+DROP INDEX IF EXISTS woulder.idx_kaya_locations_closed_name;
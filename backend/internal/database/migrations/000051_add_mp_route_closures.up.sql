@@ -0,0 +1,53 @@
+-- Migration 000051: Derive MP route closures from Kaya
+-- Kaya flags closed climbs and locations (raptor closures, access disputes).
+-- mp_route_closures carries that through approved kaya_mp_route_matches to
+-- every matched MP route: a route is closed when a matched Kaya climb is, or
+-- when the climb's Kaya area or destination is. The note is the closed
+-- location's access-issues text for the climb's type, falling back to the
+-- location name and closure date.
+--
+-- Synced climbs only carry their area and destination by name, so closed
+-- locations are matched by ID when the climb has one and otherwise by name
+-- within the same Woulder location. Routes without an approved match have no
+-- row; matched open routes have a row with is_closed = false, so areas can
+-- tell "all matched routes closed" from "no Kaya data".
+
+CREATE INDEX IF NOT EXISTS idx_kaya_locations_closed_name
+    ON woulder.kaya_locations(name) WHERE is_closed;
+
+CREATE OR REPLACE VIEW woulder.mp_route_closures AS
+SELECT
+    m.mp_route_id,
+    bool_or(kc.is_closed OR cl.kaya_location_id IS NOT NULL) AS is_closed,
+    MIN(COALESCE(cl.note, CASE WHEN kc.is_closed THEN 'Closed on Kaya' END)) AS closure_note
+FROM woulder.kaya_mp_route_matches m
+JOIN woulder.kaya_climbs kc ON kc.slug = m.kaya_climb_id
+LEFT JOIN LATERAL (
+    SELECT
+        l.kaya_location_id,
+        COALESCE(
+            NULLIF(BTRIM(CASE
+                WHEN kc.climb_type_name ILIKE 'boulder%' THEN l.access_issues_description_bouldering
+                ELSE l.access_issues_description_routes
+            END), ''),
+            NULLIF(BTRIM(COALESCE(l.access_issues_description_bouldering, l.access_issues_description_routes)), ''),
+            'Closed on Kaya: ' || l.name || COALESCE(' (since ' || to_char(l.closed_date, 'YYYY-MM-DD') || ')', '')
+        ) AS note
+    FROM woulder.kaya_locations l
+    WHERE l.is_closed
+      AND (
+          l.kaya_location_id IN (kc.kaya_area_id, kc.kaya_destination_id)
+          OR (
+              l.name IN (kc.kaya_area_name, kc.kaya_destination_name)
+              AND (l.woulder_location_id IS NULL OR kc.woulder_location_id IS NULL
+                   OR l.woulder_location_id = kc.woulder_location_id)
+          )
+      )
+    -- Prefer the climb's own area over its destination
+    ORDER BY (l.kaya_location_id = kc.kaya_area_id OR l.name = kc.kaya_area_name) DESC NULLS LAST
+    LIMIT 1
+) cl ON true
+WHERE m.match_status = 'approved'
+GROUP BY m.mp_route_id;
+
+COMMENT ON VIEW woulder.mp_route_closures IS 'Closure status of MP routes with approved Kaya matches, from Kaya climb and location closed flags';
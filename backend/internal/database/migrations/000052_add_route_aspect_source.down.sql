@@ -0,0 +1,15 @@
+-- Migration 000052 rollback: Remove route aspect source tracking
+-- Comment-sourced aspects are reverted to the computed value first.
+
+UPDATE woulder.mp_routes
+SET aspect = computed_aspect
+WHERE aspect_source = 'comment';
+
+DROP INDEX IF EXISTS woulder.idx_mp_routes_aspect_source_comment;
+
+ALTER TABLE woulder.mp_routes
+    DROP COLUMN IF EXISTS aspect_evidence,
+    DROP COLUMN IF EXISTS computed_aspect,
+    DROP COLUMN IF EXISTS aspect_source;
+
+COMMENT ON COLUMN woulder.mp_routes.aspect IS 'Cardinal direction boulder faces (calculated from position in circular distribution): N, NE, E, SE, S, SW, W, NW';
@@ -0,0 +1,24 @@
+-- Migration 000052: Track where each route's aspect came from
+-- Computed aspects come from a boulder's bearing off its area center, which is
+-- often wrong. When route comments carry explicit sun beta ("faces south"),
+-- the comment sync overrides aspect with it and records the phrases it used.
+-- The geometric value is kept in computed_aspect so GPS recalculation never
+-- clobbers an override and an override can always be reverted.
+
+ALTER TABLE woulder.mp_routes
+    ADD COLUMN IF NOT EXISTS aspect_source VARCHAR(10) NOT NULL DEFAULT 'computed'
+        CHECK (aspect_source IN ('computed', 'comment')),
+    ADD COLUMN IF NOT EXISTS computed_aspect VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS aspect_evidence TEXT[];
+
+UPDATE woulder.mp_routes
+SET computed_aspect = aspect
+WHERE aspect IS NOT NULL AND computed_aspect IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_mp_routes_aspect_source_comment
+    ON woulder.mp_routes(mp_route_id) WHERE aspect_source = 'comment';
+
+COMMENT ON COLUMN woulder.mp_routes.aspect IS 'Cardinal direction the boulder faces (N, NE, E, SE, S, SW, W, NW); see aspect_source';
+COMMENT ON COLUMN woulder.mp_routes.aspect_source IS 'computed (geometric, = computed_aspect) or comment (sun beta from route comments)';
+COMMENT ON COLUMN woulder.mp_routes.computed_aspect IS 'Aspect calculated from position in the area''s circular distribution';
+COMMENT ON COLUMN woulder.mp_routes.aspect_evidence IS 'Comment phrases behind a comment-sourced aspect, for auditing';
@@ -0,0 +1,6 @@
+-- Migration 000053 rollback: Remove grade agreement from Kaya <-> MP route matches
+
+ALTER TABLE kaya_mp_route_matches
+    DROP COLUMN IF EXISTS grade_agreement,
+    DROP COLUMN IF EXISTS mp_rating,
+    DROP COLUMN IF EXISTS kaya_grade;
@@ -0,0 +1,16 @@
+-- Migration 000053: Record grade agreement on Kaya <-> MP route matches
+-- match_kaya_mp compares the Kaya climb's grade with the MP route's rating
+-- (V-scale and Font normalized to a V ordinal) and adjusts confidence: a
+-- bonus when they agree, a penalty when they clearly conflict. The grades and
+-- the outcome are kept so reviewers can see why a match scored as it did.
+-- Matches saved before this migration, or by sync_kaya_job, leave them NULL.
+
+ALTER TABLE kaya_mp_route_matches
+    ADD COLUMN IF NOT EXISTS kaya_grade VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS mp_rating VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS grade_agreement VARCHAR(10)
+        CHECK (grade_agreement IN ('match', 'near', 'conflict', 'unknown'));
+
+COMMENT ON COLUMN kaya_mp_route_matches.kaya_grade IS 'Kaya climb grade when matched';
+COMMENT ON COLUMN kaya_mp_route_matches.mp_rating IS 'MP route rating when matched';
+COMMENT ON COLUMN kaya_mp_route_matches.grade_agreement IS 'match (within 1 V-grade), near, conflict (different scales or 3+ V-grades apart), unknown (grade missing or unrecognized)';
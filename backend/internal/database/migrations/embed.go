@@ -0,0 +1,12 @@
+// Package migrations embeds the numbered migration files, so cmd/migrate
+// applies them without depending on its working directory.
+package migrations
+
+import "embed"
+
+// FS holds every .sql file in this directory. cmd/migrate only reads the
+// numbered NNNNNN_name.up.sql / .down.sql files; the other scripts here are
+// run by hand.
+//
+//go:embed *.sql
+var FS embed.FS
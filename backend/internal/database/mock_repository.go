@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
@@ -73,8 +74,8 @@ type MockRepository struct {
 	// Priority-based sync mocks
 	UpdateRouteSyncPrioritiesFn   func(ctx context.Context) error
 	GetLocationRoutesDueForSyncFn func(ctx context.Context, syncType string) ([]int64, error)
-	GetRoutesDueForTickSyncFn     func(ctx context.Context, priority string) ([]int64, error)
-	GetRoutesDueForCommentSyncFn  func(ctx context.Context, priority string) ([]int64, error)
+	GetRoutesDueForTickSyncFn     func(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
+	GetRoutesDueForCommentSyncFn  func(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
 	GetPriorityDistributionFn     func(ctx context.Context) (map[string]int, error)
 
 	// Upsert operations mocks
@@ -92,9 +93,9 @@ type MockRepository struct {
 	GetMPRoutesByIDsFn                   func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.MPRoute, error)
 
 	// Heat map mocks
-	GetHeatMapDataFn           func(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error)
+	GetHeatMapDataFn           func(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error)
 	GetAreaActivityDetailFn    func(ctx context.Context, areaID int64, startDate, endDate time.Time, routeTypes []string) (*models.AreaActivityDetail, error)
-	GetRoutesByBoundsFn        func(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
+	GetRoutesByBoundsFn        func(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error)
 	GetRouteTicksInDateRangeFn func(ctx context.Context, routeID int64, startDate, endDate time.Time, limit int, routeTypes []string) ([]models.TickDetail, error)
 	SearchRoutesInAreasFn      func(ctx context.Context, areaIDs []int64, searchQuery string, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
 
@@ -542,7 +543,7 @@ func (m *MockRepository) GetLocationRoutesDueForSync(ctx context.Context, syncTy
 }
 
 // GetRoutesDueForTickSync mock
-func (m *MockRepository) GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error) {
+func (m *MockRepository) GetRoutesDueForTickSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	if m.GetRoutesDueForTickSyncFn != nil {
 		return m.GetRoutesDueForTickSyncFn(ctx, priority)
 	}
@@ -550,7 +551,7 @@ func (m *MockRepository) GetRoutesDueForTickSync(ctx context.Context, priority s
 }
 
 // GetRoutesDueForCommentSync mock
-func (m *MockRepository) GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error) {
+func (m *MockRepository) GetRoutesDueForCommentSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	if m.GetRoutesDueForCommentSyncFn != nil {
 		return m.GetRoutesDueForCommentSyncFn(ctx, priority)
 	}
@@ -566,9 +567,9 @@ func (m *MockRepository) GetPriorityDistribution(ctx context.Context) (map[strin
 }
 
 // GetHeatMapData mock
-func (m *MockRepository) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+func (m *MockRepository) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 	if m.GetHeatMapDataFn != nil {
-		return m.GetHeatMapDataFn(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders)
+		return m.GetHeatMapDataFn(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders, excludeClosed)
 	}
 	return nil, nil
 }
@@ -582,9 +583,9 @@ func (m *MockRepository) GetAreaActivityDetail(ctx context.Context, areaID int64
 }
 
 // GetRoutesByBounds mock
-func (m *MockRepository) GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error) {
+func (m *MockRepository) GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error) {
 	if m.GetRoutesByBoundsFn != nil {
-		return m.GetRoutesByBoundsFn(ctx, bounds, startDate, endDate, limit)
+		return m.GetRoutesByBoundsFn(ctx, bounds, startDate, endDate, limit, excludeClosed)
 	}
 	return nil, nil
 }
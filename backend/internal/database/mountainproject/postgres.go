@@ -70,6 +70,36 @@ func (r *PostgresRepository) GetAreaByID(ctx context.Context, mpAreaID int64) (*
 	return &area, nil
 }
 
+func (r *PostgresRepository) GetAreasByLocation(ctx context.Context, locationID int) ([]models.MPArea, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetAreasByLocation, locationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	areas := []models.MPArea{}
+	for rows.Next() {
+		var area models.MPArea
+		if err := rows.Scan(
+			&area.ID,
+			&area.MPAreaID,
+			&area.Name,
+			&area.ParentMPAreaID,
+			&area.AreaType,
+			&area.LocationID,
+			&area.Latitude,
+			&area.Longitude,
+			&area.LastSyncedAt,
+			&area.CreatedAt,
+			&area.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		areas = append(areas, area)
+	}
+	return areas, rows.Err()
+}
+
 func (r *PostgresRepository) UpdateRouteCount(ctx context.Context, mpAreaID string, total int) error {
 	_, err := r.db.ExecContext(ctx, queryUpdateAreaRouteCount, total, mpAreaID)
 	return err
@@ -126,6 +156,91 @@ func (r *PostgresRepository) GetAllStateConfigs(ctx context.Context) ([]StateCon
 	return configs, rows.Err()
 }
 
+func (r *PostgresRepository) GetAreaLinkProblems(ctx context.Context) ([]AreaLinkProblem, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetAreaLinkProblems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []AreaLinkProblem
+	for rows.Next() {
+		var p AreaLinkProblem
+		var locationID, parentLocationID sql.NullInt64
+		if err := rows.Scan(&p.MPAreaID, &p.Name, &p.ParentMPAreaID, &locationID, &parentLocationID, &p.Problem); err != nil {
+			return nil, err
+		}
+		if locationID.Valid {
+			id := int(locationID.Int64)
+			p.LocationID = &id
+		}
+		if parentLocationID.Valid {
+			id := int(parentLocationID.Int64)
+			p.ParentLocationID = &id
+		}
+		problems = append(problems, p)
+	}
+	return problems, rows.Err()
+}
+
+func (r *PostgresRepository) DetachAreaParents(ctx context.Context, mpAreaIDs []int64) (int64, error) {
+	if len(mpAreaIDs) == 0 {
+		return 0, nil
+	}
+	result, err := r.db.ExecContext(ctx, queryDetachAreaParents, pq.Array(mpAreaIDs))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) ReconcileAreaLocations(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, queryReconcileAreaLocations)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) GetAreaCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]CoordinateProblem, error) {
+	return r.getCoordinateProblems(ctx, queryGetAreaCoordinateProblems, maxDistanceKm)
+}
+
+func (r *PostgresRepository) ClearAreaCoordinates(ctx context.Context, mpAreaIDs []int64) (int64, error) {
+	return r.clearCoordinates(ctx, queryClearAreaCoordinates, mpAreaIDs)
+}
+
+// getCoordinateProblems runs one of the coordinate problem queries.
+func (r *PostgresRepository) getCoordinateProblems(ctx context.Context, query string, maxDistanceKm float64) ([]CoordinateProblem, error) {
+	rows, err := r.db.QueryContext(ctx, query, maxDistanceKm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var problems []CoordinateProblem
+	for rows.Next() {
+		var p CoordinateProblem
+		if err := rows.Scan(&p.ID, &p.Name, &p.Latitude, &p.Longitude, &p.ReferenceAreaID, &p.DistanceKm, &p.Problem); err != nil {
+			return nil, err
+		}
+		problems = append(problems, p)
+	}
+	return problems, rows.Err()
+}
+
+// clearCoordinates runs one of the coordinate clearing updates.
+func (r *PostgresRepository) clearCoordinates(ctx context.Context, query string, ids []int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result, err := r.db.ExecContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 // RoutesRepository implementation
 
 func (r *PostgresRepository) SaveRoute(ctx context.Context, route *models.MPRoute) error {
@@ -156,6 +271,7 @@ func (r *PostgresRepository) GetByID(ctx context.Context, mpRouteID int64) (*mod
 		&route.Latitude,
 		&route.Longitude,
 		&route.Aspect,
+		&route.AspectSource,
 		&route.Difficulty,
 		&route.Pitches,
 		&route.HeightFeet,
@@ -205,6 +321,7 @@ func (r *PostgresRepository) GetByIDs(ctx context.Context, mpRouteIDs []int64) (
 			&route.Latitude,
 			&route.Longitude,
 			&route.Aspect,
+			&route.AspectSource,
 			&route.Difficulty,
 			&route.Pitches,
 			&route.HeightFeet,
@@ -258,6 +375,16 @@ func (r *PostgresRepository) UpdateGPS(ctx context.Context, routeID int64, latit
 	return err
 }
 
+func (r *PostgresRepository) SetCommentAspect(ctx context.Context, mpRouteID int64, aspect string, evidence []string) error {
+	_, err := r.db.ExecContext(ctx, querySetCommentAspect, mpRouteID, aspect, pq.Array(evidence))
+	return err
+}
+
+func (r *PostgresRepository) ClearCommentAspect(ctx context.Context, mpRouteID int64) error {
+	_, err := r.db.ExecContext(ctx, queryClearCommentAspect, mpRouteID)
+	return err
+}
+
 func (r *PostgresRepository) GetIDsForArea(ctx context.Context, mpAreaID string) ([]string, error) {
 	rows, err := r.db.QueryContext(ctx, queryGetRouteIDsForArea, mpAreaID)
 	if err != nil {
@@ -297,6 +424,7 @@ func (r *PostgresRepository) GetWithGPSByArea(ctx context.Context, mpAreaID int6
 			&route.Latitude,
 			&route.Longitude,
 			&route.Aspect,
+			&route.AspectSource,
 			&route.Difficulty,
 			&route.Pitches,
 			&route.HeightFeet,
@@ -327,11 +455,89 @@ func (r *PostgresRepository) UpsertRoute(ctx context.Context, mpRouteID, mpAreaI
 	return err
 }
 
+func (r *PostgresRepository) MarkDiscovered(ctx context.Context, mpRouteID int64) error {
+	_, err := r.db.ExecContext(ctx, queryMarkRouteDiscovered, mpRouteID)
+	return err
+}
+
 func (r *PostgresRepository) UpdateRouteDetails(ctx context.Context, mpRouteID int64, difficulty *string, pitches *int, heightFeet *int, mpRating, popularity *float64, descriptionText, locationText, protectionText, safetyText *string) error {
 	_, err := r.db.ExecContext(ctx, queryUpdateRouteDetails, mpRouteID, difficulty, pitches, heightFeet, mpRating, popularity, descriptionText, locationText, protectionText, safetyText)
 	return err
 }
 
+func (r *PostgresRepository) CountLocationMismatches(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, queryCountLocationMismatches).Scan(&count)
+	return count, err
+}
+
+func (r *PostgresRepository) GetLocationMismatches(ctx context.Context, limit int) ([]RouteLocationMismatch, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetLocationMismatches, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mismatches []RouteLocationMismatch
+	for rows.Next() {
+		var m RouteLocationMismatch
+		var routeLocationID sql.NullInt64
+		if err := rows.Scan(&m.MPRouteID, &m.RouteName, &m.MPAreaID, &routeLocationID, &m.AreaLocationID); err != nil {
+			return nil, err
+		}
+		if routeLocationID.Valid {
+			id := int(routeLocationID.Int64)
+			m.RouteLocationID = &id
+		}
+		mismatches = append(mismatches, m)
+	}
+	return mismatches, rows.Err()
+}
+
+func (r *PostgresRepository) ReconcileLocationMismatches(ctx context.Context, batchSize int) (int64, error) {
+	result, err := r.db.ExecContext(ctx, queryReconcileLocationMismatches, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *PostgresRepository) GetRouteCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]CoordinateProblem, error) {
+	return r.getCoordinateProblems(ctx, queryGetRouteCoordinateProblems, maxDistanceKm)
+}
+
+func (r *PostgresRepository) ClearRouteCoordinates(ctx context.Context, mpRouteIDs []int64) (int64, error) {
+	return r.clearCoordinates(ctx, queryClearRouteCoordinates, mpRouteIDs)
+}
+
+func (r *PostgresRepository) GetGradeCountsByLocation(ctx context.Context, locationID int, routeType string) ([]GradeCount, error) {
+	return r.getGradeCounts(ctx, queryGetGradeCountsByLocation, locationID, routeType)
+}
+
+func (r *PostgresRepository) GetGradeCountsByArea(ctx context.Context, mpAreaID int64, routeType string) ([]GradeCount, error) {
+	return r.getGradeCounts(ctx, queryGetGradeCountsByArea, mpAreaID, routeType)
+}
+
+// getGradeCounts runs a grade count query taking a location or area ID and
+// a route type.
+func (r *PostgresRepository) getGradeCounts(ctx context.Context, query string, id interface{}, routeType string) ([]GradeCount, error) {
+	rows, err := r.db.QueryContext(ctx, query, id, routeType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []GradeCount
+	for rows.Next() {
+		var gc GradeCount
+		if err := rows.Scan(&gc.GradeOrder, &gc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, gc)
+	}
+	return counts, rows.Err()
+}
+
 // TicksRepository implementation
 
 func (r *PostgresRepository) SaveTick(ctx context.Context, tick *models.MPTick) error {
@@ -372,6 +578,59 @@ func (r *PostgresRepository) UpsertTick(ctx context.Context, mpRouteID int64, us
 	return err
 }
 
+func (r *PostgresRepository) GetTickedRouteIDs(ctx context.Context, userName string, routeIDs []int64) ([]int64, error) {
+	if len(routeIDs) == 0 {
+		return []int64{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryGetTickedRouteIDs, pq.Array(routeIDs), userName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ticked := []int64{}
+	for rows.Next() {
+		var routeID int64
+		if err := rows.Scan(&routeID); err != nil {
+			return nil, err
+		}
+		ticked = append(ticked, routeID)
+	}
+	return ticked, rows.Err()
+}
+
+func (r *PostgresRepository) GetTicksForRoutes(ctx context.Context, routeIDs []int64, since time.Time) ([]models.MPTick, error) {
+	if len(routeIDs) == 0 {
+		return []models.MPTick{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryGetTicksForRoutes, pq.Array(routeIDs), since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ticks := []models.MPTick{}
+	for rows.Next() {
+		var tick models.MPTick
+		if err := rows.Scan(
+			&tick.ID,
+			&tick.MPRouteID,
+			&tick.UserName,
+			&tick.ClimbedAt,
+			&tick.Style,
+			&tick.Comment,
+			&tick.CreatedAt,
+			&tick.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		ticks = append(ticks, tick)
+	}
+	return ticks, rows.Err()
+}
+
 // CommentsRepository implementation
 
 func (r *PostgresRepository) SaveAreaComment(ctx context.Context, mpCommentID, mpAreaID int64, userName, commentText string, commentedAt time.Time) error {
@@ -427,6 +686,92 @@ func (r *PostgresRepository) UpsertRouteComment(ctx context.Context, mpCommentID
 	return err
 }
 
+func (r *PostgresRepository) GetComments(ctx context.Context, areaIDs, routeIDs []int64) ([]models.MPComment, error) {
+	if len(areaIDs) == 0 && len(routeIDs) == 0 {
+		return []models.MPComment{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, queryGetComments, pq.Array(areaIDs), pq.Array(routeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []models.MPComment{}
+	for rows.Next() {
+		var c models.MPComment
+		if err := rows.Scan(
+			&c.ID,
+			&c.MPCommentID,
+			&c.CommentType,
+			&c.MPAreaID,
+			&c.MPRouteID,
+			&c.UserName,
+			&c.UserID,
+			&c.CommentText,
+			&c.CommentedAt,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+	return comments, rows.Err()
+}
+
+func (r *PostgresRepository) GetAreaCommentTexts(ctx context.Context, mpAreaID int64) ([]string, error) {
+	return r.queryCommentTexts(ctx, queryGetAreaCommentTexts, mpAreaID)
+}
+
+func (r *PostgresRepository) GetRouteCommentTexts(ctx context.Context, mpRouteID int64) ([]string, error) {
+	return r.queryCommentTexts(ctx, queryGetRouteCommentTexts, mpRouteID)
+}
+
+func (r *PostgresRepository) queryCommentTexts(ctx context.Context, query string, mpID int64) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, mpID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var texts []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, err
+		}
+		texts = append(texts, text)
+	}
+	return texts, rows.Err()
+}
+
+func (r *PostgresRepository) GetTargetsMissingConditionsBeta(ctx context.Context, targetType string) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, queryGetTargetsMissingConditionsBeta, targetType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *PostgresRepository) SaveConditionsBeta(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error {
+	if tags == nil {
+		tags = []string{}
+	}
+	_, err := r.db.ExecContext(ctx, querySaveConditionsBeta, targetType, mpID, pq.Array(tags), commentCount)
+	return err
+}
+
 // SyncRepository implementation
 
 func (r *PostgresRepository) UpdateRoutePriorities(ctx context.Context) error {
@@ -463,22 +808,15 @@ func (r *PostgresRepository) GetLocationRoutesDueForSync(ctx context.Context, sy
 	return routeIDs, rows.Err()
 }
 
-func (r *PostgresRepository) GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error) {
-	var interval string
-	switch priority {
-	case "high":
-		interval = "24 hours"
-	case "medium":
-		interval = "7 days"
-	case "low":
-		interval = "30 days"
-	default:
-		return nil, fmt.Errorf("invalid priority: %s (must be 'high', 'medium', or 'low')", priority)
+func (r *PostgresRepository) GetRoutesDueForTickSync(ctx context.Context, priority Priority) ([]int64, error) {
+	interval, err := priority.syncInterval()
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(queryGetRoutesDueForTickSyncTemplate, interval)
 
-	rows, err := r.db.QueryContext(ctx, query, priority)
+	rows, err := r.db.QueryContext(ctx, query, string(priority))
 	if err != nil {
 		return nil, err
 	}
@@ -496,22 +834,15 @@ func (r *PostgresRepository) GetRoutesDueForTickSync(ctx context.Context, priori
 	return routeIDs, rows.Err()
 }
 
-func (r *PostgresRepository) GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error) {
-	var interval string
-	switch priority {
-	case "high":
-		interval = "24 hours"
-	case "medium":
-		interval = "7 days"
-	case "low":
-		interval = "30 days"
-	default:
-		return nil, fmt.Errorf("invalid priority: %s (must be 'high', 'medium', or 'low')", priority)
+func (r *PostgresRepository) GetRoutesDueForCommentSync(ctx context.Context, priority Priority) ([]int64, error) {
+	interval, err := priority.syncInterval()
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(queryGetRoutesDueForCommentSyncTemplate, interval)
 
-	rows, err := r.db.QueryContext(ctx, query, priority)
+	rows, err := r.db.QueryContext(ctx, query, string(priority))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,54 @@
+package mountainproject
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Priority is the sync priority tier of a non-location route. The values
+// match the sync_priority column set by UpdateRoutePriorities.
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"   // synced daily
+	PriorityMedium Priority = "medium" // synced weekly
+	PriorityLow    Priority = "low"    // synced monthly
+)
+
+// Priorities lists every valid priority, most frequently synced first.
+var Priorities = []Priority{PriorityHigh, PriorityMedium, PriorityLow}
+
+// ErrInvalidPriority is returned (wrapped) for a priority outside Priorities.
+var ErrInvalidPriority = errors.New("invalid priority")
+
+// ParsePriority converts s to a Priority, returning an error wrapping
+// ErrInvalidPriority if it is not a valid tier.
+func ParsePriority(s string) (Priority, error) {
+	p := Priority(s)
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// Validate returns an error wrapping ErrInvalidPriority unless p is one of
+// Priorities.
+func (p Priority) Validate() error {
+	_, err := p.syncInterval()
+	return err
+}
+
+// syncInterval is how long a route at this priority goes between syncs, as a
+// Postgres interval literal.
+func (p Priority) syncInterval() (string, error) {
+	switch p {
+	case PriorityHigh:
+		return "24 hours", nil
+	case PriorityMedium:
+		return "7 days", nil
+	case PriorityLow:
+		return "30 days", nil
+	default:
+		return "", fmt.Errorf("%w: %q (must be 'high', 'medium', or 'low')", ErrInvalidPriority, string(p))
+	}
+}
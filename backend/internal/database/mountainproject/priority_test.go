@@ -0,0 +1,23 @@
+package mountainproject_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+)
+
+func TestParsePriority(t *testing.T) {
+	for _, p := range mountainproject.Priorities {
+		got, err := mountainproject.ParsePriority(string(p))
+		if err != nil || got != p {
+			t.Errorf("ParsePriority(%q) = %q, %v, want %q, nil", p, got, err, p)
+		}
+	}
+
+	for _, s := range []string{"", "High", "urgent"} {
+		if _, err := mountainproject.ParsePriority(s); !errors.Is(err, mountainproject.ErrInvalidPriority) {
+			t.Errorf("ParsePriority(%q) error = %v, want ErrInvalidPriority", s, err)
+		}
+	}
+}
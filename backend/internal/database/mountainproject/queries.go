@@ -46,6 +46,16 @@ const queryGetAreaByID = `
 	WHERE mp_area_id = $1
 `
 
+// queryGetAreasByLocation retrieves all Mountain Project areas linked to a location.
+// Indexes: idx_mp_areas_location_id
+const queryGetAreasByLocation = `
+	SELECT id, mp_area_id, name, parent_mp_area_id, area_type,
+	       location_id, latitude, longitude, last_synced_at, created_at, updated_at
+	FROM woulder.mp_areas
+	WHERE location_id = $1
+	ORDER BY mp_area_id
+`
+
 // queryUpdateAreaRouteCount updates the cached route count for an area.
 const queryUpdateAreaRouteCount = `
 	UPDATE woulder.mp_areas
@@ -77,6 +87,135 @@ const queryGetAllStateConfigs = `
 	ORDER BY display_order, state_name
 `
 
+// queryGetAreaLinkProblems lists areas whose parent link or location_id is
+// inconsistent with the hierarchy. Cycles are found by walking each area's
+// parent chain; the walk is capped at 64 levels, far deeper than any real MP
+// hierarchy, so chains that loop without returning to their start end.
+const queryGetAreaLinkProblems = `
+	WITH RECURSIVE parent_walk AS (
+		SELECT mp_area_id AS start_id, parent_mp_area_id AS current_id, 1 AS depth
+		FROM woulder.mp_areas
+		WHERE parent_mp_area_id IS NOT NULL
+		  AND parent_mp_area_id <> mp_area_id
+
+		UNION ALL
+
+		SELECT w.start_id, p.parent_mp_area_id, w.depth + 1
+		FROM parent_walk w
+		JOIN woulder.mp_areas p ON p.mp_area_id = w.current_id
+		WHERE p.parent_mp_area_id IS NOT NULL
+		  AND w.current_id <> w.start_id
+		  AND w.depth < 64
+	),
+	problems AS (
+		SELECT a.mp_area_id, a.name, a.parent_mp_area_id, a.location_id,
+		       NULL::INTEGER AS parent_location_id, 'self_parent' AS problem
+		FROM woulder.mp_areas a
+		WHERE a.parent_mp_area_id = a.mp_area_id
+
+		UNION ALL
+
+		SELECT a.mp_area_id, a.name, a.parent_mp_area_id, a.location_id,
+		       NULL::INTEGER, 'orphan'
+		FROM woulder.mp_areas a
+		WHERE a.parent_mp_area_id IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM woulder.mp_areas p WHERE p.mp_area_id = a.parent_mp_area_id
+		  )
+
+		UNION ALL
+
+		SELECT a.mp_area_id, a.name, a.parent_mp_area_id, a.location_id,
+		       NULL::INTEGER, 'cycle'
+		FROM woulder.mp_areas a
+		WHERE a.mp_area_id IN (
+			SELECT start_id FROM parent_walk WHERE current_id = start_id
+		)
+
+		UNION ALL
+
+		SELECT a.mp_area_id, a.name, a.parent_mp_area_id, a.location_id,
+		       p.location_id, 'location_mismatch'
+		FROM woulder.mp_areas a
+		JOIN woulder.mp_areas p ON p.mp_area_id = a.parent_mp_area_id
+		WHERE a.parent_mp_area_id <> a.mp_area_id
+		  AND p.location_id IS NOT NULL
+		  AND a.location_id IS DISTINCT FROM p.location_id
+	)
+	SELECT mp_area_id, name, parent_mp_area_id, location_id, parent_location_id, problem
+	FROM problems
+	ORDER BY problem, mp_area_id
+`
+
+// queryDetachAreaParents makes the given areas roots.
+const queryDetachAreaParents = `
+	UPDATE woulder.mp_areas
+	SET parent_mp_area_id = NULL, updated_at = NOW()
+	WHERE mp_area_id = ANY($1)
+	  AND parent_mp_area_id IS NOT NULL
+`
+
+// queryReconcileAreaLocations copies the parent's location_id onto areas
+// whose location_id differs from it. Parents with no location are skipped so
+// an area is never detached from a location.
+const queryReconcileAreaLocations = `
+	UPDATE woulder.mp_areas
+	SET location_id = p.location_id, updated_at = NOW()
+	FROM woulder.mp_areas p
+	WHERE p.mp_area_id = mp_areas.parent_mp_area_id
+	  AND mp_areas.parent_mp_area_id <> mp_areas.mp_area_id
+	  AND p.location_id IS NOT NULL
+	  AND mp_areas.location_id IS DISTINCT FROM p.location_id
+`
+
+// queryCoordinateProblem classifies the checked CTE row's coordinates as
+// 'out_of_range', 'null_island' or, when they are more than $1 km from the
+// reference area, 'far_from_area'; NULL means they look fine. The range and
+// null island rules match geo.IsPlausibleCoord.
+const queryCoordinateProblem = `
+	CASE
+		WHEN latitude NOT BETWEEN -90 AND 90 OR longitude NOT BETWEEN -180 AND 180 THEN 'out_of_range'
+		WHEN abs(latitude) < 0.01 AND abs(longitude) < 0.01 THEN 'null_island'
+		WHEN distance_km > $1 THEN 'far_from_area'
+	END
+`
+
+// queryGetAreaCoordinateProblems lists areas with implausible coordinates.
+// Distance is measured from the parent area, skipping root parents (states
+// and countries span hundreds of km, so their centers say little about where
+// a child belongs) and parents that are themselves at null island.
+const queryGetAreaCoordinateProblems = `
+	WITH checked AS (
+		SELECT a.mp_area_id, a.name, a.latitude, a.longitude, p.mp_area_id AS reference_area_id,
+		       ST_Distance(a.geog, p.geog) / 1000.0 AS distance_km
+		FROM woulder.mp_areas a
+		LEFT JOIN woulder.mp_areas p
+		       ON p.mp_area_id = a.parent_mp_area_id
+		      AND p.mp_area_id <> a.mp_area_id
+		      AND p.parent_mp_area_id IS NOT NULL
+		      AND NOT (abs(p.latitude) < 0.01 AND abs(p.longitude) < 0.01)
+		WHERE a.latitude IS NOT NULL AND a.longitude IS NOT NULL
+	),
+	problems AS (
+		SELECT *, ` + queryCoordinateProblem + ` AS problem
+		FROM checked
+	)
+	SELECT mp_area_id, name, latitude, longitude, reference_area_id,
+	       CASE WHEN problem = 'far_from_area' THEN distance_km END, problem
+	FROM problems
+	WHERE problem IS NOT NULL
+	ORDER BY problem, mp_area_id
+`
+
+// queryClearAreaCoordinates nulls the coordinates of the given areas. geog is
+// generated from them, so it is cleared too.
+const queryClearAreaCoordinates = `
+	UPDATE woulder.mp_areas
+	SET latitude = NULL, longitude = NULL, updated_at = NOW()
+	WHERE mp_area_id = ANY($1)
+	  AND (latitude IS NOT NULL OR longitude IS NOT NULL)
+`
+
 // RoutesRepository queries
 
 // querySaveRoute inserts or updates a Mountain Project route.
@@ -91,12 +230,16 @@ const queryGetAllStateConfigs = `
 // for no functional benefit. The IS DISTINCT FROM guard short-circuits
 // the no-op case at the Postgres level (no tuple version, no WAL, no
 // index update).
+//
+// The given aspect is the computed one. It is always stored in
+// computed_aspect but only replaces aspect when aspect_source is still
+// 'computed', so a comment-sourced aspect survives re-scrapes.
 const querySaveRoute = `
 	INSERT INTO woulder.mp_routes (
 		mp_route_id, mp_area_id, name, route_type, rating, location_id,
-		latitude, longitude, aspect
+		latitude, longitude, aspect, computed_aspect
 	)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
 	ON CONFLICT (mp_route_id) DO UPDATE SET
 		mp_area_id = EXCLUDED.mp_area_id,
 		name = EXCLUDED.name,
@@ -105,21 +248,22 @@ const querySaveRoute = `
 		location_id = EXCLUDED.location_id,
 		latitude = EXCLUDED.latitude,
 		longitude = EXCLUDED.longitude,
-		aspect = EXCLUDED.aspect
-	WHERE mp_routes.mp_area_id  IS DISTINCT FROM EXCLUDED.mp_area_id
-	   OR mp_routes.name        IS DISTINCT FROM EXCLUDED.name
-	   OR mp_routes.route_type  IS DISTINCT FROM EXCLUDED.route_type
-	   OR mp_routes.rating      IS DISTINCT FROM EXCLUDED.rating
-	   OR mp_routes.location_id IS DISTINCT FROM EXCLUDED.location_id
-	   OR mp_routes.latitude    IS DISTINCT FROM EXCLUDED.latitude
-	   OR mp_routes.longitude   IS DISTINCT FROM EXCLUDED.longitude
-	   OR mp_routes.aspect      IS DISTINCT FROM EXCLUDED.aspect
+		aspect = CASE WHEN mp_routes.aspect_source = 'comment' THEN mp_routes.aspect ELSE EXCLUDED.aspect END,
+		computed_aspect = EXCLUDED.computed_aspect
+	WHERE mp_routes.mp_area_id      IS DISTINCT FROM EXCLUDED.mp_area_id
+	   OR mp_routes.name            IS DISTINCT FROM EXCLUDED.name
+	   OR mp_routes.route_type      IS DISTINCT FROM EXCLUDED.route_type
+	   OR mp_routes.rating          IS DISTINCT FROM EXCLUDED.rating
+	   OR mp_routes.location_id     IS DISTINCT FROM EXCLUDED.location_id
+	   OR mp_routes.latitude        IS DISTINCT FROM EXCLUDED.latitude
+	   OR mp_routes.longitude       IS DISTINCT FROM EXCLUDED.longitude
+	   OR mp_routes.computed_aspect IS DISTINCT FROM EXCLUDED.computed_aspect
 `
 
 // queryGetRouteByID retrieves a Mountain Project route by its MP route ID.
 const queryGetRouteByID = `
 	SELECT id, mp_route_id, mp_area_id, name, route_type, rating,
-		   location_id, latitude, longitude, aspect,
+		   location_id, latitude, longitude, aspect, aspect_source,
 		   difficulty, pitches, height_feet, mp_rating, popularity,
 		   description_text, location_text, protection_text, safety_text,
 		   created_at, updated_at
@@ -130,7 +274,7 @@ const queryGetRouteByID = `
 // queryGetRoutesByIDs retrieves multiple Mountain Project routes by IDs.
 const queryGetRoutesByIDs = `
 	SELECT id, mp_route_id, mp_area_id, name, route_type, rating,
-		   location_id, latitude, longitude, aspect,
+		   location_id, latitude, longitude, aspect, aspect_source,
 		   difficulty, pitches, height_feet, mp_rating, popularity,
 		   description_text, location_text, protection_text, safety_text,
 		   created_at, updated_at
@@ -154,7 +298,7 @@ const queryGetRoutesWithGPSByArea = `
 		INNER JOIN area_tree at ON a.parent_mp_area_id = at.mp_area_id
 	)
 	SELECT id, mp_route_id, mp_area_id, name, route_type, rating,
-	       location_id, latitude, longitude, aspect,
+	       location_id, latitude, longitude, aspect, aspect_source,
 	       difficulty, pitches, height_feet, mp_rating, popularity,
 	       description_text, location_text, protection_text, safety_text,
 	       created_at, updated_at
@@ -172,10 +316,13 @@ const queryGetAllRouteIDsForLocation = `
 	WHERE location_id = $1
 `
 
-// queryUpdateRouteGPS updates GPS coordinates and aspect for a route.
+// queryUpdateRouteGPS updates GPS coordinates and computed aspect for a
+// route. aspect follows the computed value unless it came from comments.
 const queryUpdateRouteGPS = `
 	UPDATE woulder.mp_routes
-	SET latitude = $1, longitude = $2, aspect = $3, updated_at = NOW()
+	SET latitude = $1, longitude = $2, computed_aspect = $3,
+		aspect = CASE WHEN aspect_source = 'comment' THEN aspect ELSE $3 END,
+		updated_at = NOW()
 	WHERE mp_route_id = $4
 `
 
@@ -190,16 +337,17 @@ const queryGetRouteIDsForArea = `
 // queryUpsertRoute inserts or updates a route with full update semantics.
 // Used by mountainprojectsync for compatibility.
 //
-// PERFORMANCE: See querySaveRoute. Same IS DISTINCT FROM guard, with the
-// added wrinkle that updated_at = NOW() in the SET list is fine because
-// the WHERE clause skips the entire UPDATE branch when nothing changed,
-// so updated_at only advances when there is a real content change.
+// PERFORMANCE: See querySaveRoute. Same IS DISTINCT FROM guard and
+// computed_aspect handling, with the added wrinkle that updated_at = NOW()
+// in the SET list is fine because the WHERE clause skips the entire UPDATE
+// branch when nothing changed, so updated_at only advances when there is a
+// real content change.
 const queryUpsertRoute = `
 	INSERT INTO woulder.mp_routes (
 		mp_route_id, mp_area_id, location_id, name, route_type, rating,
-		latitude, longitude, aspect
+		latitude, longitude, aspect, computed_aspect
 	)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
 	ON CONFLICT (mp_route_id) DO UPDATE SET
 		mp_area_id = EXCLUDED.mp_area_id,
 		name = EXCLUDED.name,
@@ -207,15 +355,42 @@ const queryUpsertRoute = `
 		rating = EXCLUDED.rating,
 		latitude = EXCLUDED.latitude,
 		longitude = EXCLUDED.longitude,
-		aspect = EXCLUDED.aspect,
+		aspect = CASE WHEN mp_routes.aspect_source = 'comment' THEN mp_routes.aspect ELSE EXCLUDED.aspect END,
+		computed_aspect = EXCLUDED.computed_aspect,
 		updated_at = NOW()
-	WHERE mp_routes.mp_area_id IS DISTINCT FROM EXCLUDED.mp_area_id
-	   OR mp_routes.name       IS DISTINCT FROM EXCLUDED.name
-	   OR mp_routes.route_type IS DISTINCT FROM EXCLUDED.route_type
-	   OR mp_routes.rating     IS DISTINCT FROM EXCLUDED.rating
-	   OR mp_routes.latitude   IS DISTINCT FROM EXCLUDED.latitude
-	   OR mp_routes.longitude  IS DISTINCT FROM EXCLUDED.longitude
-	   OR mp_routes.aspect     IS DISTINCT FROM EXCLUDED.aspect
+	WHERE mp_routes.mp_area_id      IS DISTINCT FROM EXCLUDED.mp_area_id
+	   OR mp_routes.name            IS DISTINCT FROM EXCLUDED.name
+	   OR mp_routes.route_type      IS DISTINCT FROM EXCLUDED.route_type
+	   OR mp_routes.rating          IS DISTINCT FROM EXCLUDED.rating
+	   OR mp_routes.latitude        IS DISTINCT FROM EXCLUDED.latitude
+	   OR mp_routes.longitude       IS DISTINCT FROM EXCLUDED.longitude
+	   OR mp_routes.computed_aspect IS DISTINCT FROM EXCLUDED.computed_aspect
+`
+
+// querySetCommentAspect overrides a route's aspect with one extracted from
+// its comments. The guard skips the write when nothing changed.
+const querySetCommentAspect = `
+	UPDATE woulder.mp_routes
+	SET aspect = $2, aspect_source = 'comment', aspect_evidence = $3, updated_at = NOW()
+	WHERE mp_route_id = $1
+	  AND (aspect IS DISTINCT FROM $2
+	       OR aspect_source <> 'comment'
+	       OR aspect_evidence IS DISTINCT FROM $3)
+`
+
+// queryClearCommentAspect reverts a comment-sourced aspect to the computed
+// one. Routes whose aspect is already computed are untouched.
+const queryClearCommentAspect = `
+	UPDATE woulder.mp_routes
+	SET aspect = computed_aspect, aspect_source = 'computed', aspect_evidence = NULL, updated_at = NOW()
+	WHERE mp_route_id = $1 AND aspect_source = 'comment'
+`
+
+// queryMarkRouteDiscovered records when the new-route sweep first found a route.
+const queryMarkRouteDiscovered = `
+	UPDATE woulder.mp_routes
+	SET discovered_at = NOW()
+	WHERE mp_route_id = $1 AND discovered_at IS NULL
 `
 
 // queryUpdateRouteDetails updates detailed route information fields.
@@ -234,6 +409,113 @@ const queryUpdateRouteDetails = `
 	WHERE mp_route_id = $1
 `
 
+// queryLocationMismatchWhere matches routes whose location_id differs from
+// their parent area's. Areas with no location are skipped so reconciling never
+// detaches a route from a location.
+const queryLocationMismatchWhere = `
+	FROM woulder.mp_routes r
+	JOIN woulder.mp_areas a ON a.mp_area_id = r.mp_area_id
+	WHERE a.location_id IS NOT NULL
+	  AND r.location_id IS DISTINCT FROM a.location_id
+`
+
+// queryCountLocationMismatches counts routes whose location_id differs from their area's.
+const queryCountLocationMismatches = `
+	SELECT COUNT(*)
+` + queryLocationMismatchWhere
+
+// queryGetLocationMismatches lists routes whose location_id differs from their area's.
+const queryGetLocationMismatches = `
+	SELECT r.mp_route_id, r.name, r.mp_area_id, r.location_id, a.location_id
+` + queryLocationMismatchWhere + `
+	ORDER BY r.mp_route_id
+	LIMIT $1
+`
+
+// queryReconcileLocationMismatches sets location_id from the parent area for
+// the first $1 mismatched routes (by route ID).
+const queryReconcileLocationMismatches = `
+	UPDATE woulder.mp_routes
+	SET location_id = a.location_id, updated_at = NOW()
+	FROM woulder.mp_areas a
+	WHERE a.mp_area_id = mp_routes.mp_area_id
+	  AND mp_routes.mp_route_id IN (
+		SELECT r.mp_route_id
+` + queryLocationMismatchWhere + `
+		ORDER BY r.mp_route_id
+		LIMIT $1
+	  )
+`
+
+// queryGetRouteCoordinateProblems lists routes with implausible coordinates.
+// Distance is measured from the route's area, unless that area is at null
+// island itself.
+const queryGetRouteCoordinateProblems = `
+	WITH checked AS (
+		SELECT r.mp_route_id, r.name, r.latitude, r.longitude, a.mp_area_id AS reference_area_id,
+		       ST_Distance(r.geog, a.geog) / 1000.0 AS distance_km
+		FROM woulder.mp_routes r
+		LEFT JOIN woulder.mp_areas a
+		       ON a.mp_area_id = r.mp_area_id
+		      AND NOT (abs(a.latitude) < 0.01 AND abs(a.longitude) < 0.01)
+		WHERE r.latitude IS NOT NULL AND r.longitude IS NOT NULL
+	),
+	problems AS (
+		SELECT *, ` + queryCoordinateProblem + ` AS problem
+		FROM checked
+	)
+	SELECT mp_route_id, name, latitude, longitude, reference_area_id,
+	       CASE WHEN problem = 'far_from_area' THEN distance_km END, problem
+	FROM problems
+	WHERE problem IS NOT NULL
+	ORDER BY problem, mp_route_id
+`
+
+// queryClearRouteCoordinates nulls the coordinates of the given routes.
+const queryClearRouteCoordinates = `
+	UPDATE woulder.mp_routes
+	SET latitude = NULL, longitude = NULL, updated_at = NOW()
+	WHERE mp_route_id = ANY($1)
+	  AND (latitude IS NOT NULL OR longitude IS NOT NULL)
+`
+
+// routeTypeFilter matches routes whose comma-separated route_type includes
+// $2 as a whole type, or every route when $2 is empty. Whole-type matching
+// keeps "TR" from matching "Trad".
+const routeTypeFilter = `($2 = '' OR r.route_type ~* ('(^|,)\s*' || $2 || '\s*(,|$)'))`
+
+// queryGetGradeCountsByLocation counts location $1's routes per grade_order.
+const queryGetGradeCountsByLocation = `
+	SELECT r.grade_order, COUNT(*)
+	FROM woulder.mp_routes r
+	WHERE r.location_id = $1
+	  AND ` + routeTypeFilter + `
+	GROUP BY r.grade_order
+	ORDER BY r.grade_order NULLS LAST
+`
+
+// queryGetGradeCountsByArea counts the routes in area $1's subtree per
+// grade_order.
+const queryGetGradeCountsByArea = `
+	WITH RECURSIVE area_tree AS (
+		SELECT mp_area_id
+		FROM woulder.mp_areas
+		WHERE mp_area_id = $1
+
+		UNION ALL
+
+		SELECT a.mp_area_id
+		FROM woulder.mp_areas a
+		INNER JOIN area_tree at ON a.parent_mp_area_id = at.mp_area_id
+	)
+	SELECT r.grade_order, COUNT(*)
+	FROM woulder.mp_routes r
+	WHERE r.mp_area_id IN (SELECT mp_area_id FROM area_tree)
+	  AND ` + routeTypeFilter + `
+	GROUP BY r.grade_order
+	ORDER BY r.grade_order NULLS LAST
+`
+
 // TicksRepository queries
 
 // querySaveTick inserts a Mountain Project tick.
@@ -261,6 +543,27 @@ const queryGetLastTickTimestamp = `
 	WHERE mp_route_id = $1
 `
 
+// queryGetTickedRouteIDs returns which of the given routes a user has ticked.
+// Indexes: (mp_route_id, user_name, climbed_at) UNIQUE
+const queryGetTickedRouteIDs = `
+	SELECT DISTINCT mp_route_id
+	FROM woulder.mp_ticks
+	WHERE mp_route_id = ANY($1)
+		AND user_name = $2
+	ORDER BY mp_route_id
+`
+
+// queryGetTicksForRoutes retrieves ticks on a set of routes since a time.
+// Indexes: idx_mp_ticks_route_climbed (mp_route_id, climbed_at DESC)
+const queryGetTicksForRoutes = `
+	SELECT id, mp_route_id, COALESCE(user_name, ''), climbed_at, COALESCE(style, ''),
+	       comment, created_at, updated_at
+	FROM woulder.mp_ticks
+	WHERE mp_route_id = ANY($1)
+		AND climbed_at >= $2
+	ORDER BY mp_route_id, climbed_at
+`
+
 // CommentsRepository queries
 
 // querySaveAreaComment inserts or updates an area comment.
@@ -316,6 +619,63 @@ const queryUpdateRouteCommentSyncTimestamp = `
 	WHERE mp_route_id = $1
 `
 
+// queryGetComments retrieves every comment on a set of areas and routes.
+// $1 = area IDs, $2 = route IDs.
+// Indexes: idx_mp_comments_area, idx_mp_comments_route (both partial)
+const queryGetComments = `
+	SELECT id, mp_comment_id, comment_type, mp_area_id, mp_route_id,
+	       user_name, user_id, comment_text, commented_at, created_at, updated_at
+	FROM woulder.mp_comments
+	WHERE (comment_type = 'area' AND mp_area_id = ANY($1))
+	   OR (comment_type = 'route' AND mp_route_id = ANY($2))
+	ORDER BY mp_comment_id
+`
+
+// queryGetAreaCommentTexts retrieves the text of every comment on an area.
+// Indexes: idx_mp_comments_area (partial, comment_type = 'area')
+const queryGetAreaCommentTexts = `
+	SELECT comment_text
+	FROM woulder.mp_comments
+	WHERE comment_type = 'area' AND mp_area_id = $1
+	ORDER BY commented_at DESC
+`
+
+// queryGetRouteCommentTexts retrieves the text of every comment on a route.
+// Indexes: idx_mp_comments_route (partial, comment_type = 'route')
+const queryGetRouteCommentTexts = `
+	SELECT comment_text
+	FROM woulder.mp_comments
+	WHERE comment_type = 'route' AND mp_route_id = $1
+	ORDER BY commented_at DESC
+`
+
+// queryGetTargetsMissingConditionsBeta lists the areas or routes that have
+// comments but no conditions beta row, e.g. comments stored before
+// migration 000045. $1 = comment_type ('area' or 'route').
+const queryGetTargetsMissingConditionsBeta = `
+	SELECT DISTINCT COALESCE(c.mp_area_id, c.mp_route_id) AS mp_id
+	FROM woulder.mp_comments c
+	LEFT JOIN woulder.mp_conditions_beta b
+		ON b.target_type = c.comment_type
+		AND b.mp_id = COALESCE(c.mp_area_id, c.mp_route_id)
+	WHERE c.comment_type = $1 AND b.mp_id IS NULL
+	ORDER BY mp_id
+`
+
+// querySaveConditionsBeta upserts the extracted conditions tags for an area
+// or route. The guard skips the write when a re-sync produced the same tags
+// from the same number of comments.
+const querySaveConditionsBeta = `
+	INSERT INTO woulder.mp_conditions_beta (target_type, mp_id, tags, comment_count, extracted_at)
+	VALUES ($1, $2, $3, $4, NOW())
+	ON CONFLICT (target_type, mp_id) DO UPDATE SET
+		tags = EXCLUDED.tags,
+		comment_count = EXCLUDED.comment_count,
+		extracted_at = EXCLUDED.extracted_at
+	WHERE mp_conditions_beta.tags          IS DISTINCT FROM EXCLUDED.tags
+	   OR mp_conditions_beta.comment_count IS DISTINCT FROM EXCLUDED.comment_count
+`
+
 // queryUpsertAreaComment inserts or updates an area comment with user_id support.
 // Used by mountainprojectsync for compatibility.
 //
@@ -28,6 +28,10 @@ type AreasRepository interface {
 	// Returns nil if not found.
 	GetAreaByID(ctx context.Context, mpAreaID int64) (*models.MPArea, error)
 
+	// GetAreasByLocation retrieves all Mountain Project areas linked to a
+	// location, ordered by MP area ID.
+	GetAreasByLocation(ctx context.Context, locationID int) ([]models.MPArea, error)
+
 	// UpdateRouteCount updates the cached route count for an area.
 	UpdateRouteCount(ctx context.Context, mpAreaID string, total int) error
 
@@ -40,6 +44,28 @@ type AreasRepository interface {
 
 	// GetAllStateConfigs retrieves all state configurations ordered by display_order.
 	GetAllStateConfigs(ctx context.Context) ([]StateConfig, error)
+
+	// GetAreaLinkProblems returns areas whose parent link or location_id is
+	// inconsistent with the hierarchy, ordered by problem then area ID.
+	GetAreaLinkProblems(ctx context.Context) ([]AreaLinkProblem, error)
+
+	// DetachAreaParents clears parent_mp_area_id on the given areas, making
+	// them roots. Returns the number of areas updated.
+	DetachAreaParents(ctx context.Context, mpAreaIDs []int64) (int64, error)
+
+	// ReconcileAreaLocations copies the parent's location_id onto areas whose
+	// location_id differs from it, one hierarchy level per call. Parents
+	// without a location are skipped. Returns the number of areas updated.
+	ReconcileAreaLocations(ctx context.Context) (int64, error)
+
+	// GetAreaCoordinateProblems returns areas whose coordinates are out of
+	// range, at null island, or more than maxDistanceKm from their parent
+	// area, ordered by problem then area ID.
+	GetAreaCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]CoordinateProblem, error)
+
+	// ClearAreaCoordinates nulls latitude and longitude on the given areas.
+	// Returns the number of areas updated.
+	ClearAreaCoordinates(ctx context.Context, mpAreaIDs []int64) (int64, error)
 }
 
 // RoutesRepository handles Mountain Project route operations.
@@ -59,9 +85,18 @@ type RoutesRepository interface {
 	// GetAllIDsForLocation returns all route IDs associated with a location.
 	GetAllIDsForLocation(ctx context.Context, locationID int) ([]int64, error)
 
-	// UpdateGPS updates only the GPS coordinates and aspect for a route.
+	// UpdateGPS updates only the GPS coordinates and computed aspect for a
+	// route. A comment-sourced aspect is kept.
 	UpdateGPS(ctx context.Context, routeID int64, latitude, longitude float64, aspect string) error
 
+	// SetCommentAspect overrides a route's aspect with one extracted from its
+	// comments, recording the matched phrases as evidence.
+	SetCommentAspect(ctx context.Context, mpRouteID int64, aspect string, evidence []string) error
+
+	// ClearCommentAspect reverts a comment-sourced aspect to the computed one.
+	// Routes without a comment-sourced aspect are unchanged.
+	ClearCommentAspect(ctx context.Context, mpRouteID int64) error
+
 	// GetIDsForArea retrieves all route IDs currently in an area.
 	GetIDsForArea(ctx context.Context, mpAreaID string) ([]string, error)
 
@@ -72,8 +107,43 @@ type RoutesRepository interface {
 	// UpsertRoute inserts or updates a route (compatibility with mountainprojectsync).
 	UpsertRoute(ctx context.Context, mpRouteID, mpAreaID int64, locationID *int, name, routeType, rating string, lat, lon *float64, aspect *string) error
 
+	// MarkDiscovered stamps discovered_at on a route found by the new-route
+	// sweep. Routes that already have a discovery time are left unchanged.
+	MarkDiscovered(ctx context.Context, mpRouteID int64) error
+
 	// UpdateRouteDetails updates the detailed route information fields.
 	UpdateRouteDetails(ctx context.Context, mpRouteID int64, difficulty *string, pitches *int, heightFeet *int, mpRating, popularity *float64, descriptionText, locationText, protectionText, safetyText *string) error
+
+	// CountLocationMismatches returns how many routes have a location_id that
+	// differs from their parent area's. Areas without a location are ignored.
+	CountLocationMismatches(ctx context.Context) (int, error)
+
+	// GetLocationMismatches returns up to limit routes whose location_id differs
+	// from their parent area's, ordered by route ID.
+	GetLocationMismatches(ctx context.Context, limit int) ([]RouteLocationMismatch, error)
+
+	// ReconcileLocationMismatches copies the parent area's location_id onto at
+	// most batchSize mismatched routes and returns how many were updated.
+	ReconcileLocationMismatches(ctx context.Context, batchSize int) (int64, error)
+
+	// GetRouteCoordinateProblems returns routes whose coordinates are out of
+	// range, at null island, or more than maxDistanceKm from their area,
+	// ordered by problem then route ID.
+	GetRouteCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]CoordinateProblem, error)
+
+	// ClearRouteCoordinates nulls latitude and longitude on the given routes.
+	// Returns the number of routes updated.
+	ClearRouteCoordinates(ctx context.Context, mpRouteIDs []int64) (int64, error)
+
+	// GetGradeCountsByLocation counts a location's routes per grade_order.
+	// routeType, when not empty, keeps only routes with that MP route type
+	// (e.g. "Boulder"), matched case-insensitively against each type of
+	// multi-type routes.
+	GetGradeCountsByLocation(ctx context.Context, locationID int, routeType string) ([]GradeCount, error)
+
+	// GetGradeCountsByArea is GetGradeCountsByLocation over the routes in an
+	// area and its subareas.
+	GetGradeCountsByArea(ctx context.Context, mpAreaID int64, routeType string) ([]GradeCount, error)
 }
 
 // TicksRepository handles Mountain Project tick operations.
@@ -87,6 +157,14 @@ type TicksRepository interface {
 
 	// UpsertTick inserts or updates a tick (compatibility with mountainprojectsync).
 	UpsertTick(ctx context.Context, mpRouteID int64, userName string, climbedAt time.Time, style string, comment *string) error
+
+	// GetTickedRouteIDs returns which of routeIDs userName has ticked, in
+	// ascending order.
+	GetTickedRouteIDs(ctx context.Context, userName string, routeIDs []int64) ([]int64, error)
+
+	// GetTicksForRoutes retrieves the ticks on routeIDs climbed at or after
+	// since, ordered by route then climb time.
+	GetTicksForRoutes(ctx context.Context, routeIDs []int64, since time.Time) ([]models.MPTick, error)
 }
 
 // CommentsRepository handles Mountain Project comment operations.
@@ -102,8 +180,32 @@ type CommentsRepository interface {
 
 	// UpsertRouteComment inserts or updates a route comment (compatibility with mountainprojectsync).
 	UpsertRouteComment(ctx context.Context, mpCommentID, mpRouteID int64, userName string, userID *string, commentText string, commentedAt time.Time) error
+
+	// GetAreaCommentTexts retrieves the text of all stored comments for an area, newest first.
+	GetAreaCommentTexts(ctx context.Context, mpAreaID int64) ([]string, error)
+
+	// GetRouteCommentTexts retrieves the text of all stored comments for a route, newest first.
+	GetRouteCommentTexts(ctx context.Context, mpRouteID int64) ([]string, error)
+
+	// GetComments retrieves all stored comments on areaIDs and routeIDs,
+	// ordered by MP comment ID.
+	GetComments(ctx context.Context, areaIDs, routeIDs []int64) ([]models.MPComment, error)
+
+	// GetTargetsMissingConditionsBeta lists the IDs of areas or routes
+	// (per targetType) that have comments but no stored conditions beta.
+	GetTargetsMissingConditionsBeta(ctx context.Context, targetType string) ([]int64, error)
+
+	// SaveConditionsBeta stores the conditions tags extracted from a target's comments.
+	// targetType is ConditionsBetaTargetArea or ConditionsBetaTargetRoute.
+	SaveConditionsBeta(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error
 }
 
+// Conditions beta target types, matching mp_comments.comment_type.
+const (
+	ConditionsBetaTargetArea  = "area"
+	ConditionsBetaTargetRoute = "route"
+)
+
 // SyncRepository handles sync priority and scheduling operations.
 type SyncRepository interface {
 	// UpdateRoutePriorities recalculates sync priority for all NON-LOCATION routes.
@@ -115,12 +217,14 @@ type SyncRepository interface {
 	GetLocationRoutesDueForSync(ctx context.Context, syncType string) ([]int64, error)
 
 	// GetRoutesDueForTickSync returns NON-LOCATION routes due for tick syncing based on priority.
-	// Only returns routes WHERE location_id IS NULL.
-	GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error)
+	// Only returns routes WHERE location_id IS NULL. Returns an error wrapping
+	// ErrInvalidPriority for an unknown priority.
+	GetRoutesDueForTickSync(ctx context.Context, priority Priority) ([]int64, error)
 
 	// GetRoutesDueForCommentSync returns NON-LOCATION routes due for comment syncing based on priority.
-	// Only returns routes WHERE location_id IS NULL.
-	GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error)
+	// Only returns routes WHERE location_id IS NULL. Returns an error wrapping
+	// ErrInvalidPriority for an unknown priority.
+	GetRoutesDueForCommentSync(ctx context.Context, priority Priority) ([]int64, error)
 
 	// GetPriorityDistribution returns count of routes in each priority tier (for monitoring).
 	GetPriorityDistribution(ctx context.Context) (map[string]int, error)
@@ -132,6 +236,62 @@ type ChildArea struct {
 	Name     string
 }
 
+// Area link problem kinds reported by GetAreaLinkProblems.
+const (
+	AreaProblemSelfParent       = "self_parent"       // parent_mp_area_id is the area itself
+	AreaProblemOrphan           = "orphan"            // parent_mp_area_id names an area that is not stored
+	AreaProblemCycle            = "cycle"             // following parents leads back to the area
+	AreaProblemLocationMismatch = "location_mismatch" // location_id differs from the parent's
+)
+
+// AreaLinkProblem is an area whose parent link or location_id is
+// inconsistent with the rest of the area hierarchy.
+type AreaLinkProblem struct {
+	MPAreaID         int64
+	Name             string
+	ParentMPAreaID   *int64
+	LocationID       *int
+	ParentLocationID *int // set for location mismatches only
+	Problem          string
+}
+
+// Coordinate problem kinds reported by GetAreaCoordinateProblems and
+// GetRouteCoordinateProblems.
+const (
+	CoordProblemOutOfRange  = "out_of_range"  // latitude or longitude outside the valid range
+	CoordProblemNullIsland  = "null_island"   // at (0, 0), a stand-in for a missing position
+	CoordProblemFarFromArea = "far_from_area" // too far from the reference area to be right
+)
+
+// CoordinateProblem is an area or route whose stored coordinates are
+// implausible.
+type CoordinateProblem struct {
+	ID              int64 // mp_area_id or mp_route_id
+	Name            string
+	Latitude        float64
+	Longitude       float64
+	ReferenceAreaID *int64   // area the distance was measured from, if any
+	DistanceKm      *float64 // set for far_from_area only
+	Problem         string
+}
+
+// RouteLocationMismatch is a route whose location_id disagrees with its
+// parent area's location_id.
+type RouteLocationMismatch struct {
+	MPRouteID       int64
+	RouteName       string
+	MPAreaID        int64
+	RouteLocationID *int
+	AreaLocationID  int
+}
+
+// GradeCount is the number of routes with one grade_order. GradeOrder is
+// nil for routes without a recognized grade.
+type GradeCount struct {
+	GradeOrder *int
+	Count      int
+}
+
 // StateConfig represents a state configuration for Mountain Project syncing.
 type StateConfig struct {
 	StateName string
@@ -3,6 +3,7 @@ package mountainproject_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -252,8 +253,199 @@ func TestPostgresRepository_GetAllStateConfigs(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_GetAreaLinkProblems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mp_area_id", "name", "parent_mp_area_id", "location_id", "parent_location_id", "problem"}).
+		AddRow(int64(200), "Lower Town Wall", int64(100), nil, 2, "location_mismatch").
+		AddRow(int64(300), "Clearing", int64(999), 3, nil, "orphan")
+
+	mock.ExpectQuery(`WITH RECURSIVE parent_walk AS`).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	problems, err := repo.Areas().GetAreaLinkProblems(context.Background())
+
+	if err != nil {
+		t.Fatalf("GetAreaLinkProblems() error = %v", err)
+	}
+
+	if len(problems) != 2 {
+		t.Fatalf("GetAreaLinkProblems() returned %d rows, want 2", len(problems))
+	}
+
+	mismatch := problems[0]
+	if mismatch.Problem != mountainproject.AreaProblemLocationMismatch {
+		t.Errorf("first Problem = %q, want %q", mismatch.Problem, mountainproject.AreaProblemLocationMismatch)
+	}
+	if mismatch.LocationID != nil {
+		t.Errorf("first LocationID = %v, want nil", *mismatch.LocationID)
+	}
+	if mismatch.ParentLocationID == nil || *mismatch.ParentLocationID != 2 {
+		t.Errorf("first ParentLocationID = %v, want 2", mismatch.ParentLocationID)
+	}
+
+	orphan := problems[1]
+	if orphan.ParentMPAreaID == nil || *orphan.ParentMPAreaID != 999 {
+		t.Errorf("second ParentMPAreaID = %v, want 999", orphan.ParentMPAreaID)
+	}
+	if orphan.ParentLocationID != nil {
+		t.Errorf("second ParentLocationID = %v, want nil", *orphan.ParentLocationID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_DetachAreaParents(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE woulder\.mp_areas\s+SET parent_mp_area_id = NULL`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	updated, err := repo.Areas().DetachAreaParents(context.Background(), []int64{300, 400})
+
+	if err != nil {
+		t.Errorf("DetachAreaParents() error = %v", err)
+	}
+
+	if updated != 2 {
+		t.Errorf("DetachAreaParents() = %d, want 2", updated)
+	}
+
+	// No IDs means no query
+	updated, err = repo.Areas().DetachAreaParents(context.Background(), nil)
+	if err != nil || updated != 0 {
+		t.Errorf("DetachAreaParents(nil) = %d, %v, want 0, nil", updated, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetAreaCoordinateProblems(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mp_area_id", "name", "latitude", "longitude", "reference_area_id", "distance_km", "problem"}).
+		AddRow(int64(200), "Lower Town Wall", 33.9, -118.4, int64(100), 1412.6, "far_from_area").
+		AddRow(int64(300), "Clearing", 0.0, 0.0, nil, nil, "null_island")
+
+	mock.ExpectQuery(`WITH checked AS`).
+		WithArgs(100.0).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	problems, err := repo.Areas().GetAreaCoordinateProblems(context.Background(), 100)
+
+	if err != nil {
+		t.Fatalf("GetAreaCoordinateProblems() error = %v", err)
+	}
+
+	if len(problems) != 2 {
+		t.Fatalf("GetAreaCoordinateProblems() returned %d rows, want 2", len(problems))
+	}
+
+	far := problems[0]
+	if far.Problem != mountainproject.CoordProblemFarFromArea {
+		t.Errorf("first Problem = %q, want %q", far.Problem, mountainproject.CoordProblemFarFromArea)
+	}
+	if far.ReferenceAreaID == nil || *far.ReferenceAreaID != 100 {
+		t.Errorf("first ReferenceAreaID = %v, want 100", far.ReferenceAreaID)
+	}
+	if far.DistanceKm == nil || *far.DistanceKm != 1412.6 {
+		t.Errorf("first DistanceKm = %v, want 1412.6", far.DistanceKm)
+	}
+
+	nullIsland := problems[1]
+	if nullIsland.ReferenceAreaID != nil || nullIsland.DistanceKm != nil {
+		t.Errorf("second ReferenceAreaID, DistanceKm = %v, %v, want nil, nil", nullIsland.ReferenceAreaID, nullIsland.DistanceKm)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_ReconcileAreaLocations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE woulder\.mp_areas\s+SET location_id = p\.location_id`).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	updated, err := repo.Areas().ReconcileAreaLocations(context.Background())
+
+	if err != nil {
+		t.Errorf("ReconcileAreaLocations() error = %v", err)
+	}
+
+	if updated != 5 {
+		t.Errorf("ReconcileAreaLocations() = %d, want 5", updated)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 // RoutesRepository Tests
 
+func TestPostgresRepository_GetAreasByLocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "mp_area_id", "name", "parent_mp_area_id", "area_type",
+		"location_id", "latitude", "longitude", "last_synced_at", "created_at", "updated_at",
+	}).
+		AddRow(1, int64(100), "Gold Bar", nil, "area", 3, 47.85, -121.64, now, now, now).
+		AddRow(2, int64(101), "Zeke's Trail", int64(100), "area", 3, nil, nil, nil, now, now)
+
+	mock.ExpectQuery(`SELECT (.+) FROM woulder\.mp_areas\s+WHERE location_id = \$1`).
+		WithArgs(3).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	areas, err := repo.Areas().GetAreasByLocation(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("GetAreasByLocation() error = %v", err)
+	}
+	if len(areas) != 2 {
+		t.Fatalf("GetAreasByLocation() returned %d areas, want 2", len(areas))
+	}
+	if areas[1].ParentMPAreaID == nil || *areas[1].ParentMPAreaID != 100 {
+		t.Errorf("GetAreasByLocation()[1].ParentMPAreaID = %v, want 100", areas[1].ParentMPAreaID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPostgresRepository_SaveRoute(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -352,6 +544,35 @@ func TestPostgresRepository_UpdateGPS(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_CommentAspect(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE woulder\.mp_routes\s+SET aspect = \$2, aspect_source = 'comment'`).
+		WithArgs(int64(456), "S", "{\"faces south\",\"south facing\"}").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE woulder\.mp_routes\s+SET aspect = computed_aspect, aspect_source = 'computed'`).
+		WithArgs(int64(456)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Routes().SetCommentAspect(ctx, 456, "S", []string{"faces south", "south facing"}); err != nil {
+		t.Errorf("SetCommentAspect() error = %v", err)
+	}
+	if err := repo.Routes().ClearCommentAspect(ctx, 456); err != nil {
+		t.Errorf("ClearCommentAspect() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPostgresRepository_GetIDsForArea(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -415,6 +636,202 @@ func TestPostgresRepository_UpsertRoute(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_CountLocationMismatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\)\s+FROM woulder\.mp_routes r\s+JOIN woulder\.mp_areas a`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	count, err := repo.Routes().CountLocationMismatches(context.Background())
+
+	if err != nil {
+		t.Errorf("CountLocationMismatches() error = %v", err)
+	}
+
+	if count != 7 {
+		t.Errorf("CountLocationMismatches() = %d, want 7", count)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetLocationMismatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mp_route_id", "name", "mp_area_id", "location_id", "location_id"}).
+		AddRow(int64(456), "Just Do It", int64(123), 2, 1).
+		AddRow(int64(789), "Chain Reaction", int64(123), nil, 1)
+
+	mock.ExpectQuery(`SELECT r\.mp_route_id, r\.name, r\.mp_area_id, r\.location_id, a\.location_id`).
+		WithArgs(10).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	mismatches, err := repo.Routes().GetLocationMismatches(context.Background(), 10)
+
+	if err != nil {
+		t.Fatalf("GetLocationMismatches() error = %v", err)
+	}
+
+	if len(mismatches) != 2 {
+		t.Fatalf("GetLocationMismatches() returned %d rows, want 2", len(mismatches))
+	}
+
+	if mismatches[0].RouteLocationID == nil || *mismatches[0].RouteLocationID != 2 {
+		t.Errorf("first RouteLocationID = %v, want 2", mismatches[0].RouteLocationID)
+	}
+
+	if mismatches[1].RouteLocationID != nil {
+		t.Errorf("second RouteLocationID = %v, want nil", *mismatches[1].RouteLocationID)
+	}
+
+	if mismatches[1].AreaLocationID != 1 {
+		t.Errorf("second AreaLocationID = %d, want 1", mismatches[1].AreaLocationID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_ReconcileLocationMismatches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE woulder\.mp_routes\s+SET location_id = a\.location_id`).
+		WithArgs(500).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	updated, err := repo.Routes().ReconcileLocationMismatches(context.Background(), 500)
+
+	if err != nil {
+		t.Errorf("ReconcileLocationMismatches() error = %v", err)
+	}
+
+	if updated != 42 {
+		t.Errorf("ReconcileLocationMismatches() = %d, want 42", updated)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetGradeCountsByLocation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"grade_order", "count"}).
+		AddRow(2, 14).
+		AddRow(5, 3).
+		AddRow(nil, 4)
+
+	mock.ExpectQuery(`SELECT r\.grade_order, COUNT\(\*\)\s+FROM woulder\.mp_routes r\s+WHERE r\.location_id = \$1`).
+		WithArgs(5, "Boulder").
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	counts, err := repo.Routes().GetGradeCountsByLocation(context.Background(), 5, "Boulder")
+
+	if err != nil {
+		t.Fatalf("GetGradeCountsByLocation() error = %v", err)
+	}
+
+	if len(counts) != 3 {
+		t.Fatalf("GetGradeCountsByLocation() returned %d counts, want 3", len(counts))
+	}
+
+	if counts[0].GradeOrder == nil || *counts[0].GradeOrder != 2 || counts[0].Count != 14 {
+		t.Errorf("counts[0] = %+v, want grade_order 2 with 14 routes", counts[0])
+	}
+
+	if counts[2].GradeOrder != nil || counts[2].Count != 4 {
+		t.Errorf("counts[2] = %+v, want 4 ungraded routes", counts[2])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetGradeCountsByArea(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`WITH RECURSIVE area_tree AS .+ SELECT r\.grade_order, COUNT\(\*\)`).
+		WithArgs(int64(105790237), "").
+		WillReturnRows(sqlmock.NewRows([]string{"grade_order", "count"}).AddRow(110, 7))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	counts, err := repo.Routes().GetGradeCountsByArea(context.Background(), 105790237, "")
+
+	if err != nil {
+		t.Fatalf("GetGradeCountsByArea() error = %v", err)
+	}
+
+	if len(counts) != 1 || counts[0].GradeOrder == nil || *counts[0].GradeOrder != 110 || counts[0].Count != 7 {
+		t.Errorf("GetGradeCountsByArea() = %+v, want grade_order 110 with 7 routes", counts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_ClearRouteCoordinates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`UPDATE woulder\.mp_routes\s+SET latitude = NULL, longitude = NULL`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	updated, err := repo.Routes().ClearRouteCoordinates(context.Background(), []int64{1, 2, 3})
+
+	if err != nil {
+		t.Errorf("ClearRouteCoordinates() error = %v", err)
+	}
+
+	if updated != 3 {
+		t.Errorf("ClearRouteCoordinates() = %d, want 3", updated)
+	}
+
+	// No IDs means no query
+	updated, err = repo.Routes().ClearRouteCoordinates(context.Background(), nil)
+	if err != nil || updated != 0 {
+		t.Errorf("ClearRouteCoordinates(nil) = %d, %v, want 0, nil", updated, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 // TicksRepository Tests
 
 func TestPostgresRepository_SaveTick(t *testing.T) {
@@ -488,6 +905,76 @@ func TestPostgresRepository_GetLastTimestampForRoute(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_GetTickedRouteIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"mp_route_id"}).AddRow(int64(1)).AddRow(int64(3))
+	mock.ExpectQuery(`SELECT DISTINCT mp_route_id\s+FROM woulder\.mp_ticks`).
+		WithArgs(sqlmock.AnyArg(), "Alex").
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	ticked, err := repo.Ticks().GetTickedRouteIDs(context.Background(), "Alex", []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetTickedRouteIDs() error = %v", err)
+	}
+	if len(ticked) != 2 || ticked[0] != 1 || ticked[1] != 3 {
+		t.Errorf("GetTickedRouteIDs() = %v, want [1 3]", ticked)
+	}
+
+	// No route IDs means no query.
+	ticked, err = repo.Ticks().GetTickedRouteIDs(context.Background(), "Alex", nil)
+	if err != nil || len(ticked) != 0 {
+		t.Errorf("GetTickedRouteIDs(nil) = %v, %v, want empty, nil", ticked, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetTicksForRoutes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	climbed := since.Add(48 * time.Hour)
+	comment := "Sent!"
+	rows := sqlmock.NewRows([]string{
+		"id", "mp_route_id", "user_name", "climbed_at", "style", "comment", "created_at", "updated_at",
+	}).AddRow(9, int64(2), "Alex", climbed, "Flash", comment, climbed, climbed)
+
+	mock.ExpectQuery(`SELECT (.+) FROM woulder\.mp_ticks\s+WHERE mp_route_id = ANY\(\$1\)\s+AND climbed_at >= \$2`).
+		WithArgs(sqlmock.AnyArg(), since).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	ticks, err := repo.Ticks().GetTicksForRoutes(context.Background(), []int64{1, 2}, since)
+	if err != nil {
+		t.Fatalf("GetTicksForRoutes() error = %v", err)
+	}
+	if len(ticks) != 1 || ticks[0].UserName != "Alex" || ticks[0].Comment == nil || *ticks[0].Comment != comment {
+		t.Errorf("GetTicksForRoutes() = %+v, want Alex's flash", ticks)
+	}
+
+	// No route IDs means no query.
+	ticks, err = repo.Ticks().GetTicksForRoutes(context.Background(), nil, since)
+	if err != nil || len(ticks) != 0 {
+		t.Errorf("GetTicksForRoutes(nil) = %v, %v, want empty, nil", ticks, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestPostgresRepository_GetLastTimestampForRoute_NoTicks(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -623,6 +1110,136 @@ func TestPostgresRepository_UpsertRouteComment(t *testing.T) {
 	}
 }
 
+func TestPostgresRepository_GetRouteCommentTexts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT comment_text\s+FROM woulder\.mp_comments\s+WHERE comment_type = 'route' AND mp_route_id = \$1`).
+		WithArgs(int64(456)).
+		WillReturnRows(sqlmock.NewRows([]string{"comment_text"}).
+			AddRow("Seeps for days after rain").
+			AddRow("Gets afternoon sun"))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	texts, err := repo.Comments().GetRouteCommentTexts(context.Background(), 456)
+
+	if err != nil {
+		t.Errorf("GetRouteCommentTexts() error = %v", err)
+	}
+	if len(texts) != 2 || texts[0] != "Seeps for days after rain" {
+		t.Errorf("GetRouteCommentTexts() = %v, want 2 texts newest first", texts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetTargetsMissingConditionsBeta(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT DISTINCT (.+) FROM woulder\.mp_comments c\s+LEFT JOIN woulder\.mp_conditions_beta b`).
+		WithArgs(mountainproject.ConditionsBetaTargetRoute).
+		WillReturnRows(sqlmock.NewRows([]string{"mp_id"}).AddRow(int64(101)).AddRow(int64(202)))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	ids, err := repo.Comments().GetTargetsMissingConditionsBeta(context.Background(), mountainproject.ConditionsBetaTargetRoute)
+
+	if err != nil {
+		t.Errorf("GetTargetsMissingConditionsBeta() error = %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 101 || ids[1] != 202 {
+		t.Errorf("GetTargetsMissingConditionsBeta() = %v, want [101 202]", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_GetComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	userID := "200123"
+	rows := sqlmock.NewRows([]string{
+		"id", "mp_comment_id", "comment_type", "mp_area_id", "mp_route_id",
+		"user_name", "user_id", "comment_text", "commented_at", "created_at", "updated_at",
+	}).
+		AddRow(1, int64(500), "area", int64(100), nil, "Alex", userID, "Seeps after rain", now, now, now).
+		AddRow(2, int64(501), "route", nil, int64(2), "Sam", nil, "Great problem", now, now, now)
+
+	mock.ExpectQuery(`SELECT (.+) FROM woulder\.mp_comments`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	repo := mountainproject.NewPostgresRepository(db)
+	comments, err := repo.Comments().GetComments(context.Background(), []int64{100}, []int64{2})
+	if err != nil {
+		t.Fatalf("GetComments() error = %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("GetComments() returned %d comments, want 2", len(comments))
+	}
+	if comments[0].MPAreaID == nil || *comments[0].MPAreaID != 100 || comments[0].MPRouteID != nil {
+		t.Errorf("GetComments()[0] = %+v, want an area comment on 100", comments[0])
+	}
+	if comments[1].MPRouteID == nil || *comments[1].MPRouteID != 2 || comments[1].UserID != nil {
+		t.Errorf("GetComments()[1] = %+v, want a route comment on 2", comments[1])
+	}
+
+	// No targets means no query.
+	comments, err = repo.Comments().GetComments(context.Background(), nil, nil)
+	if err != nil || len(comments) != 0 {
+		t.Errorf("GetComments(nil, nil) = %v, %v, want empty, nil", comments, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_SaveConditionsBeta(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO woulder\.mp_conditions_beta`).
+		WithArgs("route", int64(456), "{\"seeps\",\"afternoon_sun\"}", 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// No tags are stored as an empty array, not NULL.
+	mock.ExpectExec(`INSERT INTO woulder\.mp_conditions_beta`).
+		WithArgs("area", int64(123), "{}", 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := mountainproject.NewPostgresRepository(db)
+	ctx := context.Background()
+
+	if err := repo.Comments().SaveConditionsBeta(ctx, mountainproject.ConditionsBetaTargetRoute, 456, []string{"seeps", "afternoon_sun"}, 2); err != nil {
+		t.Errorf("SaveConditionsBeta() error = %v", err)
+	}
+	if err := repo.Comments().SaveConditionsBeta(ctx, mountainproject.ConditionsBetaTargetArea, 123, nil, 0); err != nil {
+		t.Errorf("SaveConditionsBeta() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 // SyncRepository Tests
 
 func TestPostgresRepository_UpdateRoutePriorities(t *testing.T) {
@@ -737,7 +1354,7 @@ func TestPostgresRepository_GetRoutesDueForTickSync_HighPriority(t *testing.T) {
 		WillReturnRows(rows)
 
 	repo := mountainproject.NewPostgresRepository(db)
-	routeIDs, err := repo.Sync().GetRoutesDueForTickSync(context.Background(), "high")
+	routeIDs, err := repo.Sync().GetRoutesDueForTickSync(context.Background(), mountainproject.PriorityHigh)
 
 	if err != nil {
 		t.Errorf("GetRoutesDueForTickSync() error = %v", err)
@@ -762,8 +1379,8 @@ func TestPostgresRepository_GetRoutesDueForTickSync_InvalidPriority(t *testing.T
 	repo := mountainproject.NewPostgresRepository(db)
 	_, err = repo.Sync().GetRoutesDueForTickSync(context.Background(), "invalid")
 
-	if err == nil {
-		t.Errorf("GetRoutesDueForTickSync() expected error for invalid priority, got nil")
+	if !errors.Is(err, mountainproject.ErrInvalidPriority) {
+		t.Errorf("GetRoutesDueForTickSync() error = %v, want ErrInvalidPriority", err)
 	}
 }
 
@@ -782,7 +1399,7 @@ func TestPostgresRepository_GetRoutesDueForCommentSync_MediumPriority(t *testing
 		WillReturnRows(rows)
 
 	repo := mountainproject.NewPostgresRepository(db)
-	routeIDs, err := repo.Sync().GetRoutesDueForCommentSync(context.Background(), "medium")
+	routeIDs, err := repo.Sync().GetRoutesDueForCommentSync(context.Background(), mountainproject.PriorityMedium)
 
 	if err != nil {
 		t.Errorf("GetRoutesDueForCommentSync() error = %v", err)
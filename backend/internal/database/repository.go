@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
@@ -81,8 +82,8 @@ type Repository interface {
 	// DEPRECATED: Use MountainProject().Sync() methods instead
 	UpdateRouteSyncPriorities(ctx context.Context) error
 	GetLocationRoutesDueForSync(ctx context.Context, syncType string) ([]int64, error)
-	GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error)
-	GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error)
+	GetRoutesDueForTickSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
+	GetRoutesDueForCommentSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
 	GetPriorityDistribution(ctx context.Context) (map[string]int, error)
 
 	// Boulder drying operations
@@ -103,9 +104,9 @@ type Repository interface {
 	GetRoutesWithGPSByArea(ctx context.Context, mpAreaID int64) ([]*models.MPRoute, error)
 
 	// Heat map operations
-	GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error)
+	GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error)
 	GetAreaActivityDetail(ctx context.Context, areaID int64, startDate, endDate time.Time, routeTypes []string) (*models.AreaActivityDetail, error)
-	GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
+	GetRoutesByBounds(ctx context.Context, bounds GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error)
 	GetRouteTicksInDateRange(ctx context.Context, routeID int64, startDate, endDate time.Time, limit int, routeTypes []string) ([]models.TickDetail, error)
 	SearchRoutesInAreas(ctx context.Context, areaIDs []int64, searchQuery string, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
 
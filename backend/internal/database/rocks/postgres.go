@@ -95,3 +95,25 @@ func (r *PostgresRepository) GetSunExposureByLocation(ctx context.Context, locat
 
 	return &se, nil
 }
+
+// SetLocationTreeCoverage upserts a location's tree coverage percentage.
+func (r *PostgresRepository) SetLocationTreeCoverage(ctx context.Context, locationID int, percent float64) error {
+	_, err := r.db.ExecContext(ctx, querySetLocationTreeCoverage, locationID, percent)
+	return err
+}
+
+// SaveSunExposure upserts a location's full sun exposure profile.
+func (r *PostgresRepository) SaveSunExposure(ctx context.Context, se *models.LocationSunExposure) error {
+	_, err := r.db.ExecContext(ctx, querySaveSunExposure,
+		se.LocationID,
+		se.SouthFacingPercent,
+		se.WestFacingPercent,
+		se.EastFacingPercent,
+		se.NorthFacingPercent,
+		se.SlabPercent,
+		se.OverhangPercent,
+		se.TreeCoveragePercent,
+		se.Description,
+	)
+	return err
+}
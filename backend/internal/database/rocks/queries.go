@@ -35,13 +35,48 @@ const (
 	// queryGetSunExposureByLocation retrieves sun exposure data for a location.
 	// Contains directional exposure percentages and features like tree coverage.
 	// Primary key lookup via location_id - very fast.
+	// description is NULL on profiles created by querySetLocationTreeCoverage.
 	queryGetSunExposureByLocation = `
 		SELECT id, location_id,
 		       south_facing_percent, west_facing_percent,
 		       east_facing_percent, north_facing_percent,
 		       slab_percent, overhang_percent,
-		       tree_coverage_percent, description
+		       tree_coverage_percent, COALESCE(description, '')
 		FROM woulder.location_sun_exposure
 		WHERE location_id = $1
 	`
+
+	// querySetLocationTreeCoverage stores a location's tree coverage,
+	// creating its sun exposure profile (other columns at their defaults) if
+	// it has none.
+	// Indexes: location_sun_exposure(location_id) UNIQUE
+	querySetLocationTreeCoverage = `
+		INSERT INTO woulder.location_sun_exposure (location_id, tree_coverage_percent)
+		VALUES ($1, $2)
+		ON CONFLICT (location_id) DO UPDATE SET
+			tree_coverage_percent = EXCLUDED.tree_coverage_percent,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	// querySaveSunExposure creates or replaces a location's sun exposure
+	// profile.
+	// Indexes: location_sun_exposure(location_id) UNIQUE
+	querySaveSunExposure = `
+		INSERT INTO woulder.location_sun_exposure (
+			location_id, south_facing_percent, west_facing_percent,
+			east_facing_percent, north_facing_percent, slab_percent,
+			overhang_percent, tree_coverage_percent, description
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (location_id) DO UPDATE SET
+			south_facing_percent = EXCLUDED.south_facing_percent,
+			west_facing_percent = EXCLUDED.west_facing_percent,
+			east_facing_percent = EXCLUDED.east_facing_percent,
+			north_facing_percent = EXCLUDED.north_facing_percent,
+			slab_percent = EXCLUDED.slab_percent,
+			overhang_percent = EXCLUDED.overhang_percent,
+			tree_coverage_percent = EXCLUDED.tree_coverage_percent,
+			description = EXCLUDED.description,
+			updated_at = CURRENT_TIMESTAMP
+	`
 )
@@ -26,4 +26,12 @@ type Repository interface {
 	// Contains directional exposure percentages and tree coverage data.
 	// Returns nil if no sun exposure data exists for the location.
 	GetSunExposureByLocation(ctx context.Context, locationID int) (*models.LocationSunExposure, error)
+
+	// SetLocationTreeCoverage stores a location's tree coverage percentage
+	// (0-100), creating its sun exposure profile if it has none.
+	SetLocationTreeCoverage(ctx context.Context, locationID int, percent float64) error
+
+	// SaveSunExposure creates or replaces the sun exposure profile for
+	// se.LocationID. se.ID is ignored.
+	SaveSunExposure(ctx context.Context, se *models.LocationSunExposure) error
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/alexscott64/woulder/backend/internal/database/rocks"
+	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
 func TestPostgresRepository_GetRockTypesByLocation(t *testing.T) {
@@ -309,3 +310,57 @@ func TestPostgresRepository_GetSunExposureByLocation_NotFound(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
 }
+
+func TestPostgresRepository_SetLocationTreeCoverage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO woulder.location_sun_exposure .*ON CONFLICT \(location_id\) DO UPDATE`).
+		WithArgs(7, 42.5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := rocks.NewPostgresRepository(db)
+	if err := repo.SetLocationTreeCoverage(context.Background(), 7, 42.5); err != nil {
+		t.Errorf("SetLocationTreeCoverage() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPostgresRepository_SaveSunExposure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectExec(`INSERT INTO woulder.location_sun_exposure .*ON CONFLICT \(location_id\) DO UPDATE`).
+		WithArgs(7, 40.0, 20.0, 20.0, 20.0, 10.0, 5.0, 35.0, "Mostly south-facing").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := rocks.NewPostgresRepository(db)
+	err = repo.SaveSunExposure(context.Background(), &models.LocationSunExposure{
+		ID:                  99, // ignored
+		LocationID:          7,
+		SouthFacingPercent:  40,
+		WestFacingPercent:   20,
+		EastFacingPercent:   20,
+		NorthFacingPercent:  20,
+		SlabPercent:         10,
+		OverhangPercent:     5,
+		TreeCoveragePercent: 35,
+		Description:         "Mostly south-facing",
+	})
+	if err != nil {
+		t.Errorf("SaveSunExposure() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -13,11 +13,11 @@ import (
 // weatherDataColumnCount is the number of columns inserted per row by
 // bulkInsertForecast. Must stay in sync with the column list in
 // buildBulkInsertQuery and with querySave.
-const weatherDataColumnCount = 16
+const weatherDataColumnCount = 17
 
 // maxBulkInsertRows caps the number of rows in a single bulk INSERT.
 // PostgreSQL allows up to 65,535 bind parameters per statement (uint16);
-// at 16 params per row that's 4096 rows. We pick a comfortable margin
+// at 17 params per row that's 3855 rows. We pick a comfortable margin
 // below that to leave room for query-planner overhead and to keep any one
 // transaction's WAL footprint bounded. The expected payload from Open-Meteo
 // is ~396 rows, so this only matters as a safety valve.
@@ -54,6 +54,7 @@ func (r *PostgresRepository) Save(ctx context.Context, data *models.WeatherData)
 		data.DirectRadiation,
 		data.DiffuseRadiation,
 		data.DewpointF,
+		precipTypeOrNone(data.PrecipType),
 	)
 	return err
 }
@@ -76,6 +77,7 @@ func (r *PostgresRepository) GetHistorical(ctx context.Context, locationID int,
 			&d.CloudCover, &d.Pressure, &d.Description,
 			&d.Icon,
 			&d.ShortwaveRadiation, &d.DirectRadiation, &d.DiffuseRadiation, &d.DewpointF,
+			&d.PrecipType,
 			&d.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -108,6 +110,7 @@ func (r *PostgresRepository) GetForecast(ctx context.Context, locationID int, ho
 			&d.CloudCover, &d.Pressure, &d.Description,
 			&d.Icon,
 			&d.ShortwaveRadiation, &d.DirectRadiation, &d.DiffuseRadiation, &d.DewpointF,
+			&d.PrecipType,
 			&d.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -132,6 +135,7 @@ func (r *PostgresRepository) GetCurrent(ctx context.Context, locationID int) (*m
 		&d.CloudCover, &d.Pressure, &d.Description,
 		&d.Icon,
 		&d.ShortwaveRadiation, &d.DirectRadiation, &d.DiffuseRadiation, &d.DewpointF,
+		&d.PrecipType,
 		&d.CreatedAt,
 	)
 
@@ -256,7 +260,8 @@ func buildBulkInsertQuery(chunk []models.WeatherData) (string, []interface{}) {
 		location_id, timestamp, temperature, feels_like, precipitation,
 		humidity, wind_speed, wind_direction, cloud_cover, pressure,
 		description, icon,
-		shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f
+		shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f,
+		precip_type
 	) VALUES `)
 
 	args := make([]interface{}, 0, len(chunk)*weatherDataColumnCount)
@@ -265,17 +270,19 @@ func buildBulkInsertQuery(chunk []models.WeatherData) (string, []interface{}) {
 			b.WriteString(",")
 		}
 		base := i * weatherDataColumnCount
-		// $1..$16 for the first row, $17..$32 for the second, etc.
+		// $1..$17 for the first row, $18..$34 for the second, etc.
 		fmt.Fprintf(&b,
-			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
+			"($%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d,$%d)",
 			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8,
 			base+9, base+10, base+11, base+12, base+13, base+14, base+15, base+16,
+			base+17,
 		)
 		args = append(args,
 			d.LocationID, d.Timestamp, d.Temperature, d.FeelsLike,
 			d.Precipitation, d.Humidity, d.WindSpeed, d.WindDirection,
 			d.CloudCover, d.Pressure, d.Description, d.Icon,
 			d.ShortwaveRadiation, d.DirectRadiation, d.DiffuseRadiation, d.DewpointF,
+			precipTypeOrNone(d.PrecipType),
 		)
 	}
 
@@ -294,6 +301,7 @@ func buildBulkInsertQuery(chunk []models.WeatherData) (string, []interface{}) {
 		direct_radiation = EXCLUDED.direct_radiation,
 		diffuse_radiation = EXCLUDED.diffuse_radiation,
 		dewpoint_f = EXCLUDED.dewpoint_f,
+		precip_type = EXCLUDED.precip_type,
 		created_at = CURRENT_TIMESTAMP
 	WHERE weather_data.temperature         IS DISTINCT FROM EXCLUDED.temperature
 	   OR weather_data.feels_like          IS DISTINCT FROM EXCLUDED.feels_like
@@ -308,11 +316,22 @@ func buildBulkInsertQuery(chunk []models.WeatherData) (string, []interface{}) {
 	   OR weather_data.shortwave_radiation IS DISTINCT FROM EXCLUDED.shortwave_radiation
 	   OR weather_data.direct_radiation    IS DISTINCT FROM EXCLUDED.direct_radiation
 	   OR weather_data.diffuse_radiation   IS DISTINCT FROM EXCLUDED.diffuse_radiation
-	   OR weather_data.dewpoint_f          IS DISTINCT FROM EXCLUDED.dewpoint_f`)
+	   OR weather_data.dewpoint_f          IS DISTINCT FROM EXCLUDED.dewpoint_f
+	   OR weather_data.precip_type         IS DISTINCT FROM EXCLUDED.precip_type`)
 
 	return b.String(), args
 }
 
+// precipTypeOrNone stores rows without a classification as none, the
+// column default, so upserts from providers that don't classify
+// precipitation don't violate the column's NOT NULL constraint.
+func precipTypeOrNone(precipType string) string {
+	if precipType == "" {
+		return models.PrecipTypeNone
+	}
+	return precipType
+}
+
 // UpsertDailyAggregates upserts daily weather rollups for a location/date range.
 func (r *PostgresRepository) UpsertDailyAggregates(ctx context.Context, locationID int, startDate, endDate string) error {
 	_, err := r.db.ExecContext(ctx, queryUpsertDailyAggregates, locationID, startDate, endDate)
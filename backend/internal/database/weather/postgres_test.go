@@ -39,6 +39,7 @@ func TestBuildBulkInsertQueryUsesWeatherNoOpGuard(t *testing.T) {
 		"ON CONFLICT(location_id, timestamp) DO UPDATE SET",
 		"WHERE weather_data.temperature",
 		"OR weather_data.dewpoint_f",
+		"OR weather_data.precip_type",
 	} {
 		if !strings.Contains(query, want) {
 			t.Fatalf("bulk insert query missing %q", want)
@@ -14,9 +14,10 @@ const (
 			location_id, timestamp, temperature, feels_like, precipitation,
 			humidity, wind_speed, wind_direction, cloud_cover, pressure,
 			description, icon,
-			shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f
+			shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f,
+			precip_type
 		)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17)
 		ON CONFLICT(location_id, timestamp) DO UPDATE SET
 			temperature = EXCLUDED.temperature,
 			feels_like = EXCLUDED.feels_like,
@@ -32,6 +33,7 @@ const (
 			direct_radiation = EXCLUDED.direct_radiation,
 			diffuse_radiation = EXCLUDED.diffuse_radiation,
 			dewpoint_f = EXCLUDED.dewpoint_f,
+			precip_type = EXCLUDED.precip_type,
 			created_at = CURRENT_TIMESTAMP
 		WHERE weather_data.temperature         IS DISTINCT FROM EXCLUDED.temperature
 		   OR weather_data.feels_like          IS DISTINCT FROM EXCLUDED.feels_like
@@ -47,6 +49,7 @@ const (
 		   OR weather_data.direct_radiation    IS DISTINCT FROM EXCLUDED.direct_radiation
 		   OR weather_data.diffuse_radiation   IS DISTINCT FROM EXCLUDED.diffuse_radiation
 		   OR weather_data.dewpoint_f          IS DISTINCT FROM EXCLUDED.dewpoint_f
+		   OR weather_data.precip_type         IS DISTINCT FROM EXCLUDED.precip_type
 	`
 
 	// queryGetHistorical retrieves past weather data for a location.
@@ -57,7 +60,7 @@ const (
 		       precipitation, humidity, wind_speed, wind_direction,
 		       cloud_cover, pressure, description, icon,
 		       shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f,
-		       created_at
+		       precip_type, created_at
 		FROM woulder.weather_data
 		WHERE location_id = $1
 		  AND timestamp >= NOW() - INTERVAL '1 day' * $2
@@ -73,7 +76,7 @@ const (
 		       precipitation, humidity, wind_speed, wind_direction,
 		       cloud_cover, pressure, description, icon,
 		       shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f,
-		       created_at
+		       precip_type, created_at
 		FROM woulder.weather_data
 		WHERE location_id = $1
 		  AND timestamp > NOW()
@@ -90,7 +93,7 @@ const (
 		       precipitation, humidity, wind_speed, wind_direction,
 		       cloud_cover, pressure, description, icon,
 		       shortwave_radiation, direct_radiation, diffuse_radiation, dewpoint_f,
-		       created_at
+		       precip_type, created_at
 		FROM woulder.weather_data
 		WHERE location_id = $1
 		ORDER BY ABS(EXTRACT(EPOCH FROM (timestamp - NOW())))
@@ -10,6 +10,7 @@ func TestWeatherUpsertNoOpGuards(t *testing.T) {
 		"WHERE weather_data.temperature",
 		"IS DISTINCT FROM EXCLUDED.precipitation",
 		"IS DISTINCT FROM EXCLUDED.dewpoint_f",
+		"IS DISTINCT FROM EXCLUDED.precip_type",
 	} {
 		if !strings.Contains(querySave, want) {
 			t.Fatalf("querySave missing %q", want)
@@ -42,6 +42,7 @@ func TestPostgresRepository_Save(t *testing.T) {
 			data.Precipitation, data.Humidity, data.WindSpeed, data.WindDirection,
 			data.CloudCover, data.Pressure, data.Description, data.Icon,
 			data.ShortwaveRadiation, data.DirectRadiation, data.DiffuseRadiation, data.DewpointF,
+			models.PrecipTypeNone,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -70,19 +71,19 @@ func TestPostgresRepository_GetHistorical(t *testing.T) {
 		"precipitation", "humidity", "wind_speed", "wind_direction",
 		"cloud_cover", "pressure", "description", "icon",
 		"shortwave_radiation", "direct_radiation", "diffuse_radiation", "dewpoint_f",
-		"created_at",
+		"precip_type", "created_at",
 	}).AddRow(
 		1, 10, now.Add(-24*time.Hour), 65.0, 63.0,
 		0.0, 50, 5.0, 90,
 		25, 1015, "Clear", "01d",
 		0.0, 0.0, 0.0, 0.0,
-		now.Add(-25*time.Hour),
+		"none", now.Add(-25*time.Hour),
 	).AddRow(
 		2, 10, now.Add(-12*time.Hour), 70.0, 68.0,
 		0.0, 55, 7.0, 120,
 		30, 1014, "Few clouds", "02d",
 		0.0, 0.0, 0.0, 0.0,
-		now.Add(-13*time.Hour),
+		"none", now.Add(-13*time.Hour),
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM woulder.weather_data").
@@ -121,7 +122,7 @@ func TestPostgresRepository_GetHistorical_Empty(t *testing.T) {
 		"precipitation", "humidity", "wind_speed", "wind_direction",
 		"cloud_cover", "pressure", "description", "icon",
 		"shortwave_radiation", "direct_radiation", "diffuse_radiation", "dewpoint_f",
-		"created_at",
+		"precip_type", "created_at",
 	})
 
 	mock.ExpectQuery("SELECT (.+) FROM woulder.weather_data").
@@ -157,19 +158,19 @@ func TestPostgresRepository_GetForecast(t *testing.T) {
 		"precipitation", "humidity", "wind_speed", "wind_direction",
 		"cloud_cover", "pressure", "description", "icon",
 		"shortwave_radiation", "direct_radiation", "diffuse_radiation", "dewpoint_f",
-		"created_at",
+		"precip_type", "created_at",
 	}).AddRow(
 		3, 10, now.Add(6*time.Hour), 75.0, 73.0,
 		0.1, 60, 12.0, 180,
 		70, 1012, "Partly cloudy", "03d",
 		0.0, 0.0, 0.0, 0.0,
-		now,
+		"none", now,
 	).AddRow(
 		4, 10, now.Add(12*time.Hour), 80.0, 78.0,
 		0.2, 65, 15.0, 200,
 		80, 1011, "Cloudy", "04d",
 		0.0, 0.0, 0.0, 0.0,
-		now,
+		"none", now,
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM woulder.weather_data").
@@ -209,13 +210,13 @@ func TestPostgresRepository_GetCurrent(t *testing.T) {
 		"precipitation", "humidity", "wind_speed", "wind_direction",
 		"cloud_cover", "pressure", "description", "icon",
 		"shortwave_radiation", "direct_radiation", "diffuse_radiation", "dewpoint_f",
-		"created_at",
+		"precip_type", "created_at",
 	}).AddRow(
 		5, 10, now, 72.0, 70.0,
-		0.0, 55, 8.0, 150,
-		40, 1013, "Clear", "01d",
+		0.05, 55, 8.0, 150,
+		40, 1013, "Light rain", "10d",
 		0.0, 0.0, 0.0, 0.0,
-		now.Add(-1*time.Hour),
+		"rain", now.Add(-1*time.Hour),
 	)
 
 	mock.ExpectQuery("SELECT (.+) FROM woulder.weather_data").
@@ -237,6 +238,10 @@ func TestPostgresRepository_GetCurrent(t *testing.T) {
 		t.Errorf("GetCurrent() temperature = %v, want 72.0", result.Temperature)
 	}
 
+	if result.PrecipType != models.PrecipTypeRain {
+		t.Errorf("GetCurrent() precip type = %q, want %q", result.PrecipType, models.PrecipTypeRain)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
@@ -397,7 +402,7 @@ func TestPostgresRepository_GetDailyAggregates(t *testing.T) {
 //     location).
 //   - Exactly one INSERT statement is issued for the supplied 3-row payload
 //     (i.e. it is NOT one INSERT per row).
-//   - All 3 * 16 = 48 bind parameters are passed in row-major order.
+//   - All 3 * 17 = 51 bind parameters are passed in row-major order.
 //   - Everything runs inside a single transaction (BEGIN/COMMIT).
 func TestPostgresRepository_ReplaceFutureForLocation_BulkInsert(t *testing.T) {
 	db, mock, err := sqlmock.New()
@@ -408,17 +413,17 @@ func TestPostgresRepository_ReplaceFutureForLocation_BulkInsert(t *testing.T) {
 
 	now := time.Now().UTC()
 	rows := []models.WeatherData{
-		{Timestamp: now.Add(1 * time.Hour), Temperature: 50.1, FeelsLike: 49.0, Precipitation: 0.0, Humidity: 70, WindSpeed: 5.0, WindDirection: 180, CloudCover: 40, Pressure: 1013, Description: "Clear", Icon: "01d"},
-		{Timestamp: now.Add(2 * time.Hour), Temperature: 51.2, FeelsLike: 50.1, Precipitation: 0.0, Humidity: 68, WindSpeed: 6.0, WindDirection: 190, CloudCover: 45, Pressure: 1012, Description: "Clear", Icon: "01d"},
-		{Timestamp: now.Add(3 * time.Hour), Temperature: 52.3, FeelsLike: 51.2, Precipitation: 0.0, Humidity: 65, WindSpeed: 7.0, WindDirection: 200, CloudCover: 50, Pressure: 1011, Description: "Clear", Icon: "02d"},
+		{Timestamp: now.Add(1 * time.Hour), Temperature: 50.1, FeelsLike: 49.0, Precipitation: 0.0, Humidity: 70, WindSpeed: 5.0, WindDirection: 180, CloudCover: 40, Pressure: 1013, Description: "Clear", Icon: "01d", PrecipType: models.PrecipTypeNone},
+		{Timestamp: now.Add(2 * time.Hour), Temperature: 51.2, FeelsLike: 50.1, Precipitation: 0.0, Humidity: 68, WindSpeed: 6.0, WindDirection: 190, CloudCover: 45, Pressure: 1012, Description: "Clear", Icon: "01d", PrecipType: models.PrecipTypeNone},
+		{Timestamp: now.Add(3 * time.Hour), Temperature: 52.3, FeelsLike: 51.2, Precipitation: 0.05, Humidity: 65, WindSpeed: 7.0, WindDirection: 200, CloudCover: 50, Pressure: 1011, Description: "Clear", Icon: "02d", PrecipType: models.PrecipTypeRain},
 	}
 
 	const locationID = 42
 
-	// Build the 48 expected args in row-major order. locationID must be
+	// Build the 51 expected args in row-major order. locationID must be
 	// stamped on every row by ReplaceFutureForLocation regardless of what
 	// the caller set on the input rows.
-	expectedArgs := make([]driver.Value, 0, len(rows)*16)
+	expectedArgs := make([]driver.Value, 0, len(rows)*17)
 	for _, d := range rows {
 		expectedArgs = append(expectedArgs,
 			locationID,
@@ -437,6 +442,7 @@ func TestPostgresRepository_ReplaceFutureForLocation_BulkInsert(t *testing.T) {
 			d.DirectRadiation,
 			d.DiffuseRadiation,
 			d.DewpointF,
+			d.PrecipType,
 		)
 	}
 
@@ -448,7 +454,7 @@ func TestPostgresRepository_ReplaceFutureForLocation_BulkInsert(t *testing.T) {
 	// VALUES groups. If the implementation regresses to N single-row
 	// INSERTs, this expectation will fail because only the first INSERT
 	// will be matched and the next two will be unexpected.
-	mock.ExpectExec(`INSERT INTO woulder\.weather_data .*VALUES\s*\(\$1,.*\$16\),\s*\(\$17,.*\$32\),\s*\(\$33,.*\$48\)`).
+	mock.ExpectExec(`INSERT INTO woulder\.weather_data .*VALUES\s*\(\$1,.*\$17\),\s*\(\$18,.*\$34\),\s*\(\$35,.*\$51\)`).
 		WithArgs(expectedArgs...).
 		WillReturnResult(sqlmock.NewResult(0, int64(len(rows))))
 	mock.ExpectCommit()
@@ -0,0 +1,26 @@
+package geo
+
+import "math"
+
+// nullIslandDegrees is how close to (0, 0) a point must be in both latitude
+// and longitude to count as null island (about 1 km at the equator).
+const nullIslandDegrees = 0.01
+
+// IsPlausibleCoord reports whether (lat, lon) could be a real position: both
+// finite and in range, and not at null island (0, 0), which upstream sources
+// send in place of a missing position. The same rules are applied in SQL by
+// the coordinate problem queries that validate_coordinates runs.
+func IsPlausibleCoord(lat, lon float64) bool {
+	if math.IsNaN(lat) || math.IsNaN(lon) || math.IsInf(lat, 0) || math.IsInf(lon, 0) {
+		return false
+	}
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return false
+	}
+	return !IsNullIsland(lat, lon)
+}
+
+// IsNullIsland reports whether (lat, lon) is at or next to (0, 0).
+func IsNullIsland(lat, lon float64) bool {
+	return math.Abs(lat) < nullIslandDegrees && math.Abs(lon) < nullIslandDegrees
+}
@@ -0,0 +1,33 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsPlausibleCoord(t *testing.T) {
+	cases := []struct {
+		name     string
+		lat, lon float64
+		want     bool
+	}{
+		{"Index, WA", 47.82, -121.55, true},
+		{"near the equator but not null island", 0.5, 32.6, true},
+		{"prime meridian", 51.48, 0, true},
+		{"null island", 0, 0, false},
+		{"next to null island", 0.001, -0.004, false},
+		{"swapped lat/lon", -121.55, 47.82, false},
+		{"latitude out of range", 91, -121.55, false},
+		{"longitude out of range", 47.82, 181, false},
+		{"NaN", math.NaN(), -121.55, false},
+		{"infinite", 47.82, math.Inf(-1), false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsPlausibleCoord(tc.lat, tc.lon); got != tc.want {
+				t.Errorf("IsPlausibleCoord(%v, %v) = %v, want %v", tc.lat, tc.lon, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package grades
+
+// Band is a contiguous run of grades within one family, used to summarize
+// grade distributions ("V0-V2", "5.11").
+type Band struct {
+	Name     string
+	Family   string
+	MinOrder int
+	MaxOrder int
+}
+
+// bands covers every grade order, grouped the way climbers describe an
+// area's level: V-scale in threes, YDS by number grade, and the ice and
+// mixed scales into easy, moderate, and hard.
+var bands = []Band{
+	{"V0-V2", FamilyV, offsetV + 0, offsetV + 2},
+	{"V3-V5", FamilyV, offsetV + 3, offsetV + 5},
+	{"V6-V8", FamilyV, offsetV + 6, offsetV + 8},
+	{"V9-V11", FamilyV, offsetV + 9, offsetV + 11},
+	{"V12+", FamilyV, offsetV + 12, offsetV + len(vScaleGrades) - 1},
+
+	{"5.4-5.9", FamilyYDS, offsetYDS + 0, offsetYDS + 5},
+	{"5.10", FamilyYDS, offsetYDS + 6, offsetYDS + 9},
+	{"5.11", FamilyYDS, offsetYDS + 10, offsetYDS + 13},
+	{"5.12", FamilyYDS, offsetYDS + 14, offsetYDS + 17},
+	{"5.13", FamilyYDS, offsetYDS + 18, offsetYDS + 21},
+	{"5.14+", FamilyYDS, offsetYDS + 22, offsetYDS + len(ydsGrades) - 1},
+
+	{"WI1-WI3", FamilyWI, offsetWI + 0, offsetWI + 2},
+	{"WI4-WI5", FamilyWI, offsetWI + 3, offsetWI + 4},
+	{"WI6+", FamilyWI, offsetWI + 5, offsetWI + len(wiGrades) - 1},
+
+	{"M1-M4", FamilyMixed, offsetMixed + 0, offsetMixed + 3},
+	{"M5-M7", FamilyMixed, offsetMixed + 4, offsetMixed + 6},
+	{"M8+", FamilyMixed, offsetMixed + 7, offsetMixed + len(mixedGrades) - 1},
+
+	{"AI1-AI3", FamilyAI, offsetAI + 0, offsetAI + 2},
+	{"AI4+", FamilyAI, offsetAI + 3, offsetAI + len(aiGrades) - 1},
+}
+
+// Bands returns every grade band, ordered by family (V, YDS, WI, mixed, AI)
+// and then difficulty.
+func Bands() []Band {
+	result := make([]Band, len(bands))
+	copy(result, bands)
+	return result
+}
+
+// BandForOrder returns the band containing a grade order, or false if the
+// order isn't a known grade.
+func BandForOrder(order int) (Band, bool) {
+	for _, b := range bands {
+		if order >= b.MinOrder && order <= b.MaxOrder {
+			return b, true
+		}
+	}
+	return Band{}, false
+}
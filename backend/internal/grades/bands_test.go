@@ -0,0 +1,59 @@
+package grades
+
+import "testing"
+
+func TestBandForOrder(t *testing.T) {
+	tests := []struct {
+		grade string
+		want  string
+	}{
+		{"V0", "V0-V2"},
+		{"V2", "V0-V2"},
+		{"V3", "V3-V5"},
+		{"V8", "V6-V8"},
+		{"V12", "V12+"},
+		{"V17", "V12+"},
+		{"5.9", "5.4-5.9"},
+		{"5.10a", "5.10"},
+		{"5.11d", "5.11"},
+		{"5.15d", "5.14+"},
+		{"WI3", "WI1-WI3"},
+		{"WI7", "WI6+"},
+		{"M13", "M8+"},
+		{"AI6", "AI4+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.grade, func(t *testing.T) {
+			band, ok := BandForOrder(ToOrder(tt.grade))
+			if !ok || band.Name != tt.want {
+				t.Errorf("BandForOrder(%s) = %q, %v, want %q", tt.grade, band.Name, ok, tt.want)
+			}
+			if band.Family != Family(tt.grade) {
+				t.Errorf("BandForOrder(%s) family = %q, want %q", tt.grade, band.Family, Family(tt.grade))
+			}
+		})
+	}
+
+	for _, order := range []int{-1, 18, 130, 999} {
+		if band, ok := BandForOrder(order); ok {
+			t.Errorf("BandForOrder(%d) = %q, want no band", order, band.Name)
+		}
+	}
+}
+
+func TestBandsCoverEveryGradeOnce(t *testing.T) {
+	all := append(append(append(append(VScaleGrades(), YDSGrades()...), WIGrades()...), MixedGrades()...), AIGrades()...)
+	for _, g := range all {
+		order := ToOrder(g)
+		matches := 0
+		for _, b := range Bands() {
+			if order >= b.MinOrder && order <= b.MaxOrder {
+				matches++
+			}
+		}
+		if matches != 1 {
+			t.Errorf("grade %s (order %d) is in %d bands, want 1", g, order, matches)
+		}
+	}
+}
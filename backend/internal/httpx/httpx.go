@@ -0,0 +1,148 @@
+// Package httpx provides small helpers for calling JSON HTTP APIs.
+//
+// Every helper reads and closes the response body, treats any non-2xx status
+// as a *StatusError that carries the response body, and decodes successful
+// responses into a caller-supplied type.
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StatusError is returned when a server responds with a non-2xx status code.
+// Body holds the (whitespace-trimmed) response body so callers and logs can see
+// what the upstream API actually said.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status code %d", e.StatusCode)
+	}
+	return fmt.Sprintf("unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+// HasStatus reports whether err wraps a *StatusError with one of the given
+// status codes.
+func HasStatus(err error, codes ...int) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	for _, code := range codes {
+		if statusErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTransient reports whether err is worth retrying: a rate limit (429) or
+// server error (5xx) status, or a failure to reach the server at all. A
+// cancelled request is not transient.
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// ReadResponse reads and closes resp.Body. Non-2xx responses are returned as a
+// *StatusError including the body.
+func ReadResponse(resp *http.Response) ([]byte, error) {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return body, nil
+}
+
+// Decode reads resp via ReadResponse and unmarshals the body into T.
+func Decode[T any](resp *http.Response) (T, error) {
+	var result T
+	body, err := ReadResponse(resp)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result, nil
+}
+
+// Do sends req and returns the body of a 2xx response.
+func Do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return ReadResponse(resp)
+}
+
+// GetJSON issues a GET request to url with the given headers (which may be nil)
+// and decodes the JSON response into T.
+func GetJSON[T any](ctx context.Context, client *http.Client, url string, header http.Header) (T, error) {
+	var zero T
+	req, err := newRequest(ctx, http.MethodGet, url, nil, header)
+	if err != nil {
+		return zero, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return zero, err
+	}
+	return Decode[T](resp)
+}
+
+// PostJSON encodes body as JSON, POSTs it to url with the given headers (which
+// may be nil) and decodes the JSON response into T.
+func PostJSON[T any](ctx context.Context, client *http.Client, url string, body any, header http.Header) (T, error) {
+	var zero T
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return zero, fmt.Errorf("failed to encode request body: %w", err)
+	}
+	req, err := newRequest(ctx, http.MethodPost, url, bytes.NewReader(payload), header)
+	if err != nil {
+		return zero, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return zero, err
+	}
+	return Decode[T](resp)
+}
+
+func newRequest(ctx context.Context, method, url string, body io.Reader, header http.Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,137 @@
+package httpx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type payload struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestGetJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		if got := r.Header.Get("User-Agent"); got != "woulder-test" {
+			t.Errorf("User-Agent = %q, want woulder-test", got)
+		}
+		w.Write([]byte(`{"name":"crag","count":3}`))
+	}))
+	defer srv.Close()
+
+	got, err := GetJSON[payload](context.Background(), srv.Client(), srv.URL, http.Header{"User-Agent": {"woulder-test"}})
+	if err != nil {
+		t.Fatalf("GetJSON: %v", err)
+	}
+	if got != (payload{Name: "crag", Count: 3}) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		var in payload
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		in.Count++
+		json.NewEncoder(w).Encode(in)
+	}))
+	defer srv.Close()
+
+	got, err := PostJSON[payload](context.Background(), srv.Client(), srv.URL, payload{Name: "a", Count: 1}, nil)
+	if err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if got.Count != 2 || got.Name != "a" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestGetJSON_NonSuccessIncludesBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("  upstream overloaded\n"))
+	}))
+	defer srv.Close()
+
+	_, err := GetJSON[payload](context.Background(), srv.Client(), srv.URL, nil)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected *StatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusServiceUnavailable || statusErr.Body != "upstream overloaded" {
+		t.Errorf("got %+v", statusErr)
+	}
+	if !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "upstream overloaded") {
+		t.Errorf("error %q should include status and body", err)
+	}
+	if !HasStatus(err, http.StatusNotFound, http.StatusServiceUnavailable) {
+		t.Error("HasStatus should match 503")
+	}
+	if HasStatus(err, http.StatusNotFound) {
+		t.Error("HasStatus should not match 404")
+	}
+}
+
+func TestGetJSON_DecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":`))
+	}))
+	defer srv.Close()
+
+	_, err := GetJSON[payload](context.Background(), srv.Client(), srv.URL, nil)
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to decode response") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	if HasStatus(err, http.StatusOK) {
+		t.Error("decode errors should not be status errors")
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+	_, unreachable := GetJSON[payload](context.Background(), http.DefaultClient, srv.URL, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, cancelled := GetJSON[payload](ctx, http.DefaultClient, srv.URL, nil)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", &StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", fmt.Errorf("wrapped: %w", &StatusError{StatusCode: http.StatusBadGateway}), true},
+		{"client error", &StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"unreachable", unreachable, true},
+		{"cancelled", cancelled, false},
+		{"decode error", errors.New("failed to decode response"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,23 +1,76 @@
 package kaya
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"log"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+	"github.com/alexscott64/woulder/backend/internal/retry"
 )
 
+// graphqlURL is a var (not a const) so tests can point the client at an
+// httptest server. It is not modified at runtime in production code paths.
+var graphqlURL = "https://kaya-beta.kayaclimb.com/graphql"
+
 const (
-	graphqlURL     = "https://kaya-beta.kayaclimb.com/graphql"
 	rateLimitDelay = 1000 * time.Millisecond // 1 second between requests to be respectful
+
+	requestMaxAttempts = 3               // attempts per request, including the first
+	requestMaxDelay    = 2 * time.Minute // cap on the wait between attempts
 )
 
-// Client handles communication with the Kaya GraphQL API
+// requestRetryDelay is the default wait before the first retry of a request.
+// Tests shorten it.
+var requestRetryDelay = 2 * time.Second
+
+// newRequestRetryPolicy returns a policy that retries rate limiting (429),
+// server errors and network failures. An auth failure (401) is not
+// transient; executeQuery handles it by refreshing the token.
+func newRequestRetryPolicy(maxAttempts int, initialDelay time.Duration) retry.Policy {
+	return retry.Policy{
+		MaxAttempts:  maxAttempts,
+		InitialDelay: initialDelay,
+		MaxDelay:     requestMaxDelay,
+		Jitter:       0.2,
+		ShouldRetry:  httpx.IsTransient,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			log.Printf("[Kaya] Request failed (attempt %d/%d), retrying after %v: %v", attempt, maxAttempts, delay.Round(time.Millisecond), err)
+		},
+	}
+}
+
+// RequestLimiter paces requests across everything that shares it. Wait blocks
+// until the next request may be sent and must be safe for concurrent use;
+// mountainproject.RequestBudget is one.
+type RequestLimiter interface {
+	Wait()
+}
+
+// Client handles communication with the Kaya GraphQL API. It is safe for
+// concurrent use, so parallel syncs can share one Client and its rate limit.
 type Client struct {
-	httpClient      *http.Client
+	httpClient  *http.Client
+	refresher   TokenRefresher
+	retryPolicy retry.Policy
+
+	// mu guards the fields below.
+	mu sync.Mutex
+
+	// lastRequestTime is when the latest request was scheduled to go out.
+	// It is only used when no limiter is set. See SetRequestLimiter.
 	lastRequestTime time.Time
+	limiter         RequestLimiter
+
+	// authToken is sent as a bearer token when set. tokenExpiry is its exp
+	// claim (zero if unknown). See SetAuthToken and SetTokenRefresher.
+	authToken   string
+	tokenExpiry time.Time
 }
 
 // NewClient creates a new Kaya API client
@@ -26,16 +79,121 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retryPolicy: newRequestRetryPolicy(requestMaxAttempts, requestRetryDelay),
 	}
 }
 
-// rateLimit ensures we don't exceed rate limits by waiting if needed
+// SetRetry sets how often a request that fails transiently is attempted
+// (including the first attempt) and the wait before the first retry; each
+// later wait doubles it. Call it before making requests.
+func (c *Client) SetRetry(maxAttempts int, initialDelay time.Duration) {
+	c.retryPolicy = newRequestRetryPolicy(maxAttempts, initialDelay)
+}
+
+// rateLimit waits for the limiter if one is set. Otherwise it spaces
+// requests rateLimitDelay apart, reserving each caller's slot under the lock
+// so concurrent callers queue up instead of all going out at once.
 func (c *Client) rateLimit() {
-	elapsed := time.Since(c.lastRequestTime)
-	if elapsed < rateLimitDelay {
-		time.Sleep(rateLimitDelay - elapsed)
+	c.mu.Lock()
+	limiter := c.limiter
+	var wait time.Duration
+	if limiter == nil {
+		now := time.Now()
+		next := c.lastRequestTime.Add(rateLimitDelay)
+		if next.Before(now) {
+			next = now
+		}
+		c.lastRequestTime = next
+		wait = next.Sub(now)
+	}
+	c.mu.Unlock()
+
+	if limiter != nil {
+		limiter.Wait()
+		return
+	}
+	time.Sleep(wait)
+}
+
+// SetRequestLimiter paces requests with limiter instead of the fixed
+// rateLimitDelay spacing, so the request rate can be tuned and shared with
+// other clients. A nil limiter restores the fixed spacing.
+func (c *Client) SetRequestLimiter(limiter RequestLimiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.limiter = limiter
+}
+
+// SetAuthToken sets the JWT sent with every request. Its exp claim is parsed
+// so an expired token fails fast with a TokenExpiredError instead of being
+// sent; a token without a readable exp claim is used until the API rejects it.
+func (c *Client) SetAuthToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setAuthToken(token)
+}
+
+// setAuthToken is SetAuthToken with c.mu held.
+func (c *Client) setAuthToken(token string) {
+	c.authToken = token
+	c.tokenExpiry = time.Time{}
+	if token == "" {
+		return
+	}
+	if exp, ok, err := ParseTokenExpiry(token); err != nil {
+		log.Printf("[Kaya] Warning: could not read auth token expiry: %v", err)
+	} else if ok {
+		c.tokenExpiry = exp
+	}
+}
+
+// SetTokenRefresher configures how a new token is obtained when the current
+// one expires or is rejected with a 401. Without a refresher, those cases
+// return a TokenExpiredError.
+func (c *Client) SetTokenRefresher(refresher TokenRefresher) {
+	c.refresher = refresher
+}
+
+// authState returns the current token and its expiry.
+func (c *Client) authState() (token string, expiry time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authToken, c.tokenExpiry
+}
+
+// tokenExpired reports whether the current token's exp claim has passed.
+// c.mu must be held.
+func (c *Client) tokenExpired() bool {
+	return !c.tokenExpiry.IsZero() && time.Now().Add(tokenExpirySkew).After(c.tokenExpiry)
+}
+
+// refreshToken replaces the current token using the configured refresher.
+// statusCode is the HTTP status that triggered the refresh (0 if none).
+// Refreshes hold c.mu, so concurrent requests wait for the new token.
+func (c *Client) refreshToken(statusCode int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expired := &TokenExpiredError{ExpiresAt: c.tokenExpiry, StatusCode: statusCode}
+	if c.refresher == nil {
+		return expired
+	}
+
+	token, err := c.refresher()
+	if err != nil {
+		expired.Err = err
+		return expired
+	}
+
+	c.setAuthToken(token)
+	if c.tokenExpired() {
+		expired.ExpiresAt = c.tokenExpiry
+		expired.Err = errors.New("refreshed token is already expired")
+		return expired
 	}
-	c.lastRequestTime = time.Now()
+
+	log.Printf("[Kaya] Auth token refreshed")
+	return nil
 }
 
 // GraphQLRequest represents a GraphQL request payload
@@ -57,45 +215,60 @@ type GraphQLError struct {
 	Path    []interface{} `json:"path,omitempty"`
 }
 
-// executeQuery performs a GraphQL query and returns the raw response
+// executeQuery performs a GraphQL query and returns the raw response. An
+// expired or rejected auth token is refreshed once if a refresher is
+// configured; otherwise a TokenExpiredError is returned.
 func (c *Client) executeQuery(req GraphQLRequest) (*GraphQLResponse, error) {
-	c.rateLimit()
-
-	payload, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequest("POST", graphqlURL, bytes.NewBuffer(payload))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Fetch a token up front when only a refresher is configured, and
+	// replace one that has already expired rather than sending it.
+	c.mu.Lock()
+	needsToken := (c.authToken == "" && c.refresher != nil) || (c.authToken != "" && c.tokenExpired())
+	c.mu.Unlock()
+	if needsToken {
+		if err := c.refreshToken(0); err != nil {
+			return nil, err
+		}
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "*/*")
-	httpReq.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	httpReq.Header.Set("Origin", "https://kaya-app.kayaclimb.com")
-	httpReq.Header.Set("Referer", "https://kaya-app.kayaclimb.com/")
+	resp, err := c.doQuery(req)
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	var expired *TokenExpiredError
+	if errors.As(err, &expired) && c.refresher != nil {
+		if refreshErr := c.refreshToken(expired.StatusCode); refreshErr != nil {
+			return nil, refreshErr
+		}
+		return c.doQuery(req)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
-	}
+	return resp, err
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// doQuery sends a GraphQL request with the current auth token, retrying
+// transient failures (see SetRetry).
+func (c *Client) doQuery(req GraphQLRequest) (*GraphQLResponse, error) {
+	authToken, tokenExpiry := c.authState()
+
+	header := http.Header{}
+	header.Set("Accept", "*/*")
+	header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	header.Set("Origin", "https://kaya-app.kayaclimb.com")
+	header.Set("Referer", "https://kaya-app.kayaclimb.com/")
+	if authToken != "" {
+		header.Set("Authorization", "Bearer "+authToken)
 	}
 
 	var gqlResp GraphQLResponse
-	if err := json.Unmarshal(body, &gqlResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	err := retry.Do(context.Background(), c.retryPolicy, func() error {
+		c.rateLimit()
+		var err error
+		gqlResp, err = httpx.PostJSON[GraphQLResponse](context.Background(), c.httpClient, graphqlURL, req, header)
+		return err
+	})
+	if err != nil {
+		if authToken != "" && httpx.HasStatus(err, http.StatusUnauthorized) {
+			return nil, &TokenExpiredError{ExpiresAt: tokenExpiry, StatusCode: http.StatusUnauthorized}
+		}
+		return nil, fmt.Errorf("kaya request failed: %w", err)
 	}
 
 	if len(gqlResp.Errors) > 0 {
@@ -0,0 +1,157 @@
+package kaya
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func setRetryDelayForTest(t *testing.T, delay time.Duration) {
+	t.Helper()
+	original := requestRetryDelay
+	requestRetryDelay = delay
+	t.Cleanup(func() { requestRetryDelay = original })
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	setRetryDelayForTest(t, time.Millisecond)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	resp, err := NewClient().executeQuery(GraphQLRequest{OperationName: "test"})
+	if err != nil {
+		t.Fatalf("executeQuery() error = %v, want success after retry", err)
+	}
+	if string(resp.Data) != `{"ok":true}` {
+		t.Errorf("Data = %s, want {\"ok\":true}", resp.Data)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	setRetryDelayForTest(t, time.Millisecond)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	if _, err := NewClient().executeQuery(GraphQLRequest{OperationName: "test"}); err == nil {
+		t.Fatal("executeQuery() expected error on 400")
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 (400 is not transient)", requests)
+	}
+}
+
+func TestClient_SetRetryLimitsAttempts(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	client := NewClient()
+	client.SetRequestLimiter(&countingLimiter{}) // skip the 1s spacing
+	client.SetRetry(5, time.Millisecond)
+	if _, err := client.executeQuery(GraphQLRequest{OperationName: "test"}); err == nil {
+		t.Fatal("executeQuery() expected error on 503")
+	}
+	if requests != 5 {
+		t.Errorf("requests = %d, want 5", requests)
+	}
+}
+
+// countingLimiter records how many requests waited on it.
+type countingLimiter struct {
+	waits atomic.Int32
+}
+
+func (l *countingLimiter) Wait() { l.waits.Add(1) }
+
+func TestClient_RequestLimiterPacesEveryAttempt(t *testing.T) {
+	setRetryDelayForTest(t, time.Millisecond)
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	limiter := &countingLimiter{}
+	client := NewClient()
+	client.SetRequestLimiter(limiter)
+
+	start := time.Now()
+	if _, err := client.executeQuery(GraphQLRequest{OperationName: "test"}); err != nil {
+		t.Fatalf("executeQuery() error = %v", err)
+	}
+	if got := limiter.waits.Load(); got != 2 {
+		t.Errorf("limiter waits = %d, want 2 (one per attempt)", got)
+	}
+	// The limiter replaces the fixed spacing, so the retry isn't held back
+	// by rateLimitDelay.
+	if elapsed := time.Since(start); elapsed >= rateLimitDelay {
+		t.Errorf("executeQuery took %v, want the limiter to replace the %v spacing", elapsed, rateLimitDelay)
+	}
+}
+
+func TestClient_ConcurrentRequestsShareLimiter(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"data":{"ok":true}}`))
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	limiter := &countingLimiter{}
+	client := NewClient()
+	client.SetAuthToken("token")
+	client.SetRequestLimiter(limiter)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.executeQuery(GraphQLRequest{OperationName: "test"}); err != nil {
+				t.Errorf("executeQuery() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != workers {
+		t.Errorf("requests = %d, want %d", got, workers)
+	}
+	if got := limiter.waits.Load(); got != workers {
+		t.Errorf("limiter waits = %d, want %d", got, workers)
+	}
+}
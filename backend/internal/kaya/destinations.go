@@ -0,0 +1,50 @@
+package kaya
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// DestinationsFile is the shared list of Kaya destinations to sync, relative
+// to the backend directory the sync commands are run from.
+const DestinationsFile = "../docs/kaya-destinations.txt"
+
+// LoadDestinationSlugs reads destination slugs from a destinations file.
+func LoadDestinationSlugs(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parseDestinationSlugs(file)
+}
+
+// parseDestinationSlugs reads one "Name-ID" slug per line, skipping blank
+// lines, comments, and the "Total:"/"Format:" header lines.
+func parseDestinationSlugs(r io.Reader) ([]string, error) {
+	var slugs []string
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Skip empty lines, comments, and header lines
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Total:") {
+			continue
+		}
+
+		// Valid slug format: "Name-ID"
+		if strings.Contains(line, "-") && !strings.HasPrefix(line, "Format:") {
+			slugs = append(slugs, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return slugs, nil
+}
@@ -0,0 +1,35 @@
+package kaya
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDestinationSlugs(t *testing.T) {
+	input := `# Kaya official destinations
+Total: 3
+Format: Name-ID
+
+Squamish-295658
+  Red-Rocks-331387
+not a slug
+Leavenworth-344933
+`
+
+	slugs, err := parseDestinationSlugs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseDestinationSlugs() error = %v", err)
+	}
+
+	want := []string{"Squamish-295658", "Red-Rocks-331387", "Leavenworth-344933"}
+	if !reflect.DeepEqual(slugs, want) {
+		t.Errorf("parseDestinationSlugs() = %v, want %v", slugs, want)
+	}
+}
+
+func TestLoadDestinationSlugs_MissingFile(t *testing.T) {
+	if _, err := LoadDestinationSlugs(t.TempDir() + "/missing.txt"); err == nil {
+		t.Error("LoadDestinationSlugs() expected error for a missing file")
+	}
+}
@@ -0,0 +1,103 @@
+package kaya
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// tokenExpirySkew treats a token as expired slightly before its exp claim so
+// a request is not sent with a token that expires in flight.
+const tokenExpirySkew = 30 * time.Second
+
+// ErrTokenExpired is matched (via errors.Is) by every TokenExpiredError.
+// Retry logic should treat it as permanent: retrying with the same token
+// cannot succeed.
+var ErrTokenExpired = errors.New("kaya auth token expired")
+
+// TokenExpiredError is returned when the Kaya auth token has expired, either
+// according to its exp claim or because the API rejected it with a 401, and
+// no refresh produced a usable token.
+type TokenExpiredError struct {
+	ExpiresAt  time.Time // exp claim of the rejected token (zero if unknown)
+	StatusCode int       // HTTP status returned by the API (0 if detected locally)
+	Err        error     // refresh failure, if a refresh was attempted
+}
+
+func (e *TokenExpiredError) Error() string {
+	msg := ErrTokenExpired.Error()
+	if !e.ExpiresAt.IsZero() {
+		msg += fmt.Sprintf(" at %s", e.ExpiresAt.Format(time.RFC3339))
+	}
+	if e.StatusCode != 0 {
+		msg += fmt.Sprintf(" (status %d)", e.StatusCode)
+	}
+	if e.Err != nil {
+		msg += fmt.Sprintf(": refresh failed: %v", e.Err)
+	}
+	return msg
+}
+
+// Is reports whether target is ErrTokenExpired.
+func (e *TokenExpiredError) Is(target error) bool {
+	return target == ErrTokenExpired
+}
+
+func (e *TokenExpiredError) Unwrap() error {
+	return e.Err
+}
+
+// TokenRefresher returns a fresh Kaya auth token. It is called when the
+// current token is expired or rejected.
+type TokenRefresher func() (string, error)
+
+// FileTokenRefresher returns a TokenRefresher that re-reads the token from
+// path, so an external process can rotate the token during a long sync.
+func FileTokenRefresher(path string) TokenRefresher {
+	return func() (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read token file: %w", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	}
+}
+
+// ParseTokenExpiry returns the exp claim of a JWT. The signature is not
+// verified; the claim is only used to anticipate expiry. ok is false when the
+// token has no exp claim.
+func ParseTokenExpiry(token string) (expiresAt time.Time, ok bool, err error) {
+	parts := strings.Split(strings.TrimPrefix(token, "Bearer "), ".")
+	if len(parts) != 3 {
+		return time.Time{}, false, fmt.Errorf("malformed JWT: expected 3 segments, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("malformed JWT payload: %w", err)
+	}
+
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, false, fmt.Errorf("malformed JWT claims: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, false, nil
+	}
+
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), true, nil
+}
@@ -0,0 +1,135 @@
+package kaya
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testJWT builds an unsigned JWT with the given claims JSON.
+func testJWT(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(claims)) + ".signature"
+}
+
+func jwtExpiringAt(t time.Time) string {
+	return testJWT(fmt.Sprintf(`{"sub":"user","exp":%d}`, t.Unix()))
+}
+
+func setGraphQLURLForTest(t *testing.T, url string) {
+	t.Helper()
+	original := graphqlURL
+	graphqlURL = url
+	t.Cleanup(func() { graphqlURL = original })
+}
+
+func TestParseTokenExpiry(t *testing.T) {
+	exp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	got, ok, err := ParseTokenExpiry(jwtExpiringAt(exp))
+	if err != nil || !ok {
+		t.Fatalf("ParseTokenExpiry() = %v, %v, %v; want exp", got, ok, err)
+	}
+	if !got.Equal(exp) {
+		t.Errorf("ParseTokenExpiry() = %v, want %v", got, exp)
+	}
+
+	if _, ok, err := ParseTokenExpiry(testJWT(`{"sub":"user"}`)); err != nil || ok {
+		t.Errorf("ParseTokenExpiry() without exp = ok %v, err %v; want ok false, no error", ok, err)
+	}
+
+	if _, _, err := ParseTokenExpiry("not-a-jwt"); err == nil {
+		t.Error("ParseTokenExpiry() on malformed token: expected error")
+	}
+}
+
+func TestClient_ExpiredTokenFailsFast(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	exp := time.Now().Add(-time.Hour).Truncate(time.Second)
+	client := NewClient()
+	client.SetAuthToken(jwtExpiringAt(exp))
+
+	_, err := client.GetLocation("Leavenworth-344933")
+
+	var expired *TokenExpiredError
+	if !errors.As(err, &expired) || !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("GetLocation() error = %v, want TokenExpiredError", err)
+	}
+	if !expired.ExpiresAt.Equal(exp) {
+		t.Errorf("ExpiresAt = %v, want %v", expired.ExpiresAt, exp)
+	}
+	if requests != 0 {
+		t.Errorf("sent %d request(s) with an expired token, want 0", requests)
+	}
+}
+
+func TestClient_UnauthorizedWithoutRefresher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "jwt expired", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	client := NewClient()
+	client.SetAuthToken(testJWT(`{"sub":"user"}`))
+
+	_, err := client.GetLocation("Leavenworth-344933")
+
+	var expired *TokenExpiredError
+	if !errors.As(err, &expired) {
+		t.Fatalf("GetLocation() error = %v, want TokenExpiredError", err)
+	}
+	if expired.StatusCode != http.StatusUnauthorized {
+		t.Errorf("StatusCode = %d, want 401", expired.StatusCode)
+	}
+}
+
+func TestClient_UnauthorizedRefreshesAndRetries(t *testing.T) {
+	stale := testJWT(`{"sub":"stale"}`)
+	fresh := jwtExpiringAt(time.Now().Add(time.Hour))
+
+	var authHeaders []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer "+fresh {
+			http.Error(w, "jwt expired", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"webLocation":{"id":"1","slug":"Leavenworth-344933","name":"Leavenworth"}}}`)
+	}))
+	defer srv.Close()
+	setGraphQLURLForTest(t, srv.URL)
+
+	client := NewClient()
+	client.SetAuthToken(stale)
+	refreshes := 0
+	client.SetTokenRefresher(func() (string, error) {
+		refreshes++
+		return fresh, nil
+	})
+
+	location, err := client.GetLocation("Leavenworth-344933")
+	if err != nil {
+		t.Fatalf("GetLocation() error = %v", err)
+	}
+	if location.Name != "Leavenworth" {
+		t.Errorf("location name = %q, want Leavenworth", location.Name)
+	}
+	if refreshes != 1 {
+		t.Errorf("refreshed %d time(s), want 1", refreshes)
+	}
+	if len(authHeaders) != 2 || authHeaders[0] != "Bearer "+stale || authHeaders[1] != "Bearer "+fresh {
+		t.Errorf("Authorization headers = %v, want stale then fresh token", authHeaders)
+	}
+}
@@ -0,0 +1,29 @@
+package kayamatch
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldedCharacters maps curly quotes to ASCII and spells out letters that
+// don't decompose under NFD.
+var foldedCharacters = strings.NewReplacer(
+	"\u2018", "'", "\u2019", "'", "\u201b", "'", "\u2032", "'",
+	"\u201c", `"`, "\u201d", `"`, "\u2033", `"`,
+	"ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d",
+)
+
+// FoldDiacritics strips accents (NFD, then drop combining marks), so "café"
+// becomes "cafe" and "señor" becomes "senor" instead of losing the letter to
+// an ASCII filter when route names are normalized. Curly quotes become ASCII.
+func FoldDiacritics(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	if folded, _, err := transform.String(t, name); err == nil {
+		name = folded
+	}
+	return foldedCharacters.Replace(name)
+}
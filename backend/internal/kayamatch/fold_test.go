@@ -0,0 +1,21 @@
+package kayamatch
+
+import "testing"
+
+func TestFoldDiacritics(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"café", "cafe"},
+		{"Señor", "Senor"},
+		{"L’Arête", "L'Arete"},
+		{"straße", "strasse"},
+		{"plain", "plain"},
+	}
+	for _, tt := range tests {
+		if got := FoldDiacritics(tt.name); got != tt.want {
+			t.Errorf("FoldDiacritics(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
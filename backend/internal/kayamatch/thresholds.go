@@ -0,0 +1,248 @@
+// Package kayamatch holds the scoring shared by everything that matches Kaya
+// climbs to Mountain Project routes: the confidence weights and match-type
+// thresholds, the flags and JSON file that tune them, the review status a new
+// match is saved with, and the folding applied to route names before they
+// are compared.
+package kayamatch
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// Thresholds holds the weights and boundaries used to score and classify
+// matches. The defaults reproduce the values the matcher was tuned with.
+type Thresholds struct {
+	// Confidence scoring
+	NameWeight        float64 `json:"name_weight"`         // share of confidence from name similarity
+	LocationBonus     float64 `json:"location_bonus"`      // added when location names match
+	ProximityBonus    float64 `json:"proximity_bonus"`     // maximum bonus for GPS proximity
+	ProximityRadiusKM float64 `json:"proximity_radius_km"` // distance at which the proximity bonus reaches 0
+
+	// match_type classification
+	ExactNameSimilarity         float64 `json:"exact_name_similarity"`          // exact_name at or above
+	FuzzyNameLocationSimilarity float64 `json:"fuzzy_name_location_similarity"` // fuzzy_name_location at or above, with location match
+	FuzzyNameSimilarity         float64 `json:"fuzzy_name_similarity"`          // fuzzy_name at or above
+	GPSProximityKM              float64 `json:"gps_proximity_km"`               // location_gps_proximity below this distance
+}
+
+// DefaultThresholds returns the thresholds the matcher was tuned with.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		NameWeight:                  0.7,
+		LocationBonus:               0.2,
+		ProximityBonus:              0.1,
+		ProximityRadiusKM:           5.0,
+		ExactNameSimilarity:         1.0,
+		FuzzyNameLocationSimilarity: 0.9,
+		FuzzyNameSimilarity:         0.85,
+		GPSProximityKM:              1.0,
+	}
+}
+
+// thresholdFlag binds a command-line flag to one Thresholds field.
+type thresholdFlag struct {
+	name  string
+	usage string
+	field func(t *Thresholds) *float64
+}
+
+var thresholdFlags = []thresholdFlag{
+	{"name-weight", "Confidence weight of name similarity", func(t *Thresholds) *float64 { return &t.NameWeight }},
+	{"location-bonus", "Confidence bonus for a location name match", func(t *Thresholds) *float64 { return &t.LocationBonus }},
+	{"proximity-bonus", "Maximum confidence bonus for GPS proximity", func(t *Thresholds) *float64 { return &t.ProximityBonus }},
+	{"proximity-radius-km", "Distance (km) at which the proximity bonus reaches 0", func(t *Thresholds) *float64 { return &t.ProximityRadiusKM }},
+	{"exact-name", "Name similarity for match_type exact_name", func(t *Thresholds) *float64 { return &t.ExactNameSimilarity }},
+	{"fuzzy-name-location", "Name similarity for match_type fuzzy_name_location", func(t *Thresholds) *float64 { return &t.FuzzyNameLocationSimilarity }},
+	{"fuzzy-name", "Name similarity for match_type fuzzy_name", func(t *Thresholds) *float64 { return &t.FuzzyNameSimilarity }},
+	{"gps-proximity-km", "Distance (km) for match_type location_gps_proximity", func(t *Thresholds) *float64 { return &t.GPSProximityKM }},
+}
+
+// weightFlags are shorter names for the confidence weights, so a run can be
+// tuned with -weight-name rather than -threshold-name-weight.
+var weightFlags = []struct {
+	flag      string
+	threshold string
+}{
+	{"weight-name", "name-weight"},
+	{"weight-location", "location-bonus"},
+	{"weight-proximity", "proximity-bonus"},
+}
+
+// RegisterFlags adds a -threshold-<name> flag per threshold and a -weight-*
+// alias per confidence weight. Read them back with ResolveThresholds.
+func RegisterFlags(fs *flag.FlagSet) {
+	defaults := DefaultThresholds()
+	for _, tf := range thresholdFlags {
+		fs.Float64("threshold-"+tf.name, *tf.field(&defaults), tf.usage)
+	}
+	for _, wf := range weightFlags {
+		tf, _ := lookupThresholdFlag("threshold-" + wf.threshold)
+		fs.Float64(wf.flag, *tf.field(&defaults), tf.usage+" (same as -threshold-"+wf.threshold+")")
+	}
+}
+
+// lookupThresholdFlag returns the threshold a -threshold-* or -weight-* flag
+// sets.
+func lookupThresholdFlag(flagName string) (thresholdFlag, bool) {
+	for _, wf := range weightFlags {
+		if flagName == wf.flag {
+			flagName = "threshold-" + wf.threshold
+		}
+	}
+	for _, tf := range thresholdFlags {
+		if flagName == "threshold-"+tf.name {
+			return tf, true
+		}
+	}
+	return thresholdFlag{}, false
+}
+
+// ResolveThresholds builds the effective thresholds: defaults, then the JSON
+// file at path (if any), then -threshold-* flags set on the command line.
+func ResolveThresholds(fs *flag.FlagSet, path string) (Thresholds, error) {
+	t := DefaultThresholds()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return t, fmt.Errorf("failed to read thresholds file: %w", err)
+		}
+		if err := json.Unmarshal(data, &t); err != nil {
+			return t, fmt.Errorf("failed to parse thresholds file %s: %w", path, err)
+		}
+	}
+
+	var flagErr error
+	setBy := make(map[string]string) // threshold name -> flag that set it
+	fs.Visit(func(f *flag.Flag) {
+		tf, ok := lookupThresholdFlag(f.Name)
+		if !ok || flagErr != nil {
+			return
+		}
+		if prev, dup := setBy[tf.name]; dup {
+			flagErr = fmt.Errorf("-%s and -%s both set threshold %s", prev, f.Name, tf.name)
+			return
+		}
+		setBy[tf.name] = f.Name
+		v, err := strconv.ParseFloat(f.Value.String(), 64)
+		if err != nil {
+			flagErr = fmt.Errorf("invalid -%s: %w", f.Name, err)
+			return
+		}
+		*tf.field(&t) = v
+	})
+	if flagErr != nil {
+		return t, flagErr
+	}
+
+	return t, t.Validate()
+}
+
+// weightSumTolerance absorbs float rounding, so weights like 0.7, 0.2 and 0.1
+// pass the sum check.
+const weightSumTolerance = 1e-9
+
+// Validate reports whether t is usable: no negative values, confidence
+// weights summing to at most 1.0, and name thresholds in order.
+func (t Thresholds) Validate() error {
+	for _, tf := range thresholdFlags {
+		if v := *tf.field(&t); v < 0 {
+			return fmt.Errorf("threshold %s must not be negative, got %v", tf.name, v)
+		}
+	}
+	// Confidence is capped at 1.0, so weights summing past it would let a
+	// partial name match reach full confidence on location alone.
+	if sum := t.NameWeight + t.LocationBonus + t.ProximityBonus; sum > 1.0+weightSumTolerance {
+		return fmt.Errorf("confidence weights name-weight + location-bonus + proximity-bonus must sum to at most 1.0, got %.4g", sum)
+	}
+	if t.ProximityRadiusKM == 0 {
+		return fmt.Errorf("threshold proximity-radius-km must be positive")
+	}
+	if t.FuzzyNameSimilarity > t.FuzzyNameLocationSimilarity || t.FuzzyNameLocationSimilarity > t.ExactNameSimilarity {
+		return fmt.Errorf("name similarity thresholds must satisfy fuzzy-name <= fuzzy-name-location <= exact-name, got %v, %v, %v",
+			t.FuzzyNameSimilarity, t.FuzzyNameLocationSimilarity, t.ExactNameSimilarity)
+	}
+	return nil
+}
+
+// Confidence computes a match's overall confidence from its name similarity,
+// whether the location names match, and the GPS distance (nil if unknown),
+// capped at 1.0.
+func (t Thresholds) Confidence(nameSim float64, locationMatch bool, distanceKM *float64) float64 {
+	confidence := nameSim * t.NameWeight
+
+	if locationMatch {
+		confidence += t.LocationBonus
+	}
+
+	if distanceKM != nil && *distanceKM < t.ProximityRadiusKM {
+		proximityBonus := t.ProximityBonus * (1.0 - (*distanceKM / t.ProximityRadiusKM))
+		confidence += proximityBonus
+	}
+
+	if confidence > 1.0 {
+		confidence = 1.0
+	}
+
+	return confidence
+}
+
+// MatchType classifies a match for kaya_mp_route_matches.match_type.
+func (t Thresholds) MatchType(nameSim float64, locationMatch bool, distanceKM *float64) string {
+	if nameSim >= t.ExactNameSimilarity {
+		return "exact_name"
+	}
+	if nameSim >= t.FuzzyNameLocationSimilarity && locationMatch {
+		return "fuzzy_name_location"
+	}
+	if nameSim >= t.FuzzyNameSimilarity {
+		return "fuzzy_name"
+	}
+	if locationMatch && distanceKM != nil && *distanceKM < t.GPSProximityKM {
+		return "location_gps_proximity"
+	}
+	if locationMatch {
+		return "location_name"
+	}
+	return "low_confidence"
+}
+
+// DefaultAutoApproveThreshold is the confidence at or above which a match is
+// saved as approved rather than queued for review.
+const DefaultAutoApproveThreshold = 0.90
+
+// Values of kaya_mp_route_matches.match_status set by the matcher.
+const (
+	StatusApproved = "approved"
+	StatusPending  = "pending"
+)
+
+// StatusFor returns the review status a new match is saved with.
+func StatusFor(confidence, autoApproveThreshold float64) string {
+	if confidence >= autoApproveThreshold {
+		return StatusApproved
+	}
+	return StatusPending
+}
+
+// ValidateAutoApproveThreshold rejects thresholds outside the 0-1 confidence
+// range. A threshold above 1 would silently queue every match for review.
+func ValidateAutoApproveThreshold(v float64) error {
+	if v < 0 || v > 1 {
+		return fmt.Errorf("auto-approve threshold must be between 0 and 1, got %v", v)
+	}
+	return nil
+}
+
+// Log prints the effective thresholds for a run summary.
+func (t Thresholds) Log() {
+	log.Printf("Match thresholds:")
+	for _, tf := range thresholdFlags {
+		log.Printf("  - %s: %v", tf.name, *tf.field(&t))
+	}
+}
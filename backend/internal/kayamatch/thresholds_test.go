@@ -0,0 +1,177 @@
+package kayamatch
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchType_Defaults(t *testing.T) {
+	th := DefaultThresholds()
+	near, far := 0.5, 3.0
+
+	tests := []struct {
+		name          string
+		nameSim       float64
+		locationMatch bool
+		distanceKM    *float64
+		want          string
+	}{
+		{"exact name", 1.0, false, nil, "exact_name"},
+		{"fuzzy name with location", 0.9, true, nil, "fuzzy_name_location"},
+		{"fuzzy name without location", 0.9, false, nil, "fuzzy_name"},
+		{"fuzzy name boundary", 0.85, false, nil, "fuzzy_name"},
+		{"gps proximity", 0.5, true, &near, "location_gps_proximity"},
+		{"location name only", 0.5, true, &far, "location_name"},
+		{"low confidence", 0.5, false, &near, "low_confidence"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := th.MatchType(tt.nameSim, tt.locationMatch, tt.distanceKM); got != tt.want {
+				t.Errorf("MatchType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchType_CustomThresholds(t *testing.T) {
+	th := DefaultThresholds()
+	th.FuzzyNameSimilarity = 0.8
+	th.GPSProximityKM = 5.0
+	dist := 3.0
+
+	if got := th.MatchType(0.82, false, nil); got != "fuzzy_name" {
+		t.Errorf("got %q, want fuzzy_name with lowered fuzzy-name threshold", got)
+	}
+	if got := th.MatchType(0.5, true, &dist); got != "location_gps_proximity" {
+		t.Errorf("got %q, want location_gps_proximity with widened gps radius", got)
+	}
+}
+
+func TestConfidence_Defaults(t *testing.T) {
+	th := DefaultThresholds()
+	dist := 2.5
+
+	// 0.8*0.7 + 0.2 + 0.1*(1 - 2.5/5) = 0.81
+	if got := th.Confidence(0.8, true, &dist); got < 0.809 || got > 0.811 {
+		t.Errorf("Confidence() = %v, want 0.81", got)
+	}
+	th.LocationBonus = 0.5
+	if got := th.Confidence(1.0, true, &dist); got != 1.0 {
+		t.Errorf("Confidence() = %v, want capped at 1.0", got)
+	}
+}
+
+func TestResolveThresholds(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "thresholds.json")
+	if err := os.WriteFile(path, []byte(`{"fuzzy_name_similarity": 0.8, "gps_proximity_km": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newFlagSet := func(args ...string) *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		RegisterFlags(fs)
+		if err := fs.Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return fs
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		got, err := ResolveThresholds(newFlagSet(), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != DefaultThresholds() {
+			t.Errorf("got %+v, want defaults", got)
+		}
+	})
+
+	t.Run("file overrides defaults and flags override file", func(t *testing.T) {
+		got, err := ResolveThresholds(newFlagSet("-threshold-gps-proximity-km", "3"), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.FuzzyNameSimilarity != 0.8 {
+			t.Errorf("FuzzyNameSimilarity = %v, want 0.8 from file", got.FuzzyNameSimilarity)
+		}
+		if got.GPSProximityKM != 3 {
+			t.Errorf("GPSProximityKM = %v, want 3 from flag", got.GPSProximityKM)
+		}
+		if got.ExactNameSimilarity != 1.0 {
+			t.Errorf("ExactNameSimilarity = %v, want default 1.0", got.ExactNameSimilarity)
+		}
+	})
+
+	t.Run("rejects inverted name thresholds", func(t *testing.T) {
+		if _, err := ResolveThresholds(newFlagSet("-threshold-fuzzy-name", "0.95"), ""); err == nil {
+			t.Error("expected error when fuzzy-name exceeds fuzzy-name-location")
+		}
+	})
+
+	t.Run("weight flags set the confidence weights", func(t *testing.T) {
+		got, err := ResolveThresholds(newFlagSet("-weight-name", "0.6", "-weight-location", "0.3", "-weight-proximity", "0.1"), "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.NameWeight != 0.6 || got.LocationBonus != 0.3 || got.ProximityBonus != 0.1 {
+			t.Errorf("weights = %v, %v, %v, want 0.6, 0.3, 0.1", got.NameWeight, got.LocationBonus, got.ProximityBonus)
+		}
+	})
+
+	t.Run("rejects a weight flag and its threshold flag together", func(t *testing.T) {
+		if _, err := ResolveThresholds(newFlagSet("-weight-name", "0.6", "-threshold-name-weight", "0.5"), ""); err == nil {
+			t.Error("expected error when -weight-name and -threshold-name-weight are both set")
+		}
+	})
+
+	t.Run("rejects weights summing past 1.0", func(t *testing.T) {
+		if _, err := ResolveThresholds(newFlagSet("-weight-location", "0.3"), ""); err == nil {
+			t.Error("expected error when weights sum to 1.1")
+		}
+	})
+
+	t.Run("rejects missing file", func(t *testing.T) {
+		if _, err := ResolveThresholds(newFlagSet(), filepath.Join(dir, "missing.json")); err == nil {
+			t.Error("expected error for missing file")
+		}
+	})
+}
+
+func TestStatusFor(t *testing.T) {
+	tests := []struct {
+		confidence float64
+		threshold  float64
+		want       string
+	}{
+		{0.95, 0.90, StatusApproved},
+		{0.90, 0.90, StatusApproved},
+		{0.89, 0.90, StatusPending},
+		{0.80, 0.75, StatusApproved},
+		{0.99, 1.0, StatusPending},
+	}
+
+	for _, tt := range tests {
+		if got := StatusFor(tt.confidence, tt.threshold); got != tt.want {
+			t.Errorf("StatusFor(%v, %v) = %q, want %q", tt.confidence, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAutoApproveThreshold(t *testing.T) {
+	for _, v := range []float64{0, 0.9, 1} {
+		if err := ValidateAutoApproveThreshold(v); err != nil {
+			t.Errorf("ValidateAutoApproveThreshold(%v) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []float64{-0.1, 1.5} {
+		if err := ValidateAutoApproveThreshold(v); err == nil {
+			t.Errorf("ValidateAutoApproveThreshold(%v) = nil, want error", v)
+		}
+	}
+}
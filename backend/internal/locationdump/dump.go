@@ -0,0 +1,130 @@
+// Package locationdump serializes one location's climbing data — its
+// Mountain Project areas, routes, recent ticks and comments, and its drying
+// profiles — to a single JSON document, and restores it into a database.
+//
+// It backs the export_location and import_location commands, which are used
+// to reproduce issues locally and to seed development databases. Mountain
+// Project IDs are stable across databases, so areas, routes, ticks and
+// comments are upserted by their MP IDs. The location itself is upserted by
+// name and its woulder area is resolved by name, since serial IDs differ
+// between databases.
+package locationdump
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// FormatVersion is the dump format written by Write. Read rejects any other
+// version.
+const FormatVersion = 1
+
+// Dump is one location's exported dataset.
+type Dump struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	TicksSince time.Time `json:"ticks_since"` // Ticks climbed before this are not included
+
+	Location models.Location `json:"location"`
+	AreaName string          `json:"area_name"` // Name of the location's woulder area
+
+	// SunExposure is the location's drying profile, if it has one.
+	SunExposure *models.LocationSunExposure `json:"sun_exposure,omitempty"`
+
+	// MPAreas are the areas linked to the location, plus any other area one
+	// of its routes belongs to.
+	MPAreas        []models.MPArea               `json:"mp_areas"`
+	Routes         []models.MPRoute              `json:"routes"`
+	Ticks          []models.MPTick               `json:"ticks"`
+	Comments       []models.MPComment            `json:"comments"`
+	DryingProfiles []models.BoulderDryingProfile `json:"drying_profiles"`
+}
+
+// Write encodes d as indented JSON.
+func Write(w io.Writer, d *Dump) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// Read decodes and validates a dump.
+func Read(r io.Reader) (*Dump, error) {
+	var d Dump
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return nil, fmt.Errorf("decode dump: %w", err)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Validate checks that d is a supported version and self-contained: every
+// route's area, and every tick, comment and drying profile's target, is in
+// the dump. Import relies on this to satisfy the foreign keys between them.
+func (d *Dump) Validate() error {
+	if d.Version != FormatVersion {
+		return fmt.Errorf("unsupported dump version %d (want %d)", d.Version, FormatVersion)
+	}
+	if strings.TrimSpace(d.Location.Name) == "" {
+		return fmt.Errorf("location name is empty")
+	}
+	if strings.TrimSpace(d.AreaName) == "" {
+		return fmt.Errorf("area name is empty")
+	}
+	if d.Location.Timezone == "" {
+		return fmt.Errorf("location timezone is empty")
+	}
+
+	areaIDs := make(map[int64]bool, len(d.MPAreas))
+	for _, a := range d.MPAreas {
+		areaIDs[a.MPAreaID] = true
+	}
+	routeIDs := make(map[int64]bool, len(d.Routes))
+	for _, r := range d.Routes {
+		if !areaIDs[r.MPAreaID] {
+			return fmt.Errorf("route %d: area %d is not in the dump", r.MPRouteID, r.MPAreaID)
+		}
+		routeIDs[r.MPRouteID] = true
+	}
+	for _, t := range d.Ticks {
+		if !routeIDs[t.MPRouteID] {
+			return fmt.Errorf("tick by %q: route %d is not in the dump", t.UserName, t.MPRouteID)
+		}
+	}
+	for _, c := range d.Comments {
+		switch {
+		case c.CommentType == "area" && c.MPAreaID != nil && c.MPRouteID == nil:
+			if !areaIDs[*c.MPAreaID] {
+				return fmt.Errorf("comment %d: area %d is not in the dump", c.MPCommentID, *c.MPAreaID)
+			}
+		case c.CommentType == "route" && c.MPRouteID != nil && c.MPAreaID == nil:
+			if !routeIDs[*c.MPRouteID] {
+				return fmt.Errorf("comment %d: route %d is not in the dump", c.MPCommentID, *c.MPRouteID)
+			}
+		default:
+			return fmt.Errorf("comment %d: invalid target (type %q)", c.MPCommentID, c.CommentType)
+		}
+	}
+	for _, p := range d.DryingProfiles {
+		if !routeIDs[p.MPRouteID] {
+			return fmt.Errorf("drying profile: route %d is not in the dump", p.MPRouteID)
+		}
+	}
+	return nil
+}
+
+// remapLocationID maps a location reference from the exporting database to
+// the importing one. References to the exported location become to; any
+// other location does not exist in the importing database, so it is cleared.
+func remapLocationID(id *int, from, to int) *int {
+	if id == nil || *id != from {
+		return nil
+	}
+	return &to
+}
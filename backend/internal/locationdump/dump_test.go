@@ -0,0 +1,200 @@
+package locationdump
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func testDump() *Dump {
+	locationID := 7
+	otherLocationID := 8
+	routeID := int64(2)
+	areaID := int64(100)
+	climbed := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	return &Dump{
+		Version:    FormatVersion,
+		ExportedAt: climbed.Add(24 * time.Hour),
+		TicksSince: climbed.AddDate(0, -1, 0),
+		Location:   models.Location{ID: locationID, Name: "Gold Bar", Latitude: 47.85, Longitude: -121.64, AreaID: 3, Timezone: "America/Los_Angeles"},
+		AreaName:   "Skykomish",
+		MPAreas: []models.MPArea{
+			{MPAreaID: areaID, Name: "Gold Bar", AreaType: "area", LocationID: &locationID},
+			{MPAreaID: 101, Name: "Elsewhere", AreaType: "area", LocationID: &otherLocationID},
+		},
+		Routes: []models.MPRoute{
+			{MPRouteID: routeID, MPAreaID: areaID, Name: "Hobbit Hole", RouteType: "Boulder", Rating: "V4", LocationID: &locationID},
+		},
+		Ticks: []models.MPTick{
+			{MPRouteID: routeID, UserName: "Alex", ClimbedAt: climbed, Style: "Flash"},
+		},
+		Comments: []models.MPComment{
+			{MPCommentID: 500, CommentType: "area", MPAreaID: &areaID, UserName: "Sam", CommentText: "Seeps after rain", CommentedAt: climbed},
+			{MPCommentID: 501, CommentType: "route", MPRouteID: &routeID, UserName: "Alex", CommentText: "Great problem", CommentedAt: climbed},
+		},
+		DryingProfiles: []models.BoulderDryingProfile{
+			{MPRouteID: routeID},
+		},
+	}
+}
+
+func TestDump_Validate(t *testing.T) {
+	missing := int64(999)
+	tests := []struct {
+		name   string
+		modify func(d *Dump)
+		want   string
+	}{
+		{"valid", func(d *Dump) {}, ""},
+		{"wrong version", func(d *Dump) { d.Version = 2 }, "unsupported dump version 2"},
+		{"no location name", func(d *Dump) { d.Location.Name = " " }, "location name is empty"},
+		{"no area name", func(d *Dump) { d.AreaName = "" }, "area name is empty"},
+		{"no timezone", func(d *Dump) { d.Location.Timezone = "" }, "timezone is empty"},
+		{"route area missing", func(d *Dump) { d.MPAreas = d.MPAreas[1:] }, "route 2: area 100 is not in the dump"},
+		{"tick route missing", func(d *Dump) { d.Ticks[0].MPRouteID = missing }, "route 999 is not in the dump"},
+		{"comment area missing", func(d *Dump) { d.Comments[0].MPAreaID = &missing }, "comment 500: area 999"},
+		{"comment with two targets", func(d *Dump) { d.Comments[1].MPAreaID = d.Comments[0].MPAreaID }, "comment 501: invalid target"},
+		{"profile route missing", func(d *Dump) { d.DryingProfiles[0].MPRouteID = missing }, "drying profile: route 999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := testDump()
+			tt.modify(d)
+			err := d.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testDump()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	want := testDump()
+	if got.Location.Name != want.Location.Name || got.AreaName != want.AreaName {
+		t.Errorf("Read() location = %q in %q, want %q in %q", got.Location.Name, got.AreaName, want.Location.Name, want.AreaName)
+	}
+	if len(got.Routes) != 1 || len(got.Ticks) != 1 || len(got.Comments) != 2 || len(got.DryingProfiles) != 1 {
+		t.Errorf("Read() = %d routes, %d ticks, %d comments, %d profiles, want 1, 1, 2, 1",
+			len(got.Routes), len(got.Ticks), len(got.Comments), len(got.DryingProfiles))
+	}
+	if !got.Ticks[0].ClimbedAt.Equal(want.Ticks[0].ClimbedAt) {
+		t.Errorf("Read() tick climbed_at = %v, want %v", got.Ticks[0].ClimbedAt, want.Ticks[0].ClimbedAt)
+	}
+
+	if _, err := Read(strings.NewReader(`{"version": 2}`)); err == nil {
+		t.Error("Read() should reject an unsupported version")
+	}
+}
+
+func TestRemapLocationID(t *testing.T) {
+	from, other := 7, 8
+
+	if got := remapLocationID(&from, 7, 42); got == nil || *got != 42 {
+		t.Errorf("remapLocationID(7) = %v, want 42", got)
+	}
+	if got := remapLocationID(&other, 7, 42); got != nil {
+		t.Errorf("remapLocationID(8) = %v, want nil", *got)
+	}
+	if got := remapLocationID(nil, 7, 42); got != nil {
+		t.Errorf("remapLocationID(nil) = %v, want nil", *got)
+	}
+}
+
+func TestImport(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM woulder\.areas`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "description", "region", "display_order", "is_active", "created_at", "updated_at",
+		}).AddRow(5, "skykomish", "", "", 1, true, now, now))
+	// The location is linked to the importing database's area ID
+	mock.ExpectQuery(`INSERT INTO woulder\.locations`).
+		WithArgs("Gold Bar", 47.85, -121.64, 0, 5, false, "America/Los_Angeles").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "inserted"}).AddRow(42, true))
+	// Areas and routes of the exported location move to the new ID; other
+	// locations don't exist here
+	mock.ExpectExec(`INSERT INTO woulder\.mp_areas`).
+		WithArgs(int64(100), "Gold Bar", nil, "area", 42, nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO woulder\.mp_areas`).
+		WithArgs(int64(101), "Elsewhere", nil, "area", nil, nil, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO woulder\.mp_routes`).
+		WithArgs(int64(2), int64(100), "Hobbit Hole", "Boulder", "V4", 42, nil, nil, nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE woulder\.mp_routes\s+SET difficulty`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO woulder\.mp_ticks`).
+		WithArgs(int64(2), "Alex", sqlmock.AnyArg(), "Flash", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`VALUES ($1, 'area'`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`VALUES ($1, 'route'`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO woulder\.boulder_drying_profiles`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := Import(context.Background(), NewPostgresRepos(db), testDump())
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	want := Result{LocationID: 42, LocationCreated: true, MPAreas: 2, Routes: 1, Ticks: 1, Comments: 2, DryingProfiles: 1}
+	if *res != want {
+		t.Errorf("Import() = %+v, want %+v", *res, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestImport_UnknownArea(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM woulder\.areas`).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "name", "description", "region", "display_order", "is_active", "created_at", "updated_at",
+		}).AddRow(5, "Leavenworth", "", "", 1, true, now, now))
+
+	_, err = Import(context.Background(), NewPostgresRepos(db), testDump())
+	if err == nil || !strings.Contains(err.Error(), `area "Skykomish" does not exist`) {
+		t.Errorf("Import() error = %v, want unknown area", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
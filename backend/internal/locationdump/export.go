@@ -0,0 +1,112 @@
+package locationdump
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// Export reads location locationID's dataset, including ticks climbed at or
+// after ticksSince. Slices are sorted by MP ID so that exports of the same
+// data are identical apart from ExportedAt.
+func Export(ctx context.Context, r Repos, locationID int, ticksSince, now time.Time) (*Dump, error) {
+	loc, err := r.Locations.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("get location %d: %w", locationID, err)
+	}
+	area, err := r.Areas.GetByID(ctx, loc.AreaID)
+	if err != nil {
+		return nil, fmt.Errorf("get area %d: %w", loc.AreaID, err)
+	}
+	sunExposure, err := r.Rocks.GetSunExposureByLocation(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("get sun exposure: %w", err)
+	}
+
+	mp := r.MountainProject
+	mpAreas, err := mp.Areas().GetAreasByLocation(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("get mp areas: %w", err)
+	}
+
+	routeIDs, err := mp.Routes().GetAllIDsForLocation(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("get route ids: %w", err)
+	}
+	routesByID, err := mp.Routes().GetByIDs(ctx, routeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get routes: %w", err)
+	}
+	routes := make([]models.MPRoute, 0, len(routesByID))
+	for _, route := range routesByID {
+		routes = append(routes, *route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].MPRouteID < routes[j].MPRouteID })
+
+	// A route can sit in an area linked to another location (or to none);
+	// include those areas too so the routes' area references resolve.
+	haveArea := make(map[int64]bool, len(mpAreas))
+	for _, a := range mpAreas {
+		haveArea[a.MPAreaID] = true
+	}
+	for _, route := range routes {
+		if haveArea[route.MPAreaID] {
+			continue
+		}
+		a, err := mp.Areas().GetAreaByID(ctx, route.MPAreaID)
+		if err != nil {
+			return nil, fmt.Errorf("get mp area %d: %w", route.MPAreaID, err)
+		}
+		if a == nil {
+			return nil, fmt.Errorf("route %d: mp area %d not found", route.MPRouteID, route.MPAreaID)
+		}
+		mpAreas = append(mpAreas, *a)
+		haveArea[a.MPAreaID] = true
+	}
+	sort.Slice(mpAreas, func(i, j int) bool { return mpAreas[i].MPAreaID < mpAreas[j].MPAreaID })
+
+	areaIDs := make([]int64, len(mpAreas))
+	for i, a := range mpAreas {
+		areaIDs[i] = a.MPAreaID
+	}
+	routeIDs = make([]int64, len(routes))
+	for i, route := range routes {
+		routeIDs[i] = route.MPRouteID
+	}
+
+	ticks, err := mp.Ticks().GetTicksForRoutes(ctx, routeIDs, ticksSince)
+	if err != nil {
+		return nil, fmt.Errorf("get ticks: %w", err)
+	}
+	comments, err := mp.Comments().GetComments(ctx, areaIDs, routeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get comments: %w", err)
+	}
+
+	profilesByID, err := r.Boulders.GetProfilesByIDs(ctx, routeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get drying profiles: %w", err)
+	}
+	profiles := make([]models.BoulderDryingProfile, 0, len(profilesByID))
+	for _, p := range profilesByID {
+		profiles = append(profiles, *p)
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].MPRouteID < profiles[j].MPRouteID })
+
+	return &Dump{
+		Version:        FormatVersion,
+		ExportedAt:     now,
+		TicksSince:     ticksSince,
+		Location:       *loc,
+		AreaName:       area.Name,
+		SunExposure:    sunExposure,
+		MPAreas:        mpAreas,
+		Routes:         routes,
+		Ticks:          ticks,
+		Comments:       comments,
+		DryingProfiles: profiles,
+	}, nil
+}
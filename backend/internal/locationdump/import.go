@@ -0,0 +1,121 @@
+package locationdump
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Result counts what Import wrote.
+type Result struct {
+	LocationID      int
+	LocationCreated bool
+	MPAreas         int
+	Routes          int
+	Ticks           int
+	Comments        int
+	DryingProfiles  int
+}
+
+// Import upserts d into the database behind r. It writes row by row, so the
+// caller should run it inside a transaction (see NewPostgresRepos) to get all
+// or nothing.
+//
+// The location is upserted by name, as with import_locations: an existing
+// location keeps its elevation and seepage settings. MP areas and routes
+// linked to the exported location are linked to the imported one; links to
+// any other location are cleared. Ticks and comments are upserted without
+// touching the routes' sync timestamps, so the next background sync still
+// fetches the routes.
+func Import(ctx context.Context, r Repos, d *Dump) (*Result, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	areaID, err := resolveArea(ctx, r, d.AreaName)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := d.Location
+	loc.AreaID = areaID
+	locationID, created, err := r.Locations.Upsert(ctx, loc)
+	if err != nil {
+		return nil, fmt.Errorf("upsert location %q: %w", loc.Name, err)
+	}
+	res := &Result{LocationID: locationID, LocationCreated: created}
+
+	if d.SunExposure != nil {
+		se := *d.SunExposure
+		se.LocationID = locationID
+		if err := r.Rocks.SaveSunExposure(ctx, &se); err != nil {
+			return nil, fmt.Errorf("save sun exposure: %w", err)
+		}
+	}
+
+	mp := r.MountainProject
+	for _, a := range d.MPAreas {
+		a.LocationID = remapLocationID(a.LocationID, d.Location.ID, locationID)
+		if err := mp.Areas().SaveArea(ctx, &a); err != nil {
+			return nil, fmt.Errorf("save mp area %d: %w", a.MPAreaID, err)
+		}
+		res.MPAreas++
+	}
+
+	for _, route := range d.Routes {
+		route.LocationID = remapLocationID(route.LocationID, d.Location.ID, locationID)
+		if err := mp.Routes().SaveRoute(ctx, &route); err != nil {
+			return nil, fmt.Errorf("save route %d: %w", route.MPRouteID, err)
+		}
+		if err := mp.Routes().UpdateRouteDetails(ctx, route.MPRouteID,
+			route.Difficulty, route.Pitches, route.HeightFeet, route.MPRating, route.Popularity,
+			route.DescriptionText, route.LocationText, route.ProtectionText, route.SafetyText,
+		); err != nil {
+			return nil, fmt.Errorf("save route %d details: %w", route.MPRouteID, err)
+		}
+		res.Routes++
+	}
+
+	for _, t := range d.Ticks {
+		if err := mp.Ticks().UpsertTick(ctx, t.MPRouteID, t.UserName, t.ClimbedAt, t.Style, t.Comment); err != nil {
+			return nil, fmt.Errorf("save tick on route %d: %w", t.MPRouteID, err)
+		}
+		res.Ticks++
+	}
+
+	for _, c := range d.Comments {
+		// Validate guarantees exactly one target, matching the comment type.
+		if c.CommentType == "area" {
+			err = mp.Comments().UpsertAreaComment(ctx, c.MPCommentID, *c.MPAreaID, c.UserName, c.UserID, c.CommentText, c.CommentedAt)
+		} else {
+			err = mp.Comments().UpsertRouteComment(ctx, c.MPCommentID, *c.MPRouteID, c.UserName, c.UserID, c.CommentText, c.CommentedAt)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("save comment %d: %w", c.MPCommentID, err)
+		}
+		res.Comments++
+	}
+
+	for _, p := range d.DryingProfiles {
+		if err := r.Boulders.SaveProfile(ctx, &p); err != nil {
+			return nil, fmt.Errorf("save drying profile for route %d: %w", p.MPRouteID, err)
+		}
+		res.DryingProfiles++
+	}
+
+	return res, nil
+}
+
+// resolveArea finds the active woulder area named name (case-insensitive).
+func resolveArea(ctx context.Context, r Repos, name string) (int, error) {
+	areas, err := r.Areas.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("get areas: %w", err)
+	}
+	for _, a := range areas {
+		if strings.EqualFold(a.Name, strings.TrimSpace(name)) {
+			return a.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("area %q does not exist; create it before importing", name)
+}
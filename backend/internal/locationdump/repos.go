@@ -0,0 +1,40 @@
+package locationdump
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/alexscott64/woulder/backend/internal/database/areas"
+	"github.com/alexscott64/woulder/backend/internal/database/boulders"
+	"github.com/alexscott64/woulder/backend/internal/database/locations"
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/database/rocks"
+)
+
+// DBConn is satisfied by both *sql.DB and *sql.Tx, so Export can read from a
+// plain connection and Import can write inside a transaction.
+type DBConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Repos are the repositories a dump is read from and written to.
+type Repos struct {
+	Locations       locations.Repository
+	Areas           areas.Repository
+	MountainProject mountainproject.Repository
+	Boulders        boulders.Repository
+	Rocks           rocks.Repository
+}
+
+// NewPostgresRepos creates Repos backed by db.
+func NewPostgresRepos(db DBConn) Repos {
+	return Repos{
+		Locations:       locations.NewPostgresRepository(db),
+		Areas:           areas.NewPostgresRepository(db),
+		MountainProject: mountainproject.NewPostgresRepository(db),
+		Boulders:        boulders.NewPostgresRepository(db),
+		Rocks:           rocks.NewPostgresRepository(db),
+	}
+}
@@ -57,17 +57,22 @@ type AuthResponse struct {
 }
 
 type AccessClaims struct {
-	UserID    string
-	Email     string
-	Role      string
-	IssuedAt  time.Time
-	ExpiresAt time.Time
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 func (u User) Current() CurrentUser {
 	return CurrentUser{ID: u.ID, Email: u.Email, DisplayName: u.DisplayName, Role: u.Role}
 }
 
+// CanRunMaintenance reports whether role may trigger admin maintenance tasks.
+func CanRunMaintenance(role string) bool {
+	return role == RoleAdmin
+}
+
 func CanWriteMoney(role string) bool {
 	return role == RoleAdmin || role == RoleDeveloper
 }
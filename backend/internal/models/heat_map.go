@@ -37,6 +37,8 @@ type AreaActivityDetail struct {
 	RecentComments   []CommentSummary  `json:"recent_comments"`
 	ActivityTimeline []DailyActivity   `json:"activity_timeline"`
 	TopRoutes        []TopRouteSummary `json:"top_routes"`
+	ConditionsBeta   []string          `json:"conditions_beta,omitempty"` // Tags extracted from area comments (seeps, morning_sun, ...)
+	LastSyncedAt     *time.Time        `json:"last_synced_at,omitempty"`  // When the area was last synced from MP (nil if never)
 }
 
 // TickDetail represents a single tick for area detail views
@@ -72,8 +74,8 @@ type RouteActivity struct {
 	MPRouteID    int64     `json:"mp_route_id"`
 	Name         string    `json:"name"`
 	Rating       string    `json:"rating"`
-	Latitude     *float64  `json:"latitude"`
-	Longitude    *float64  `json:"longitude"`
+	Latitude     *float64  `json:"latitude,omitempty"`
+	Longitude    *float64  `json:"longitude,omitempty"`
 	TickCount    int       `json:"tick_count"`
 	LastActivity time.Time `json:"last_activity"`
 	MPAreaID     int64     `json:"mp_area_id"`
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"encoding/json"
+	"flag"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden JSON files in testdata/")
+
+var snakeCaseTag = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// TestModelJSONTags audits every struct in this package: exported fields must
+// carry a snake_case json tag, and pointer fields must be omitempty so that
+// optional values are omitted rather than serialized as null.
+func TestModelJSONTags(t *testing.T) {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				// Embedded structs are flattened into the parent object.
+				if len(field.Names) == 0 {
+					continue
+				}
+				for _, name := range field.Names {
+					if !name.IsExported() {
+						continue
+					}
+					checkFieldTag(t, fset.Position(name.Pos()).String(), spec.Name.Name+"."+name.Name, field)
+				}
+			}
+			return true
+		})
+	}
+}
+
+func checkFieldTag(t *testing.T, pos, name string, field *ast.Field) {
+	t.Helper()
+
+	if field.Tag == nil {
+		t.Errorf("%s: %s has no json tag", pos, name)
+		return
+	}
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		t.Errorf("%s: %s has an unparseable tag: %v", pos, name, err)
+		return
+	}
+	tag, ok := reflect.StructTag(raw).Lookup("json")
+	if !ok {
+		t.Errorf("%s: %s has no json tag", pos, name)
+		return
+	}
+	if tag == "-" {
+		return
+	}
+
+	key, opts, _ := strings.Cut(tag, ",")
+	if !snakeCaseTag.MatchString(key) {
+		t.Errorf("%s: %s json key %q is not snake_case", pos, name, key)
+	}
+	if _, isPointer := field.Type.(*ast.StarExpr); isPointer && !hasOption(opts, "omitempty") {
+		t.Errorf("%s: %s is a pointer without omitempty", pos, name)
+	}
+}
+
+func hasOption(opts, want string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == want {
+			return true
+		}
+	}
+	return false
+}
+
+// TestModelJSONGolden pins the wire format of representative API responses.
+// Run `go test ./internal/models -update` after an intentional change.
+func TestModelJSONGolden(t *testing.T) {
+	at := time.Date(2024, 6, 1, 15, 30, 0, 0, time.UTC)
+	lat, lon := 47.6062, -122.3321
+	locationID := 3
+	comment := "Sent it"
+	hoursSinceRain := 18.5
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{
+			name: "route_activity_without_coordinates",
+			value: RouteActivity{
+				MPRouteID: 105717310, Name: "Midnight Lightning", Rating: "V8",
+				TickCount: 12, LastActivity: at, MPAreaID: 105716763, AreaName: "Camp 4",
+			},
+		},
+		{
+			name: "route_activity_with_coordinates",
+			value: RouteActivity{
+				MPRouteID: 105717310, Name: "Midnight Lightning", Rating: "V8",
+				Latitude: &lat, Longitude: &lon,
+				TickCount: 12, LastActivity: at, MPAreaID: 105716763, AreaName: "Camp 4",
+			},
+		},
+		{
+			name: "river_without_optional_fields",
+			value: River{
+				ID: 1, LocationID: 3, GaugeID: "12134500", RiverName: "Skykomish River",
+				SafeCrossingCFS: 800, CautionCrossingCFS: 1200, CreatedAt: at, UpdatedAt: at,
+			},
+		},
+		{
+			name: "trending_route",
+			value: TrendingRoute{
+				MPRouteID: 105717310, Name: "Midnight Lightning", Rating: "V8",
+				MPAreaID: 105716763, AreaName: "Camp 4", LocationID: &locationID,
+				TickCount: 7, LastClimbAt: at,
+			},
+		},
+		{
+			name: "climb_history_entry",
+			value: ClimbHistoryEntry{
+				MPRouteID: 105717310, RouteName: "Midnight Lightning", RouteRating: "V8",
+				MPAreaID: 105716763, AreaName: "Camp 4", ClimbedAt: at, ClimbedBy: "alex",
+				Style: "Send", Comment: &comment, DaysSinceClimb: 2, Source: "mp",
+			},
+		},
+		{
+			name: "location_now",
+			value: LocationNow{
+				LocationID: 3, Name: "Gold Bar", Verdict: "wait", Reason: "Rock still drying",
+				Temperature: 58.5, Precipitation: 0, IsDry: false, HoursUntilDry: 4.5,
+				DryingStatus: "fair", HoursSinceRain: &hoursSinceRain, ClimbabilityScore: 71,
+				IsDaylight: true, WeatherUpdatedAt: at,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(tt.value, "", "  ")
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			got = append(got, '\n')
+
+			path := filepath.Join("testdata", tt.name+".json")
+			if *updateGolden {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("write golden: %v", err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read golden (run with -update to create): %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("JSON mismatch for %s\ngot:\n%s\nwant:\n%s", tt.name, got, want)
+			}
+		})
+	}
+}
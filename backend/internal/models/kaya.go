@@ -144,7 +144,7 @@ type KayaSyncProgress struct {
 	ID                 int        `json:"id" db:"id"`
 	KayaLocationID     string     `json:"kaya_location_id" db:"kaya_location_id"`
 	LocationName       string     `json:"location_name" db:"location_name"`
-	Status             string     `json:"status" db:"status"` // 'pending', 'in_progress', 'completed', 'failed'
+	Status             string     `json:"status" db:"status"` // 'pending', 'in_progress', 'completed', 'failed', 'skipped' (closed)
 	LastSyncAt         *time.Time `json:"last_sync_at,omitempty" db:"last_sync_at"`
 	NextSyncAt         *time.Time `json:"next_sync_at,omitempty" db:"next_sync_at"`
 	SyncError          *string    `json:"sync_error,omitempty" db:"sync_error"`
@@ -19,25 +19,81 @@ type Location struct {
 	Timezone  string    `json:"timezone" db:"timezone"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	// Access is approach/access beta from the matched Kaya destination. Only
+	// populated on the location detail endpoint; never persisted.
+	Access *LocationAccessInfo `json:"access,omitempty" db:"-"`
+	// SyncState is whether Mountain Project routes have been imported for
+	// this location (one of the LocationSyncState constants), so clients can
+	// tell "never synced" from "synced, nothing recent". Set by
+	// LocationService; never persisted.
+	SyncState string `json:"sync_state,omitempty" db:"-"`
+	// LastSyncedAt is the most recent tick sync of any of the location's
+	// routes, nil until one has completed. Set alongside SyncState.
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" db:"-"`
+	// DryStreaks lists upcoming runs of dry days from the cached forecast.
+	// Only populated on the location detail endpoint; never persisted.
+	DryStreaks *DryStreakOutlook `json:"dry_streaks,omitempty" db:"-"`
+}
+
+// Location sync states reported in Location.SyncState.
+const (
+	LocationSyncStateNeverSynced = "never_synced" // No routes imported yet
+	LocationSyncStateSyncing     = "syncing"      // Routes imported, no tick sync completed yet
+	LocationSyncStateSynced      = "synced"       // At least one route's ticks synced
+)
+
+// LocationSyncInfo summarizes the Mountain Project routes linked to a
+// location, from which its sync state is derived.
+type LocationSyncInfo struct {
+	LocationID   int        `json:"location_id" db:"location_id"`
+	RouteCount   int        `json:"route_count" db:"route_count"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty" db:"last_synced_at"` // Latest route last_tick_sync_at; nil if none synced
+}
+
+// LocationAccessInfo is approach and access beta for a location, sourced
+// from Kaya destination descriptions. Text fields are plain text (HTML
+// stripped, whitespace collapsed) and omitted when Kaya has nothing.
+type LocationAccessInfo struct {
+	ShortDescription  *string `json:"short_description,omitempty"` // One-line summary of the area
+	Approach          *string `json:"approach,omitempty"`          // How to get there (parking, trail)
+	AccessNotes       *string `json:"access_notes,omitempty"`      // Access issues, closures, etiquette
+	IsAccessSensitive bool    `json:"is_access_sensitive"`
+	IsClosed          bool    `json:"is_closed"`
+	Source            string  `json:"source"` // Always "kaya" for now
+	KayaLocationSlug  string  `json:"kaya_location_slug"`
+}
+
+// NearbyLocation is a location with its distance from a query point
+type NearbyLocation struct {
+	Location
+	DistanceKm float64 `json:"distance_km"`
 }
 
 // River represents a river crossing associated with a location
 type River struct {
 	ID                    int       `json:"id" db:"id"`
 	LocationID            int       `json:"location_id" db:"location_id"`
-	GaugeID               string    `json:"gauge_id" db:"gauge_id"`                                   // USGS river gauge station ID (may be nearby if no direct gauge)
-	RiverName             string    `json:"river_name" db:"river_name"`                               // Name of the river/creek for crossing
-	SafeCrossingCFS       int       `json:"safe_crossing_cfs" db:"safe_crossing_cfs"`                 // Safe crossing threshold in CFS
-	CautionCrossingCFS    int       `json:"caution_crossing_cfs" db:"caution_crossing_cfs"`           // Caution threshold in CFS
-	DrainageAreaSqMi      *float64  `json:"drainage_area_sq_mi" db:"drainage_area_sq_mi"`             // Drainage area for flow estimation
-	GaugeDrainageAreaSqMi *float64  `json:"gauge_drainage_area_sq_mi" db:"gauge_drainage_area_sq_mi"` // Reference gauge drainage area
-	FlowDivisor           *float64  `json:"flow_divisor" db:"flow_divisor"`                           // Simple divisor for gauge value (e.g., 2.0 means gauge/2)
-	IsEstimated           bool      `json:"is_estimated" db:"is_estimated"`                           // TRUE if flow is estimated
-	Description           *string   `json:"description" db:"description"`                             // Additional notes about the crossing
+	GaugeID               string    `json:"gauge_id" db:"gauge_id"`                                             // USGS river gauge station ID (may be nearby if no direct gauge)
+	RiverName             string    `json:"river_name" db:"river_name"`                                         // Name of the river/creek for crossing
+	SafeCrossingCFS       int       `json:"safe_crossing_cfs" db:"safe_crossing_cfs"`                           // Safe crossing threshold in CFS
+	CautionCrossingCFS    int       `json:"caution_crossing_cfs" db:"caution_crossing_cfs"`                     // Caution threshold in CFS
+	DrainageAreaSqMi      *float64  `json:"drainage_area_sq_mi,omitempty" db:"drainage_area_sq_mi"`             // Drainage area for flow estimation
+	GaugeDrainageAreaSqMi *float64  `json:"gauge_drainage_area_sq_mi,omitempty" db:"gauge_drainage_area_sq_mi"` // Reference gauge drainage area
+	FlowDivisor           *float64  `json:"flow_divisor,omitempty" db:"flow_divisor"`                           // Simple divisor for gauge value (e.g., 2.0 means gauge/2)
+	IsEstimated           bool      `json:"is_estimated" db:"is_estimated"`                                     // TRUE if flow is estimated
+	Description           *string   `json:"description,omitempty" db:"description"`                             // Additional notes about the crossing
 	CreatedAt             time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Precipitation types for WeatherData.PrecipType.
+const (
+	PrecipTypeNone  = "none"  // nothing measurable falling
+	PrecipTypeRain  = "rain"  // mostly liquid
+	PrecipTypeSnow  = "snow"  // mostly frozen
+	PrecipTypeMixed = "mixed" // a real share of both
+)
+
 // WeatherData represents weather information for a location
 type WeatherData struct {
 	ID                 int       `json:"id" db:"id"`
@@ -57,6 +113,11 @@ type WeatherData struct {
 	DirectRadiation    float64   `json:"direct_radiation" db:"direct_radiation"`       // W/m^2 direct beam on horizontal
 	DiffuseRadiation   float64   `json:"diffuse_radiation" db:"diffuse_radiation"`     // W/m^2 diffuse on horizontal
 	DewpointF          float64   `json:"dewpoint_f" db:"dewpoint_f"`                   // Fahrenheit
+	PrecipType         string    `json:"precip_type,omitempty" db:"precip_type"`       // PrecipTypeNone/Rain/Snow/Mixed; empty when unknown
+	Confidence         string    `json:"confidence,omitempty" db:"-"`                  // "high", "medium", "low" by forecast horizon (not persisted)
+	Partial            bool      `json:"partial,omitempty" db:"-"`                     // Some fields were missing upstream and filled (not persisted)
+	IsRaining          bool      `json:"is_raining" db:"-"`                            // Precipitation above threshold with a rain weather code (current only, not persisted)
+	IsSnowing          bool      `json:"is_snowing" db:"-"`                            // Precipitation above threshold with a snow weather code (current only, not persisted)
 	CreatedAt          time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -79,6 +140,50 @@ type WeatherDailyAggregate struct {
 	UpdatedAt          time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// DryStreak is a run of consecutive forecast days that are dry with an
+// acceptable high temperature.
+type DryStreak struct {
+	StartDate          string  `json:"start_date"` // YYYY-MM-DD, local to the location
+	EndDate            string  `json:"end_date"`   // Last day of the streak, inclusive
+	Days               int     `json:"days"`
+	TotalPrecipitation float64 `json:"total_precipitation"` // Inches over the whole streak
+}
+
+// DryStreakOutlook is a location's upcoming dry streaks.
+type DryStreakOutlook struct {
+	Longest         *DryStreak  `json:"longest,omitempty"` // Earliest of the longest streaks; absent if no day qualifies
+	Streaks         []DryStreak `json:"streaks"`           // Every streak, soonest first
+	ForecastThrough string      `json:"forecast_through"`  // Last forecast day considered (YYYY-MM-DD)
+}
+
+// ConditionsHistory pairs a location's daily weather with its daily climbing
+// activity so the two can be compared day by day.
+type ConditionsHistory struct {
+	LocationID int                    `json:"location_id"`
+	Timezone   string                 `json:"timezone"` // Days are local dates in this zone
+	Days       []ConditionsHistoryDay `json:"days"`     // Oldest first, one entry per day
+}
+
+// ConditionsHistoryDay is one local day of weather and tick activity.
+type ConditionsHistoryDay struct {
+	Date       string               `json:"date"`              // YYYY-MM-DD
+	Weather    *DailyWeatherSummary `json:"weather,omitempty"` // Omitted when no weather is stored for the day
+	TickCount  int                  `json:"tick_count"`
+	RouteCount int                  `json:"route_count"` // Distinct routes ticked
+}
+
+// DailyWeatherSummary is the weather for one local day.
+type DailyWeatherSummary struct {
+	MinTemperature     float64 `json:"min_temperature"`
+	MaxTemperature     float64 `json:"max_temperature"`
+	AvgTemperature     float64 `json:"avg_temperature"`
+	TotalPrecipitation float64 `json:"total_precipitation"`
+	AvgHumidity        float64 `json:"avg_humidity"`
+	AvgWindSpeed       float64 `json:"avg_wind_speed"`
+	SnowEstimateInches float64 `json:"snow_estimate_inches"`
+	HourCount          int     `json:"hour_count"` // Hours of data behind the summary
+}
+
 // DailySunTimes represents sunrise/sunset for a single day
 type DailySunTimes struct {
 	Date    string `json:"date"`    // Date in YYYY-MM-DD format
@@ -113,6 +218,7 @@ type WeatherForecast struct {
 	DailySunTimes         []DailySunTimes        `json:"daily_sun_times"`                   // Sunrise/sunset for each forecast day
 	RockDryingStatus      *RockDryingStatus      `json:"rock_drying_status,omitempty"`      // Rock drying status (current day)
 	RockTemperatureStatus *RockTemperatureStatus `json:"rock_temperature_status,omitempty"` // Rock surface temp + friction conditions
+	SunExposureSummary    *SunExposureSummary    `json:"sun_exposure_summary,omitempty"`    // Dominant aspect + shade rating for the location
 	SnowDepthInches       *float64               `json:"snow_depth_inches,omitempty"`       // Current snow depth on ground in inches
 	DailySnowDepth        map[string]float64     `json:"daily_snow_depth,omitempty"`        // Snow depth forecast by date (YYYY-MM-DD)
 	TodayCondition        *ClimbingCondition     `json:"today_condition,omitempty"`         // Today's overall climbing condition
@@ -121,6 +227,57 @@ type WeatherForecast struct {
 	PestConditions        *PestConditions        `json:"pest_conditions,omitempty"`         // Pest activity levels (mosquitoes, outdoor pests)
 	LastClimbedInfo       *LastClimbedInfo       `json:"last_climbed_info,omitempty"`       // DEPRECATED: Most recent climb (use climb_history instead)
 	ClimbHistory          []ClimbHistoryEntry    `json:"climb_history,omitempty"`           // Recent climb history at this location (from Mountain Project)
+	Alerts                []WeatherAlert         `json:"alerts,omitempty"`                  // Active severe-weather alerts covering the location
+}
+
+// WeatherAlert is an active weather warning, watch or advisory covering a
+// location, as issued by the alert provider (currently the US National
+// Weather Service, so locations outside the US never have alerts).
+type WeatherAlert struct {
+	ID          string     `json:"id"`
+	Event       string     `json:"event"`                 // e.g. "Severe Thunderstorm Watch"
+	Severity    string     `json:"severity"`              // "Extreme", "Severe", "Moderate", "Minor", "Unknown"
+	Urgency     string     `json:"urgency"`               // "Immediate", "Expected", "Future", "Past", "Unknown"
+	Headline    string     `json:"headline,omitempty"`    // One-line summary from the issuer
+	Description string     `json:"description,omitempty"` // Full alert text
+	Onset       *time.Time `json:"onset,omitempty"`       // When the hazard begins
+	Expires     *time.Time `json:"expires,omitempty"`     // When the alert stops applying
+	Source      string     `json:"source"`                // Provider, e.g. "nws"
+}
+
+// LocationNow is a compact "is it climbable right now" snapshot for
+// notifications and home-screen widgets. It is derived from the full
+// WeatherForecast and served from cache between weather refreshes.
+type LocationNow struct {
+	LocationID        int            `json:"location_id"`
+	Name              string         `json:"name"`
+	Verdict           string         `json:"verdict"`                    // "go", "wait", "no"
+	Reason            string         `json:"reason,omitempty"`           // Short explanation of a non-"go" verdict
+	Temperature       float64        `json:"temperature"`                // Current air temperature (°F)
+	Precipitation     float64        `json:"precipitation"`              // Current-hour precipitation (inches)
+	IsRaining         bool           `json:"is_raining"`                 // See WeatherData.IsRaining
+	IsSnowing         bool           `json:"is_snowing"`                 // See WeatherData.IsSnowing
+	IsDry             bool           `json:"is_dry"`                     // Rock drying estimate says the rock is dry
+	HoursUntilDry     float64        `json:"hours_until_dry"`            // 0 when dry
+	DryingStatus      string         `json:"drying_status,omitempty"`    // Rock drying status: "critical", "poor", "fair", "good"
+	HoursSinceRain    *float64       `json:"hours_since_rain,omitempty"` // Since the last rainy hour (absent if none in the last week)
+	ClimbabilityScore int            `json:"climbability_score"`         // 0-100 rating of current conditions, for ranking locations
+	IsDaylight        bool           `json:"is_daylight"`                // Sun above the horizon right now
+	Sunrise           string         `json:"sunrise,omitempty"`          // Today's sunrise (ISO 8601)
+	Sunset            string         `json:"sunset,omitempty"`           // Today's sunset (ISO 8601)
+	WeatherUpdatedAt  time.Time      `json:"weather_updated_at"`         // When the underlying weather was fetched
+	Alerts            []WeatherAlert `json:"alerts,omitempty"`           // Active weather alerts (see WeatherForecast.Alerts)
+}
+
+// RankedLocation is a location's place in the current climbability
+// ranking, taken from its cached LocationNow snapshot.
+type RankedLocation struct {
+	LocationID        int       `json:"location_id"`
+	Name              string    `json:"name"`
+	ClimbabilityScore int       `json:"climbability_score"` // See LocationNow.ClimbabilityScore
+	Verdict           string    `json:"verdict"`            // "go", "wait", "no"
+	Reason            string    `json:"reason"`             // Short explanation of the score
+	WeatherUpdatedAt  time.Time `json:"weather_updated_at"`
 }
 
 // RiverData represents river gauge information with current conditions
@@ -154,7 +154,7 @@ type MoneySnapshot struct {
 
 type MoneyCragSnapshot struct {
 	Project MoneyProject    `json:"project"`
-	Root    *MoneyCragNode  `json:"root"`
+	Root    *MoneyCragNode  `json:"root,omitempty"`
 	Trails  []MoneyCragNode `json:"trails"`
 	Notes   []MoneyNote     `json:"notes"`
 	Uploads []MoneyUpload   `json:"uploads"`
@@ -192,7 +192,7 @@ type MoneyFeatureRequest struct {
 	ParentFeatureID *string         `json:"parent_feature_id,omitempty"`
 	FeatureType     string          `json:"feature_type"`
 	Title           string          `json:"title"`
-	Description     *string         `json:"description"`
+	Description     *string         `json:"description,omitempty"`
 	Status          string          `json:"status"`
 	GeoJSON         json.RawMessage `json:"geojson"`
 	Style           json.RawMessage `json:"style"`
@@ -205,7 +205,7 @@ type MoneyFeatureRequest struct {
 type MoneyCragAreaRequest struct {
 	ParentFeatureID *string         `json:"parent_feature_id,omitempty"`
 	Title           string          `json:"title"`
-	Description     *string         `json:"description"`
+	Description     *string         `json:"description,omitempty"`
 	GeoJSON         json.RawMessage `json:"geojson"`
 	Properties      json.RawMessage `json:"properties"`
 }
@@ -226,14 +226,14 @@ type MoneyArchiveFeatureRequest struct {
 }
 
 type MoneyMoveFeatureRequest struct {
-	ParentFeatureID *string `json:"parent_feature_id"`
+	ParentFeatureID *string `json:"parent_feature_id,omitempty"`
 	SortOrder       *int    `json:"sort_order,omitempty"`
 }
 
 type MoneyCragBoulderRequest struct {
 	ParentFeatureID string          `json:"parent_feature_id"`
 	Title           string          `json:"title"`
-	Description     *string         `json:"description"`
+	Description     *string         `json:"description,omitempty"`
 	DevStatus       string          `json:"dev_status"`
 	GeoJSON         json.RawMessage `json:"geojson"`
 	Properties      json.RawMessage `json:"properties"`
@@ -247,7 +247,7 @@ type MoneyCragProblemRequest struct {
 	Stars       int             `json:"stars"`
 	FA          *string         `json:"fa,omitempty"`
 	Types       []string        `json:"types"`
-	Description *string         `json:"description"`
+	Description *string         `json:"description,omitempty"`
 	Properties  json.RawMessage `json:"properties"`
 }
 
@@ -265,21 +265,21 @@ type MoneyNoteRequest struct {
 }
 
 type MoneyUploadMetadataRequest struct {
-	Title    *string `json:"title"`
-	Comments *string `json:"comments"`
+	Title    *string `json:"title,omitempty"`
+	Comments *string `json:"comments,omitempty"`
 }
 
 type BBox struct {
-	MinLon float64
-	MinLat float64
-	MaxLon float64
-	MaxLat float64
+	MinLon float64 `json:"min_lon"`
+	MinLat float64 `json:"min_lat"`
+	MaxLon float64 `json:"max_lon"`
+	MaxLat float64 `json:"max_lat"`
 }
 
 type MoneyFeatureFilter struct {
-	FeatureType     string
-	Status          string
-	BBox            *BBox
-	UpdatedAfter    *time.Time
-	IncludeArchived bool
+	FeatureType     string     `json:"feature_type"`
+	Status          string     `json:"status"`
+	BBox            *BBox      `json:"bbox,omitempty"`
+	UpdatedAfter    *time.Time `json:"updated_after,omitempty"`
+	IncludeArchived bool       `json:"include_archived"`
 }
@@ -28,7 +28,8 @@ type MPRoute struct {
 	LocationID      *int      `json:"location_id,omitempty" db:"location_id"`
 	Latitude        *float64  `json:"latitude,omitempty" db:"latitude"`
 	Longitude       *float64  `json:"longitude,omitempty" db:"longitude"`
-	Aspect          *string   `json:"aspect,omitempty" db:"aspect"` // N, NE, E, SE, S, SW, W, NW
+	Aspect          *string   `json:"aspect,omitempty" db:"aspect"`     // N, NE, E, SE, S, SW, W, NW
+	AspectSource    string    `json:"aspect_source" db:"aspect_source"` // AspectSourceComputed or AspectSourceComment
 	Difficulty      *string   `json:"difficulty,omitempty" db:"difficulty"`
 	Pitches         *int      `json:"pitches,omitempty" db:"pitches"`
 	HeightFeet      *int      `json:"height_feet,omitempty" db:"height_feet"`
@@ -42,6 +43,12 @@ type MPRoute struct {
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Where an MPRoute's aspect came from.
+const (
+	AspectSourceComputed = "computed" // Bearing from the area center
+	AspectSourceComment  = "comment"  // Explicit sun beta in route comments
+)
+
 // MPTick represents a single climb log (tick) from a Mountain Project user
 type MPTick struct {
 	ID        int       `json:"id" db:"id"`
@@ -54,6 +61,22 @@ type MPTick struct {
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// MPComment is a Mountain Project comment on an area or a route.
+// CommentType is "area" or "route"; exactly one of MPAreaID and MPRouteID is set.
+type MPComment struct {
+	ID          int       `json:"id" db:"id"`
+	MPCommentID int64     `json:"mp_comment_id" db:"mp_comment_id"`
+	CommentType string    `json:"comment_type" db:"comment_type"`
+	MPAreaID    *int64    `json:"mp_area_id,omitempty" db:"mp_area_id"`
+	MPRouteID   *int64    `json:"mp_route_id,omitempty" db:"mp_route_id"`
+	UserName    string    `json:"user_name" db:"user_name"`
+	UserID      *string   `json:"user_id,omitempty" db:"user_id"`
+	CommentText string    `json:"comment_text" db:"comment_text"`
+	CommentedAt time.Time `json:"commented_at" db:"commented_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
 // BoulderDryingProfile stores boulder-specific drying metadata
 type BoulderDryingProfile struct {
 	ID                    int        `json:"id" db:"id"`
@@ -121,19 +144,59 @@ type AreaActivitySummary struct {
 	HasSubareas    bool             `json:"has_subareas"`                // Whether this area has child subareas
 	SubareaCount   int              `json:"subarea_count"`               // Number of direct child subareas
 	DryingStats    *AreaDryingStats `json:"drying_stats,omitempty"`      // Aggregated drying conditions (optional)
+	Closed         bool             `json:"closed"`                      // Every Kaya-matched route in the subtree is closed
+	ClosureNote    *string          `json:"closure_note,omitempty"`      // Kaya access note for the closure
+}
+
+// AreaTreeNode is an area's activity summary with its nested subareas.
+// Activity on each node covers the node's whole subtree.
+type AreaTreeNode struct {
+	AreaActivitySummary
+	Subareas []*AreaTreeNode `json:"subareas"` // Ordered by most recent activity
 }
 
 // RouteActivitySummary represents a boulder with recent activity
 // Used for API responses to show routes ordered by recent climbing activity
 type RouteActivitySummary struct {
-	MPRouteID      int64               `json:"mp_route_id"`                // Mountain Project route ID
-	Name           string              `json:"name"`                       // Route name
-	Rating         string              `json:"rating"`                     // Grade (V4, 5.10a, etc.)
-	MPAreaID       int64               `json:"mp_area_id"`                 // Parent area ID
-	LastClimbAt    time.Time           `json:"last_climb_at"`              // Most recent climb timestamp
-	MostRecentTick *ClimbHistoryEntry  `json:"most_recent_tick,omitempty"` // Latest tick details (null if no ticks)
-	RecentTicks    []ClimbHistoryEntry `json:"recent_ticks,omitempty"`     // Additional recent ticks (optional)
-	DaysSinceClimb int                 `json:"days_since_climb"`           // Days since last climb
+	MPRouteID      int64               `json:"mp_route_id"`                 // Mountain Project route ID
+	Name           string              `json:"name"`                        // Route name
+	Rating         string              `json:"rating"`                      // Grade (V4, 5.10a, etc.)
+	MPAreaID       int64               `json:"mp_area_id"`                  // Parent area ID
+	LastClimbAt    time.Time           `json:"last_climb_at"`               // Most recent climb timestamp
+	MostRecentTick *ClimbHistoryEntry  `json:"most_recent_tick,omitempty"`  // Latest tick details (null if no ticks)
+	RecentTicks    []ClimbHistoryEntry `json:"recent_ticks,omitempty"`      // Additional recent ticks (optional)
+	DaysSinceClimb int                 `json:"days_since_climb"`            // Days since last climb
+	ConditionsBeta []string            `json:"conditions_beta,omitempty"`   // Tags extracted from route comments (seeps, morning_sun, ...)
+	Closed         bool                `json:"closed"`                      // Closed per Kaya (raptor closure, access dispute)
+	ClosureNote    *string             `json:"closure_note,omitempty"`      // Kaya access note for the closure
+	LastTickSyncAt *time.Time          `json:"last_tick_sync_at,omitempty"` // When ticks were last fetched from MP (nil if never)
+}
+
+// TrendingRoute represents a route ranked by tick volume within a recent window
+// Used for API responses listing the most-climbed routes this week/month
+type TrendingRoute struct {
+	MPRouteID   int64     `json:"mp_route_id"`           // Mountain Project route ID
+	Name        string    `json:"name"`                  // Route name
+	Rating      string    `json:"rating"`                // Grade (V4, 5.10a, etc.)
+	MPAreaID    int64     `json:"mp_area_id"`            // Parent area ID
+	AreaName    string    `json:"area_name"`             // Parent area name
+	LocationID  *int      `json:"location_id,omitempty"` // Woulder location (null if unassigned)
+	TickCount   int       `json:"tick_count"`            // Ticks within the window
+	LastClimbAt time.Time `json:"last_climb_at"`         // Most recent tick within the window
+}
+
+// DiscoveredRoute represents a route recently found by the new-route sweep
+// Used for API responses in the "new routes" feed
+type DiscoveredRoute struct {
+	MPRouteID    int64     `json:"mp_route_id"`             // Mountain Project route ID
+	Name         string    `json:"name"`                    // Route name
+	Rating       string    `json:"rating"`                  // Grade (V4, 5.10a, etc.)
+	RouteType    string    `json:"route_type"`              // Boulder, Sport, Trad, etc.
+	MPAreaID     int64     `json:"mp_area_id"`              // Parent area ID
+	AreaName     string    `json:"area_name"`               // Parent area name
+	LocationID   *int      `json:"location_id,omitempty"`   // Woulder location (null if unassigned)
+	LocationName *string   `json:"location_name,omitempty"` // Woulder location name
+	DiscoveredAt time.Time `json:"discovered_at"`           // When the sweep found the route
 }
 
 // SearchResult represents a unified search result that can be either an area or a route
@@ -151,3 +214,68 @@ type SearchResult struct {
 	UniqueRoutes   *int               `json:"unique_routes,omitempty"`    // Unique routes (only for areas)
 	MostRecentTick *ClimbHistoryEntry `json:"most_recent_tick,omitempty"` // Latest tick (only for routes)
 }
+
+// FeaturedRouteCandidate is a route eligible to be the featured route, with
+// the signals used to weight the pick
+type FeaturedRouteCandidate struct {
+	MPRouteID   int64    `json:"mp_route_id"`         // Mountain Project route ID
+	Name        string   `json:"name"`                // Route name
+	Rating      string   `json:"rating"`              // Grade (V4, 5.10a, etc.)
+	RouteType   string   `json:"route_type"`          // Boulder, Sport, Trad, etc.
+	MPAreaID    int64    `json:"mp_area_id"`          // Parent area ID
+	AreaName    string   `json:"area_name"`           // Parent area name
+	LocationID  int      `json:"location_id"`         // Woulder location (route's, else its area's)
+	MPRating    *float64 `json:"mp_rating,omitempty"` // Star rating (0-4)
+	RecentTicks int      `json:"recent_ticks"`        // Ticks within the weighting window
+}
+
+// SimilarRouteCandidate is a route near another one, with the signals used
+// to rank how similar the two are
+type SimilarRouteCandidate struct {
+	MPRouteID  int64    `json:"mp_route_id"`           // Mountain Project route ID
+	Name       string   `json:"name"`                  // Route name
+	Rating     string   `json:"rating"`                // Grade (V4, 5.10a, etc.)
+	RouteType  string   `json:"route_type"`            // Boulder, Sport, Trad, etc.
+	MPAreaID   int64    `json:"mp_area_id"`            // Parent area ID
+	AreaName   string   `json:"area_name"`             // Parent area name
+	LocationID *int     `json:"location_id,omitempty"` // Woulder location (route's, else its area's)
+	MPRating   *float64 `json:"mp_rating,omitempty"`   // Star rating (0-4)
+	DistanceKm *float64 `json:"distance_km,omitempty"` // From the reference route (absent without GPS)
+	TickCount  int      `json:"tick_count"`            // Ticks within the counting window
+}
+
+// DailyTickCount is the number of ticks logged at a location on one local day.
+type DailyTickCount struct {
+	Date       string `json:"date"` // YYYY-MM-DD
+	TickCount  int    `json:"tick_count"`
+	RouteCount int    `json:"route_count"` // Distinct routes ticked
+}
+
+// ActivitySeriesPoint is the number of ticks in one day or week bucket of an
+// activity series. Buckets without ticks are included with a zero count.
+type ActivitySeriesPoint struct {
+	BucketStart string `json:"bucket_start"` // YYYY-MM-DD; weeks start on Monday
+	TickCount   int    `json:"tick_count"`
+}
+
+// GradeDistribution counts a location's or area's routes by grade band.
+// Every band of each grade family with at least one route is listed, in
+// difficulty order, so empty bands show as zero.
+type GradeDistribution struct {
+	RouteType      string           `json:"route_type,omitempty"` // route type filter, if any
+	TotalRoutes    int              `json:"total_routes"`         // including ungraded routes
+	UngradedRoutes int              `json:"ungraded_routes"`
+	Bands          []GradeBandCount `json:"bands"`
+}
+
+// GradeBandCount is the number of routes in one grade band. MinOrder and
+// MaxOrder are grade_order values usable as a heat map grade filter.
+type GradeBandCount struct {
+	Band     string `json:"band"` // e.g. "V3-V5", "5.11"
+	Family   string `json:"family"`
+	MinGrade string `json:"min_grade"`
+	MaxGrade string `json:"max_grade"`
+	MinOrder int    `json:"min_order"`
+	MaxOrder int    `json:"max_order"`
+	Count    int    `json:"count"`
+}
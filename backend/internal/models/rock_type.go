@@ -42,14 +42,26 @@ type LocationSunExposure struct {
 	Description         string  `json:"description,omitempty" db:"description"`
 }
 
+// SunExposureSummary is a location-level rollup of which way the rock faces
+// and how much direct sun it gets, used to judge cold-weather (sunny) vs
+// hot-weather (shady) suitability at a glance.
+type SunExposureSummary struct {
+	DominantAspect        string  `json:"dominant_aspect"`         // "N", "E", "S", "W", or "mixed"
+	DominantAspectPercent float64 `json:"dominant_aspect_percent"` // 0-100 share of the dominant face
+	ShadeScore            int     `json:"shade_score"`             // 0 (full sun) - 100 (full shade)
+	ShadeRating           string  `json:"shade_rating"`            // "sunny", "mixed", "shady"
+	Source                string  `json:"source"`                  // "sun_exposure_profile"
+	Description           string  `json:"description"`             // Human-readable summary
+}
+
 // RainEvent represents a contiguous rain event derived from weather data
 type RainEvent struct {
-	StartTime     time.Time // First precipitation reading
-	EndTime       time.Time // Last precipitation reading
-	TotalRain     float64   // Total rainfall in inches
-	Duration      float64   // Duration in hours
-	MaxHourlyRate float64   // Maximum hourly rate (inches/hour)
-	AvgHourlyRate float64   // Average hourly rate (inches/hour)
+	StartTime     time.Time `json:"start_time"`      // First precipitation reading
+	EndTime       time.Time `json:"end_time"`        // Last precipitation reading
+	TotalRain     float64   `json:"total_rain"`      // Total rainfall in inches
+	Duration      float64   `json:"duration"`        // Duration in hours
+	MaxHourlyRate float64   `json:"max_hourly_rate"` // Maximum hourly rate (inches/hour)
+	AvgHourlyRate float64   `json:"avg_hourly_rate"` // Average hourly rate (inches/hour)
 }
 
 // RockDryingStatus represents the current drying state of rock at a location
@@ -19,6 +19,7 @@ type UnifiedRouteActivitySummary struct {
 	MPRouteID      *int64             `json:"mp_route_id,omitempty"`      // Mountain Project route ID
 	MPAreaID       *int64             `json:"mp_area_id,omitempty"`       // Parent MP area ID
 	MostRecentTick *ClimbHistoryEntry `json:"most_recent_tick,omitempty"` // Latest MP tick details
+	ConditionsBeta []string           `json:"conditions_beta,omitempty"`  // Tags extracted from MP route comments
 
 	// Kaya-specific fields (null for MP)
 	KayaClimbSlug    *string            `json:"kaya_climb_slug,omitempty"`    // Kaya climb slug
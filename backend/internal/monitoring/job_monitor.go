@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -34,6 +35,17 @@ const (
 	StatusCancelled = "cancelled"
 )
 
+// ErrJobNotActive is returned by CancelJob for a job that isn't running or
+// paused.
+var ErrJobNotActive = errors.New("job is not running or paused")
+
+// ErrJobCancelled is the cause of a context from WatchForCancel once its job
+// has been cancelled.
+var ErrJobCancelled = errors.New("job cancelled")
+
+// cancelPollInterval is how often WatchForCancel re-reads a job's status
+const cancelPollInterval = 10 * time.Second
+
 // JobMonitor tracks job execution progress
 type JobMonitor struct {
 	db *sql.DB
@@ -157,13 +169,13 @@ func (m *JobMonitor) UpdateCurrentItem(ctx context.Context, jobID int64, current
 	return m.UpdateMetadata(ctx, jobID, metadata)
 }
 
-// CompleteJob marks job as completed
+// CompleteJob marks job as completed. A cancelled job stays cancelled.
 func (m *JobMonitor) CompleteJob(ctx context.Context, jobID int64) error {
 	query := `
 		UPDATE woulder.job_executions
 		SET status = $1,
 		    completed_at = $2
-		WHERE id = $3
+		WHERE id = $3 AND status != 'cancelled'
 	`
 
 	_, err := m.db.ExecContext(ctx, query, StatusCompleted, time.Now(), jobID)
@@ -174,14 +186,14 @@ func (m *JobMonitor) CompleteJob(ctx context.Context, jobID int64) error {
 	return nil
 }
 
-// FailJob marks job as failed
+// FailJob marks job as failed. A cancelled job stays cancelled.
 func (m *JobMonitor) FailJob(ctx context.Context, jobID int64, errorMsg string) error {
 	query := `
 		UPDATE woulder.job_executions
 		SET status = $1,
 		    completed_at = $2,
 		    error_message = $3
-		WHERE id = $4
+		WHERE id = $4 AND status != 'cancelled'
 	`
 
 	_, err := m.db.ExecContext(ctx, query, StatusFailed, time.Now(), errorMsg, jobID)
@@ -192,6 +204,84 @@ func (m *JobMonitor) FailJob(ctx context.Context, jobID int64, errorMsg string)
 	return nil
 }
 
+// CancelJob marks a running or paused job as cancelled. The job itself
+// stops the next time it re-reads its status (see WatchForCancel).
+func (m *JobMonitor) CancelJob(ctx context.Context, jobID int64) error {
+	query := `
+		UPDATE woulder.job_executions
+		SET status = $1,
+		    completed_at = $2
+		WHERE id = $3 AND status IN ('running', 'paused')
+	`
+
+	result, err := m.db.ExecContext(ctx, query, StatusCancelled, time.Now(), jobID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return ErrJobNotActive
+	}
+	return nil
+}
+
+// IsJobCancelled reports whether a job has been cancelled
+func (m *JobMonitor) IsJobCancelled(ctx context.Context, jobID int64) (bool, error) {
+	query := `
+		SELECT status
+		FROM woulder.job_executions
+		WHERE id = $1
+	`
+
+	var status string
+	if err := m.db.QueryRowContext(ctx, query, jobID).Scan(&status); err != nil {
+		return false, fmt.Errorf("failed to read job status: %w", err)
+	}
+	return status == StatusCancelled, nil
+}
+
+// WatchForCancel returns a copy of ctx that is cancelled, with cause
+// ErrJobCancelled, once the job is cancelled. Long-running syncs pass it to
+// their loops so a cancel from the API stops them at the next ctx.Done()
+// check. The status is re-read every cancelPollInterval until stop is called
+// or ctx is done; failed reads are logged and retried on the next poll.
+func (m *JobMonitor) WatchForCancel(ctx context.Context, jobID int64) (context.Context, context.CancelFunc) {
+	return m.watchForCancel(ctx, jobID, cancelPollInterval)
+}
+
+func (m *JobMonitor) watchForCancel(ctx context.Context, jobID int64, interval time.Duration) (context.Context, context.CancelFunc) {
+	watchCtx, cancel := context.WithCancelCause(ctx)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-watchCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			cancelled, err := m.IsJobCancelled(watchCtx, jobID)
+			if err != nil {
+				if watchCtx.Err() == nil {
+					log.Printf("Warning: failed to check job %d for cancellation: %v", jobID, err)
+				}
+				continue
+			}
+			if cancelled {
+				log.Printf("Job %d was cancelled, stopping", jobID)
+				cancel(ErrJobCancelled)
+				return
+			}
+		}
+	}()
+
+	return watchCtx, func() { cancel(nil) }
+}
+
 // GetActiveJobs returns the most recent running job for each job_name
 func (m *JobMonitor) GetActiveJobs(ctx context.Context) ([]*JobExecution, error) {
 	query := `
@@ -318,6 +408,41 @@ func (m *JobMonitor) GetLatestJobByName(ctx context.Context, jobName string) (*J
 	return scanJobExecution(row)
 }
 
+// GetLatestFinishedByJobType returns the most recently finished (completed or
+// failed) execution for each job type whose completion falls within the last
+// `within` duration. Job types with no finished run in the window are omitted.
+func (m *JobMonitor) GetLatestFinishedByJobType(ctx context.Context, within time.Duration) ([]*JobExecution, error) {
+	query := `
+		SELECT DISTINCT ON (job_type)
+		       id, job_name, job_type, status, total_items, items_processed,
+		       items_succeeded, items_failed, error_message, started_at,
+		       completed_at, updated_at, metadata
+		FROM woulder.job_executions
+		WHERE status IN ($1, $2)
+		  AND completed_at IS NOT NULL
+		  AND completed_at > $3
+		ORDER BY job_type, completed_at DESC
+	`
+
+	cutoff := time.Now().Add(-within)
+	rows, err := m.db.QueryContext(ctx, query, StatusCompleted, StatusFailed, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query finished jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*JobExecution
+	for rows.Next() {
+		job, err := scanJobExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
 // scanJobExecution scans a row into a JobExecution struct
 func scanJobExecution(scanner interface {
 	Scan(dest ...interface{}) error
@@ -562,6 +687,29 @@ func (m *JobMonitor) RecoverInterruptedJobs(ctx context.Context, maxAge time.Dur
 	return jobs, nil
 }
 
+// DeleteOlderThan deletes finished (completed, failed or cancelled) job
+// executions that finished before cutoff and returns how many were deleted.
+// Running and paused jobs are never deleted, however old. Sync summaries of
+// deleted jobs are kept, unlinked from their job.
+func (m *JobMonitor) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM woulder.job_executions
+		WHERE status IN ($1, $2, $3)
+		  AND completed_at < $4
+	`
+
+	result, err := m.db.ExecContext(ctx, query, StatusCompleted, StatusFailed, StatusCancelled, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old jobs: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted jobs: %w", err)
+	}
+	return deleted, nil
+}
+
 // WasJobCompletedRecently checks if a job was successfully completed within the given duration
 // Returns true if the job should be skipped (was completed recently)
 func (m *JobMonitor) WasJobCompletedRecently(ctx context.Context, jobName string, within time.Duration) (bool, error) {
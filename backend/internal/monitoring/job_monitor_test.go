@@ -3,8 +3,10 @@ package monitoring
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 )
@@ -108,3 +110,183 @@ func TestSaveCheckpoint_NoSelectRoundTrip(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %v", err)
 	}
 }
+
+// TestGetLatestFinishedByJobType_FiltersFinishedWithinWindow verifies the
+// query only asks for completed/failed runs newer than the cutoff and scans
+// one row per job type.
+func TestGetLatestFinishedByJobType_FiltersFinishedWithinWindow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	completedAt := time.Now().Add(-time.Hour)
+	cols := []string{
+		"id", "job_name", "job_type", "status", "total_items", "items_processed",
+		"items_succeeded", "items_failed", "error_message", "started_at",
+		"completed_at", "updated_at", "metadata",
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT ON (job_type)")).
+		WithArgs(StatusCompleted, StatusFailed, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow(1, "location_tick_sync", "location_tick_sync", StatusCompleted, 10, 10, 10, 0, nil,
+				completedAt.Add(-time.Minute), completedAt, completedAt, []byte(`{}`)).
+			AddRow(2, "high_priority_comment_sync", "comment_sync", StatusFailed, 5, 2, 1, 1, "boom",
+				completedAt.Add(-time.Minute), completedAt, completedAt, []byte(`{}`)))
+
+	jobs, err := monitor.GetLatestFinishedByJobType(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("GetLatestFinishedByJobType() error = %v", err)
+	}
+
+	if len(jobs) != 2 {
+		t.Fatalf("len(jobs) = %d, want 2", len(jobs))
+	}
+	if jobs[1].JobType != "comment_sync" || jobs[1].ErrorMessage == nil || *jobs[1].ErrorMessage != "boom" {
+		t.Errorf("unexpected second job: %+v", jobs[1])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	// Only running or paused jobs can be cancelled
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = $3 AND status IN ('running', 'paused')")).
+		WithArgs(StatusCancelled, sqlmock.AnyArg(), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE woulder.job_executions")).
+		WithArgs(StatusCancelled, sqlmock.AnyArg(), int64(6)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := monitor.CancelJob(context.Background(), 5); err != nil {
+		t.Errorf("CancelJob(running) error = %v", err)
+	}
+	if err := monitor.CancelJob(context.Background(), 6); !errors.Is(err, ErrJobNotActive) {
+		t.Errorf("CancelJob(finished) error = %v, want ErrJobNotActive", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// TestCompleteJob_KeepsCancelledStatus guards against a sync that finishes
+// after being cancelled overwriting the operator's cancel.
+func TestCompleteJob_KeepsCancelledStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = $3 AND status != 'cancelled'")).
+		WithArgs(StatusCompleted, sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("WHERE id = $4 AND status != 'cancelled'")).
+		WithArgs(StatusFailed, sqlmock.AnyArg(), "boom", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := monitor.CompleteJob(context.Background(), 1); err != nil {
+		t.Errorf("CompleteJob() error = %v", err)
+	}
+	if err := monitor.FailJob(context.Background(), 1, "boom"); err != nil {
+		t.Errorf("FailJob() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWatchForCancel_StopsOnCancelledStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	statusQuery := regexp.QuoteMeta("SELECT status")
+	mock.ExpectQuery(statusQuery).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(StatusRunning))
+	mock.ExpectQuery(statusQuery).WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(StatusCancelled))
+
+	ctx, stop := monitor.watchForCancel(context.Background(), 3, time.Millisecond)
+	defer stop()
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context not cancelled after the job was cancelled")
+	}
+	if cause := context.Cause(ctx); !errors.Is(cause, ErrJobCancelled) {
+		t.Errorf("context.Cause() = %v, want ErrJobCancelled", cause)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWatchForCancel_StopDoesNotReportCancel(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := NewJobMonitor(db).watchForCancel(context.Background(), 3, time.Hour)
+	stop()
+
+	<-ctx.Done()
+	if errors.Is(context.Cause(ctx), ErrJobCancelled) {
+		t.Error("stopping the watch reported the job as cancelled")
+	}
+}
+
+// TestDeleteOlderThan_OnlyFinishedJobs verifies that pruning only targets
+// finished jobs, so a long-running or paused job is never deleted however
+// old it is.
+func TestDeleteOlderThan_OnlyFinishedJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+	cutoff := time.Now().AddDate(0, 0, -90)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM woulder.job_executions")).
+		WithArgs(StatusCompleted, StatusFailed, StatusCancelled, cutoff).
+		WillReturnResult(sqlmock.NewResult(0, 42))
+
+	deleted, err := monitor.DeleteOlderThan(context.Background(), cutoff)
+	if err != nil {
+		t.Fatalf("DeleteOlderThan() error = %v", err)
+	}
+	if deleted != 42 {
+		t.Errorf("DeleteOlderThan() = %d, want 42", deleted)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// SyncSummary is a persisted per-run record of a sync job's throughput.
+// Unlike JobExecution (which tracks live progress and is overwritten as the
+// job runs), summaries are written once when a run finishes so they can be
+// charted over time.
+type SyncSummary struct {
+	ID              int64     `json:"id"`
+	JobExecutionID  *int64    `json:"job_execution_id,omitempty"`
+	JobName         string    `json:"job_name"`
+	JobType         string    `json:"job_type"`
+	Status          string    `json:"status"`
+	StartedAt       time.Time `json:"started_at"`
+	CompletedAt     time.Time `json:"completed_at"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	ItemsProcessed  int       `json:"items_processed"`
+	ItemsSucceeded  int       `json:"items_succeeded"`
+	ItemsFailed     int       `json:"items_failed"`
+	NewTicks        int       `json:"new_ticks"`
+	NewComments     int       `json:"new_comments"`
+	NewRoutes       int       `json:"new_routes"`
+}
+
+// SyncTrendPoint is one day of aggregated sync summaries for a job.
+type SyncTrendPoint struct {
+	Date               string  `json:"date"` // YYYY-MM-DD (UTC)
+	JobName            string  `json:"job_name"`
+	Runs               int     `json:"runs"`
+	FailedRuns         int     `json:"failed_runs"`
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+	ItemsProcessed     int     `json:"items_processed"`
+	ItemsSucceeded     int     `json:"items_succeeded"`
+	ItemsFailed        int     `json:"items_failed"`
+	NewTicks           int     `json:"new_ticks"`
+	NewComments        int     `json:"new_comments"`
+	NewRoutes          int     `json:"new_routes"`
+}
+
+// RecordSyncSummary persists a summary row for a finished sync run.
+// CompletedAt defaults to now and DurationSeconds is derived from the
+// start/completion times when the caller leaves them zero.
+func (m *JobMonitor) RecordSyncSummary(ctx context.Context, summary *SyncSummary) error {
+	if summary.CompletedAt.IsZero() {
+		summary.CompletedAt = time.Now()
+	}
+	if summary.DurationSeconds == 0 && !summary.StartedAt.IsZero() {
+		summary.DurationSeconds = summary.CompletedAt.Sub(summary.StartedAt).Seconds()
+	}
+
+	query := `
+		INSERT INTO woulder.sync_summaries (
+			job_execution_id, job_name, job_type, status, started_at, completed_at,
+			duration_seconds, items_processed, items_succeeded, items_failed,
+			new_ticks, new_comments, new_routes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id
+	`
+
+	err := m.db.QueryRowContext(
+		ctx, query,
+		summary.JobExecutionID, summary.JobName, summary.JobType, summary.Status,
+		summary.StartedAt, summary.CompletedAt, summary.DurationSeconds,
+		summary.ItemsProcessed, summary.ItemsSucceeded, summary.ItemsFailed,
+		summary.NewTicks, summary.NewComments, summary.NewRoutes,
+	).Scan(&summary.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record sync summary: %w", err)
+	}
+
+	return nil
+}
+
+// GetSyncTrends returns per-day aggregates of sync summaries over the last
+// `days` days, oldest first. An empty jobName returns one series per job.
+func (m *JobMonitor) GetSyncTrends(ctx context.Context, jobName string, days int) ([]*SyncTrendPoint, error) {
+	query := `
+		SELECT TO_CHAR(DATE_TRUNC('day', completed_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS day,
+		       job_name,
+		       COUNT(*) AS runs,
+		       COUNT(*) FILTER (WHERE status = $1) AS failed_runs,
+		       COALESCE(AVG(duration_seconds), 0) AS avg_duration_seconds,
+		       COALESCE(SUM(items_processed), 0),
+		       COALESCE(SUM(items_succeeded), 0),
+		       COALESCE(SUM(items_failed), 0),
+		       COALESCE(SUM(new_ticks), 0),
+		       COALESCE(SUM(new_comments), 0),
+		       COALESCE(SUM(new_routes), 0)
+		FROM woulder.sync_summaries
+		WHERE completed_at > $2
+		  AND ($3 = '' OR job_name = $3)
+		GROUP BY day, job_name
+		ORDER BY day ASC, job_name ASC
+	`
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	rows, err := m.db.QueryContext(ctx, query, StatusFailed, cutoff, jobName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync trends: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*SyncTrendPoint
+	for rows.Next() {
+		p := &SyncTrendPoint{}
+		if err := rows.Scan(
+			&p.Date,
+			&p.JobName,
+			&p.Runs,
+			&p.FailedRuns,
+			&p.AvgDurationSeconds,
+			&p.ItemsProcessed,
+			&p.ItemsSucceeded,
+			&p.ItemsFailed,
+			&p.NewTicks,
+			&p.NewComments,
+			&p.NewRoutes,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan sync trend: %w", err)
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
@@ -0,0 +1,101 @@
+package monitoring
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// TestRecordSyncSummary_DerivesDuration verifies that a summary with only a
+// start time gets CompletedAt/DurationSeconds filled in before the insert,
+// and that the returned id is written back onto the summary.
+func TestRecordSyncSummary_DerivesDuration(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	execID := int64(9)
+	summary := &SyncSummary{
+		JobExecutionID: &execID,
+		JobName:        "location_tick_sync",
+		JobType:        "location_tick_sync",
+		Status:         StatusCompleted,
+		StartedAt:      time.Now().Add(-2 * time.Minute),
+		ItemsProcessed: 10,
+		ItemsSucceeded: 9,
+		ItemsFailed:    1,
+		NewTicks:       42,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO woulder.sync_summaries")).
+		WithArgs(
+			&execID, "location_tick_sync", "location_tick_sync", StatusCompleted,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			10, 9, 1, 42, 0, 0,
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(5)))
+
+	if err := monitor.RecordSyncSummary(context.Background(), summary); err != nil {
+		t.Fatalf("RecordSyncSummary() error = %v", err)
+	}
+
+	if summary.ID != 5 {
+		t.Errorf("ID = %d, want 5", summary.ID)
+	}
+	if summary.CompletedAt.IsZero() {
+		t.Error("CompletedAt was not defaulted")
+	}
+	if summary.DurationSeconds < 119 {
+		t.Errorf("DurationSeconds = %v, want ~120", summary.DurationSeconds)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+// TestGetSyncTrends_ScansRows verifies the aggregate query is filtered by
+// job name and each grouped row is scanned into a SyncTrendPoint.
+func TestGetSyncTrends_ScansRows(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	monitor := NewJobMonitor(db)
+
+	cols := []string{
+		"day", "job_name", "runs", "failed_runs", "avg_duration_seconds",
+		"items_processed", "items_succeeded", "items_failed",
+		"new_ticks", "new_comments", "new_routes",
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("FROM woulder.sync_summaries")).
+		WithArgs(StatusFailed, sqlmock.AnyArg(), "tick_sync").
+		WillReturnRows(sqlmock.NewRows(cols).
+			AddRow("2026-10-01", "tick_sync", 2, 1, 30.5, 100, 95, 5, 12, 0, 0).
+			AddRow("2026-10-02", "tick_sync", 1, 0, 12.0, 40, 40, 0, 3, 0, 0))
+
+	points, err := monitor.GetSyncTrends(context.Background(), "tick_sync", 7)
+	if err != nil {
+		t.Fatalf("GetSyncTrends() error = %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("len(points) = %d, want 2", len(points))
+	}
+	if points[0].Date != "2026-10-01" || points[0].FailedRuns != 1 || points[0].NewTicks != 12 {
+		t.Errorf("unexpected first point: %+v", points[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package mountainproject
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RequestBudget is a token bucket bounding the aggregate Mountain Project
+// request rate across every sync that shares it. Client's own rateLimit only
+// spaces the requests of one Client, and the sync loops pace themselves per
+// method, so back-to-back or concurrent syncs need one budget between them.
+//
+// A nil *RequestBudget is unlimited, so callers can pass one through without
+// checking whether a rate was configured.
+type RequestBudget struct {
+	mu     sync.Mutex
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64
+	last   time.Time
+
+	// now and sleep are swapped out by tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewRequestBudget returns a budget allowing requestsPerSecond on average,
+// with bursts of up to one second's worth of requests (at least one). It
+// returns nil, meaning unlimited, when requestsPerSecond is not positive.
+func NewRequestBudget(requestsPerSecond float64) *RequestBudget {
+	if requestsPerSecond <= 0 || math.IsNaN(requestsPerSecond) || math.IsInf(requestsPerSecond, 0) {
+		return nil
+	}
+	burst := math.Max(1, math.Floor(requestsPerSecond))
+	return &RequestBudget{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+		now:    time.Now,
+		sleep:  time.Sleep,
+	}
+}
+
+// Rate returns the configured average requests per second, or 0 for an
+// unlimited (nil) budget.
+func (b *RequestBudget) Rate() float64 {
+	if b == nil {
+		return 0
+	}
+	return b.rate
+}
+
+// Wait blocks until a request may be sent. It is safe for concurrent use:
+// each caller reserves its token under the lock and sleeps outside it, so
+// waiters are served in arrival order and the lock is never held while
+// sleeping.
+func (b *RequestBudget) Wait() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	now := b.now()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay > 0 {
+		b.sleep(delay)
+	}
+}
@@ -0,0 +1,117 @@
+package mountainproject
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBudgetClock advances only when the budget sleeps, so tests can read
+// exactly how long each Wait blocked.
+type fakeBudgetClock struct {
+	now   time.Time
+	slept []time.Duration
+	mu    sync.Mutex
+}
+
+func (c *fakeBudgetClock) install(b *RequestBudget) {
+	b.last = c.now
+	b.now = func() time.Time { return c.now }
+	b.sleep = func(d time.Duration) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.slept = append(c.slept, d)
+		c.now = c.now.Add(d)
+	}
+}
+
+func TestNewRequestBudget_DisabledRates(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		if b := NewRequestBudget(rate); b != nil {
+			t.Errorf("NewRequestBudget(%v) = %+v, want nil", rate, b)
+		}
+	}
+
+	var b *RequestBudget
+	b.Wait() // a nil budget must not block or panic
+	if b.Rate() != 0 {
+		t.Errorf("nil budget Rate() = %v, want 0", b.Rate())
+	}
+}
+
+func TestRequestBudget_BurstThenSteadyRate(t *testing.T) {
+	b := NewRequestBudget(4)
+	clock := &fakeBudgetClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	clock.install(b)
+
+	// A full bucket allows one second's worth of requests without waiting.
+	for i := 0; i < 4; i++ {
+		b.Wait()
+	}
+	if len(clock.slept) != 0 {
+		t.Fatalf("burst slept %v, want no waits", clock.slept)
+	}
+
+	// After that, requests are spaced at 1/rate.
+	b.Wait()
+	b.Wait()
+	want := []time.Duration{250 * time.Millisecond, 250 * time.Millisecond}
+	if len(clock.slept) != len(want) {
+		t.Fatalf("slept %v, want %v", clock.slept, want)
+	}
+	for i, d := range want {
+		if clock.slept[i] != d {
+			t.Errorf("wait %d slept %v, want %v", i, clock.slept[i], d)
+		}
+	}
+}
+
+func TestRequestBudget_RefillsWhileIdle(t *testing.T) {
+	b := NewRequestBudget(2)
+	clock := &fakeBudgetClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	clock.install(b)
+
+	b.Wait()
+	b.Wait()
+	clock.now = clock.now.Add(time.Hour) // refill is capped at the burst size
+	b.Wait()
+	b.Wait()
+	b.Wait()
+
+	if len(clock.slept) != 1 || clock.slept[0] != 500*time.Millisecond {
+		t.Errorf("slept %v, want a single 500ms wait after the refilled burst", clock.slept)
+	}
+}
+
+func TestRequestBudget_SharedAcrossGoroutines(t *testing.T) {
+	b := NewRequestBudget(1)
+	clock := &fakeBudgetClock{now: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	// Sleeps are recorded but the clock stays put, so each waiter's delay
+	// reflects only the tokens reserved ahead of it.
+	b.last = clock.now
+	b.now = func() time.Time { return clock.now }
+	b.sleep = func(d time.Duration) {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		clock.slept = append(clock.slept, d)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.Wait()
+		}()
+	}
+	wg.Wait()
+
+	// One request uses the burst; the other three queue up 1s, 2s and 3s out.
+	var total time.Duration
+	for _, d := range clock.slept {
+		total += d
+	}
+	if len(clock.slept) != 3 || total != 6*time.Second {
+		t.Errorf("slept %v, want three waits totalling 6s", clock.slept)
+	}
+}
@@ -1,21 +1,38 @@
 package mountainproject
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
+	"sync"
 	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/geo"
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 )
 
+// baseURL is a var (not a const) so tests can point the client at an
+// httptest server. It is not modified at runtime in production code paths.
+var baseURL = "https://www.mountainproject.com/api/v2"
+
 const (
-	baseURL        = "https://www.mountainproject.com/api/v2"
 	rateLimitDelay = 500 * time.Millisecond // 500ms between requests to be respectful
+
+	// commentFetchConcurrency bounds in-flight requests in the batched
+	// comment fetches. Start times are still spaced by rateLimit, so this
+	// only overlaps response latency and does not raise the request rate.
+	commentFetchConcurrency = 4
 )
 
 // Client handles communication with the Mountain Project API
 type Client struct {
-	httpClient      *http.Client
+	httpClient *http.Client
+
+	// rateMu guards lastRequestTime. A Client is shared by concurrent sync
+	// workers, so the delay is enforced across all of them rather than per
+	// goroutine.
+	rateMu          sync.Mutex
 	lastRequestTime time.Time
 }
 
@@ -28,8 +45,13 @@ func NewClient() *Client {
 	}
 }
 
-// rateLimit ensures we don't exceed rate limits by waiting if needed
+// rateLimit ensures we don't exceed rate limits by waiting if needed. It is
+// safe for concurrent use: callers are serialized so requests stay at least
+// rateLimitDelay apart no matter how many goroutines share the client.
 func (c *Client) rateLimit() {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+
 	elapsed := time.Since(c.lastRequestTime)
 	if elapsed < rateLimitDelay {
 		time.Sleep(rateLimitDelay - elapsed)
@@ -47,6 +69,25 @@ type AreaResponse struct {
 	RouteTypeCounts *RouteTypeCounts `json:"route_type_counts"` // Route statistics for this area and descendants
 }
 
+// LatLon returns the area's GPS position. ok is false when MP sent no usable
+// coordinates (missing, not a [longitude, latitude] pair, out of range, or at
+// null island).
+func (a *AreaResponse) LatLon() (lat, lon float64, ok bool) {
+	return parseCoordinates(a.Coordinates)
+}
+
+// parseCoordinates validates an MP [longitude, latitude] pair.
+func parseCoordinates(coords []float64) (lat, lon float64, ok bool) {
+	if len(coords) != 2 {
+		return 0, 0, false
+	}
+	lon, lat = coords[0], coords[1]
+	if !geo.IsPlausibleCoord(lat, lon) {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
 // RouteTypeCounts represents route statistics from the Mountain Project API
 type RouteTypeCounts struct {
 	Aid     int `json:"aid"`
@@ -100,11 +141,23 @@ func (r *RouteResponse) GetSectionByTitle(title string) string {
 	return ""
 }
 
-// TickResponse represents the response from the Mountain Project ticks endpoint
-type TickResponse struct {
-	Data []Tick `json:"data"`
+// pagedResponse is the paginated (Laravel-style) envelope used by the tick
+// list endpoints: CurrentPage echoes the requested page and NextPageURL is
+// null on the last page.
+type pagedResponse[T any] struct {
+	Data        []T     `json:"data"`
+	CurrentPage int     `json:"current_page,omitempty"`  // Page actually served
+	NextPageURL *string `json:"next_page_url,omitempty"` // Null on the last page
 }
 
+// TickResponse represents the response from the Mountain Project route ticks
+// endpoint.
+type TickResponse = pagedResponse[Tick]
+
+// UserTickResponse represents the response from the Mountain Project user
+// ticks endpoint.
+type UserTickResponse = pagedResponse[UserTick]
+
 // Tick represents a single climb log entry
 type Tick struct {
 	Date    string          `json:"date"`    // "Jan 2, 2006, 3:04 pm"
@@ -114,6 +167,13 @@ type Tick struct {
 	User    json.RawMessage `json:"user"`    // User who logged the tick (can be object or false)
 }
 
+// UserTick is an entry from a user's public tick list. Unlike a route tick
+// it names the route it was logged on.
+type UserTick struct {
+	Tick
+	RouteID int64 `json:"route_id"`
+}
+
 // TickUser represents the user who logged a tick
 type TickUser struct {
 	ID   int    `json:"id"`
@@ -142,6 +202,15 @@ func (t *Tick) GetUserName() string {
 	return ""
 }
 
+// GetUser extracts the user from the user field. ok is false when the field
+// is not an object (e.g. false for anonymous/deleted users).
+func (t *Tick) GetUser() (user TickUser, ok bool) {
+	if err := json.Unmarshal(t.User, &user); err != nil {
+		return TickUser{}, false
+	}
+	return user, true
+}
+
 // CommentResponse represents the response from the Mountain Project comments endpoint
 type CommentResponse struct {
 	Data []Comment `json:"data"`
@@ -169,201 +238,202 @@ func (c *Comment) GetUserInfo() string {
 	return "Anonymous"
 }
 
-// GetArea fetches area data including children (subareas and routes)
-func (c *Client) GetArea(areaID string) (*AreaResponse, error) {
-	c.rateLimit()
+// userAgent identifies Woulder to the Mountain Project API.
+const userAgent = "Woulder/1.0 (https://woulder.com)"
 
-	url := fmt.Sprintf("%s/areas/%s", baseURL, areaID)
+// get performs a rate-limited GET against the MP API and returns the body of
+// a successful response. Non-200 responses surface as *httpx.StatusError.
+func (c *Client) get(url string) ([]byte, error) {
+	c.rateLimit()
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("User-Agent", userAgent)
 
-	req.Header.Set("User-Agent", "Woulder/1.0 (https://woulder.com)")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch area %s: %w", areaID, err)
-	}
-	defer resp.Body.Close()
+	return httpx.Do(c.httpClient, req)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d for area %s: %s", resp.StatusCode, areaID, string(body))
-	}
+// getJSON performs a rate-limited GET against the MP API and decodes the
+// JSON response into T.
+func getJSON[T any](c *Client, url string) (T, error) {
+	c.rateLimit()
+	return httpx.GetJSON[T](context.Background(), c.httpClient, url, http.Header{"User-Agent": {userAgent}})
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GetArea fetches area data including children (subareas and routes)
+func (c *Client) GetArea(areaID string) (*AreaResponse, error) {
+	areaResp, err := getJSON[AreaResponse](c, fmt.Sprintf("%s/areas/%s", baseURL, areaID))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var areaResp AreaResponse
-	if err := json.Unmarshal(body, &areaResp); err != nil {
-		return nil, fmt.Errorf("failed to parse area response for %s: %w", areaID, err)
+		return nil, fmt.Errorf("failed to fetch area %s: %w", areaID, err)
 	}
-
 	return &areaResp, nil
 }
 
 // GetRoute fetches detailed route information including description, sections, and ratings
 func (c *Client) GetRoute(routeID string) (*RouteResponse, error) {
-	c.rateLimit()
-
-	url := fmt.Sprintf("%s/routes/%s", baseURL, routeID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Woulder/1.0 (https://woulder.com)")
-
-	resp, err := c.httpClient.Do(req)
+	routeResp, err := getJSON[RouteResponse](c, fmt.Sprintf("%s/routes/%s", baseURL, routeID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch route %s: %w", routeID, err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d for route %s: %s", resp.StatusCode, routeID, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var routeResp RouteResponse
-	if err := json.Unmarshal(body, &routeResp); err != nil {
-		return nil, fmt.Errorf("failed to parse route response for %s: %w", routeID, err)
-	}
-
 	return &routeResp, nil
 }
 
 // GetRouteTicks fetches tick data (climb logs) for a specific route
 func (c *Client) GetRouteTicks(routeID string) ([]Tick, error) {
-	c.rateLimit()
-
-	url := fmt.Sprintf("%s/routes/%s/ticks", baseURL, routeID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Woulder/1.0 (https://woulder.com)")
-
-	resp, err := c.httpClient.Do(req)
+	tickResp, err := getJSON[TickResponse](c, fmt.Sprintf("%s/routes/%s/ticks", baseURL, routeID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch ticks for route %s: %w", routeID, err)
 	}
-	defer resp.Body.Close()
+	return tickResp.Data, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d for route %s: %s", resp.StatusCode, routeID, string(body))
+// tickPageSize is the per_page value requested by GetRouteTicksPaged.
+const tickPageSize = 100
+
+// GetRouteTicksPaged fetches ticks for a route one page at a time, newest
+// first, passing each page to visit. Fetching stops as soon as visit returns
+// false, a page comes back empty, or the response has no next page. This lets
+// incremental syncs stop downloading once they reach ticks they already have.
+//
+// The ticks endpoint returns newest first and honours page/per_page, echoing
+// the served page in current_page. If a later page reports a different
+// current_page than requested, the paging parameters were ignored and the
+// first response was already the full list, so fetching stops without
+// visiting it again.
+func (c *Client) GetRouteTicksPaged(routeID string, visit func(page []Tick) bool) error {
+	err := getPages(c, func(page int) string {
+		return fmt.Sprintf("%s/routes/%s/ticks?per_page=%d&page=%d", baseURL, routeID, tickPageSize, page)
+	}, visit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch ticks for route %s: %w", routeID, err)
 	}
+	return nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// GetUserTicksPaged fetches a user's public tick list one page at a time,
+// newest first, passing each page to visit. MP addresses users by numeric ID.
+// Paging stops under the same rules as GetRouteTicksPaged.
+func (c *Client) GetUserTicksPaged(userID string, visit func(page []UserTick) bool) error {
+	err := getPages(c, func(page int) string {
+		return fmt.Sprintf("%s/users/%s/ticks?per_page=%d&page=%d", baseURL, userID, tickPageSize, page)
+	}, visit)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to fetch ticks for user %s: %w", userID, err)
 	}
+	return nil
+}
 
-	var tickResp TickResponse
-	if err := json.Unmarshal(body, &tickResp); err != nil {
-		return nil, fmt.Errorf("failed to parse tick response for route %s: %w", routeID, err)
-	}
+// getPages requests pageURL(1), pageURL(2), ... and passes each page's data
+// to visit until visit returns false, a page is empty, there is no next page,
+// or the endpoint turns out to ignore paging (see GetRouteTicksPaged).
+func getPages[T any](c *Client, pageURL func(page int) string, visit func(page []T) bool) error {
+	for page := 1; ; page++ {
+		resp, err := getJSON[pagedResponse[T]](c, pageURL(page))
+		if err != nil {
+			return fmt.Errorf("page %d: %w", page, err)
+		}
 
-	return tickResp.Data, nil
+		// A later page echoing an earlier current_page means paging was
+		// ignored and this is the list we already visited
+		if page > 1 && resp.CurrentPage != 0 && resp.CurrentPage != page {
+			return nil
+		}
+		if len(resp.Data) == 0 || !visit(resp.Data) {
+			return nil
+		}
+		if resp.NextPageURL == nil || *resp.NextPageURL == "" {
+			return nil
+		}
+	}
 }
 
 // GetAreaComments fetches all comments for a specific area
 func (c *Client) GetAreaComments(areaID string) ([]Comment, error) {
-	c.rateLimit()
-
-	url := fmt.Sprintf("%s/areas/%s/comments", baseURL, areaID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Woulder/1.0 (https://woulder.com)")
-
-	resp, err := c.httpClient.Do(req)
+	body, err := c.get(fmt.Sprintf("%s/areas/%s/comments", baseURL, areaID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch comments for area %s: %w", areaID, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d for area %s: %s", resp.StatusCode, areaID, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	comments, err := parseComments(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// Try parsing as direct array first (API v2 format)
-	var comments []Comment
-	if err := json.Unmarshal(body, &comments); err == nil {
-		return comments, nil
-	}
-
-	// Fall back to wrapped response format
-	var commentResp CommentResponse
-	if err := json.Unmarshal(body, &commentResp); err != nil {
 		return nil, fmt.Errorf("failed to parse comment response for area %s: %w", areaID, err)
 	}
-
-	return commentResp.Data, nil
+	return comments, nil
 }
 
 // GetRouteComments fetches all comments for a specific route
 func (c *Client) GetRouteComments(routeID string) ([]Comment, error) {
-	c.rateLimit()
-
-	url := fmt.Sprintf("%s/routes/%s/comments", baseURL, routeID)
-
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("User-Agent", "Woulder/1.0 (https://woulder.com)")
-
-	resp, err := c.httpClient.Do(req)
+	body, err := c.get(fmt.Sprintf("%s/routes/%s/comments", baseURL, routeID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch comments for route %s: %w", routeID, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code %d for route %s: %s", resp.StatusCode, routeID, string(body))
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	comments, err := parseComments(body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to parse comment response for route %s: %w", routeID, err)
 	}
+	return comments, nil
+}
 
-	// Try parsing as direct array first (API v2 format)
+// parseComments accepts both the bare array returned by API v2 and the older
+// {"data": [...]} wrapper.
+func parseComments(body []byte) ([]Comment, error) {
 	var comments []Comment
 	if err := json.Unmarshal(body, &comments); err == nil {
 		return comments, nil
 	}
 
-	// Fall back to wrapped response format
 	var commentResp CommentResponse
 	if err := json.Unmarshal(body, &commentResp); err != nil {
-		return nil, fmt.Errorf("failed to parse comment response for route %s: %w", routeID, err)
+		return nil, err
 	}
-
 	return commentResp.Data, nil
 }
+
+// CommentsResult is the outcome of fetching comments for one ID in a batch.
+type CommentsResult struct {
+	ID       string
+	Comments []Comment
+	Err      error
+}
+
+// GetAreaCommentsBatch fetches comments for several areas. Results are in
+// the order of areaIDs; a failure for one area is reported in its result and
+// does not affect the others.
+func (c *Client) GetAreaCommentsBatch(areaIDs []string) []CommentsResult {
+	return c.fetchCommentsBatch(areaIDs, c.GetAreaComments)
+}
+
+// GetRouteCommentsBatch fetches comments for several routes. Results are in
+// the order of routeIDs; a failure for one route is reported in its result
+// and does not affect the others.
+func (c *Client) GetRouteCommentsBatch(routeIDs []string) []CommentsResult {
+	return c.fetchCommentsBatch(routeIDs, c.GetRouteComments)
+}
+
+// fetchCommentsBatch runs fetch for each ID with up to
+// commentFetchConcurrency requests in flight. The MP API has no multi-ID
+// comments endpoint, so a batch is still one request per ID; running them
+// concurrently saves the response latency that would otherwise sit between
+// rate-limited requests.
+func (c *Client) fetchCommentsBatch(ids []string, fetch func(id string) ([]Comment, error)) []CommentsResult {
+	results := make([]CommentsResult, len(ids))
+	sem := make(chan struct{}, commentFetchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			comments, err := fetch(id)
+			results[i] = CommentsResult{ID: id, Comments: comments, Err: err}
+		}(i, id)
+	}
+
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,293 @@
+package mountainproject
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tickPagesServer serves pages of ticks for /routes/1/ticks using the
+// paginated envelope. When ignorePaging is set it behaves like an endpoint
+// that ignores page/per_page and always returns the full list.
+func tickPagesServer(t *testing.T, pages [][]Tick, ignorePaging bool, requested *[]int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/routes/1/ticks" {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		*requested = append(*requested, page)
+
+		resp := map[string]interface{}{}
+		switch {
+		case ignorePaging:
+			var all []Tick
+			for _, p := range pages {
+				all = append(all, p...)
+			}
+			resp["data"] = all
+			resp["current_page"] = 1
+			resp["next_page_url"] = "http://example.invalid/next"
+		case page >= 1 && page <= len(pages):
+			resp["data"] = pages[page-1]
+			resp["current_page"] = page
+			if page < len(pages) {
+				resp["next_page_url"] = fmt.Sprintf("http://example.invalid/ticks?page=%d", page+1)
+			} else {
+				resp["next_page_url"] = nil
+			}
+		default:
+			resp["data"] = []Tick{}
+			resp["current_page"] = page
+			resp["next_page_url"] = nil
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func setBaseURLForTest(t *testing.T, url string) {
+	t.Helper()
+	original := baseURL
+	baseURL = url
+	t.Cleanup(func() { baseURL = original })
+}
+
+func testTickPages() [][]Tick {
+	return [][]Tick{
+		{{Date: "Mar 3, 2026, 1:00 pm"}, {Date: "Mar 2, 2026, 1:00 pm"}},
+		{{Date: "Mar 1, 2026, 1:00 pm"}},
+		{{Date: "Feb 1, 2026, 1:00 pm"}},
+	}
+}
+
+func TestGetRouteTicksPaged_WalksAllPages(t *testing.T) {
+	var requested []int
+	srv := tickPagesServer(t, testTickPages(), false, &requested)
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	var seen []string
+	err := NewClient().GetRouteTicksPaged("1", func(page []Tick) bool {
+		for _, tick := range page {
+			seen = append(seen, tick.Date)
+		}
+		return true
+	})
+	if err != nil {
+		t.Fatalf("GetRouteTicksPaged() error = %v", err)
+	}
+	if len(seen) != 4 {
+		t.Errorf("visited %d ticks, want 4", len(seen))
+	}
+	if fmt.Sprint(requested) != "[1 2 3]" {
+		t.Errorf("requested pages %v, want [1 2 3] (stop when next_page_url is null)", requested)
+	}
+}
+
+func TestGetRouteTicksPaged_StopsWhenVisitReturnsFalse(t *testing.T) {
+	var requested []int
+	srv := tickPagesServer(t, testTickPages(), false, &requested)
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	err := NewClient().GetRouteTicksPaged("1", func(page []Tick) bool {
+		return false
+	})
+	if err != nil {
+		t.Fatalf("GetRouteTicksPaged() error = %v", err)
+	}
+	if fmt.Sprint(requested) != "[1]" {
+		t.Errorf("requested pages %v, want [1]", requested)
+	}
+}
+
+func TestGetRouteTicksPaged_StopsWhenPagingIgnored(t *testing.T) {
+	var requested []int
+	srv := tickPagesServer(t, testTickPages(), true, &requested)
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	visits := 0
+	err := NewClient().GetRouteTicksPaged("1", func(page []Tick) bool {
+		visits++
+		return true
+	})
+	if err != nil {
+		t.Fatalf("GetRouteTicksPaged() error = %v", err)
+	}
+	if visits != 1 {
+		t.Errorf("visited %d pages, want 1 (full list served once)", visits)
+	}
+	if len(requested) != 2 {
+		t.Errorf("requested %d pages, want 2 (stop on current_page mismatch)", len(requested))
+	}
+}
+
+func TestGetRouteTicksPaged_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	err := NewClient().GetRouteTicksPaged("1", func(page []Tick) bool {
+		t.Error("visit should not be called on an error response")
+		return true
+	})
+	if err == nil {
+		t.Fatal("expected error for 500 response")
+	}
+}
+
+func TestGetUserTicksPaged(t *testing.T) {
+	var requested []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/42/ticks" {
+			http.NotFound(w, r)
+			return
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requested = append(requested, page)
+		if page == 1 {
+			fmt.Fprint(w, `{"data":[{"date":"Mar 3, 2026, 1:00 pm","style":"Send","route_id":105717310,"user":{"id":42,"name":"Alex"}}],"current_page":1,"next_page_url":"http://example.invalid/next"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":[{"date":"Mar 1, 2026","style":"Flash","route_id":7,"user":false}],"current_page":%d,"next_page_url":null}`, page)
+	}))
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	var seen []UserTick
+	err := NewClient().GetUserTicksPaged("42", func(page []UserTick) bool {
+		seen = append(seen, page...)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("GetUserTicksPaged() error = %v", err)
+	}
+	if fmt.Sprint(requested) != "[1 2]" {
+		t.Errorf("requested pages %v, want [1 2]", requested)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("visited %d ticks, want 2", len(seen))
+	}
+	if seen[0].RouteID != 105717310 || seen[0].Style != "Send" || seen[0].GetUserName() != "Alex" {
+		t.Errorf("first tick = %+v, want route 105717310, style Send, user Alex", seen[0])
+	}
+	if seen[1].RouteID != 7 || seen[1].Date != "Mar 1, 2026" {
+		t.Errorf("second tick = %+v, want route 7 dated Mar 1, 2026", seen[1])
+	}
+}
+
+func TestRateLimit_SharedAcrossGoroutines(t *testing.T) {
+	c := NewClient()
+
+	const callers = 3
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.rateLimit()
+		}()
+	}
+	wg.Wait()
+
+	// The first call goes straight through; each later one waits a full delay.
+	if elapsed, want := time.Since(start), (callers-1)*rateLimitDelay; elapsed < want {
+		t.Errorf("%d concurrent calls took %v, want at least %v", callers, elapsed, want)
+	}
+}
+
+func TestAreaResponse_LatLon(t *testing.T) {
+	tests := []struct {
+		name        string
+		coordinates []float64
+		wantLat     float64
+		wantLon     float64
+		wantOK      bool
+	}{
+		{"valid pair", []float64{-121.1408, 44.3672}, 44.3672, -121.1408, true},
+		{"nil", nil, 0, 0, false},
+		{"empty", []float64{}, 0, 0, false},
+		{"single value", []float64{-121.1408}, 0, 0, false},
+		{"extra values", []float64{-121.1408, 44.3672, 900}, 0, 0, false},
+		{"latitude out of range", []float64{-121.1408, 144.3672}, 0, 0, false},
+		{"longitude out of range", []float64{-221.1408, 44.3672}, 0, 0, false},
+		{"NaN", []float64{math.NaN(), 44.3672}, 0, 0, false},
+		{"null island", []float64{0, 0}, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			area := &AreaResponse{Coordinates: tt.coordinates}
+			lat, lon, ok := area.LatLon()
+			if ok != tt.wantOK || lat != tt.wantLat || lon != tt.wantLon {
+				t.Errorf("LatLon() = (%v, %v, %v), want (%v, %v, %v)", lat, lon, ok, tt.wantLat, tt.wantLon, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestGetArea_EmptyCoordinates is a regression test: MP occasionally returns
+// an empty coordinates array, which must decode and report no position
+// rather than panic on index.
+func TestGetArea_EmptyCoordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 105, "title": "Unmapped Area", "type": "Area", "children": [], "coordinates": []}`)
+	}))
+	defer server.Close()
+
+	setBaseURLForTest(t, server.URL)
+
+	area, err := NewClient().GetArea("105")
+	if err != nil {
+		t.Fatalf("GetArea() error = %v", err)
+	}
+	if _, _, ok := area.LatLon(); ok {
+		t.Errorf("LatLon() ok = true for empty coordinates")
+	}
+}
+
+func TestGetRouteCommentsBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/routes/1/comments":
+			json.NewEncoder(w).Encode([]Comment{{ID: 10, Message: "Seeps after rain"}})
+		case "/routes/2/comments":
+			json.NewEncoder(w).Encode(CommentResponse{Data: []Comment{{ID: 20}, {ID: 21}}})
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	setBaseURLForTest(t, srv.URL)
+
+	results := NewClient().GetRouteCommentsBatch([]string{"1", "3", "2"})
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, want := range []string{"1", "3", "2"} {
+		if results[i].ID != want {
+			t.Errorf("results[%d].ID = %q, want %q (input order)", i, results[i].ID, want)
+		}
+	}
+	if results[0].Err != nil || len(results[0].Comments) != 1 || results[0].Comments[0].ID != 10 {
+		t.Errorf("route 1: %+v", results[0])
+	}
+	if results[1].Err == nil {
+		t.Error("route 3: expected error for 500 response")
+	}
+	if results[2].Err != nil || len(results[2].Comments) != 2 {
+		t.Errorf("route 2: %+v", results[2])
+	}
+}
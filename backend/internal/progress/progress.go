@@ -0,0 +1,199 @@
+// Package progress reports how far a CLI command has got through a known
+// number of items: a live progress bar with rate and ETA when output is a
+// terminal, periodic log lines otherwise.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// barWidth is the width of a Tracker's live bar, excluding brackets.
+	barWidth = 30
+
+	// logInterval is how often a Tracker that isn't live logs its status.
+	logInterval = 30 * time.Second
+
+	// clearLine returns the cursor to the start of the line and erases it.
+	clearLine = "\r\033[K"
+)
+
+// Bar renders a progress bar percent full, width cells wide plus brackets,
+// padded to a constant width.
+func Bar(percent float64, width int) string {
+	filled := int(percent / 100 * float64(width))
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	empty := width - filled
+
+	bar := "[" + strings.Repeat("█", filled) + strings.Repeat("░", empty) + "]"
+	return fmt.Sprintf("%-*s", width+2, bar)
+}
+
+// FormatDuration formats seconds as "45s", "3m 20s" or "2h 5m".
+func FormatDuration(seconds int64) string {
+	if seconds < 60 {
+		return fmt.Sprintf("%ds", seconds)
+	} else if seconds < 3600 {
+		return fmt.Sprintf("%dm %ds", seconds/60, seconds%60)
+	} else {
+		hours := seconds / 3600
+		minutes := (seconds % 3600) / 60
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
+
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Tracker tracks progress through a fixed number of items. It is safe for
+// concurrent use, so parallel workers can share one.
+type Tracker struct {
+	mu       sync.Mutex
+	out      io.Writer
+	unit     string // plural item noun, e.g. "areas"
+	total    int
+	done     int
+	live     bool
+	drawn    bool // a live bar is on screen
+	finished bool
+	start    time.Time
+	lastLog  time.Time
+	now      func() time.Time
+}
+
+// New returns a Tracker for total items, described as unit (e.g. "areas").
+// When live, it draws a bar on stdout and redraws it in place on every
+// update; callers should pass live only when stdout is a terminal (see
+// IsTerminal). Otherwise it logs its status every 30 seconds.
+func New(unit string, total int, live bool) *Tracker {
+	return newTracker(os.Stdout, unit, total, live, time.Now)
+}
+
+func newTracker(out io.Writer, unit string, total int, live bool, now func() time.Time) *Tracker {
+	start := now()
+	t := &Tracker{out: out, unit: unit, total: total, live: live, start: start, lastLog: start, now: now}
+	if live {
+		t.draw()
+	}
+	return t
+}
+
+// Increment records one finished item.
+func (t *Tracker) Increment() {
+	t.Add(1)
+}
+
+// Add records n finished items.
+func (t *Tracker) Add(n int) {
+	t.mu.Lock()
+	t.done += n
+	if t.live && !t.finished {
+		t.draw()
+		t.mu.Unlock()
+		return
+	}
+
+	// Log outside the lock: log's output may be this Tracker's LogWriter.
+	var status string
+	if now := t.now(); !t.finished && now.Sub(t.lastLog) >= logInterval {
+		t.lastLog = now
+		status = t.status()
+	}
+	t.mu.Unlock()
+
+	if status != "" {
+		log.Printf("Progress: %s", status)
+	}
+}
+
+// Finish leaves the final bar on its own line. A Tracker that isn't live
+// does nothing, since callers log their own summary.
+func (t *Tracker) Finish() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.finished {
+		return
+	}
+	t.finished = true
+	if t.live {
+		t.draw()
+		fmt.Fprintln(t.out)
+		t.drawn = false
+	}
+}
+
+// LogWriter wraps w, normally the log package's output, so lines logged
+// while the bar is live are written above it instead of mid-bar: the bar is
+// cleared before each write and redrawn after.
+func (t *Tracker) LogWriter(w io.Writer) io.Writer {
+	return &logWriter{t: t, w: w}
+}
+
+type logWriter struct {
+	t *Tracker
+	w io.Writer
+}
+
+func (lw *logWriter) Write(p []byte) (int, error) {
+	t := lw.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.drawn {
+		return lw.w.Write(p)
+	}
+	fmt.Fprint(t.out, clearLine)
+	n, err := lw.w.Write(p)
+	t.draw()
+	return n, err
+}
+
+// draw redraws the live bar. Callers must hold t.mu.
+func (t *Tracker) draw() {
+	fmt.Fprintf(t.out, "%s%s %s", clearLine, Bar(t.percent(), barWidth), t.status())
+	t.drawn = true
+}
+
+// status describes progress, e.g. "12/50 areas (24.0%) | 1.5/min | ETA 25m 20s".
+// Callers must hold t.mu.
+func (t *Tracker) status() string {
+	elapsed := t.now().Sub(t.start)
+	status := fmt.Sprintf("%d/%d %s (%.1f%%) | %s", t.done, t.total, t.unit, t.percent(), FormatDuration(int64(elapsed.Seconds())))
+	if t.done == 0 || elapsed <= 0 {
+		return status + " | ETA -"
+	}
+
+	perSecond := float64(t.done) / elapsed.Seconds()
+	rate := fmt.Sprintf("%.2f/s", perSecond)
+	if perSecond < 1 {
+		rate = fmt.Sprintf("%.1f/min", perSecond*60)
+	}
+	remaining := t.total - t.done
+	if remaining < 0 {
+		remaining = 0
+	}
+	eta := FormatDuration(int64(float64(remaining) / perSecond))
+	return fmt.Sprintf("%s | %s | ETA %s", status, rate, eta)
+}
+
+// percent returns how much is done, 0-100. Callers must hold t.mu.
+func (t *Tracker) percent() float64 {
+	if t.total <= 0 {
+		return 100
+	}
+	return float64(t.done) / float64(t.total) * 100
+}
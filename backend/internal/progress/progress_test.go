@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    string
+	}{
+		{0, "[░░░░░░░░░░]"},
+		{50, "[█████░░░░░]"},
+		{100, "[██████████]"},
+		{150, "[██████████]"},
+		{-5, "[░░░░░░░░░░]"},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.percent, 10); got != tt.want {
+			t.Errorf("Bar(%v, 10) = %q, want %q", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		seconds int64
+		want    string
+	}{
+		{45, "45s"},
+		{200, "3m 20s"},
+		{7500, "2h 5m"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.seconds); got != tt.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+// fakeClock returns a controllable now func for newTracker.
+func fakeClock() (now func() time.Time, advance func(time.Duration)) {
+	current := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time { return current }, func(d time.Duration) { current = current.Add(d) }
+}
+
+func TestTracker_Live(t *testing.T) {
+	var out bytes.Buffer
+	now, advance := fakeClock()
+	tracker := newTracker(&out, "areas", 4, true, now)
+
+	if !strings.Contains(out.String(), "0/4 areas (0.0%)") || !strings.Contains(out.String(), "ETA -") {
+		t.Errorf("initial draw = %q, want 0/4 with no ETA", out.String())
+	}
+
+	advance(2 * time.Minute)
+	out.Reset()
+	tracker.Increment()
+
+	got := out.String()
+	if !strings.HasPrefix(got, clearLine) {
+		t.Errorf("redraw = %q, want it to start by clearing the line", got)
+	}
+	for _, want := range []string{"1/4 areas (25.0%)", "2m 0s", "0.5/min", "ETA 6m 0s"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("redraw = %q, want it to contain %q", got, want)
+		}
+	}
+
+	out.Reset()
+	tracker.Finish()
+	if !strings.HasSuffix(out.String(), "\n") {
+		t.Errorf("Finish() wrote %q, want the bar ended with a newline", out.String())
+	}
+}
+
+func TestTracker_LogWriterKeepsBarBelowLogs(t *testing.T) {
+	var out, logs bytes.Buffer
+	now, _ := fakeClock()
+	tracker := newTracker(&out, "locations", 2, true, now)
+	w := tracker.LogWriter(&logs)
+
+	out.Reset()
+	if _, err := w.Write([]byte("syncing Leavenworth\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if logs.String() != "syncing Leavenworth\n" {
+		t.Errorf("log output = %q", logs.String())
+	}
+	if got := out.String(); !strings.HasPrefix(got, clearLine+clearLine) || !strings.Contains(got, "0/2 locations") {
+		t.Errorf("bar output around log write = %q, want clear then redraw", got)
+	}
+
+	// Once finished the bar is left alone.
+	tracker.Finish()
+	out.Reset()
+	w.Write([]byte("done\n"))
+	if out.Len() != 0 {
+		t.Errorf("bar output after Finish() = %q, want none", out.String())
+	}
+}
+
+func TestTracker_NotLiveLogsPeriodically(t *testing.T) {
+	var out, logs bytes.Buffer
+	log.SetOutput(&logs)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	}()
+
+	now, advance := fakeClock()
+	tracker := newTracker(&out, "areas", 10, false, now)
+
+	tracker.Increment()
+	if logs.Len() != 0 {
+		t.Errorf("logged %q before the log interval", logs.String())
+	}
+
+	advance(logInterval)
+	tracker.Increment()
+	if !strings.Contains(logs.String(), "Progress: 2/10 areas (20.0%)") {
+		t.Errorf("log output = %q, want a progress line", logs.String())
+	}
+
+	tracker.Finish()
+	if out.Len() != 0 {
+		t.Errorf("tracker that isn't live wrote %q to its output", out.String())
+	}
+}
+
+func TestTracker_ConcurrentIncrements(t *testing.T) {
+	var out bytes.Buffer
+	tracker := newTracker(&out, "routes", 100, true, time.Now)
+	w := tracker.LogWriter(&bytes.Buffer{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				tracker.Increment()
+				w.Write([]byte("line\n"))
+			}
+		}()
+	}
+	wg.Wait()
+	tracker.Finish()
+
+	if tracker.done != 100 {
+		t.Errorf("done = %d, want 100", tracker.done)
+	}
+}
@@ -0,0 +1,100 @@
+// Package retry runs operations that can fail transiently, retrying them
+// with exponential backoff according to a Policy.
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Policy controls how Do retries an operation.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 mean a single attempt.
+	MaxAttempts int
+
+	// InitialDelay is the wait before the first retry. Each later wait is
+	// Multiplier times the previous one (2 if Multiplier is 0), capped at
+	// MaxDelay when MaxDelay is set.
+	InitialDelay time.Duration
+	Multiplier   float64
+	MaxDelay     time.Duration
+
+	// Jitter randomizes each wait by up to this fraction of it in either
+	// direction (0.2 means ±20%), so concurrent callers don't retry in
+	// lockstep. 0 disables jitter.
+	Jitter float64
+
+	// ShouldRetry reports whether an error is worth retrying. Errors it
+	// rejects are returned immediately. nil retries every error.
+	ShouldRetry func(err error) bool
+
+	// OnRetry, if set, is called before each wait with the attempt that just
+	// failed (starting at 1), its error and the wait. Useful for logging.
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+// Do calls fn until it succeeds, returns an error policy.ShouldRetry
+// rejects, or policy.MaxAttempts attempts have been made, and returns the
+// last error. If ctx is done before an attempt, Do stops and returns
+// ctx.Err(), wrapping the last error if there was one.
+func Do(ctx context.Context, policy Policy, fn func() error) error {
+	attempts := max(policy.MaxAttempts, 1)
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return stopped(err, lastErr)
+		}
+
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+		if attempt >= attempts || (policy.ShouldRetry != nil && !policy.ShouldRetry(lastErr)) {
+			return lastErr
+		}
+
+		wait := policy.delay(attempt, rand.Float64)
+		if policy.OnRetry != nil {
+			policy.OnRetry(attempt, lastErr, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return stopped(ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+	}
+}
+
+// stopped is the error returned when ctx ends the retries.
+func stopped(ctxErr, lastErr error) error {
+	if lastErr == nil {
+		return ctxErr
+	}
+	return fmt.Errorf("%w (last error: %w)", ctxErr, lastErr)
+}
+
+// delay is the wait after the given failed attempt (starting at 1). random
+// returns values in [0, 1) and drives the jitter.
+func (p Policy) delay(attempt int, random func() float64) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	d := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 {
+		d = math.Min(d, float64(p.MaxDelay))
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*random()-1)
+	}
+	return time.Duration(math.Max(d, 0))
+}
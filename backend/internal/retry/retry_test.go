@@ -0,0 +1,200 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+func TestDo_SucceedsOnThirdAttempt(t *testing.T) {
+	calls := 0
+	var retried []int
+	policy := Policy{
+		MaxAttempts:  5,
+		InitialDelay: time.Millisecond,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			if !errors.Is(err, errTransient) {
+				t.Errorf("OnRetry got err %v, want %v", err, errTransient)
+			}
+			retried = append(retried, attempt)
+		},
+	}
+
+	err := Do(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Do() error = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+	if len(retried) != 2 || retried[0] != 1 || retried[1] != 2 {
+		t.Errorf("OnRetry attempts = %v, want [1 2]", retried)
+	}
+}
+
+func TestDo_RespectsMaxAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		wantCalls   int
+	}{
+		{"three attempts", 3, 3},
+		{"single attempt", 1, 1},
+		{"zero means one attempt", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), Policy{MaxAttempts: tt.maxAttempts, InitialDelay: time.Millisecond}, func() error {
+				calls++
+				return errTransient
+			})
+
+			if !errors.Is(err, errTransient) {
+				t.Errorf("Do() error = %v, want the last error", err)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDo_ReturnsLastError(t *testing.T) {
+	calls := 0
+	errs := []error{errors.New("first"), errors.New("second"), errors.New("third")}
+
+	err := Do(context.Background(), Policy{MaxAttempts: 3, InitialDelay: time.Millisecond}, func() error {
+		calls++
+		return errs[calls-1]
+	})
+
+	if err != errs[2] {
+		t.Errorf("Do() error = %v, want %v", err, errs[2])
+	}
+}
+
+func TestDo_StopsOnNonRetryableError(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	policy := Policy{
+		MaxAttempts:  5,
+		InitialDelay: time.Millisecond,
+		ShouldRetry:  func(err error) bool { return errors.Is(err, errTransient) },
+	}
+
+	err := Do(context.Background(), policy, func() error {
+		calls++
+		if calls == 1 {
+			return errTransient
+		}
+		return permanent
+	})
+
+	if err != permanent {
+		t.Errorf("Do() error = %v, want %v", err, permanent)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestDo_HonorsContextCancellation(t *testing.T) {
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		policy := Policy{
+			MaxAttempts:  5,
+			InitialDelay: time.Hour,
+			OnRetry:      func(int, error, time.Duration) { cancel() },
+		}
+
+		start := time.Now()
+		err := Do(ctx, policy, func() error {
+			calls++
+			return errTransient
+		})
+
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Do() error = %v, want context.Canceled", err)
+		}
+		if !errors.Is(err, errTransient) {
+			t.Errorf("Do() error = %v, want it to wrap the last error", err)
+		}
+		if calls != 1 {
+			t.Errorf("calls = %d, want 1", calls)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Do() waited %v after cancellation", elapsed)
+		}
+	})
+
+	t.Run("cancelled before the first attempt", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+
+		err := Do(ctx, Policy{MaxAttempts: 3}, func() error {
+			calls++
+			return nil
+		})
+
+		if err != context.Canceled {
+			t.Errorf("Do() error = %v, want context.Canceled", err)
+		}
+		if calls != 0 {
+			t.Errorf("calls = %d, want 0", calls)
+		}
+	})
+
+	t.Run("deadline while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		err := Do(ctx, Policy{MaxAttempts: 3, InitialDelay: time.Hour}, func() error {
+			return errTransient
+		})
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Do() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+func TestPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		attempt int
+		random  float64
+		want    time.Duration
+	}{
+		{"first retry", Policy{InitialDelay: time.Second}, 1, 0.5, time.Second},
+		{"default doubling", Policy{InitialDelay: time.Second}, 3, 0.5, 4 * time.Second},
+		{"custom multiplier", Policy{InitialDelay: time.Second, Multiplier: 3}, 3, 0.5, 9 * time.Second},
+		{"capped", Policy{InitialDelay: time.Second, MaxDelay: 5 * time.Second}, 4, 0.5, 5 * time.Second},
+		{"jitter low", Policy{InitialDelay: time.Second, Jitter: 0.2}, 1, 0, 800 * time.Millisecond},
+		{"jitter high", Policy{InitialDelay: time.Second, Jitter: 0.2}, 1, 1, 1200 * time.Millisecond},
+		{"jitter applied after cap", Policy{InitialDelay: time.Second, MaxDelay: 2 * time.Second, Jitter: 0.5}, 5, 0, time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.delay(tt.attempt, func() float64 { return tt.random })
+			if got != tt.want {
+				t.Errorf("delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,15 +1,15 @@
 package rivers
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"log"
 	"math"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
@@ -75,21 +75,10 @@ func (c *USGSClient) GetRiverData(gaugeID string) (float64, float64, string, err
 	url := fmt.Sprintf("%s?format=json&sites=%s&parameterCd=00060,00065&siteStatus=active",
 		usgsWaterServicesURL, gaugeID)
 
-	resp, err := c.httpClient.Get(url)
+	data, err := httpx.GetJSON[usgsResponse](context.Background(), c.httpClient, url, nil)
 	if err != nil {
 		return 0, 0, "", fmt.Errorf("failed to fetch USGS data: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return 0, 0, "", fmt.Errorf("USGS API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data usgsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return 0, 0, "", fmt.Errorf("failed to decode USGS response: %w", err)
-	}
 
 	var flowCFS float64
 	var gaugeHeightFt float64
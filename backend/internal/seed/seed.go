@@ -0,0 +1,164 @@
+// Package seed loads a small, fixed dataset into a development database:
+// a few locations with their Mountain Project areas, routes, ticks and
+// comments, and a day of weather for each. It backs the seed command, so
+// developers and integration tests get a populated database without calling
+// Mountain Project or the weather APIs.
+//
+// The fixtures are embedded from fixtures/. Location fixtures use the
+// export_location dump format and are loaded with locationdump.Import, so
+// everything is upserted and seeding twice leaves the same rows. Their MP IDs
+// are in the 900000000 range, clear of real Mountain Project IDs, so seeding
+// never overwrites synced data. Weather hours have fixed timestamps and are
+// upserted on (location_id, timestamp).
+package seed
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+
+	"github.com/alexscott64/woulder/backend/internal/database/weather"
+	"github.com/alexscott64/woulder/backend/internal/locationdump"
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// releaseMode is gin's production mode; see CheckEnvironment.
+const releaseMode = "release"
+
+// WeatherSnapshot is the fixture weather for one seeded location.
+type WeatherSnapshot struct {
+	Location string               `json:"location"` // Name of a location fixture
+	Hours    []models.WeatherData `json:"hours"`
+}
+
+// Fixtures is the embedded seed dataset.
+type Fixtures struct {
+	Locations []*locationdump.Dump
+	Weather   []WeatherSnapshot
+}
+
+// Repos are the repositories the seed is written to.
+type Repos struct {
+	locationdump.Repos
+	Weather weather.Repository
+}
+
+// NewPostgresRepos creates Repos backed by db, normally a transaction.
+func NewPostgresRepos(db locationdump.DBConn) Repos {
+	return Repos{
+		Repos:   locationdump.NewPostgresRepos(db),
+		Weather: weather.NewPostgresRepository(db),
+	}
+}
+
+// Result counts what Run wrote.
+type Result struct {
+	Locations        int
+	LocationsCreated int
+	MPAreas          int
+	Routes           int
+	Ticks            int
+	Comments         int
+	WeatherHours     int
+}
+
+// CheckEnvironment refuses to seed a production server's database, which
+// runs with GIN_MODE=release (the default). Set GIN_MODE=debug or test to
+// seed.
+func CheckEnvironment(ginMode string) error {
+	if ginMode == releaseMode {
+		return fmt.Errorf("refusing to seed with GIN_MODE=%q; set GIN_MODE=debug or test for a development database", releaseMode)
+	}
+	return nil
+}
+
+// LoadFixtures reads and validates the embedded fixtures. Locations are in
+// file name order.
+func LoadFixtures() (*Fixtures, error) {
+	files, err := fs.Glob(fixtures, "fixtures/locations/*.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	f := &Fixtures{}
+	names := make(map[string]bool)
+	for _, file := range files {
+		data, err := fixtures.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		dump, err := locationdump.Read(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path.Base(file), err)
+		}
+		if names[dump.Location.Name] {
+			return nil, fmt.Errorf("%s: location %q is already in another fixture", path.Base(file), dump.Location.Name)
+		}
+		names[dump.Location.Name] = true
+		f.Locations = append(f.Locations, dump)
+	}
+
+	data, err := fixtures.ReadFile("fixtures/weather.json")
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &f.Weather); err != nil {
+		return nil, fmt.Errorf("weather.json: %w", err)
+	}
+	for _, w := range f.Weather {
+		if !names[w.Location] {
+			return nil, fmt.Errorf("weather.json: location %q has no location fixture", w.Location)
+		}
+	}
+
+	return f, nil
+}
+
+// Run upserts f into the database behind r. Like locationdump.Import it
+// writes row by row, so the caller should run it inside a transaction.
+func Run(ctx context.Context, r Repos, f *Fixtures) (*Result, error) {
+	res := &Result{}
+	locationIDs := make(map[string]int, len(f.Locations))
+
+	for _, dump := range f.Locations {
+		imported, err := locationdump.Import(ctx, r.Repos, dump)
+		if err != nil {
+			return nil, fmt.Errorf("seed location %q: %w", dump.Location.Name, err)
+		}
+		locationIDs[dump.Location.Name] = imported.LocationID
+
+		res.Locations++
+		if imported.LocationCreated {
+			res.LocationsCreated++
+		}
+		res.MPAreas += imported.MPAreas
+		res.Routes += imported.Routes
+		res.Ticks += imported.Ticks
+		res.Comments += imported.Comments
+	}
+
+	for _, w := range f.Weather {
+		locationID, ok := locationIDs[w.Location]
+		if !ok {
+			return nil, fmt.Errorf("seed weather: location %q was not seeded", w.Location)
+		}
+		for _, hour := range w.Hours {
+			hour.LocationID = locationID
+			if err := r.Weather.Save(ctx, &hour); err != nil {
+				return nil, fmt.Errorf("seed weather for %q at %s: %w", w.Location, hour.Timestamp.Format("2006-01-02 15:04"), err)
+			}
+			res.WeatherHours++
+		}
+	}
+
+	return res, nil
+}
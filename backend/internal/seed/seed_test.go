@@ -0,0 +1,60 @@
+package seed
+
+import (
+	"testing"
+)
+
+func TestCheckEnvironment(t *testing.T) {
+	if err := CheckEnvironment("release"); err == nil {
+		t.Error("CheckEnvironment(release) = nil, want an error")
+	}
+	for _, mode := range []string{"debug", "test"} {
+		if err := CheckEnvironment(mode); err != nil {
+			t.Errorf("CheckEnvironment(%q) = %v, want nil", mode, err)
+		}
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	f, err := LoadFixtures()
+	if err != nil {
+		t.Fatalf("LoadFixtures() error = %v", err)
+	}
+	if len(f.Locations) == 0 {
+		t.Fatal("LoadFixtures() returned no locations")
+	}
+
+	for _, d := range f.Locations {
+		if len(d.MPAreas) == 0 || len(d.Routes) == 0 || len(d.Ticks) == 0 {
+			t.Errorf("location %q: %d areas, %d routes, %d ticks, want some of each",
+				d.Location.Name, len(d.MPAreas), len(d.Routes), len(d.Ticks))
+		}
+		// Fixture MP IDs must stay clear of real Mountain Project IDs.
+		for _, a := range d.MPAreas {
+			if a.MPAreaID < 900000000 {
+				t.Errorf("location %q: area %d is outside the fixture ID range", d.Location.Name, a.MPAreaID)
+			}
+		}
+		for _, r := range d.Routes {
+			if r.MPRouteID < 900000000 {
+				t.Errorf("location %q: route %d is outside the fixture ID range", d.Location.Name, r.MPRouteID)
+			}
+		}
+	}
+
+	if len(f.Weather) == 0 {
+		t.Fatal("LoadFixtures() returned no weather")
+	}
+	for _, w := range f.Weather {
+		seen := make(map[int64]bool)
+		for _, h := range w.Hours {
+			if h.Timestamp.IsZero() {
+				t.Errorf("weather for %q: hour with no timestamp", w.Location)
+			}
+			if seen[h.Timestamp.Unix()] {
+				t.Errorf("weather for %q: duplicate hour %s", w.Location, h.Timestamp)
+			}
+			seen[h.Timestamp.Unix()] = true
+		}
+	}
+}
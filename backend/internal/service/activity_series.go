@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// Activity series bucket sizes accepted by GetRouteActivitySeries and
+// GetAreaActivitySeries.
+const (
+	ActivityBucketDay  = "day"
+	ActivityBucketWeek = "week"
+)
+
+// ErrAreaNotFound is returned when a requested area has not been synced.
+var ErrAreaNotFound = errors.New("area not found")
+
+// IsValidActivityBucket reports whether bucket is a supported series bucket.
+func IsValidActivityBucket(bucket string) bool {
+	return bucket == ActivityBucketDay || bucket == ActivityBucketWeek
+}
+
+// GetRouteActivitySeries counts a route's ticks per day or week over the
+// last days Pacific days up to and including the day containing now. Empty
+// buckets are zero-filled. Weekly buckets start on Monday, so the first one
+// can begin before the window does but only counts ticks inside it.
+func (s *ClimbTrackingService) GetRouteActivitySeries(ctx context.Context, routeID int64, bucket string, days int, now time.Time) ([]models.ActivitySeriesPoint, error) {
+	route, err := s.mountainProjectRepo.Routes().GetByID(ctx, routeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get route %d: %w", routeID, err)
+	}
+	if route == nil {
+		return nil, fmt.Errorf("route %d: %w", routeID, ErrRouteNotFound)
+	}
+
+	startDate, endDate, err := activitySeriesWindow(bucket, days, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.climbingRepo.Activity().GetRouteActivitySeries(ctx, routeID, bucket, startDate, endDate)
+}
+
+// GetAreaActivitySeries is GetRouteActivitySeries over every route in an
+// area and its subareas.
+func (s *ClimbTrackingService) GetAreaActivitySeries(ctx context.Context, areaID int64, bucket string, days int, now time.Time) ([]models.ActivitySeriesPoint, error) {
+	area, err := s.mountainProjectRepo.Areas().GetAreaByID(ctx, areaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get area %d: %w", areaID, err)
+	}
+	if area == nil {
+		return nil, fmt.Errorf("area %d: %w", areaID, ErrAreaNotFound)
+	}
+
+	startDate, endDate, err := activitySeriesWindow(bucket, days, now)
+	if err != nil {
+		return nil, err
+	}
+	return s.climbingRepo.Activity().GetAreaActivitySeries(ctx, areaID, bucket, startDate, endDate)
+}
+
+// activitySeriesWindow returns the inclusive YYYY-MM-DD range covering the
+// last days Pacific days up to the day containing now.
+func activitySeriesWindow(bucket string, days int, now time.Time) (startDate, endDate string, err error) {
+	if !IsValidActivityBucket(bucket) {
+		return "", "", fmt.Errorf("invalid activity bucket %q", bucket)
+	}
+	if days < 1 {
+		return "", "", fmt.Errorf("invalid activity window of %d days", days)
+	}
+
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load Pacific timezone: %w", err)
+	}
+	local := now.In(pacificTZ)
+	end := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, pacificTZ)
+	start := end.AddDate(0, 0, -(days - 1))
+	return start.Format("2006-01-02"), end.Format("2006-01-02"), nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRouteActivitySeries_PacificWindow(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetByIDFn = func(ctx context.Context, mpRouteID int64) (*models.MPRoute, error) {
+		return &models.MPRoute{MPRouteID: mpRouteID}, nil
+	}
+	climbingRepo := NewMockClimbingRepository()
+	climbingRepo.activity.GetRouteActivitySeriesFn = func(ctx context.Context, routeID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+		assert.Equal(t, int64(42), routeID)
+		assert.Equal(t, ActivityBucketWeek, bucket)
+		assert.Equal(t, "2026-03-04", startDate)
+		assert.Equal(t, "2026-03-10", endDate)
+		return []models.ActivitySeriesPoint{{BucketStart: "2026-03-02", TickCount: 3}, {BucketStart: "2026-03-09", TickCount: 0}}, nil
+	}
+	svc := NewClimbTrackingService(mpRepo, climbingRepo, nil, nil, nil)
+
+	// 05:00 UTC on March 11 is still March 10 in Pacific time.
+	now := time.Date(2026, 3, 11, 5, 0, 0, 0, time.UTC)
+	series, err := svc.GetRouteActivitySeries(context.Background(), 42, ActivityBucketWeek, 7, now)
+	require.NoError(t, err)
+	assert.Len(t, series, 2)
+}
+
+func TestGetRouteActivitySeries_RouteNotFound(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetRouteActivitySeries(context.Background(), 42, ActivityBucketDay, 30, time.Now())
+	assert.True(t, errors.Is(err, ErrRouteNotFound), "err = %v", err)
+}
+
+func TestGetAreaActivitySeries_AreaNotFound(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetAreaActivitySeries(context.Background(), 7, ActivityBucketDay, 30, time.Now())
+	assert.True(t, errors.Is(err, ErrAreaNotFound), "err = %v", err)
+}
+
+func TestActivitySeriesWindow_RejectsInvalidInput(t *testing.T) {
+	_, _, err := activitySeriesWindow("month", 30, time.Now())
+	assert.Error(t, err)
+
+	_, _, err = activitySeriesWindow(ActivityBucketDay, 0, time.Now())
+	assert.Error(t, err)
+}
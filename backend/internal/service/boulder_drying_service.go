@@ -18,11 +18,12 @@ import (
 	"github.com/alexscott64/woulder/backend/internal/weather/client"
 	"github.com/alexscott64/woulder/backend/internal/weather/rock_drying"
 	"github.com/alexscott64/woulder/backend/internal/weather/rock_temp"
+	sunpkg "github.com/alexscott64/woulder/backend/internal/weather/sun"
 )
 
 // WeatherClientInterface defines the interface for weather operations
 type WeatherClientInterface interface {
-	GetCurrentAndForecast(lat, lon float64) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error)
+	GetCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error)
 }
 
 // Ensure WeatherService implements the interface
@@ -38,6 +39,10 @@ type BoulderDryingService struct {
 	calculator          *boulder_drying.Calculator
 	rockTempCalculator  *rock_temp.Calculator
 	weatherClient       WeatherClientInterface
+
+	// Set by SetTreeCoverRefresher; nil disables RefreshStaleTreeCoverage.
+	treeCover           TreeCoverFetcher
+	treeCoverJobMonitor TreeCoverRefreshJobMonitor
 }
 
 // NewBoulderDryingService creates a new boulder drying service
@@ -144,6 +149,50 @@ func (s *BoulderDryingService) calculateBoulderRockTempStatus(
 	return &status
 }
 
+// calculateRouteDryingStatus computes a route's drying status from its boulder
+// profile, or estimates it from location-level data when the route has no
+// profile yet. primaryRock is loaded lazily (once per location) through
+// loadPrimaryRock so fully profiled locations never pay for the query.
+func (s *BoulderDryingService) calculateRouteDryingStatus(
+	ctx context.Context,
+	route *models.MPRoute,
+	locationDrying *models.RockDryingStatus,
+	profile *models.BoulderDryingProfile,
+	sunExposure *models.LocationSunExposure,
+	loadPrimaryRock func() *models.RockType,
+	hourlyForecast []models.WeatherData,
+) (*boulder_drying.BoulderDryingStatus, error) {
+	if profile != nil {
+		locationTreeCoverage := 0.0
+		if sunExposure != nil {
+			locationTreeCoverage = sunExposure.TreeCoveragePercent
+		}
+		return s.calculator.CalculateBoulderDryingStatus(ctx, route, locationDrying, profile, locationTreeCoverage, hourlyForecast)
+	}
+	return s.calculator.EstimateBoulderDryingStatus(ctx, route, locationDrying, loadPrimaryRock(), sunExposure, hourlyForecast)
+}
+
+// primaryRockLoader returns a function that fetches the location's primary
+// rock type on first call and caches the result (nil on error).
+func (s *BoulderDryingService) primaryRockLoader(ctx context.Context, locationID int) func() *models.RockType {
+	var (
+		rock   *models.RockType
+		loaded bool
+	)
+	return func() *models.RockType {
+		if !loaded {
+			loaded = true
+			var err error
+			rock, err = s.rocksRepo.GetPrimaryRockType(ctx, locationID)
+			if err != nil {
+				log.Printf("Warning: Failed to get primary rock type for location %d: %v", locationID, err)
+				rock = nil
+			}
+		}
+		return rock
+	}
+}
+
 // GetBatchBoulderDryingStatus calculates the drying status for multiple boulders efficiently
 func (s *BoulderDryingService) GetBatchBoulderDryingStatus(
 	ctx context.Context,
@@ -217,10 +266,7 @@ func (s *BoulderDryingService) GetBatchBoulderDryingStatus(
 		}
 		log.Printf("[PERF] GetSunExposureByLocation took %v", time.Since(sunStart))
 
-		locationTreeCoverage := 0.0
-		if sunExposure != nil {
-			locationTreeCoverage = sunExposure.TreeCoveragePercent
-		}
+		loadPrimaryRock := s.primaryRockLoader(ctx, locationID)
 
 		// Use the fresh forecast from getLocationRockDryingStatus (already fetched from API)
 		// This ensures boulder 6-day forecast matches the location drying calculation
@@ -235,12 +281,13 @@ func (s *BoulderDryingService) GetBatchBoulderDryingStatus(
 			profile := profilesMap[route.MPRouteID]
 
 			calcStart := time.Now()
-			status, err := s.calculator.CalculateBoulderDryingStatus(
+			status, err := s.calculateRouteDryingStatus(
 				ctx,
 				route,
 				locationDrying,
 				profile,
-				locationTreeCoverage,
+				sunExposure,
+				loadPrimaryRock,
 				hourlyForecast,
 			)
 			if err != nil {
@@ -319,23 +366,18 @@ func (s *BoulderDryingService) GetBoulderDryingStatus(
 		profile = nil // Continue without profile
 	}
 
-	// Get location sun exposure (for tree coverage)
+	// Get location sun exposure (for tree coverage and the no-profile estimate)
 	sunExposure, err := s.rocksRepo.GetSunExposureByLocation(ctx, *route.LocationID)
 	if err != nil {
 		log.Printf("Warning: Failed to get sun exposure for location %d: %v", *route.LocationID, err)
 		sunExposure = nil
 	}
 
-	// Extract location tree coverage
-	locationTreeCoverage := 0.0
-	if sunExposure != nil {
-		locationTreeCoverage = sunExposure.TreeCoveragePercent
-	}
-
 	// hourlyForecast already obtained from getLocationRockDryingStatus (fresh API data)
 
-	// Calculate boulder-specific drying status
-	status, err := s.calculator.CalculateBoulderDryingStatus(ctx, route, locationDrying, profile, locationTreeCoverage, hourlyForecast)
+	// Calculate boulder-specific drying status (estimated if no profile exists)
+	status, err := s.calculateRouteDryingStatus(ctx, route, locationDrying, profile, sunExposure,
+		s.primaryRockLoader(ctx, *route.LocationID), hourlyForecast)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate boulder drying status: %w", err)
 	}
@@ -344,12 +386,43 @@ func (s *BoulderDryingService) GetBoulderDryingStatus(
 	// rock_type_override and tree_coverage_percent overrides.
 	status.RockTemperatureStatus = s.calculateBoulderRockTempStatus(locWeatherCtx, profile)
 
+	// Today's sun/shade timeline for the face, in the location's timezone.
+	// Only on the single-route detail; batch callers don't need it.
+	if locWeatherCtx != nil {
+		status.ShadeClock = shadeClockForStatus(status, locWeatherCtx.location, time.Now())
+	}
+
 	// NOTE: Sun exposure is NOT cached because it's time-dependent (next 6 days from NOW)
 	// Tree coverage should be pre-populated by background job (cmd/sync_tree_cover)
 
 	return status, nil
 }
 
+// shadeClockForStatus estimates today's sun/shade timeline for a route's face
+// from its drying status aspect and GPS, falling back to the location's
+// coordinates when the route has none.
+func shadeClockForStatus(status *boulder_drying.BoulderDryingStatus, location *models.Location, now time.Time) *sunpkg.ShadeClock {
+	if status == nil || status.Aspect == "" {
+		return nil
+	}
+
+	lat, lon := status.Latitude, status.Longitude
+	tz := time.UTC
+	if location != nil {
+		if lat == 0 && lon == 0 {
+			lat, lon = location.Latitude, location.Longitude
+		}
+		if loc, err := time.LoadLocation(location.Timezone); err == nil && location.Timezone != "" {
+			tz = loc
+		}
+	}
+	if lat == 0 && lon == 0 {
+		return nil
+	}
+
+	return sunpkg.ComputeShadeClock(lat, lon, status.Aspect, now, tz)
+}
+
 // getLocationRockDryingStatus calculates location-level rock drying status.
 // Returns the drying status, the fresh forecast data, and a locationWeatherContext
 // containing the inputs (location, current weather, historical weather, rock types,
@@ -596,10 +669,7 @@ func (s *BoulderDryingService) GetBatchAreaDryingStats(
 		log.Printf("Warning: Failed to get sun exposure for location %d: %v", locationID, err)
 		sunExposure = nil
 	}
-	locationTreeCoverage := 0.0
-	if sunExposure != nil {
-		locationTreeCoverage = sunExposure.TreeCoveragePercent
-	}
+	loadPrimaryRock := s.primaryRockLoader(ctx, locationID)
 
 	// hourlyForecast already obtained from getLocationRockDryingStatus (fresh API data)
 	log.Printf("[PERF] Using fresh forecast from API (%d hours)", len(hourlyForecast))
@@ -641,13 +711,14 @@ func (s *BoulderDryingService) GetBatchAreaDryingStats(
 		}
 
 		profile := profilesMap[routeID]
-		status, err := s.calculator.CalculateBoulderDryingStatus(
+		status, err := s.calculateRouteDryingStatus(
 			ctx,
 			route,
 			locationDrying, // REUSED for all routes
 			profile,
-			locationTreeCoverage, // REUSED for all routes
-			hourlyForecast,       // REUSED for all routes
+			sunExposure,     // REUSED for all routes
+			loadPrimaryRock, // REUSED for all routes
+			hourlyForecast,  // REUSED for all routes
 		)
 		if err != nil {
 			log.Printf("Warning: Failed to calculate status for route %d: %v", routeID, err)
@@ -15,7 +15,7 @@ type mockWeatherClient struct {
 	forecastWeather []models.WeatherData
 }
 
-func (m *mockWeatherClient) GetCurrentAndForecast(lat, lon float64) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
+func (m *mockWeatherClient) GetCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
 	return m.currentWeather, m.forecastWeather, nil, nil
 }
 
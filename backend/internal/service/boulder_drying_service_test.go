@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
 	"github.com/alexscott64/woulder/backend/internal/weather/client"
 )
 
@@ -15,7 +16,7 @@ type mockBoulderWeatherClient struct {
 	forecastWeather []models.WeatherData
 }
 
-func (m *mockBoulderWeatherClient) GetCurrentAndForecast(lat, lon float64) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
+func (m *mockBoulderWeatherClient) GetCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
 	return m.currentWeather, m.forecastWeather, nil, nil
 }
 
@@ -540,7 +541,144 @@ func TestGetAreaDryingStats_MixedStatus(t *testing.T) {
 		stats.PercentDry, stats.AvgHoursUntilDry)
 }
 
+// TestGetBatchBoulderDryingStatus_EstimatesWithoutProfile verifies routes with
+// no boulder drying profile fall back to a location-level estimate.
+func TestGetBatchBoulderDryingStatus_EstimatesWithoutProfile(t *testing.T) {
+	now := time.Now()
+	locationID := 1
+
+	routes := map[int64]*models.MPRoute{
+		2001: {
+			MPRouteID:  2001,
+			Name:       "Profiled",
+			LocationID: &locationID,
+			Latitude:   ptrFloat64(47.6),
+			Longitude:  ptrFloat64(-120.9),
+			Aspect:     ptrString("S"),
+		},
+		2002: {
+			MPRouteID:  2002,
+			Name:       "Unprofiled, no aspect",
+			LocationID: &locationID,
+			Latitude:   ptrFloat64(47.61),
+			Longitude:  ptrFloat64(-120.91),
+		},
+		2003: {
+			MPRouteID:  2003,
+			Name:       "Unprofiled",
+			LocationID: &locationID,
+			Latitude:   ptrFloat64(47.62),
+			Longitude:  ptrFloat64(-120.92),
+			Aspect:     ptrString("E"),
+		},
+	}
+
+	currentWeather := &models.WeatherData{LocationID: locationID, Timestamp: now, Temperature: 60.0, Humidity: 50.0}
+
+	mockBouldersRepo := &MockBouldersRepository{
+		GetProfilesByIDsFn: func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.BoulderDryingProfile, error) {
+			return map[int64]*models.BoulderDryingProfile{
+				2001: {MPRouteID: 2001, TreeCoveragePercent: ptrFloat64(10.0)},
+			}, nil
+		},
+	}
+	mockWeatherRepo := &MockWeatherRepository{
+		GetCurrentFn: func(ctx context.Context, locID int) (*models.WeatherData, error) {
+			return currentWeather, nil
+		},
+	}
+	mockLocationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id, Name: "Test Location", Latitude: 47.6, Longitude: -120.9}, nil
+		},
+	}
+	primaryRockCalls := 0
+	mockRocksRepo := &MockRocksRepository{
+		GetRockTypesByLocationFn: func(ctx context.Context, locID int) ([]models.RockType, error) {
+			return []models.RockType{{Name: "Granite", BaseDryingHours: 12.0}}, nil
+		},
+		GetPrimaryRockTypeFn: func(ctx context.Context, locID int) (*models.RockType, error) {
+			primaryRockCalls++
+			return &models.RockType{Name: "Granite", BaseDryingHours: 12.0}, nil
+		},
+		GetSunExposureByLocationFn: func(ctx context.Context, locID int) (*models.LocationSunExposure, error) {
+			return &models.LocationSunExposure{NorthFacingPercent: 70, EastFacingPercent: 30}, nil
+		},
+	}
+	mockMPRepo := NewMockMountainProjectRepository()
+	mockMPRepo.routes.GetByIDsFn = func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.MPRoute, error) {
+		return routes, nil
+	}
+
+	service := NewBoulderDryingService(mockBouldersRepo, mockWeatherRepo, mockLocationsRepo, mockRocksRepo, mockMPRepo,
+		&mockBoulderWeatherClient{currentWeather: currentWeather})
+	results, err := service.GetBatchBoulderDryingStatus(context.Background(), []int64{2001, 2002, 2003})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if got := results[2001].Source; got != "profiled" {
+		t.Errorf("route 2001 Source = %q, want profiled", got)
+	}
+	for _, id := range []int64{2002, 2003} {
+		status := results[id]
+		if status.Source != "estimated" {
+			t.Errorf("route %d Source = %q, want estimated", id, status.Source)
+		}
+		if status.RockType != "Granite" {
+			t.Errorf("route %d RockType = %q, want Granite", id, status.RockType)
+		}
+		if status.TreeCoveragePercent != 30.0 {
+			t.Errorf("route %d TreeCoveragePercent = %.1f, want default 30", id, status.TreeCoveragePercent)
+		}
+	}
+	if got := results[2002].Aspect; got != "N" {
+		t.Errorf("route 2002 Aspect = %q, want location dominant aspect N", got)
+	}
+	if got := results[2003].Aspect; got != "E" {
+		t.Errorf("route 2003 Aspect = %q, want its own aspect E", got)
+	}
+	if primaryRockCalls != 1 {
+		t.Errorf("GetPrimaryRockType called %d times, want 1 per location", primaryRockCalls)
+	}
+}
+
+func TestShadeClockForStatus(t *testing.T) {
+	location := &models.Location{Latitude: 47.85, Longitude: -121.69, Timezone: "America/Los_Angeles"}
+	now := time.Date(2026, 6, 21, 19, 0, 0, 0, time.UTC) // noon PDT
+
+	t.Run("uses route GPS and location timezone", func(t *testing.T) {
+		status := &boulder_drying.BoulderDryingStatus{Aspect: "W", Latitude: 47.85, Longitude: -121.69}
+		clock := shadeClockForStatus(status, location, now)
+		if clock == nil {
+			t.Fatal("expected a shade clock")
+		}
+		if clock.Date != "2026-06-21" || clock.Aspect != "W" {
+			t.Errorf("clock = %+v", clock)
+		}
+		if clock.Summary != "Shaded until 1pm, then sun" {
+			t.Errorf("Summary = %q", clock.Summary)
+		}
+	})
+
+	t.Run("falls back to location coordinates", func(t *testing.T) {
+		status := &boulder_drying.BoulderDryingStatus{Aspect: "E"}
+		clock := shadeClockForStatus(status, location, now)
+		if clock == nil || clock.Summary != "Sun until 1pm, then shaded" {
+			t.Errorf("clock = %+v", clock)
+		}
+	})
+
+	t.Run("no coordinates", func(t *testing.T) {
+		status := &boulder_drying.BoulderDryingStatus{Aspect: "S"}
+		if clock := shadeClockForStatus(status, nil, now); clock != nil {
+			t.Errorf("expected nil without coordinates, got %+v", clock)
+		}
+	})
+}
+
 // Helper functions
+
 func ptrFloat64(f float64) *float64 {
 	return &f
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"html"
 	"log"
@@ -10,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/beta"
 	"github.com/alexscott64/woulder/backend/internal/database/climbing"
 	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
 	"github.com/alexscott64/woulder/backend/internal/models"
@@ -18,9 +20,20 @@ import (
 	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
 )
 
+// ErrRouteNotFound is returned by SyncSingleRoute when the route has not been
+// synced yet. Ticks and comments reference the route row, so its area must be
+// synced first.
+var ErrRouteNotFound = errors.New("route not found")
+
+// ErrPriorityRecalculationRunning is returned when an on-demand priority
+// recalculation is requested while another one is still running.
+var ErrPriorityRecalculationRunning = errors.New("priority recalculation already running")
+
 // MPClientInterface defines the interface for Mountain Project API operations
 type MPClientInterface interface {
 	GetRouteTicks(routeID string) ([]mpClient.Tick, error)
+	GetRouteTicksPaged(routeID string, visit func(page []mpClient.Tick) bool) error
+	GetUserTicksPaged(userID string, visit func(page []mpClient.UserTick) bool) error
 	GetRoute(routeID string) (*mpClient.RouteResponse, error)
 	GetArea(areaID string) (*mpClient.AreaResponse, error)
 	GetAreaComments(areaID string) ([]mpClient.Comment, error)
@@ -30,6 +43,19 @@ type MPClientInterface interface {
 // Ensure real Client implements the interface
 var _ MPClientInterface = (*mpClient.Client)(nil)
 
+// mpCommentsBatchClient is implemented by clients that can fetch comments for
+// several routes in one call. Clients without it (including test mocks) are
+// driven one route at a time by fetchRouteComments.
+type mpCommentsBatchClient interface {
+	GetRouteCommentsBatch(routeIDs []string) []mpClient.CommentsResult
+}
+
+var _ mpCommentsBatchClient = (*mpClient.Client)(nil)
+
+// commentBatchSize is how many routes' comments the comment-sync jobs fetch
+// per batch.
+const commentBatchSize = 20
+
 // AreaDiscoveryJobMonitor is the narrow subset of *monitoring.JobMonitor used
 // by SyncLocationAreaDiscovery. Defining it as an interface (rather than
 // reusing the concrete *monitoring.JobMonitor everywhere) lets tests inject
@@ -67,23 +93,64 @@ type ClimbTrackingService struct {
 	syncMutex            sync.Mutex
 	lastSyncTime         time.Time
 	isSyncing            bool
+
+	// tickEarlyStop makes SyncNewTicksForLocation page through ticks newest
+	// first and stop at the first tick it already has, instead of
+	// downloading a route's entire tick history. See SetTickEarlyStop.
+	tickEarlyStop bool
+
+	// stateSyncWorkers bounds how many states SyncNewRoutesForAllStates
+	// checks concurrently. See SetStateSyncWorkers.
+	stateSyncWorkers int
+
+	// priorityRecalcMu keeps on-demand priority recalculations from
+	// overlapping. See RunPriorityRecalculationJob.
+	priorityRecalcMu sync.Mutex
 }
 
-// NewClimbTrackingService creates a new climb tracking service
+// defaultStateSyncWorkers is kept low to respect Mountain Project limits.
+const defaultStateSyncWorkers = 3
+
+// NewClimbTrackingService creates a new climb tracking service. Every
+// Mountain Project request made by its syncs draws from budget, so the
+// aggregate request rate stays bounded when several syncs run back to back
+// or at once. A nil budget leaves only the client's own rate limit.
 func NewClimbTrackingService(
 	mountainProjectRepo mountainproject.Repository,
 	climbingRepo climbing.Repository,
 	mpClient MPClientInterface,
 	jobMonitor *monitoring.JobMonitor,
+	budget *mpClient.RequestBudget,
 ) *ClimbTrackingService {
 	return &ClimbTrackingService{
 		mountainProjectRepo: mountainProjectRepo,
 		climbingRepo:        climbingRepo,
-		mpClient:            mpClient,
+		mpClient:            withRequestBudget(mpClient, budget),
 		jobMonitor:          jobMonitor,
+		stateSyncWorkers:    defaultStateSyncWorkers,
 	}
 }
 
+// SetTickEarlyStop toggles newest-first paging with early stop for
+// incremental tick syncs (SyncNewTicksForLocation). Only enable this when the
+// Mountain Project tick API returns ticks newest first; otherwise new ticks
+// further down the list would be missed. Wire from MP_TICKS_NEWEST_FIRST.
+func (s *ClimbTrackingService) SetTickEarlyStop(enabled bool) {
+	s.tickEarlyStop = enabled
+}
+
+// SetStateSyncWorkers sets how many states SyncNewRoutesForAllStates checks
+// concurrently. Values below 1 fall back to defaultStateSyncWorkers. Workers
+// share the Mountain Project client, whose rate limit applies across all of
+// them, so this overlaps request latency and DB work without raising the
+// request rate. Wire from MP_STATE_SYNC_WORKERS.
+func (s *ClimbTrackingService) SetStateSyncWorkers(n int) {
+	if n < 1 {
+		n = defaultStateSyncWorkers
+	}
+	s.stateSyncWorkers = n
+}
+
 // areaDiscoveryJobMonitor returns the monitor used by
 // SyncLocationAreaDiscovery, preferring the test-injected interface when
 // set and falling back to the concrete *monitoring.JobMonitor otherwise.
@@ -166,6 +233,66 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 		s.syncMutex.Unlock()
 	}()
 
+	return s.syncAreaTree(ctx, rootAreaID, locationID, make(map[string]bool))
+}
+
+// AreaSyncRoot is a Mountain Project area for SyncAreaRoots to crawl and the
+// woulder location its areas and routes are assigned to (nil for none).
+type AreaSyncRoot struct {
+	LocationID *int  `json:"location_id"`
+	MPAreaID   int64 `json:"mp_area_id"`
+}
+
+// SyncAreaRoots crawls each root like SyncAreaRecursive, in order, as a
+// single sync. The roots share one set of processed areas, so a root listed
+// twice or nested inside an earlier root is skipped rather than crawled
+// again; its areas keep the location of the root that reached them first.
+// afterRoot, if non-nil, is called after each root with whether it was
+// skipped.
+func (s *ClimbTrackingService) SyncAreaRoots(
+	ctx context.Context,
+	roots []AreaSyncRoot,
+	afterRoot func(root AreaSyncRoot, skipped bool),
+) error {
+	s.syncMutex.Lock()
+	if s.isSyncing {
+		s.syncMutex.Unlock()
+		return fmt.Errorf("sync already in progress")
+	}
+	s.isSyncing = true
+	s.syncMutex.Unlock()
+
+	defer func() {
+		s.syncMutex.Lock()
+		s.isSyncing = false
+		s.lastSyncTime = time.Now()
+		s.syncMutex.Unlock()
+	}()
+
+	processedAreas := make(map[string]bool)
+	for _, root := range roots {
+		rootAreaID := strconv.FormatInt(root.MPAreaID, 10)
+		skipped := processedAreas[rootAreaID]
+		if skipped {
+			log.Printf("Skipping area %s: already synced from an earlier root", rootAreaID)
+		} else if err := s.syncAreaTree(ctx, rootAreaID, root.LocationID, processedAreas); err != nil {
+			return err
+		}
+		if afterRoot != nil {
+			afterRoot(root, skipped)
+		}
+	}
+	return nil
+}
+
+// syncAreaTree crawls the areas and routes under rootAreaID breadth-first,
+// skipping and adding to processedAreas. Callers must hold the sync flag.
+func (s *ClimbTrackingService) syncAreaTree(
+	ctx context.Context,
+	rootAreaID string,
+	locationID *int,
+	processedAreas map[string]bool,
+) error {
 	// Initialize breadth-first queue
 	queue := []areaQueueItem{{
 		mpAreaID:   rootAreaID,
@@ -173,7 +300,6 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 		parentID:   nil,
 	}}
 
-	processedAreas := make(map[string]bool)
 	routeCount := 0
 	areaCount := 0
 
@@ -210,6 +336,8 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 		if item.parentID != nil {
 			if parentID, err := strconv.ParseInt(*item.parentID, 10, 64); err == nil {
 				parentIDInt64 = &parentID
+			} else {
+				log.Printf("Warning: invalid parent area ID %q for area %s: %v", *item.parentID, areaIDStr, err)
 			}
 		}
 
@@ -223,12 +351,12 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 		}
 
 		// Extract GPS coordinates if available
-		if len(areaData.Coordinates) == 2 {
-			longitude := areaData.Coordinates[0]
-			latitude := areaData.Coordinates[1]
+		if latitude, longitude, ok := areaData.LatLon(); ok {
 			area.Longitude = &longitude
 			area.Latitude = &latitude
 			log.Printf("Area GPS: %.4f, %.4f", latitude, longitude)
+		} else {
+			log.Printf("Area %s has no usable coordinates (%v); saving without GPS", areaIDStr, areaData.Coordinates)
 		}
 
 		if err := s.mountainProjectRepo.Areas().SaveArea(ctx, area); err != nil {
@@ -248,6 +376,7 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 
 		// Collect boulder routes for GPS distribution
 		var boulderRoutes []int64 // Store route IDs for GPS calculation
+		var commentRouteIDs []string
 
 		// Process children
 		for _, child := range areaData.Children {
@@ -306,11 +435,19 @@ func (s *ClimbTrackingService) SyncAreaRecursive(
 					// Continue processing other routes even if tick sync fails
 				}
 
-				// Fetch and save comments for this route
-				if err := s.syncRouteComments(ctx, childIDStr); err != nil {
-					log.Printf("Warning: failed to sync comments for route %s: %v", childIDStr, err)
-					// Continue processing other routes even if comment sync fails
-				}
+				commentRouteIDs = append(commentRouteIDs, childIDStr)
+			}
+		}
+
+		// Fetch and save comments for this area's routes in one batch
+		for _, result := range s.fetchRouteComments(commentRouteIDs) {
+			if result.Err != nil {
+				log.Printf("Warning: failed to sync comments for route %s: failed to fetch comments: %v", result.ID, result.Err)
+				// Continue processing other routes even if comment sync fails
+				continue
+			}
+			if err := s.saveRouteComments(ctx, result.ID, result.Comments); err != nil {
+				log.Printf("Warning: failed to sync comments for route %s: %v", result.ID, err)
 			}
 		}
 
@@ -433,6 +570,7 @@ func (s *ClimbTrackingService) syncAreaComments(ctx context.Context, areaID stri
 
 	if commentCount > 0 {
 		log.Printf("Saved %d comments for area %s", commentCount, areaID)
+		s.refreshConditionsBeta(ctx, mountainproject.ConditionsBetaTargetArea, areaIDInt64)
 	}
 
 	return nil
@@ -444,7 +582,31 @@ func (s *ClimbTrackingService) syncRouteComments(ctx context.Context, routeID st
 	if err != nil {
 		return fmt.Errorf("failed to fetch comments: %w", err)
 	}
+	return s.saveRouteComments(ctx, routeID, comments)
+}
+
+// fetchRouteComments fetches comments for several routes, in one batch when
+// the client supports it and one request per route otherwise. Results are
+// in the order of routeIDs.
+func (s *ClimbTrackingService) fetchRouteComments(routeIDs []string) []mpClient.CommentsResult {
+	if len(routeIDs) == 0 {
+		return nil
+	}
+	if batch, ok := s.mpClient.(mpCommentsBatchClient); ok {
+		return batch.GetRouteCommentsBatch(routeIDs)
+	}
+
+	results := make([]mpClient.CommentsResult, len(routeIDs))
+	for i, routeID := range routeIDs {
+		comments, err := s.mpClient.GetRouteComments(routeID)
+		results[i] = mpClient.CommentsResult{ID: routeID, Comments: comments, Err: err}
+	}
+	return results
+}
 
+// saveRouteComments saves fetched comments for a route and refreshes its
+// conditions beta when any were saved.
+func (s *ClimbTrackingService) saveRouteComments(ctx context.Context, routeID string, comments []mpClient.Comment) error {
 	// Convert route ID string to int64
 	routeIDInt64, err := strconv.ParseInt(routeID, 10, 64)
 	if err != nil {
@@ -465,6 +627,111 @@ func (s *ClimbTrackingService) syncRouteComments(ctx context.Context, routeID st
 
 	if commentCount > 0 {
 		log.Printf("Saved %d comments for route %s", commentCount, routeID)
+		s.refreshConditionsBeta(ctx, mountainproject.ConditionsBetaTargetRoute, routeIDInt64)
+	}
+
+	return nil
+}
+
+// refreshConditionsBeta re-extracts conditions beta from a target's stored
+// comments and saves the tags. For routes it also refreshes the
+// comment-sourced aspect. Failures are logged, not returned: beta is
+// derived data and must not fail the comment sync that triggered it.
+func (s *ClimbTrackingService) refreshConditionsBeta(ctx context.Context, targetType string, mpID int64) {
+	comments := s.mountainProjectRepo.Comments()
+
+	var texts []string
+	var err error
+	if targetType == mountainproject.ConditionsBetaTargetArea {
+		texts, err = comments.GetAreaCommentTexts(ctx, mpID)
+	} else {
+		texts, err = comments.GetRouteCommentTexts(ctx, mpID)
+	}
+	if err != nil {
+		log.Printf("Error loading comments for %s %d conditions beta: %v", targetType, mpID, err)
+		return
+	}
+
+	if err := comments.SaveConditionsBeta(ctx, targetType, mpID, beta.Extract(texts), len(texts)); err != nil {
+		log.Printf("Error saving conditions beta for %s %d: %v", targetType, mpID, err)
+	}
+
+	if targetType == mountainproject.ConditionsBetaTargetRoute {
+		s.refreshCommentAspect(ctx, mpID, texts)
+	}
+}
+
+// BackfillConditionsBeta extracts conditions beta for every area and route
+// whose comments were stored without it (before migration 000045, or when
+// the extraction failed). Extraction otherwise only runs when a target's
+// comments are re-synced. It returns the number of targets processed; with
+// dryRun it only counts them.
+func (s *ClimbTrackingService) BackfillConditionsBeta(ctx context.Context, dryRun bool) (int, error) {
+	processed := 0
+	for _, targetType := range []string{mountainproject.ConditionsBetaTargetArea, mountainproject.ConditionsBetaTargetRoute} {
+		ids, err := s.mountainProjectRepo.Comments().GetTargetsMissingConditionsBeta(ctx, targetType)
+		if err != nil {
+			return processed, fmt.Errorf("failed to list %ss missing conditions beta: %w", targetType, err)
+		}
+		log.Printf("Found %d %ss with comments but no conditions beta", len(ids), targetType)
+		if dryRun {
+			processed += len(ids)
+			continue
+		}
+
+		for _, id := range ids {
+			if err := ctx.Err(); err != nil {
+				return processed, err
+			}
+			s.refreshConditionsBeta(ctx, targetType, id)
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// refreshCommentAspect overrides a route's computed aspect when its comments
+// state one confidently, and reverts an earlier override when they no
+// longer do.
+func (s *ClimbTrackingService) refreshCommentAspect(ctx context.Context, routeID int64, texts []string) {
+	routes := s.mountainProjectRepo.Routes()
+
+	var err error
+	if hint := beta.ExtractAspect(texts); hint != nil {
+		err = routes.SetCommentAspect(ctx, routeID, hint.Aspect, hint.Evidence)
+	} else {
+		err = routes.ClearCommentAspect(ctx, routeID)
+	}
+	if err != nil {
+		log.Printf("Error saving comment aspect for route %d: %v", routeID, err)
+	}
+}
+
+// SyncSingleRoute fetches and saves the ticks and comments for one route
+// without traversing its area. Intended for spot-fixing a route's data.
+// Comments are still synced when the tick sync fails; both errors are
+// returned together.
+func (s *ClimbTrackingService) SyncSingleRoute(ctx context.Context, routeID int64) error {
+	route, err := s.mountainProjectRepo.Routes().GetByID(ctx, routeID)
+	if err != nil {
+		return fmt.Errorf("failed to load route %d: %w", routeID, err)
+	}
+	if route == nil {
+		return fmt.Errorf("route %d: %w", routeID, ErrRouteNotFound)
+	}
+
+	log.Printf("Syncing route: %s (%d)", route.Name, routeID)
+	routeIDStr := strconv.FormatInt(routeID, 10)
+
+	var errs []error
+	if err := s.syncRouteTicks(ctx, routeIDStr); err != nil {
+		errs = append(errs, fmt.Errorf("ticks: %w", err))
+	}
+	if err := s.syncRouteComments(ctx, routeIDStr); err != nil {
+		errs = append(errs, fmt.Errorf("comments: %w", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to sync route %d: %w", routeID, errors.Join(errs...))
 	}
 
 	return nil
@@ -504,7 +771,53 @@ func (s *ClimbTrackingService) GetAreasOrderedByActivity(
 	ctx context.Context,
 	locationID int,
 ) ([]models.AreaActivitySummary, error) {
-	return s.climbingRepo.Activity().GetAreasOrderedByActivity(ctx, locationID)
+	areas, err := s.climbingRepo.Activity().GetAreasOrderedByActivity(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	return areas, s.markClosedAreas(ctx, areas)
+}
+
+// GetAreaTree retrieves a location's full area hierarchy with per-area
+// activity in a single query. Top-level nodes follow the same rule as
+// GetAreasOrderedByActivity: when the location has a single root area its
+// children are returned, otherwise the roots themselves.
+func (s *ClimbTrackingService) GetAreaTree(ctx context.Context, locationID int) ([]*models.AreaTreeNode, error) {
+	areas, err := s.climbingRepo.Activity().GetAreaTreeWithActivity(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get area tree for location %d: %w", locationID, err)
+	}
+	if err := s.markClosedAreas(ctx, areas); err != nil {
+		return nil, err
+	}
+	return buildAreaTree(areas), nil
+}
+
+// buildAreaTree nests flat area rows under their parents, keeping the input
+// order among siblings. Areas whose parent is not in the set are roots; a
+// single root is replaced by its children.
+func buildAreaTree(areas []models.AreaActivitySummary) []*models.AreaTreeNode {
+	nodes := make(map[int64]*models.AreaTreeNode, len(areas))
+	for _, area := range areas {
+		nodes[area.MPAreaID] = &models.AreaTreeNode{AreaActivitySummary: area, Subareas: []*models.AreaTreeNode{}}
+	}
+
+	roots := []*models.AreaTreeNode{}
+	for _, area := range areas {
+		node := nodes[area.MPAreaID]
+		if area.ParentMPAreaID != nil {
+			if parent, ok := nodes[*area.ParentMPAreaID]; ok && parent != node {
+				parent.Subareas = append(parent.Subareas, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	if len(roots) == 1 {
+		return roots[0].Subareas
+	}
+	return roots
 }
 
 // GetSubareasOrderedByActivity retrieves subareas of a parent area ordered by recent climb activity
@@ -513,7 +826,11 @@ func (s *ClimbTrackingService) GetSubareasOrderedByActivity(
 	parentAreaID int64,
 	locationID int,
 ) ([]models.AreaActivitySummary, error) {
-	return s.climbingRepo.Activity().GetSubareasOrderedByActivity(ctx, parentAreaID, locationID)
+	areas, err := s.climbingRepo.Activity().GetSubareasOrderedByActivity(ctx, parentAreaID, locationID)
+	if err != nil {
+		return nil, err
+	}
+	return areas, s.markClosedAreas(ctx, areas)
 }
 
 // GetRoutesOrderedByActivity retrieves routes in an area ordered by recent climb activity
@@ -523,7 +840,11 @@ func (s *ClimbTrackingService) GetRoutesOrderedByActivity(
 	locationID int,
 	limit int,
 ) ([]models.RouteActivitySummary, error) {
-	return s.climbingRepo.Activity().GetRoutesOrderedByActivity(ctx, areaID, locationID, limit)
+	routes, err := s.climbingRepo.Activity().GetRoutesOrderedByActivity(ctx, areaID, locationID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return routes, s.markClosedRoutes(ctx, routes)
 }
 
 // GetRecentTicksForRoute retrieves recent ticks for a specific route
@@ -535,6 +856,57 @@ func (s *ClimbTrackingService) GetRecentTicksForRoute(
 	return s.climbingRepo.Activity().GetRecentTicksForRoute(ctx, routeID, limit)
 }
 
+// Trending windows accepted by GetTrendingRoutes.
+const (
+	TrendingWindowWeek  = "week"
+	TrendingWindowMonth = "month"
+)
+
+// ErrInvalidTrendingWindow is returned by GetTrendingRoutes for a window
+// other than TrendingWindowWeek or TrendingWindowMonth.
+var ErrInvalidTrendingWindow = errors.New("invalid trending window")
+
+// GetTrendingRoutes ranks routes by tick volume over the last week or month,
+// optionally scoped to a location (nil for all locations).
+func (s *ClimbTrackingService) GetTrendingRoutes(
+	ctx context.Context,
+	window string,
+	locationID *int,
+	limit int,
+) ([]models.TrendingRoute, error) {
+	var since time.Time
+	switch window {
+	case TrendingWindowWeek:
+		since = time.Now().AddDate(0, 0, -7)
+	case TrendingWindowMonth:
+		since = time.Now().AddDate(0, -1, 0)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTrendingWindow, window)
+	}
+	return s.climbingRepo.Activity().GetTrendingRoutes(ctx, since, locationID, limit)
+}
+
+// GetRecentlyDiscoveredRoutes returns routes the new-route sweep found within
+// the given number of days, newest first.
+func (s *ClimbTrackingService) GetRecentlyDiscoveredRoutes(
+	ctx context.Context,
+	days int,
+	limit int,
+) ([]models.DiscoveredRoute, error) {
+	since := time.Now().AddDate(0, 0, -days)
+	return s.climbingRepo.Activity().GetRecentlyDiscoveredRoutes(ctx, since, limit)
+}
+
+// GetDailyTickCounts counts a location's ticks per Pacific local day between
+// two YYYY-MM-DD dates, inclusive. Days without ticks are omitted.
+func (s *ClimbTrackingService) GetDailyTickCounts(
+	ctx context.Context,
+	locationID int,
+	startDate, endDate string,
+) ([]models.DailyTickCount, error) {
+	return s.climbingRepo.Activity().GetDailyTickCounts(ctx, locationID, startDate, endDate)
+}
+
 // SearchInLocation searches all areas and routes in a location by name
 func (s *ClimbTrackingService) SearchInLocation(
 	ctx context.Context,
@@ -552,7 +924,11 @@ func (s *ClimbTrackingService) SearchRoutesInLocation(
 	searchQuery string,
 	limit int,
 ) ([]models.RouteActivitySummary, error) {
-	return s.climbingRepo.Search().SearchRoutesInLocation(ctx, locationID, searchQuery, limit)
+	routes, err := s.climbingRepo.Search().SearchRoutesInLocation(ctx, locationID, searchQuery, limit)
+	if err != nil {
+		return nil, err
+	}
+	return routes, s.markClosedRoutes(ctx, routes)
 }
 
 // GetSyncStatus returns the current sync status
@@ -564,11 +940,14 @@ func (s *ClimbTrackingService) GetSyncStatus() (isSyncing bool, lastSync time.Ti
 
 // SyncNewTicksForLocation performs incremental sync of only new ticks for a location
 // This is much more efficient than full sync as it only fetches ticks newer than the last known tick
-func (s *ClimbTrackingService) SyncNewTicksForLocation(ctx context.Context, locationID int) error {
+func (s *ClimbTrackingService) SyncNewTicksForLocation(ctx context.Context, locationID int) (*SyncResult, error) {
+	startTime := time.Now()
+	result := &SyncResult{}
+
 	s.syncMutex.Lock()
 	if s.isSyncing {
 		s.syncMutex.Unlock()
-		return fmt.Errorf("sync already in progress")
+		return result, fmt.Errorf("sync already in progress")
 	}
 	s.isSyncing = true
 	s.syncMutex.Unlock()
@@ -583,12 +962,12 @@ func (s *ClimbTrackingService) SyncNewTicksForLocation(ctx context.Context, loca
 	// Get all route IDs for this location
 	routeIDs, err := s.mountainProjectRepo.Routes().GetAllIDsForLocation(ctx, locationID)
 	if err != nil {
-		return fmt.Errorf("failed to get route IDs: %w", err)
+		return result, fmt.Errorf("failed to get route IDs: %w", err)
 	}
 
 	if len(routeIDs) == 0 {
 		log.Printf("No routes found for location %d, skipping tick sync", locationID)
-		return nil
+		return result, nil
 	}
 
 	log.Printf("Starting incremental tick sync for location %d (%d routes)", locationID, len(routeIDs))
@@ -600,14 +979,12 @@ func (s *ClimbTrackingService) SyncNewTicksForLocation(ctx context.Context, loca
 		pacificTZ = time.UTC
 	}
 
-	totalNewTicks := 0
-	routesProcessed := 0
-
 	// For each route, sync only new ticks
 	for _, routeID := range routeIDs {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			result.Duration = time.Since(startTime)
+			return result, ctx.Err()
 		default:
 		}
 
@@ -615,80 +992,143 @@ func (s *ClimbTrackingService) SyncNewTicksForLocation(ctx context.Context, loca
 		lastTickTime, err := s.mountainProjectRepo.Ticks().GetLastTimestampForRoute(ctx, routeID)
 		if err != nil {
 			log.Printf("Error getting last tick for route %d: %v", routeID, err)
+			result.routeFailed()
 			continue
 		}
 
 		// Fetch ticks from Mountain Project (API requires string)
 		routeIDStr := strconv.FormatInt(routeID, 10)
-		ticks, err := s.mpClient.GetRouteTicks(routeIDStr)
-		if err != nil {
-			log.Printf("Error fetching ticks for route %d: %v", routeID, err)
-			continue
-		}
-
 		newTickCount := 0
-		for _, tick := range ticks {
-			// Parse the date in Pacific timezone
-			var climbedAt time.Time
-			climbedAt, err = time.ParseInLocation("Jan 2, 2006, 3:04 pm", tick.Date, pacificTZ)
+		if s.tickEarlyStop && lastTickTime != nil {
+			// Newest-first: stop paging once we reach a tick we already have.
+			// Nothing is saved until paging finishes, so a failed page can't
+			// advance the route's last tick past ticks we never fetched.
+			var fetched []mpClient.Tick
+			err = s.mpClient.GetRouteTicksPaged(routeIDStr, func(page []mpClient.Tick) bool {
+				fetched = append(fetched, page...)
+				return !reachesKnownTick(page, lastTickTime, pacificTZ)
+			})
 			if err != nil {
-				climbedAt, err = time.ParseInLocation("2006-01-02 15:04:05", tick.Date, pacificTZ)
-				if err != nil {
-					climbedAt, err = time.ParseInLocation("2006-01-02", tick.Date, pacificTZ)
-					if err != nil {
-						log.Printf("Warning: invalid date format for tick on route %d: %s", routeID, tick.Date)
-						continue
-					}
-				}
+				log.Printf("Error fetching ticks for route %d: %v", routeID, err)
+				result.routeFailed()
+				continue
 			}
-
-			// Skip ticks with future dates (data quality issue)
-			if !isTickDateValid(climbedAt) {
-				log.Printf("Warning: skipping tick with future date on route %d: %s", routeID, tick.Date)
+			// Save oldest first so an interrupted save never leaves a gap
+			// behind the newest saved tick
+			for i, j := 0, len(fetched)-1; i < j; i, j = i+1, j-1 {
+				fetched[i], fetched[j] = fetched[j], fetched[i]
+			}
+			newTickCount = s.saveNewTicks(ctx, routeID, fetched, lastTickTime, pacificTZ)
+		} else {
+			ticks, err := s.mpClient.GetRouteTicks(routeIDStr)
+			if err != nil {
+				log.Printf("Error fetching ticks for route %d: %v", routeID, err)
+				result.routeFailed()
 				continue
 			}
+			newTickCount = s.saveNewTicks(ctx, routeID, ticks, lastTickTime, pacificTZ)
+		}
 
-			// Skip if we already have this tick (incremental check)
-			if lastTickTime != nil && !climbedAt.After(*lastTickTime) {
-				continue // Already have this tick or older
-			}
+		result.routeSynced(newTickCount)
+	}
 
-			tickModel := &models.MPTick{
-				MPRouteID: routeID,
-				UserName:  tick.GetUserName(),
-				ClimbedAt: climbedAt,
-				Style:     tick.Style,
-			}
+	result.Duration = time.Since(startTime)
+	s.recordSyncSummary(ctx, nil, nil, &monitoring.SyncSummary{
+		JobName:        fmt.Sprintf("location_%d_incremental_tick_sync", locationID),
+		JobType:        "tick_sync",
+		StartedAt:      startTime,
+		ItemsProcessed: result.RoutesProcessed,
+		ItemsSucceeded: result.RoutesProcessed - result.Failures,
+		ItemsFailed:    result.Failures,
+		NewTicks:       result.ItemsAdded,
+	}, nil)
 
-			textStr := tick.GetTextString()
-			if textStr != "" {
-				cleanedText := cleanCommentText(textStr)
-				if cleanedText != "" {
-					tickModel.Comment = &cleanedText
-				}
-			}
+	log.Printf("Incremental sync complete for location %d: %v", locationID, result)
 
-			if err := s.mountainProjectRepo.Ticks().SaveTick(ctx, tickModel); err != nil {
-				log.Printf("Error saving tick for route %d: %v", routeID, err)
-				continue
-			}
+	return result, nil
+}
 
-			newTickCount++
+// parseTickDate parses a Mountain Project tick date in Pacific time,
+// accepting the display format and the two ISO-style fallbacks the API uses.
+func parseTickDate(date string, pacificTZ *time.Location) (time.Time, error) {
+	climbedAt, err := time.ParseInLocation("Jan 2, 2006, 3:04 pm", date, pacificTZ)
+	if err == nil {
+		return climbedAt, nil
+	}
+	climbedAt, err = time.ParseInLocation("2006-01-02 15:04:05", date, pacificTZ)
+	if err == nil {
+		return climbedAt, nil
+	}
+	return time.ParseInLocation("2006-01-02", date, pacificTZ)
+}
+
+// reachesKnownTick reports whether page contains a tick at-or-before
+// lastTickTime, meaning older pages hold nothing new.
+func reachesKnownTick(page []mpClient.Tick, lastTickTime *time.Time, pacificTZ *time.Location) bool {
+	for _, tick := range page {
+		climbedAt, err := parseTickDate(tick.Date, pacificTZ)
+		if err != nil {
+			continue
+		}
+		if !climbedAt.After(*lastTickTime) {
+			return true
 		}
+	}
+	return false
+}
 
-		if newTickCount > 0 {
-			totalNewTicks += newTickCount
-			// Verbose per-route logging disabled - see summary log below
-			// log.Printf("Route %d: %d new ticks", routeID, newTickCount)
+// saveNewTicks saves the ticks newer than lastTickTime (all ticks when nil)
+// in the order given and returns how many were saved.
+func (s *ClimbTrackingService) saveNewTicks(
+	ctx context.Context,
+	routeID int64,
+	ticks []mpClient.Tick,
+	lastTickTime *time.Time,
+	pacificTZ *time.Location,
+) (saved int) {
+	for _, tick := range ticks {
+		// Parse the date in Pacific timezone
+		climbedAt, err := parseTickDate(tick.Date, pacificTZ)
+		if err != nil {
+			log.Printf("Warning: invalid date format for tick on route %d: %s", routeID, tick.Date)
+			continue
 		}
 
-		routesProcessed++
-	}
+		// Skip ticks with future dates (data quality issue)
+		if !isTickDateValid(climbedAt) {
+			log.Printf("Warning: skipping tick with future date on route %d: %s", routeID, tick.Date)
+			continue
+		}
 
-	log.Printf("Incremental sync complete for location %d: %d new ticks across %d routes",
-		locationID, totalNewTicks, routesProcessed)
+		// Skip if we already have this tick (incremental check)
+		if lastTickTime != nil && !climbedAt.After(*lastTickTime) {
+			continue // Already have this tick or older
+		}
 
-	return nil
+		tickModel := &models.MPTick{
+			MPRouteID: routeID,
+			UserName:  tick.GetUserName(),
+			ClimbedAt: climbedAt,
+			Style:     tick.Style,
+		}
+
+		textStr := tick.GetTextString()
+		if textStr != "" {
+			cleanedText := cleanCommentText(textStr)
+			if cleanedText != "" {
+				tickModel.Comment = &cleanedText
+			}
+		}
+
+		if err := s.mountainProjectRepo.Ticks().SaveTick(ctx, tickModel); err != nil {
+			log.Printf("Error saving tick for route %d: %v", routeID, err)
+			continue
+		}
+
+		saved++
+	}
+
+	return saved
 }
 
 // SyncNewTicksForAllLocations performs incremental sync for all locations with MP data
@@ -704,7 +1144,7 @@ func (s *ClimbTrackingService) SyncNewTicksForAllLocations(ctx context.Context)
 
 	for _, locationID := range locationIDs {
 		log.Printf("Syncing location %d...", locationID)
-		if err := s.SyncNewTicksForLocation(ctx, locationID); err != nil {
+		if _, err := s.SyncNewTicksForLocation(ctx, locationID); err != nil {
 			log.Printf("Error syncing location %d: %v", locationID, err)
 			failCount++
 		} else {
@@ -761,6 +1201,7 @@ func (s *ClimbTrackingService) calculateBoulderGPS(
 // Uses smart binary-search traversal to minimize API calls
 // Supports checkpoint-based resume for Air hot-reload recovery
 func (s *ClimbTrackingService) SyncNewRoutesForAllStates(ctx context.Context) error {
+	startTime := time.Now()
 	log.Println("Starting new route sync for all states...")
 
 	// STEP 1: Check for interrupted job from previous run
@@ -834,64 +1275,137 @@ func (s *ClimbTrackingService) SyncNewRoutesForAllStates(ctx context.Context) er
 	successCount := startIndex
 	failCount := 0
 
-	// STEP 4: Process states starting from checkpoint
-	for i := startIndex; i < len(states); i++ {
-		state := states[i]
+	// STEP 4: Process states starting from checkpoint across a bounded
+	// worker pool. Results are aggregated on this goroutine only, so the
+	// counters and checkpoint need no extra locking.
+	workers := s.stateSyncWorkers
+	if workers < 1 {
+		workers = defaultStateSyncWorkers
+	}
+
+	type stateResult struct {
+		index     int
+		newRoutes int
+		err       error
+	}
+
+	indices := make(chan int)
+	results := make(chan stateResult)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				state := states[i]
+				log.Printf("Checking state: %s (MP Area ID: %s) [%d/%d]", state.StateName, state.MPAreaID, i+1, len(states))
+				newRoutes, err := s.checkAreaForNewRoutes(ctx, state.MPAreaID)
+				results <- stateResult{index: i, newRoutes: newRoutes, err: err}
+			}
+		}()
+	}
 
-		// Check for cancellation
-		select {
-		case <-ctx.Done():
-			if jobExec != nil {
-				s.jobMonitor.MarkJobPaused(ctx, jobExec.ID)
-				log.Printf("Job paused due to context cancellation at state %d/%d", i, len(states))
+	go func() {
+		defer close(indices)
+		for i := startIndex; i < len(states); i++ {
+			select {
+			case indices <- i:
+			case <-ctx.Done():
+				return
 			}
-			return ctx.Err()
-		default:
 		}
+	}()
 
-		log.Printf("Checking state: %s (MP Area ID: %s) [%d/%d]", state.StateName, state.MPAreaID, i+1, len(states))
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
 
-		newRoutes, err := s.checkAreaForNewRoutes(ctx, state.MPAreaID)
-		if err != nil {
-			log.Printf("Error checking %s: %v", state.StateName, err)
-			failCount++
+	// States finish out of order, so the checkpoint cursor only advances over
+	// the contiguous prefix of finished states. A resumed run may re-check a
+	// few states that had already finished, but never skips one that had not.
+	finished := make(map[int]bool)
+	cursor := startIndex
+
+	for res := range results {
+		state := states[res.index]
+
+		if res.err != nil && ctx.Err() != nil {
+			// Interrupted by cancellation: leave the state for the resumed run.
 			continue
 		}
 
-		if newRoutes > 0 {
-			log.Printf("✓ %s: Found and synced %d new route(s)", state.StateName, newRoutes)
-			totalNewRoutes += newRoutes
+		if res.err != nil {
+			log.Printf("Error checking %s: %v", state.StateName, res.err)
+			failCount++
+		} else {
+			if res.newRoutes > 0 {
+				log.Printf("✓ %s: Found and synced %d new route(s)", state.StateName, res.newRoutes)
+				totalNewRoutes += res.newRoutes
+			}
+			successCount++
 		}
 
-		successCount++
+		finished[res.index] = true
+		advanced := false
+		for finished[cursor] {
+			delete(finished, cursor)
+			cursor++
+			advanced = true
+		}
 
-		// STEP 5: Save checkpoint after EVERY state (critical for Air hot-reload).
-		// Payload is intentionally small (~5 keys, no arrays) so this UPDATE
-		// stays well under the TOAST threshold and gets HOT-updated in place.
-		// See job_monitor.SaveCheckpoint for the jsonb_set rationale.
-		if jobExec != nil {
+		// STEP 5: Save checkpoint whenever the cursor advances (critical for
+		// Air hot-reload). Payload is intentionally small (~5 keys, no
+		// arrays) so this UPDATE stays well under the TOAST threshold and
+		// gets HOT-updated in place. See job_monitor.SaveCheckpoint for the
+		// jsonb_set rationale.
+		if jobExec != nil && advanced && ctx.Err() == nil {
 			checkpointData := map[string]interface{}{
-				"current_state_index": i + 1,
-				"current_state_name":  state.StateName,
+				"current_state_index": cursor,
+				"current_state_name":  states[cursor-1].StateName,
 				"total_new_routes":    totalNewRoutes,
-				"states_remaining":    len(states) - (i + 1),
+				"states_remaining":    len(states) - cursor,
 			}
 
 			if err := s.jobMonitor.SaveCheckpoint(ctx, jobExec.ID, checkpointData); err != nil {
 				log.Printf("Warning: failed to save checkpoint: %v", err)
 			} else {
 				// Only log every 5 states to avoid log spam
-				if (i+1)%5 == 0 || i == 0 {
-					log.Printf("Checkpoint saved: %d/%d states complete", i+1, len(states))
+				if cursor%5 == 0 || cursor == 1 {
+					log.Printf("Checkpoint saved: %d/%d states complete", cursor, len(states))
 				}
 			}
 		}
 	}
 
+	// Check for cancellation
+	if ctx.Err() != nil {
+		if jobExec != nil {
+			s.jobMonitor.MarkJobPaused(ctx, jobExec.ID)
+			log.Printf("Job paused due to context cancellation at state %d/%d", cursor, len(states))
+		}
+		return ctx.Err()
+	}
+
 	// STEP 6: Complete job
 	log.Printf("New route sync complete: %d states checked, %d new routes found, %d failures",
 		successCount, totalNewRoutes, failCount)
 
+	var summaryErr error
+	if failCount > 0 {
+		summaryErr = fmt.Errorf("sync completed with %d failures", failCount)
+	}
+	s.recordSyncSummary(ctx, jobExec, nil, &monitoring.SyncSummary{
+		JobName:        "route_sync_all_states",
+		JobType:        "route_sync",
+		StartedAt:      startTime,
+		ItemsProcessed: successCount + failCount,
+		ItemsSucceeded: successCount,
+		ItemsFailed:    failCount,
+		NewRoutes:      totalNewRoutes,
+	}, summaryErr)
+
 	if jobExec != nil {
 		if failCount > 0 {
 			errMsg := fmt.Sprintf("sync completed with %d failures", failCount)
@@ -1049,9 +1563,7 @@ func (s *ClimbTrackingService) syncNewRoutesInArea(ctx context.Context, areaID s
 
 	// Get GPS coordinates from area
 	var lat, lon *float64
-	if len(areaResp.Coordinates) >= 2 {
-		latitude := areaResp.Coordinates[1] // [longitude, latitude]
-		longitude := areaResp.Coordinates[0]
+	if latitude, longitude, ok := areaResp.LatLon(); ok {
 		lat = &latitude
 		lon = &longitude
 	}
@@ -1074,6 +1586,9 @@ func (s *ClimbTrackingService) syncNewRoutesInArea(ctx context.Context, areaID s
 			log.Printf("Error syncing route %s: %v", routeID, err)
 			continue
 		}
+		if err := s.mountainProjectRepo.Routes().MarkDiscovered(ctx, routeIDInt64); err != nil {
+			log.Printf("Warning: failed to record discovery time for route %s: %v", routeID, err)
+		}
 
 		// Fetch and sync ticks
 		ticks, err := s.mpClient.GetRouteTicks(routeID)
@@ -1129,6 +1644,9 @@ func (s *ClimbTrackingService) syncNewRoutesInArea(ctx context.Context, areaID s
 					log.Printf("Warning: failed to insert comment for route %s: %v", routeID, err)
 				}
 			}
+			if len(comments) > 0 {
+				s.refreshConditionsBeta(ctx, mountainproject.ConditionsBetaTargetRoute, routeIDInt64)
+			}
 		}
 
 		syncedCount++
@@ -1153,25 +1671,84 @@ func (s *ClimbTrackingService) RecalculateAllPriorities(ctx context.Context) err
 		log.Printf("Warning: failed to get priority distribution: %v", err)
 	} else {
 		log.Printf("Priority recalculation complete: high=%d, medium=%d, low=%d [non-location routes]",
-			distribution["high"], distribution["medium"], distribution["low"])
+			distribution[string(mountainproject.PriorityHigh)], distribution[string(mountainproject.PriorityMedium)], distribution[string(mountainproject.PriorityLow)])
 	}
 
 	return nil
 }
 
+// PriorityRecalculationResult is the outcome of an on-demand priority
+// recalculation
+type PriorityRecalculationResult struct {
+	JobID        *int64         `json:"job_id,omitempty"` // nil when job monitoring was unavailable
+	Distribution map[string]int `json:"distribution"`     // Route count per priority tier
+}
+
+// RunPriorityRecalculationJob runs RecalculateAllPriorities as a tracked
+// "priority_recalculation" job and returns the new priority distribution.
+// Returns ErrPriorityRecalculationRunning if a recalculation is in progress.
+func (s *ClimbTrackingService) RunPriorityRecalculationJob(ctx context.Context) (*PriorityRecalculationResult, error) {
+	if !s.priorityRecalcMu.TryLock() {
+		return nil, ErrPriorityRecalculationRunning
+	}
+	defer s.priorityRecalcMu.Unlock()
+
+	var jobExec *monitoring.JobExecution
+	if s.jobMonitor != nil {
+		var err error
+		jobExec, err = s.jobMonitor.StartJob(ctx, "priority_recalculation", "priority_recalculation", 0, map[string]interface{}{
+			"trigger": "manual",
+		})
+		if err != nil {
+			log.Printf("Warning: failed to start job monitoring: %v", err)
+			jobExec = nil
+		}
+	}
+
+	if err := s.RecalculateAllPriorities(ctx); err != nil {
+		if jobExec != nil {
+			if failErr := s.jobMonitor.FailJob(ctx, jobExec.ID, err.Error()); failErr != nil {
+				log.Printf("Warning: failed to mark job as failed: %v", failErr)
+			}
+		}
+		return nil, err
+	}
+
+	if jobExec != nil {
+		if err := s.jobMonitor.CompleteJob(ctx, jobExec.ID); err != nil {
+			log.Printf("Warning: failed to complete job: %v", err)
+		}
+	}
+
+	distribution, err := s.mountainProjectRepo.Sync().GetPriorityDistribution(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get priority distribution: %w", err)
+	}
+
+	result := &PriorityRecalculationResult{Distribution: distribution}
+	if jobExec != nil {
+		result.JobID = &jobExec.ID
+	}
+	return result, nil
+}
+
 // SyncLocationRouteTicks syncs ticks for ALL routes with location_id (woulder locations - always daily)
-func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error {
+//
+// When resuming an interrupted run, the result covers only the routes synced
+// by this call.
+func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) (*SyncResult, error) {
 	startTime := time.Now()
+	result := &SyncResult{}
 
 	// Get ALL location routes due for tick sync
 	routeIDs, err := s.mountainProjectRepo.Sync().GetLocationRoutesDueForSync(ctx, "ticks")
 	if err != nil {
-		return fmt.Errorf("failed to get location routes for tick sync: %w", err)
+		return result, fmt.Errorf("failed to get location routes for tick sync: %w", err)
 	}
 
 	if len(routeIDs) == 0 {
 		log.Println("No location routes due for tick sync")
-		return nil
+		return result, nil
 	}
 
 	// STEP 1: Check for interrupted job from previous run
@@ -1254,9 +1831,13 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 		pacificTZ = time.UTC
 	}
 
+	// Stop between routes if an operator cancels the job
+	syncCtx, stopWatching := s.watchForCancel(ctx, jobExec)
+	defer stopWatching()
+
 	// STEP 3: Process routes starting from checkpoint
 	routeIndex := 0
-	err = s.rateLimitedSync(ctx, routeIDs, func(routeID string) error {
+	err = s.rateLimitedSync(syncCtx, routeIDs, func(routeID string) error {
 		currentIndex := routeIndex
 		routeIndex++
 
@@ -1293,6 +1874,7 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 
 		lastTickTime, tickErr := s.mountainProjectRepo.Ticks().GetLastTimestampForRoute(ctx, routeIDInt64)
 		if tickErr != nil {
+			result.routeFailed()
 			// Report progress
 			if reporter != nil {
 				reporter.Increment(ctx, false)
@@ -1303,6 +1885,7 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 		// Fetch ticks from MP API
 		ticks, tickErr := s.mpClient.GetRouteTicks(routeID)
 		if tickErr != nil {
+			result.routeFailed()
 			// Report progress
 			if reporter != nil {
 				reporter.Increment(ctx, false)
@@ -1360,6 +1943,7 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 			newTickCount++
 		}
 		totalNewTicks += newTickCount
+		result.routeSynced(newTickCount)
 
 		// STEP 4: Save checkpoint every 250 routes (was 50). Each save is
 		// now a tiny jsonb_set UPDATE on a ~80-byte payload, but it still
@@ -1390,7 +1974,7 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 		return nil
 	})
 
-	duration := time.Since(startTime)
+	result.Duration = time.Since(startTime)
 
 	// COMPLETE MONITORING: Mark job as completed or failed
 	if jobExec != nil {
@@ -1401,14 +1985,20 @@ func (s *ClimbTrackingService) SyncLocationRouteTicks(ctx context.Context) error
 		}
 	}
 
+	s.recordSyncSummary(ctx, jobExec, reporter, &monitoring.SyncSummary{
+		JobName:   jobName,
+		JobType:   "location_tick_sync",
+		StartedAt: startTime,
+		NewTicks:  totalNewTicks,
+	}, err)
+
 	if err != nil {
-		return fmt.Errorf("location route tick sync error: %w", err)
+		return result, fmt.Errorf("location route tick sync error: %w", err)
 	}
 
-	log.Printf("Location route tick sync complete: %d routes, %d new ticks, %.1f minutes",
-		len(routeIDs), totalNewTicks, duration.Minutes())
+	log.Printf("Location route tick sync complete: %v", result)
 
-	return nil
+	return result, nil
 }
 
 // SyncLocationRouteComments syncs comments for ALL routes with location_id (woulder locations - always daily)
@@ -1450,8 +2040,12 @@ func (s *ClimbTrackingService) SyncLocationRouteComments(ctx context.Context) er
 
 	totalNewComments := 0
 
-	// Sync comments for each route with rate limiting
-	err = s.rateLimitedSync(ctx, routeIDs, func(routeID string) error {
+	// Stop between routes if an operator cancels the job
+	syncCtx, stopWatching := s.watchForCancel(ctx, jobExec)
+	defer stopWatching()
+
+	// Sync comments for each route, fetched in rate-limited batches
+	err = s.rateLimitedCommentSync(syncCtx, routeIDs, func(routeID string, comments []mpClient.Comment, commentErr error) error {
 		routeIDInt64, _ := strconv.ParseInt(routeID, 10, 64)
 
 		// Get route name for tracking
@@ -1468,8 +2062,6 @@ func (s *ClimbTrackingService) SyncLocationRouteComments(ctx context.Context) er
 			}
 		}
 
-		// Fetch comments from MP API
-		comments, commentErr := s.mpClient.GetRouteComments(routeID)
 		if commentErr != nil {
 			// Report progress
 			if reporter != nil {
@@ -1493,6 +2085,9 @@ func (s *ClimbTrackingService) SyncLocationRouteComments(ctx context.Context) er
 			newCommentCount++
 		}
 
+		if newCommentCount > 0 {
+			s.refreshConditionsBeta(ctx, mountainproject.ConditionsBetaTargetRoute, routeIDInt64)
+		}
 		totalNewComments += newCommentCount
 
 		// Report progress to monitoring system (success)
@@ -1514,6 +2109,13 @@ func (s *ClimbTrackingService) SyncLocationRouteComments(ctx context.Context) er
 		}
 	}
 
+	s.recordSyncSummary(ctx, jobExec, reporter, &monitoring.SyncSummary{
+		JobName:     jobName,
+		JobType:     "location_comment_sync",
+		StartedAt:   startTime,
+		NewComments: totalNewComments,
+	}, err)
+
 	if err != nil {
 		return fmt.Errorf("location route comment sync error: %w", err)
 	}
@@ -1525,18 +2127,19 @@ func (s *ClimbTrackingService) SyncLocationRouteComments(ctx context.Context) er
 }
 
 // SyncTicksByPriority syncs ticks for non-location routes at a specific priority tier
-func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority string) error {
+func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority mountainproject.Priority) (*SyncResult, error) {
 	startTime := time.Now()
+	result := &SyncResult{}
 
 	// Get non-location routes due for tick sync at this priority
 	routeIDs, err := s.mountainProjectRepo.Sync().GetRoutesDueForTickSync(ctx, priority)
 	if err != nil {
-		return fmt.Errorf("failed to get routes for priority %s tick sync: %w", priority, err)
+		return result, fmt.Errorf("failed to get routes for priority %s tick sync: %w", priority, err)
 	}
 
 	if len(routeIDs) == 0 {
 		log.Printf("No %s priority routes due for tick sync", priority)
-		return nil
+		return result, nil
 	}
 
 	// START MONITORING: Create job execution record
@@ -1570,8 +2173,12 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 
 	totalNewTicks := 0
 
+	// Stop between routes if an operator cancels the job
+	syncCtx, stopWatching := s.watchForCancel(ctx, jobExec)
+	defer stopWatching()
+
 	// Sync ticks for each route with rate limiting
-	err = s.rateLimitedSync(ctx, routeIDs, func(routeID string) error {
+	err = s.rateLimitedSync(syncCtx, routeIDs, func(routeID string) error {
 		// Get last tick timestamp for this route
 		routeIDInt64, _ := strconv.ParseInt(routeID, 10, 64)
 
@@ -1591,6 +2198,7 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 
 		lastTickTime, tickErr := s.mountainProjectRepo.Ticks().GetLastTimestampForRoute(ctx, routeIDInt64)
 		if tickErr != nil {
+			result.routeFailed()
 			// Report progress
 			if reporter != nil {
 				reporter.Increment(ctx, false)
@@ -1601,6 +2209,7 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 		// Fetch ticks from MP API
 		ticks, tickErr := s.mpClient.GetRouteTicks(routeID)
 		if tickErr != nil {
+			result.routeFailed()
 			// Report progress
 			if reporter != nil {
 				reporter.Increment(ctx, false)
@@ -1659,6 +2268,7 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 		}
 
 		totalNewTicks += newTickCount
+		result.routeSynced(newTickCount)
 
 		// Report progress to monitoring system (success)
 		if reporter != nil {
@@ -1668,7 +2278,7 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 		return nil
 	})
 
-	duration := time.Since(startTime)
+	result.Duration = time.Since(startTime)
 
 	// COMPLETE MONITORING: Mark job as completed or failed
 	if jobExec != nil {
@@ -1679,18 +2289,24 @@ func (s *ClimbTrackingService) SyncTicksByPriority(ctx context.Context, priority
 		}
 	}
 
+	s.recordSyncSummary(ctx, jobExec, reporter, &monitoring.SyncSummary{
+		JobName:   jobName,
+		JobType:   "tick_sync",
+		StartedAt: startTime,
+		NewTicks:  totalNewTicks,
+	}, err)
+
 	if err != nil {
-		return fmt.Errorf("priority %s tick sync error: %w", priority, err)
+		return result, fmt.Errorf("priority %s tick sync error: %w", priority, err)
 	}
 
-	log.Printf("Priority %s tick sync complete: %d routes, %d new ticks, %.1f minutes",
-		priority, len(routeIDs), totalNewTicks, duration.Minutes())
+	log.Printf("Priority %s tick sync complete: %v", priority, result)
 
-	return nil
+	return result, nil
 }
 
 // SyncCommentsByPriority syncs comments for non-location routes at a specific priority tier
-func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, priority string) error {
+func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, priority mountainproject.Priority) error {
 	startTime := time.Now()
 
 	// Get non-location routes due for comment sync at this priority
@@ -1728,12 +2344,14 @@ func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, prior
 
 	totalNewComments := 0
 
-	// Sync comments for each route with rate limiting
-	err = s.rateLimitedSync(ctx, routeIDs, func(routeID string) error {
+	// Stop between routes if an operator cancels the job
+	syncCtx, stopWatching := s.watchForCancel(ctx, jobExec)
+	defer stopWatching()
+
+	// Sync comments for each route, fetched in rate-limited batches
+	err = s.rateLimitedCommentSync(syncCtx, routeIDs, func(routeID string, comments []mpClient.Comment, commentErr error) error {
 		routeIDInt64, _ := strconv.ParseInt(routeID, 10, 64)
 
-		// Fetch comments from MP API
-		comments, commentErr := s.mpClient.GetRouteComments(routeID)
 		if commentErr != nil {
 			// Report progress (failure)
 			if reporter != nil {
@@ -1757,6 +2375,9 @@ func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, prior
 			newCommentCount++
 		}
 
+		if newCommentCount > 0 {
+			s.refreshConditionsBeta(ctx, mountainproject.ConditionsBetaTargetRoute, routeIDInt64)
+		}
 		totalNewComments += newCommentCount
 
 		// Report progress to monitoring system (success)
@@ -1778,6 +2399,13 @@ func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, prior
 		}
 	}
 
+	s.recordSyncSummary(ctx, jobExec, reporter, &monitoring.SyncSummary{
+		JobName:     jobName,
+		JobType:     "comment_sync",
+		StartedAt:   startTime,
+		NewComments: totalNewComments,
+	}, err)
+
 	if err != nil {
 		return fmt.Errorf("priority %s comment sync error: %w", priority, err)
 	}
@@ -1788,8 +2416,19 @@ func (s *ClimbTrackingService) SyncCommentsByPriority(ctx context.Context, prior
 	return nil
 }
 
+// watchForCancel wraps ctx with JobMonitor.WatchForCancel so a monitored
+// sync's loop stops once an operator cancels its job (POST
+// /api/monitoring/jobs/:job_id/cancel). Unmonitored runs get ctx back as-is.
+func (s *ClimbTrackingService) watchForCancel(ctx context.Context, jobExec *monitoring.JobExecution) (context.Context, context.CancelFunc) {
+	if s.jobMonitor == nil || jobExec == nil {
+		return ctx, func() {}
+	}
+	return s.jobMonitor.WatchForCancel(ctx, jobExec.ID)
+}
+
 // rateLimitedSync processes routes with consistent rate limiting
-// 50ms between requests, 10 second pause every 500 requests
+// 50ms between requests, 10 second pause every 500 requests.
+// A cancelled ctx returns its cause, e.g. monitoring.ErrJobCancelled.
 func (s *ClimbTrackingService) rateLimitedSync(
 	ctx context.Context,
 	routeIDs []int64,
@@ -1801,7 +2440,7 @@ func (s *ClimbTrackingService) rateLimitedSync(
 		// Check context cancellation
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return context.Cause(ctx)
 		default:
 		}
 
@@ -1826,6 +2465,89 @@ func (s *ClimbTrackingService) rateLimitedSync(
 	return nil
 }
 
+// rateLimitedCommentSync is rateLimitedSync for comment jobs: comments are
+// fetched commentBatchSize routes at a time via fetchRouteComments, then each
+// route's comments (or fetch error) are passed to syncFunc in order.
+func (s *ClimbTrackingService) rateLimitedCommentSync(
+	ctx context.Context,
+	routeIDs []int64,
+	syncFunc func(routeID string, comments []mpClient.Comment, fetchErr error) error,
+) error {
+	requestCount := 0
+
+	for start := 0; start < len(routeIDs); start += commentBatchSize {
+		// Check context cancellation
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		default:
+		}
+
+		end := min(start+commentBatchSize, len(routeIDs))
+		batch := make([]string, 0, end-start)
+		for _, routeID := range routeIDs[start:end] {
+			batch = append(batch, strconv.FormatInt(routeID, 10))
+		}
+
+		for _, result := range s.fetchRouteComments(batch) {
+			if err := syncFunc(result.ID, result.Comments, result.Err); err != nil {
+				log.Printf("Error syncing route %s: %v", result.ID, err)
+				continue
+			}
+
+			requestCount++
+
+			// Every 500 requests, pause for 10 seconds
+			if requestCount%500 == 0 {
+				log.Printf("Processed %d requests, pausing for 10 seconds...", requestCount)
+				time.Sleep(10 * time.Second)
+			}
+		}
+	}
+
+	return nil
+}
+
+// recordSyncSummary persists a monitoring.SyncSummary for a finished sync run
+// so throughput can be charted over time (see GET /api/monitoring/trends).
+// Item counts are taken from the reporter when one is supplied; otherwise the
+// caller's values are kept. When the run resumed an interrupted job, the
+// counts include the interrupted run's work, so StartedAt is moved back to
+// the job execution's original start to keep duration and day consistent.
+// Failures are logged only - trend history must never fail the sync itself.
+func (s *ClimbTrackingService) recordSyncSummary(
+	ctx context.Context,
+	jobExec *monitoring.JobExecution,
+	reporter *monitoring.ProgressReporter,
+	summary *monitoring.SyncSummary,
+	syncErr error,
+) {
+	if s.jobMonitor == nil {
+		return
+	}
+
+	summary.Status = monitoring.StatusCompleted
+	if errors.Is(syncErr, monitoring.ErrJobCancelled) {
+		summary.Status = monitoring.StatusCancelled
+	} else if syncErr != nil {
+		summary.Status = monitoring.StatusFailed
+	}
+	if jobExec != nil {
+		jobID := jobExec.ID
+		summary.JobExecutionID = &jobID
+		if !jobExec.StartedAt.IsZero() && jobExec.StartedAt.Before(summary.StartedAt) {
+			summary.StartedAt = jobExec.StartedAt
+		}
+	}
+	if reporter != nil {
+		summary.ItemsProcessed, summary.ItemsSucceeded, summary.ItemsFailed = reporter.GetProgress()
+	}
+
+	if err := s.jobMonitor.RecordSyncSummary(ctx, summary); err != nil {
+		log.Printf("Warning: failed to record sync summary for %s: %v", summary.JobName, err)
+	}
+}
+
 // SyncLocationAreaDiscovery walks the Mountain Project area trees rooted at
 // every configured location root (see LocationRoots) and inserts any newly
 // discovered MP sub-areas / routes into the database.
@@ -1866,6 +2588,7 @@ func (s *ClimbTrackingService) SyncLocationAreaDiscovery(ctx context.Context) er
 	// index instead of restarting from the first root.
 	monitor := s.areaDiscoveryJobMonitor()
 	var startIndex int
+	var priorRootsFailed int // failures before the interrupt, for the sync summary
 	var resumingJob *monitoring.JobExecution
 	if monitor != nil {
 		interrupted, err := monitor.GetInterruptedJob(ctx, jobName)
@@ -1877,6 +2600,9 @@ func (s *ClimbTrackingService) SyncLocationAreaDiscovery(ctx context.Context) er
 				if idx, ok := checkpoint["current_root_index"].(float64); ok {
 					startIndex = int(idx)
 				}
+				if failed, ok := checkpoint["roots_failed"].(float64); ok {
+					priorRootsFailed = int(failed)
+				}
 			}
 			log.Printf("Resuming %s from root index %d (job id=%d)", jobName, startIndex, interrupted.ID)
 		}
@@ -1975,7 +2701,7 @@ func (s *ClimbTrackingService) SyncLocationAreaDiscovery(ctx context.Context) er
 		if jobExec != nil && monitor != nil {
 			checkpoint := map[string]interface{}{
 				"current_root_index": i + 1,
-				"roots_failed":       rootsFailed,
+				"roots_failed":       priorRootsFailed + rootsFailed,
 			}
 			if err := monitor.SaveCheckpoint(ctx, jobExec.ID, checkpoint); err != nil {
 				log.Printf("Warning: failed to save %s checkpoint: %v", jobName, err)
@@ -2009,6 +2735,23 @@ done:
 	log.Printf("location_area_discovery complete: processed=%d succeeded=%d failed=%d (%.1f minutes)",
 		rootsProcessed, rootsSucceeded, rootsFailed, duration.Minutes())
 
+	// A cancelled run is paused and resumes later; its summary is written
+	// when that resumed run finishes.
+	if !cancelled {
+		var summaryErr error
+		if rootsFailed > 0 {
+			summaryErr = fmt.Errorf("%d/%d roots failed", rootsFailed, rootsProcessed)
+		}
+		s.recordSyncSummary(ctx, jobExec, nil, &monitoring.SyncSummary{
+			JobName:        jobName,
+			JobType:        jobName,
+			StartedAt:      startTime,
+			ItemsProcessed: startIndex + rootsProcessed,
+			ItemsSucceeded: startIndex - priorRootsFailed + rootsSucceeded,
+			ItemsFailed:    priorRootsFailed + rootsFailed,
+		}, summaryErr)
+	}
+
 	if cancelled {
 		return ctx.Err()
 	}
@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	mpdb "github.com/alexscott64/woulder/backend/internal/database/mountainproject"
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/alexscott64/woulder/backend/internal/monitoring"
 	"github.com/alexscott64/woulder/backend/internal/mountainproject"
@@ -31,11 +37,13 @@ func createTickWithUser(date, userName, style string) mountainproject.Tick {
 
 // Mock Mountain Project Client
 type MockMPClient struct {
-	GetRouteTicksFn    func(routeID string) ([]mountainproject.Tick, error)
-	GetRouteFn         func(routeID string) (*mountainproject.RouteResponse, error)
-	GetAreaFn          func(areaID string) (*mountainproject.AreaResponse, error)
-	GetAreaCommentsFn  func(areaID string) ([]mountainproject.Comment, error)
-	GetRouteCommentsFn func(routeID string) ([]mountainproject.Comment, error)
+	GetRouteTicksFn      func(routeID string) ([]mountainproject.Tick, error)
+	GetRouteTicksPagedFn func(routeID string, visit func(page []mountainproject.Tick) bool) error
+	GetUserTicksPagedFn  func(userID string, visit func(page []mountainproject.UserTick) bool) error
+	GetRouteFn           func(routeID string) (*mountainproject.RouteResponse, error)
+	GetAreaFn            func(areaID string) (*mountainproject.AreaResponse, error)
+	GetAreaCommentsFn    func(areaID string) ([]mountainproject.Comment, error)
+	GetRouteCommentsFn   func(routeID string) ([]mountainproject.Comment, error)
 }
 
 func (m *MockMPClient) GetRouteTicks(routeID string) ([]mountainproject.Tick, error) {
@@ -45,6 +53,27 @@ func (m *MockMPClient) GetRouteTicks(routeID string) ([]mountainproject.Tick, er
 	return nil, nil
 }
 
+// GetRouteTicksPaged falls back to returning GetRouteTicksFn's result as a
+// single page when no paged behaviour is configured.
+func (m *MockMPClient) GetRouteTicksPaged(routeID string, visit func(page []mountainproject.Tick) bool) error {
+	if m.GetRouteTicksPagedFn != nil {
+		return m.GetRouteTicksPagedFn(routeID, visit)
+	}
+	ticks, err := m.GetRouteTicks(routeID)
+	if err != nil || len(ticks) == 0 {
+		return err
+	}
+	visit(ticks)
+	return nil
+}
+
+func (m *MockMPClient) GetUserTicksPaged(userID string, visit func(page []mountainproject.UserTick) bool) error {
+	if m.GetUserTicksPagedFn != nil {
+		return m.GetUserTicksPagedFn(userID, visit)
+	}
+	return nil
+}
+
 func (m *MockMPClient) GetRoute(routeID string) (*mountainproject.RouteResponse, error) {
 	if m.GetRouteFn != nil {
 		return m.GetRouteFn(routeID)
@@ -133,7 +162,7 @@ func TestClimbTrackingService_GetClimbHistoryForLocation(t *testing.T) {
 			mockClimbingRepo := NewMockClimbingRepository()
 			mockClimbingRepo.history.GetClimbHistoryForLocationFn = tt.mockFn
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, &MockMPClient{}, nil)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, &MockMPClient{}, nil, nil)
 			history, err := service.GetClimbHistoryForLocation(context.Background(), tt.locID, tt.limit)
 
 			if tt.wantErr {
@@ -248,25 +277,139 @@ func TestClimbTrackingService_SyncNewTicksForLocation(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), tt.locationID)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			result, err := service.SyncNewTicksForLocation(context.Background(), tt.locationID)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tt.expectedSaved, savedCount, "Expected %d ticks to be saved, got %d", tt.expectedSaved, savedCount)
+				assert.Equal(t, tt.expectedSaved, result.ItemsAdded)
+				assert.Equal(t, len(tt.mockRouteIDs), result.RoutesProcessed)
+				assert.Zero(t, result.Failures)
 			}
 		})
 	}
 }
 
+// TestClimbTrackingService_SyncNewTicksForLocation_EarlyStop verifies that
+// with tick early stop enabled, paging stops at the page holding the first
+// tick at-or-before the last known tick, later pages are never requested,
+// and only the new ticks are saved, oldest first.
+func TestClimbTrackingService_SyncNewTicksForLocation_EarlyStop(t *testing.T) {
+	// Tick dates are parsed as Pacific time, so build them in Pacific too
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("Pacific timezone unavailable: %v", err)
+	}
+	now := time.Now().In(pacificTZ)
+	lastKnown := now.Add(-24 * time.Hour)
+	format := "Jan 2, 2006, 3:04 pm"
+
+	pages := [][]mountainproject.Tick{
+		{
+			createTickWithUser(now.Add(-1*time.Hour).Format(format), "NewestUser", "Flash"),
+			createTickWithUser(now.Add(-2*time.Hour).Format(format), "NewerUser", "Send"),
+			createTickWithUser(lastKnown.Add(-1*time.Hour).Format(format), "KnownUser", "Send"),
+			createTickWithUser(lastKnown.Add(-2*time.Hour).Format(format), "OlderUser", "Send"),
+		},
+		{
+			createTickWithUser(lastKnown.Add(-48*time.Hour).Format(format), "AncientUser", "Send"),
+		},
+	}
+
+	mockMPRepo := NewMockMountainProjectRepository()
+	mockMPRepo.routes.GetAllIDsForLocationFn = func(ctx context.Context, locationID int) ([]int64, error) {
+		return []int64{123}, nil
+	}
+	mockMPRepo.ticks.GetLastTimestampForRouteFn = func(ctx context.Context, routeID int64) (*time.Time, error) {
+		return &lastKnown, nil
+	}
+	var savedUsers []string
+	mockMPRepo.ticks.SaveTickFn = func(ctx context.Context, tick *models.MPTick) error {
+		savedUsers = append(savedUsers, tick.UserName)
+		return nil
+	}
+
+	pagesFetched := 0
+	mockMPClient := &MockMPClient{
+		GetRouteTicksFn: func(routeID string) ([]mountainproject.Tick, error) {
+			t.Error("GetRouteTicks should not be called when early stop is enabled")
+			return nil, nil
+		},
+		GetRouteTicksPagedFn: func(routeID string, visit func(page []mountainproject.Tick) bool) error {
+			for _, page := range pages {
+				pagesFetched++
+				if !visit(page) {
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+
+	service := NewClimbTrackingService(mockMPRepo, NewMockClimbingRepository(), mockMPClient, nil, nil)
+	service.SetTickEarlyStop(true)
+
+	_, err = service.SyncNewTicksForLocation(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NewerUser", "NewestUser"}, savedUsers)
+	assert.Equal(t, 1, pagesFetched, "should stop paging after reaching a known tick")
+}
+
+// TestClimbTrackingService_SyncNewTicksForLocation_EarlyStopPageError verifies
+// that a failed page saves nothing, so the route's last tick does not move
+// past ticks that were never fetched.
+func TestClimbTrackingService_SyncNewTicksForLocation_EarlyStopPageError(t *testing.T) {
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("Pacific timezone unavailable: %v", err)
+	}
+	now := time.Now().In(pacificTZ)
+	lastKnown := now.Add(-72 * time.Hour)
+	format := "Jan 2, 2006, 3:04 pm"
+
+	mockMPRepo := NewMockMountainProjectRepository()
+	mockMPRepo.routes.GetAllIDsForLocationFn = func(ctx context.Context, locationID int) ([]int64, error) {
+		return []int64{123}, nil
+	}
+	mockMPRepo.ticks.GetLastTimestampForRouteFn = func(ctx context.Context, routeID int64) (*time.Time, error) {
+		return &lastKnown, nil
+	}
+	saved := 0
+	mockMPRepo.ticks.SaveTickFn = func(ctx context.Context, tick *models.MPTick) error {
+		saved++
+		return nil
+	}
+
+	mockMPClient := &MockMPClient{
+		GetRouteTicksPagedFn: func(routeID string, visit func(page []mountainproject.Tick) bool) error {
+			// First page is entirely new, so paging continues...
+			visit([]mountainproject.Tick{
+				createTickWithUser(now.Add(-1*time.Hour).Format(format), "NewestUser", "Flash"),
+				createTickWithUser(now.Add(-2*time.Hour).Format(format), "NewerUser", "Send"),
+			})
+			// ...and the second page fails
+			return errors.New("page 2: connection reset")
+		},
+	}
+
+	service := NewClimbTrackingService(mockMPRepo, NewMockClimbingRepository(), mockMPClient, nil, nil)
+	service.SetTickEarlyStop(true)
+
+	result, err := service.SyncNewTicksForLocation(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, saved, "no ticks should be saved when paging fails")
+	assert.Equal(t, 1, result.Failures, "the route whose page failed should count as a failure")
+}
+
 func TestClimbTrackingService_GetSyncStatus(t *testing.T) {
 	mockMPRepo := NewMockMountainProjectRepository()
 	mockClimbingRepo := NewMockClimbingRepository()
 	mockMPClient := &MockMPClient{}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
 
 	// Initially not syncing
 	isSyncing, lastSync := service.GetSyncStatus()
@@ -302,18 +445,18 @@ func TestClimbTrackingService_ConcurrentSyncPrevention(t *testing.T) {
 	mockClimbingRepo := NewMockClimbingRepository()
 	mockMPClient := &MockMPClient{}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
 
 	// Start first sync
 	go func() {
-		_ = service.SyncNewTicksForLocation(context.Background(), 1)
+		_, _ = service.SyncNewTicksForLocation(context.Background(), 1)
 	}()
 
 	// Wait a bit to ensure first sync has started
 	time.Sleep(10 * time.Millisecond)
 
 	// Try to start second sync - should fail
-	err := service.SyncNewTicksForLocation(context.Background(), 1)
+	_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "sync already in progress")
 }
@@ -369,8 +512,8 @@ func TestClimbTrackingService_DateParsing(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), 1)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 
 			assert.NoError(t, err)
 			if tt.shouldSave {
@@ -440,8 +583,8 @@ func TestDateParsingWithTimezone(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), 1)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 
 			assert.NoError(t, err)
 			assert.Equal(t, 1, len(savedTicks), "Expected 1 tick to be saved")
@@ -537,8 +680,8 @@ func TestTimezoneConsistencyBetweenSyncs(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), 1)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 
 			assert.NoError(t, err)
 
@@ -624,8 +767,8 @@ func TestFutureDateFiltering(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), 1)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 
 			assert.NoError(t, err)
 
@@ -729,8 +872,8 @@ func TestCommentCleaning(t *testing.T) {
 				},
 			}
 
-			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-			err := service.SyncNewTicksForLocation(context.Background(), 1)
+			service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+			_, err := service.SyncNewTicksForLocation(context.Background(), 1)
 
 			assert.NoError(t, err)
 			assert.Equal(t, 1, len(savedTicks), "%s: Expected tick to be saved", tt.description)
@@ -786,8 +929,8 @@ func TestJamieZeldaRailsScenario(t *testing.T) {
 		},
 	}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
-	err := service.SyncNewTicksForLocation(context.Background(), 2) // Location 2 is Index, WA
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
+	_, err := service.SyncNewTicksForLocation(context.Background(), 2) // Location 2 is Index, WA
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, len(savedTicks), "Expected Jamie's tick to be saved")
@@ -1010,7 +1153,7 @@ func TestSyncLocationAreaDiscovery_FantasiaBoulders(t *testing.T) {
 		GetAreaCommentsFn:  func(_ string) ([]mountainproject.Comment, error) { return nil, nil },
 	}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
 	mockMonitor := &mockAreaDiscoveryJobMonitor{}
 	restoreMonitor := service.SetAreaDiscoveryJobMonitorForTest(mockMonitor)
 	defer restoreMonitor()
@@ -1110,7 +1253,7 @@ func TestSyncLocationAreaDiscovery_ErrorIsolation(t *testing.T) {
 		},
 	}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
 	mockMonitor := &mockAreaDiscoveryJobMonitor{}
 	restoreMonitor := service.SetAreaDiscoveryJobMonitorForTest(mockMonitor)
 	defer restoreMonitor()
@@ -1176,7 +1319,7 @@ func TestSyncLocationAreaDiscovery_ContextCancellation(t *testing.T) {
 		},
 	}
 
-	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil)
+	service := NewClimbTrackingService(mockMPRepo, mockClimbingRepo, mockMPClient, nil, nil)
 	mockMonitor := &mockAreaDiscoveryJobMonitor{}
 	restoreMonitor := service.SetAreaDiscoveryJobMonitorForTest(mockMonitor)
 	defer restoreMonitor()
@@ -1195,3 +1338,783 @@ func TestSyncLocationAreaDiscovery_ContextCancellation(t *testing.T) {
 	assert.Len(t, mockMonitor.MarkJobPausedCalls, 1,
 		"MarkJobPaused should fire exactly once so the next boot resumes the run")
 }
+
+// TestClimbTrackingService_RecordSyncSummary_ResumedJobUsesOriginalStart
+// verifies that a summary for a resumed job is dated from the job
+// execution's original start, since its counts include the interrupted run.
+func TestClimbTrackingService_RecordSyncSummary_ResumedJobUsesOriginalStart(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), &MockMPClient{}, monitoring.NewJobMonitor(db), nil)
+
+	originalStart := time.Now().Add(-3 * time.Hour)
+	jobExec := &monitoring.JobExecution{ID: 7, StartedAt: originalStart}
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO woulder.sync_summaries")).
+		WithArgs(
+			sqlmock.AnyArg(), "location_tick_sync", "location_tick_sync", monitoring.StatusCompleted,
+			originalStart, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			0, 0, 0, 12, 0, 0,
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+	summary := &monitoring.SyncSummary{
+		JobName:   "location_tick_sync",
+		JobType:   "location_tick_sync",
+		StartedAt: time.Now(), // this process's start, after the resume
+		NewTicks:  12,
+	}
+	service.recordSyncSummary(context.Background(), jobExec, nil, summary, nil)
+
+	assert.Equal(t, originalStart, summary.StartedAt)
+	assert.GreaterOrEqual(t, summary.DurationSeconds, 3*60*60.0-1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRateLimitedSync_StopsWhenJobCancelled verifies that a sync whose job
+// was cancelled (see JobMonitor.WatchForCancel) stops before the next route
+// and reports the cancel, so the run is summarized as cancelled.
+func TestRateLimitedSync_StopsWhenJobCancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), &MockMPClient{}, monitoring.NewJobMonitor(db), nil)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	synced := 0
+	err = service.rateLimitedSync(ctx, []int64{1, 2, 3}, func(routeID string) error {
+		synced++
+		cancel(monitoring.ErrJobCancelled)
+		return nil
+	})
+
+	assert.ErrorIs(t, err, monitoring.ErrJobCancelled)
+	assert.Equal(t, 1, synced, "no routes should sync after the cancel")
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO woulder.sync_summaries")).
+		WithArgs(
+			sqlmock.AnyArg(), "high_priority_tick_sync", "tick_sync", monitoring.StatusCancelled,
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			0, 0, 0, 0, 0, 0,
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+	service.recordSyncSummary(context.Background(), &monitoring.JobExecution{ID: 8}, nil, &monitoring.SyncSummary{
+		JobName:   "high_priority_tick_sync",
+		JobType:   "tick_sync",
+		StartedAt: time.Now(),
+	}, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSyncNewRoutesForAllStates_BoundedWorkers verifies that states are
+// checked concurrently by at most stateSyncWorkers workers and that
+// failures from individual states are aggregated into the result.
+func TestSyncNewRoutesForAllStates_BoundedWorkers(t *testing.T) {
+	// No expectations: every job monitor query fails, which the sync treats
+	// as "run without monitoring".
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	states := []mpdb.StateConfig{
+		{StateName: "Washington", MPAreaID: "1"},
+		{StateName: "Oregon", MPAreaID: "2"},
+		{StateName: "Idaho", MPAreaID: "3"},
+		{StateName: "Montana", MPAreaID: "4"},
+		{StateName: "Utah", MPAreaID: "5"},
+		{StateName: "Nevada", MPAreaID: "6"},
+		{StateName: "California", MPAreaID: "7"},
+	}
+
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.areas.GetAllStateConfigsFn = func(ctx context.Context) ([]mpdb.StateConfig, error) {
+		return states, nil
+	}
+	mpRepo.areas.GetRouteCountFn = func(ctx context.Context, mpAreaID string) (int, error) {
+		return 10, nil
+	}
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	checked := map[string]bool{}
+	mpClient := &MockMPClient{
+		GetAreaFn: func(areaID string) (*mountainproject.AreaResponse, error) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			checked[areaID] = true
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+
+			if areaID == "3" {
+				return nil, errors.New("mountain project unavailable")
+			}
+			return &mountainproject.AreaResponse{
+				Title:           "State " + areaID,
+				RouteTypeCounts: &mountainproject.RouteTypeCounts{Total: 10},
+			}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, monitoring.NewJobMonitor(db), nil)
+	service.SetStateSyncWorkers(3)
+
+	err = service.SyncNewRoutesForAllStates(context.Background())
+	assert.EqualError(t, err, "sync completed with 1 failures")
+	assert.Len(t, checked, len(states), "every state should be checked once")
+	assert.LessOrEqual(t, maxInFlight, 3, "worker pool should bound concurrency")
+	assert.Greater(t, maxInFlight, 1, "states should be checked concurrently")
+}
+
+func TestSyncSingleRoute(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetByIDFn = func(ctx context.Context, mpRouteID int64) (*models.MPRoute, error) {
+		return &models.MPRoute{MPRouteID: mpRouteID, Name: "Jamie's Rail"}, nil
+	}
+
+	var savedTicks []*models.MPTick
+	mpRepo.ticks.SaveTickFn = func(ctx context.Context, tick *models.MPTick) error {
+		savedTicks = append(savedTicks, tick)
+		return nil
+	}
+	var savedComments []int64
+	mpRepo.comments.SaveRouteCommentFn = func(ctx context.Context, mpCommentID, mpRouteID int64, userName, commentText string, commentedAt time.Time) error {
+		assert.Equal(t, int64(42), mpRouteID)
+		savedComments = append(savedComments, mpCommentID)
+		return nil
+	}
+
+	mpClient := &MockMPClient{
+		GetRouteTicksFn: func(routeID string) ([]mountainproject.Tick, error) {
+			assert.Equal(t, "42", routeID)
+			return []mountainproject.Tick{
+				createTickWithUser("Mar 3, 2025, 1:00 pm", "alice", "Send"),
+				createTickWithUser("Mar 1, 2025, 1:00 pm", "bob", "Flash"),
+			}, nil
+		},
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			return []mountainproject.Comment{{ID: 7, Message: "Great line", Created: 1700000000}}, nil
+		},
+		GetAreaFn: func(areaID string) (*mountainproject.AreaResponse, error) {
+			t.Errorf("SyncSingleRoute should not traverse areas, fetched %s", areaID)
+			return nil, errors.New("unexpected")
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.SyncSingleRoute(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.Len(t, savedTicks, 2)
+	for _, tick := range savedTicks {
+		assert.Equal(t, int64(42), tick.MPRouteID)
+	}
+	assert.Equal(t, []int64{7}, savedComments)
+}
+
+func TestSyncSingleRoute_NotFound(t *testing.T) {
+	mpClient := &MockMPClient{
+		GetRouteTicksFn: func(routeID string) ([]mountainproject.Tick, error) {
+			t.Error("ticks should not be fetched for an unknown route")
+			return nil, nil
+		},
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.SyncSingleRoute(context.Background(), 42)
+
+	assert.ErrorIs(t, err, ErrRouteNotFound)
+}
+
+func TestSyncSingleRoute_SyncsCommentsWhenTicksFail(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetByIDFn = func(ctx context.Context, mpRouteID int64) (*models.MPRoute, error) {
+		return &models.MPRoute{MPRouteID: mpRouteID, Name: "Jamie's Rail"}, nil
+	}
+	commentsSaved := 0
+	mpRepo.comments.SaveRouteCommentFn = func(ctx context.Context, mpCommentID, mpRouteID int64, userName, commentText string, commentedAt time.Time) error {
+		commentsSaved++
+		return nil
+	}
+
+	mpClient := &MockMPClient{
+		GetRouteTicksFn: func(routeID string) ([]mountainproject.Tick, error) {
+			return nil, errors.New("mountain project unavailable")
+		},
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			return []mountainproject.Comment{{ID: 7, Message: "Great line", Created: 1700000000}}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.SyncSingleRoute(context.Background(), 42)
+
+	assert.ErrorContains(t, err, "ticks: failed to fetch ticks: mountain project unavailable")
+	assert.Equal(t, 1, commentsSaved)
+}
+
+func TestSyncRouteComments_StoresConditionsBeta(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.comments.GetRouteCommentTextsFn = func(ctx context.Context, mpRouteID int64) ([]string, error) {
+		assert.Equal(t, int64(42), mpRouteID)
+		return []string{"Seeps for days after rain.", "Doesn't get morning sun, shady until noon."}, nil
+	}
+
+	var savedType string
+	var savedTags []string
+	var savedCount int
+	mpRepo.comments.SaveConditionsBetaFn = func(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error {
+		savedType, savedTags, savedCount = targetType, tags, commentCount
+		return nil
+	}
+
+	mpClient := &MockMPClient{
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			return []mountainproject.Comment{{ID: 7, Message: "Seeps for days after rain.", Created: 1700000000}}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.syncRouteComments(context.Background(), "42")
+
+	assert.NoError(t, err)
+	assert.Equal(t, mpdb.ConditionsBetaTargetRoute, savedType)
+	assert.Equal(t, []string{"seeps", "shady"}, savedTags)
+	assert.Equal(t, 2, savedCount)
+}
+
+func TestSyncRouteComments_ConditionsBetaFailureIsNonFatal(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.comments.GetRouteCommentTextsFn = func(ctx context.Context, mpRouteID int64) ([]string, error) {
+		return nil, errors.New("connection reset")
+	}
+	mpRepo.comments.SaveConditionsBetaFn = func(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error {
+		t.Error("beta should not be saved when comments could not be loaded")
+		return nil
+	}
+
+	mpClient := &MockMPClient{
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			return []mountainproject.Comment{{ID: 7, Message: "Great line", Created: 1700000000}}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	assert.NoError(t, service.syncRouteComments(context.Background(), "42"))
+}
+
+func TestBackfillConditionsBeta(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.comments.GetTargetsMissingConditionsBetaFn = func(ctx context.Context, targetType string) ([]int64, error) {
+		if targetType == mpdb.ConditionsBetaTargetArea {
+			return []int64{10}, nil
+		}
+		return []int64{20, 30}, nil
+	}
+	mpRepo.comments.GetAreaCommentTextsFn = func(ctx context.Context, mpAreaID int64) ([]string, error) {
+		return []string{"Seeps for days after rain."}, nil
+	}
+	mpRepo.comments.GetRouteCommentTextsFn = func(ctx context.Context, mpRouteID int64) ([]string, error) {
+		return []string{"Shady until noon."}, nil
+	}
+
+	var saved []string
+	mpRepo.comments.SaveConditionsBetaFn = func(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error {
+		saved = append(saved, fmt.Sprintf("%s %d", targetType, mpID))
+		return nil
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), &MockMPClient{}, nil, nil)
+
+	n, err := service.BackfillConditionsBeta(context.Background(), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Empty(t, saved, "dry run must not write")
+
+	n, err = service.BackfillConditionsBeta(context.Background(), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"area 10", "route 20", "route 30"}, saved)
+}
+
+func TestSyncRouteComments_CommentAspect(t *testing.T) {
+	tests := []struct {
+		name         string
+		comments     []string
+		wantAspect   string
+		wantEvidence []string
+		wantCleared  bool
+	}{
+		{
+			name:         "explicit sun beta overrides the aspect",
+			comments:     []string{"Faces south, dries fast.", "South-facing so go in winter."},
+			wantAspect:   "S",
+			wantEvidence: []string{"faces south", "south-facing"},
+		},
+		{
+			name:        "conflicting beta reverts to the computed aspect",
+			comments:    []string{"Faces south.", "Faces north."},
+			wantCleared: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mpRepo := NewMockMountainProjectRepository()
+			mpRepo.comments.GetRouteCommentTextsFn = func(ctx context.Context, mpRouteID int64) ([]string, error) {
+				return tt.comments, nil
+			}
+
+			var gotAspect string
+			var gotEvidence []string
+			cleared := false
+			mpRepo.routes.SetCommentAspectFn = func(ctx context.Context, mpRouteID int64, aspect string, evidence []string) error {
+				assert.Equal(t, int64(42), mpRouteID)
+				gotAspect, gotEvidence = aspect, evidence
+				return nil
+			}
+			mpRepo.routes.ClearCommentAspectFn = func(ctx context.Context, mpRouteID int64) error {
+				assert.Equal(t, int64(42), mpRouteID)
+				cleared = true
+				return nil
+			}
+
+			mpClient := &MockMPClient{
+				GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+					return []mountainproject.Comment{{ID: 7, Message: "Faces south.", Created: 1700000000}}, nil
+				},
+			}
+
+			service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+			assert.NoError(t, service.syncRouteComments(context.Background(), "42"))
+
+			assert.Equal(t, tt.wantAspect, gotAspect)
+			assert.Equal(t, tt.wantEvidence, gotEvidence)
+			assert.Equal(t, tt.wantCleared, cleared)
+		})
+	}
+}
+
+// batchMPClient adds batched comment fetching to MockMPClient.
+type batchMPClient struct {
+	*MockMPClient
+	GetRouteCommentsBatchFn func(routeIDs []string) []mountainproject.CommentsResult
+}
+
+func (m *batchMPClient) GetRouteCommentsBatch(routeIDs []string) []mountainproject.CommentsResult {
+	return m.GetRouteCommentsBatchFn(routeIDs)
+}
+
+func TestFetchRouteComments_UsesBatchWhenSupported(t *testing.T) {
+	var batched []string
+	mpClient := &batchMPClient{
+		MockMPClient: &MockMPClient{
+			GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+				t.Errorf("per-route fetch called for %s", routeID)
+				return nil, nil
+			},
+		},
+		GetRouteCommentsBatchFn: func(routeIDs []string) []mountainproject.CommentsResult {
+			batched = routeIDs
+			results := make([]mountainproject.CommentsResult, len(routeIDs))
+			for i, id := range routeIDs {
+				results[i] = mountainproject.CommentsResult{ID: id}
+			}
+			return results
+		},
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), mpClient, nil, nil)
+	results := service.fetchRouteComments([]string{"1", "2"})
+
+	assert.Equal(t, []string{"1", "2"}, batched)
+	assert.Len(t, results, 2)
+}
+
+func TestFetchRouteComments_FallsBackToPerRoute(t *testing.T) {
+	mpClient := &MockMPClient{
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			if routeID == "2" {
+				return nil, errors.New("timeout")
+			}
+			return []mountainproject.Comment{{ID: 7, Message: "Great line"}}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), mpClient, nil, nil)
+	results := service.fetchRouteComments([]string{"1", "2"})
+
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "1", results[0].ID)
+		assert.Len(t, results[0].Comments, 1)
+		assert.NoError(t, results[0].Err)
+		assert.Equal(t, "2", results[1].ID)
+		assert.Error(t, results[1].Err)
+	}
+}
+
+// TestSyncAreaRecursive_EmptyCoordinates is a regression test: an area whose
+// coordinates array is empty must be saved without GPS instead of panicking.
+func TestSyncAreaRecursive_EmptyCoordinates(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	var saved *models.MPArea
+	mpRepo.areas.SaveAreaFn = func(ctx context.Context, area *models.MPArea) error {
+		saved = area
+		return nil
+	}
+
+	mpClient := &MockMPClient{
+		GetAreaFn: func(areaID string) (*mountainproject.AreaResponse, error) {
+			return &mountainproject.AreaResponse{ID: 105, Title: "Unmapped Area", Type: "Area", Coordinates: []float64{}}, nil
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.SyncAreaRecursive(context.Background(), "105", nil)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, saved) {
+		assert.Nil(t, saved.Latitude)
+		assert.Nil(t, saved.Longitude)
+	}
+}
+
+// TestSyncAreaRoots_SharedDedupe checks that roots share one processed-area
+// set: a root nested in an earlier one, or listed twice, is not re-crawled.
+func TestSyncAreaRoots_SharedDedupe(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	savedLocations := map[int64]*int{}
+	mpRepo.areas.SaveAreaFn = func(ctx context.Context, area *models.MPArea) error {
+		savedLocations[area.MPAreaID] = area.LocationID
+		return nil
+	}
+
+	var fetched []string
+	mpClient := &MockMPClient{
+		GetAreaFn: func(areaID string) (*mountainproject.AreaResponse, error) {
+			fetched = append(fetched, areaID)
+			area := &mountainproject.AreaResponse{Title: "Area " + areaID, Type: "Area"}
+			area.ID, _ = strconv.Atoi(areaID)
+			if areaID == "100" {
+				area.Children = []mountainproject.ChildElement{{ID: 101, Title: "Sub Area", Type: "Area"}}
+			}
+			return area, nil
+		},
+	}
+
+	loc5, loc6 := 5, 6
+	roots := []AreaSyncRoot{
+		{LocationID: &loc5, MPAreaID: 100},
+		{LocationID: &loc6, MPAreaID: 101}, // nested in 100
+		{LocationID: &loc5, MPAreaID: 100}, // duplicate
+		{MPAreaID: 200},
+	}
+
+	var skipped []bool
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), mpClient, nil, nil)
+	err := service.SyncAreaRoots(context.Background(), roots, func(root AreaSyncRoot, wasSkipped bool) {
+		skipped = append(skipped, wasSkipped)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"100", "101", "200"}, fetched)
+	assert.Equal(t, []bool{false, true, true, false}, skipped)
+	if assert.NotNil(t, savedLocations[101]) {
+		assert.Equal(t, 5, *savedLocations[101], "nested area keeps the location of the root that reached it first")
+	}
+	assert.Nil(t, savedLocations[200])
+}
+
+func TestSyncNewRoutesInArea_EmptyCoordinates(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	var upserted int
+	mpRepo.routes.UpsertRouteFn = func(ctx context.Context, mpRouteID, mpAreaID int64, locationID *int, name, routeType, rating string, lat, lon *float64, aspect *string) error {
+		upserted++
+		assert.Nil(t, lat)
+		assert.Nil(t, lon)
+		return nil
+	}
+
+	area := &mountainproject.AreaResponse{
+		ID:          105,
+		Title:       "Unmapped Area",
+		Coordinates: []float64{},
+		Children:    []mountainproject.ChildElement{{ID: 201, Title: "New Problem", Type: "Route", RouteTypes: []string{"Boulder"}}},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), &MockMPClient{}, nil, nil)
+	synced, err := service.syncNewRoutesInArea(context.Background(), "105", area)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	assert.Equal(t, 1, upserted)
+}
+
+func TestSyncNewRoutesInArea_MarksDiscovered(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetIDsForAreaFn = func(ctx context.Context, mpAreaID string) ([]string, error) {
+		return []string{"201"}, nil
+	}
+	var discovered []int64
+	mpRepo.routes.MarkDiscoveredFn = func(ctx context.Context, mpRouteID int64) error {
+		discovered = append(discovered, mpRouteID)
+		return nil
+	}
+
+	area := &mountainproject.AreaResponse{
+		ID:    105,
+		Title: "Growing Area",
+		Children: []mountainproject.ChildElement{
+			{ID: 201, Title: "Old Problem", Type: "Route"},
+			{ID: 202, Title: "New Problem", Type: "Route"},
+		},
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), &MockMPClient{}, nil, nil)
+	synced, err := service.syncNewRoutesInArea(context.Background(), "105", area)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, synced)
+	assert.Equal(t, []int64{202}, discovered)
+}
+
+func TestGetTrendingRoutes_Window(t *testing.T) {
+	climbingRepo := NewMockClimbingRepository()
+	var gotSince time.Time
+	var gotLocation *int
+	climbingRepo.activity.GetTrendingRoutesFn = func(ctx context.Context, since time.Time, locationID *int, limit int) ([]models.TrendingRoute, error) {
+		gotSince, gotLocation = since, locationID
+		return []models.TrendingRoute{{MPRouteID: 1, TickCount: 3}}, nil
+	}
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+
+	locationID := 7
+	routes, err := service.GetTrendingRoutes(context.Background(), TrendingWindowWeek, &locationID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -7), gotSince, time.Minute)
+	assert.Equal(t, &locationID, gotLocation)
+
+	_, err = service.GetTrendingRoutes(context.Background(), TrendingWindowMonth, nil, 10)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(0, -1, 0), gotSince, time.Minute)
+	assert.Nil(t, gotLocation)
+
+	_, err = service.GetTrendingRoutes(context.Background(), "year", nil, 10)
+	assert.ErrorIs(t, err, ErrInvalidTrendingWindow)
+}
+
+func TestGetRecentlyDiscoveredRoutes_Days(t *testing.T) {
+	climbingRepo := NewMockClimbingRepository()
+	var gotSince time.Time
+	var gotLimit int
+	climbingRepo.activity.GetRecentlyDiscoveredRoutesFn = func(ctx context.Context, since time.Time, limit int) ([]models.DiscoveredRoute, error) {
+		gotSince, gotLimit = since, limit
+		return []models.DiscoveredRoute{{MPRouteID: 202, Name: "New Problem"}}, nil
+	}
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+
+	routes, err := service.GetRecentlyDiscoveredRoutes(context.Background(), 14, 25)
+	assert.NoError(t, err)
+	assert.Len(t, routes, 1)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -14), gotSince, time.Minute)
+	assert.Equal(t, 25, gotLimit)
+}
+
+func TestGetAreaTree(t *testing.T) {
+	parent := func(id int64) *int64 { return &id }
+
+	t.Run("single root is replaced by its children", func(t *testing.T) {
+		climbingRepo := NewMockClimbingRepository()
+		climbingRepo.activity.GetAreaTreeWithActivityFn = func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+			assert.Equal(t, 10, locationID)
+			// Ordered by activity, so a child may come before its parent
+			return []models.AreaActivitySummary{
+				{MPAreaID: 300, Name: "Morning Glory Wall", ParentMPAreaID: parent(200), TotalTicks: 48},
+				{MPAreaID: 100, Name: "Index", TotalTicks: 60},
+				{MPAreaID: 200, Name: "Lower Town Wall", ParentMPAreaID: parent(100), TotalTicks: 50},
+				{MPAreaID: 400, Name: "Quarry", ParentMPAreaID: parent(100), TotalTicks: 10},
+				{MPAreaID: 310, Name: "Great Northern Slab", ParentMPAreaID: parent(200), TotalTicks: 2},
+			}, nil
+		}
+		service := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+
+		tree, err := service.GetAreaTree(context.Background(), 10)
+		assert.NoError(t, err)
+		if assert.Len(t, tree, 2) {
+			assert.Equal(t, "Lower Town Wall", tree[0].Name)
+			assert.Equal(t, "Quarry", tree[1].Name)
+			assert.Empty(t, tree[1].Subareas)
+			if assert.Len(t, tree[0].Subareas, 2) {
+				assert.Equal(t, "Morning Glory Wall", tree[0].Subareas[0].Name)
+				assert.Equal(t, "Great Northern Slab", tree[0].Subareas[1].Name)
+			}
+		}
+	})
+
+	t.Run("multiple roots are kept", func(t *testing.T) {
+		climbingRepo := NewMockClimbingRepository()
+		climbingRepo.activity.GetAreaTreeWithActivityFn = func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+			return []models.AreaActivitySummary{
+				{MPAreaID: 1, Name: "Gold Bar", ParentMPAreaID: parent(999)}, // parent outside the location
+				{MPAreaID: 2, Name: "Zeke's Wall"},
+				{MPAreaID: 3, Name: "Clearing", ParentMPAreaID: parent(1)},
+			}, nil
+		}
+		service := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+
+		tree, err := service.GetAreaTree(context.Background(), 10)
+		assert.NoError(t, err)
+		if assert.Len(t, tree, 2) {
+			assert.Equal(t, "Gold Bar", tree[0].Name)
+			assert.Len(t, tree[0].Subareas, 1)
+			assert.Equal(t, "Zeke's Wall", tree[1].Name)
+		}
+	})
+
+	t.Run("no areas", func(t *testing.T) {
+		service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), &MockMPClient{}, nil, nil)
+		tree, err := service.GetAreaTree(context.Background(), 10)
+		assert.NoError(t, err)
+		assert.NotNil(t, tree)
+		assert.Empty(t, tree)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		climbingRepo := NewMockClimbingRepository()
+		climbingRepo.activity.GetAreaTreeWithActivityFn = func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+			return nil, errors.New("connection refused")
+		}
+		service := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+		_, err := service.GetAreaTree(context.Background(), 10)
+		assert.EqualError(t, err, "failed to get area tree for location 10: connection refused")
+	})
+}
+
+// TestRunPriorityRecalculationJob verifies the on-demand recalculation is
+// recorded as a priority_recalculation job and returns the new distribution.
+func TestRunPriorityRecalculationJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mpRepo := NewMockMountainProjectRepository()
+	recalculated := false
+	mpRepo.sync.UpdateRoutePrioritiesFn = func(ctx context.Context) error {
+		recalculated = true
+		return nil
+	}
+	mpRepo.sync.GetPriorityDistributionFn = func(ctx context.Context) (map[string]int, error) {
+		return map[string]int{"high": 12, "medium": 340, "low": 9001}, nil
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), &MockMPClient{}, monitoring.NewJobMonitor(db), nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO woulder.job_executions")).
+		WithArgs("priority_recalculation", "priority_recalculation", monitoring.StatusRunning, 0, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "updated_at"}).AddRow(int64(42), time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE woulder.job_executions")).
+		WithArgs(monitoring.StatusCompleted, sqlmock.AnyArg(), int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := service.RunPriorityRecalculationJob(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, recalculated)
+	if assert.NotNil(t, result.JobID) {
+		assert.Equal(t, int64(42), *result.JobID)
+	}
+	assert.Equal(t, map[string]int{"high": 12, "medium": 340, "low": 9001}, result.Distribution)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRunPriorityRecalculationJob_Failure verifies a failed recalculation
+// marks the tracked job as failed.
+func TestRunPriorityRecalculationJob_Failure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock: %v", err)
+	}
+	defer db.Close()
+
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.sync.UpdateRoutePrioritiesFn = func(ctx context.Context) error {
+		return errors.New("statement timeout")
+	}
+
+	service := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), &MockMPClient{}, monitoring.NewJobMonitor(db), nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO woulder.job_executions")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "started_at", "updated_at"}).AddRow(int64(7), time.Now(), time.Now()))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE woulder.job_executions")).
+		WithArgs(monitoring.StatusFailed, sqlmock.AnyArg(), "failed to recalculate priorities: statement timeout", int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := service.RunPriorityRecalculationJob(context.Background())
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "failed to recalculate priorities: statement timeout")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestNewClimbTrackingService_RequestBudget verifies the shared request
+// budget wraps the MP client without changing what the syncs see, including
+// the per-route fallback for clients that cannot batch comments.
+func TestNewClimbTrackingService_RequestBudget(t *testing.T) {
+	client := &MockMPClient{
+		GetRouteCommentsFn: func(routeID string) ([]mountainproject.Comment, error) {
+			if routeID == "2" {
+				return nil, errors.New("not found")
+			}
+			return []mountainproject.Comment{{ID: 1, Message: "route " + routeID}}, nil
+		},
+		GetRouteTicksPagedFn: func(routeID string, visit func(page []mountainproject.Tick) bool) error {
+			for i := 0; i < 3; i++ {
+				if !visit(make([]mountainproject.Tick, 1)) {
+					return nil
+				}
+			}
+			return nil
+		},
+	}
+
+	unbudgeted := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), client, nil, nil)
+	assert.Same(t, client, unbudgeted.mpClient)
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), client, nil, mountainproject.NewRequestBudget(1000))
+	assert.IsType(t, &budgetedMPClient{}, service.mpClient)
+
+	results := service.fetchRouteComments([]string{"1", "2", "3"})
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "1", results[0].ID)
+		assert.Equal(t, "route 1", results[0].Comments[0].Message)
+		assert.EqualError(t, results[1].Err, "not found")
+		assert.Equal(t, "route 3", results[2].Comments[0].Message)
+	}
+
+	pages := 0
+	err := service.mpClient.GetRouteTicksPaged("1", func(page []mountainproject.Tick) bool {
+		pages++
+		return pages < 2
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pages, "early stop must still reach the wrapped client")
+}
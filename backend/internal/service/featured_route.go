@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+const (
+	// featuredRouteWindowDays is how far back recent ticks count toward a
+	// route's featured weight.
+	featuredRouteWindowDays = 30
+
+	// featuredRouteCandidateLimit caps how many routes the pick is drawn from.
+	featuredRouteCandidateLimit = 200
+
+	// featuredRouteRecentTicks is how many recent ticks are returned with the pick.
+	featuredRouteRecentTicks = 5
+)
+
+// ErrNoFeaturedRoute is returned by GetFeaturedRoute when no route in scope
+// is a classic or has recent ticks.
+var ErrNoFeaturedRoute = errors.New("no featured route candidates")
+
+// FeaturedRoute is the route featured for a day, with its recent activity.
+type FeaturedRoute struct {
+	Date        string                        `json:"date"`                  // Pacific day the pick is for (YYYY-MM-DD)
+	LocationID  *int                          `json:"location_id,omitempty"` // Requested scope (null for all locations)
+	Route       models.FeaturedRouteCandidate `json:"route"`
+	RecentTicks []models.ClimbHistoryEntry    `json:"recent_ticks"`
+}
+
+// GetFeaturedRoute picks the featured route for the Pacific day containing
+// now, optionally scoped to a location (nil for all locations).
+//
+// The pick is a weighted draw seeded by the date and scope, so every request
+// that day gets the same route. Routes are weighted toward classics (MP
+// stars) and toward routes ticked over the last featuredRouteWindowDays,
+// which stands in for recent good conditions. Only ticks dated before today
+// count, so new ticks synced during the day don't change the pick. Live
+// drying status is deliberately not part of the weight: it changes hourly.
+func (s *ClimbTrackingService) GetFeaturedRoute(ctx context.Context, locationID *int, now time.Time) (*FeaturedRoute, error) {
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Pacific timezone: %w", err)
+	}
+	local := now.In(pacificTZ)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, pacificTZ)
+	date := dayStart.Format("2006-01-02")
+
+	candidates, err := s.climbingRepo.Activity().GetFeaturedRouteCandidates(
+		ctx, locationID, dayStart.AddDate(0, 0, -featuredRouteWindowDays), dayStart, featuredRouteCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured route candidates: %w", err)
+	}
+
+	seed := date + "|all"
+	if locationID != nil {
+		seed = date + "|location:" + strconv.Itoa(*locationID)
+	}
+	route, ok := pickFeaturedRoute(candidates, seed)
+	if !ok {
+		return nil, ErrNoFeaturedRoute
+	}
+
+	ticks, err := s.climbingRepo.Activity().GetRecentTicksForRoute(ctx, route.MPRouteID, featuredRouteRecentTicks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent ticks for route %d: %w", route.MPRouteID, err)
+	}
+	if ticks == nil {
+		ticks = []models.ClimbHistoryEntry{}
+	}
+
+	return &FeaturedRoute{
+		Date:        date,
+		LocationID:  locationID,
+		Route:       route,
+		RecentTicks: ticks,
+	}, nil
+}
+
+// pickFeaturedRoute makes a weighted draw from candidates, determined
+// entirely by seed. candidates must be in a stable order (the repository
+// returns them by route ID).
+func pickFeaturedRoute(candidates []models.FeaturedRouteCandidate, seed string) (models.FeaturedRouteCandidate, bool) {
+	if len(candidates) == 0 {
+		return models.FeaturedRouteCandidate{}, false
+	}
+
+	weights := make([]float64, len(candidates))
+	total := 0.0
+	for i, c := range candidates {
+		weights[i] = featuredRouteWeight(c)
+		total += weights[i]
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	// Top 53 bits as a uniform fraction in [0, 1).
+	target := float64(h.Sum64()>>11) / (1 << 53) * total
+
+	for i, w := range weights {
+		if target < w {
+			return candidates[i], true
+		}
+		target -= w
+	}
+	return candidates[len(candidates)-1], true
+}
+
+// featuredRouteWeight favors classics quadratically by stars (unrated 1,
+// 3★ 10, 4★ 17) and recently climbed routes logarithmically by tick count,
+// so a busy route can't drown out every classic.
+func featuredRouteWeight(c models.FeaturedRouteCandidate) float64 {
+	stars := 0.0
+	if c.MPRating != nil {
+		stars = math.Max(0, math.Min(4, *c.MPRating))
+	}
+	return (1 + stars*stars) * (1 + math.Log1p(float64(c.RecentTicks)))
+}
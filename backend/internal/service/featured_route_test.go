@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func featuredCandidates() []models.FeaturedRouteCandidate {
+	stars := func(v float64) *float64 { return &v }
+	return []models.FeaturedRouteCandidate{
+		{MPRouteID: 101, Name: "Classic", LocationID: 1, MPRating: stars(4)},
+		{MPRouteID: 102, Name: "Busy", LocationID: 1, RecentTicks: 12},
+		{MPRouteID: 103, Name: "Solid", LocationID: 1, MPRating: stars(3), RecentTicks: 2},
+		{MPRouteID: 104, Name: "Obscure", LocationID: 1, RecentTicks: 1},
+	}
+}
+
+func TestGetFeaturedRoute_StableForTheDay(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	require.NoError(t, err)
+	dayStart := time.Date(2026, 3, 10, 0, 0, 0, 0, pacific)
+
+	climbingRepo := NewMockClimbingRepository()
+	climbingRepo.activity.GetFeaturedRouteCandidatesFn = func(ctx context.Context, locationID *int, since, until time.Time, limit int) ([]models.FeaturedRouteCandidate, error) {
+		assert.True(t, until.Equal(dayStart), "window should end at the start of the Pacific day, got %v", until)
+		assert.True(t, since.Equal(dayStart.AddDate(0, 0, -featuredRouteWindowDays)), "window start = %v", since)
+		return featuredCandidates(), nil
+	}
+	climbingRepo.activity.GetRecentTicksForRouteFn = func(ctx context.Context, routeID int64, limit int) ([]models.ClimbHistoryEntry, error) {
+		return []models.ClimbHistoryEntry{{MPRouteID: routeID}}, nil
+	}
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, nil, nil, nil)
+
+	locationID := 1
+	morning, err := svc.GetFeaturedRoute(context.Background(), &locationID, dayStart.Add(30*time.Minute))
+	require.NoError(t, err)
+	evening, err := svc.GetFeaturedRoute(context.Background(), &locationID, dayStart.Add(23*time.Hour+50*time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-03-10", morning.Date)
+	assert.Equal(t, morning.Route, evening.Route, "pick should not change during the day")
+	assert.Equal(t, &locationID, morning.LocationID)
+	require.Len(t, morning.RecentTicks, 1)
+	assert.Equal(t, morning.Route.MPRouteID, morning.RecentTicks[0].MPRouteID)
+}
+
+func TestGetFeaturedRoute_NoCandidates(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetFeaturedRoute(context.Background(), nil, time.Now())
+	assert.True(t, errors.Is(err, ErrNoFeaturedRoute), "got %v", err)
+}
+
+func TestPickFeaturedRoute(t *testing.T) {
+	candidates := featuredCandidates()
+
+	first, ok := pickFeaturedRoute(candidates, "2026-03-10|location:1")
+	require.True(t, ok)
+	again, _ := pickFeaturedRoute(candidates, "2026-03-10|location:1")
+	assert.Equal(t, first, again, "same seed should give the same pick")
+
+	_, ok = pickFeaturedRoute(nil, "2026-03-10|all")
+	assert.False(t, ok)
+
+	// Over a year of days every candidate should come up, weighted toward
+	// the 4-star classic over the unrated route with a single tick.
+	counts := make(map[int64]int)
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 365; i++ {
+		route, _ := pickFeaturedRoute(candidates, fmt.Sprintf("%s|all", day.AddDate(0, 0, i).Format("2006-01-02")))
+		counts[route.MPRouteID]++
+	}
+	for _, c := range candidates {
+		assert.Positive(t, counts[c.MPRouteID], "route %d never picked", c.MPRouteID)
+	}
+	assert.Greater(t, counts[101], counts[104]*3, "classic should be picked far more often: %v", counts)
+}
+
+func TestFeaturedRouteWeight(t *testing.T) {
+	stars := func(v float64) *float64 { return &v }
+
+	unrated := featuredRouteWeight(models.FeaturedRouteCandidate{})
+	classic := featuredRouteWeight(models.FeaturedRouteCandidate{MPRating: stars(4)})
+	busyClassic := featuredRouteWeight(models.FeaturedRouteCandidate{MPRating: stars(4), RecentTicks: 10})
+	overRated := featuredRouteWeight(models.FeaturedRouteCandidate{MPRating: stars(9)})
+
+	assert.Equal(t, 1.0, unrated)
+	assert.Equal(t, 17.0, classic)
+	assert.Greater(t, busyClassic, classic)
+	assert.Equal(t, classic, overRated, "stars should be clamped to 4")
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/grades"
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// gradeDistributionRouteTypes are the Mountain Project route types accepted
+// as a grade distribution filter, keyed by lowercase name.
+var gradeDistributionRouteTypes = map[string]string{
+	"boulder": "Boulder",
+	"sport":   "Sport",
+	"trad":    "Trad",
+	"tr":      "TR",
+	"ice":     "Ice",
+	"mixed":   "Mixed",
+	"alpine":  "Alpine",
+	"aid":     "Aid",
+	"snow":    "Snow",
+}
+
+// NormalizeRouteTypeFilter returns the Mountain Project route type named by
+// routeType, case-insensitively, and whether it is one. An empty routeType
+// is valid and means no filter.
+func NormalizeRouteTypeFilter(routeType string) (string, bool) {
+	routeType = strings.TrimSpace(routeType)
+	if routeType == "" {
+		return "", true
+	}
+	normalized, ok := gradeDistributionRouteTypes[strings.ToLower(routeType)]
+	return normalized, ok
+}
+
+// GetLocationGradeDistribution counts a location's routes by grade band,
+// optionally only routes of routeType (see NormalizeRouteTypeFilter).
+func (s *ClimbTrackingService) GetLocationGradeDistribution(ctx context.Context, locationID int, routeType string) (*models.GradeDistribution, error) {
+	routeType, ok := NormalizeRouteTypeFilter(routeType)
+	if !ok {
+		return nil, fmt.Errorf("invalid route type %q", routeType)
+	}
+
+	counts, err := s.mountainProjectRepo.Routes().GetGradeCountsByLocation(ctx, locationID, routeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count grades for location %d: %w", locationID, err)
+	}
+	return buildGradeDistribution(counts, routeType), nil
+}
+
+// GetAreaGradeDistribution is GetLocationGradeDistribution over the routes
+// in an area and its subareas.
+func (s *ClimbTrackingService) GetAreaGradeDistribution(ctx context.Context, areaID int64, routeType string) (*models.GradeDistribution, error) {
+	routeType, ok := NormalizeRouteTypeFilter(routeType)
+	if !ok {
+		return nil, fmt.Errorf("invalid route type %q", routeType)
+	}
+
+	area, err := s.mountainProjectRepo.Areas().GetAreaByID(ctx, areaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get area %d: %w", areaID, err)
+	}
+	if area == nil {
+		return nil, fmt.Errorf("area %d: %w", areaID, ErrAreaNotFound)
+	}
+
+	counts, err := s.mountainProjectRepo.Routes().GetGradeCountsByArea(ctx, areaID, routeType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count grades for area %d: %w", areaID, err)
+	}
+	return buildGradeDistribution(counts, routeType), nil
+}
+
+// buildGradeDistribution buckets per-grade route counts into bands. Routes
+// whose grade_order is missing or outside every band count as ungraded.
+func buildGradeDistribution(counts []mountainproject.GradeCount, routeType string) *models.GradeDistribution {
+	dist := &models.GradeDistribution{RouteType: routeType, Bands: []models.GradeBandCount{}}
+
+	bandCounts := make(map[string]int)
+	families := make(map[string]bool)
+	for _, c := range counts {
+		dist.TotalRoutes += c.Count
+		if c.GradeOrder == nil {
+			dist.UngradedRoutes += c.Count
+			continue
+		}
+		band, ok := grades.BandForOrder(*c.GradeOrder)
+		if !ok {
+			dist.UngradedRoutes += c.Count
+			continue
+		}
+		bandCounts[band.Name] += c.Count
+		families[band.Family] = true
+	}
+
+	for _, band := range grades.Bands() {
+		if !families[band.Family] {
+			continue
+		}
+		dist.Bands = append(dist.Bands, models.GradeBandCount{
+			Band:     band.Name,
+			Family:   band.Family,
+			MinGrade: grades.OrderToGrade(band.MinOrder),
+			MaxGrade: grades.OrderToGrade(band.MaxOrder),
+			MinOrder: band.MinOrder,
+			MaxOrder: band.MaxOrder,
+			Count:    bandCounts[band.Name],
+		})
+	}
+	return dist
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
+	"github.com/alexscott64/woulder/backend/internal/grades"
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLocationGradeDistribution_BucketsByBand(t *testing.T) {
+	v1 := grades.ToOrder("V1")
+	v4 := grades.ToOrder("V4")
+	v5 := grades.ToOrder("V5")
+
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetGradeCountsByLocationFn = func(ctx context.Context, locationID int, routeType string) ([]mountainproject.GradeCount, error) {
+		assert.Equal(t, 3, locationID)
+		assert.Equal(t, "Boulder", routeType)
+		return []mountainproject.GradeCount{
+			{GradeOrder: &v1, Count: 2},
+			{GradeOrder: &v4, Count: 5},
+			{GradeOrder: &v5, Count: 1},
+			{GradeOrder: nil, Count: 4},
+		}, nil
+	}
+	svc := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), nil, nil, nil)
+
+	dist, err := svc.GetLocationGradeDistribution(context.Background(), 3, "boulder")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Boulder", dist.RouteType)
+	assert.Equal(t, 12, dist.TotalRoutes)
+	assert.Equal(t, 4, dist.UngradedRoutes)
+
+	// Only the V-scale is present, with every band listed.
+	require.Len(t, dist.Bands, 5)
+	assert.Equal(t, models.GradeBandCount{
+		Band: "V0-V2", Family: grades.FamilyV, MinGrade: "V0", MaxGrade: "V2",
+		MinOrder: grades.ToOrder("V0"), MaxOrder: grades.ToOrder("V2"), Count: 2,
+	}, dist.Bands[0])
+	assert.Equal(t, 6, dist.Bands[1].Count)
+	assert.Equal(t, 0, dist.Bands[4].Count)
+}
+
+func TestGetLocationGradeDistribution_InvalidRouteType(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetLocationGradeDistribution(context.Background(), 3, "Boulder'; --")
+	assert.Error(t, err)
+}
+
+func TestGetAreaGradeDistribution_AreaNotFound(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetAreaGradeDistribution(context.Background(), 7, "")
+	assert.True(t, errors.Is(err, ErrAreaNotFound), "err = %v", err)
+}
+
+func TestGetAreaGradeDistribution_EmptyArea(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.areas.GetAreaByIDFn = func(ctx context.Context, mpAreaID int64) (*models.MPArea, error) {
+		return &models.MPArea{MPAreaID: 7}, nil
+	}
+	svc := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), nil, nil, nil)
+
+	dist, err := svc.GetAreaGradeDistribution(context.Background(), 7, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, dist.TotalRoutes)
+	assert.NotNil(t, dist.Bands)
+	assert.Empty(t, dist.Bands)
+}
+
+func TestNormalizeRouteTypeFilter(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"", "", true},
+		{"sport", "Sport", true},
+		{" TR ", "TR", true},
+		{"Boulder,Sport", "", false},
+		{"5.10", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeRouteTypeFilter(tt.in)
+		assert.Equal(t, tt.want, got, "NormalizeRouteTypeFilter(%q)", tt.in)
+		assert.Equal(t, tt.ok, ok, "NormalizeRouteTypeFilter(%q) ok", tt.in)
+	}
+}
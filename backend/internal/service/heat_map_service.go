@@ -28,6 +28,7 @@ func (s *HeatMapService) GetHeatMapData(
 	routeTypes []string,
 	lightweight bool,
 	gradeOrders []int,
+	excludeClosed bool,
 ) ([]models.HeatMapPoint, error) {
 	// Validate inputs
 	if startDate.After(endDate) {
@@ -49,7 +50,7 @@ func (s *HeatMapService) GetHeatMapData(
 	}
 
 	// Fetch raw data with route type filtering, grade filtering, and lightweight option
-	points, err := s.heatMapRepo.GetHeatMapData(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders)
+	points, err := s.heatMapRepo.GetHeatMapData(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders, excludeClosed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch heat map data: %w", err)
 	}
@@ -128,6 +129,7 @@ func (s *HeatMapService) GetRoutesByBounds(
 	bounds heatmap.GeoBounds,
 	startDate, endDate time.Time,
 	limit int,
+	excludeClosed bool,
 ) ([]models.RouteActivity, error) {
 	if err := bounds.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid bounds: %w", err)
@@ -141,7 +143,7 @@ func (s *HeatMapService) GetRoutesByBounds(
 		limit = 100 // Default to 100 for routes
 	}
 
-	routes, err := s.heatMapRepo.GetRoutesByBounds(ctx, bounds, startDate, endDate, limit)
+	routes, err := s.heatMapRepo.GetRoutesByBounds(ctx, bounds, startDate, endDate, limit, excludeClosed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch routes: %w", err)
 	}
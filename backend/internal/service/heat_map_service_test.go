@@ -17,7 +17,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 
 	t.Run("successfully retrieves and calculates activity scores", func(t *testing.T) {
 		mockRepo := &MockHeatMapRepository{
-			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 				return []models.HeatMapPoint{
 					{
 						MPAreaID:       1,
@@ -44,7 +44,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 		}
 
 		service := NewHeatMapService(mockRepo)
-		points, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, nil, false, nil)
+		points, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, nil, false, nil, false)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -74,7 +74,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 		service := NewHeatMapService(mockRepo)
 
 		// Invalid: start after end
-		_, err := service.GetHeatMapData(ctx, now, thirtyDaysAgo, nil, 1, 500, nil, false, nil)
+		_, err := service.GetHeatMapData(ctx, now, thirtyDaysAgo, nil, 1, 500, nil, false, nil, false)
 
 		if err == nil {
 			t.Error("Expected error for invalid date range")
@@ -84,7 +84,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 	t.Run("passes grade orders filter to repository", func(t *testing.T) {
 		var receivedGradeOrders []int
 		mockRepo := &MockHeatMapRepository{
-			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 				receivedGradeOrders = gradeOrders
 				return []models.HeatMapPoint{
 					{
@@ -101,7 +101,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 
 		// Test boulder V9-V17 filtering (the reported bug scenario)
 		boulderGradeOrders := []int{9, 10, 11, 12, 13, 14, 15, 16, 17}
-		points, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, []string{"Boulder"}, false, boulderGradeOrders)
+		points, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, []string{"Boulder"}, false, boulderGradeOrders, false)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -124,7 +124,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 		var receivedGradeOrders []int
 		var receivedRouteTypes []string
 		mockRepo := &MockHeatMapRepository{
-			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 				receivedGradeOrders = gradeOrders
 				receivedRouteTypes = routeTypes
 				return []models.HeatMapPoint{}, nil
@@ -136,7 +136,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 		// Multi-type: Boulder V0-V2 + Ice WI1-WI3
 		multiGradeOrders := []int{0, 1, 2, 200, 201, 202}
 		multiRouteTypes := []string{"Boulder", "Ice"}
-		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, multiRouteTypes, false, multiGradeOrders)
+		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, multiRouteTypes, false, multiGradeOrders, false)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -172,14 +172,14 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 	t.Run("nil grade orders passes through (no filtering)", func(t *testing.T) {
 		var receivedGradeOrders []int
 		mockRepo := &MockHeatMapRepository{
-			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+			GetHeatMapDataFn: func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 				receivedGradeOrders = gradeOrders
 				return []models.HeatMapPoint{}, nil
 			},
 		}
 
 		service := NewHeatMapService(mockRepo)
-		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, nil, false, nil)
+		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, nil, 1, 500, nil, false, nil, false)
 
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
@@ -201,7 +201,7 @@ func TestHeatMapService_GetHeatMapData(t *testing.T) {
 			MaxLon: -120.0,
 		}
 
-		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, invalidBounds, 1, 500, nil, false, nil)
+		_, err := service.GetHeatMapData(ctx, thirtyDaysAgo, now, invalidBounds, 1, 500, nil, false, nil, false)
 
 		if err == nil {
 			t.Error("Expected error for invalid bounds")
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"math"
 	"strings"
+
+	"github.com/alexscott64/woulder/backend/internal/kayamatch"
 )
 
 // KayaMPMatchingService handles intelligent matching between Kaya climbs and Mountain Project routes
@@ -88,7 +90,7 @@ func calculateNameSimilarity(name1, name2 string) float64 {
 
 // normalizeRouteName standardizes route names for comparison
 func normalizeRouteName(name string) string {
-	name = strings.ToLower(name)
+	name = kayamatch.FoldDiacritics(strings.ToLower(name))
 
 	// Remove common prefixes
 	name = strings.TrimPrefix(name, "the ")
@@ -222,51 +224,16 @@ func matchLocationNames(kayaLocation, mpAreaHierarchy string) bool {
 	return false
 }
 
-// calculateMatchConfidence computes overall match confidence score
+// calculateMatchConfidence computes overall match confidence score with the
+// default thresholds (see kayamatch.Thresholds.Confidence)
 func calculateMatchConfidence(nameSim float64, locationMatch bool, distanceKM *float64) float64 {
-	confidence := nameSim * 0.7 // Name similarity weighted 70%
-
-	if locationMatch {
-		confidence += 0.2 // Location name match adds 20%
-	}
-
-	if distanceKM != nil && *distanceKM < 5.0 {
-		// GPS proximity bonus (up to 10%)
-		proximityBonus := 0.1 * (1.0 - (*distanceKM / 5.0))
-		confidence += proximityBonus
-	}
-
-	// Cap at 1.0
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-
-	return confidence
+	return kayamatch.DefaultThresholds().Confidence(nameSim, locationMatch, distanceKM)
 }
 
-// determineMatchType classifies the match based on characteristics
+// determineMatchType classifies the match with the default thresholds (see
+// kayamatch.Thresholds.MatchType)
 func determineMatchType(nameSim float64, locationMatch bool, distanceKM *float64) string {
-	if nameSim == 1.0 {
-		return "exact_name"
-	}
-
-	if nameSim >= 0.9 && locationMatch {
-		return "fuzzy_name_location"
-	}
-
-	if nameSim >= 0.85 {
-		return "fuzzy_name"
-	}
-
-	if locationMatch && distanceKM != nil && *distanceKM < 1.0 {
-		return "location_gps_proximity"
-	}
-
-	if locationMatch {
-		return "location_name"
-	}
-
-	return "low_confidence"
+	return kayamatch.DefaultThresholds().MatchType(nameSim, locationMatch, distanceKM)
 }
 
 // SaveMatch persists a route match to the database
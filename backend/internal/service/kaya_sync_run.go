@@ -0,0 +1,43 @@
+package service
+
+import "sync"
+
+// KayaSyncRun records what one sync run has saved: climb slugs, ascent IDs
+// and sub-location IDs. Content reached through more than one location is
+// saved and counted once. It is safe for concurrent use, so services syncing
+// in parallel can share one run (see SetSyncRun).
+type KayaSyncRun struct {
+	mu           sync.Mutex
+	climbs       map[string]struct{}
+	ascents      map[string]struct{}
+	subLocations map[string]struct{}
+}
+
+// NewKayaSyncRun returns an empty run.
+func NewKayaSyncRun() *KayaSyncRun {
+	return &KayaSyncRun{
+		climbs:       make(map[string]struct{}),
+		ascents:      make(map[string]struct{}),
+		subLocations: make(map[string]struct{}),
+	}
+}
+
+// claim adds key to set, reporting false if it was already there, i.e. the
+// item was saved (or is being saved) by another sync in this run. A caller
+// that fails to save a claimed item should release it.
+func (r *KayaSyncRun) claim(set map[string]struct{}, key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, done := set[key]; done {
+		return false
+	}
+	set[key] = struct{}{}
+	return true
+}
+
+// release removes key from set so a later sync can save it.
+func (r *KayaSyncRun) release(set map[string]struct{}, key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(set, key)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	kayaDB "github.com/alexscott64/woulder/backend/internal/database/kaya"
+	"github.com/alexscott64/woulder/backend/internal/geo"
 	kayaClient "github.com/alexscott64/woulder/backend/internal/kaya"
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/alexscott64/woulder/backend/internal/monitoring"
@@ -44,13 +46,25 @@ type KayaClientInterface interface {
 var _ KayaClientInterface = (*kayaClient.Client)(nil)
 var _ KayaClientInterface = (*kayaClient.BrowserClient)(nil)
 
-// KayaSyncService handles Kaya data synchronization and retrieval
+// KayaSyncService handles Kaya data synchronization and retrieval.
+//
+// A service instance is one sync run: climbs, ascents and sub-locations it
+// has already saved are skipped and not counted again, so content reached
+// through both a location and its sub-location, or through parent and child
+// targets in the same job, is counted once in the sync counters. Services
+// syncing in parallel share a run with SetSyncRun.
 type KayaSyncService struct {
 	kayaRepo   kayaDB.Repository
 	kayaClient KayaClientInterface
 	jobMonitor *monitoring.JobMonitor
 	syncMutex  sync.Mutex
 	isSyncing  bool
+
+	// skipClosed skips locations stored as closed. See SetSkipClosed.
+	skipClosed bool
+
+	// run records what this sync run has saved. See KayaSyncRun.
+	run *KayaSyncRun
 }
 
 // NewKayaSyncService creates a new Kaya sync service
@@ -63,24 +77,55 @@ func NewKayaSyncService(
 		kayaRepo:   kayaRepo,
 		kayaClient: kayaClient,
 		jobMonitor: jobMonitor,
+		run:        NewKayaSyncRun(),
 	}
 }
 
+// SetSyncRun makes the service record saved content in run, so services
+// syncing different locations in parallel still save and count shared
+// content once. Call it before syncing.
+func (s *KayaSyncService) SetSyncRun(run *KayaSyncRun) {
+	s.run = run
+}
+
+// ErrLocationClosed is returned by SyncLocationBySlug and SyncDueLocation for
+// a location skipped because it is closed. See SetSkipClosed.
+var ErrLocationClosed = errors.New("location is closed")
+
+// kayaStatusSkipped is the kaya_sync_progress status of a location skipped
+// because it is closed. The sync queue picks it again once its next sync,
+// kayaClosedRecheckInterval after the skip, is due.
+const kayaStatusSkipped = "skipped"
+
+// kayaClosedRecheckInterval is how long a closed location is skipped before
+// the queue syncs it again to learn whether it has reopened.
+const kayaClosedRecheckInterval = 30 * 24 * time.Hour
+
+// SetSkipClosed toggles skipping locations whose stored copy is marked
+// closed: no Kaya API calls are made for them, the skip is recorded in
+// kaya_sync_progress and the sync returns ErrLocationClosed. A location not
+// stored yet is synced once, which records whether it is closed, and a
+// skipped location the queue hands back for its periodic recheck is synced
+// too.
+func (s *KayaSyncService) SetSkipClosed(enabled bool) {
+	s.skipClosed = enabled
+}
+
 // SyncLocationBySlug syncs a single location and optionally its sub-locations
 func (s *KayaSyncService) SyncLocationBySlug(ctx context.Context, slug string, recursive bool) error {
-	s.syncMutex.Lock()
-	if s.isSyncing {
-		s.syncMutex.Unlock()
-		return fmt.Errorf("sync already in progress")
+	if err := s.beginSync(); err != nil {
+		return err
 	}
-	s.isSyncing = true
-	s.syncMutex.Unlock()
+	defer s.endSync()
 
-	defer func() {
-		s.syncMutex.Lock()
-		s.isSyncing = false
-		s.syncMutex.Unlock()
-	}()
+	if s.skipClosed {
+		stored, err := s.kayaRepo.Locations().GetLocationBySlug(ctx, slug)
+		if err != nil {
+			log.Printf("[Kaya] Warning: failed to look up stored location %s, syncing it: %v", slug, err)
+		} else if err := s.skipIfClosed(ctx, stored); err != nil {
+			return err
+		}
+	}
 
 	log.Printf("[Kaya] Starting sync for location slug: %s (recursive: %v)", slug, recursive)
 
@@ -110,40 +155,7 @@ func (s *KayaSyncService) SyncLocationBySlug(ctx context.Context, slug string, r
 		log.Printf("[Kaya] Warning: failed to save sync progress: %v", err)
 	}
 
-	var syncError error
-
-	// Sync climbs for this location (both boulders and routes)
-	climbsSynced := 0
-	if climbs, err := s.syncClimbsForLocation(ctx, location.ID); err != nil {
-		log.Printf("[Kaya] Warning: failed to sync climbs for location %s: %v", location.ID, err)
-		syncError = err
-	} else {
-		climbsSynced = climbs
-	}
-
-	// Sync ascents for this location
-	ascentsSynced := 0
-	if ascents, err := s.syncAscentsForLocation(ctx, location.ID); err != nil {
-		log.Printf("[Kaya] Warning: failed to sync ascents for location %s: %v", location.ID, err)
-		if syncError == nil {
-			syncError = err
-		}
-	} else {
-		ascentsSynced = ascents
-	}
-
-	// Sync sub-locations if recursive
-	subLocationsSynced := 0
-	if recursive {
-		if subLocs, err := s.syncSubLocations(ctx, location.ID); err != nil {
-			log.Printf("[Kaya] Warning: failed to sync sub-locations for %s: %v", location.ID, err)
-			if syncError == nil {
-				syncError = err
-			}
-		} else {
-			subLocationsSynced = subLocs
-		}
-	}
+	climbsSynced, ascentsSynced, subLocationsSynced, syncError := s.syncLocationContent(ctx, location.ID, recursive)
 
 	// Update sync progress
 	status := "completed"
@@ -154,7 +166,7 @@ func (s *KayaSyncService) SyncLocationBySlug(ctx context.Context, slug string, r
 		errMsg = &msg
 	}
 
-	nextSync := time.Now().Add(24 * time.Hour) // Re-sync daily
+	nextSync := time.Now().Add(kayaResyncInterval)
 	progress = &models.KayaSyncProgress{
 		KayaLocationID:     location.ID,
 		LocationName:       location.Name,
@@ -176,6 +188,195 @@ func (s *KayaSyncService) SyncLocationBySlug(ctx context.Context, slug string, r
 	return syncError
 }
 
+// skipIfClosed records a skip and returns ErrLocationClosed if stored, the
+// stored copy of a location about to be synced, is closed. It returns nil
+// for an open location or one not stored yet (stored nil).
+func (s *KayaSyncService) skipIfClosed(ctx context.Context, stored *models.KayaLocation) error {
+	if stored == nil || !stored.IsClosed {
+		return nil
+	}
+
+	closed := "closed"
+	if stored.ClosedDate != nil {
+		closed = "closed since " + stored.ClosedDate.Format(time.DateOnly)
+	}
+	log.Printf("[Kaya] Skipping %s (%s)", stored.Name, closed)
+
+	// Keep an existing row's counters; only a location that was never
+	// tracked needs a new one. Either way the queue rechecks it later.
+	recheckAt := time.Now().Add(kayaClosedRecheckInterval)
+	existing, err := s.kayaRepo.Sync().GetSyncProgress(ctx, stored.KayaLocationID)
+	if err == nil && existing != nil {
+		err = s.kayaRepo.Sync().UpdateSyncStatus(ctx, stored.KayaLocationID, kayaStatusSkipped, nil)
+		if err == nil {
+			err = s.kayaRepo.Sync().ScheduleNextSync(ctx, stored.KayaLocationID, recheckAt, false)
+		}
+	} else if err == nil {
+		err = s.kayaRepo.Sync().SaveSyncProgress(ctx, &models.KayaSyncProgress{
+			KayaLocationID: stored.KayaLocationID,
+			LocationName:   stored.Name,
+			Status:         kayaStatusSkipped,
+			NextSyncAt:     &recheckAt,
+		})
+	}
+	if err != nil {
+		log.Printf("[Kaya] Warning: failed to record skip for %s: %v", stored.Name, err)
+	}
+
+	return fmt.Errorf("%s %s: %w", stored.Name, closed, ErrLocationClosed)
+}
+
+// kayaResyncInterval is how long after a sync a location is due again.
+const kayaResyncInterval = 24 * time.Hour
+
+// beginSync marks the service as syncing, failing if a sync is already
+// running. Callers must defer endSync on success.
+func (s *KayaSyncService) beginSync() error {
+	s.syncMutex.Lock()
+	defer s.syncMutex.Unlock()
+	if s.isSyncing {
+		return fmt.Errorf("sync already in progress")
+	}
+	s.isSyncing = true
+	return nil
+}
+
+func (s *KayaSyncService) endSync() {
+	s.syncMutex.Lock()
+	s.isSyncing = false
+	s.syncMutex.Unlock()
+}
+
+// GetLocationsDueForSync returns up to limit locations from the sync queue
+// whose next sync is due, pending and failed locations first.
+func (s *KayaSyncService) GetLocationsDueForSync(ctx context.Context, limit int) ([]*models.KayaSyncProgress, error) {
+	return s.kayaRepo.Sync().GetLocationsDueForSync(ctx, limit)
+}
+
+// SyncDueLocation syncs a location taken from the sync queue and updates its
+// queue entry: status and error, cumulative counters, and the next sync time.
+// The next sync is scheduled even when the sync fails, so a broken location
+// is retried once it is due again without starving the rest of the queue;
+// only a successful sync updates the last sync time.
+func (s *KayaSyncService) SyncDueLocation(ctx context.Context, progress *models.KayaSyncProgress, recursive bool) error {
+	if err := s.beginSync(); err != nil {
+		return err
+	}
+	defer s.endSync()
+
+	id := progress.KayaLocationID
+	// A skipped location is only handed back once its recheck is due; sync
+	// it to learn whether it has reopened.
+	if s.skipClosed && progress.Status != kayaStatusSkipped {
+		stored, err := s.kayaRepo.Locations().GetLocationByID(ctx, id)
+		if err != nil {
+			log.Printf("[Kaya] Warning: failed to look up stored location %s, syncing it: %v", id, err)
+		} else if err := s.skipIfClosed(ctx, stored); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[Kaya] Starting queued sync for %s (%s, status: %s)", progress.LocationName, id, progress.Status)
+
+	if err := s.kayaRepo.Sync().UpdateSyncStatus(ctx, id, "in_progress", nil); err != nil {
+		log.Printf("[Kaya] Warning: failed to mark %s in progress: %v", id, err)
+	}
+
+	climbs, ascents, subLocations, syncError := s.syncQueuedLocation(ctx, id, recursive)
+
+	if err := s.kayaRepo.Sync().IncrementSyncCounters(ctx, id, climbs, ascents, subLocations); err != nil {
+		log.Printf("[Kaya] Warning: failed to update sync counters for %s: %v", id, err)
+	}
+
+	status := "completed"
+	var errMsg *string
+	if syncError != nil {
+		status = "failed"
+		msg := syncError.Error()
+		errMsg = &msg
+	}
+	if err := s.kayaRepo.Sync().UpdateSyncStatus(ctx, id, status, errMsg); err != nil {
+		log.Printf("[Kaya] Warning: failed to update sync status for %s: %v", id, err)
+	}
+	if err := s.kayaRepo.Sync().ScheduleNextSync(ctx, id, time.Now().Add(kayaResyncInterval), syncError == nil); err != nil {
+		log.Printf("[Kaya] Warning: failed to schedule next sync for %s: %v", id, err)
+	}
+
+	log.Printf("[Kaya] Queued sync %s for %s: climbs=%d, ascents=%d, sub-locations=%d",
+		status, progress.LocationName, climbs, ascents, subLocations)
+
+	return syncError
+}
+
+// syncQueuedLocation refreshes a queued location from the Kaya API, resolving
+// its slug from the stored location, then syncs its content.
+func (s *KayaSyncService) syncQueuedLocation(ctx context.Context, kayaLocationID string, recursive bool) (int, int, int, error) {
+	stored, err := s.kayaRepo.Locations().GetLocationByID(ctx, kayaLocationID)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to load location %s: %w", kayaLocationID, err)
+	}
+	if stored == nil {
+		return 0, 0, 0, fmt.Errorf("location %s not found in database", kayaLocationID)
+	}
+
+	location, err := s.kayaClient.GetLocation(stored.Slug)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to fetch location %s: %w", stored.Slug, err)
+	}
+	if location == nil {
+		return 0, 0, 0, fmt.Errorf("location not found: %s", stored.Slug)
+	}
+	if err := s.saveLocation(ctx, location); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to save location: %w", err)
+	}
+
+	return s.syncLocationContent(ctx, location.ID, recursive)
+}
+
+// syncLocationContent syncs climbs, ascents and, if recursive, sub-locations
+// for a saved location. Steps continue past individual failures except an
+// expired token, which fails every later request; the returned error is the
+// first failure, or the token error if one was seen. Climb and ascent counts
+// include sub-location content not already counted in this run.
+func (s *KayaSyncService) syncLocationContent(ctx context.Context, locationID string, recursive bool) (climbsSynced, ascentsSynced, subLocationsSynced int, syncError error) {
+	// Sync climbs for this location (both boulders and routes)
+	if climbs, err := s.syncClimbsForLocation(ctx, locationID); err != nil {
+		log.Printf("[Kaya] Warning: failed to sync climbs for location %s: %v", locationID, err)
+		syncError = err
+	} else {
+		climbsSynced = climbs
+	}
+
+	// Sync ascents for this location. An expired token fails every later
+	// request, so the remaining steps are skipped once one is seen.
+	if !isKayaTokenExpired(syncError) {
+		if ascents, err := s.syncAscentsForLocation(ctx, locationID); err != nil {
+			log.Printf("[Kaya] Warning: failed to sync ascents for location %s: %v", locationID, err)
+			if syncError == nil || isKayaTokenExpired(err) {
+				syncError = err
+			}
+		} else {
+			ascentsSynced = ascents
+		}
+	}
+
+	// Sync sub-locations if recursive
+	if recursive && !isKayaTokenExpired(syncError) {
+		subLocs, subClimbs, subAscents, err := s.syncSubLocations(ctx, locationID)
+		subLocationsSynced = subLocs
+		climbsSynced += subClimbs
+		ascentsSynced += subAscents
+		if err != nil {
+			log.Printf("[Kaya] Warning: failed to sync sub-locations for %s: %v", locationID, err)
+			if syncError == nil || isKayaTokenExpired(err) {
+				syncError = err
+			}
+		}
+	}
+
+	return climbsSynced, ascentsSynced, subLocationsSynced, syncError
+}
+
 // saveLocation converts API location to model and saves it
 func (s *KayaSyncService) saveLocation(ctx context.Context, apiLoc *kayaClient.WebLocation) error {
 	// Convert string lat/lon to float64
@@ -190,6 +391,10 @@ func (s *KayaSyncService) saveLocation(ctx context.Context, apiLoc *kayaClient.W
 			lon = &f
 		}
 	}
+	if lat != nil && lon != nil && !geo.IsPlausibleCoord(*lat, *lon) {
+		log.Printf("Kaya location %s has implausible coordinates (%v, %v); saving without GPS", apiLoc.Slug, *lat, *lon)
+		lat, lon = nil, nil
+	}
 
 	loc := &models.KayaLocation{
 		KayaLocationID:                    apiLoc.ID,
@@ -219,6 +424,12 @@ func (s *KayaSyncService) saveLocation(ctx context.Context, apiLoc *kayaClient.W
 		ClimbTypeID:                       apiLoc.ClimbTypeID,
 	}
 
+	// Link mapped destinations to their Woulder location so the location
+	// detail can show Kaya's access and approach beta
+	if woulderLocID, ok := kayaDestinationToWoulderLocation[apiLoc.Name]; ok {
+		loc.WoulderLocationID = &woulderLocID
+	}
+
 	// Handle location type
 	if apiLoc.LocationType != nil {
 		loc.LocationTypeID = &apiLoc.LocationType.ID
@@ -263,10 +474,14 @@ func (s *KayaSyncService) syncClimbsForLocation(ctx context.Context, locationID
 				break // No more climbs
 			}
 
-			// Save each climb
+			// Save each climb not already synced in this run
 			for _, climb := range climbs {
+				if !s.run.claim(s.run.climbs, climb.Slug) {
+					continue
+				}
 				if err := s.saveClimb(ctx, climb); err != nil {
 					log.Printf("[Kaya] Warning: failed to save climb %s: %v", climb.Slug, err)
+					s.run.release(s.run.climbs, climb.Slug)
 					continue
 				}
 				totalSynced++
@@ -344,14 +559,15 @@ func (s *KayaSyncService) saveClimb(ctx context.Context, apiClimb *kayaClient.We
 	return s.kayaRepo.Climbs().SaveClimb(ctx, climb)
 }
 
-// syncAscentsForLocation syncs recent ascents for a location with pagination
+// syncAscentsForLocation syncs recent ascents for a location with pagination.
+// Ascents already synced in this run are skipped and not counted.
 func (s *KayaSyncService) syncAscentsForLocation(ctx context.Context, locationID string) (int, error) {
 	const pageSize = 15    // Kaya API limit (captured queries use 15)
 	const maxAscents = 500 // Limit to recent ascents to avoid overwhelming database
 	offset := 0
 	totalSynced := 0
 
-	for totalSynced < maxAscents {
+	for offset < maxAscents {
 		ascents, err := s.kayaClient.GetAscents(locationID, offset, pageSize)
 		if err != nil {
 			return totalSynced, fmt.Errorf("failed to fetch ascents (offset %d): %w", offset, err)
@@ -361,10 +577,14 @@ func (s *KayaSyncService) syncAscentsForLocation(ctx context.Context, locationID
 			break // No more ascents
 		}
 
-		// Save each ascent
+		// Save each ascent not already synced in this run
 		for _, ascent := range ascents {
+			if !s.run.claim(s.run.ascents, ascent.ID) {
+				continue
+			}
 			if err := s.saveAscent(ctx, ascent); err != nil {
 				log.Printf("[Kaya] Warning: failed to save ascent %s: %v", ascent.ID, err)
+				s.run.release(s.run.ascents, ascent.ID)
 				continue
 			}
 			totalSynced++
@@ -372,8 +592,9 @@ func (s *KayaSyncService) syncAscentsForLocation(ctx context.Context, locationID
 
 		log.Printf("[Kaya] Synced %d ascents for location %s (total: %d)", len(ascents), locationID, totalSynced)
 
-		// Check if we've reached the end or limit
-		if len(ascents) < pageSize || totalSynced >= maxAscents {
+		// Check if we've reached the end. The limit counts fetched ascents,
+		// not new ones, so pages of duplicates do not walk the whole history.
+		if len(ascents) < pageSize {
 			break
 		}
 
@@ -481,39 +702,54 @@ func (s *KayaSyncService) saveUser(ctx context.Context, apiUser *kayaClient.WebU
 	return s.kayaRepo.Users().SaveUser(ctx, user)
 }
 
-// syncSubLocations syncs all sub-locations for a location
-func (s *KayaSyncService) syncSubLocations(ctx context.Context, locationID string) (int, error) {
+// syncSubLocations syncs all sub-locations for a location, returning the
+// number of sub-locations saved and the climbs and ascents synced under them.
+func (s *KayaSyncService) syncSubLocations(ctx context.Context, locationID string) (int, int, int, error) {
 	const pageSize = 20 // Kaya API limit
 	offset := 0
-	totalSynced := 0
+	totalSynced, climbsSynced, ascentsSynced := 0, 0, 0
 
 	for {
 		subLocs, err := s.kayaClient.GetSubLocations(locationID, nil, offset, pageSize)
 		if err != nil {
-			return totalSynced, fmt.Errorf("failed to fetch sub-locations (offset %d): %w", offset, err)
+			return totalSynced, climbsSynced, ascentsSynced, fmt.Errorf("failed to fetch sub-locations (offset %d): %w", offset, err)
 		}
 
 		if len(subLocs) == 0 {
 			break // No more sub-locations
 		}
 
-		// Save each sub-location
+		// Save each sub-location not already synced in this run
 		for _, subLoc := range subLocs {
+			if !s.run.claim(s.run.subLocations, subLoc.ID) {
+				continue
+			}
 			if err := s.saveLocation(ctx, subLoc); err != nil {
 				log.Printf("[Kaya] Warning: failed to save sub-location %s: %v", subLoc.Slug, err)
+				s.run.release(s.run.subLocations, subLoc.ID)
 				continue
 			}
 			totalSynced++
 
 			// Recursively sync this sub-location (climbs and ascents only, not more sub-locations)
 			log.Printf("[Kaya] Syncing sub-location: %s", subLoc.Name)
-			if climbs, err := s.syncClimbsForLocation(ctx, subLoc.ID); err != nil {
+			climbs, err := s.syncClimbsForLocation(ctx, subLoc.ID)
+			climbsSynced += climbs
+			if err != nil {
+				if isKayaTokenExpired(err) {
+					return totalSynced, climbsSynced, ascentsSynced, err
+				}
 				log.Printf("[Kaya] Warning: failed to sync climbs for sub-location %s: %v", subLoc.ID, err)
 			} else {
 				log.Printf("[Kaya] Synced %d climbs for sub-location %s", climbs, subLoc.Name)
 			}
 
-			if ascents, err := s.syncAscentsForLocation(ctx, subLoc.ID); err != nil {
+			ascents, err := s.syncAscentsForLocation(ctx, subLoc.ID)
+			ascentsSynced += ascents
+			if err != nil {
+				if isKayaTokenExpired(err) {
+					return totalSynced, climbsSynced, ascentsSynced, err
+				}
 				log.Printf("[Kaya] Warning: failed to sync ascents for sub-location %s: %v", subLoc.ID, err)
 			} else {
 				log.Printf("[Kaya] Synced %d ascents for sub-location %s", ascents, subLoc.Name)
@@ -530,7 +766,14 @@ func (s *KayaSyncService) syncSubLocations(ctx context.Context, locationID strin
 		offset += pageSize
 	}
 
-	return totalSynced, nil
+	return totalSynced, climbsSynced, ascentsSynced, nil
+}
+
+// isKayaTokenExpired reports whether err is caused by an expired Kaya auth
+// token. Such errors abort the sync instead of being logged and skipped,
+// since every later request would fail the same way.
+func isKayaTokenExpired(err error) bool {
+	return errors.Is(err, kayaClient.ErrTokenExpired)
 }
 
 // GetLocationBySlug retrieves a location from the database
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	kayaDB "github.com/alexscott64/woulder/backend/internal/database/kaya"
+	kayaClient "github.com/alexscott64/woulder/backend/internal/kaya"
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKayaRepo implements only the sub-repositories SyncDueLocation touches;
+// calling anything else panics on the nil embedded interface.
+type fakeKayaRepo struct {
+	kayaDB.Repository
+	locations *fakeKayaLocations
+	climbs    *fakeKayaClimbs
+	ascents   *fakeKayaAscents
+	sync      *fakeKayaSync
+}
+
+func (r *fakeKayaRepo) Locations() kayaDB.LocationsRepository { return r.locations }
+func (r *fakeKayaRepo) Climbs() kayaDB.ClimbsRepository       { return r.climbs }
+func (r *fakeKayaRepo) Ascents() kayaDB.AscentsRepository     { return r.ascents }
+func (r *fakeKayaRepo) Sync() kayaDB.SyncRepository           { return r.sync }
+
+type fakeKayaClimbs struct {
+	kayaDB.ClimbsRepository
+	saved []string
+}
+
+func (c *fakeKayaClimbs) SaveClimb(ctx context.Context, climb *models.KayaClimb) error {
+	c.saved = append(c.saved, climb.Slug)
+	return nil
+}
+
+type fakeKayaAscents struct {
+	kayaDB.AscentsRepository
+	saved []string
+}
+
+func (a *fakeKayaAscents) SaveAscent(ctx context.Context, ascent *models.KayaAscent) error {
+	a.saved = append(a.saved, ascent.KayaAscentID)
+	return nil
+}
+
+type fakeKayaLocations struct {
+	kayaDB.LocationsRepository
+	byID  map[string]*models.KayaLocation
+	saved []string
+}
+
+func (l *fakeKayaLocations) GetLocationByID(ctx context.Context, id string) (*models.KayaLocation, error) {
+	return l.byID[id], nil
+}
+
+func (l *fakeKayaLocations) GetLocationBySlug(ctx context.Context, slug string) (*models.KayaLocation, error) {
+	for _, loc := range l.byID {
+		if loc.Slug == slug {
+			return loc, nil
+		}
+	}
+	return nil, nil
+}
+
+func (l *fakeKayaLocations) SaveLocation(ctx context.Context, loc *models.KayaLocation) error {
+	l.saved = append(l.saved, loc.KayaLocationID)
+	return nil
+}
+
+type fakeKayaSync struct {
+	kayaDB.SyncRepository
+	progress   map[string]*models.KayaSyncProgress
+	saved      []string // statuses passed to SaveSyncProgress
+	statuses   []string
+	syncError  *string
+	counters   [3]int
+	nextSyncAt time.Time
+	synced     bool // last ScheduleNextSync reported success
+}
+
+func (s *fakeKayaSync) UpdateSyncStatus(ctx context.Context, id, status string, syncError *string) error {
+	s.statuses = append(s.statuses, status)
+	s.syncError = syncError
+	return nil
+}
+
+func (s *fakeKayaSync) GetSyncProgress(ctx context.Context, id string) (*models.KayaSyncProgress, error) {
+	return s.progress[id], nil
+}
+
+func (s *fakeKayaSync) SaveSyncProgress(ctx context.Context, progress *models.KayaSyncProgress) error {
+	s.saved = append(s.saved, progress.Status)
+	if progress.NextSyncAt != nil {
+		s.nextSyncAt = *progress.NextSyncAt
+	}
+	return nil
+}
+
+func (s *fakeKayaSync) IncrementSyncCounters(ctx context.Context, id string, climbs, ascents, subLocations int) error {
+	s.counters = [3]int{climbs, ascents, subLocations}
+	return nil
+}
+
+func (s *fakeKayaSync) ScheduleNextSync(ctx context.Context, id string, nextSyncAt time.Time, synced bool) error {
+	s.nextSyncAt = nextSyncAt
+	s.synced = synced
+	return nil
+}
+
+// fakeKayaClient serves one page of sub-locations, boulders and ascents per
+// location ID.
+type fakeKayaClient struct {
+	locationSlugs []string
+	ascentsErr    error
+	subLocations  map[string][]*kayaClient.WebLocation
+	climbs        map[string][]*kayaClient.WebClimb
+	ascents       map[string][]*kayaClient.WebAscent
+}
+
+func (c *fakeKayaClient) GetLocation(slug string) (*kayaClient.WebLocation, error) {
+	c.locationSlugs = append(c.locationSlugs, slug)
+	// Slugs end in the location ID, e.g. Gold-Bar-344983.
+	id := slug[strings.LastIndex(slug, "-")+1:]
+	return &kayaClient.WebLocation{ID: id, Slug: slug, Name: "Gold Bar"}, nil
+}
+
+func (c *fakeKayaClient) GetSubLocations(locationID string, climbTypeID *string, offset, count int) ([]*kayaClient.WebLocation, error) {
+	if offset > 0 {
+		return nil, nil
+	}
+	return c.subLocations[locationID], nil
+}
+
+func (c *fakeKayaClient) GetClimbs(locationID string, climbTypeID *string, offset, count int) ([]*kayaClient.WebClimb, error) {
+	if offset > 0 || climbTypeID == nil || *climbTypeID != "1" {
+		return nil, nil
+	}
+	return c.climbs[locationID], nil
+}
+
+func (c *fakeKayaClient) GetAscents(locationID string, offset, count int) ([]*kayaClient.WebAscent, error) {
+	if c.ascentsErr != nil || offset > 0 {
+		return nil, c.ascentsErr
+	}
+	return c.ascents[locationID], nil
+}
+
+func (c *fakeKayaClient) GetPosts(locationID string, subLocationIDs []string, offset, count int) ([]*kayaClient.WebPost, error) {
+	return nil, nil
+}
+
+func newQueuedSyncFixture(client *fakeKayaClient) (*KayaSyncService, *fakeKayaRepo) {
+	repo := &fakeKayaRepo{
+		locations: &fakeKayaLocations{byID: map[string]*models.KayaLocation{
+			"344983": {KayaLocationID: "344983", Slug: "Gold-Bar-344983", Name: "Gold Bar"},
+		}},
+		climbs:  &fakeKayaClimbs{},
+		ascents: &fakeKayaAscents{},
+		sync:    &fakeKayaSync{},
+	}
+	return NewKayaSyncService(repo, client, nil), repo
+}
+
+func TestSyncDueLocation_Success(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := newQueuedSyncFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: "completed"}
+
+	before := time.Now()
+	err := svc.SyncDueLocation(context.Background(), progress, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gold-Bar-344983"}, client.locationSlugs)
+	assert.Equal(t, []string{"344983"}, repo.locations.saved)
+	assert.Equal(t, []string{"in_progress", "completed"}, repo.sync.statuses)
+	assert.Nil(t, repo.sync.syncError)
+	assert.Equal(t, [3]int{0, 0, 0}, repo.sync.counters)
+	assert.False(t, repo.sync.nextSyncAt.Before(before.Add(kayaResyncInterval)))
+	assert.True(t, repo.sync.synced)
+}
+
+func TestSyncDueLocation_FailureStillSchedulesNextSync(t *testing.T) {
+	client := &fakeKayaClient{ascentsErr: &kayaClient.TokenExpiredError{StatusCode: 401}}
+	svc, repo := newQueuedSyncFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: "failed"}
+
+	err := svc.SyncDueLocation(context.Background(), progress, true)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, kayaClient.ErrTokenExpired))
+	assert.Equal(t, []string{"in_progress", "failed"}, repo.sync.statuses)
+	require.NotNil(t, repo.sync.syncError)
+	assert.Contains(t, *repo.sync.syncError, "token expired")
+	assert.False(t, repo.sync.nextSyncAt.IsZero())
+	assert.False(t, repo.sync.synced, "a failed sync should not update last_sync_at")
+}
+
+func TestSyncDueLocation_UnknownLocation(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := newQueuedSyncFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "999", LocationName: "Gone", Status: "pending"}
+
+	err := svc.SyncDueLocation(context.Background(), progress, true)
+
+	require.Error(t, err)
+	assert.Empty(t, client.locationSlugs, "API should not be called without a slug")
+	assert.Equal(t, []string{"in_progress", "failed"}, repo.sync.statuses)
+	assert.False(t, repo.sync.nextSyncAt.IsZero())
+}
+
+func TestSyncDueLocation_CountsSharedContentOnce(t *testing.T) {
+	// The parent's listings include everything under the child area, so
+	// the same climb and ascent come back for both locations.
+	climb := &kayaClient.WebClimb{Slug: "The-Trophy-123", Name: "The Trophy"}
+	ascent := &kayaClient.WebAscent{ID: "a1", Date: "2026-07-01", Climb: climb}
+	client := &fakeKayaClient{
+		subLocations: map[string][]*kayaClient.WebLocation{
+			"344983": {{ID: "400", Slug: "Hidden-Forest-400", Name: "Hidden Forest"}},
+		},
+		climbs: map[string][]*kayaClient.WebClimb{
+			"344983": {climb},
+			"400":    {climb},
+		},
+		ascents: map[string][]*kayaClient.WebAscent{
+			"344983": {ascent},
+			"400":    {ascent},
+		},
+	}
+	svc, repo := newQueuedSyncFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: "pending"}
+
+	require.NoError(t, svc.SyncDueLocation(context.Background(), progress, true))
+	assert.Equal(t, [3]int{1, 1, 1}, repo.sync.counters, "climbs, ascents, sub-locations")
+	assert.Equal(t, []string{"a1"}, repo.ascents.saved, "duplicate ascent should not be re-saved")
+
+	// A later target in the same run (here the child itself) counts nothing new.
+	repo.locations.byID["400"] = &models.KayaLocation{KayaLocationID: "400", Slug: "Hidden-Forest-400", Name: "Hidden Forest"}
+	child := &models.KayaSyncProgress{KayaLocationID: "400", LocationName: "Hidden Forest", Status: "pending"}
+	require.NoError(t, svc.SyncDueLocation(context.Background(), child, false))
+	assert.Equal(t, [3]int{0, 0, 0}, repo.sync.counters)
+}
+
+func TestSyncDueLocation_SharedRunCountsAcrossServices(t *testing.T) {
+	climb := &kayaClient.WebClimb{Slug: "The-Trophy-123", Name: "The Trophy"}
+	ascent := &kayaClient.WebAscent{ID: "a1", Date: "2026-07-01", Climb: climb}
+	client := &fakeKayaClient{
+		climbs:  map[string][]*kayaClient.WebClimb{"344983": {climb}, "400": {climb}},
+		ascents: map[string][]*kayaClient.WebAscent{"344983": {ascent}, "400": {ascent}},
+	}
+	svc, repo := newQueuedSyncFixture(client)
+	repo.locations.byID["400"] = &models.KayaLocation{KayaLocationID: "400", Slug: "Hidden-Forest-400", Name: "Hidden Forest"}
+
+	// Two workers of one job, each with its own service.
+	run := NewKayaSyncRun()
+	svc.SetSyncRun(run)
+	other := NewKayaSyncService(repo, client, nil)
+	other.SetSyncRun(run)
+
+	parent := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: "pending"}
+	require.NoError(t, svc.SyncDueLocation(context.Background(), parent, true))
+	assert.Equal(t, [3]int{1, 1, 0}, repo.sync.counters)
+
+	child := &models.KayaSyncProgress{KayaLocationID: "400", LocationName: "Hidden Forest", Status: "pending"}
+	require.NoError(t, other.SyncDueLocation(context.Background(), child, true))
+	assert.Equal(t, [3]int{0, 0, 0}, repo.sync.counters)
+	assert.Equal(t, []string{"a1"}, repo.ascents.saved)
+}
+
+func closedLocationFixture(client *fakeKayaClient) (*KayaSyncService, *fakeKayaRepo) {
+	svc, repo := newQueuedSyncFixture(client)
+	closedDate := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	repo.locations.byID["344983"].IsClosed = true
+	repo.locations.byID["344983"].ClosedDate = &closedDate
+	svc.SetSkipClosed(true)
+	return svc, repo
+}
+
+func TestSyncLocationBySlug_SkipsClosedLocation(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := closedLocationFixture(client)
+
+	err := svc.SyncLocationBySlug(context.Background(), "Gold-Bar-344983", true)
+
+	require.ErrorIs(t, err, ErrLocationClosed)
+	assert.Contains(t, err.Error(), "closed since 2025-03-01")
+	assert.Empty(t, client.locationSlugs, "closed location should not hit the API")
+	assert.Equal(t, []string{kayaStatusSkipped}, repo.sync.saved, "untracked location gets a new skipped row")
+	assert.Empty(t, repo.sync.statuses)
+	assert.WithinDuration(t, time.Now().Add(kayaClosedRecheckInterval), repo.sync.nextSyncAt, time.Minute)
+}
+
+func TestSyncDueLocation_SkipsClosedLocation(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := closedLocationFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: "completed", ClimbsSynced: 12}
+	repo.sync.progress = map[string]*models.KayaSyncProgress{"344983": progress}
+
+	err := svc.SyncDueLocation(context.Background(), progress, true)
+
+	require.ErrorIs(t, err, ErrLocationClosed)
+	assert.Empty(t, client.locationSlugs, "closed location should not hit the API")
+	assert.Equal(t, []string{kayaStatusSkipped}, repo.sync.statuses, "existing row keeps its counters")
+	assert.Empty(t, repo.sync.saved)
+	assert.WithinDuration(t, time.Now().Add(kayaClosedRecheckInterval), repo.sync.nextSyncAt, time.Minute,
+		"skipped location should be rechecked later")
+	assert.False(t, repo.sync.synced, "a skip is not a sync")
+}
+
+func TestSyncDueLocation_RechecksSkippedLocation(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := closedLocationFixture(client)
+	progress := &models.KayaSyncProgress{KayaLocationID: "344983", LocationName: "Gold Bar", Status: kayaStatusSkipped}
+
+	err := svc.SyncDueLocation(context.Background(), progress, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gold-Bar-344983"}, client.locationSlugs, "a due recheck should hit the API")
+	assert.Equal(t, []string{"in_progress", "completed"}, repo.sync.statuses)
+}
+
+func TestSyncLocationBySlug_SyncsClosedLocationWhenNotSkipping(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := closedLocationFixture(client)
+	svc.SetSkipClosed(false)
+
+	require.NoError(t, svc.SyncLocationBySlug(context.Background(), "Gold-Bar-344983", false))
+	assert.Equal(t, []string{"Gold-Bar-344983"}, client.locationSlugs)
+	assert.Equal(t, []string{"in_progress", "completed"}, repo.sync.saved)
+}
+
+func TestSyncLocationBySlug_SyncsUnknownLocationToLearnClosed(t *testing.T) {
+	client := &fakeKayaClient{}
+	svc, repo := closedLocationFixture(client)
+
+	require.NoError(t, svc.SyncLocationBySlug(context.Background(), "Index-500", false))
+	assert.Equal(t, []string{"Index-500"}, client.locationSlugs)
+	assert.Equal(t, []string{"in_progress", "completed"}, repo.sync.saved)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// htmlTagPattern matches HTML tags in Kaya descriptions, which are authored
+// in a rich text editor
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// blockBreakPattern matches tags that separate paragraphs so they survive
+// tag stripping as line breaks
+var blockBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+
+// BuildLocationAccessInfo maps a Kaya destination's descriptions onto
+// location access info. Bouldering descriptions are preferred, falling back
+// to the routes descriptions when empty. Returns nil if loc is nil or Kaya
+// has no usable access data for it.
+func BuildLocationAccessInfo(loc *models.KayaLocation) *models.LocationAccessInfo {
+	if loc == nil {
+		return nil
+	}
+
+	info := &models.LocationAccessInfo{
+		ShortDescription:  firstSanitized(loc.DescriptionShortBouldering, loc.DescriptionShortRoutes),
+		Approach:          firstSanitized(loc.AccessDescriptionBouldering, loc.AccessDescriptionRoutes),
+		AccessNotes:       firstSanitized(loc.AccessIssuesDescriptionBouldering, loc.AccessIssuesDescriptionRoutes),
+		IsAccessSensitive: loc.IsAccessSensitive,
+		IsClosed:          loc.IsClosed,
+		Source:            "kaya",
+		KayaLocationSlug:  loc.Slug,
+	}
+
+	if info.ShortDescription == nil && info.Approach == nil && info.AccessNotes == nil &&
+		!info.IsAccessSensitive && !info.IsClosed {
+		return nil
+	}
+	return info
+}
+
+// firstSanitized returns the first candidate that is non-empty after
+// sanitizing, or nil if none are
+func firstSanitized(candidates ...*string) *string {
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		if s := sanitizeDescription(*c); s != "" {
+			return &s
+		}
+	}
+	return nil
+}
+
+// sanitizeDescription converts a Kaya description to trimmed plain text:
+// block-level breaks become newlines, remaining tags are stripped, entities
+// are unescaped, and runs of whitespace are collapsed
+func sanitizeDescription(s string) string {
+	s = blockBreakPattern.ReplaceAllString(s, "\n")
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildLocationAccessInfo(t *testing.T) {
+	t.Run("nil location", func(t *testing.T) {
+		assert.Nil(t, BuildLocationAccessInfo(nil))
+	})
+
+	t.Run("no access data", func(t *testing.T) {
+		assert.Nil(t, BuildLocationAccessInfo(&models.KayaLocation{
+			Slug:                        "empty",
+			DescriptionShortBouldering:  strPtr("   "),
+			AccessDescriptionBouldering: strPtr("<p></p>"),
+		}))
+	})
+
+	t.Run("prefers bouldering descriptions", func(t *testing.T) {
+		info := BuildLocationAccessInfo(&models.KayaLocation{
+			Slug:                        "gold-bar",
+			DescriptionShortBouldering:  strPtr("Granite boulders"),
+			DescriptionShortRoutes:      strPtr("Sport crags"),
+			AccessDescriptionBouldering: strPtr("Park at the trailhead"),
+			AccessDescriptionRoutes:     strPtr("Park on the road"),
+		})
+		require.NotNil(t, info)
+		assert.Equal(t, "Granite boulders", *info.ShortDescription)
+		assert.Equal(t, "Park at the trailhead", *info.Approach)
+		assert.Nil(t, info.AccessNotes)
+		assert.Equal(t, "kaya", info.Source)
+		assert.Equal(t, "gold-bar", info.KayaLocationSlug)
+	})
+
+	t.Run("falls back to routes when bouldering is empty", func(t *testing.T) {
+		info := BuildLocationAccessInfo(&models.KayaLocation{
+			DescriptionShortBouldering:        strPtr(""),
+			DescriptionShortRoutes:            strPtr("Sport crags"),
+			AccessIssuesDescriptionRoutes:     strPtr("Closed for raptor nesting Feb-Jul"),
+			AccessIssuesDescriptionBouldering: nil,
+		})
+		require.NotNil(t, info)
+		assert.Equal(t, "Sport crags", *info.ShortDescription)
+		assert.Equal(t, "Closed for raptor nesting Feb-Jul", *info.AccessNotes)
+	})
+
+	t.Run("flags alone are enough", func(t *testing.T) {
+		info := BuildLocationAccessInfo(&models.KayaLocation{IsClosed: true})
+		require.NotNil(t, info)
+		assert.True(t, info.IsClosed)
+		assert.Nil(t, info.Approach)
+	})
+}
+
+func TestSanitizeDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "  Park at the lot.  ", "Park at the lot."},
+		{"collapses whitespace", "Walk\t 10   minutes", "Walk 10 minutes"},
+		{"strips tags", "<p>Park at the <b>upper</b> lot</p>", "Park at the upper lot"},
+		{"paragraphs become lines", "<p>Park.</p><p>Walk uphill.</p>", "Park.\nWalk uphill."},
+		{"br becomes line", "Park.<br/>Walk.<BR>Climb.", "Park.\nWalk.\nClimb."},
+		{"drops blank lines", "Park.\n\n\n  \nWalk.", "Park.\nWalk."},
+		{"unescapes entities", "Rock &amp; Ice&nbsp;lot", "Rock & Ice lot"},
+		{"empty", "<p> </p>", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeDescription(tt.in))
+		})
+	}
+}
@@ -38,6 +38,9 @@ func (s *LocationService) GetAllLocations(ctx context.Context) ([]models.Locatio
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all locations: %w", err)
 	}
+	if err := s.setSyncStates(ctx, locationPtrs(locations)); err != nil {
+		return nil, err
+	}
 	return locations, nil
 }
 
@@ -47,6 +50,9 @@ func (s *LocationService) GetLocation(ctx context.Context, id int) (*models.Loca
 	if err != nil {
 		return nil, fmt.Errorf("failed to get location %d: %w", id, err)
 	}
+	if err := s.setSyncStates(ctx, []*models.Location{location}); err != nil {
+		return nil, err
+	}
 	return location, nil
 }
 
@@ -56,9 +62,74 @@ func (s *LocationService) GetLocationsByArea(ctx context.Context, areaID int) ([
 	if err != nil {
 		return nil, fmt.Errorf("failed to get locations for area %d: %w", areaID, err)
 	}
+	if err := s.setSyncStates(ctx, locationPtrs(locations)); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// GetNearbyLocations retrieves locations within radiusKm of (lat, lon), nearest first
+func (s *LocationService) GetNearbyLocations(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+	locations, err := s.locationsRepo.GetNearby(ctx, lat, lon, radiusKm, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get locations near (%.4f, %.4f): %w", lat, lon, err)
+	}
+	ptrs := make([]*models.Location, len(locations))
+	for i := range locations {
+		ptrs[i] = &locations[i].Location
+	}
+	if err := s.setSyncStates(ctx, ptrs); err != nil {
+		return nil, err
+	}
 	return locations, nil
 }
 
+// setSyncStates sets SyncState and LastSyncedAt on each location from its
+// Mountain Project routes, in a single query.
+func (s *LocationService) setSyncStates(ctx context.Context, locs []*models.Location) error {
+	if len(locs) == 0 {
+		return nil
+	}
+
+	ids := make([]int, len(locs))
+	for i, loc := range locs {
+		ids[i] = loc.ID
+	}
+	infos, err := s.locationsRepo.GetSyncInfo(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get location sync info: %w", err)
+	}
+
+	for _, loc := range locs {
+		info := infos[loc.ID]
+		loc.SyncState = locationSyncState(info)
+		loc.LastSyncedAt = info.LastSyncedAt
+	}
+	return nil
+}
+
+// locationSyncState derives a location's sync state: never synced until it
+// has routes, then syncing until the first route's ticks have been synced.
+func locationSyncState(info models.LocationSyncInfo) string {
+	switch {
+	case info.RouteCount == 0:
+		return models.LocationSyncStateNeverSynced
+	case info.LastSyncedAt == nil:
+		return models.LocationSyncStateSyncing
+	default:
+		return models.LocationSyncStateSynced
+	}
+}
+
+// locationPtrs returns pointers to the elements of locs, for in-place updates.
+func locationPtrs(locs []models.Location) []*models.Location {
+	ptrs := make([]*models.Location, len(locs))
+	for i := range locs {
+		ptrs[i] = &locs[i]
+	}
+	return ptrs
+}
+
 // GetAllAreas retrieves all areas
 func (s *LocationService) GetAllAreas(ctx context.Context) ([]models.Area, error) {
 	areas, err := s.areasRepo.GetAll(ctx)
@@ -97,6 +168,36 @@ func (s *LocationService) GetAreaByID(ctx context.Context, id int) (*models.Area
 //
 // Returns the newly inserted location's ID.
 func (s *LocationService) CreateLocation(ctx context.Context, loc models.Location) (int, error) {
+	if err := resolveTimezone(&loc); err != nil {
+		return 0, err
+	}
+
+	id, err := s.locationsRepo.Create(ctx, loc)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create location: %w", err)
+	}
+	return id, nil
+}
+
+// UpsertLocation creates loc, or moves the existing location with the same
+// name to loc's coordinates and area. The timezone is derived and validated
+// as in CreateLocation, so a moved location gets the timezone of its new
+// coordinates. Returns the location's ID and whether it was newly created.
+func (s *LocationService) UpsertLocation(ctx context.Context, loc models.Location) (int, bool, error) {
+	if err := resolveTimezone(&loc); err != nil {
+		return 0, false, err
+	}
+
+	id, created, err := s.locationsRepo.Upsert(ctx, loc)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to upsert location %q: %w", loc.Name, err)
+	}
+	return id, created, nil
+}
+
+// resolveTimezone fills in loc.Timezone from its coordinates when empty and
+// validates it, returning ErrInvalidTimezone for unknown names.
+func resolveTimezone(loc *models.Location) error {
 	if loc.Timezone == "" {
 		loc.Timezone = geo.LookupTimezone(loc.Latitude, loc.Longitude)
 		if loc.Timezone == "" {
@@ -108,12 +209,7 @@ func (s *LocationService) CreateLocation(ctx context.Context, loc models.Locatio
 	}
 
 	if _, err := time.LoadLocation(loc.Timezone); err != nil {
-		return 0, fmt.Errorf("%w: %q", ErrInvalidTimezone, loc.Timezone)
+		return fmt.Errorf("%w: %q", ErrInvalidTimezone, loc.Timezone)
 	}
-
-	id, err := s.locationsRepo.Create(ctx, loc)
-	if err != nil {
-		return 0, fmt.Errorf("failed to create location: %w", err)
-	}
-	return id, nil
+	return nil
 }
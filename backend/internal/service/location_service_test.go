@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -167,6 +168,94 @@ func TestLocationService_GetLocationsByArea(t *testing.T) {
 	}
 }
 
+func TestLocationService_GetNearbyLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		mockFn  func(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error)
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "success with results",
+			mockFn: func(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+				return []models.NearbyLocation{
+					{Location: models.Location{ID: 1, Name: "Location 1"}, DistanceKm: 3.2},
+					{Location: models.Location{ID: 2, Name: "Location 2"}, DistanceKm: 18.9},
+				}, nil
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "database error",
+			mockFn: func(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+				return nil, errors.New("database error")
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLocationsRepo := &MockLocationsRepository{
+				GetNearbyFn: tt.mockFn,
+			}
+			mockAreasRepo := &MockAreasRepository{}
+
+			service := NewLocationService(mockLocationsRepo, mockAreasRepo)
+			locations, err := service.GetNearbyLocations(context.Background(), 47.6, -122.3, 50, 10)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, locations, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocationService_SyncState(t *testing.T) {
+	synced := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	mockLocationsRepo := &MockLocationsRepository{
+		GetAllFn: func(ctx context.Context) ([]models.Location, error) {
+			return []models.Location{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+		},
+		GetNearbyFn: func(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+			return []models.NearbyLocation{{Location: models.Location{ID: 1}, DistanceKm: 2}}, nil
+		},
+		GetSyncInfoFn: func(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error) {
+			// Location 3 has no routes, so the repository omits it
+			return map[int]models.LocationSyncInfo{
+				1: {LocationID: 1, RouteCount: 40, LastSyncedAt: &synced},
+				2: {LocationID: 2, RouteCount: 5},
+			}, nil
+		},
+	}
+
+	service := NewLocationService(mockLocationsRepo, &MockAreasRepository{})
+
+	locations, err := service.GetAllLocations(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, locations, 3)
+	assert.Equal(t, models.LocationSyncStateSynced, locations[0].SyncState)
+	assert.Equal(t, &synced, locations[0].LastSyncedAt)
+	assert.Equal(t, models.LocationSyncStateSyncing, locations[1].SyncState)
+	assert.Nil(t, locations[1].LastSyncedAt)
+	assert.Equal(t, models.LocationSyncStateNeverSynced, locations[2].SyncState)
+
+	nearby, err := service.GetNearbyLocations(context.Background(), 47.7, -121.6, 50, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.LocationSyncStateSynced, nearby[0].SyncState)
+
+	mockLocationsRepo.GetSyncInfoFn = func(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error) {
+		return nil, errors.New("database error")
+	}
+	_, err = service.GetAllLocations(context.Background())
+	assert.Error(t, err)
+}
+
 func TestLocationService_GetAllAreas(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -389,3 +478,63 @@ func TestLocationService_CreateLocation(t *testing.T) {
 		assert.False(t, createCalled, "repo.Create should not be called when tz invalid")
 	})
 }
+
+func TestLocationService_UpsertLocation(t *testing.T) {
+	t.Run("derives timezone and reports creation", func(t *testing.T) {
+		var captured models.Location
+		mockLocationsRepo := &MockLocationsRepository{
+			UpsertFn: func(ctx context.Context, loc models.Location) (int, bool, error) {
+				captured = loc
+				return 12, true, nil
+			},
+		}
+		svc := NewLocationService(mockLocationsRepo, &MockAreasRepository{})
+
+		id, created, err := svc.UpsertLocation(context.Background(), models.Location{
+			Name:      "Squamish",
+			Latitude:  49.7016,
+			Longitude: -123.1558,
+			AreaID:    1,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, id)
+		assert.True(t, created)
+		assert.Equal(t, "America/Vancouver", captured.Timezone)
+	})
+
+	t.Run("rejects invalid timezone without writing", func(t *testing.T) {
+		mockLocationsRepo := &MockLocationsRepository{
+			UpsertFn: func(ctx context.Context, loc models.Location) (int, bool, error) {
+				t.Error("repo.Upsert should not be called when tz invalid")
+				return 0, false, nil
+			},
+		}
+		svc := NewLocationService(mockLocationsRepo, &MockAreasRepository{})
+
+		_, _, err := svc.UpsertLocation(context.Background(), models.Location{
+			Name:     "Invalid",
+			Timezone: "Mars/Olympus",
+		})
+
+		assert.True(t, errors.Is(err, ErrInvalidTimezone), "got %v", err)
+	})
+
+	t.Run("wraps repository errors", func(t *testing.T) {
+		mockLocationsRepo := &MockLocationsRepository{
+			UpsertFn: func(ctx context.Context, loc models.Location) (int, bool, error) {
+				return 0, false, errors.New("area_id violates foreign key")
+			},
+		}
+		svc := NewLocationService(mockLocationsRepo, &MockAreasRepository{})
+
+		_, _, err := svc.UpsertLocation(context.Background(), models.Location{
+			Name:      "Bad Area",
+			Latitude:  47.5,
+			Longitude: -121.5,
+			AreaID:    999,
+		})
+
+		assert.ErrorContains(t, err, "Bad Area")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/database/boulders"
 	"github.com/alexscott64/woulder/backend/internal/database/climbing"
 	"github.com/alexscott64/woulder/backend/internal/database/heatmap"
 	"github.com/alexscott64/woulder/backend/internal/database/mountainproject"
@@ -117,6 +118,10 @@ type MockLocationsRepository struct {
 	GetByIDFn   func(ctx context.Context, id int) (*models.Location, error)
 	GetByAreaFn func(ctx context.Context, areaID int) ([]models.Location, error)
 	CreateFn    func(ctx context.Context, loc models.Location) (int, error)
+	UpsertFn    func(ctx context.Context, loc models.Location) (int, bool, error)
+	GetNearbyFn func(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error)
+
+	GetSyncInfoFn func(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error)
 }
 
 func (m *MockLocationsRepository) GetAll(ctx context.Context) ([]models.Location, error) {
@@ -147,6 +152,27 @@ func (m *MockLocationsRepository) Create(ctx context.Context, loc models.Locatio
 	return 0, nil
 }
 
+func (m *MockLocationsRepository) Upsert(ctx context.Context, loc models.Location) (int, bool, error) {
+	if m.UpsertFn != nil {
+		return m.UpsertFn(ctx, loc)
+	}
+	return 0, false, nil
+}
+
+func (m *MockLocationsRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]models.NearbyLocation, error) {
+	if m.GetNearbyFn != nil {
+		return m.GetNearbyFn(ctx, lat, lon, radiusKm, limit)
+	}
+	return []models.NearbyLocation{}, nil
+}
+
+func (m *MockLocationsRepository) GetSyncInfo(ctx context.Context, locationIDs []int) (map[int]models.LocationSyncInfo, error) {
+	if m.GetSyncInfoFn != nil {
+		return m.GetSyncInfoFn(ctx, locationIDs)
+	}
+	return map[int]models.LocationSyncInfo{}, nil
+}
+
 // ============================================================================
 // ROCKS REPOSITORY MOCKS
 // ============================================================================
@@ -156,6 +182,8 @@ type MockRocksRepository struct {
 	GetRockTypesByLocationFn   func(ctx context.Context, locationID int) ([]models.RockType, error)
 	GetPrimaryRockTypeFn       func(ctx context.Context, locationID int) (*models.RockType, error)
 	GetSunExposureByLocationFn func(ctx context.Context, locationID int) (*models.LocationSunExposure, error)
+	SetLocationTreeCoverageFn  func(ctx context.Context, locationID int, percent float64) error
+	SaveSunExposureFn          func(ctx context.Context, se *models.LocationSunExposure) error
 }
 
 func (m *MockRocksRepository) GetRockTypesByLocation(ctx context.Context, locationID int) ([]models.RockType, error) {
@@ -179,6 +207,20 @@ func (m *MockRocksRepository) GetSunExposureByLocation(ctx context.Context, loca
 	return nil, nil
 }
 
+func (m *MockRocksRepository) SetLocationTreeCoverage(ctx context.Context, locationID int, percent float64) error {
+	if m.SetLocationTreeCoverageFn != nil {
+		return m.SetLocationTreeCoverageFn(ctx, locationID, percent)
+	}
+	return nil
+}
+
+func (m *MockRocksRepository) SaveSunExposure(ctx context.Context, se *models.LocationSunExposure) error {
+	if m.SaveSunExposureFn != nil {
+		return m.SaveSunExposureFn(ctx, se)
+	}
+	return nil
+}
+
 // ============================================================================
 // RIVERS REPOSITORY MOCKS
 // ============================================================================
@@ -286,6 +328,15 @@ type MockMPAreasRepository struct {
 	GetRouteCountFn      func(ctx context.Context, mpAreaID string) (int, error)
 	GetAllStateConfigsFn func(ctx context.Context) ([]mountainproject.StateConfig, error)
 	GetChildAreasFn      func(ctx context.Context, parentMPAreaID string) ([]mountainproject.ChildArea, error)
+
+	GetAreaLinkProblemsFn    func(ctx context.Context) ([]mountainproject.AreaLinkProblem, error)
+	DetachAreaParentsFn      func(ctx context.Context, mpAreaIDs []int64) (int64, error)
+	ReconcileAreaLocationsFn func(ctx context.Context) (int64, error)
+
+	GetAreaCoordinateProblemsFn func(ctx context.Context, maxDistanceKm float64) ([]mountainproject.CoordinateProblem, error)
+	ClearAreaCoordinatesFn      func(ctx context.Context, mpAreaIDs []int64) (int64, error)
+
+	GetAreasByLocationFn func(ctx context.Context, locationID int) ([]models.MPArea, error)
 }
 
 func (m *MockMPAreasRepository) SaveArea(ctx context.Context, area *models.MPArea) error {
@@ -302,6 +353,13 @@ func (m *MockMPAreasRepository) GetAreaByID(ctx context.Context, mpAreaID int64)
 	return nil, nil
 }
 
+func (m *MockMPAreasRepository) GetAreasByLocation(ctx context.Context, locationID int) ([]models.MPArea, error) {
+	if m.GetAreasByLocationFn != nil {
+		return m.GetAreasByLocationFn(ctx, locationID)
+	}
+	return []models.MPArea{}, nil
+}
+
 func (m *MockMPAreasRepository) UpdateRouteCount(ctx context.Context, mpAreaID string, total int) error {
 	if m.UpdateRouteCountFn != nil {
 		return m.UpdateRouteCountFn(ctx, mpAreaID, total)
@@ -330,17 +388,62 @@ func (m *MockMPAreasRepository) GetChildAreas(ctx context.Context, parentMPAreaI
 	return []mountainproject.ChildArea{}, nil
 }
 
+func (m *MockMPAreasRepository) GetAreaLinkProblems(ctx context.Context) ([]mountainproject.AreaLinkProblem, error) {
+	if m.GetAreaLinkProblemsFn != nil {
+		return m.GetAreaLinkProblemsFn(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockMPAreasRepository) DetachAreaParents(ctx context.Context, mpAreaIDs []int64) (int64, error) {
+	if m.DetachAreaParentsFn != nil {
+		return m.DetachAreaParentsFn(ctx, mpAreaIDs)
+	}
+	return 0, nil
+}
+
+func (m *MockMPAreasRepository) ReconcileAreaLocations(ctx context.Context) (int64, error) {
+	if m.ReconcileAreaLocationsFn != nil {
+		return m.ReconcileAreaLocationsFn(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockMPAreasRepository) GetAreaCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]mountainproject.CoordinateProblem, error) {
+	if m.GetAreaCoordinateProblemsFn != nil {
+		return m.GetAreaCoordinateProblemsFn(ctx, maxDistanceKm)
+	}
+	return nil, nil
+}
+
+func (m *MockMPAreasRepository) ClearAreaCoordinates(ctx context.Context, mpAreaIDs []int64) (int64, error) {
+	if m.ClearAreaCoordinatesFn != nil {
+		return m.ClearAreaCoordinatesFn(ctx, mpAreaIDs)
+	}
+	return 0, nil
+}
+
 // MockMPRoutesRepository implements mountainproject.RoutesRepository
 type MockMPRoutesRepository struct {
-	SaveRouteFn            func(ctx context.Context, route *models.MPRoute) error
-	GetByIDFn              func(ctx context.Context, mpRouteID int64) (*models.MPRoute, error)
-	GetByIDsFn             func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.MPRoute, error)
-	GetAllIDsForLocationFn func(ctx context.Context, locationID int) ([]int64, error)
-	UpdateGPSFn            func(ctx context.Context, routeID int64, latitude, longitude float64, aspect string) error
-	GetIDsForAreaFn        func(ctx context.Context, mpAreaID string) ([]string, error)
-	GetWithGPSByAreaFn     func(ctx context.Context, mpAreaID int64) ([]*models.MPRoute, error)
-	UpsertRouteFn          func(ctx context.Context, mpRouteID, mpAreaID int64, locationID *int, name, routeType, rating string, lat, lon *float64, aspect *string) error
-	UpdateRouteDetailsFn   func(ctx context.Context, mpRouteID int64, difficulty *string, pitches *int, heightFeet *int, mpRating, popularity *float64, descriptionText, locationText, protectionText, safetyText *string) error
+	SaveRouteFn                   func(ctx context.Context, route *models.MPRoute) error
+	GetByIDFn                     func(ctx context.Context, mpRouteID int64) (*models.MPRoute, error)
+	GetByIDsFn                    func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.MPRoute, error)
+	GetAllIDsForLocationFn        func(ctx context.Context, locationID int) ([]int64, error)
+	UpdateGPSFn                   func(ctx context.Context, routeID int64, latitude, longitude float64, aspect string) error
+	SetCommentAspectFn            func(ctx context.Context, mpRouteID int64, aspect string, evidence []string) error
+	ClearCommentAspectFn          func(ctx context.Context, mpRouteID int64) error
+	GetIDsForAreaFn               func(ctx context.Context, mpAreaID string) ([]string, error)
+	GetWithGPSByAreaFn            func(ctx context.Context, mpAreaID int64) ([]*models.MPRoute, error)
+	UpsertRouteFn                 func(ctx context.Context, mpRouteID, mpAreaID int64, locationID *int, name, routeType, rating string, lat, lon *float64, aspect *string) error
+	MarkDiscoveredFn              func(ctx context.Context, mpRouteID int64) error
+	UpdateRouteDetailsFn          func(ctx context.Context, mpRouteID int64, difficulty *string, pitches *int, heightFeet *int, mpRating, popularity *float64, descriptionText, locationText, protectionText, safetyText *string) error
+	CountLocationMismatchesFn     func(ctx context.Context) (int, error)
+	GetLocationMismatchesFn       func(ctx context.Context, limit int) ([]mountainproject.RouteLocationMismatch, error)
+	ReconcileLocationMismatchesFn func(ctx context.Context, batchSize int) (int64, error)
+	GetRouteCoordinateProblemsFn  func(ctx context.Context, maxDistanceKm float64) ([]mountainproject.CoordinateProblem, error)
+	ClearRouteCoordinatesFn       func(ctx context.Context, mpRouteIDs []int64) (int64, error)
+	GetGradeCountsByLocationFn    func(ctx context.Context, locationID int, routeType string) ([]mountainproject.GradeCount, error)
+	GetGradeCountsByAreaFn        func(ctx context.Context, mpAreaID int64, routeType string) ([]mountainproject.GradeCount, error)
 }
 
 func (m *MockMPRoutesRepository) SaveRoute(ctx context.Context, route *models.MPRoute) error {
@@ -378,6 +481,20 @@ func (m *MockMPRoutesRepository) UpdateGPS(ctx context.Context, routeID int64, l
 	return nil
 }
 
+func (m *MockMPRoutesRepository) SetCommentAspect(ctx context.Context, mpRouteID int64, aspect string, evidence []string) error {
+	if m.SetCommentAspectFn != nil {
+		return m.SetCommentAspectFn(ctx, mpRouteID, aspect, evidence)
+	}
+	return nil
+}
+
+func (m *MockMPRoutesRepository) ClearCommentAspect(ctx context.Context, mpRouteID int64) error {
+	if m.ClearCommentAspectFn != nil {
+		return m.ClearCommentAspectFn(ctx, mpRouteID)
+	}
+	return nil
+}
+
 func (m *MockMPRoutesRepository) GetIDsForArea(ctx context.Context, mpAreaID string) ([]string, error) {
 	if m.GetIDsForAreaFn != nil {
 		return m.GetIDsForAreaFn(ctx, mpAreaID)
@@ -399,6 +516,13 @@ func (m *MockMPRoutesRepository) UpsertRoute(ctx context.Context, mpRouteID, mpA
 	return nil
 }
 
+func (m *MockMPRoutesRepository) MarkDiscovered(ctx context.Context, mpRouteID int64) error {
+	if m.MarkDiscoveredFn != nil {
+		return m.MarkDiscoveredFn(ctx, mpRouteID)
+	}
+	return nil
+}
+
 func (m *MockMPRoutesRepository) UpdateRouteDetails(ctx context.Context, mpRouteID int64, difficulty *string, pitches *int, heightFeet *int, mpRating, popularity *float64, descriptionText, locationText, protectionText, safetyText *string) error {
 	if m.UpdateRouteDetailsFn != nil {
 		return m.UpdateRouteDetailsFn(ctx, mpRouteID, difficulty, pitches, heightFeet, mpRating, popularity, descriptionText, locationText, protectionText, safetyText)
@@ -406,11 +530,62 @@ func (m *MockMPRoutesRepository) UpdateRouteDetails(ctx context.Context, mpRoute
 	return nil
 }
 
+func (m *MockMPRoutesRepository) CountLocationMismatches(ctx context.Context) (int, error) {
+	if m.CountLocationMismatchesFn != nil {
+		return m.CountLocationMismatchesFn(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockMPRoutesRepository) GetLocationMismatches(ctx context.Context, limit int) ([]mountainproject.RouteLocationMismatch, error) {
+	if m.GetLocationMismatchesFn != nil {
+		return m.GetLocationMismatchesFn(ctx, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockMPRoutesRepository) ReconcileLocationMismatches(ctx context.Context, batchSize int) (int64, error) {
+	if m.ReconcileLocationMismatchesFn != nil {
+		return m.ReconcileLocationMismatchesFn(ctx, batchSize)
+	}
+	return 0, nil
+}
+
+func (m *MockMPRoutesRepository) GetRouteCoordinateProblems(ctx context.Context, maxDistanceKm float64) ([]mountainproject.CoordinateProblem, error) {
+	if m.GetRouteCoordinateProblemsFn != nil {
+		return m.GetRouteCoordinateProblemsFn(ctx, maxDistanceKm)
+	}
+	return nil, nil
+}
+
+func (m *MockMPRoutesRepository) ClearRouteCoordinates(ctx context.Context, mpRouteIDs []int64) (int64, error) {
+	if m.ClearRouteCoordinatesFn != nil {
+		return m.ClearRouteCoordinatesFn(ctx, mpRouteIDs)
+	}
+	return 0, nil
+}
+
+func (m *MockMPRoutesRepository) GetGradeCountsByLocation(ctx context.Context, locationID int, routeType string) ([]mountainproject.GradeCount, error) {
+	if m.GetGradeCountsByLocationFn != nil {
+		return m.GetGradeCountsByLocationFn(ctx, locationID, routeType)
+	}
+	return nil, nil
+}
+
+func (m *MockMPRoutesRepository) GetGradeCountsByArea(ctx context.Context, mpAreaID int64, routeType string) ([]mountainproject.GradeCount, error) {
+	if m.GetGradeCountsByAreaFn != nil {
+		return m.GetGradeCountsByAreaFn(ctx, mpAreaID, routeType)
+	}
+	return nil, nil
+}
+
 // MockMPTicksRepository implements mountainproject.TicksRepository
 type MockMPTicksRepository struct {
 	SaveTickFn                 func(ctx context.Context, tick *models.MPTick) error
 	GetLastTimestampForRouteFn func(ctx context.Context, routeID int64) (*time.Time, error)
 	UpsertTickFn               func(ctx context.Context, mpRouteID int64, userName string, climbedAt time.Time, style string, comment *string) error
+	GetTickedRouteIDsFn        func(ctx context.Context, userName string, routeIDs []int64) ([]int64, error)
+	GetTicksForRoutesFn        func(ctx context.Context, routeIDs []int64, since time.Time) ([]models.MPTick, error)
 }
 
 func (m *MockMPTicksRepository) SaveTick(ctx context.Context, tick *models.MPTick) error {
@@ -434,12 +609,31 @@ func (m *MockMPTicksRepository) UpsertTick(ctx context.Context, mpRouteID int64,
 	return nil
 }
 
+func (m *MockMPTicksRepository) GetTickedRouteIDs(ctx context.Context, userName string, routeIDs []int64) ([]int64, error) {
+	if m.GetTickedRouteIDsFn != nil {
+		return m.GetTickedRouteIDsFn(ctx, userName, routeIDs)
+	}
+	return []int64{}, nil
+}
+
+func (m *MockMPTicksRepository) GetTicksForRoutes(ctx context.Context, routeIDs []int64, since time.Time) ([]models.MPTick, error) {
+	if m.GetTicksForRoutesFn != nil {
+		return m.GetTicksForRoutesFn(ctx, routeIDs, since)
+	}
+	return []models.MPTick{}, nil
+}
+
 // MockMPCommentsRepository implements mountainproject.CommentsRepository
 type MockMPCommentsRepository struct {
-	SaveAreaCommentFn    func(ctx context.Context, mpCommentID, mpAreaID int64, userName, commentText string, commentedAt time.Time) error
-	SaveRouteCommentFn   func(ctx context.Context, mpCommentID, mpRouteID int64, userName, commentText string, commentedAt time.Time) error
-	UpsertAreaCommentFn  func(ctx context.Context, mpCommentID, mpAreaID int64, userName string, userID *string, commentText string, commentedAt time.Time) error
-	UpsertRouteCommentFn func(ctx context.Context, mpCommentID, mpRouteID int64, userName string, userID *string, commentText string, commentedAt time.Time) error
+	SaveAreaCommentFn                 func(ctx context.Context, mpCommentID, mpAreaID int64, userName, commentText string, commentedAt time.Time) error
+	SaveRouteCommentFn                func(ctx context.Context, mpCommentID, mpRouteID int64, userName, commentText string, commentedAt time.Time) error
+	UpsertAreaCommentFn               func(ctx context.Context, mpCommentID, mpAreaID int64, userName string, userID *string, commentText string, commentedAt time.Time) error
+	UpsertRouteCommentFn              func(ctx context.Context, mpCommentID, mpRouteID int64, userName string, userID *string, commentText string, commentedAt time.Time) error
+	GetAreaCommentTextsFn             func(ctx context.Context, mpAreaID int64) ([]string, error)
+	GetRouteCommentTextsFn            func(ctx context.Context, mpRouteID int64) ([]string, error)
+	SaveConditionsBetaFn              func(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error
+	GetTargetsMissingConditionsBetaFn func(ctx context.Context, targetType string) ([]int64, error)
+	GetCommentsFn                     func(ctx context.Context, areaIDs, routeIDs []int64) ([]models.MPComment, error)
 }
 
 func (m *MockMPCommentsRepository) SaveAreaComment(ctx context.Context, mpCommentID, mpAreaID int64, userName, commentText string, commentedAt time.Time) error {
@@ -470,12 +664,47 @@ func (m *MockMPCommentsRepository) UpsertRouteComment(ctx context.Context, mpCom
 	return nil
 }
 
+func (m *MockMPCommentsRepository) GetAreaCommentTexts(ctx context.Context, mpAreaID int64) ([]string, error) {
+	if m.GetAreaCommentTextsFn != nil {
+		return m.GetAreaCommentTextsFn(ctx, mpAreaID)
+	}
+	return nil, nil
+}
+
+func (m *MockMPCommentsRepository) GetRouteCommentTexts(ctx context.Context, mpRouteID int64) ([]string, error) {
+	if m.GetRouteCommentTextsFn != nil {
+		return m.GetRouteCommentTextsFn(ctx, mpRouteID)
+	}
+	return nil, nil
+}
+
+func (m *MockMPCommentsRepository) SaveConditionsBeta(ctx context.Context, targetType string, mpID int64, tags []string, commentCount int) error {
+	if m.SaveConditionsBetaFn != nil {
+		return m.SaveConditionsBetaFn(ctx, targetType, mpID, tags, commentCount)
+	}
+	return nil
+}
+
+func (m *MockMPCommentsRepository) GetTargetsMissingConditionsBeta(ctx context.Context, targetType string) ([]int64, error) {
+	if m.GetTargetsMissingConditionsBetaFn != nil {
+		return m.GetTargetsMissingConditionsBetaFn(ctx, targetType)
+	}
+	return nil, nil
+}
+
+func (m *MockMPCommentsRepository) GetComments(ctx context.Context, areaIDs, routeIDs []int64) ([]models.MPComment, error) {
+	if m.GetCommentsFn != nil {
+		return m.GetCommentsFn(ctx, areaIDs, routeIDs)
+	}
+	return []models.MPComment{}, nil
+}
+
 // MockMPSyncRepository implements mountainproject.SyncRepository
 type MockMPSyncRepository struct {
 	UpdateRoutePrioritiesFn       func(ctx context.Context) error
 	GetLocationRoutesDueForSyncFn func(ctx context.Context, syncType string) ([]int64, error)
-	GetRoutesDueForTickSyncFn     func(ctx context.Context, priority string) ([]int64, error)
-	GetRoutesDueForCommentSyncFn  func(ctx context.Context, priority string) ([]int64, error)
+	GetRoutesDueForTickSyncFn     func(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
+	GetRoutesDueForCommentSyncFn  func(ctx context.Context, priority mountainproject.Priority) ([]int64, error)
 	GetPriorityDistributionFn     func(ctx context.Context) (map[string]int, error)
 }
 
@@ -493,14 +722,14 @@ func (m *MockMPSyncRepository) GetLocationRoutesDueForSync(ctx context.Context,
 	return []int64{}, nil
 }
 
-func (m *MockMPSyncRepository) GetRoutesDueForTickSync(ctx context.Context, priority string) ([]int64, error) {
+func (m *MockMPSyncRepository) GetRoutesDueForTickSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	if m.GetRoutesDueForTickSyncFn != nil {
 		return m.GetRoutesDueForTickSyncFn(ctx, priority)
 	}
 	return []int64{}, nil
 }
 
-func (m *MockMPSyncRepository) GetRoutesDueForCommentSync(ctx context.Context, priority string) ([]int64, error) {
+func (m *MockMPSyncRepository) GetRoutesDueForCommentSync(ctx context.Context, priority mountainproject.Priority) ([]int64, error) {
 	if m.GetRoutesDueForCommentSyncFn != nil {
 		return m.GetRoutesDueForCommentSyncFn(ctx, priority)
 	}
@@ -577,8 +806,18 @@ func (m *MockClimbingHistoryRepository) GetClimbHistoryForLocations(ctx context.
 type MockClimbingActivityRepository struct {
 	GetAreasOrderedByActivityFn    func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error)
 	GetSubareasOrderedByActivityFn func(ctx context.Context, parentAreaID int64, locationID int) ([]models.AreaActivitySummary, error)
+	GetAreaTreeWithActivityFn      func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error)
 	GetRoutesOrderedByActivityFn   func(ctx context.Context, areaID int64, locationID int, limit int) ([]models.RouteActivitySummary, error)
 	GetRecentTicksForRouteFn       func(ctx context.Context, routeID int64, limit int) ([]models.ClimbHistoryEntry, error)
+	GetTrendingRoutesFn            func(ctx context.Context, since time.Time, locationID *int, limit int) ([]models.TrendingRoute, error)
+	GetFeaturedRouteCandidatesFn   func(ctx context.Context, locationID *int, since, until time.Time, limit int) ([]models.FeaturedRouteCandidate, error)
+	GetSimilarRouteCandidatesFn    func(ctx context.Context, routeID int64, radiusMeters float64, since time.Time, limit int) ([]models.SimilarRouteCandidate, error)
+	GetRecentlyDiscoveredRoutesFn  func(ctx context.Context, since time.Time, limit int) ([]models.DiscoveredRoute, error)
+	GetDailyTickCountsFn           func(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error)
+	GetRouteActivitySeriesFn       func(ctx context.Context, routeID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error)
+	GetAreaActivitySeriesFn        func(ctx context.Context, areaID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error)
+	GetRouteClosuresFn             func(ctx context.Context, routeIDs []int64) (map[int64]string, error)
+	GetAreaClosuresFn              func(ctx context.Context, areaIDs []int64) (map[int64]string, error)
 }
 
 func (m *MockClimbingActivityRepository) GetAreasOrderedByActivity(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
@@ -595,6 +834,13 @@ func (m *MockClimbingActivityRepository) GetSubareasOrderedByActivity(ctx contex
 	return []models.AreaActivitySummary{}, nil
 }
 
+func (m *MockClimbingActivityRepository) GetAreaTreeWithActivity(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+	if m.GetAreaTreeWithActivityFn != nil {
+		return m.GetAreaTreeWithActivityFn(ctx, locationID)
+	}
+	return []models.AreaActivitySummary{}, nil
+}
+
 func (m *MockClimbingActivityRepository) GetRoutesOrderedByActivity(ctx context.Context, areaID int64, locationID int, limit int) ([]models.RouteActivitySummary, error) {
 	if m.GetRoutesOrderedByActivityFn != nil {
 		return m.GetRoutesOrderedByActivityFn(ctx, areaID, locationID, limit)
@@ -609,6 +855,69 @@ func (m *MockClimbingActivityRepository) GetRecentTicksForRoute(ctx context.Cont
 	return []models.ClimbHistoryEntry{}, nil
 }
 
+func (m *MockClimbingActivityRepository) GetTrendingRoutes(ctx context.Context, since time.Time, locationID *int, limit int) ([]models.TrendingRoute, error) {
+	if m.GetTrendingRoutesFn != nil {
+		return m.GetTrendingRoutesFn(ctx, since, locationID, limit)
+	}
+	return []models.TrendingRoute{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetFeaturedRouteCandidates(ctx context.Context, locationID *int, since, until time.Time, limit int) ([]models.FeaturedRouteCandidate, error) {
+	if m.GetFeaturedRouteCandidatesFn != nil {
+		return m.GetFeaturedRouteCandidatesFn(ctx, locationID, since, until, limit)
+	}
+	return []models.FeaturedRouteCandidate{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetSimilarRouteCandidates(ctx context.Context, routeID int64, radiusMeters float64, since time.Time, limit int) ([]models.SimilarRouteCandidate, error) {
+	if m.GetSimilarRouteCandidatesFn != nil {
+		return m.GetSimilarRouteCandidatesFn(ctx, routeID, radiusMeters, since, limit)
+	}
+	return []models.SimilarRouteCandidate{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetRecentlyDiscoveredRoutes(ctx context.Context, since time.Time, limit int) ([]models.DiscoveredRoute, error) {
+	if m.GetRecentlyDiscoveredRoutesFn != nil {
+		return m.GetRecentlyDiscoveredRoutesFn(ctx, since, limit)
+	}
+	return []models.DiscoveredRoute{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetDailyTickCounts(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error) {
+	if m.GetDailyTickCountsFn != nil {
+		return m.GetDailyTickCountsFn(ctx, locationID, startDate, endDate)
+	}
+	return []models.DailyTickCount{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetRouteActivitySeries(ctx context.Context, routeID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+	if m.GetRouteActivitySeriesFn != nil {
+		return m.GetRouteActivitySeriesFn(ctx, routeID, bucket, startDate, endDate)
+	}
+	return []models.ActivitySeriesPoint{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetAreaActivitySeries(ctx context.Context, areaID int64, bucket, startDate, endDate string) ([]models.ActivitySeriesPoint, error) {
+	if m.GetAreaActivitySeriesFn != nil {
+		return m.GetAreaActivitySeriesFn(ctx, areaID, bucket, startDate, endDate)
+	}
+	return []models.ActivitySeriesPoint{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetRouteClosures(ctx context.Context, routeIDs []int64) (map[int64]string, error) {
+	if m.GetRouteClosuresFn != nil {
+		return m.GetRouteClosuresFn(ctx, routeIDs)
+	}
+	return map[int64]string{}, nil
+}
+
+func (m *MockClimbingActivityRepository) GetAreaClosures(ctx context.Context, areaIDs []int64) (map[int64]string, error) {
+	if m.GetAreaClosuresFn != nil {
+		return m.GetAreaClosuresFn(ctx, areaIDs)
+	}
+	return map[int64]string{}, nil
+}
+
 // MockClimbingSearchRepository provides search methods
 type MockClimbingSearchRepository struct {
 	SearchInLocationFn       func(ctx context.Context, locationID int, searchQuery string, limit int) ([]models.SearchResult, error)
@@ -638,6 +947,9 @@ type MockBouldersRepository struct {
 	GetProfileFn       func(ctx context.Context, mpRouteID int64) (*models.BoulderDryingProfile, error)
 	GetProfilesByIDsFn func(ctx context.Context, mpRouteIDs []int64) (map[int64]*models.BoulderDryingProfile, error)
 	SaveProfileFn      func(ctx context.Context, profile *models.BoulderDryingProfile) error
+
+	GetStaleTreeCoverProfilesFn func(ctx context.Context, olderThan time.Time, limit int) ([]boulders.TreeCoverTarget, error)
+	UpdateTreeCoverageFn        func(ctx context.Context, mpRouteID int64, treeCoveragePercent float64) error
 }
 
 func (m *MockBouldersRepository) GetProfile(ctx context.Context, mpRouteID int64) (*models.BoulderDryingProfile, error) {
@@ -661,22 +973,36 @@ func (m *MockBouldersRepository) SaveProfile(ctx context.Context, profile *model
 	return nil
 }
 
+func (m *MockBouldersRepository) GetStaleTreeCoverProfiles(ctx context.Context, olderThan time.Time, limit int) ([]boulders.TreeCoverTarget, error) {
+	if m.GetStaleTreeCoverProfilesFn != nil {
+		return m.GetStaleTreeCoverProfilesFn(ctx, olderThan, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockBouldersRepository) UpdateTreeCoverage(ctx context.Context, mpRouteID int64, treeCoveragePercent float64) error {
+	if m.UpdateTreeCoverageFn != nil {
+		return m.UpdateTreeCoverageFn(ctx, mpRouteID, treeCoveragePercent)
+	}
+	return nil
+}
+
 // ============================================================================
 // HEATMAP REPOSITORY MOCKS
 // ============================================================================
 
 // MockHeatMapRepository implements heatmap.Repository
 type MockHeatMapRepository struct {
-	GetHeatMapDataFn           func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error)
+	GetHeatMapDataFn           func(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error)
 	GetAreaActivityDetailFn    func(ctx context.Context, areaID int64, startDate, endDate time.Time, routeTypes []string) (*models.AreaActivityDetail, error)
-	GetRoutesByBoundsFn        func(ctx context.Context, bounds heatmap.GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
+	GetRoutesByBoundsFn        func(ctx context.Context, bounds heatmap.GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error)
 	GetRouteTicksInDateRangeFn func(ctx context.Context, routeID int64, startDate, endDate time.Time, limit int, routeTypes []string) ([]models.TickDetail, error)
 	SearchRoutesInAreasFn      func(ctx context.Context, areaIDs []int64, searchQuery string, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error)
 }
 
-func (m *MockHeatMapRepository) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int) ([]models.HeatMapPoint, error) {
+func (m *MockHeatMapRepository) GetHeatMapData(ctx context.Context, startDate, endDate time.Time, bounds *heatmap.GeoBounds, minActivity, limit int, routeTypes []string, lightweight bool, gradeOrders []int, excludeClosed bool) ([]models.HeatMapPoint, error) {
 	if m.GetHeatMapDataFn != nil {
-		return m.GetHeatMapDataFn(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders)
+		return m.GetHeatMapDataFn(ctx, startDate, endDate, bounds, minActivity, limit, routeTypes, lightweight, gradeOrders, excludeClosed)
 	}
 	return []models.HeatMapPoint{}, nil
 }
@@ -688,9 +1014,9 @@ func (m *MockHeatMapRepository) GetAreaActivityDetail(ctx context.Context, areaI
 	return nil, nil
 }
 
-func (m *MockHeatMapRepository) GetRoutesByBounds(ctx context.Context, bounds heatmap.GeoBounds, startDate, endDate time.Time, limit int) ([]models.RouteActivity, error) {
+func (m *MockHeatMapRepository) GetRoutesByBounds(ctx context.Context, bounds heatmap.GeoBounds, startDate, endDate time.Time, limit int, excludeClosed bool) ([]models.RouteActivity, error) {
 	if m.GetRoutesByBoundsFn != nil {
-		return m.GetRoutesByBoundsFn(ctx, bounds, startDate, endDate, limit)
+		return m.GetRoutesByBoundsFn(ctx, bounds, startDate, endDate, limit, excludeClosed)
 	}
 	return []models.RouteActivity{}, nil
 }
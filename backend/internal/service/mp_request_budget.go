@@ -0,0 +1,93 @@
+package service
+
+import (
+	mpClient "github.com/alexscott64/woulder/backend/internal/mountainproject"
+)
+
+// budgetedMPClient draws from a shared RequestBudget before every Mountain
+// Project request, so the aggregate rate of all ClimbTrackingService syncs
+// stays within the budget however many of them run at once.
+type budgetedMPClient struct {
+	MPClientInterface
+	budget *mpClient.RequestBudget
+}
+
+// withRequestBudget wraps client so its requests draw from budget. A nil
+// budget leaves client unwrapped.
+func withRequestBudget(client MPClientInterface, budget *mpClient.RequestBudget) MPClientInterface {
+	if budget == nil {
+		return client
+	}
+	return &budgetedMPClient{MPClientInterface: client, budget: budget}
+}
+
+func (c *budgetedMPClient) GetRouteTicks(routeID string) ([]mpClient.Tick, error) {
+	c.budget.Wait()
+	return c.MPClientInterface.GetRouteTicks(routeID)
+}
+
+// GetRouteTicksPaged waits before the first page and before each following
+// page the caller asks for, since every page is a separate request.
+func (c *budgetedMPClient) GetRouteTicksPaged(routeID string, visit func(page []mpClient.Tick) bool) error {
+	c.budget.Wait()
+	return c.MPClientInterface.GetRouteTicksPaged(routeID, func(page []mpClient.Tick) bool {
+		if !visit(page) {
+			return false
+		}
+		c.budget.Wait()
+		return true
+	})
+}
+
+// GetUserTicksPaged waits before every page, like GetRouteTicksPaged.
+func (c *budgetedMPClient) GetUserTicksPaged(userID string, visit func(page []mpClient.UserTick) bool) error {
+	c.budget.Wait()
+	return c.MPClientInterface.GetUserTicksPaged(userID, func(page []mpClient.UserTick) bool {
+		if !visit(page) {
+			return false
+		}
+		c.budget.Wait()
+		return true
+	})
+}
+
+func (c *budgetedMPClient) GetRoute(routeID string) (*mpClient.RouteResponse, error) {
+	c.budget.Wait()
+	return c.MPClientInterface.GetRoute(routeID)
+}
+
+func (c *budgetedMPClient) GetArea(areaID string) (*mpClient.AreaResponse, error) {
+	c.budget.Wait()
+	return c.MPClientInterface.GetArea(areaID)
+}
+
+func (c *budgetedMPClient) GetAreaComments(areaID string) ([]mpClient.Comment, error) {
+	c.budget.Wait()
+	return c.MPClientInterface.GetAreaComments(areaID)
+}
+
+func (c *budgetedMPClient) GetRouteComments(routeID string) ([]mpClient.Comment, error) {
+	c.budget.Wait()
+	return c.MPClientInterface.GetRouteComments(routeID)
+}
+
+// GetRouteCommentsBatch reserves one request per route before handing the
+// batch to the wrapped client, falling back to per-route requests when the
+// wrapped client cannot batch. The batch is one request per route on the
+// wire, so it costs the same budget either way.
+func (c *budgetedMPClient) GetRouteCommentsBatch(routeIDs []string) []mpClient.CommentsResult {
+	batch, ok := c.MPClientInterface.(mpCommentsBatchClient)
+	if !ok {
+		results := make([]mpClient.CommentsResult, len(routeIDs))
+		for i, routeID := range routeIDs {
+			comments, err := c.GetRouteComments(routeID)
+			results[i] = mpClient.CommentsResult{ID: routeID, Comments: comments, Err: err}
+		}
+		return results
+	}
+
+	for range routeIDs {
+		c.budget.Wait()
+	}
+	return batch.GetRouteCommentsBatch(routeIDs)
+}
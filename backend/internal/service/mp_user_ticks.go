@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	mpClient "github.com/alexscott64/woulder/backend/internal/mountainproject"
+)
+
+// ErrInvalidMPUser is returned (wrapped) when SyncUserTicks is given a
+// non-positive user ID, or MP's tick list doesn't say whose it is.
+var ErrInvalidMPUser = errors.New("invalid Mountain Project user")
+
+// UserTickSyncResult summarizes a SyncUserTicks run.
+type UserTickSyncResult struct {
+	UserName            string `json:"user_name"`
+	Fetched             int    `json:"fetched"`               // Ticks returned by MP
+	Imported            int    `json:"imported"`              // Ticks saved, counting ones already stored
+	SkippedUnknownRoute int    `json:"skipped_unknown_route"` // Ticks on routes that are not synced
+	SkippedInvalid      int    `json:"skipped_invalid"`       // Ticks with an unparseable or future date
+}
+
+// SyncUserTicks imports a Mountain Project user's public tick list. MP looks
+// up tick lists by numeric user ID; each tick is stored under the username MP
+// reports for that ID, so it matches the user's ticks already stored by the
+// route syncs and can't be filed under anyone else's name. Only ticks on
+// routes already stored are imported, since
+// mp_ticks references mp_routes. Re-running is safe: ticks already stored
+// are skipped by the (route, user, climbed_at) unique index.
+func (s *ClimbTrackingService) SyncUserTicks(ctx context.Context, mpUserID int64) (*UserTickSyncResult, error) {
+	if mpUserID <= 0 {
+		return nil, fmt.Errorf("%w: user ID must be positive", ErrInvalidMPUser)
+	}
+
+	var ticks []mpClient.UserTick
+	err := s.mpClient.GetUserTicksPaged(strconv.FormatInt(mpUserID, 10), func(page []mpClient.UserTick) bool {
+		ticks = append(ticks, page...)
+		return ctx.Err() == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &UserTickSyncResult{Fetched: len(ticks)}
+	if len(ticks) == 0 {
+		return result, nil
+	}
+
+	mpUsername := tickListUserName(ticks, mpUserID)
+	if mpUsername == "" {
+		return nil, fmt.Errorf("%w: the tick list for user %d doesn't name the user", ErrInvalidMPUser, mpUserID)
+	}
+	result.UserName = mpUsername
+
+	routeIDs := make([]int64, 0, len(ticks))
+	seen := make(map[int64]bool, len(ticks))
+	for _, tick := range ticks {
+		if !seen[tick.RouteID] {
+			seen[tick.RouteID] = true
+			routeIDs = append(routeIDs, tick.RouteID)
+		}
+	}
+	knownRoutes, err := s.mountainProjectRepo.Routes().GetByIDs(ctx, routeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up ticked routes: %w", err)
+	}
+
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Pacific timezone: %w", err)
+	}
+
+	for _, tick := range ticks {
+		if _, ok := knownRoutes[tick.RouteID]; !ok {
+			result.SkippedUnknownRoute++
+			continue
+		}
+
+		climbedAt, err := parseTickDate(tick.Date, pacificTZ)
+		if err != nil || !isTickDateValid(climbedAt) {
+			result.SkippedInvalid++
+			continue
+		}
+
+		var comment *string
+		if cleaned := cleanCommentText(tick.GetTextString()); cleaned != "" {
+			comment = &cleaned
+		}
+
+		// UpsertTick rather than SaveTick: importing one user's ticks says
+		// nothing about whether the route's full tick list is current, so
+		// last_tick_sync_at is left alone.
+		if err := s.mountainProjectRepo.Ticks().UpsertTick(ctx, tick.RouteID, mpUsername, climbedAt, tick.Style, comment); err != nil {
+			return result, fmt.Errorf("failed to save tick on route %d: %w", tick.RouteID, err)
+		}
+		result.Imported++
+	}
+
+	log.Printf("Imported %d of %d ticks for MP user %s (%d on unsynced routes, %d invalid)",
+		result.Imported, result.Fetched, mpUsername, result.SkippedUnknownRoute, result.SkippedInvalid)
+
+	return result, nil
+}
+
+// tickListUserName returns the username MP gives user mpUserID in their
+// tick list, or "" if no tick names them.
+func tickListUserName(ticks []mpClient.UserTick, mpUserID int64) string {
+	for _, tick := range ticks {
+		if user, ok := tick.GetUser(); ok && int64(user.ID) == mpUserID {
+			if name := strings.TrimSpace(user.Name); name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// GetTickedRouteIDs returns which of routeIDs mpUsername has ticked.
+func (s *ClimbTrackingService) GetTickedRouteIDs(ctx context.Context, mpUsername string, routeIDs []int64) ([]int64, error) {
+	return s.mountainProjectRepo.Ticks().GetTickedRouteIDs(ctx, mpUsername, routeIDs)
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/mountainproject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userTick(routeID int64, date, style string) mountainproject.UserTick {
+	return mountainproject.UserTick{
+		Tick: mountainproject.Tick{
+			Date:  date,
+			Style: style,
+			Text:  []byte(`"Great climb"`),
+			User:  []byte(`{"id":42,"name":" Alex "}`),
+		},
+		RouteID: routeID,
+	}
+}
+
+func TestSyncUserTicks(t *testing.T) {
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.routes.GetByIDsFn = func(ctx context.Context, ids []int64) (map[int64]*models.MPRoute, error) {
+		assert.ElementsMatch(t, []int64{1, 2, 3}, ids, "route IDs should be looked up once each")
+		return map[int64]*models.MPRoute{1: {MPRouteID: 1}, 3: {MPRouteID: 3}}, nil
+	}
+
+	type saved struct {
+		routeID  int64
+		userName string
+		style    string
+	}
+	var stored []saved
+	mpRepo.ticks.UpsertTickFn = func(ctx context.Context, routeID int64, userName string, climbedAt time.Time, style string, comment *string) error {
+		require.NotNil(t, comment)
+		assert.Equal(t, "Great climb", *comment)
+		stored = append(stored, saved{routeID, userName, style})
+		return nil
+	}
+	mpRepo.ticks.SaveTickFn = func(ctx context.Context, tick *models.MPTick) error {
+		t.Error("SaveTick should not be used: it marks the route's tick list as synced")
+		return nil
+	}
+
+	client := &MockMPClient{
+		GetUserTicksPagedFn: func(userID string, visit func(page []mountainproject.UserTick) bool) error {
+			assert.Equal(t, "42", userID)
+			if !visit([]mountainproject.UserTick{
+				userTick(1, "Mar 3, 2026, 1:00 pm", "Send"),
+				userTick(2, "Mar 2, 2026, 1:00 pm", "Flash"), // route not synced
+			}) {
+				return nil
+			}
+			visit([]mountainproject.UserTick{
+				userTick(3, "2026-03-01", "Lead"),
+				userTick(1, "not a date", "Send"),
+				userTick(1, "2999-01-01", "Send"), // future
+			})
+			return nil
+		},
+	}
+
+	svc := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), client, nil, nil)
+	result, err := svc.SyncUserTicks(context.Background(), 42)
+	require.NoError(t, err)
+
+	assert.Equal(t, &UserTickSyncResult{
+		UserName:            "Alex",
+		Fetched:             5,
+		Imported:            2,
+		SkippedUnknownRoute: 1,
+		SkippedInvalid:      2,
+	}, result)
+	assert.Equal(t, []saved{{1, "Alex", "Send"}, {3, "Alex", "Lead"}}, stored)
+}
+
+func TestSyncUserTicks_InvalidUser(t *testing.T) {
+	client := &MockMPClient{
+		GetUserTicksPagedFn: func(userID string, visit func(page []mountainproject.UserTick) bool) error {
+			t.Error("MP should not be called for an invalid user")
+			return nil
+		},
+	}
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), client, nil, nil)
+
+	_, err := svc.SyncUserTicks(context.Background(), 0)
+	assert.True(t, errors.Is(err, ErrInvalidMPUser), "zero user ID: got %v", err)
+}
+
+func TestSyncUserTicks_UsernameFromTickList(t *testing.T) {
+	anonymous := userTick(1, "Mar 3, 2026, 1:00 pm", "Send")
+	anonymous.User = []byte(`false`)
+	otherUser := userTick(1, "Mar 2, 2026, 1:00 pm", "Send")
+	otherUser.User = []byte(`{"id":7,"name":"Someone Else"}`)
+
+	client := &MockMPClient{
+		GetUserTicksPagedFn: func(userID string, visit func(page []mountainproject.UserTick) bool) error {
+			visit([]mountainproject.UserTick{anonymous, otherUser})
+			return nil
+		},
+	}
+	mpRepo := NewMockMountainProjectRepository()
+	mpRepo.ticks.UpsertTickFn = func(ctx context.Context, routeID int64, userName string, climbedAt time.Time, style string, comment *string) error {
+		t.Errorf("tick stored under %q without a username for user 42", userName)
+		return nil
+	}
+	svc := NewClimbTrackingService(mpRepo, NewMockClimbingRepository(), client, nil, nil)
+
+	_, err := svc.SyncUserTicks(context.Background(), 42)
+	assert.True(t, errors.Is(err, ErrInvalidMPUser), "no tick names user 42: got %v", err)
+}
+
+func TestSyncUserTicks_FetchError(t *testing.T) {
+	client := &MockMPClient{
+		GetUserTicksPagedFn: func(userID string, visit func(page []mountainproject.UserTick) bool) error {
+			return errors.New("mp down")
+		},
+	}
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), client, nil, nil)
+
+	_, err := svc.SyncUserTicks(context.Background(), 42)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidMPUser))
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// markClosedRoutes flags the routes an approved Kaya match marks closed
+// (raptor closures, access disputes) and attaches Kaya's access note.
+func (s *ClimbTrackingService) markClosedRoutes(ctx context.Context, routes []models.RouteActivitySummary) error {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(routes))
+	for i, route := range routes {
+		ids[i] = route.MPRouteID
+	}
+	closures, err := s.climbingRepo.Activity().GetRouteClosures(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get route closures: %w", err)
+	}
+
+	for i := range routes {
+		if note, ok := closures[routes[i].MPRouteID]; ok {
+			routes[i].Closed = true
+			routes[i].ClosureNote = closureNote(note)
+		}
+	}
+	return nil
+}
+
+// markClosedAreas flags the areas whose Kaya-matched routes are all closed.
+// An area with an open matched route, or no Kaya data, stays open.
+func (s *ClimbTrackingService) markClosedAreas(ctx context.Context, areas []models.AreaActivitySummary) error {
+	if len(areas) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(areas))
+	for i, area := range areas {
+		ids[i] = area.MPAreaID
+	}
+	closures, err := s.climbingRepo.Activity().GetAreaClosures(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get area closures: %w", err)
+	}
+
+	for i := range areas {
+		if note, ok := closures[areas[i].MPAreaID]; ok {
+			areas[i].Closed = true
+			areas[i].ClosureNote = closureNote(note)
+		}
+	}
+	return nil
+}
+
+// closureNote returns note for a response, or nil when Kaya gave none.
+func closureNote(note string) *string {
+	if note == "" {
+		return nil
+	}
+	return &note
+}
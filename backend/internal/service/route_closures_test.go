@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClimbTrackingService_GetRoutesOrderedByActivity_MarksClosedRoutes(t *testing.T) {
+	mockClimbingRepo := NewMockClimbingRepository()
+	mockClimbingRepo.activity.GetRoutesOrderedByActivityFn = func(ctx context.Context, areaID int64, locationID int, limit int) ([]models.RouteActivitySummary, error) {
+		return []models.RouteActivitySummary{{MPRouteID: 1}, {MPRouteID: 2}, {MPRouteID: 3}}, nil
+	}
+	var requested []int64
+	mockClimbingRepo.activity.GetRouteClosuresFn = func(ctx context.Context, routeIDs []int64) (map[int64]string, error) {
+		requested = routeIDs
+		return map[int64]string{2: "Raptor closure Feb 1 - Jul 31", 3: ""}, nil
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), mockClimbingRepo, &MockMPClient{}, nil, nil)
+	routes, err := service.GetRoutesOrderedByActivity(context.Background(), 10, 1, 50)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, requested)
+	require.Len(t, routes, 3)
+	assert.False(t, routes[0].Closed)
+	assert.Nil(t, routes[0].ClosureNote)
+	assert.True(t, routes[1].Closed)
+	require.NotNil(t, routes[1].ClosureNote)
+	assert.Equal(t, "Raptor closure Feb 1 - Jul 31", *routes[1].ClosureNote)
+	assert.True(t, routes[2].Closed)
+	assert.Nil(t, routes[2].ClosureNote, "an empty Kaya note is omitted")
+}
+
+func TestClimbTrackingService_GetAreaTree_MarksClosedAreas(t *testing.T) {
+	parent := int64(100)
+	mockClimbingRepo := NewMockClimbingRepository()
+	mockClimbingRepo.activity.GetAreaTreeWithActivityFn = func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+		return []models.AreaActivitySummary{
+			{MPAreaID: 100},
+			{MPAreaID: 101, ParentMPAreaID: &parent},
+			{MPAreaID: 102, ParentMPAreaID: &parent},
+		}, nil
+	}
+	mockClimbingRepo.activity.GetAreaClosuresFn = func(ctx context.Context, areaIDs []int64) (map[int64]string, error) {
+		return map[int64]string{102: "Closed on Kaya: Upper Wall"}, nil
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), mockClimbingRepo, &MockMPClient{}, nil, nil)
+	tree, err := service.GetAreaTree(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.False(t, tree[0].Closed)
+	assert.True(t, tree[1].Closed)
+	assert.Equal(t, "Closed on Kaya: Upper Wall", *tree[1].ClosureNote)
+}
+
+func TestClimbTrackingService_GetAreasOrderedByActivity_ClosureError(t *testing.T) {
+	mockClimbingRepo := NewMockClimbingRepository()
+	mockClimbingRepo.activity.GetAreasOrderedByActivityFn = func(ctx context.Context, locationID int) ([]models.AreaActivitySummary, error) {
+		return []models.AreaActivitySummary{{MPAreaID: 100}}, nil
+	}
+	mockClimbingRepo.activity.GetAreaClosuresFn = func(ctx context.Context, areaIDs []int64) (map[int64]string, error) {
+		return nil, errors.New("view missing")
+	}
+
+	service := NewClimbTrackingService(NewMockMountainProjectRepository(), mockClimbingRepo, &MockMPClient{}, nil, nil)
+	_, err := service.GetAreasOrderedByActivity(context.Background(), 1)
+
+	assert.ErrorContains(t, err, "failed to get area closures")
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/grades"
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+const (
+	// similarRoutesRadiusMeters is how far from a route, beyond its own
+	// location, similar routes are looked for.
+	similarRoutesRadiusMeters = 25000
+
+	// similarRoutesCandidateLimit caps how many nearby routes are ranked.
+	similarRoutesCandidateLimit = 500
+
+	// similarRoutesTickWindowDays is how far back ticks count toward a
+	// route's popularity.
+	similarRoutesTickWindowDays = 365
+)
+
+// Weights of the similarity signals; they sum to 1, so scores are in [0, 1].
+const (
+	similarGradeWeight      = 0.4
+	similarDistanceWeight   = 0.3
+	similarPopularityWeight = 0.2
+	similarTypeWeight       = 0.1
+)
+
+// SimilarRoute is a recommended route with its similarity to the reference
+// route (0-1, higher is more similar).
+type SimilarRoute struct {
+	models.SimilarRouteCandidate
+	Score float64 `json:"score"`
+}
+
+// GetSimilarRoutes recommends up to limit routes a climber of routeID might
+// also like, most similar first. Returns ErrRouteNotFound for an unknown
+// route.
+//
+// Candidates are routes of the same kind (boulder or roped) in the route's
+// location or within similarRoutesRadiusMeters of it, and only those within
+// a grade band of the route count (see similarGradeScore). They are ranked
+// by grade proximity, distance, tick volume over the last year and route
+// type; see similarRouteScore.
+func (s *ClimbTrackingService) GetSimilarRoutes(ctx context.Context, routeID int64, limit int, now time.Time) ([]SimilarRoute, error) {
+	candidates, err := s.climbingRepo.Activity().GetSimilarRouteCandidates(
+		ctx, routeID, similarRoutesRadiusMeters, now.AddDate(0, 0, -similarRoutesTickWindowDays), similarRoutesCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get similar route candidates: %w", err)
+	}
+
+	return rankSimilarRoutes(routeID, candidates, limit)
+}
+
+// rankSimilarRoutes scores candidates against the reference route, which
+// must be among them, and returns the top limit.
+func rankSimilarRoutes(routeID int64, candidates []models.SimilarRouteCandidate, limit int) ([]SimilarRoute, error) {
+	var ref *models.SimilarRouteCandidate
+	maxTicks := 0
+	for i := range candidates {
+		if candidates[i].MPRouteID == routeID {
+			ref = &candidates[i]
+		}
+		maxTicks = max(maxTicks, candidates[i].TickCount)
+	}
+	if ref == nil {
+		return nil, fmt.Errorf("route %d: %w", routeID, ErrRouteNotFound)
+	}
+
+	routes := []SimilarRoute{}
+	for _, c := range candidates {
+		if c.MPRouteID == routeID {
+			continue
+		}
+		gradeScore, ok := similarGradeScore(ref.Rating, c.Rating)
+		if !ok {
+			continue
+		}
+		routes = append(routes, SimilarRoute{
+			SimilarRouteCandidate: c,
+			Score:                 similarRouteScore(*ref, c, gradeScore, maxTicks),
+		})
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Score != routes[j].Score {
+			return routes[i].Score > routes[j].Score
+		}
+		if routes[i].TickCount != routes[j].TickCount {
+			return routes[i].TickCount > routes[j].TickCount
+		}
+		return routes[i].MPRouteID < routes[j].MPRouteID
+	})
+
+	if len(routes) > limit {
+		routes = routes[:limit]
+	}
+	return routes, nil
+}
+
+// similarRouteScore combines the similarity signals of candidate c to the
+// reference route ref. maxTicks is the most ticks on any candidate.
+//
+//   - grade: from similarGradeScore.
+//   - distance: 1 in the same MP area, halving every 2 km beyond it; routes
+//     in the same location without GPS get 0.25.
+//   - popularity: half how close the two tick counts are (on a log scale),
+//     half c's own tick volume relative to the busiest candidate.
+//   - type: 1 for the same route type, 0.5 when they share a style (e.g.
+//     "Sport" and "Sport, TR").
+func similarRouteScore(ref, c models.SimilarRouteCandidate, gradeScore float64, maxTicks int) float64 {
+	distanceScore := 0.25
+	switch {
+	case c.MPAreaID == ref.MPAreaID:
+		distanceScore = 1
+	case c.DistanceKm != nil:
+		distanceScore = 1 / (1 + *c.DistanceKm/2)
+	}
+
+	refTicks, ticks := math.Log1p(float64(ref.TickCount)), math.Log1p(float64(c.TickCount))
+	popularityScore := 0.5 / (1 + math.Abs(refTicks-ticks))
+	if maxTicks > 0 {
+		popularityScore += 0.5 * ticks / math.Log1p(float64(maxTicks))
+	}
+
+	score := similarGradeWeight*gradeScore +
+		similarDistanceWeight*distanceScore +
+		similarPopularityWeight*popularityScore +
+		similarTypeWeight*similarTypeScore(ref.RouteType, c.RouteType)
+	return math.Round(score*1000) / 1000
+}
+
+// similarGradeScore rates how close grade is to ref: 1 for the same grade,
+// falling off linearly to the edge of the family's band (2 V grades, 3 YDS
+// letter grades, 1 step otherwise). ok is false outside the band, across
+// grade families, or when either grade can't be normalized and they differ.
+func similarGradeScore(ref, grade string) (score float64, ok bool) {
+	if strings.EqualFold(strings.TrimSpace(ref), strings.TrimSpace(grade)) {
+		return 1, true
+	}
+
+	refOrder, order := grades.ToOrder(ref), grades.ToOrder(grade)
+	if refOrder < 0 || order < 0 {
+		return 0, false
+	}
+
+	band := 1
+	switch grades.Family(ref) {
+	case grades.FamilyV:
+		band = 2
+	case grades.FamilyYDS:
+		band = 3
+	}
+
+	// Families use separate order ranges, so they are always out of band.
+	diff := refOrder - order
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > band {
+		return 0, false
+	}
+	return 1 - float64(diff)/float64(band+1), true
+}
+
+// similarTypeScore compares MP route types such as "Sport, TR".
+func similarTypeScore(ref, routeType string) float64 {
+	if strings.EqualFold(ref, routeType) {
+		return 1
+	}
+	styles := make(map[string]bool)
+	for _, style := range strings.Split(ref, ",") {
+		if style = strings.ToLower(strings.TrimSpace(style)); style != "" {
+			styles[style] = true
+		}
+	}
+	for _, style := range strings.Split(routeType, ",") {
+		if styles[strings.ToLower(strings.TrimSpace(style))] {
+			return 0.5
+		}
+	}
+	return 0
+}
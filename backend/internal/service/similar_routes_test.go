@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func similarCandidates() []models.SimilarRouteCandidate {
+	km := func(v float64) *float64 { return &v }
+	return []models.SimilarRouteCandidate{
+		{MPRouteID: 100, Name: "Reference", Rating: "V4", RouteType: "Boulder", MPAreaID: 1, TickCount: 20},
+		{MPRouteID: 101, Name: "Same area, same grade", Rating: "V4", RouteType: "Boulder", MPAreaID: 1, DistanceKm: km(0.1), TickCount: 18},
+		{MPRouteID: 102, Name: "Far, same grade", Rating: "V4+", RouteType: "Boulder", MPAreaID: 2, DistanceKm: km(15), TickCount: 18},
+		{MPRouteID: 103, Name: "Same area, two grades up", Rating: "V6", RouteType: "Boulder", MPAreaID: 1, DistanceKm: km(0.2), TickCount: 20},
+		{MPRouteID: 104, Name: "Out of band", Rating: "V7", RouteType: "Boulder", MPAreaID: 1, DistanceKm: km(0.1), TickCount: 40},
+		{MPRouteID: 105, Name: "Ungraded", Rating: "V?", RouteType: "Boulder", MPAreaID: 1, TickCount: 5},
+	}
+}
+
+func TestGetSimilarRoutes_Ranking(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	climbingRepo := NewMockClimbingRepository()
+	climbingRepo.activity.GetSimilarRouteCandidatesFn = func(ctx context.Context, routeID int64, radiusMeters float64, since time.Time, limit int) ([]models.SimilarRouteCandidate, error) {
+		assert.Equal(t, int64(100), routeID)
+		assert.True(t, since.Equal(now.AddDate(0, 0, -similarRoutesTickWindowDays)), "tick window start = %v", since)
+		return similarCandidates(), nil
+	}
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, nil, nil, nil)
+
+	routes, err := svc.GetSimilarRoutes(context.Background(), 100, 10, now)
+	require.NoError(t, err)
+
+	ids := make([]int64, len(routes))
+	for i, r := range routes {
+		ids[i] = r.MPRouteID
+	}
+	// The route itself, out-of-band and unparseable grades are excluded
+	assert.Equal(t, []int64{101, 103, 102}, ids)
+	for i := 1; i < len(routes); i++ {
+		assert.GreaterOrEqual(t, routes[i-1].Score, routes[i].Score)
+	}
+	assert.LessOrEqual(t, routes[0].Score, 1.0)
+
+	limited, err := svc.GetSimilarRoutes(context.Background(), 100, 1, now)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func TestGetSimilarRoutes_UnknownRoute(t *testing.T) {
+	svc := NewClimbTrackingService(NewMockMountainProjectRepository(), NewMockClimbingRepository(), nil, nil, nil)
+
+	_, err := svc.GetSimilarRoutes(context.Background(), 999, 10, time.Now())
+	assert.True(t, errors.Is(err, ErrRouteNotFound), "got %v", err)
+}
+
+func TestSimilarGradeScore(t *testing.T) {
+	tests := []struct {
+		ref, grade string
+		want       float64
+		wantOK     bool
+	}{
+		{"V4", "V4", 1, true},
+		{"V4", "v4", 1, true},
+		{"V4", "V5", 2.0 / 3, true},
+		{"V4", "V2", 1.0 / 3, true},
+		{"V4", "V7", 0, false},
+		{"5.10a", "5.10d", 0.25, true},
+		{"5.10a", "5.11a", 0, false},
+		{"V4", "5.10a", 0, false},
+		{"A2", "A2", 1, true},
+		{"A2", "A3", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := similarGradeScore(tt.ref, tt.grade)
+		assert.Equal(t, tt.wantOK, ok, "similarGradeScore(%q, %q) ok", tt.ref, tt.grade)
+		assert.InDelta(t, tt.want, got, 1e-9, "similarGradeScore(%q, %q)", tt.ref, tt.grade)
+	}
+}
+
+func TestSimilarTypeScore(t *testing.T) {
+	assert.Equal(t, 1.0, similarTypeScore("Sport", "sport"))
+	assert.Equal(t, 0.5, similarTypeScore("Sport, TR", "Sport"))
+	assert.Equal(t, 0.0, similarTypeScore("Trad", "Sport"))
+}
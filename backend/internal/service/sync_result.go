@@ -0,0 +1,34 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// SyncResult summarizes one run of a route sync such as SyncTicksByPriority.
+// Sync methods return it alongside their error; it is non-nil even when the
+// error is set and then covers the work done before the failure.
+type SyncResult struct {
+	RoutesProcessed int           `json:"routes_processed"` // Routes attempted this run, including failures
+	ItemsAdded      int           `json:"items_added"`      // New ticks (or comments, routes) saved this run
+	Failures        int           `json:"failures"`         // Routes that could not be synced
+	Duration        time.Duration `json:"duration_ns"`
+}
+
+// String formats the result for logs and command output.
+func (r *SyncResult) String() string {
+	return fmt.Sprintf("%d routes processed, %d items added, %d failures in %s",
+		r.RoutesProcessed, r.ItemsAdded, r.Failures, r.Duration.Round(time.Second))
+}
+
+// routeFailed records a route that was attempted but could not be synced.
+func (r *SyncResult) routeFailed() {
+	r.RoutesProcessed++
+	r.Failures++
+}
+
+// routeSynced records a route that synced and the items it added.
+func (r *SyncResult) routeSynced(itemsAdded int) {
+	r.RoutesProcessed++
+	r.ItemsAdded += itemsAdded
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/monitoring"
+	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
+)
+
+// treeCoverRefreshJobName is the JobMonitor job name for tree coverage
+// refresh runs.
+const treeCoverRefreshJobName = "tree_cover_refresh"
+
+// treeCoverRefreshProgressEvery is how many profiles are processed between
+// job progress updates.
+const treeCoverRefreshProgressEvery = 10
+
+// TreeCoverFetcher looks up measured tree coverage without falling back to
+// estimates. *boulder_drying.TreeCoverClient implements it.
+type TreeCoverFetcher interface {
+	IsEnabled() bool
+	FetchTreeCoverage(ctx context.Context, lat, lon float64) (float64, error)
+}
+
+var _ TreeCoverFetcher = (*boulder_drying.TreeCoverClient)(nil)
+
+// TreeCoverRefreshJobMonitor is the subset of *monitoring.JobMonitor used by
+// RefreshStaleTreeCoverage.
+type TreeCoverRefreshJobMonitor interface {
+	StartJob(ctx context.Context, jobName, jobType string, totalItems int, metadata map[string]interface{}) (*monitoring.JobExecution, error)
+	UpdateProgress(ctx context.Context, jobID int64, itemsProcessed, succeeded, failed int) error
+	CompleteJob(ctx context.Context, jobID int64) error
+	FailJob(ctx context.Context, jobID int64, errorMsg string) error
+}
+
+var _ TreeCoverRefreshJobMonitor = (*monitoring.JobMonitor)(nil)
+
+// TreeCoverRefreshOptions configures a RefreshStaleTreeCoverage run.
+type TreeCoverRefreshOptions struct {
+	MaxAge       time.Duration // refresh profiles last updated longer ago than this
+	BatchSize    int           // most profiles refreshed per run; the rest wait for the next run
+	RequestDelay time.Duration // pause between Earth Engine queries
+}
+
+// TreeCoverRefreshResult summarizes a RefreshStaleTreeCoverage run.
+type TreeCoverRefreshResult struct {
+	Due     int // stale profiles picked up this run
+	Updated int
+	Failed  int
+	// CircuitOpen is set when the run stopped early because the Earth
+	// Engine circuit breaker opened. Unprocessed profiles stay stale and
+	// are retried next run.
+	CircuitOpen bool
+}
+
+// SetTreeCoverRefresher enables RefreshStaleTreeCoverage. jobMonitor may be
+// nil to run without job tracking.
+func (s *BoulderDryingService) SetTreeCoverRefresher(fetcher TreeCoverFetcher, jobMonitor TreeCoverRefreshJobMonitor) {
+	s.treeCover = fetcher
+	s.treeCoverJobMonitor = jobMonitor
+}
+
+// RefreshStaleTreeCoverage re-fetches tree coverage from Earth Engine for
+// profiles older than opts.MaxAge, so shade estimates follow wildfires and
+// logging. Profiles are refreshed oldest first, at most opts.BatchSize per
+// run with opts.RequestDelay between queries. A failed lookup leaves the
+// profile's coverage as it was, and the run stops early if the Earth Engine
+// circuit breaker opens.
+func (s *BoulderDryingService) RefreshStaleTreeCoverage(ctx context.Context, opts TreeCoverRefreshOptions) (*TreeCoverRefreshResult, error) {
+	if s.treeCover == nil || !s.treeCover.IsEnabled() {
+		return nil, boulder_drying.ErrTreeCoverDisabled
+	}
+
+	cutoff := time.Now().Add(-opts.MaxAge)
+	targets, err := s.bouldersRepo.GetStaleTreeCoverProfiles(ctx, cutoff, opts.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stale tree cover profiles: %w", err)
+	}
+
+	result := &TreeCoverRefreshResult{Due: len(targets)}
+	if len(targets) == 0 {
+		log.Printf("No boulder drying profiles older than %v need a tree cover refresh", opts.MaxAge)
+		return result, nil
+	}
+
+	var jobExec *monitoring.JobExecution
+	if s.treeCoverJobMonitor != nil {
+		jobExec, err = s.treeCoverJobMonitor.StartJob(ctx, treeCoverRefreshJobName, treeCoverRefreshJobName, len(targets), map[string]interface{}{
+			"max_age_days": int(opts.MaxAge.Hours() / 24),
+			"cutoff":       cutoff.Format(time.RFC3339),
+		})
+		if err != nil {
+			log.Printf("Warning: failed to start job monitoring: %v", err)
+			jobExec = nil
+		}
+	}
+
+	reportProgress := func() {
+		if jobExec == nil {
+			return
+		}
+		processed := result.Updated + result.Failed
+		if err := s.treeCoverJobMonitor.UpdateProgress(ctx, jobExec.ID, processed, result.Updated, result.Failed); err != nil {
+			log.Printf("Warning: failed to update job progress: %v", err)
+		}
+	}
+
+	log.Printf("Refreshing tree coverage for %d boulder drying profiles older than %v...", len(targets), opts.MaxAge)
+
+	for i, target := range targets {
+		if i > 0 && opts.RequestDelay > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(opts.RequestDelay):
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			reportProgress()
+			if jobExec != nil {
+				if failErr := s.treeCoverJobMonitor.FailJob(ctx, jobExec.ID, err.Error()); failErr != nil {
+					log.Printf("Warning: failed to mark job as failed: %v", failErr)
+				}
+			}
+			return result, err
+		}
+
+		coverage, err := s.treeCover.FetchTreeCoverage(ctx, target.Latitude, target.Longitude)
+		if errors.Is(err, boulder_drying.ErrTreeCoverCircuitOpen) {
+			result.CircuitOpen = true
+			log.Printf("Earth Engine circuit breaker open, stopping tree cover refresh with %d profiles left for the next run",
+				len(targets)-i)
+			break
+		}
+		if err != nil {
+			result.Failed++
+			log.Printf("Warning: tree cover refresh failed for route %d: %v", target.MPRouteID, err)
+		} else if err := s.bouldersRepo.UpdateTreeCoverage(ctx, target.MPRouteID, coverage); err != nil {
+			result.Failed++
+			log.Printf("Warning: failed to save tree coverage for route %d: %v", target.MPRouteID, err)
+		} else {
+			result.Updated++
+			if target.TreeCoveragePercent != nil && *target.TreeCoveragePercent != coverage {
+				log.Printf("Tree coverage for route %d changed: %.1f%% -> %.1f%%",
+					target.MPRouteID, *target.TreeCoveragePercent, coverage)
+			}
+		}
+
+		if (result.Updated+result.Failed)%treeCoverRefreshProgressEvery == 0 {
+			reportProgress()
+		}
+	}
+
+	reportProgress()
+	if jobExec != nil {
+		if err := s.treeCoverJobMonitor.CompleteJob(ctx, jobExec.ID); err != nil {
+			log.Printf("Warning: failed to complete job: %v", err)
+		}
+	}
+
+	log.Printf("Tree cover refresh complete: %d updated, %d failed of %d due", result.Updated, result.Failed, result.Due)
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/database/boulders"
+	"github.com/alexscott64/woulder/backend/internal/weather/boulder_drying"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTreeCoverFetcher returns coverage per route latitude, or errs[lat].
+type stubTreeCoverFetcher struct {
+	disabled bool
+	coverage map[float64]float64
+	errs     map[float64]error
+	calls    int
+}
+
+func (f *stubTreeCoverFetcher) IsEnabled() bool { return !f.disabled }
+
+func (f *stubTreeCoverFetcher) FetchTreeCoverage(ctx context.Context, lat, lon float64) (float64, error) {
+	f.calls++
+	if err := f.errs[lat]; err != nil {
+		return 0, err
+	}
+	return f.coverage[lat], nil
+}
+
+func newTreeCoverRefreshService(repo *MockBouldersRepository, fetcher TreeCoverFetcher, monitor TreeCoverRefreshJobMonitor) *BoulderDryingService {
+	s := NewBoulderDryingService(repo, nil, nil, nil, nil, nil)
+	s.SetTreeCoverRefresher(fetcher, monitor)
+	return s
+}
+
+func TestRefreshStaleTreeCoverage_UpdatesStaleProfiles(t *testing.T) {
+	old := 60.0
+	var gotCutoff time.Time
+	var gotLimit int
+	updated := map[int64]float64{}
+
+	repo := &MockBouldersRepository{
+		GetStaleTreeCoverProfilesFn: func(ctx context.Context, olderThan time.Time, limit int) ([]boulders.TreeCoverTarget, error) {
+			gotCutoff, gotLimit = olderThan, limit
+			return []boulders.TreeCoverTarget{
+				{MPRouteID: 1, Latitude: 1, Longitude: -120, TreeCoveragePercent: &old},
+				{MPRouteID: 2, Latitude: 2, Longitude: -120},
+				{MPRouteID: 3, Latitude: 3, Longitude: -120},
+			}, nil
+		},
+		UpdateTreeCoverageFn: func(ctx context.Context, mpRouteID int64, pct float64) error {
+			updated[mpRouteID] = pct
+			return nil
+		},
+	}
+	fetcher := &stubTreeCoverFetcher{
+		coverage: map[float64]float64{1: 15, 3: 40},
+		errs:     map[float64]error{2: errors.New("timeout")},
+	}
+	monitor := &mockAreaDiscoveryJobMonitor{}
+
+	s := newTreeCoverRefreshService(repo, fetcher, monitor)
+	maxAge := 365 * 24 * time.Hour
+	result, err := s.RefreshStaleTreeCoverage(context.Background(), TreeCoverRefreshOptions{MaxAge: maxAge, BatchSize: 50})
+	require.NoError(t, err)
+
+	assert.WithinDuration(t, time.Now().Add(-maxAge), gotCutoff, time.Minute)
+	assert.Equal(t, 50, gotLimit)
+	assert.Equal(t, &TreeCoverRefreshResult{Due: 3, Updated: 2, Failed: 1}, result)
+	// The failed lookup keeps its old coverage rather than being overwritten.
+	assert.Equal(t, map[int64]float64{1: 15, 3: 40}, updated)
+
+	require.Len(t, monitor.StartJobCalls, 1)
+	assert.Equal(t, "tree_cover_refresh", monitor.StartJobCalls[0].JobName)
+	assert.Equal(t, 3, monitor.StartJobCalls[0].TotalItems)
+	require.NotEmpty(t, monitor.UpdateProgressCalls)
+	last := monitor.UpdateProgressCalls[len(monitor.UpdateProgressCalls)-1]
+	assert.Equal(t, updateProgressCall{JobID: 1, ItemsProcessed: 3, Succeeded: 2, Failed: 1}, last)
+	assert.Equal(t, []int64{1}, monitor.CompleteJobCalls)
+	assert.Empty(t, monitor.FailJobCalls)
+}
+
+func TestRefreshStaleTreeCoverage_StopsWhenCircuitOpens(t *testing.T) {
+	repo := &MockBouldersRepository{
+		GetStaleTreeCoverProfilesFn: func(ctx context.Context, olderThan time.Time, limit int) ([]boulders.TreeCoverTarget, error) {
+			return []boulders.TreeCoverTarget{
+				{MPRouteID: 1, Latitude: 1},
+				{MPRouteID: 2, Latitude: 2},
+				{MPRouteID: 3, Latitude: 3},
+			}, nil
+		},
+	}
+	fetcher := &stubTreeCoverFetcher{
+		coverage: map[float64]float64{1: 20},
+		errs:     map[float64]error{2: boulder_drying.ErrTreeCoverCircuitOpen},
+	}
+	monitor := &mockAreaDiscoveryJobMonitor{}
+
+	s := newTreeCoverRefreshService(repo, fetcher, monitor)
+	result, err := s.RefreshStaleTreeCoverage(context.Background(), TreeCoverRefreshOptions{MaxAge: time.Hour, BatchSize: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, &TreeCoverRefreshResult{Due: 3, Updated: 1, CircuitOpen: true}, result)
+	assert.Equal(t, 2, fetcher.calls, "route 3 should not be queried once the breaker is open")
+	assert.Equal(t, []int64{1}, monitor.CompleteJobCalls)
+}
+
+func TestRefreshStaleTreeCoverage_NothingDue(t *testing.T) {
+	monitor := &mockAreaDiscoveryJobMonitor{}
+	s := newTreeCoverRefreshService(&MockBouldersRepository{}, &stubTreeCoverFetcher{}, monitor)
+
+	result, err := s.RefreshStaleTreeCoverage(context.Background(), TreeCoverRefreshOptions{MaxAge: time.Hour, BatchSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, &TreeCoverRefreshResult{}, result)
+	assert.Empty(t, monitor.StartJobCalls, "no job is recorded when nothing is due")
+}
+
+func TestRefreshStaleTreeCoverage_Disabled(t *testing.T) {
+	repo := &MockBouldersRepository{
+		GetStaleTreeCoverProfilesFn: func(ctx context.Context, olderThan time.Time, limit int) ([]boulders.TreeCoverTarget, error) {
+			t.Fatal("profiles should not be listed without Earth Engine")
+			return nil, nil
+		},
+	}
+
+	s := newTreeCoverRefreshService(repo, &stubTreeCoverFetcher{disabled: true}, nil)
+	_, err := s.RefreshStaleTreeCoverage(context.Background(), TreeCoverRefreshOptions{MaxAge: time.Hour, BatchSize: 10})
+	assert.ErrorIs(t, err, boulder_drying.ErrTreeCoverDisabled)
+
+	s = NewBoulderDryingService(repo, nil, nil, nil, nil, nil)
+	_, err = s.RefreshStaleTreeCoverage(context.Background(), TreeCoverRefreshOptions{MaxAge: time.Hour, BatchSize: 10})
+	assert.ErrorIs(t, err, boulder_drying.ErrTreeCoverDisabled)
+}
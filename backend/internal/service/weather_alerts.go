@@ -0,0 +1,65 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	weatherPkg "github.com/alexscott64/woulder/backend/internal/weather"
+)
+
+// weatherAlertsMaxAge is how long fetched alerts are reused. Alerts change
+// faster than the hourly weather cache, but every forecast build (including
+// the all-locations endpoint) reads them, so they are not fetched per request.
+const weatherAlertsMaxAge = 15 * time.Minute
+
+// weatherAlertsRetryDelay is how long after a fetch starts that no other
+// fetch is made for the same location. It keeps concurrent requests from
+// all fetching at once, and a failing provider from being called (and
+// slowing down) every request until it recovers.
+const weatherAlertsRetryDelay = time.Minute
+
+type weatherAlertsEntry struct {
+	alerts    []models.WeatherAlert
+	fetchedAt time.Time
+	retryAt   time.Time // no new fetch before this; see weatherAlertsRetryDelay
+}
+
+// getActiveAlerts returns the weather alerts in effect for a location, from
+// cache when fresh. A failed fetch is logged and falls back to the last
+// alerts fetched, so a flaky provider does not clear a known warning, and is
+// not retried for weatherAlertsRetryDelay. No alerts are fetched in offline
+// mode.
+func (s *WeatherService) getActiveAlerts(location *models.Location, now time.Time) []models.WeatherAlert {
+	if s.offlineMode || s.weatherClient == nil {
+		return nil
+	}
+
+	s.alertsCacheMu.Lock()
+	if s.alertsCache == nil {
+		s.alertsCache = make(map[int]weatherAlertsEntry)
+	}
+	entry, ok := s.alertsCache[location.ID]
+	fetch := (!ok || now.Sub(entry.fetchedAt) >= weatherAlertsMaxAge) && !now.Before(entry.retryAt)
+	if fetch {
+		// Claim the fetch; until it succeeds, callers use the cached alerts.
+		entry.retryAt = now.Add(weatherAlertsRetryDelay)
+		s.alertsCache[location.ID] = entry
+	}
+	s.alertsCacheMu.Unlock()
+
+	if fetch {
+		alerts, err := s.weatherClient.GetActiveAlerts(location.Latitude, location.Longitude)
+		if err != nil {
+			log.Printf("Warning: failed to fetch weather alerts for location %d (retrying after %v): %v",
+				location.ID, weatherAlertsRetryDelay, err)
+		} else {
+			entry = weatherAlertsEntry{alerts: alerts, fetchedAt: now}
+			s.alertsCacheMu.Lock()
+			s.alertsCache[location.ID] = entry
+			s.alertsCacheMu.Unlock()
+		}
+	}
+
+	return weatherPkg.ActiveAlerts(entry.alerts, now)
+}
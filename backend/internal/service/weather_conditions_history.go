@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	weatherPkg "github.com/alexscott64/woulder/backend/internal/weather"
+)
+
+const (
+	// maxConditionsHistoryDays bounds the conditions history window.
+	maxConditionsHistoryDays = 365
+
+	// conditionsHistoryTimezone is the zone days are keyed by. Stored daily
+	// weather aggregates and tick dates are both Pacific, so pairing them
+	// in any other zone would shift one series against the other.
+	conditionsHistoryTimezone = "America/Los_Angeles"
+)
+
+// GetConditionsHistory pairs a location's daily weather with its daily tick
+// counts for the last days days, ending today, so climbing activity can be
+// compared with the conditions on each day. Weather comes from the stored
+// daily aggregates, with days covered by the hourly history rolled up fresh
+// (see mergeDailyWeather). Days with no stored weather have a nil Weather.
+func (s *WeatherService) GetConditionsHistory(ctx context.Context, locationID int, days int) (*models.ConditionsHistory, error) {
+	return s.conditionsHistory(ctx, locationID, days, time.Now())
+}
+
+func (s *WeatherService) conditionsHistory(ctx context.Context, locationID int, days int, now time.Time) (*models.ConditionsHistory, error) {
+	if days < 1 {
+		days = 1
+	}
+	if days > maxConditionsHistoryDays {
+		days = maxConditionsHistoryDays
+	}
+
+	if _, err := s.locationsRepo.GetByID(ctx, locationID); err != nil {
+		return nil, fmt.Errorf("location not found: %w", err)
+	}
+
+	tz, err := time.LoadLocation(conditionsHistoryTimezone)
+	if err != nil {
+		tz = time.UTC
+	}
+	today := now.In(tz)
+	startDate := today.AddDate(0, 0, -(days - 1)).Format("2006-01-02")
+	endDate := today.Format("2006-01-02")
+
+	stored, err := s.weatherRepo.GetDailyAggregates(ctx, locationID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily weather: %w", err)
+	}
+
+	// Hourly rows are only kept for the last 30 days.
+	hourly, err := s.weatherRepo.GetHistorical(ctx, locationID, min(days, 30))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hourly weather: %w", err)
+	}
+	weatherByDate := mergeDailyWeather(stored, weatherPkg.AggregateDaily(hourly, tz))
+
+	ticksByDate := make(map[string]models.DailyTickCount)
+	if s.climbTrackingService != nil {
+		counts, err := s.climbTrackingService.GetDailyTickCounts(ctx, locationID, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get daily tick counts: %w", err)
+		}
+		for _, c := range counts {
+			ticksByDate[c.Date] = c
+		}
+	}
+
+	history := &models.ConditionsHistory{
+		LocationID: locationID,
+		Timezone:   tz.String(),
+		Days:       make([]models.ConditionsHistoryDay, 0, days),
+	}
+	for i := days - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		day := models.ConditionsHistoryDay{
+			Date:       date,
+			TickCount:  ticksByDate[date].TickCount,
+			RouteCount: ticksByDate[date].RouteCount,
+		}
+		if agg, ok := weatherByDate[date]; ok {
+			day.Weather = &models.DailyWeatherSummary{
+				MinTemperature:     agg.MinTemperature,
+				MaxTemperature:     agg.MaxTemperature,
+				AvgTemperature:     agg.AvgTemperature,
+				TotalPrecipitation: agg.TotalPrecipitation,
+				AvgHumidity:        agg.AvgHumidity,
+				AvgWindSpeed:       agg.AvgWindSpeed,
+				SnowEstimateInches: agg.SnowEstimateInches,
+				HourCount:          agg.SourceHourCount,
+			}
+		}
+		history.Days = append(history.Days, day)
+	}
+
+	return history, nil
+}
+
+// mergeDailyWeather keys daily weather by date. Stored aggregates are only
+// refreshed with the weather, so a day rolled up from the hourly history
+// replaces the stored one when it covers at least as many hours. The oldest
+// hourly day is usually cut off partway, so its stored aggregate is kept.
+func mergeDailyWeather(stored, fromHourly []models.WeatherDailyAggregate) map[string]models.WeatherDailyAggregate {
+	byDate := make(map[string]models.WeatherDailyAggregate, len(stored)+len(fromHourly))
+	for _, agg := range stored {
+		byDate[agg.LocalDate] = agg
+	}
+	for _, agg := range fromHourly {
+		if existing, ok := byDate[agg.LocalDate]; ok && existing.SourceHourCount > agg.SourceHourCount {
+			continue
+		}
+		byDate[agg.LocalDate] = agg
+	}
+	return byDate
+}
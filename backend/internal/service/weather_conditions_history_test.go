@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/database/dberrors"
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConditionsHistory_PairsWeatherWithTicks(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	now := time.Date(2025, 3, 12, 15, 0, 0, 0, pacific)
+
+	weatherRepo := &MockWeatherRepository{
+		GetDailyAggregatesFn: func(ctx context.Context, locationID int, startDate, endDate string) ([]models.WeatherDailyAggregate, error) {
+			assert.Equal(t, "2025-03-09", startDate)
+			assert.Equal(t, "2025-03-12", endDate)
+			return []models.WeatherDailyAggregate{
+				{LocalDate: "2025-03-09", MaxTemperature: 55, TotalPrecipitation: 0, SourceHourCount: 24},
+				// Stale: refreshed before the afternoon's hours came in
+				{LocalDate: "2025-03-12", MaxTemperature: 40, SourceHourCount: 8},
+			}, nil
+		},
+		GetHistoricalFn: func(ctx context.Context, locationID int, days int) ([]models.WeatherData, error) {
+			assert.Equal(t, 4, days)
+			var hourly []models.WeatherData
+			for h := 0; h < 15; h++ {
+				hourly = append(hourly, models.WeatherData{
+					Timestamp:     time.Date(2025, 3, 12, h, 0, 0, 0, pacific),
+					Temperature:   45,
+					Precipitation: 0.02,
+				})
+			}
+			return hourly, nil
+		},
+	}
+	locationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id, Name: "Index"}, nil
+		},
+	}
+	climbingRepo := NewMockClimbingRepository()
+	climbingRepo.activity.GetDailyTickCountsFn = func(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error) {
+		assert.Equal(t, 2, locationID)
+		return []models.DailyTickCount{
+			{Date: "2025-03-09", TickCount: 14, RouteCount: 9},
+			{Date: "2025-03-10", TickCount: 3, RouteCount: 3},
+		}, nil
+	}
+	climbService := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+	service := NewWeatherService(weatherRepo, locationsRepo, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), climbService)
+
+	history, err := service.conditionsHistory(context.Background(), 2, 4, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, history.LocationID)
+	assert.Equal(t, "America/Los_Angeles", history.Timezone)
+	if !assert.Len(t, history.Days, 4) {
+		return
+	}
+
+	dry := history.Days[0]
+	assert.Equal(t, "2025-03-09", dry.Date)
+	assert.Equal(t, 14, dry.TickCount)
+	assert.Equal(t, 9, dry.RouteCount)
+	if assert.NotNil(t, dry.Weather) {
+		assert.Equal(t, 55.0, dry.Weather.MaxTemperature)
+		assert.Equal(t, 24, dry.Weather.HourCount)
+	}
+
+	// Ticks but no stored weather
+	assert.Equal(t, "2025-03-10", history.Days[1].Date)
+	assert.Equal(t, 3, history.Days[1].TickCount)
+	assert.Nil(t, history.Days[1].Weather)
+
+	// Neither
+	assert.Equal(t, "2025-03-11", history.Days[2].Date)
+	assert.Zero(t, history.Days[2].TickCount)
+	assert.Nil(t, history.Days[2].Weather)
+
+	// Today comes from the fuller hourly rollup
+	today := history.Days[3]
+	assert.Equal(t, "2025-03-12", today.Date)
+	if assert.NotNil(t, today.Weather) {
+		assert.Equal(t, 45.0, today.Weather.MaxTemperature)
+		assert.Equal(t, 15, today.Weather.HourCount)
+		assert.InDelta(t, 0.3, today.Weather.TotalPrecipitation, 1e-9)
+	}
+}
+
+func TestConditionsHistory_LocationNotFound(t *testing.T) {
+	locationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return nil, dberrors.ErrNotFound
+		},
+	}
+	service := NewWeatherService(&MockWeatherRepository{}, locationsRepo, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), nil)
+
+	_, err := service.GetConditionsHistory(context.Background(), 99, 30)
+	assert.True(t, dberrors.IsNotFound(err), "error should wrap not found, got %v", err)
+}
+
+func TestConditionsHistory_TickError(t *testing.T) {
+	locationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id}, nil
+		},
+	}
+	climbingRepo := NewMockClimbingRepository()
+	climbingRepo.activity.GetDailyTickCountsFn = func(ctx context.Context, locationID int, startDate, endDate string) ([]models.DailyTickCount, error) {
+		return nil, errors.New("connection refused")
+	}
+	climbService := NewClimbTrackingService(NewMockMountainProjectRepository(), climbingRepo, &MockMPClient{}, nil, nil)
+	service := NewWeatherService(&MockWeatherRepository{}, locationsRepo, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), climbService)
+
+	_, err := service.GetConditionsHistory(context.Background(), 2, 7)
+	assert.EqualError(t, err, "failed to get daily tick counts: connection refused")
+}
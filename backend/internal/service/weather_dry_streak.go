@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	weatherPkg "github.com/alexscott64/woulder/backend/internal/weather"
+)
+
+// dryStreakForecastHours is the cached forecast window scanned for dry
+// streaks, the full 16-day horizon the forecast is fetched with.
+const dryStreakForecastHours = 384
+
+// GetDryStreaks finds the upcoming runs of dry days at a location (see
+// weatherPkg.FindDryStreaks) in its local timezone. It reads only the cached
+// forecast, never the upstream API, so it is cheap enough for the location
+// detail; with no cached forecast the outlook has no streaks.
+func (s *WeatherService) GetDryStreaks(ctx context.Context, locationID int) (*models.DryStreakOutlook, error) {
+	return s.dryStreaks(ctx, locationID, time.Now())
+}
+
+func (s *WeatherService) dryStreaks(ctx context.Context, locationID int, now time.Time) (*models.DryStreakOutlook, error) {
+	location, err := s.locationsRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("location not found: %w", err)
+	}
+
+	tz, err := time.LoadLocation(locationTimezone(location))
+	if err != nil {
+		tz = time.UTC
+	}
+
+	hourly, err := s.weatherRepo.GetForecast(ctx, locationID, dryStreakForecastHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forecast: %w", err)
+	}
+	days := weatherPkg.AggregateDaily(hourly, tz)
+
+	streaks := weatherPkg.FindDryStreaks(days, now.In(tz).Format("2006-01-02"), weatherPkg.DefaultDryStreakCriteria)
+	outlook := &models.DryStreakOutlook{
+		Longest: weatherPkg.LongestDryStreak(streaks),
+		Streaks: streaks,
+	}
+	if len(days) > 0 {
+		outlook.ForecastThrough = days[len(days)-1].LocalDate
+	}
+	return outlook, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryStreaks_UsesLocationTimezone(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+	// 11pm on May 10 in Denver is already May 11 in UTC.
+	now := time.Date(2026, 5, 10, 23, 0, 0, 0, denver)
+
+	weatherRepo := &MockWeatherRepository{
+		GetForecastFn: func(ctx context.Context, locationID int, hours int) ([]models.WeatherData, error) {
+			assert.Equal(t, dryStreakForecastHours, hours)
+			var hourly []models.WeatherData
+			for h := 0; h < 72; h++ {
+				ts := time.Date(2026, 5, 11, 0, 0, 0, 0, denver).Add(time.Duration(h) * time.Hour)
+				precip := 0.0
+				if ts.Day() == 11 && ts.Hour() == 15 {
+					precip = 0.5 // afternoon storm tomorrow, then it clears
+				}
+				hourly = append(hourly, models.WeatherData{Timestamp: ts, Temperature: 60, Precipitation: precip})
+			}
+			return hourly, nil
+		},
+	}
+	locationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id, Name: "Eldorado", Timezone: "America/Denver"}, nil
+		},
+	}
+	svc := NewWeatherService(weatherRepo, locationsRepo, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), nil)
+
+	outlook, err := svc.dryStreaks(context.Background(), 4, now)
+	require.NoError(t, err)
+
+	require.Len(t, outlook.Streaks, 1)
+	assert.Equal(t, "2026-05-12", outlook.Streaks[0].StartDate)
+	assert.Equal(t, 2, outlook.Streaks[0].Days)
+	require.NotNil(t, outlook.Longest)
+	assert.Equal(t, "2026-05-12", outlook.Longest.StartDate)
+	assert.Equal(t, "2026-05-13", outlook.ForecastThrough)
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	weatherPkg "github.com/alexscott64/woulder/backend/internal/weather"
+	sunpkg "github.com/alexscott64/woulder/backend/internal/weather/sun"
+)
+
+// Verdicts reported on models.LocationNow.Verdict.
+const (
+	VerdictGo   = "go"
+	VerdictWait = "wait"
+	VerdictNo   = "no"
+)
+
+const (
+	// locationNowMaxAge bounds how long a cached conditions snapshot is
+	// served. The background weather refresh rebuilds every location's
+	// snapshot each cycle, so this only expires snapshots of locations whose
+	// refresh failed, or when background refresh is off; it matches the
+	// lifetime of the cached weather they are built from.
+	locationNowMaxAge = 1 * time.Hour
+
+	// locationsNowWorkers caps concurrent snapshot builds on cache misses.
+	locationsNowWorkers = 10
+
+	// snowboundDepthInches is the snow depth at which a location counts as
+	// out of season: the boulders are buried, whatever the weather does.
+	snowboundDepthInches = 12.0
+)
+
+// locationNowEntry is a cached conditions snapshot. Daylight, hours since
+// rain, alerts, the verdict and the score are recomputed on every read so
+// they stay correct between refreshes.
+type locationNowEntry struct {
+	snapshot   models.LocationNow
+	latitude   float64
+	longitude  float64
+	condition  *models.ClimbingCondition
+	rockSafe   bool       // false when wet-sensitive rock is wet
+	lastRainAt *time.Time // nil when no rain in the fetched history
+	alerts     []models.WeatherAlert
+	snowDepth  float64 // inches on the ground; 0 when unknown
+	builtAt    time.Time
+}
+
+// outOfSeason reports whether the location is snowbound.
+func (entry *locationNowEntry) outOfSeason() bool {
+	return entry.snowDepth >= snowboundDepthInches
+}
+
+// GetLocationNow returns a compact climbable-now snapshot for a location,
+// combining current weather, the rock drying estimate, and sun times. The
+// weather-derived part is cached: the background weather refresh rebuilds
+// it, and a manual refresh (see InvalidateLocationNow) drops it, so frequent
+// polling does not rebuild the full forecast.
+func (s *WeatherService) GetLocationNow(ctx context.Context, locationID int) (*models.LocationNow, error) {
+	now := time.Now()
+	entry, err := s.locationNowEntry(ctx, locationID, now)
+	if err != nil {
+		return nil, err
+	}
+	return entry.read(now), nil
+}
+
+// GetLocationsNow returns the climbable-now snapshot of every location, or
+// of an area's locations, best climbability score first. With climbableOnly,
+// only locations with a "go" verdict are returned. Snapshots come from the
+// same cache as GetLocationNow; locations whose snapshot cannot be built are
+// skipped.
+func (s *WeatherService) GetLocationsNow(ctx context.Context, areaID *int, climbableOnly bool) ([]models.LocationNow, error) {
+	ranked, err := s.rankLocationsNow(ctx, areaID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.LocationNow, 0, len(ranked))
+	for _, r := range ranked {
+		if climbableOnly && r.snapshot.Verdict != VerdictGo {
+			continue
+		}
+		result = append(result, *r.snapshot)
+	}
+	return result, nil
+}
+
+// GetRankedLocations returns locations best climbability score first (ties
+// by name), at most limit. It ranks like GetLocationsNow but only reads
+// snapshots that are already cached, which the background weather refresh
+// keeps warm, and never builds one, so it is cheap enough for the homepage.
+// Locations without a fresh snapshot are left out, as are those in exclude
+// and those out of season (snowbound).
+func (s *WeatherService) GetRankedLocations(ctx context.Context, exclude map[int]bool, limit int) ([]models.RankedLocation, error) {
+	ranked, err := s.rankLocationsNow(ctx, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.RankedLocation, 0, min(limit, len(ranked)))
+	for _, r := range ranked {
+		if len(result) == limit {
+			break
+		}
+		if exclude[r.snapshot.LocationID] || r.entry.outOfSeason() {
+			continue
+		}
+		result = append(result, models.RankedLocation{
+			LocationID:        r.snapshot.LocationID,
+			Name:              r.snapshot.Name,
+			ClimbabilityScore: r.snapshot.ClimbabilityScore,
+			Verdict:           r.snapshot.Verdict,
+			Reason:            rankedReason(r.snapshot, r.entry),
+			WeatherUpdatedAt:  r.snapshot.WeatherUpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// rankedSnapshot is a location's snapshot as read now, with the cache entry
+// it was read from.
+type rankedSnapshot struct {
+	snapshot *models.LocationNow
+	entry    *locationNowEntry
+}
+
+// rankLocationsNow reads the snapshot of every location, or of an area's
+// locations, best climbability score first (ties by name). Missing or stale
+// snapshots are built, unless cachedOnly is set, in which case those
+// locations are left out and no weather is fetched. Locations whose snapshot
+// cannot be built are skipped.
+func (s *WeatherService) rankLocationsNow(ctx context.Context, areaID *int, cachedOnly bool) ([]rankedSnapshot, error) {
+	var locations []models.Location
+	var err error
+	if areaID != nil {
+		locations, err = s.locationsRepo.GetByArea(ctx, *areaID)
+	} else {
+		locations, err = s.locationsRepo.GetAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entries := make([]*locationNowEntry, len(locations))
+	if cachedOnly {
+		for i, loc := range locations {
+			entries[i] = s.cachedLocationNow(loc.ID, now)
+		}
+	} else {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, locationsNowWorkers)
+		for i, loc := range locations {
+			wg.Add(1)
+			go func(i, locationID int) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				entry, err := s.locationNowEntry(ctx, locationID, now)
+				if err != nil {
+					log.Printf("Warning: failed to build conditions for location %d: %v", locationID, err)
+					return
+				}
+				entries[i] = entry
+			}(i, loc.ID)
+		}
+		wg.Wait()
+	}
+
+	ranked := make([]rankedSnapshot, 0, len(entries))
+	for _, entry := range entries {
+		if entry != nil {
+			ranked = append(ranked, rankedSnapshot{snapshot: entry.read(now), entry: entry})
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i].snapshot, ranked[j].snapshot
+		if a.ClimbabilityScore != b.ClimbabilityScore {
+			return a.ClimbabilityScore > b.ClimbabilityScore
+		}
+		return a.Name < b.Name
+	})
+	return ranked, nil
+}
+
+// rankedReason explains a ranked snapshot in a few words: the verdict's
+// reason when it has one, otherwise the first of today's condition reasons.
+// "dark" is skipped because the score ignores daylight.
+func rankedReason(snapshot *models.LocationNow, entry *locationNowEntry) string {
+	if snapshot.Reason != "" && snapshot.Reason != "dark" {
+		return snapshot.Reason
+	}
+	if entry.condition != nil && len(entry.condition.Reasons) > 0 {
+		return entry.condition.Reasons[0]
+	}
+	return "dry"
+}
+
+// InvalidateLocationNow drops every cached conditions snapshot, so the next
+// read rebuilds it from the latest weather.
+func (s *WeatherService) InvalidateLocationNow() {
+	s.nowCacheMu.Lock()
+	s.nowCache = make(map[int]*locationNowEntry)
+	s.nowCacheMu.Unlock()
+}
+
+// locationNowEntry returns the cached snapshot entry for a location,
+// building it if it is missing or older than locationNowMaxAge.
+func (s *WeatherService) locationNowEntry(ctx context.Context, locationID int, now time.Time) (*locationNowEntry, error) {
+	if entry := s.cachedLocationNow(locationID, now); entry != nil {
+		return entry, nil
+	}
+
+	forecast, err := s.getLocationWeatherWithOptions(ctx, locationID, false)
+	if err != nil {
+		return nil, err
+	}
+	entry := buildLocationNowEntry(forecast, s.rainThresholdInches, now)
+	s.storeLocationNow(entry)
+	return entry, nil
+}
+
+// cachedLocationNow returns the cached snapshot entry for a location, or nil
+// if there is none younger than locationNowMaxAge.
+func (s *WeatherService) cachedLocationNow(locationID int, now time.Time) *locationNowEntry {
+	s.nowCacheMu.Lock()
+	entry, ok := s.nowCache[locationID]
+	s.nowCacheMu.Unlock()
+
+	if ok && now.Sub(entry.builtAt) < locationNowMaxAge {
+		return entry
+	}
+	return nil
+}
+
+// storeLocationNow caches a snapshot entry, replacing any older one.
+func (s *WeatherService) storeLocationNow(entry *locationNowEntry) {
+	s.nowCacheMu.Lock()
+	defer s.nowCacheMu.Unlock()
+	if s.nowCache == nil {
+		s.nowCache = make(map[int]*locationNowEntry)
+	}
+	s.nowCache[entry.snapshot.LocationID] = entry
+}
+
+// read completes the cached snapshot for the time now.
+func (entry *locationNowEntry) read(now time.Time) *models.LocationNow {
+	result := entry.snapshot
+	result.IsDaylight = sunpkg.Calculate(entry.latitude, entry.longitude, now).IsAboveHorizon()
+	result.Alerts = weatherPkg.ActiveAlerts(entry.alerts, now)
+	if entry.lastRainAt != nil {
+		hours := math.Max(0, math.Round(now.Sub(*entry.lastRainAt).Hours()*10)/10)
+		result.HoursSinceRain = &hours
+	}
+	result.Verdict, result.Reason = locationNowVerdict(&result, entry)
+	result.ClimbabilityScore = climbabilityScore(&result, entry)
+	return &result
+}
+
+// buildLocationNowEntry builds a snapshot entry from a full forecast. Hours
+// with at least rainThresholdInches of precipitation count as rain.
+func buildLocationNowEntry(forecast *models.WeatherForecast, rainThresholdInches float64, builtAt time.Time) *locationNowEntry {
+	snapshot := models.LocationNow{
+		LocationID:       forecast.LocationID,
+		Name:             forecast.Location.Name,
+		Temperature:      forecast.Current.Temperature,
+		Precipitation:    forecast.Current.Precipitation,
+		IsRaining:        forecast.Current.IsRaining,
+		IsSnowing:        forecast.Current.IsSnowing,
+		IsDry:            true,
+		Sunrise:          forecast.Sunrise,
+		Sunset:           forecast.Sunset,
+		WeatherUpdatedAt: forecast.Current.CreatedAt,
+	}
+	if snapshot.WeatherUpdatedAt.IsZero() {
+		// Freshly fetched rows are not stamped until they are persisted.
+		snapshot.WeatherUpdatedAt = builtAt
+	}
+	rockSafe := true
+	var snowDepth float64
+	if forecast.SnowDepthInches != nil {
+		snowDepth = *forecast.SnowDepthInches
+	}
+	if rock := forecast.RockDryingStatus; rock != nil {
+		snapshot.IsDry = !rock.IsWet
+		snapshot.HoursUntilDry = rock.HoursUntilDry
+		snapshot.DryingStatus = rock.Status
+		rockSafe = rock.IsSafe
+	}
+
+	return &locationNowEntry{
+		snapshot:   snapshot,
+		latitude:   forecast.Location.Latitude,
+		longitude:  forecast.Location.Longitude,
+		condition:  forecast.TodayCondition,
+		rockSafe:   rockSafe,
+		lastRainAt: lastRainAt(forecast, rainThresholdInches, builtAt),
+		alerts:     forecast.Alerts,
+		snowDepth:  snowDepth,
+		builtAt:    builtAt,
+	}
+}
+
+// lastRainAt finds the latest hour up to now with at least threshold inches
+// of precipitation, in the history, the past hours at the head of the
+// forecast, and the current observation. Returns nil if there is none.
+func lastRainAt(forecast *models.WeatherForecast, threshold float64, now time.Time) *time.Time {
+	var latest *time.Time
+	consider := func(w *models.WeatherData) {
+		if w.Precipitation >= threshold && !w.Timestamp.After(now) && (latest == nil || w.Timestamp.After(*latest)) {
+			ts := w.Timestamp
+			latest = &ts
+		}
+	}
+	for i := range forecast.Historical {
+		consider(&forecast.Historical[i])
+	}
+	for i := range forecast.Hourly {
+		consider(&forecast.Hourly[i])
+	}
+	consider(&forecast.Current)
+	return latest
+}
+
+// climbabilityScore rates current conditions from 0 to 100 for ranking
+// locations against each other. Rain, snow and wet wet-sensitive rock score
+// 0. Otherwise today's conditions set the base (good 100, marginal 60, bad
+// 20, unknown 80), drying rock costs 2 points per hour until dry, and an
+// active severe alert caps the score at 30. Daylight is deliberately left
+// out so the ranking does not flip at sunset.
+func climbabilityScore(now *models.LocationNow, entry *locationNowEntry) int {
+	if now.IsRaining || now.IsSnowing || !entry.rockSafe {
+		return 0
+	}
+
+	score := 80.0
+	if entry.condition != nil {
+		switch entry.condition.Level {
+		case "good":
+			score = 100
+		case "marginal":
+			score = 60
+		case "bad":
+			score = 20
+		}
+	}
+	if !now.IsDry {
+		score -= 2 * now.HoursUntilDry
+	}
+	if weatherPkg.FirstSevereAlert(now.Alerts) != nil {
+		score = math.Min(score, 30)
+	}
+	return int(math.Round(math.Max(0, math.Min(100, score))))
+}
+
+// locationNowVerdict reduces a snapshot to a one-word verdict:
+//
+//	no   - raining or snowing, today's conditions are bad, wet-sensitive rock is wet,
+//	       or the rock will not dry within the day
+//	wait - a severe weather alert is active, rock is still drying, or it
+//	       is dark
+//	go   - otherwise
+func locationNowVerdict(now *models.LocationNow, entry *locationNowEntry) (string, string) {
+	condition := entry.condition
+	severeAlert := weatherPkg.FirstSevereAlert(now.Alerts)
+	switch {
+	case now.IsRaining:
+		return VerdictNo, "raining"
+	case now.IsSnowing:
+		return VerdictNo, "snowing"
+	case !entry.rockSafe:
+		return VerdictNo, "wet-sensitive rock is wet"
+	case condition != nil && condition.Level == "bad":
+		if len(condition.Reasons) > 0 {
+			return VerdictNo, condition.Reasons[0]
+		}
+		return VerdictNo, "poor conditions today"
+	case !now.IsDry && now.HoursUntilDry > 24:
+		return VerdictNo, "rock is wet"
+	case severeAlert != nil:
+		return VerdictWait, "weather alert: " + severeAlert.Event
+	case !now.IsDry:
+		return VerdictWait, "rock is drying"
+	case !now.IsDaylight:
+		return VerdictWait, "dark"
+	default:
+		return VerdictGo, ""
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocationNowVerdict(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        models.LocationNow
+		entry      locationNowEntry
+		wantVerd   string
+		wantReason string
+	}{
+		{
+			name:     "dry daylight",
+			now:      models.LocationNow{IsDry: true, IsDaylight: true},
+			entry:    locationNowEntry{rockSafe: true},
+			wantVerd: VerdictGo,
+		},
+		{
+			name:       "raining",
+			now:        models.LocationNow{Precipitation: 0.05, IsRaining: true, IsDry: true, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictNo,
+			wantReason: "raining",
+		},
+		{
+			name:       "snowing",
+			now:        models.LocationNow{Precipitation: 0.05, IsSnowing: true, IsDry: true, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictNo,
+			wantReason: "snowing",
+		},
+		{
+			name:     "trace precipitation is not rain",
+			now:      models.LocationNow{Precipitation: 0.005, IsDry: true, IsDaylight: true},
+			entry:    locationNowEntry{rockSafe: true},
+			wantVerd: VerdictGo,
+		},
+		{
+			name:       "wet-sensitive rock wet",
+			now:        models.LocationNow{HoursUntilDry: 6, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: false},
+			wantVerd:   VerdictNo,
+			wantReason: "wet-sensitive rock is wet",
+		},
+		{
+			name:       "bad day",
+			now:        models.LocationNow{IsDry: true, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: true, condition: &models.ClimbingCondition{Level: "bad", Reasons: []string{"high winds"}}},
+			wantVerd:   VerdictNo,
+			wantReason: "high winds",
+		},
+		{
+			name:       "wet for days",
+			now:        models.LocationNow{HoursUntilDry: 36, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictNo,
+			wantReason: "rock is wet",
+		},
+		{
+			name:       "drying",
+			now:        models.LocationNow{HoursUntilDry: 3, IsDaylight: true},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictWait,
+			wantReason: "rock is drying",
+		},
+		{
+			name:       "dark",
+			now:        models.LocationNow{IsDry: true},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictWait,
+			wantReason: "dark",
+		},
+		{
+			name: "severe weather alert",
+			now: models.LocationNow{IsDry: true, IsDaylight: true, Alerts: []models.WeatherAlert{
+				{Event: "Air Quality Alert", Severity: "Moderate"},
+				{Event: "Severe Thunderstorm Watch", Severity: "Severe"},
+			}},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictWait,
+			wantReason: "weather alert: Severe Thunderstorm Watch",
+		},
+		{
+			name: "minor weather alert",
+			now: models.LocationNow{IsDry: true, IsDaylight: true, Alerts: []models.WeatherAlert{
+				{Event: "Air Quality Alert", Severity: "Moderate"},
+			}},
+			entry:    locationNowEntry{rockSafe: true},
+			wantVerd: VerdictGo,
+		},
+		{
+			name: "raining outranks alert",
+			now: models.LocationNow{Precipitation: 0.05, IsRaining: true, IsDry: true, IsDaylight: true, Alerts: []models.WeatherAlert{
+				{Event: "Flood Watch", Severity: "Severe"},
+			}},
+			entry:      locationNowEntry{rockSafe: true},
+			wantVerd:   VerdictNo,
+			wantReason: "raining",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, reason := locationNowVerdict(&tt.now, &tt.entry)
+			assert.Equal(t, tt.wantVerd, verdict)
+			assert.Equal(t, tt.wantReason, reason)
+		})
+	}
+}
+
+func TestWeatherService_GetLocationNow_CachesBetweenRefreshes(t *testing.T) {
+	getCurrentCalls := 0
+	fetchedAt := time.Now().Add(-10 * time.Minute) // fresh DB cache, no API call
+
+	mockWeatherRepo := &MockWeatherRepository{
+		GetCurrentFn: func(ctx context.Context, locationID int) (*models.WeatherData, error) {
+			getCurrentCalls++
+			return &models.WeatherData{
+				LocationID:  locationID,
+				Timestamp:   time.Now(),
+				CreatedAt:   fetchedAt,
+				Temperature: 55.0,
+				Humidity:    50,
+			}, nil
+		},
+		GetForecastFn: func(ctx context.Context, locationID int, hours int) ([]models.WeatherData, error) {
+			return []models.WeatherData{}, nil
+		},
+		GetHistoricalFn: func(ctx context.Context, locationID int, days int) ([]models.WeatherData, error) {
+			return []models.WeatherData{}, nil
+		},
+	}
+	mockLocationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id, Name: "Index", Latitude: 47.82, Longitude: -121.55}, nil
+		},
+	}
+	mockRocksRepo := &MockRocksRepository{
+		GetRockTypesByLocationFn: func(ctx context.Context, locationID int) ([]models.RockType, error) {
+			return []models.RockType{{ID: 1, Name: "Granite", BaseDryingHours: 4.0}}, nil
+		},
+	}
+
+	service := NewWeatherService(mockWeatherRepo, mockLocationsRepo, mockRocksRepo, weather.NewWeatherService("test_api_key"), nil)
+
+	first, err := service.GetLocationNow(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, first.LocationID)
+	assert.Equal(t, "Index", first.Name)
+	assert.Equal(t, 55.0, first.Temperature)
+	assert.True(t, first.IsDry)
+	assert.True(t, first.WeatherUpdatedAt.Equal(fetchedAt))
+	assert.NotEmpty(t, first.Verdict)
+	assert.Equal(t, 1, getCurrentCalls)
+	assert.Empty(t, first.Alerts, "alerts are not fetched unless enabled")
+
+	// Polling again before a refresh is served from cache.
+	_, err = service.GetLocationNow(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, getCurrentCalls, "second poll should not rebuild the forecast")
+
+	// A manual refresh invalidates the snapshot.
+	service.InvalidateLocationNow()
+
+	_, err = service.GetLocationNow(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, getCurrentCalls, "poll after invalidation should rebuild the snapshot")
+
+	// The list endpoint is served from the same cache.
+	mockLocationsRepo.GetAllFn = func(ctx context.Context) ([]models.Location, error) {
+		return []models.Location{{ID: 2, Name: "Index"}}, nil
+	}
+	all, err := service.GetLocationsNow(context.Background(), nil, false)
+	assert.NoError(t, err)
+	assert.Len(t, all, 1)
+	assert.Equal(t, 2, getCurrentCalls, "list should reuse the cached snapshot")
+}
+
+func TestClimbabilityScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		now   models.LocationNow
+		entry locationNowEntry
+		want  int
+	}{
+		{
+			name:  "dry, unknown conditions",
+			now:   models.LocationNow{IsDry: true},
+			entry: locationNowEntry{rockSafe: true},
+			want:  80,
+		},
+		{
+			name:  "dry, good day",
+			now:   models.LocationNow{IsDry: true},
+			entry: locationNowEntry{rockSafe: true, condition: &models.ClimbingCondition{Level: "good"}},
+			want:  100,
+		},
+		{
+			name:  "marginal day, drying",
+			now:   models.LocationNow{HoursUntilDry: 5},
+			entry: locationNowEntry{rockSafe: true, condition: &models.ClimbingCondition{Level: "marginal"}},
+			want:  50,
+		},
+		{
+			name:  "long drying floors at zero",
+			now:   models.LocationNow{HoursUntilDry: 72},
+			entry: locationNowEntry{rockSafe: true},
+			want:  0,
+		},
+		{
+			name:  "raining",
+			now:   models.LocationNow{IsRaining: true, IsDry: true},
+			entry: locationNowEntry{rockSafe: true, condition: &models.ClimbingCondition{Level: "good"}},
+			want:  0,
+		},
+		{
+			name:  "wet-sensitive rock wet",
+			now:   models.LocationNow{HoursUntilDry: 1},
+			entry: locationNowEntry{rockSafe: false},
+			want:  0,
+		},
+		{
+			name: "severe alert caps score",
+			now: models.LocationNow{IsDry: true, Alerts: []models.WeatherAlert{
+				{Event: "High Wind Warning", Severity: "Severe"},
+			}},
+			entry: locationNowEntry{rockSafe: true, condition: &models.ClimbingCondition{Level: "good"}},
+			want:  30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, climbabilityScore(&tt.now, &tt.entry))
+		})
+	}
+}
+
+func TestLocationNowEntry_HoursSinceRain(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	forecast := &models.WeatherForecast{
+		Current: models.WeatherData{Timestamp: now, Precipitation: 0},
+		Historical: []models.WeatherData{
+			{Timestamp: now.Add(-30 * time.Hour), Precipitation: 0.2},
+			{Timestamp: now.Add(-20 * time.Hour), Precipitation: 0.005}, // trace
+		},
+		Hourly: []models.WeatherData{
+			{Timestamp: now.Add(-6 * time.Hour), Precipitation: 0.05},
+			{Timestamp: now.Add(3 * time.Hour), Precipitation: 0.3}, // forecast rain
+		},
+	}
+
+	entry := buildLocationNowEntry(forecast, 0.01, now)
+	got := entry.read(now.Add(90 * time.Minute))
+	if assert.NotNil(t, got.HoursSinceRain) {
+		assert.Equal(t, 7.5, *got.HoursSinceRain)
+	}
+
+	dry := buildLocationNowEntry(&models.WeatherForecast{Current: models.WeatherData{Timestamp: now}}, 0.01, now)
+	assert.Nil(t, dry.read(now).HoursSinceRain)
+}
+
+func TestWeatherService_GetRankedLocations(t *testing.T) {
+	now := time.Now()
+	entry := func(id int, name string, level string, rockSafe bool, builtAt time.Time) *locationNowEntry {
+		return &locationNowEntry{
+			snapshot:  models.LocationNow{LocationID: id, Name: name, IsDry: true},
+			condition: &models.ClimbingCondition{Level: level, Reasons: []string{level + " conditions"}},
+			rockSafe:  rockSafe,
+			builtAt:   builtAt,
+		}
+	}
+
+	locations := &MockLocationsRepository{
+		GetAllFn: func(ctx context.Context) ([]models.Location, error) {
+			all := make([]models.Location, 8)
+			for i := range all {
+				all[i].ID = i + 1
+			}
+			return all, nil
+		},
+	}
+	svc := NewWeatherService(&MockWeatherRepository{}, locations, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), nil)
+	svc.storeLocationNow(entry(1, "Index", "marginal", true, now))
+	svc.storeLocationNow(entry(2, "Gold Bar", "good", true, now))
+	svc.storeLocationNow(entry(3, "Leavenworth", "good", true, now))
+	svc.storeLocationNow(entry(4, "Tieton", "good", false, now))                           // wet sandstone
+	svc.storeLocationNow(entry(5, "Vantage", "good", true, now.Add(-2*locationNowMaxAge))) // stale
+	svc.storeLocationNow(entry(6, "Squamish", "good", true, now))                          // closed
+	snowbound := entry(7, "Mazama", "good", true, now)
+	snowbound.snowDepth = 30
+	svc.storeLocationNow(snowbound)
+	// Location 8 has no snapshot cached, so it is left out.
+
+	ranked, err := svc.GetRankedLocations(context.Background(), map[int]bool{6: true}, 10)
+	require.NoError(t, err)
+
+	names := make([]string, len(ranked))
+	for i, r := range ranked {
+		names[i] = r.Name
+	}
+	assert.Equal(t, []string{"Gold Bar", "Leavenworth", "Index", "Tieton"}, names)
+	assert.Equal(t, 100, ranked[0].ClimbabilityScore)
+	assert.Equal(t, "wet-sensitive rock is wet", ranked[3].Reason)
+	assert.NotEmpty(t, ranked[0].Reason)
+
+	top, err := svc.GetRankedLocations(context.Background(), nil, 2)
+	require.NoError(t, err)
+	assert.Len(t, top, 2)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -74,10 +75,27 @@ type WeatherService struct {
 	// to refresh DB data on demand. See WEATHER_OFFLINE_MODE in config.
 	offlineMode bool
 
+	// rainThresholdInches is the current-hour precipitation at or above
+	// which current weather is reported as raining/snowing. See
+	// WEATHER_RAIN_THRESHOLD_INCHES in config.
+	rainThresholdInches float64
+
 	// Background refresh management
 	refreshMutex sync.Mutex
 	lastRefresh  time.Time
 	isRefreshing bool
+
+	// Cached /now snapshots by location ID (see GetLocationNow)
+	nowCacheMu sync.Mutex
+	nowCache   map[int]*locationNowEntry
+
+	// Cached weather alerts by location ID (see getActiveAlerts)
+	alertsCacheMu sync.Mutex
+	alertsCache   map[int]weatherAlertsEntry
+
+	// Cached daily sun times by location ID (see GetLocationSunTimes)
+	sunTimesCacheMu sync.Mutex
+	sunTimesCache   map[int]sunTimesEntry
 }
 
 func NewWeatherService(
@@ -96,6 +114,10 @@ func NewWeatherService(
 		rockTempCalculator:   &rock_temp.Calculator{},
 		pestAnalyzer:         &pests.PestAnalyzer{},
 		climbTrackingService: climbService,
+		rainThresholdInches:  weatherPkg.DefaultRainThresholdInches,
+		nowCache:             make(map[int]*locationNowEntry),
+		alertsCache:          make(map[int]weatherAlertsEntry),
+		sunTimesCache:        make(map[int]sunTimesEntry),
 	}
 }
 
@@ -110,6 +132,16 @@ func (s *WeatherService) SetOfflineMode(enabled bool) {
 	}
 }
 
+// SetRainThreshold sets the current-hour precipitation (inches) at or above
+// which current weather counts as raining or snowing. Non-positive values
+// restore weather.DefaultRainThresholdInches.
+func (s *WeatherService) SetRainThreshold(inches float64) {
+	if inches <= 0 {
+		inches = weatherPkg.DefaultRainThresholdInches
+	}
+	s.rainThresholdInches = inches
+}
+
 // GetLocationWeather retrieves complete weather forecast for a location
 // Uses cached data from database if available and fresh (< 1 hour old)
 // includeClimbHistory controls whether to fetch climb history (expensive query)
@@ -117,6 +149,77 @@ func (s *WeatherService) GetLocationWeather(ctx context.Context, locationID int)
 	return s.getLocationWeatherWithOptions(ctx, locationID, true)
 }
 
+// GetLocationWeatherForDays is GetLocationWeather cut to a shorter (or, for
+// history, longer) window: Hourly, DailySunTimes and DailySnowDepth cover the
+// next forecastDays days and Historical the last pastDays days. Everything
+// derived from them (drying, snow depth, conditions) is still calculated from
+// the full cached data, so only the payload shrinks. History beyond the
+// default week comes from the stored hourly rows, which are kept for 30 days.
+func (s *WeatherService) GetLocationWeatherForDays(ctx context.Context, locationID, forecastDays, pastDays int) (*models.WeatherForecast, error) {
+	forecast, err := s.GetLocationWeather(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if pastDays > client.DefaultPastDays {
+		historical, err := s.weatherRepo.GetHistorical(ctx, locationID, pastDays)
+		if err != nil {
+			log.Printf("Warning: failed to get %d days of historical weather for location %d: %v", pastDays, locationID, err)
+		} else {
+			forecast.Historical = historical
+		}
+	}
+
+	trimForecastWindow(forecast, forecastDays, pastDays, time.Now().UTC())
+	return forecast, nil
+}
+
+// trimForecastWindow drops forecast data more than forecastDays days after
+// now and history more than pastDays days before it. Past spin-up hours at
+// the head of Hourly are kept.
+func trimForecastWindow(forecast *models.WeatherForecast, forecastDays, pastDays int, now time.Time) {
+	end := now.Add(time.Duration(forecastDays) * 24 * time.Hour)
+	hourly := forecast.Hourly[:0]
+	for _, h := range forecast.Hourly {
+		if h.Timestamp.Before(end) {
+			hourly = append(hourly, h)
+		}
+	}
+	forecast.Hourly = hourly
+
+	start := now.Add(-time.Duration(pastDays) * 24 * time.Hour)
+	historical := forecast.Historical[:0]
+	for _, h := range forecast.Historical {
+		if !h.Timestamp.Before(start) {
+			historical = append(historical, h)
+		}
+	}
+	forecast.Historical = historical
+
+	if len(forecast.DailySunTimes) > forecastDays {
+		forecast.DailySunTimes = forecast.DailySunTimes[:forecastDays]
+	}
+
+	// Snow depth is keyed by local date; today counts as the first day.
+	tz, err := time.LoadLocation(locationTimezone(&forecast.Location))
+	if err != nil {
+		tz = time.UTC
+	}
+	lastDate := now.In(tz).AddDate(0, 0, forecastDays-1).Format("2006-01-02")
+	for date := range forecast.DailySnowDepth {
+		if date > lastDate {
+			delete(forecast.DailySnowDepth, date)
+		}
+	}
+}
+
+// splitPastHours splits hourly, which is sorted by time, into the hours
+// before now and the rest.
+func splitPastHours(hourly []models.WeatherData, now time.Time) (past, future []models.WeatherData) {
+	i := sort.Search(len(hourly), func(i int) bool { return !hourly[i].Timestamp.Before(now) })
+	return hourly[:i], hourly[i:]
+}
+
 // getLocationWeatherWithOptions is the internal implementation with configurable options
 func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, locationID int, includeClimbHistory bool) (*models.WeatherForecast, error) {
 	// 1. Get location
@@ -185,8 +288,10 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 		} else {
 			log.Printf("Cache miss or stale data, fetching fresh weather for location %d", locationID)
 			var fetchErr error
+			// Always fetch the full horizon: the cache is shared by every
+			// caller, whatever window they asked for.
 			current, hourlyForecast, sunTimes, fetchErr = s.weatherClient.GetCurrentAndForecast(
-				location.Latitude, location.Longitude,
+				location.Latitude, location.Longitude, client.DefaultForecastDays, 0,
 			)
 			if fetchErr != nil {
 				return nil, fmt.Errorf("failed to fetch weather: %w", fetchErr)
@@ -317,7 +422,15 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 	dailySnowDepth := s.calculateDailySnowDepth(location, current, futureForecast, analyticsHistorical)
 
 	// 6. Calculate rock drying status (use high-fidelity recent hourly history)
-	rockStatus, err := s.calculateRockDryingStatus(ctx, location, current, historical, snowDepth)
+	// Sun exposure feeds rock drying, rock temp and the exposure summary;
+	// fetch it once per build.
+	sunExposure, err := s.rocksRepo.GetSunExposureByLocation(ctx, locationID)
+	if err != nil {
+		log.Printf("Warning: failed to get sun exposure for location %d: %v", locationID, err)
+		sunExposure = nil
+	}
+
+	rockStatus, err := s.calculateRockDryingStatus(ctx, location, current, historical, sunExposure, snowDepth)
 	if err != nil {
 		log.Printf("Warning: failed to calculate rock drying: %v", err)
 	}
@@ -346,16 +459,23 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 			}
 		}
 	}
-	rockTempStatus, err := s.calculateRockTempStatus(ctx, location, current, pastHourly, futureForecast)
+	rockTempStatus, err := s.calculateRockTempStatus(ctx, location, current, pastHourly, futureForecast, sunExposure)
 	if err != nil {
 		log.Printf("Warning: failed to calculate rock temp: %v", err)
 	}
 
+	sunExposureSummary := sunpkg.SummarizeExposureProfile(sunExposure)
+
 	// 7. Calculate climbing conditions
 	conditionCalc := &weatherPkg.ConditionCalculator{}
 	todayCondition := conditionCalc.CalculateTodayCondition(current, futureForecast, historical)
+	alerts := s.getActiveAlerts(location, nowUTC)
+	weatherPkg.ApplyAlertsToCondition(&todayCondition, alerts)
 	rainLast48h := conditionCalc.CalculateRainLast48h(historical, futureForecast)
 	rainNext48h := s.calculateRainNext48h(futureForecast)
+	weatherPkg.ApplyForecastConfidence(hourlyForecast, nowUTC)
+	current.Confidence = weatherPkg.ConfidenceHigh
+	weatherPkg.ApplyPrecipitationState(current, s.rainThresholdInches)
 
 	// 8. Calculate pest conditions (use analytics history)
 	pestConditions := s.calculatePestConditions(current, analyticsHistorical)
@@ -396,6 +516,7 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 				Sunset:  st.Sunset,
 			})
 		}
+		s.storeSunTimes(locationID, dailySunTimes, time.Now())
 	} else {
 		// Cache path fallback: compute sunrise/sunset locally so frontend still gets daily sun times
 		dailySunTimes = buildDailySunTimesFallback(location.Latitude, location.Longitude, 16)
@@ -416,6 +537,7 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 		DailySunTimes:         dailySunTimes,
 		RockDryingStatus:      rockStatus,
 		RockTemperatureStatus: rockTempStatus,
+		SunExposureSummary:    sunExposureSummary,
 		SnowDepthInches:       snowDepth,
 		DailySnowDepth:        dailySnowDepth,
 		TodayCondition:        &todayCondition,
@@ -424,6 +546,7 @@ func (s *WeatherService) getLocationWeatherWithOptions(ctx context.Context, loca
 		PestConditions:        pestConditions,
 		LastClimbedInfo:       lastClimbedInfo,
 		ClimbHistory:          climbHistory,
+		Alerts:                alerts,
 	}
 
 	return forecast, nil
@@ -525,6 +648,7 @@ func (s *WeatherService) calculateRockDryingStatus(
 	location *models.Location,
 	current *models.WeatherData,
 	historical []models.WeatherData,
+	sunExposure *models.LocationSunExposure,
 	snowDepth *float64,
 ) (*models.RockDryingStatus, error) {
 	// Get rock types
@@ -533,9 +657,6 @@ func (s *WeatherService) calculateRockDryingStatus(
 		return nil, fmt.Errorf("no rock types for location")
 	}
 
-	// Get sun exposure
-	sunExposure, _ := s.rocksRepo.GetSunExposureByLocation(ctx, location.ID)
-
 	// Calculate with full rock type data
 	status := s.rockCalculator.CalculateDryingStatus(
 		rockTypes,
@@ -567,6 +688,7 @@ func (s *WeatherService) calculateRockTempStatus(
 	current *models.WeatherData,
 	pastHourly []models.WeatherData,
 	forecast []models.WeatherData,
+	sunExposure *models.LocationSunExposure,
 ) (*models.RockTemperatureStatus, error) {
 	rockTypes, err := s.rocksRepo.GetRockTypesByLocation(ctx, location.ID)
 	if err != nil {
@@ -577,8 +699,6 @@ func (s *WeatherService) calculateRockTempStatus(
 		return nil, nil
 	}
 
-	sunExposure, _ := s.rocksRepo.GetSunExposureByLocation(ctx, location.ID)
-
 	// Resolve rock type group (no boulder override at the location level — boulder
 	// services pass overrides directly to the calculator).
 	rockTypeGroup, _ := rock_temp.ResolveRockTypeGroup(rockTypes, "")
@@ -663,6 +783,11 @@ func (s *WeatherService) RefreshAllWeatherWithOptions(ctx context.Context, force
 	s.isRefreshing = true
 	s.refreshMutex.Unlock()
 
+	// A manual refresh must not serve conditions built from the old weather
+	if forceRefresh {
+		s.InvalidateLocationNow()
+	}
+
 	defer func() {
 		s.refreshMutex.Lock()
 		s.isRefreshing = false
@@ -677,87 +802,130 @@ func (s *WeatherService) RefreshAllWeatherWithOptions(ctx context.Context, force
 
 	log.Printf("Refreshing weather for %d locations...", len(locations))
 
-	pacificTZ, tzErr := time.LoadLocation("America/Los_Angeles")
-	if tzErr != nil || pacificTZ == nil {
+	aggregateStartDate, aggregateEndDate := dailyAggregateWindow(time.Now())
+
+	for _, loc := range locations {
+		// Step failures are logged inside; one location never stops the rest.
+		_ = s.refreshLocation(ctx, loc, aggregateStartDate, aggregateEndDate)
+	}
+
+	return nil
+}
+
+// RefreshLocationWeather runs the background refresh for a single location:
+// it stores the last 7 days of history and the 16-day forecast, updates the
+// daily aggregates and rebuilds the location's cached weather. Each step is
+// attempted even if an earlier one fails; the failures are returned joined.
+func (s *WeatherService) RefreshLocationWeather(ctx context.Context, locationID int) error {
+	if s.offlineMode {
+		return fmt.Errorf("weather offline mode is enabled")
+	}
+	loc, err := s.locationsRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return fmt.Errorf("location not found: %w", err)
+	}
+
+	aggregateStartDate, aggregateEndDate := dailyAggregateWindow(time.Now())
+
+	return s.refreshLocation(ctx, *loc, aggregateStartDate, aggregateEndDate)
+}
+
+// dailyAggregateWindow is the Pacific date range whose daily aggregates a
+// refresh recomputes: the last 35 days through today.
+func dailyAggregateWindow(now time.Time) (startDate, endDate string) {
+	pacificTZ, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil || pacificTZ == nil {
 		pacificTZ = time.UTC
 	}
-	aggregateEndDate := time.Now().In(pacificTZ).Format("2006-01-02")
-	aggregateStartDate := time.Now().In(pacificTZ).AddDate(0, 0, -35).Format("2006-01-02")
+	today := now.In(pacificTZ)
+	return today.AddDate(0, 0, -35).Format("2006-01-02"), today.Format("2006-01-02")
+}
 
-	for _, loc := range locations {
-		// Fetch and save historical weather data (last 7 days) to database
-		// This ensures rain_last_48h calculations use fresh data
-		historical, err := s.weatherClient.GetHistoricalWeather(loc.Latitude, loc.Longitude, 7)
-		if err != nil {
-			log.Printf("Failed to fetch historical weather for location %d: %v", loc.ID, err)
+// refreshLocation refreshes one location's stored weather; see
+// RefreshLocationWeather.
+func (s *WeatherService) refreshLocation(ctx context.Context, loc models.Location, aggregateStartDate, aggregateEndDate string) error {
+	var errs []error
+
+	// Fetch and save historical weather data (last 7 days) to database
+	// This ensures rain_last_48h calculations use fresh data
+	historical, err := s.weatherClient.GetHistoricalWeather(loc.Latitude, loc.Longitude, client.DefaultPastDays)
+	if err != nil {
+		log.Printf("Failed to fetch historical weather for location %d: %v", loc.ID, err)
+		errs = append(errs, fmt.Errorf("historical weather: %w", err))
+	} else {
+		// Delete old hourly weather data (older than 30 days)
+		log.Printf("Deleting old weather data for location %d (keeping last 30 days)", loc.ID)
+		if err := s.weatherRepo.DeleteOldForLocation(ctx, loc.ID, 30); err != nil {
+			log.Printf("ERROR: failed to delete old weather data for location %d: %v", loc.ID, err)
 		} else {
-			// Delete old hourly weather data (older than 30 days)
-			log.Printf("Deleting old weather data for location %d (keeping last 30 days)", loc.ID)
-			if err := s.weatherRepo.DeleteOldForLocation(ctx, loc.ID, 30); err != nil {
-				log.Printf("ERROR: failed to delete old weather data for location %d: %v", loc.ID, err)
-			} else {
-				log.Printf("Successfully deleted old weather data for location %d", loc.ID)
-			}
+			log.Printf("Successfully deleted old weather data for location %d", loc.ID)
+		}
 
-			// Save historical data to database
-			for i := range historical {
-				historical[i].LocationID = loc.ID
-				if err := s.weatherRepo.Save(ctx, &historical[i]); err != nil {
-					log.Printf("Failed to save historical weather for location %d: %v", loc.ID, err)
-				}
+		// Save historical data to database
+		for i := range historical {
+			historical[i].LocationID = loc.ID
+			if err := s.weatherRepo.Save(ctx, &historical[i]); err != nil {
+				log.Printf("Failed to save historical weather for location %d: %v", loc.ID, err)
 			}
-			log.Printf("Updated historical weather for location %d (%d hours)", loc.ID, len(historical))
 		}
+		log.Printf("Updated historical weather for location %d (%d hours)", loc.ID, len(historical))
+	}
 
-		// Fetch and save forecast data (next 16 days) to database
-		// This is CRITICAL for boulder drying 6-day forecasts to work
-		forecast, err := s.weatherClient.GetForecast(loc.Latitude, loc.Longitude)
-		if err != nil {
-			log.Printf("Failed to fetch forecast weather for location %d: %v", loc.ID, err)
-		} else {
-			// FIX: Validate response length BEFORE replacing the cache.
-			// See minForecastHoursForCacheReplacement docs for context. The
-			// bulk-refresh path uses GetForecast() which has no past_hours,
-			// so every entry is "future" by construction — but we count
-			// explicitly anyway in case that ever changes.
-			nowUTCForCheck := time.Now().UTC()
-			futureHours := 0
-			for i := range forecast {
-				if forecast[i].Timestamp.After(nowUTCForCheck) {
-					futureHours++
-				}
+	// Fetch and save forecast data (next 16 days) to database
+	// This is CRITICAL for boulder drying 6-day forecasts to work
+	forecast, err := s.weatherClient.GetForecast(loc.Latitude, loc.Longitude, client.DefaultForecastDays)
+	if err != nil {
+		log.Printf("Failed to fetch forecast weather for location %d: %v", loc.ID, err)
+		errs = append(errs, fmt.Errorf("forecast: %w", err))
+	} else {
+		// FIX: Validate response length BEFORE replacing the cache.
+		// See minForecastHoursForCacheReplacement docs for context. The
+		// bulk-refresh path uses GetForecast() which has no past_hours,
+		// so every entry is "future" by construction — but we count
+		// explicitly anyway in case that ever changes.
+		nowUTCForCheck := time.Now().UTC()
+		futureHours := 0
+		for i := range forecast {
+			if forecast[i].Timestamp.After(nowUTCForCheck) {
+				futureHours++
 			}
+		}
 
-			if futureHours < minForecastHoursForCacheReplacement {
-				log.Printf(
-					"WARN: Open-Meteo returned truncated forecast for location %d (lat=%.5f lon=%.5f) during bulk refresh: future_hours=%d, threshold=%d. "+
-						"Skipping cache replacement to preserve previously-cached forecast.",
-					loc.ID, loc.Latitude, loc.Longitude,
-					futureHours, minForecastHoursForCacheReplacement,
-				)
+		if futureHours < minForecastHoursForCacheReplacement {
+			log.Printf(
+				"WARN: Open-Meteo returned truncated forecast for location %d (lat=%.5f lon=%.5f) during bulk refresh: future_hours=%d, threshold=%d. "+
+					"Skipping cache replacement to preserve previously-cached forecast.",
+				loc.ID, loc.Latitude, loc.Longitude,
+				futureHours, minForecastHoursForCacheReplacement,
+			)
+		} else {
+			// Atomically replace the future-forecast cache (delete + save in
+			// a single transaction) to prevent destructive intermediate
+			// state on transient DB errors.
+			if err := s.weatherRepo.ReplaceFutureForLocation(ctx, loc.ID, forecast); err != nil {
+				log.Printf("ERROR: failed to atomically replace future weather data for location %d: %v", loc.ID, err)
+				errs = append(errs, fmt.Errorf("save forecast: %w", err))
 			} else {
-				// Atomically replace the future-forecast cache (delete + save in
-				// a single transaction) to prevent destructive intermediate
-				// state on transient DB errors.
-				if err := s.weatherRepo.ReplaceFutureForLocation(ctx, loc.ID, forecast); err != nil {
-					log.Printf("ERROR: failed to atomically replace future weather data for location %d: %v", loc.ID, err)
-				} else {
-					log.Printf("Updated forecast weather for location %d (%d hours)", loc.ID, len(forecast))
-				}
+				log.Printf("Updated forecast weather for location %d (%d hours)", loc.ID, len(forecast))
 			}
 		}
+	}
 
-		if err := s.weatherRepo.UpsertDailyAggregates(ctx, loc.ID, aggregateStartDate, aggregateEndDate); err != nil {
-			log.Printf("Failed to upsert daily weather aggregates for location %d: %v", loc.ID, err)
-		}
+	if err := s.weatherRepo.UpsertDailyAggregates(ctx, loc.ID, aggregateStartDate, aggregateEndDate); err != nil {
+		log.Printf("Failed to upsert daily weather aggregates for location %d: %v", loc.ID, err)
+		errs = append(errs, fmt.Errorf("daily aggregates: %w", err))
+	}
 
-		// Fetch current/forecast weather (this also triggers calculations)
-		if _, err := s.GetLocationWeather(ctx, loc.ID); err != nil {
-			log.Printf("Failed to refresh location %d: %v", loc.ID, err)
-		}
+	// Fetch current/forecast weather (this also triggers calculations) and
+	// rebuild the cached conditions snapshot from it
+	if forecast, err := s.GetLocationWeather(ctx, loc.ID); err != nil {
+		log.Printf("Failed to refresh location %d: %v", loc.ID, err)
+		errs = append(errs, fmt.Errorf("current weather: %w", err))
+	} else {
+		s.storeLocationNow(buildLocationNowEntry(forecast, s.rainThresholdInches, time.Now()))
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
 
 // StartBackgroundRefresh starts automatic weather refresh
@@ -774,8 +942,10 @@ func (s *WeatherService) StartBackgroundRefresh(interval time.Duration) {
 	}()
 }
 
-// GetWeatherByCoordinates fetches weather for arbitrary coordinates
-func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon float64) (*models.WeatherForecast, error) {
+// GetWeatherByCoordinates fetches forecastDays days of weather for arbitrary
+// coordinates. With pastDays > 0 the past hours are returned as Historical;
+// with 0, Hourly starts with the usual 12 hours of spin-up data.
+func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon float64, forecastDays, pastDays int) (*models.WeatherForecast, error) {
 	if s.offlineMode {
 		// No DB cache exists for arbitrary coordinates — return a stub
 		// rather than calling the API. This endpoint is rarely used in dev.
@@ -788,11 +958,16 @@ func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon f
 		}, nil
 	}
 	// Fetch weather from API
-	current, hourlyForecast, sunTimes, err := s.weatherClient.GetCurrentAndForecast(lat, lon)
+	current, hourlyForecast, sunTimes, err := s.weatherClient.GetCurrentAndForecast(lat, lon, forecastDays, pastDays)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch weather: %w", err)
 	}
 
+	historical := []models.WeatherData{}
+	if pastDays > 0 {
+		historical, hourlyForecast = splitPastHours(hourlyForecast, time.Now().UTC())
+	}
+
 	// Extract sunrise/sunset
 	var sunrise, sunset string
 	if sunTimes != nil {
@@ -800,11 +975,15 @@ func (s *WeatherService) GetWeatherByCoordinates(ctx context.Context, lat, lon f
 		sunset = sunTimes.Sunset
 	}
 
+	weatherPkg.ApplyForecastConfidence(hourlyForecast, time.Now().UTC())
+	current.Confidence = weatherPkg.ConfidenceHigh
+	weatherPkg.ApplyPrecipitationState(current, s.rainThresholdInches)
+
 	// Build response (no location, no rock drying)
 	forecast := &models.WeatherForecast{
 		Current:    *current,
 		Hourly:     hourlyForecast,
-		Historical: []models.WeatherData{},
+		Historical: historical,
 		Sunrise:    sunrise,
 		Sunset:     sunset,
 	}
@@ -124,7 +124,7 @@ func TestWeatherService_GetWeatherByCoordinates(t *testing.T) {
 			// Pass nil for climb service in tests - it's optional
 			service := NewWeatherService(mockWeatherRepo, mockLocationsRepo, mockRocksRepo, client, nil)
 
-			forecast, err := service.GetWeatherByCoordinates(context.Background(), tt.lat, tt.lon)
+			forecast, err := service.GetWeatherByCoordinates(context.Background(), tt.lat, tt.lon, 16, 0)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -404,7 +404,7 @@ func TestWeatherService_GetAllWeather_IncludesClimbHistory(t *testing.T) {
 	mockMountainProjectRepo := &MockMountainProjectRepository{}
 
 	// Create climb tracking service with the mock repositories
-	mockClimbService := NewClimbTrackingService(mockMountainProjectRepo, mockClimbingRepo, nil, nil)
+	mockClimbService := NewClimbTrackingService(mockMountainProjectRepo, mockClimbingRepo, nil, nil, nil)
 
 	client := weather.NewWeatherService("test_api_key")
 	service := NewWeatherService(mockWeatherRepo, mockLocationsRepo, mockRocksRepo, client, mockClimbService)
@@ -595,6 +595,7 @@ func TestWeatherService_GetLocationWeather_FreshCacheDoesNotPersist(t *testing.T
 	var (
 		deleteFutureCalled bool
 		saveCount          int
+		sunExposureCalls   int
 	)
 
 	freshTime := time.Now().Add(-10 * time.Minute) // 10 minutes ago → fresh
@@ -671,6 +672,7 @@ func TestWeatherService_GetLocationWeather_FreshCacheDoesNotPersist(t *testing.T
 			}, nil
 		},
 		GetSunExposureByLocationFn: func(ctx context.Context, locationID int) (*models.LocationSunExposure, error) {
+			sunExposureCalls++
 			return &models.LocationSunExposure{
 				LocationID:         locationID,
 				SouthFacingPercent: 50,
@@ -690,6 +692,10 @@ func TestWeatherService_GetLocationWeather_FreshCacheDoesNotPersist(t *testing.T
 	// Fresh cache should NOT trigger any DB writes
 	assert.False(t, deleteFutureCalled, "DeleteFutureForLocation/ReplaceFutureForLocation should NOT be called for fresh cache")
 	assert.Equal(t, 0, saveCount, "Save should NOT be called for fresh cache")
+
+	// Sun exposure is shared by rock drying, rock temp and the summary
+	assert.Equal(t, 1, sunExposureCalls, "sun exposure should be fetched once per location build")
+	assert.NotNil(t, forecast.SunExposureSummary)
 }
 
 // Helper function
@@ -1049,3 +1055,50 @@ func TestWeatherService_GetLocationWeather_TruncatedResponse_PreservesCache(t *t
 	_ = err
 	_ = fmt.Sprintf // keep fmt import used if asserts are tightened later
 }
+
+func TestTrimForecastWindow(t *testing.T) {
+	now := time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC) // 13:00 in Los Angeles
+	hours := func(from, to int) []models.WeatherData {
+		var data []models.WeatherData
+		for h := from; h < to; h++ {
+			data = append(data, models.WeatherData{Timestamp: now.Add(time.Duration(h) * time.Hour)})
+		}
+		return data
+	}
+
+	forecast := &models.WeatherForecast{
+		Location:   models.Location{Timezone: "America/Los_Angeles"},
+		Hourly:     hours(-12, 16*24),
+		Historical: hours(-7*24, 0),
+		DailySunTimes: []models.DailySunTimes{
+			{Date: "2026-07-01"}, {Date: "2026-07-02"}, {Date: "2026-07-03"}, {Date: "2026-07-04"},
+		},
+		DailySnowDepth: map[string]float64{
+			"2026-07-01": 0, "2026-07-02": 0, "2026-07-03": 0, "2026-07-04": 0,
+		},
+	}
+
+	trimForecastWindow(forecast, 3, 1, now)
+
+	// The 12 spin-up hours stay; the forecast ends 72 hours from now
+	assert.Len(t, forecast.Hourly, 12+72)
+	assert.Len(t, forecast.Historical, 24)
+	assert.Len(t, forecast.DailySunTimes, 3)
+	assert.NotContains(t, forecast.DailySnowDepth, "2026-07-04")
+	assert.Contains(t, forecast.DailySnowDepth, "2026-07-03")
+}
+
+func TestSplitPastHours(t *testing.T) {
+	now := time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC)
+	hourly := []models.WeatherData{
+		{Timestamp: now.Add(-2 * time.Hour)},
+		{Timestamp: now.Add(-time.Hour)},
+		{Timestamp: now},
+		{Timestamp: now.Add(time.Hour)},
+	}
+
+	past, future := splitPastHours(hourly, now)
+	assert.Len(t, past, 2)
+	assert.Len(t, future, 2)
+	assert.True(t, future[0].Timestamp.Equal(now))
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+const (
+	// maxSunTimesDays matches the Open-Meteo forecast horizon.
+	maxSunTimesDays = 16
+
+	// sunTimesMaxAge bounds how long fetched sun times are reused. Entries
+	// are also dropped when the UTC day rolls over so day 0 stays "today".
+	sunTimesMaxAge = 12 * time.Hour
+)
+
+type sunTimesEntry struct {
+	daily     []models.DailySunTimes
+	fetchedAt time.Time
+}
+
+// GetLocationSunTimes returns daily sunrise/sunset for a location, starting
+// today, without building a weather forecast. Open-Meteo's daily sun data is
+// cached per location (and refreshed by full weather fetches); in offline
+// mode or when Open-Meteo fails it is computed locally instead.
+func (s *WeatherService) GetLocationSunTimes(ctx context.Context, locationID int, days int) ([]models.DailySunTimes, error) {
+	if days < 1 {
+		days = 1
+	}
+	if days > maxSunTimesDays {
+		days = maxSunTimesDays
+	}
+
+	location, err := s.locationsRepo.GetByID(ctx, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("location not found: %w", err)
+	}
+
+	now := time.Now()
+
+	s.sunTimesCacheMu.Lock()
+	entry, ok := s.sunTimesCache[locationID]
+	s.sunTimesCacheMu.Unlock()
+
+	if !ok || !sunTimesFresh(entry, now) {
+		daily := s.fetchSunTimes(location, now)
+		if daily == nil && ok {
+			log.Printf("Warning: using stale sun times for location %d", locationID)
+			daily = entry.daily
+		}
+		if daily == nil {
+			daily = buildDailySunTimesFallback(location.Latitude, location.Longitude, maxSunTimesDays)
+		} else {
+			s.storeSunTimes(locationID, daily, now)
+		}
+		entry = sunTimesEntry{daily: daily, fetchedAt: now}
+	}
+
+	if len(entry.daily) > days {
+		return entry.daily[:days], nil
+	}
+	return entry.daily, nil
+}
+
+// fetchSunTimes fetches the full sun-times horizon from Open-Meteo. It
+// returns nil in offline mode or when the fetch fails.
+func (s *WeatherService) fetchSunTimes(location *models.Location, now time.Time) []models.DailySunTimes {
+	if s.offlineMode || s.weatherClient == nil {
+		return nil
+	}
+
+	sunTimes, err := s.weatherClient.GetSunTimes(location.Latitude, location.Longitude, maxSunTimesDays)
+	if err != nil {
+		log.Printf("Warning: failed to fetch sun times for location %d: %v", location.ID, err)
+		return nil
+	}
+
+	daily := make([]models.DailySunTimes, 0, len(sunTimes.Daily))
+	for _, st := range sunTimes.Daily {
+		daily = append(daily, models.DailySunTimes{
+			Date:    st.Date,
+			Sunrise: st.Sunrise,
+			Sunset:  st.Sunset,
+		})
+	}
+	return daily
+}
+
+// storeSunTimes caches sun times for a location. Full weather fetches call
+// this too, so the sun-times endpoint rarely needs its own request.
+func (s *WeatherService) storeSunTimes(locationID int, daily []models.DailySunTimes, fetchedAt time.Time) {
+	s.sunTimesCacheMu.Lock()
+	defer s.sunTimesCacheMu.Unlock()
+	if s.sunTimesCache == nil {
+		s.sunTimesCache = make(map[int]sunTimesEntry)
+	}
+	s.sunTimesCache[locationID] = sunTimesEntry{daily: daily, fetchedAt: fetchedAt}
+}
+
+// sunTimesFresh reports whether cached sun times still start on today's
+// (UTC) date and are younger than sunTimesMaxAge.
+func sunTimesFresh(entry sunTimesEntry, now time.Time) bool {
+	if now.Sub(entry.fetchedAt) >= sunTimesMaxAge || len(entry.daily) == 0 {
+		return false
+	}
+	return entry.daily[0].Date == now.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSunTimesTestService() *WeatherService {
+	mockLocationsRepo := &MockLocationsRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*models.Location, error) {
+			return &models.Location{ID: id, Name: "Index", Latitude: 47.82, Longitude: -121.55}, nil
+		},
+	}
+	return NewWeatherService(&MockWeatherRepository{}, mockLocationsRepo, &MockRocksRepository{}, weather.NewWeatherService("test_api_key"), nil)
+}
+
+func TestGetLocationSunTimes_ServesCachedDays(t *testing.T) {
+	service := newSunTimesTestService()
+	now := time.Now()
+	today := now.UTC()
+	service.storeSunTimes(2, []models.DailySunTimes{
+		{Date: today.Format("2006-01-02"), Sunrise: "cached-0"},
+		{Date: today.AddDate(0, 0, 1).Format("2006-01-02"), Sunrise: "cached-1"},
+		{Date: today.AddDate(0, 0, 2).Format("2006-01-02"), Sunrise: "cached-2"},
+	}, now)
+
+	days, err := service.GetLocationSunTimes(context.Background(), 2, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, days, 2) {
+		assert.Equal(t, "cached-0", days[0].Sunrise)
+		assert.Equal(t, "cached-1", days[1].Sunrise)
+	}
+}
+
+func TestGetLocationSunTimes_OfflineComputesLocally(t *testing.T) {
+	service := newSunTimesTestService()
+	service.SetOfflineMode(true)
+
+	days, err := service.GetLocationSunTimes(context.Background(), 2, 40)
+	assert.NoError(t, err)
+	assert.Len(t, days, maxSunTimesDays, "days is capped at the forecast horizon")
+	assert.NotEmpty(t, days[0].Sunrise)
+	assert.NotEmpty(t, days[0].Sunset)
+}
+
+func TestSunTimesFresh(t *testing.T) {
+	now := time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC)
+	today := []models.DailySunTimes{{Date: "2026-07-01"}}
+
+	assert.True(t, sunTimesFresh(sunTimesEntry{daily: today, fetchedAt: now.Add(-time.Hour)}, now))
+	assert.False(t, sunTimesFresh(sunTimesEntry{daily: today, fetchedAt: now.Add(-sunTimesMaxAge)}, now), "too old")
+	assert.False(t, sunTimesFresh(sunTimesEntry{daily: []models.DailySunTimes{{Date: "2026-06-30"}}, fetchedAt: now}, now), "starts yesterday")
+	assert.False(t, sunTimesFresh(sunTimesEntry{fetchedAt: now}, now), "empty")
+}
@@ -0,0 +1,115 @@
+// Package syncretry retries the top-level steps of the Mountain Project sync
+// commands (an area or a route) when they fail transiently, with exponential
+// backoff and jitter. The attempts and base delay come from command-line
+// flags shared by every sync command; the Kaya commands pass them to
+// kaya.Client.SetRetry instead, since that client already retries each
+// request and a second layer would multiply the attempts.
+package syncretry
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+	"github.com/alexscott64/woulder/backend/internal/kaya"
+	"github.com/alexscott64/woulder/backend/internal/retry"
+)
+
+const (
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 5 * time.Second
+
+	// maxDelay caps the backoff so a large -retry-attempts doesn't stall a
+	// run for hours on one item.
+	maxDelay = 2 * time.Minute
+
+	// jitter spreads retries ±20% so concurrent workers don't retry in
+	// lockstep.
+	jitter = 0.2
+)
+
+// transientKeywords catch transient failures whose error no longer wraps an
+// *httpx.StatusError or *url.Error, e.g. database timeouts or errors
+// formatted with %v along the way. Status codes are left to the typed
+// errors: a bare "503" also matches route and area IDs.
+var transientKeywords = []string{
+	"timeout",
+	"connection refused",
+	"connection reset",
+	"temporary failure",
+}
+
+// Options controls how Do retries. Use RegisterFlags to fill them from the
+// command line.
+type Options struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; each later wait doubles
+	// it, up to maxDelay.
+	BaseDelay time.Duration
+}
+
+// RegisterFlags adds -retry-attempts and -retry-delay to fs and returns the
+// Options they set. Call Validate after parsing.
+func RegisterFlags(fs *flag.FlagSet) *Options {
+	opts := &Options{}
+	fs.IntVar(&opts.MaxAttempts, "retry-attempts", defaultMaxAttempts, "Attempts per item (including the first) when a sync fails with a transient error")
+	fs.DurationVar(&opts.BaseDelay, "retry-delay", defaultBaseDelay, "Wait before the first retry of a transient failure; doubles with each further retry")
+	return opts
+}
+
+// Validate reports whether the options are usable.
+func (o Options) Validate() error {
+	if o.MaxAttempts < 1 {
+		return fmt.Errorf("retry attempts %d must be at least 1", o.MaxAttempts)
+	}
+	if o.BaseDelay < 0 {
+		return fmt.Errorf("retry delay %v must not be negative", o.BaseDelay)
+	}
+	return nil
+}
+
+// Do calls fn until it succeeds, fails with an error IsTransientError
+// rejects, or o.MaxAttempts attempts have been made, and returns the last
+// error. Retries are logged with what label names.
+func (o Options) Do(ctx context.Context, label string, fn func() error) error {
+	policy := retry.Policy{
+		MaxAttempts:  o.MaxAttempts,
+		InitialDelay: o.BaseDelay,
+		MaxDelay:     maxDelay,
+		Jitter:       jitter,
+		ShouldRetry:  IsTransientError,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			log.Printf("Transient error syncing %s (attempt %d/%d), retrying in %v: %v",
+				label, attempt, o.MaxAttempts, delay.Round(time.Millisecond), err)
+		},
+	}
+	return retry.Do(ctx, policy, fn)
+}
+
+// IsTransientError reports whether err is likely transient and worth
+// retrying: a rate limit, server error or network failure from the Kaya or
+// Mountain Project API, or a network or database error whose message says
+// as much. An expired
+// Kaya auth token or a cancelled context is never transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, kaya.ErrTokenExpired) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if httpx.IsTransient(err) {
+		return true
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, keyword := range transientKeywords {
+		if strings.Contains(errMsg, keyword) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package syncretry
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+	"github.com/alexscott64/woulder/backend/internal/kaya"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"server error", fmt.Errorf("failed to fetch area 1: %w", &httpx.StatusError{StatusCode: http.StatusBadGateway}), true},
+		{"rate limited", &httpx.StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"not found", &httpx.StatusError{StatusCode: http.StatusNotFound}, false},
+		{"network", &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("dial tcp: i/o error")}, true},
+		{"timeout message", errors.New("pq: canceling statement due to statement timeout"), true},
+		{"status code in an ID", errors.New("failed to fetch area 105031504: not found"), false},
+		{"expired token", fmt.Errorf("failed to fetch location: %w", &kaya.TokenExpiredError{StatusCode: http.StatusUnauthorized}), false},
+		{"cancelled", fmt.Errorf("sync stopped: %w", context.Canceled), false},
+		{"other", errors.New("location not found: Foo-1"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsDo(t *testing.T) {
+	transient := &httpx.StatusError{StatusCode: http.StatusServiceUnavailable}
+	permanent := errors.New("location not found")
+
+	tests := []struct {
+		name        string
+		maxAttempts int
+		errs        []error // returned by successive calls; nil after they run out
+		wantCalls   int
+		wantErr     error
+	}{
+		{"succeeds first time", 3, nil, 1, nil},
+		{"retries transient until success", 3, []error{transient, transient}, 3, nil},
+		{"gives up after max attempts", 3, []error{transient, transient, transient, transient}, 3, transient},
+		{"does not retry permanent errors", 3, []error{permanent}, 1, permanent},
+		{"single attempt", 1, []error{transient}, 1, transient},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{MaxAttempts: tt.maxAttempts, BaseDelay: time.Millisecond}
+			calls := 0
+			err := opts.Do(context.Background(), "test", func() error {
+				calls++
+				if calls <= len(tt.errs) {
+					return tt.errs[calls-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := RegisterFlags(fs)
+
+	if opts.MaxAttempts != defaultMaxAttempts || opts.BaseDelay != defaultBaseDelay {
+		t.Errorf("defaults = %+v, want %d attempts, %v delay", *opts, defaultMaxAttempts, defaultBaseDelay)
+	}
+
+	if err := fs.Parse([]string{"-retry-attempts", "5", "-retry-delay", "250ms"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if opts.MaxAttempts != 5 || opts.BaseDelay != 250*time.Millisecond {
+		t.Errorf("parsed = %+v, want 5 attempts, 250ms delay", *opts)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{"defaults", Options{MaxAttempts: defaultMaxAttempts, BaseDelay: defaultBaseDelay}, false},
+		{"no retries", Options{MaxAttempts: 1}, false},
+		{"zero attempts", Options{MaxAttempts: 0, BaseDelay: time.Second}, true},
+		{"negative delay", Options{MaxAttempts: 3, BaseDelay: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
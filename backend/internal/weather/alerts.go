@@ -0,0 +1,50 @@
+package weather
+
+import (
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// IsSevereAlert reports whether an alert is severe enough to downgrade
+// climbing conditions. Minor and moderate alerts (air quality, frost) are
+// surfaced but do not affect the condition level.
+func IsSevereAlert(alert models.WeatherAlert) bool {
+	return alert.Severity == "Severe" || alert.Severity == "Extreme"
+}
+
+// ActiveAlerts drops alerts that have expired by now. Alerts without an
+// expiry are kept.
+func ActiveAlerts(alerts []models.WeatherAlert, now time.Time) []models.WeatherAlert {
+	var active []models.WeatherAlert
+	for _, a := range alerts {
+		if a.Expires == nil || a.Expires.After(now) {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// FirstSevereAlert returns the first severe alert, or nil if there is none.
+func FirstSevereAlert(alerts []models.WeatherAlert) *models.WeatherAlert {
+	for i := range alerts {
+		if IsSevereAlert(alerts[i]) {
+			return &alerts[i]
+		}
+	}
+	return nil
+}
+
+// ApplyAlertsToCondition downgrades a "good" condition to "marginal" when a
+// severe alert is active and puts the alert first in the reasons. Worse
+// levels are left as they are.
+func ApplyAlertsToCondition(cond *models.ClimbingCondition, alerts []models.WeatherAlert) {
+	severe := FirstSevereAlert(alerts)
+	if cond == nil || severe == nil {
+		return
+	}
+	if cond.Level == "good" {
+		cond.Level = "marginal"
+	}
+	cond.Reasons = append([]string{"Weather alert: " + severe.Event}, cond.Reasons...)
+}
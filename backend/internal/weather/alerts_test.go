@@ -0,0 +1,87 @@
+package weather
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestActiveAlerts(t *testing.T) {
+	now := time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Hour)
+
+	alerts := []models.WeatherAlert{
+		{ID: "expired", Expires: &past},
+		{ID: "active", Expires: &future},
+		{ID: "open-ended"},
+	}
+
+	var ids []string
+	for _, a := range ActiveAlerts(alerts, now) {
+		ids = append(ids, a.ID)
+	}
+	if want := []string{"active", "open-ended"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ActiveAlerts() = %v, want %v", ids, want)
+	}
+	if got := ActiveAlerts(nil, now); got != nil {
+		t.Errorf("ActiveAlerts(nil) = %v, want nil", got)
+	}
+}
+
+func TestApplyAlertsToCondition(t *testing.T) {
+	storm := models.WeatherAlert{Event: "Severe Thunderstorm Watch", Severity: "Severe"}
+	smoke := models.WeatherAlert{Event: "Air Quality Alert", Severity: "Moderate"}
+
+	tests := []struct {
+		name        string
+		cond        models.ClimbingCondition
+		alerts      []models.WeatherAlert
+		wantLevel   string
+		wantReasons []string
+	}{
+		{
+			name:        "no alerts leaves condition unchanged",
+			cond:        models.ClimbingCondition{Level: "good", Reasons: []string{}},
+			alerts:      nil,
+			wantLevel:   "good",
+			wantReasons: []string{},
+		},
+		{
+			name:        "non-severe alert is ignored",
+			cond:        models.ClimbingCondition{Level: "good", Reasons: []string{}},
+			alerts:      []models.WeatherAlert{smoke},
+			wantLevel:   "good",
+			wantReasons: []string{},
+		},
+		{
+			name:        "severe alert downgrades good to marginal",
+			cond:        models.ClimbingCondition{Level: "good", Reasons: []string{}},
+			alerts:      []models.WeatherAlert{smoke, storm},
+			wantLevel:   "marginal",
+			wantReasons: []string{"Weather alert: Severe Thunderstorm Watch"},
+		},
+		{
+			name:        "bad stays bad with alert reason first",
+			cond:        models.ClimbingCondition{Level: "bad", Reasons: []string{"Heavy rain"}},
+			alerts:      []models.WeatherAlert{storm},
+			wantLevel:   "bad",
+			wantReasons: []string{"Weather alert: Severe Thunderstorm Watch", "Heavy rain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := tt.cond
+			ApplyAlertsToCondition(&cond, tt.alerts)
+			if cond.Level != tt.wantLevel {
+				t.Errorf("Level = %q, want %q", cond.Level, tt.wantLevel)
+			}
+			if !reflect.DeepEqual(cond.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %v, want %v", cond.Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
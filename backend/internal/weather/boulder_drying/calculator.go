@@ -21,9 +21,23 @@ type DryingForecastPeriod struct {
 	RainAmount    float64   `json:"rain_amount,omitempty"`     // Inches of rain in this period
 }
 
+// Drying estimate sources reported on BoulderDryingStatus.Source.
+const (
+	// SourceProfiled means the route has a boulder_drying_profiles row.
+	SourceProfiled = "profiled"
+	// SourceEstimated means no profile exists yet and the result is built from
+	// location-level rock type, sun exposure, and tree coverage.
+	SourceEstimated = "estimated"
+)
+
+// defaultTreeCoveragePercent is the tree coverage guess used when neither the
+// boulder profile nor the location has tree coverage data.
+const defaultTreeCoveragePercent = 30.0
+
 // BoulderDryingStatus represents the drying status for a specific boulder
 type BoulderDryingStatus struct {
 	MPRouteID             int64                         `json:"mp_route_id"`
+	Source                string                        `json:"source,omitempty"` // "profiled" or "estimated"
 	IsWet                 bool                          `json:"is_wet"`
 	IsSafe                bool                          `json:"is_safe"`
 	HoursUntilDry         float64                       `json:"hours_until_dry"`
@@ -35,10 +49,12 @@ type BoulderDryingStatus struct {
 	TreeCoveragePercent   float64                       `json:"tree_coverage_percent"`         // 0-100
 	RockType              string                        `json:"rock_type"`
 	Aspect                string                        `json:"aspect"`                            // N, NE, E, SE, S, SW, W, NW
+	AspectSource          string                        `json:"aspect_source,omitempty"`           // "computed" or "comment"; empty when aspect is a fallback
 	Latitude              float64                       `json:"latitude"`                          // Boulder GPS
 	Longitude             float64                       `json:"longitude"`                         // Boulder GPS
 	Forecast              []DryingForecastPeriod        `json:"forecast,omitempty"`                // 6-day dry/wet forecast
 	RockTemperatureStatus *models.RockTemperatureStatus `json:"rock_temperature_status,omitempty"` // boulder-level rock surface temp status (Followup 6B)
+	ShadeClock            *sun.ShadeClock               `json:"shade_clock,omitempty"`             // Today's sun/shade timeline for the face (route detail only)
 }
 
 // Calculator computes boulder-specific drying times
@@ -72,9 +88,13 @@ func (c *Calculator) CalculateBoulderDryingStatus(
 ) (*BoulderDryingStatus, error) {
 	status := &BoulderDryingStatus{
 		MPRouteID:       route.MPRouteID,
+		Source:          SourceEstimated,
 		RockType:        locationDrying.PrimaryRockType,
 		ConfidenceScore: 100, // Start at full confidence, reduce for missing data
 	}
+	if profile != nil {
+		status.Source = SourceProfiled
+	}
 
 	// Extract GPS coordinates
 	if route.Latitude != nil && route.Longitude != nil {
@@ -89,6 +109,7 @@ func (c *Calculator) CalculateBoulderDryingStatus(
 	// Extract aspect
 	if route.Aspect != nil {
 		status.Aspect = *route.Aspect
+		status.AspectSource = route.AspectSource
 	} else {
 		// Missing aspect - reduce confidence, default to South (most sun)
 		status.Aspect = "S"
@@ -105,7 +126,7 @@ func (c *Calculator) CalculateBoulderDryingStatus(
 		if locationTreeCoverage > 0 {
 			status.TreeCoveragePercent = locationTreeCoverage
 		} else {
-			status.TreeCoveragePercent = 30.0 // Default if no location data
+			status.TreeCoveragePercent = defaultTreeCoveragePercent
 			status.ConfidenceScore -= 15
 		}
 	}
@@ -173,6 +194,60 @@ func (c *Calculator) CalculateBoulderDryingStatus(
 	return status, nil
 }
 
+// EstimateBoulderDryingStatus computes a drying status for a route that has no
+// boulder drying profile yet, using location-level data in place of the
+// per-boulder inputs:
+// - primaryRock (from GetPrimaryRockType) for the rock type
+// - the location's tree coverage, or a default guess when unknown
+// - the location's dominant aspect when the route has no aspect of its own
+// Either primaryRock or sunExposure may be nil. The result is flagged
+// SourceEstimated so clients can show it as an approximation.
+func (c *Calculator) EstimateBoulderDryingStatus(
+	ctx context.Context,
+	route *models.MPRoute,
+	locationDrying *models.RockDryingStatus,
+	primaryRock *models.RockType,
+	sunExposure *models.LocationSunExposure,
+	hourlyForecast []models.WeatherData,
+) (*BoulderDryingStatus, error) {
+	locationTreeCoverage := 0.0
+	if sunExposure != nil {
+		locationTreeCoverage = sunExposure.TreeCoveragePercent
+	}
+
+	// Borrow the location's dominant aspect rather than assuming South.
+	usedLocationAspect := false
+	if route.Aspect == nil {
+		if summary := sun.SummarizeExposureProfile(sunExposure); summary != nil && summary.DominantAspect != "mixed" {
+			routeCopy := *route
+			aspect := summary.DominantAspect
+			routeCopy.Aspect = &aspect
+			routeCopy.AspectSource = ""
+			route = &routeCopy
+			usedLocationAspect = true
+		}
+	}
+
+	status, err := c.CalculateBoulderDryingStatus(ctx, route, locationDrying, nil, locationTreeCoverage, hourlyForecast)
+	if err != nil {
+		return nil, err
+	}
+
+	if usedLocationAspect {
+		// A location-wide aspect is better than a blind guess, but still not
+		// the boulder's own aspect.
+		status.ConfidenceScore -= 10
+	}
+	if primaryRock != nil && primaryRock.Name != "" {
+		status.RockType = primaryRock.Name
+	}
+	if status.ConfidenceScore < 0 {
+		status.ConfidenceScore = 0
+	}
+	status.Source = SourceEstimated
+	return status, nil
+}
+
 // calculateSunExposure computes hours of direct sun hitting the boulder over next 6 days
 // Always calculates fresh since sun exposure is time-dependent (next 6 days from NOW)
 // This is fast because it uses offline astronomical calculations (no API calls)
@@ -205,6 +205,10 @@ func TestCalculateBoulderDryingStatus(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 
+	if status.Source != SourceProfiled {
+		t.Errorf("Expected source %q, got %q", SourceProfiled, status.Source)
+	}
+
 	if status.MPRouteID != 123456 {
 		t.Errorf("Expected route ID 123456, got %d", status.MPRouteID)
 	}
@@ -236,6 +240,56 @@ func TestCalculateBoulderDryingStatus(t *testing.T) {
 	}
 }
 
+func TestEstimateBoulderDryingStatus(t *testing.T) {
+	calc := NewCalculator("test-api-key")
+	ctx := context.Background()
+
+	route := &models.MPRoute{
+		MPRouteID: 654321,
+		Latitude:  func() *float64 { v := 47.8172; return &v }(),
+		Longitude: func() *float64 { v := -121.6019; return &v }(),
+	}
+	locationDrying := &models.RockDryingStatus{IsWet: true, HoursUntilDry: 24.0, PrimaryRockType: "granite"}
+	primaryRock := &models.RockType{Name: "Gneiss"}
+	sunExposure := &models.LocationSunExposure{SouthFacingPercent: 80, WestFacingPercent: 20, TreeCoveragePercent: 60}
+
+	status, err := calc.EstimateBoulderDryingStatus(ctx, route, locationDrying, primaryRock, sunExposure, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if status.Source != SourceEstimated {
+		t.Errorf("Source = %q, want %q", status.Source, SourceEstimated)
+	}
+	if status.RockType != "Gneiss" {
+		t.Errorf("RockType = %q, want primary rock type Gneiss", status.RockType)
+	}
+	if status.TreeCoveragePercent != 60 {
+		t.Errorf("TreeCoveragePercent = %.1f, want location value 60", status.TreeCoveragePercent)
+	}
+	if status.Aspect != "S" {
+		t.Errorf("Aspect = %q, want location dominant aspect S", status.Aspect)
+	}
+	if route.Aspect != nil {
+		t.Error("EstimateBoulderDryingStatus must not mutate the route")
+	}
+
+	// Nothing known about the location: default tree cover, still estimated.
+	status, err = calc.EstimateBoulderDryingStatus(ctx, route, locationDrying, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if status.TreeCoveragePercent != defaultTreeCoveragePercent {
+		t.Errorf("TreeCoveragePercent = %.1f, want default %.1f", status.TreeCoveragePercent, defaultTreeCoveragePercent)
+	}
+	if status.RockType != "granite" {
+		t.Errorf("RockType = %q, want location drying rock type granite", status.RockType)
+	}
+	if status.Source != SourceEstimated {
+		t.Errorf("Source = %q, want %q", status.Source, SourceEstimated)
+	}
+}
+
 func TestCalculate6DayForecast_CurrentlyDry_StaysDry(t *testing.T) {
 	calc := NewCalculator("test-api-key")
 	now := time.Now()
@@ -2,11 +2,11 @@ package boulder_drying
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 )
 
 // SunPositionClient fetches sun position data from IP Geolocation Astronomy API
@@ -95,28 +95,10 @@ func (c *SunPositionClient) getSunDataForDay(
 		date.Format("2006-01-02"),
 	)
 
-	// Make HTTP request
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	apiResp, err := httpx.GetJSON[AstronomyAPIResponse](ctx, c.httpClient, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch sun data: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	// Parse response
-	var apiResp AstronomyAPIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
 
 	// Generate hourly sun position data
 	return c.generateHourlySunPositions(date, apiResp)
@@ -2,18 +2,51 @@ package boulder_drying
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	earthengine "github.com/alexscott64/go-earthengine"
 	"github.com/alexscott64/go-earthengine/helpers"
 )
 
+var (
+	// ErrTreeCoverDisabled is returned by FetchTreeCoverage when Earth
+	// Engine credentials are missing or the client failed to initialize.
+	ErrTreeCoverDisabled = errors.New("google earth engine is not enabled")
+
+	// ErrTreeCoverCircuitOpen is returned by FetchTreeCoverage while the
+	// circuit breaker is open after repeated Earth Engine failures.
+	ErrTreeCoverCircuitOpen = errors.New("google earth engine circuit breaker is open")
+)
+
+const (
+	// treeCoverBreakerThreshold is how many consecutive Earth Engine
+	// failures open the circuit breaker.
+	treeCoverBreakerThreshold = 5
+
+	// treeCoverBreakerCooldown is how long the breaker stays open. The first
+	// query after it lets one request through; another failure reopens it
+	// straight away, a success closes it.
+	treeCoverBreakerCooldown = 10 * time.Minute
+)
+
 // TreeCoverClient fetches tree canopy coverage data from Google Earth Engine
 type TreeCoverClient struct {
 	geeClient *earthengine.Client
 	enabled   bool
+
+	// query fetches coverage from Earth Engine. It's a field so tests can
+	// stand in for the API.
+	query func(lat, lon float64) (float64, error)
+	now   func() time.Time
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	openUntil           time.Time
 }
 
 // IsEnabled returns whether the Google Earth Engine API is enabled
@@ -56,7 +89,67 @@ func NewTreeCoverClient() *TreeCoverClient {
 	return &TreeCoverClient{
 		geeClient: client,
 		enabled:   true,
+		query: func(lat, lon float64) (float64, error) {
+			return helpers.TreeCoverage(client, lat, lon)
+		},
+	}
+}
+
+// FetchTreeCoverage returns tree canopy coverage percentage (0-100) for a GPS
+// coordinate from Earth Engine. Unlike GetTreeCoverage it never falls back to
+// an estimate: it returns ErrTreeCoverDisabled without Earth Engine,
+// ErrTreeCoverCircuitOpen while the breaker is open, or the query error.
+func (c *TreeCoverClient) FetchTreeCoverage(ctx context.Context, lat, lon float64) (float64, error) {
+	if !c.enabled {
+		return 0, ErrTreeCoverDisabled
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if c.CircuitOpen() {
+		return 0, ErrTreeCoverCircuitOpen
+	}
+
+	coverage, err := c.query(lat, lon)
+	c.recordResult(err)
+	if err != nil {
+		return 0, err
+	}
+	return coverage, nil
+}
+
+// CircuitOpen reports whether Earth Engine queries are currently being
+// skipped after repeated failures.
+func (c *TreeCoverClient) CircuitOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.clock().Before(c.openUntil)
+}
+
+// recordResult updates the circuit breaker with the outcome of a query.
+func (c *TreeCoverClient) recordResult(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		c.consecutiveFailures = 0
+		c.openUntil = time.Time{}
+		return
+	}
+
+	c.consecutiveFailures++
+	if c.consecutiveFailures >= treeCoverBreakerThreshold {
+		c.openUntil = c.clock().Add(treeCoverBreakerCooldown)
+		log.Printf("Warning: Earth Engine failed %d times in a row, pausing queries for %v: %v",
+			c.consecutiveFailures, treeCoverBreakerCooldown, err)
+	}
+}
+
+func (c *TreeCoverClient) clock() time.Time {
+	if c.now != nil {
+		return c.now()
 	}
+	return time.Now()
 }
 
 // GetTreeCoverageWithDefault returns tree canopy coverage percentage for a GPS coordinate
@@ -79,7 +172,7 @@ func (c *TreeCoverClient) GetTreeCoverageWithDefault(ctx context.Context, lat, l
 
 	// Try to fetch from Earth Engine using the go-earthengine library
 	// This will use NLCD 2023 for USA locations (most accurate)
-	coverage, err := helpers.TreeCoverage(c.geeClient, lat, lon)
+	coverage, err := c.FetchTreeCoverage(ctx, lat, lon)
 	if err != nil {
 		log.Printf("Warning: Earth Engine query failed, using fallback: %v", err)
 		// Fallback to location tree coverage first, then GPS estimates
@@ -2,8 +2,10 @@ package boulder_drying
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestTreeCoverClient_GetTreeCoverage_GPS(t *testing.T) {
@@ -249,3 +251,75 @@ func TestTreeCoverClient_BoundaryConditions(t *testing.T) {
 		t.Errorf("East of -120.8 should be Town Walls (25%%), got %v", coverage2)
 	}
 }
+
+func TestTreeCoverClient_FetchTreeCoverage_Disabled(t *testing.T) {
+	client := &TreeCoverClient{enabled: false}
+
+	if _, err := client.FetchTreeCoverage(context.Background(), 47.6, -120.9); !errors.Is(err, ErrTreeCoverDisabled) {
+		t.Errorf("FetchTreeCoverage() error = %v, want ErrTreeCoverDisabled", err)
+	}
+}
+
+func TestTreeCoverClient_CircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	queryErr := errors.New("earth engine unavailable")
+	var failing bool
+	calls := 0
+
+	client := &TreeCoverClient{
+		enabled: true,
+		now:     func() time.Time { return now },
+		query: func(lat, lon float64) (float64, error) {
+			calls++
+			if failing {
+				return 0, queryErr
+			}
+			return 42.0, nil
+		},
+	}
+	ctx := context.Background()
+
+	failing = true
+	for i := 0; i < treeCoverBreakerThreshold; i++ {
+		if _, err := client.FetchTreeCoverage(ctx, 47.6, -120.9); !errors.Is(err, queryErr) {
+			t.Fatalf("failure %d: error = %v, want query error", i+1, err)
+		}
+	}
+	if !client.CircuitOpen() {
+		t.Fatalf("CircuitOpen() = false after %d failures, want true", treeCoverBreakerThreshold)
+	}
+
+	// While open, queries are skipped entirely.
+	if _, err := client.FetchTreeCoverage(ctx, 47.6, -120.9); !errors.Is(err, ErrTreeCoverCircuitOpen) {
+		t.Errorf("FetchTreeCoverage() while open error = %v, want ErrTreeCoverCircuitOpen", err)
+	}
+	if calls != treeCoverBreakerThreshold {
+		t.Errorf("query called %d times, want %d", calls, treeCoverBreakerThreshold)
+	}
+
+	// The fallback path skips Earth Engine but still returns an estimate.
+	coverage, err := client.GetTreeCoverageWithDefault(ctx, 47.6, -120.9, 33.0)
+	if err != nil || coverage != 33.0 {
+		t.Errorf("GetTreeCoverageWithDefault() while open = %v, %v, want 33, nil", coverage, err)
+	}
+
+	// After the cooldown one failure reopens it straight away.
+	now = now.Add(treeCoverBreakerCooldown)
+	if _, err := client.FetchTreeCoverage(ctx, 47.6, -120.9); !errors.Is(err, queryErr) {
+		t.Fatalf("FetchTreeCoverage() after cooldown error = %v, want query error", err)
+	}
+	if !client.CircuitOpen() {
+		t.Error("CircuitOpen() = false after failed trial query, want true")
+	}
+
+	// A success after the next cooldown closes it.
+	now = now.Add(treeCoverBreakerCooldown)
+	failing = false
+	coverage, err = client.FetchTreeCoverage(ctx, 47.6, -120.9)
+	if err != nil || coverage != 42.0 {
+		t.Fatalf("FetchTreeCoverage() after recovery = %v, %v, want 42, nil", coverage, err)
+	}
+	if client.CircuitOpen() {
+		t.Error("CircuitOpen() = true after success, want false")
+	}
+}
@@ -44,3 +44,51 @@ func HasPersistentPrecipitation(recentWeather []models.WeatherData, threshold fl
 
 	return periodsWithPrecip >= 2 // 2+ periods = persistent
 }
+
+// DetectRainEvents groups ordered hourly weather data into discrete rain
+// events. A reading is wet when its precipitation is at least threshold
+// (inches). Up to maxGapHours consecutive dry readings may fall inside an
+// event before it is closed, so a brief lull does not split one storm into
+// several events. Events are returned oldest first.
+func DetectRainEvents(weatherData []models.WeatherData, threshold float64, maxGapHours int) []models.RainEvent {
+	var events []models.RainEvent
+	var current *models.RainEvent
+	var wetHours, dryRun int
+
+	closeEvent := func() {
+		current.Duration = current.EndTime.Sub(current.StartTime).Hours()
+		current.AvgHourlyRate = current.TotalRain / float64(wetHours)
+		events = append(events, *current)
+		current = nil
+	}
+
+	for _, data := range weatherData {
+		if data.Precipitation < threshold {
+			if current != nil {
+				dryRun++
+				if dryRun > maxGapHours {
+					closeEvent()
+				}
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &models.RainEvent{StartTime: data.Timestamp}
+			wetHours = 0
+		}
+		current.EndTime = data.Timestamp
+		current.TotalRain += data.Precipitation
+		if data.Precipitation > current.MaxHourlyRate {
+			current.MaxHourlyRate = data.Precipitation
+		}
+		wetHours++
+		dryRun = 0
+	}
+
+	if current != nil {
+		closeEvent()
+	}
+
+	return events
+}
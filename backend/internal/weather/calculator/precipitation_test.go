@@ -0,0 +1,128 @@
+package calculator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// hourlySeries builds hourly weather data starting at start with the given
+// precipitation values (inches).
+func hourlySeries(start time.Time, precip ...float64) []models.WeatherData {
+	data := make([]models.WeatherData, len(precip))
+	for i, p := range precip {
+		data[i] = models.WeatherData{Timestamp: start.Add(time.Duration(i) * time.Hour), Precipitation: p}
+	}
+	return data
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestDetectRainEvents_NoRain(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	if events := DetectRainEvents(nil, 0.03, 1); len(events) != 0 {
+		t.Errorf("expected no events for empty data, got %d", len(events))
+	}
+
+	// Trace values below the threshold are not rain.
+	data := hourlySeries(start, 0, 0.01, 0.02, 0, 0.01)
+	if events := DetectRainEvents(data, 0.03, 1); len(events) != 0 {
+		t.Errorf("expected no events for trace precipitation, got %d", len(events))
+	}
+}
+
+func TestDetectRainEvents_SingleEvent(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := hourlySeries(start, 0, 0.05, 0.20, 0.10, 0, 0)
+
+	events := DetectRainEvents(data, 0.03, 0)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+
+	e := events[0]
+	if !e.StartTime.Equal(start.Add(1 * time.Hour)) {
+		t.Errorf("StartTime = %v, want %v", e.StartTime, start.Add(1*time.Hour))
+	}
+	if !e.EndTime.Equal(start.Add(3 * time.Hour)) {
+		t.Errorf("EndTime = %v, want %v", e.EndTime, start.Add(3*time.Hour))
+	}
+	if !approxEqual(e.TotalRain, 0.35) {
+		t.Errorf("TotalRain = %v, want 0.35", e.TotalRain)
+	}
+	if e.Duration != 2 {
+		t.Errorf("Duration = %v, want 2", e.Duration)
+	}
+	if e.MaxHourlyRate != 0.20 {
+		t.Errorf("MaxHourlyRate = %v, want 0.20", e.MaxHourlyRate)
+	}
+	if !approxEqual(e.AvgHourlyRate, 0.35/3) {
+		t.Errorf("AvgHourlyRate = %v, want %v", e.AvgHourlyRate, 0.35/3)
+	}
+}
+
+func TestDetectRainEvents_GapTolerance(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	// Two wet spells separated by a single dry hour, then a long dry
+	// stretch before a third spell.
+	data := hourlySeries(start, 0.10, 0.10, 0, 0.10, 0, 0, 0, 0.05)
+
+	tests := []struct {
+		name        string
+		maxGapHours int
+		wantTotals  []float64
+	}{
+		{"no gap tolerance splits on any dry hour", 0, []float64{0.20, 0.10, 0.05}},
+		{"one hour gap merges the lull", 1, []float64{0.30, 0.05}},
+		{"three hour gap merges everything", 3, []float64{0.35}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := DetectRainEvents(data, 0.03, tt.maxGapHours)
+			if len(events) != len(tt.wantTotals) {
+				t.Fatalf("expected %d events, got %d", len(tt.wantTotals), len(events))
+			}
+			for i, want := range tt.wantTotals {
+				if !approxEqual(events[i].TotalRain, want) {
+					t.Errorf("event %d TotalRain = %v, want %v", i, events[i].TotalRain, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDetectRainEvents_GapHoursExcludedFromAverage(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := hourlySeries(start, 0.10, 0, 0.20)
+
+	events := DetectRainEvents(data, 0.03, 1)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if events[0].Duration != 2 {
+		t.Errorf("Duration = %v, want 2", events[0].Duration)
+	}
+	// Average is over the two wet hours, not the dry hour in between.
+	if !approxEqual(events[0].AvgHourlyRate, 0.15) {
+		t.Errorf("AvgHourlyRate = %v, want 0.15", events[0].AvgHourlyRate)
+	}
+}
+
+func TestDetectRainEvents_TrailingGapDoesNotExtendEvent(t *testing.T) {
+	start := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	data := hourlySeries(start, 0.10, 0.10, 0, 0)
+
+	events := DetectRainEvents(data, 0.03, 3)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if !events[0].EndTime.Equal(start.Add(1 * time.Hour)) {
+		t.Errorf("EndTime = %v, want last wet hour %v", events[0].EndTime, start.Add(1*time.Hour))
+	}
+}
@@ -59,7 +59,7 @@ func CalculateSnowAccumulation(historicalData, futureData []models.WeatherData,
 		humidity := float64(hour.Humidity)
 
 		// --- Freezing Level Transition (30-34°F mix zone) ---
-		snowFraction := getSnowFraction(temp)
+		snowFraction := hourSnowFraction(hour)
 
 		if precip > 0 {
 			if snowFraction > 0 {
@@ -187,6 +187,22 @@ func getSnowFraction(temp float64) float64 {
 	return (34 - temp) / 4
 }
 
+// hourSnowFraction is the share of an hour's precipitation falling as snow.
+// The provider's precipitation type wins when recorded; otherwise, and for
+// mixed hours, temperature decides, with mixed kept between 20% and 80% snow.
+func hourSnowFraction(hour models.WeatherData) float64 {
+	switch hour.PrecipType {
+	case models.PrecipTypeSnow:
+		return 1.0
+	case models.PrecipTypeRain:
+		return 0.0
+	case models.PrecipTypeMixed:
+		return min(0.8, max(0.2, getSnowFraction(hour.Temperature)))
+	default:
+		return getSnowFraction(hour.Temperature)
+	}
+}
+
 // getNewSnowDensity estimates density of new snow based on temperature
 func getNewSnowDensity(temp float64) float64 {
 	if temp <= 20 {
@@ -213,3 +213,31 @@ func TestSnowAccumulation_NoSnowScenario(t *testing.T) {
 
 	t.Logf("No snow scenario: %.2f inches (expected ~0)", snowDepth)
 }
+
+// TestSnowAccumulation_PrecipTypeOverridesTemperature tests that a recorded
+// precipitation type decides snow vs rain over the temperature band
+func TestSnowAccumulation_PrecipTypeOverridesTemperature(t *testing.T) {
+	baseTime := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	elevationFt := 1000.0
+
+	depthFor := func(precipType string) float64 {
+		historical := []models.WeatherData{
+			{Timestamp: baseTime, Temperature: 35, Precipitation: 0.5, WindSpeed: 5, Humidity: 90, CloudCover: 100, PrecipType: precipType},
+		}
+		currentData := []models.WeatherData{
+			{Timestamp: baseTime.Add(1 * time.Hour), Temperature: 28, Precipitation: 0, WindSpeed: 5, Humidity: 80, CloudCover: 100},
+		}
+		return GetCurrentSnowDepth(historical, currentData, elevationFt)
+	}
+
+	// 35°F reads as all rain by temperature alone
+	if depth := depthFor(""); depth > 0.1 {
+		t.Errorf("Expected no snow without a precip type at 35°F, got %.2f inches", depth)
+	}
+	if depth := depthFor(models.PrecipTypeSnow); depth < 1 {
+		t.Errorf("Expected snow recorded as snow to accumulate at 35°F, got %.2f inches", depth)
+	}
+	if mixed, snow := depthFor(models.PrecipTypeMixed), depthFor(models.PrecipTypeSnow); mixed <= 0 || mixed >= snow {
+		t.Errorf("Expected mixed to accumulate some but less than snow, got mixed=%.2f snow=%.2f", mixed, snow)
+	}
+}
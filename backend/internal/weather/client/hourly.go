@@ -0,0 +1,127 @@
+package client
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// hourlyReader converts hours of an Open-Meteo hourly response to
+// WeatherData, tolerating arrays shorter than hourly.time.
+//
+// Temperature and precipitation are required: every downstream calculation
+// (drying, precipitation totals, conditions) is built on them, so an hour
+// missing either is dropped rather than invented. Any other field past the
+// end of its array is filled and the hour marked Partial: scalar readings
+// carry the previous hour's value forward, while radiation is filled with 0
+// because carrying daytime sun into the night would fake drying.
+type hourlyReader struct {
+	data *openMeteoResponse
+	prev models.WeatherData
+	// prevWeatherCode is the last emitted hour's code, carried forward for
+	// descriptions and icons when weather_code is short.
+	prevWeatherCode int
+
+	short   map[string]int // variable name -> array length, for arrays shorter than hourly.time
+	filled  int            // hours emitted with at least one filled field
+	dropped int            // hours dropped for missing temperature or precipitation
+}
+
+func newHourlyReader(data *openMeteoResponse) *hourlyReader {
+	h := &data.Hourly
+	n := len(h.Time)
+	short := make(map[string]int)
+	for name, length := range map[string]int{
+		"temperature_2m":       len(h.Temperature2m),
+		"precipitation":        len(h.Precipitation),
+		"apparent_temperature": len(h.ApparentTemperature),
+		"relative_humidity_2m": len(h.RelativeHumidity2m),
+		"wind_speed_10m":       len(h.WindSpeed10m),
+		"wind_direction_10m":   len(h.WindDirection10m),
+		"cloud_cover":          len(h.CloudCover),
+		"surface_pressure":     len(h.Pressure),
+		"weather_code":         len(h.WeatherCode),
+		"shortwave_radiation":  len(h.ShortwaveRadiation),
+		"direct_radiation":     len(h.DirectRadiation),
+		"diffuse_radiation":    len(h.DiffuseRadiation),
+		"dew_point_2m":         len(h.Dewpoint2m),
+	} {
+		if length < n {
+			short[name] = length
+		}
+	}
+	return &hourlyReader{data: data, short: short}
+}
+
+// hour returns the weather for hour i at timestamp, with its icon chosen by
+// icon from the hour's weather code. ok is false when the hour lacks
+// temperature or precipitation.
+func (r *hourlyReader) hour(i int, timestamp time.Time, icon func(code int) string) (weather models.WeatherData, ok bool) {
+	h := &r.data.Hourly
+	if i >= len(h.Temperature2m) || i >= len(h.Precipitation) {
+		r.dropped++
+		return models.WeatherData{}, false
+	}
+
+	partial := false
+	code := fillHourly(h.WeatherCode, i, r.prevWeatherCode, &partial)
+	weather = models.WeatherData{
+		Timestamp:          timestamp,
+		Temperature:        h.Temperature2m[i],
+		FeelsLike:          fillHourly(h.ApparentTemperature, i, r.prev.FeelsLike, &partial),
+		Precipitation:      h.Precipitation[i],
+		Humidity:           fillHourly(h.RelativeHumidity2m, i, r.prev.Humidity, &partial),
+		WindSpeed:          fillHourly(h.WindSpeed10m, i, r.prev.WindSpeed, &partial),
+		WindDirection:      fillHourly(h.WindDirection10m, i, r.prev.WindDirection, &partial),
+		CloudCover:         fillHourly(h.CloudCover, i, r.prev.CloudCover, &partial),
+		Pressure:           int(fillHourly(h.Pressure, i, float64(r.prev.Pressure), &partial)),
+		Description:        getWeatherDescription(code),
+		Icon:               icon(code),
+		ShortwaveRadiation: fillHourly(h.ShortwaveRadiation, i, 0, &partial),
+		DirectRadiation:    fillHourly(h.DirectRadiation, i, 0, &partial),
+		DiffuseRadiation:   fillHourly(h.DiffuseRadiation, i, 0, &partial),
+		DewpointF:          fillHourly(h.Dewpoint2m, i, r.prev.DewpointF, &partial),
+		PrecipType:         hourlyPrecipType(r.data, i, h.Temperature2m[i]),
+		Partial:            partial,
+	}
+	if partial {
+		r.filled++
+	}
+	r.prev = weather
+	r.prevWeatherCode = code
+	return weather, true
+}
+
+// fillHourly returns values[i], or fill with *partial set when values is too
+// short.
+func fillHourly[T any](values []T, i int, fill T, partial *bool) T {
+	if i < len(values) {
+		return values[i]
+	}
+	*partial = true
+	return fill
+}
+
+// logShortArrays logs one warning summarizing short arrays, if there were
+// any. what names the request, e.g. "forecast for (47.0000, -121.0000)".
+func (r *hourlyReader) logShortArrays(what string) {
+	if len(r.short) == 0 {
+		return
+	}
+	log.Printf("Warning: Open-Meteo %s returned short hourly arrays (%s of %d hours): filled %d hours, dropped %d",
+		what, r.shortSummary(), len(r.data.Hourly.Time), r.filled, r.dropped)
+}
+
+// shortSummary lists short arrays as "name=length", sorted by name.
+func (r *hourlyReader) shortSummary() string {
+	fields := make([]string, 0, len(r.short))
+	for name, length := range r.short {
+		fields = append(fields, fmt.Sprintf("%s=%d", name, length))
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, ", ")
+}
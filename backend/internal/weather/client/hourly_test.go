@@ -0,0 +1,96 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+// shortHourlyResponse builds n hours of hourly data where surface_pressure
+// and shortwave_radiation stop after 2 hours and temperature after n-1.
+func shortHourlyResponse(n int) *openMeteoResponse {
+	data := &openMeteoResponse{}
+	h := &data.Hourly
+	for i := 0; i < n; i++ {
+		h.Time = append(h.Time, time.Date(2026, 7, 1, i, 0, 0, 0, time.UTC).Format("2006-01-02T15:04"))
+		h.Precipitation = append(h.Precipitation, 0)
+		h.ApparentTemperature = append(h.ApparentTemperature, 48)
+		h.RelativeHumidity2m = append(h.RelativeHumidity2m, 60)
+		h.WindSpeed10m = append(h.WindSpeed10m, 5)
+		h.WindDirection10m = append(h.WindDirection10m, 180)
+		h.CloudCover = append(h.CloudCover, 20)
+		h.WeatherCode = append(h.WeatherCode, 1)
+		h.DirectRadiation = append(h.DirectRadiation, 200)
+		h.DiffuseRadiation = append(h.DiffuseRadiation, 100)
+		h.Dewpoint2m = append(h.Dewpoint2m, 40)
+		if i < n-1 {
+			h.Temperature2m = append(h.Temperature2m, 50+float64(i))
+		}
+		if i < 2 {
+			h.Pressure = append(h.Pressure, 1010+float64(i))
+			h.ShortwaveRadiation = append(h.ShortwaveRadiation, 300)
+		}
+	}
+	return data
+}
+
+func TestHourlyReader_FillsShortArrays(t *testing.T) {
+	const n = 5
+	data := shortHourlyResponse(n)
+	r := newHourlyReader(data)
+
+	var got []int
+	for i := range data.Hourly.Time {
+		ts, _ := parseTimestampUTC(data.Hourly.Time[i])
+		w, ok := r.hour(i, ts, getWeatherIcon)
+		if !ok {
+			continue
+		}
+		got = append(got, i)
+
+		wantPartial := i >= 2
+		if w.Partial != wantPartial {
+			t.Errorf("hour %d partial = %v, want %v", i, w.Partial, wantPartial)
+		}
+		if i >= 2 {
+			// Pressure carries forward; radiation is not invented.
+			if w.Pressure != 1011 {
+				t.Errorf("hour %d pressure = %d, want 1011 carried forward", i, w.Pressure)
+			}
+			if w.ShortwaveRadiation != 0 {
+				t.Errorf("hour %d shortwave radiation = %v, want 0", i, w.ShortwaveRadiation)
+			}
+		}
+		if w.Temperature != 50+float64(i) || w.DirectRadiation != 200 || w.Humidity != 60 {
+			t.Errorf("hour %d = %+v, want its own complete values", i, w)
+		}
+	}
+
+	// The last hour has no temperature and is dropped; the rest survive.
+	if len(got) != n-1 {
+		t.Errorf("emitted hours %v, want 0-%d", got, n-2)
+	}
+	if r.filled != 2 || r.dropped != 1 {
+		t.Errorf("filled/dropped = %d/%d, want 2/1", r.filled, r.dropped)
+	}
+	want := "shortwave_radiation=2, surface_pressure=2, temperature_2m=4"
+	if s := r.shortSummary(); s != want {
+		t.Errorf("shortSummary() = %q, want %q", s, want)
+	}
+}
+
+func TestHourlyReader_CompleteResponseIsNotPartial(t *testing.T) {
+	data := shortHourlyResponse(3)
+	data.Hourly.Temperature2m = append(data.Hourly.Temperature2m, 52)
+	data.Hourly.Pressure = append(data.Hourly.Pressure, 1012)
+	data.Hourly.ShortwaveRadiation = append(data.Hourly.ShortwaveRadiation, 300)
+
+	r := newHourlyReader(data)
+	for i := range data.Hourly.Time {
+		if w, ok := r.hour(i, time.Time{}, getWeatherIcon); !ok || w.Partial {
+			t.Errorf("hour %d ok/partial = %v/%v, want true/false", i, ok, w.Partial)
+		}
+	}
+	if len(r.short) != 0 {
+		t.Errorf("short = %v, want none", r.short)
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/httpx"
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// nwsAlertsURL is a var (not a const) so tests can point it at an httptest
+// server, mirroring openMeteoForecastURL.
+var nwsAlertsURL = "https://api.weather.gov/alerts/active"
+
+// NWSClient fetches active weather alerts from the US National Weather
+// Service. The API is free but only covers US points.
+type NWSClient struct {
+	userAgent  string
+	httpClient *http.Client
+}
+
+// nwsAlertsResponse is the GeoJSON feature collection returned by
+// /alerts/active.
+type nwsAlertsResponse struct {
+	Features []struct {
+		Properties struct {
+			ID          string  `json:"id"`
+			Event       string  `json:"event"`
+			Severity    string  `json:"severity"`
+			Urgency     string  `json:"urgency"`
+			Headline    string  `json:"headline"`
+			Description string  `json:"description"`
+			Onset       *string `json:"onset"`
+			Expires     *string `json:"expires"`
+			Ends        *string `json:"ends"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+// NewNWSClient creates a new NWS alerts client. The NWS requires a
+// User-Agent identifying the application and a contact.
+func NewNWSClient(userAgent string) *NWSClient {
+	return &NWSClient{
+		userAgent: userAgent,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// GetActiveAlerts fetches alerts currently in effect at a point. It returns
+// an empty slice, not an error, when no alerts are active or the point is
+// outside NWS coverage.
+func (c *NWSClient) GetActiveAlerts(lat, lon float64) ([]models.WeatherAlert, error) {
+	url := fmt.Sprintf("%s?point=%.4f,%.4f", nwsAlertsURL, lat, lon)
+
+	header := http.Header{}
+	header.Set("User-Agent", c.userAgent)
+	header.Set("Accept", "application/geo+json")
+
+	data, err := httpx.GetJSON[nwsAlertsResponse](context.Background(), c.httpClient, url, header)
+	if err != nil {
+		// Points outside the US are rejected as invalid rather than
+		// returning an empty collection.
+		if httpx.HasStatus(err, http.StatusBadRequest, http.StatusNotFound) {
+			return []models.WeatherAlert{}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch alerts: %w", err)
+	}
+
+	alerts := make([]models.WeatherAlert, 0, len(data.Features))
+	for _, f := range data.Features {
+		p := f.Properties
+		alert := models.WeatherAlert{
+			ID:          p.ID,
+			Event:       p.Event,
+			Severity:    p.Severity,
+			Urgency:     p.Urgency,
+			Headline:    p.Headline,
+			Description: p.Description,
+			Onset:       parseNWSTime(p.Onset),
+			Source:      "nws",
+		}
+		// "ends" is when the hazard is over; "expires" only bounds this
+		// version of the message. Prefer the former when present.
+		alert.Expires = parseNWSTime(p.Ends)
+		if alert.Expires == nil {
+			alert.Expires = parseNWSTime(p.Expires)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func parseNWSTime(s *string) *time.Time {
+	if s == nil || *s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *s)
+	if err != nil {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func withNWSServer(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	original := nwsAlertsURL
+	nwsAlertsURL = server.URL
+	t.Cleanup(func() { nwsAlertsURL = original })
+}
+
+func TestNWSClient_GetActiveAlerts(t *testing.T) {
+	withNWSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("point"); got != "47.8083,-121.5766" {
+			t.Errorf("point = %q", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "woulder-test" {
+			t.Errorf("User-Agent = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Write([]byte(`{"features": [
+			{"properties": {
+				"id": "urn:oid:1", "event": "Severe Thunderstorm Watch",
+				"severity": "Severe", "urgency": "Expected",
+				"headline": "Severe Thunderstorm Watch until 9PM",
+				"onset": "2026-07-01T13:00:00-07:00",
+				"expires": "2026-07-01T18:00:00-07:00",
+				"ends": "2026-07-01T21:00:00-07:00"
+			}},
+			{"properties": {
+				"id": "urn:oid:2", "event": "Air Quality Alert",
+				"severity": "Moderate", "urgency": "Unknown",
+				"expires": "2026-07-02T12:00:00-07:00",
+				"ends": null
+			}}
+		]}`))
+	})
+
+	alerts, err := NewNWSClient("woulder-test").GetActiveAlerts(47.80834, -121.57659)
+	if err != nil {
+		t.Fatalf("GetActiveAlerts() error = %v", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(alerts))
+	}
+
+	storm := alerts[0]
+	if storm.Event != "Severe Thunderstorm Watch" || storm.Severity != "Severe" || storm.Source != "nws" {
+		t.Errorf("unexpected alert: %+v", storm)
+	}
+	wantEnds := time.Date(2026, 7, 2, 4, 0, 0, 0, time.UTC)
+	if storm.Expires == nil || !storm.Expires.Equal(wantEnds) {
+		t.Errorf("Expires = %v, want %v (from ends)", storm.Expires, wantEnds)
+	}
+	if storm.Onset == nil {
+		t.Error("Onset not parsed")
+	}
+
+	wantExpires := time.Date(2026, 7, 2, 19, 0, 0, 0, time.UTC)
+	if aq := alerts[1]; aq.Expires == nil || !aq.Expires.Equal(wantExpires) {
+		t.Errorf("Expires = %v, want %v (fallback to expires)", aq.Expires, wantExpires)
+	}
+}
+
+func TestNWSClient_NoAlerts(t *testing.T) {
+	withNWSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type": "FeatureCollection", "features": []}`))
+	})
+
+	alerts, err := NewNWSClient("woulder-test").GetActiveAlerts(47.8, -121.5)
+	if err != nil {
+		t.Fatalf("GetActiveAlerts() error = %v", err)
+	}
+	if alerts == nil || len(alerts) != 0 {
+		t.Errorf("got %v, want empty non-nil slice", alerts)
+	}
+}
+
+func TestNWSClient_OutsideCoverage(t *testing.T) {
+	withNWSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"title": "Bad Request"}`, http.StatusBadRequest)
+	})
+
+	alerts, err := NewNWSClient("woulder-test").GetActiveAlerts(49.7, -123.15)
+	if err != nil {
+		t.Fatalf("GetActiveAlerts() error = %v", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("got %d alerts, want 0", len(alerts))
+	}
+}
+
+func TestNWSClient_ServerError(t *testing.T) {
+	withNWSServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	if _, err := NewNWSClient("woulder-test").GetActiveAlerts(47.8, -121.5); err == nil {
+		t.Error("expected error for 503 response")
+	}
+}
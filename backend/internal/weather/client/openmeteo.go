@@ -1,14 +1,16 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/retry"
 )
 
 // openMeteoForecastURL / openMeteoHistoricalURL are vars (not consts) so tests
@@ -16,22 +18,57 @@ import (
 // are not modified at runtime in production code paths.
 var (
 	openMeteoForecastURL   = "https://api.open-meteo.com/v1/forecast"
-	openMeteoHistoricalURL = "https://api.open-meteo.com/v1/archive"
+	openMeteoHistoricalURL = "https://archive-api.open-meteo.com/v1/archive"
 )
 
 const (
 	maxRetries        = 3
 	initialRetryDelay = 1 * time.Second
 
-	// expectedMinForecastHours is the lower bound for `hourly.time` length on
-	// the GetCurrentAndForecast endpoint (which requests
-	// forecast_days=16&past_hours=12 ≈ 396 hours). We tolerate ~60 hours of
-	// upstream slack and only reject responses with fewer than 14 days × 24h
-	// of data — this matches the service-layer threshold and addresses the
-	// observed bug where Open-Meteo intermittently returned 69-359 hours.
-	expectedMinForecastHours = 14 * 24 // 336 hours
+	// maxForecastPastDays is the furthest back the forecast endpoint's
+	// past_days reaches. Older history comes from the archive endpoint.
+	maxForecastPastDays = 92
 )
 
+// Day-count limits and defaults for the forecast endpoint. Callers that take
+// day counts from users should pass them through ClampForecastDays and
+// ClampPastDays first.
+const (
+	// DefaultForecastDays is the full horizon Open-Meteo provides and the
+	// one the forecast cache is filled with.
+	DefaultForecastDays = 16
+	MaxForecastDays     = 16
+
+	// DefaultPastDays is the hourly history served with a forecast.
+	DefaultPastDays = 7
+	MaxPastDays     = maxForecastPastDays
+
+	// spinUpPastHours is requested by GetCurrentAndForecast when the caller
+	// asks for no past days: 12 hours of warm-up for the rock temperature
+	// model.
+	spinUpPastHours = 12
+)
+
+// ClampForecastDays limits days to what the forecast endpoint accepts.
+func ClampForecastDays(days int) int {
+	return max(1, min(days, MaxForecastDays))
+}
+
+// ClampPastDays limits days to what the forecast endpoint's past_days accepts.
+func ClampPastDays(days int) int {
+	return max(0, min(days, MaxPastDays))
+}
+
+// expectedMinForecastHours is the lower bound for `hourly.time` length on the
+// GetCurrentAndForecast endpoint for a request of forecastDays days. We
+// tolerate two days of upstream slack and only reject responses shorter than
+// that; for the default 16 days this is 14 days × 24h, matching the
+// service-layer threshold and addressing the observed bug where Open-Meteo
+// intermittently returned 69-359 hours.
+func expectedMinForecastHours(forecastDays int) int {
+	return max(forecastDays-2, 1) * 24
+}
+
 // errOpenMeteoTruncated is returned by the client (and recognized by the retry
 // loop) when Open-Meteo responds with HTTP 200 but a hourly array shorter than
 // expectedMinForecastHours. Using a sentinel-style prefix lets retryableGet
@@ -82,11 +119,14 @@ type openMeteoResponse struct {
 		DiffuseRadiation    []float64 `json:"diffuse_radiation"`
 		Dewpoint2m          []float64 `json:"dew_point_2m"`
 	} `json:"hourly"`
-	Daily *struct {
-		Time    []string `json:"time"`
-		Sunrise []string `json:"sunrise"`
-		Sunset  []string `json:"sunset"`
-	} `json:"daily"`
+	Daily *openMeteoDaily `json:"daily"`
+}
+
+// openMeteoDaily holds the daily sunrise/sunset arrays requested with daily=sunrise,sunset.
+type openMeteoDaily struct {
+	Time    []string `json:"time"`
+	Sunrise []string `json:"sunrise"`
+	Sunset  []string `json:"sunset"`
 }
 
 // DailySunTime represents sunrise/sunset for a single day
@@ -123,47 +163,57 @@ func SetForecastBaseURLForTest(url string) (restore func()) {
 	return func() { openMeteoForecastURL = original }
 }
 
-// retryableGet performs an HTTP GET with retry logic for rate limiting and transient errors
-func (c *OpenMeteoClient) retryableGet(url string) (*http.Response, error) {
-	var lastErr error
-
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		if attempt > 0 {
-			// Calculate exponential backoff: 1s, 2s, 4s
-			delay := initialRetryDelay * time.Duration(1<<uint(attempt-1))
-			log.Printf("Retry attempt %d/%d for Open-Meteo after %v", attempt, maxRetries, delay)
-			time.Sleep(delay)
-		}
+// openMeteoRetryPolicy retries rate limiting (429), server errors and
+// network failures: 4 attempts, 1s/2s/4s apart.
+var openMeteoRetryPolicy = retry.Policy{
+	MaxAttempts:  maxRetries + 1,
+	InitialDelay: initialRetryDelay,
+	ShouldRetry:  httpx.IsTransient,
+	OnRetry: func(attempt int, err error, delay time.Duration) {
+		log.Printf("Open-Meteo request failed (attempt %d/%d), retrying after %v: %v", attempt, maxRetries+1, delay, err)
+	},
+}
 
-		resp, err := c.httpClient.Get(url)
+// retryableGet performs an HTTP GET with retry logic for rate limiting and transient errors.
+// Other error statuses are returned as a response for the caller to handle.
+func (c *OpenMeteoClient) retryableGet(url string) (*http.Response, error) {
+	var resp *http.Response
+	err := retry.Do(context.Background(), openMeteoRetryPolicy, func() error {
+		r, err := c.httpClient.Get(url)
 		if err != nil {
-			lastErr = err
-			// Network errors are retryable
-			log.Printf("Open-Meteo request failed (attempt %d/%d): %v", attempt+1, maxRetries+1, err)
-			continue
+			return err
 		}
-
-		// Check for rate limiting or server errors
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			body, _ := io.ReadAll(resp.Body)
-			resp.Body.Close()
-			lastErr = fmt.Errorf("Open-Meteo API error (status %d): %s", resp.StatusCode, string(body))
-			log.Printf("Open-Meteo returned %d (attempt %d/%d): %s", resp.StatusCode, attempt+1, maxRetries+1, string(body))
-
-			// For 429, check if Retry-After header is present
-			if resp.StatusCode == http.StatusTooManyRequests {
-				if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-					log.Printf("Open-Meteo rate limited. Retry-After: %s", retryAfter)
-				}
+		if r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500 {
+			if retryAfter := r.Header.Get("Retry-After"); retryAfter != "" {
+				log.Printf("Open-Meteo rate limited. Retry-After: %s", retryAfter)
 			}
-			continue
+			_, err = httpx.ReadResponse(r)
+			return err
 		}
-
-		// Success or non-retryable error
-		return resp, nil
+		resp = r
+		return nil
+	})
+	if err != nil {
+		if httpx.IsTransient(err) {
+			return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, err)
+		}
+		return nil, err
 	}
+	return resp, nil
+}
 
-	return nil, fmt.Errorf("failed after %d retries: %w", maxRetries, lastErr)
+// getForecast fetches url via retryableGet and decodes the forecast payload.
+// Non-retryable error statuses come back as *httpx.StatusError with the body.
+func (c *OpenMeteoClient) getForecast(url string) (*openMeteoResponse, error) {
+	resp, err := c.retryableGet(url)
+	if err != nil {
+		return nil, err
+	}
+	data, err := httpx.Decode[openMeteoResponse](resp)
+	if err != nil {
+		return nil, fmt.Errorf("Open-Meteo request failed: %w", err)
+	}
+	return &data, nil
 }
 
 // isRetryableTruncationErr reports whether an error from a higher-level
@@ -236,27 +286,56 @@ func formatSunTimestampUTC(timeStr string) string {
 	return parsed.Format(time.RFC3339)
 }
 
+// buildSunTimes converts the daily sunrise/sunset arrays to SunTimes, or
+// returns nil when the response has no sun data.
+func buildSunTimes(daily *openMeteoDaily) *SunTimes {
+	if daily == nil || len(daily.Sunrise) == 0 || len(daily.Sunset) == 0 {
+		return nil
+	}
+
+	sunTimes := &SunTimes{
+		Sunrise: formatSunTimestampUTC(daily.Sunrise[0]),
+		Sunset:  formatSunTimestampUTC(daily.Sunset[0]),
+	}
+	for i := 0; i < len(daily.Time) && i < len(daily.Sunrise) && i < len(daily.Sunset); i++ {
+		sunTimes.Daily = append(sunTimes.Daily, DailySunTime{
+			Date:    daily.Time[i],
+			Sunrise: formatSunTimestampUTC(daily.Sunrise[i]),
+			Sunset:  formatSunTimestampUTC(daily.Sunset[i]),
+		})
+	}
+	return sunTimes
+}
+
+// GetSunTimes fetches only the daily sunrise/sunset for the next `days` days
+// (1-16), without the current or hourly weather. Timestamps are UTC RFC3339,
+// matching GetCurrentAndForecast.
+func (c *OpenMeteoClient) GetSunTimes(lat, lon float64, days int) (*SunTimes, error) {
+	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&daily=sunrise,sunset&timezone=UTC&forecast_days=%d",
+		openMeteoForecastURL, lat, lon, days)
+
+	data, err := c.getForecast(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sun times from Open-Meteo: %w", err)
+	}
+
+	sunTimes := buildSunTimes(data.Daily)
+	if sunTimes == nil {
+		return nil, fmt.Errorf("no sunrise/sunset data returned from Open-Meteo")
+	}
+	return sunTimes, nil
+}
+
 // GetCurrentWeather fetches current weather with both current conditions and hourly forecast
 // Uses default Open-Meteo model for accurate, consistent data.
 func (c *OpenMeteoClient) GetCurrentWeather(lat, lon float64) (*models.WeatherData, error) {
 	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&current=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&hourly=precipitation,rain,snowfall&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC&forecast_days=1",
 		openMeteoForecastURL, lat, lon)
 
-	resp, err := c.retryableGet(url)
+	data, err := c.getForecast(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch current weather from Open-Meteo: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Open-Meteo API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode Open-Meteo response: %w", err)
-	}
 
 	if data.Current == nil {
 		return nil, fmt.Errorf("no current weather data returned from Open-Meteo")
@@ -293,6 +372,7 @@ func (c *OpenMeteoClient) GetCurrentWeather(lat, lon float64) (*models.WeatherDa
 		DirectRadiation:    data.Current.DirectRadiation,
 		DiffuseRadiation:   data.Current.DiffuseRadiation,
 		DewpointF:          data.Current.Dewpoint2m,
+		PrecipType:         hourlyPrecipType(data, 0, data.Current.Temperature2m),
 	}
 
 	return weather, nil
@@ -302,45 +382,58 @@ func (c *OpenMeteoClient) GetCurrentWeather(lat, lon float64) (*models.WeatherDa
 // Uses default Open-Meteo model with timezone=UTC for consistent timestamp handling.
 // Daily sunrise/sunset is also returned in UTC and converted to RFC3339 for proper frontend display.
 //
+// forecastDays (1-16) sets the forecast horizon. pastDays (0-92) prepends that
+// many days of past hours; 0 prepends only the last 12 hours of spin-up data.
+// Out-of-range values are clamped.
+//
 // On a truncated upstream response (see expectedMinForecastHours), the call is
 // retried once with a short backoff. After one failed retry the truncation
 // error is returned to the caller, which in the service layer triggers the
 // length-validation guard and preserves the existing cache.
-func (c *OpenMeteoClient) GetCurrentAndForecast(lat, lon float64) (*models.WeatherData, []models.WeatherData, *SunTimes, error) {
-	current, forecast, sunTimes, err := c.getCurrentAndForecastOnce(lat, lon)
-	if err != nil && isRetryableTruncationErr(err) {
-		log.Printf("Open-Meteo returned truncated forecast for (%.5f,%.5f); retrying once: %v", lat, lon, err)
-		time.Sleep(initialRetryDelay)
-		current, forecast, sunTimes, err = c.getCurrentAndForecastOnce(lat, lon)
+func (c *OpenMeteoClient) GetCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *SunTimes, error) {
+	forecastDays, pastDays = ClampForecastDays(forecastDays), ClampPastDays(pastDays)
+	var current *models.WeatherData
+	var forecast []models.WeatherData
+	var sunTimes *SunTimes
+	policy := retry.Policy{
+		MaxAttempts:  2,
+		InitialDelay: initialRetryDelay,
+		ShouldRetry:  isRetryableTruncationErr,
+		OnRetry: func(_ int, err error, _ time.Duration) {
+			log.Printf("Open-Meteo returned truncated forecast for (%.5f,%.5f); retrying once: %v", lat, lon, err)
+		},
 	}
+	err := retry.Do(context.Background(), policy, func() error {
+		var err error
+		current, forecast, sunTimes, err = c.getCurrentAndForecastOnce(lat, lon, forecastDays, pastDays)
+		return err
+	})
 	return current, forecast, sunTimes, err
 }
 
+// pastParam returns the query parameter selecting the past hours included
+// with a forecast: pastDays days, or the rock temperature spin-up when 0.
+func pastParam(pastDays int) string {
+	if pastDays == 0 {
+		return fmt.Sprintf("past_hours=%d", spinUpPastHours)
+	}
+	return fmt.Sprintf("past_days=%d", pastDays)
+}
+
 // getCurrentAndForecastOnce performs a single Open-Meteo fetch and parse.
 // It is the workhorse called by GetCurrentAndForecast (which adds one-shot
 // retry on truncated responses).
-func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64) (*models.WeatherData, []models.WeatherData, *SunTimes, error) {
+func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *SunTimes, error) {
 	// All data (hourly, current, daily) uses timezone=UTC for consistent timestamp handling.
 	// Sunrise/sunset timestamps are converted to RFC3339 with Z suffix so the frontend
 	// can correctly interpret them as UTC and display in the user's local timezone.
-	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&current=temperature_2m,relative_humidity_2m,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&hourly=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&daily=sunrise,sunset&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC&forecast_days=16&past_hours=12",
-		openMeteoForecastURL, lat, lon)
+	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&current=temperature_2m,relative_humidity_2m,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&hourly=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&daily=sunrise,sunset&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC&forecast_days=%d&%s",
+		openMeteoForecastURL, lat, lon, forecastDays, pastParam(pastDays))
 
-	resp, err := c.retryableGet(url)
+	data, err := c.getForecast(url)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to fetch weather from Open-Meteo: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, nil, nil, fmt.Errorf("Open-Meteo API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to decode Open-Meteo response: %w", err)
-	}
 
 	if data.Current == nil {
 		return nil, nil, nil, fmt.Errorf("no current weather data returned from Open-Meteo")
@@ -356,9 +449,9 @@ func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64) (*models.W
 	// rather than overwriting it with a stub. The error prefix is used by
 	// isRetryableTruncationErr() / GetCurrentAndForecastWithRetry() to drive
 	// at most one extra attempt.
-	if len(data.Hourly.Time) < expectedMinForecastHours {
+	if minHours := expectedMinForecastHours(forecastDays); len(data.Hourly.Time) < minHours {
 		return nil, nil, nil, fmt.Errorf("%s: got %d hours, expected at least %d",
-			truncatedResponseErrPrefix, len(data.Hourly.Time), expectedMinForecastHours)
+			truncatedResponseErrPrefix, len(data.Hourly.Time), minHours)
 	}
 
 	precipitation := data.Hourly.Precipitation
@@ -367,20 +460,7 @@ func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64) (*models.W
 	}
 
 	// Extract sunrise/sunset for all days
-	var sunTimes *SunTimes
-	if data.Daily != nil && len(data.Daily.Sunrise) > 0 && len(data.Daily.Sunset) > 0 {
-		sunTimes = &SunTimes{
-			Sunrise: formatSunTimestampUTC(data.Daily.Sunrise[0]),
-			Sunset:  formatSunTimestampUTC(data.Daily.Sunset[0]),
-		}
-		for i := 0; i < len(data.Daily.Time) && i < len(data.Daily.Sunrise) && i < len(data.Daily.Sunset); i++ {
-			sunTimes.Daily = append(sunTimes.Daily, DailySunTime{
-				Date:    data.Daily.Time[i],
-				Sunrise: formatSunTimestampUTC(data.Daily.Sunrise[i]),
-				Sunset:  formatSunTimestampUTC(data.Daily.Sunset[i]),
-			})
-		}
-	}
+	sunTimes := buildSunTimes(data.Daily)
 
 	// Parse current weather timestamp as UTC
 	timestamp, err := parseTimestampUTC(data.Current.Time)
@@ -411,22 +491,13 @@ func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64) (*models.W
 		DirectRadiation:    data.Current.DirectRadiation,
 		DiffuseRadiation:   data.Current.DiffuseRadiation,
 		DewpointF:          data.Current.Dewpoint2m,
+		PrecipType:         hourlyPrecipType(data, 0, data.Current.Temperature2m),
 	}
 
 	// Parse forecast data (all hourly data)
+	hours := newHourlyReader(data)
 	var forecast []models.WeatherData
 	for i := 0; i < len(data.Hourly.Time); i++ {
-		if i >= len(data.Hourly.Temperature2m) || i >= len(data.Hourly.ApparentTemperature) ||
-			i >= len(precipitation) || i >= len(data.Hourly.RelativeHumidity2m) ||
-			i >= len(data.Hourly.WindSpeed10m) || i >= len(data.Hourly.WindDirection10m) ||
-			i >= len(data.Hourly.CloudCover) || i >= len(data.Hourly.Pressure) ||
-			i >= len(data.Hourly.WeatherCode) ||
-			i >= len(data.Hourly.ShortwaveRadiation) || i >= len(data.Hourly.DirectRadiation) ||
-			i >= len(data.Hourly.DiffuseRadiation) || i >= len(data.Hourly.Dewpoint2m) {
-			log.Printf("Skipping hour %d due to incomplete data arrays", i)
-			continue
-		}
-
 		ts, err := parseTimestampUTC(data.Hourly.Time[i])
 		if err != nil {
 			log.Printf("Failed to parse hourly timestamp '%s': %v", data.Hourly.Time[i], err)
@@ -439,127 +510,180 @@ func (c *OpenMeteoClient) getCurrentAndForecastOnce(lat, lon float64) (*models.W
 			hourIsNight = isNightTimeForForecast(data.Hourly.Time[i], data.Daily)
 		}
 
-		weather := models.WeatherData{
-			Timestamp:          ts,
-			Temperature:        data.Hourly.Temperature2m[i],
-			FeelsLike:          data.Hourly.ApparentTemperature[i],
-			Precipitation:      precipitation[i],
-			Humidity:           data.Hourly.RelativeHumidity2m[i],
-			WindSpeed:          data.Hourly.WindSpeed10m[i],
-			WindDirection:      data.Hourly.WindDirection10m[i],
-			CloudCover:         data.Hourly.CloudCover[i],
-			Pressure:           int(data.Hourly.Pressure[i]),
-			Description:        getWeatherDescription(data.Hourly.WeatherCode[i]),
-			Icon:               getWeatherIconWithTime(data.Hourly.WeatherCode[i], hourIsNight),
-			ShortwaveRadiation: data.Hourly.ShortwaveRadiation[i],
-			DirectRadiation:    data.Hourly.DirectRadiation[i],
-			DiffuseRadiation:   data.Hourly.DiffuseRadiation[i],
-			DewpointF:          data.Hourly.Dewpoint2m[i],
+		weather, ok := hours.hour(i, ts, func(code int) string {
+			return getWeatherIconWithTime(code, hourIsNight)
+		})
+		if !ok {
+			continue
 		}
 
 		forecast = append(forecast, weather)
 	}
+	hours.logShortArrays(fmt.Sprintf("forecast for (%.4f, %.4f)", lat, lon))
 
 	return current, forecast, sunTimes, nil
 }
 
-// GetForecast fetches hourly forecast data.
-// Uses default Open-Meteo model with timezone=UTC for consistent timestamp storage.
-func (c *OpenMeteoClient) GetForecast(lat, lon float64) ([]models.WeatherData, error) {
-	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&hourly=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC&forecast_days=16",
-		openMeteoForecastURL, lat, lon)
+// GetForecast fetches the next days days (clamped to 1-16) of hourly forecast
+// data. Uses default Open-Meteo model with timezone=UTC for consistent
+// timestamp storage.
+func (c *OpenMeteoClient) GetForecast(lat, lon float64, days int) ([]models.WeatherData, error) {
+	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&hourly=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC&forecast_days=%d",
+		openMeteoForecastURL, lat, lon, ClampForecastDays(days))
 
-	resp, err := c.retryableGet(url)
+	data, err := c.getForecast(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch forecast from Open-Meteo: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Open-Meteo API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode Open-Meteo response: %w", err)
-	}
 
 	precipitation := data.Hourly.Precipitation
 	if len(precipitation) == 0 {
 		return nil, fmt.Errorf("no precipitation data returned from Open-Meteo")
 	}
 
+	hours := newHourlyReader(data)
 	var forecast []models.WeatherData
 	for i := 0; i < len(data.Hourly.Time); i++ {
-		if i >= len(data.Hourly.Temperature2m) || i >= len(data.Hourly.ApparentTemperature) ||
-			i >= len(precipitation) || i >= len(data.Hourly.RelativeHumidity2m) ||
-			i >= len(data.Hourly.WindSpeed10m) || i >= len(data.Hourly.WindDirection10m) ||
-			i >= len(data.Hourly.CloudCover) || i >= len(data.Hourly.Pressure) ||
-			i >= len(data.Hourly.WeatherCode) ||
-			i >= len(data.Hourly.ShortwaveRadiation) || i >= len(data.Hourly.DirectRadiation) ||
-			i >= len(data.Hourly.DiffuseRadiation) || i >= len(data.Hourly.Dewpoint2m) {
-			log.Printf("Skipping hour %d due to incomplete data arrays", i)
-			continue
-		}
-
 		timestamp, err := parseTimestampUTC(data.Hourly.Time[i])
 		if err != nil {
 			log.Printf("Failed to parse hourly timestamp '%s': %v", data.Hourly.Time[i], err)
 			continue
 		}
 
-		weather := models.WeatherData{
-			Timestamp:          timestamp,
-			Temperature:        data.Hourly.Temperature2m[i],
-			FeelsLike:          data.Hourly.ApparentTemperature[i],
-			Precipitation:      precipitation[i],
-			Humidity:           data.Hourly.RelativeHumidity2m[i],
-			WindSpeed:          data.Hourly.WindSpeed10m[i],
-			WindDirection:      data.Hourly.WindDirection10m[i],
-			CloudCover:         data.Hourly.CloudCover[i],
-			Pressure:           int(data.Hourly.Pressure[i]),
-			Description:        getWeatherDescription(data.Hourly.WeatherCode[i]),
-			Icon:               getWeatherIcon(data.Hourly.WeatherCode[i]),
-			ShortwaveRadiation: data.Hourly.ShortwaveRadiation[i],
-			DirectRadiation:    data.Hourly.DirectRadiation[i],
-			DiffuseRadiation:   data.Hourly.DiffuseRadiation[i],
-			DewpointF:          data.Hourly.Dewpoint2m[i],
+		weather, ok := hours.hour(i, timestamp, getWeatherIcon)
+		if !ok {
+			continue
 		}
 
 		forecast = append(forecast, weather)
 	}
+	hours.logShortArrays(fmt.Sprintf("forecast for (%.4f, %.4f)", lat, lon))
 
 	return forecast, nil
 }
 
-// GetHistoricalWeather fetches recent historical weather data using forecast API with past_days.
-// Uses default model which provides reanalysis/observed data for accurate historical precipitation.
+// historicalHourlyVariables is the hourly variable list requested for
+// historical weather from both the forecast and archive endpoints.
+const historicalHourlyVariables = "temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m"
+
+// GetHistoricalWeather fetches the last days days of hourly weather, oldest
+// first. The forecast API's past_days covers the most recent
+// maxForecastPastDays; anything older is fetched from the archive endpoint
+// and stitched on in front (see stitchHistorical).
 func (c *OpenMeteoClient) GetHistoricalWeather(lat, lon float64, days int) ([]models.WeatherData, error) {
-	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&past_days=%d&forecast_days=1&hourly=temperature_2m,relative_humidity_2m,precipitation,rain,snowfall,cloud_cover,wind_speed_10m,wind_direction_10m,weather_code,apparent_temperature,surface_pressure,shortwave_radiation,direct_radiation,diffuse_radiation,dew_point_2m&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC",
-		openMeteoForecastURL, lat, lon, days)
+	now := time.Now()
 
-	resp, err := c.retryableGet(url)
+	recent, err := c.getRecentHistorical(lat, lon, min(days, maxForecastPastDays), now)
+	if err != nil {
+		return nil, err
+	}
+	if days <= maxForecastPastDays {
+		log.Printf("Got %d historical data points for (%.4f, %.4f)", len(recent), lat, lon)
+		return recent, nil
+	}
+
+	// Ask the archive for everything up to the day the forecast window
+	// starts; the overlapping hours are dropped in favour of the forecast.
+	start := now.UTC().AddDate(0, 0, -days)
+	end := now.UTC().AddDate(0, 0, -maxForecastPastDays)
+	archived, err := c.getArchivedHistorical(lat, lon, start, end, now)
+	if err != nil {
+		return nil, err
+	}
+
+	historical := stitchHistorical(archived, recent)
+	log.Printf("Got %d historical data points for (%.4f, %.4f) (%d from archive)",
+		len(historical), lat, lon, len(historical)-len(recent))
+	return historical, nil
+}
+
+// getRecentHistorical fetches recent historical weather using the forecast
+// API with past_days. Uses default model which provides reanalysis/observed
+// data for accurate historical precipitation.
+func (c *OpenMeteoClient) getRecentHistorical(lat, lon float64, days int, now time.Time) ([]models.WeatherData, error) {
+	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&past_days=%d&forecast_days=1&hourly=%s&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC",
+		openMeteoForecastURL, lat, lon, days, historicalHourlyVariables)
+
+	data, err := c.getForecast(url)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch historical weather from Open-Meteo: %w", err)
 	}
-	defer resp.Body.Close()
+	return historicalHours(data, nil, now), nil
+}
+
+// getArchivedHistorical fetches hourly weather for the UTC dates start
+// through end from the archive endpoint. The archive is ERA5 reanalysis,
+// which trails real time by several days and reports hours it has no data
+// for yet as null; those hours are dropped rather than read as zeros.
+func (c *OpenMeteoClient) getArchivedHistorical(lat, lon float64, start, end, now time.Time) ([]models.WeatherData, error) {
+	url := fmt.Sprintf("%s?latitude=%.8f&longitude=%.8f&start_date=%s&end_date=%s&hourly=%s&temperature_unit=fahrenheit&wind_speed_unit=mph&precipitation_unit=inch&timezone=UTC",
+		openMeteoHistoricalURL, lat, lon, start.Format("2006-01-02"), end.Format("2006-01-02"), historicalHourlyVariables)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Open-Meteo API error (status %d): %s", resp.StatusCode, string(body))
+	resp, err := c.retryableGet(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archived weather from Open-Meteo: %w", err)
+	}
+	body, err := httpx.ReadResponse(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch archived weather from Open-Meteo: %w", err)
 	}
 
 	var data openMeteoResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode Open-Meteo response: %w", err)
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode archived weather: %w", err)
 	}
 
-	precipitation := data.Hourly.Precipitation
+	// Decoding null into a float64 leaves it zero, so look for nulls in
+	// the fields a missing hour would otherwise fake as dry and 0°F.
+	var nullable struct {
+		Hourly struct {
+			Temperature2m []*float64 `json:"temperature_2m"`
+			Precipitation []*float64 `json:"precipitation"`
+		} `json:"hourly"`
+	}
+	if err := json.Unmarshal(body, &nullable); err != nil {
+		return nil, fmt.Errorf("failed to decode archived weather: %w", err)
+	}
+	missing := make([]bool, len(data.Hourly.Time))
+	for i := range missing {
+		missing[i] = i >= len(nullable.Hourly.Temperature2m) || nullable.Hourly.Temperature2m[i] == nil ||
+			i >= len(nullable.Hourly.Precipitation) || nullable.Hourly.Precipitation[i] == nil
+	}
 
-	now := time.Now()
+	return historicalHours(&data, missing, now), nil
+}
+
+// stitchHistorical joins archived hours onto the front of the recent
+// forecast-API hours. Where the two overlap the forecast API's hour is kept:
+// it comes from the same model as the rest of the recent series, whereas the
+// archive's coarser reanalysis can differ by a few degrees.
+func stitchHistorical(archived, recent []models.WeatherData) []models.WeatherData {
+	if len(recent) == 0 {
+		return archived
+	}
+	cutoff := recent[0].Timestamp
+
+	stitched := make([]models.WeatherData, 0, len(archived)+len(recent))
+	for _, hour := range archived {
+		if hour.Timestamp.Before(cutoff) {
+			stitched = append(stitched, hour)
+		}
+	}
+	return append(stitched, recent...)
+}
+
+// historicalHours converts a historical hourly response to WeatherData,
+// skipping future hours, hours marked in missing, and hours without
+// temperature or precipitation. Other short arrays are filled (see
+// hourlyReader).
+func historicalHours(data *openMeteoResponse, missing []bool, now time.Time) []models.WeatherData {
+	hours := newHourlyReader(data)
 	var historical []models.WeatherData
 	for i := range data.Hourly.Time {
+		if i < len(missing) && missing[i] {
+			continue
+		}
+
 		timestamp, err := parseTimestampUTC(data.Hourly.Time[i])
 		if err != nil {
 			log.Printf("Failed to parse historical timestamp '%s': %v", data.Hourly.Time[i], err)
@@ -571,41 +695,16 @@ func (c *OpenMeteoClient) GetHistoricalWeather(lat, lon float64, days int) ([]mo
 			continue
 		}
 
-		// Bounds check for all arrays
-		if i >= len(data.Hourly.Temperature2m) || i >= len(data.Hourly.ApparentTemperature) ||
-			i >= len(precipitation) || i >= len(data.Hourly.RelativeHumidity2m) ||
-			i >= len(data.Hourly.WindSpeed10m) || i >= len(data.Hourly.WindDirection10m) ||
-			i >= len(data.Hourly.CloudCover) || i >= len(data.Hourly.Pressure) ||
-			i >= len(data.Hourly.WeatherCode) ||
-			i >= len(data.Hourly.ShortwaveRadiation) || i >= len(data.Hourly.DirectRadiation) ||
-			i >= len(data.Hourly.DiffuseRadiation) || i >= len(data.Hourly.Dewpoint2m) {
-			log.Printf("Skipping historical hour %d due to incomplete data arrays", i)
+		weather, ok := hours.hour(i, timestamp, getWeatherIcon)
+		if !ok {
 			continue
 		}
 
-		weather := models.WeatherData{
-			Timestamp:          timestamp,
-			Temperature:        data.Hourly.Temperature2m[i],
-			FeelsLike:          data.Hourly.ApparentTemperature[i],
-			Precipitation:      precipitation[i],
-			Humidity:           data.Hourly.RelativeHumidity2m[i],
-			WindSpeed:          data.Hourly.WindSpeed10m[i],
-			WindDirection:      data.Hourly.WindDirection10m[i],
-			CloudCover:         data.Hourly.CloudCover[i],
-			Pressure:           int(data.Hourly.Pressure[i]),
-			Description:        getWeatherDescription(data.Hourly.WeatherCode[i]),
-			Icon:               getWeatherIcon(data.Hourly.WeatherCode[i]),
-			ShortwaveRadiation: data.Hourly.ShortwaveRadiation[i],
-			DirectRadiation:    data.Hourly.DirectRadiation[i],
-			DiffuseRadiation:   data.Hourly.DiffuseRadiation[i],
-			DewpointF:          data.Hourly.Dewpoint2m[i],
-		}
-
 		historical = append(historical, weather)
 	}
+	hours.logShortArrays("historical weather")
 
-	log.Printf("Got %d historical data points for (%.4f, %.4f)", len(historical), lat, lon)
-	return historical, nil
+	return historical
 }
 
 // Map WMO weather codes to descriptions
@@ -670,11 +769,7 @@ func isNightTime(timeStr, sunrise, sunset string) bool {
 }
 
 // isNightTimeForForecast checks if a forecast hour is night time using the daily sunrise/sunset data
-func isNightTimeForForecast(timeStr string, daily *struct {
-	Time    []string `json:"time"`
-	Sunrise []string `json:"sunrise"`
-	Sunset  []string `json:"sunset"`
-}) bool {
+func isNightTimeForForecast(timeStr string, daily *openMeteoDaily) bool {
 	if daily == nil || len(daily.Time) == 0 {
 		return false
 	}
@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
 func TestParseTimestampUTC(t *testing.T) {
@@ -300,7 +305,7 @@ func TestGetCurrentAndForecast_RejectsTruncatedResponse(t *testing.T) {
 	defer func() { openMeteoForecastURL = originalURL }()
 
 	client := NewOpenMeteoClient()
-	current, forecast, _, err := client.GetCurrentAndForecast(47.0, -121.0)
+	current, forecast, _, err := client.GetCurrentAndForecast(47.0, -121.0, DefaultForecastDays, 0)
 
 	if err == nil {
 		t.Fatalf("expected truncation error, got nil (current=%v, forecast_len=%d)", current, len(forecast))
@@ -339,3 +344,414 @@ func TestIsRetryableTruncationErr(t *testing.T) {
 		})
 	}
 }
+
+func TestExpectedMinForecastHours(t *testing.T) {
+	tests := []struct{ days, want int }{
+		{DefaultForecastDays, 336},
+		{7, 120},
+		{3, 24},
+		{1, 24},
+	}
+	for _, tt := range tests {
+		if got := expectedMinForecastHours(tt.days); got != tt.want {
+			t.Errorf("expectedMinForecastHours(%d) = %d, want %d", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestClampDays(t *testing.T) {
+	tests := []struct{ days, forecast, past int }{
+		{-5, 1, 0},
+		{0, 1, 0},
+		{3, 3, 3},
+		{16, 16, 16},
+		{30, 16, 30},
+		{500, 16, 92},
+	}
+	for _, tt := range tests {
+		if got := ClampForecastDays(tt.days); got != tt.forecast {
+			t.Errorf("ClampForecastDays(%d) = %d, want %d", tt.days, got, tt.forecast)
+		}
+		if got := ClampPastDays(tt.days); got != tt.past {
+			t.Errorf("ClampPastDays(%d) = %d, want %d", tt.days, got, tt.past)
+		}
+	}
+}
+
+// TestForecastDayCounts checks the day counts reach the request URL, clamped
+// to Open-Meteo's limits, and that no past days still asks for the spin-up
+// hours.
+func TestForecastDayCounts(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer SetForecastBaseURLForTest(server.URL)()
+
+	client := NewOpenMeteoClient()
+	client.GetCurrentAndForecast(47.0, -121.0, 3, 0)
+	client.GetCurrentAndForecast(47.0, -121.0, 40, 200)
+	client.GetForecast(47.0, -121.0, 0)
+
+	want := []map[string]string{
+		{"forecast_days": "3", "past_hours": "12", "past_days": ""},
+		{"forecast_days": "16", "past_hours": "", "past_days": "92"},
+		{"forecast_days": "1", "past_hours": "", "past_days": ""},
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("expected %d requests, got %d", len(want), len(queries))
+	}
+	for i, q := range queries {
+		for param, v := range want[i] {
+			if got := q.Get(param); got != v {
+				t.Errorf("request %d: %s = %q, want %q", i, param, got, v)
+			}
+		}
+	}
+}
+
+func TestGetSunTimes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("daily") != "sunrise,sunset" || q.Get("forecast_days") != "2" {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		if q.Get("hourly") != "" || q.Get("current") != "" {
+			t.Errorf("sun-only request should not ask for weather: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"daily": {
+			"time": ["2026-07-01", "2026-07-02"],
+			"sunrise": ["2026-07-01T12:15", "2026-07-02T12:16"],
+			"sunset": ["2026-07-02T04:10", "2026-07-03T04:10"]
+		}}`))
+	}))
+	defer server.Close()
+	defer SetForecastBaseURLForTest(server.URL)()
+
+	sunTimes, err := NewOpenMeteoClient().GetSunTimes(47.0, -121.0, 2)
+	if err != nil {
+		t.Fatalf("GetSunTimes() error = %v", err)
+	}
+	if sunTimes.Sunrise != "2026-07-01T12:15:00Z" || sunTimes.Sunset != "2026-07-02T04:10:00Z" {
+		t.Errorf("today = %s / %s", sunTimes.Sunrise, sunTimes.Sunset)
+	}
+	if len(sunTimes.Daily) != 2 || sunTimes.Daily[1].Date != "2026-07-02" {
+		t.Errorf("Daily = %+v", sunTimes.Daily)
+	}
+}
+
+// TestOpenMeteoRequestedVariablesAreDecoded guards against a variable being
+// added to a request URL without a matching field on openMeteoResponse, which
+// Open-Meteo happily returns and encoding/json silently drops. It also locks
+// in the single (default) model: a models= parameter would suffix every
+// variable name (e.g. temperature_2m_gfs_seamless) and none would decode.
+func TestOpenMeteoRequestedVariablesAreDecoded(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer SetForecastBaseURLForTest(server.URL)()
+
+	client := NewOpenMeteoClient()
+	client.GetCurrentWeather(47.0, -121.0)
+	client.GetCurrentAndForecast(47.0, -121.0, DefaultForecastDays, 0)
+	client.GetForecast(47.0, -121.0, DefaultForecastDays)
+	client.GetHistoricalWeather(47.0, -121.0, 2)
+	client.GetSunTimes(47.0, -121.0, 1)
+	if len(queries) != 5 {
+		t.Fatalf("expected 5 requests, got %d", len(queries))
+	}
+
+	respType := reflect.TypeOf(openMeteoResponse{})
+	blocks := map[string]map[string]bool{}
+	for _, block := range []string{"current", "hourly", "daily"} {
+		field, ok := fieldByJSONTag(respType, block)
+		if !ok {
+			t.Fatalf("openMeteoResponse has no %q field", block)
+		}
+		blocks[block] = jsonTags(field.Type)
+	}
+
+	for _, q := range queries {
+		if m := q.Get("models"); m != "" {
+			t.Errorf("unexpected models=%q; the client decodes default-model fields only", m)
+		}
+		for block, tags := range blocks {
+			for _, variable := range strings.Split(q.Get(block), ",") {
+				if variable != "" && !tags[variable] {
+					t.Errorf("%s=%s is requested but openMeteoResponse.%s has no field for it", block, variable, block)
+				}
+			}
+		}
+	}
+}
+
+// TestGetCurrentAndForecast_ParsesResponse verifies each hourly array lands
+// on the matching WeatherData field, precipitation comes from the total
+// precipitation array (not rain alone), and sun times come from the daily
+// block.
+func TestGetCurrentAndForecast_ParsesResponse(t *testing.T) {
+	hours := expectedMinForecastHours(DefaultForecastDays) + 12
+	start := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	hourly := map[string][]interface{}{}
+	add := func(key string, v interface{}) { hourly[key] = append(hourly[key], v) }
+	for i := 0; i < hours; i++ {
+		add("time", start.Add(time.Duration(i)*time.Hour).Format("2006-01-02T15:04"))
+		add("temperature_2m", 50.0+float64(i%10))
+		add("relative_humidity_2m", 40+i%50)
+		add("precipitation", 0.01*float64(i%5))
+		add("rain", 0.0) // snow-only hours would read 0 if rain were used
+		add("snowfall", 0.1)
+		add("cloud_cover", i%100)
+		add("wind_speed_10m", 3.0)
+		add("wind_direction_10m", 270)
+		add("weather_code", 61)
+		add("apparent_temperature", 45.0)
+		add("surface_pressure", 1012.6)
+		add("shortwave_radiation", 300.0)
+		add("direct_radiation", 200.0)
+		add("diffuse_radiation", 100.0)
+		add("dew_point_2m", 38.0)
+	}
+
+	resp := map[string]interface{}{
+		"current": map[string]interface{}{
+			"time":                 "2026-07-01T10:00", // before sunrise (12:15Z)
+			"temperature_2m":       55.5,
+			"relative_humidity_2m": 70,
+			"cloud_cover":          90,
+			"wind_speed_10m":       4.0,
+			"wind_direction_10m":   200,
+			"weather_code":         0,
+			"apparent_temperature": 53.0,
+			"surface_pressure":     1009.4,
+			"shortwave_radiation":  0.0,
+			"direct_radiation":     0.0,
+			"diffuse_radiation":    0.0,
+			"dew_point_2m":         45.0,
+		},
+		"hourly": hourly,
+		"daily": map[string]interface{}{
+			"time":    []string{"2026-07-01", "2026-07-02"},
+			"sunrise": []string{"2026-07-01T12:15", "2026-07-02T12:16"},
+			"sunset":  []string{"2026-07-02T04:10", "2026-07-03T04:10"},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+	defer SetForecastBaseURLForTest(server.URL)()
+
+	current, forecast, sunTimes, err := NewOpenMeteoClient().GetCurrentAndForecast(47.0, -121.0, DefaultForecastDays, 0)
+	if err != nil {
+		t.Fatalf("GetCurrentAndForecast() error = %v", err)
+	}
+
+	if current.Temperature != 55.5 || current.Humidity != 70 || current.Pressure != 1009 || current.DewpointF != 45.0 {
+		t.Errorf("current = %+v", current)
+	}
+	if current.Precipitation != 0 {
+		t.Errorf("current precipitation = %v, want first hourly value 0", current.Precipitation)
+	}
+	if current.Icon != "01n" {
+		t.Errorf("current icon = %q, want night clear-sky icon before sunrise", current.Icon)
+	}
+
+	if len(forecast) != hours {
+		t.Fatalf("forecast has %d hours, want %d", len(forecast), hours)
+	}
+	hour3 := forecast[3]
+	if !hour3.Timestamp.Equal(start.Add(3 * time.Hour)) {
+		t.Errorf("hour 3 timestamp = %v", hour3.Timestamp)
+	}
+	if hour3.Temperature != 53.0 || hour3.Humidity != 43 || hour3.CloudCover != 3 {
+		t.Errorf("hour 3 = %+v", hour3)
+	}
+	if hour3.Precipitation != 0.03 {
+		t.Errorf("hour 3 precipitation = %v, want 0.03 from the precipitation array", hour3.Precipitation)
+	}
+	// 0.1in of snow is ~0.014in of water: about half of the hour's 0.03in
+	if hour3.PrecipType != models.PrecipTypeMixed {
+		t.Errorf("hour 3 precip type = %q, want %q", hour3.PrecipType, models.PrecipTypeMixed)
+	}
+	if forecast[0].PrecipType != models.PrecipTypeNone {
+		t.Errorf("hour 0 precip type = %q, want %q for no precipitation", forecast[0].PrecipType, models.PrecipTypeNone)
+	}
+	if hour3.ShortwaveRadiation != 300 || hour3.DirectRadiation != 200 || hour3.DiffuseRadiation != 100 {
+		t.Errorf("hour 3 radiation = %v/%v/%v", hour3.ShortwaveRadiation, hour3.DirectRadiation, hour3.DiffuseRadiation)
+	}
+
+	if sunTimes == nil {
+		t.Fatal("expected sun times from the daily block")
+	}
+	if sunTimes.Sunrise != "2026-07-01T12:15:00Z" || sunTimes.Sunset != "2026-07-02T04:10:00Z" {
+		t.Errorf("sun times = %s / %s", sunTimes.Sunrise, sunTimes.Sunset)
+	}
+	if len(sunTimes.Daily) != 2 {
+		t.Errorf("Daily = %+v", sunTimes.Daily)
+	}
+}
+
+// historicalResponse builds an hourly Open-Meteo payload for times, with
+// temperature temps[i] at times[i]. A nil temperature is sent as JSON null,
+// as the archive does for hours it has no data for yet.
+func historicalResponse(times []time.Time, temps []*float64) map[string]interface{} {
+	n := len(times)
+	hourlyTimes := make([]string, n)
+	precip := make([]interface{}, n)
+	temp := make([]interface{}, n)
+	for i, ts := range times {
+		hourlyTimes[i] = ts.UTC().Format("2006-01-02T15:04")
+		if temps[i] == nil {
+			temp[i], precip[i] = nil, nil
+		} else {
+			temp[i], precip[i] = *temps[i], 0.0
+		}
+	}
+	ints := make([]int, n)
+	floats := make([]float64, n)
+	return map[string]interface{}{
+		"hourly": map[string]interface{}{
+			"time":                 hourlyTimes,
+			"temperature_2m":       temp,
+			"precipitation":        precip,
+			"relative_humidity_2m": ints,
+			"rain":                 floats,
+			"snowfall":             floats,
+			"cloud_cover":          ints,
+			"wind_speed_10m":       floats,
+			"wind_direction_10m":   ints,
+			"weather_code":         ints,
+			"apparent_temperature": floats,
+			"surface_pressure":     floats,
+			"shortwave_radiation":  floats,
+			"direct_radiation":     floats,
+			"diffuse_radiation":    floats,
+			"dew_point_2m":         floats,
+		},
+	}
+}
+
+// TestGetHistoricalWeather_StitchesArchive verifies ranges beyond the
+// forecast API's past_days window pull the older part from the archive:
+// archive hours overlapping the forecast window are dropped in favour of the
+// forecast's, and null (not yet available) archive hours are skipped rather
+// than read as 0°F.
+func TestGetHistoricalWeather_StitchesArchive(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	windowStart := time.Now().UTC().AddDate(0, 0, -maxForecastPastDays).Truncate(time.Hour)
+
+	forecastTimes := []time.Time{windowStart, windowStart.Add(time.Hour)}
+	archiveTimes := []time.Time{
+		windowStart.Add(-3 * time.Hour),
+		windowStart.Add(-2 * time.Hour), // null: no archive data
+		windowStart.Add(-1 * time.Hour),
+		windowStart, // overlaps the forecast window
+	}
+
+	var forecastQuery, archiveQuery url.Values
+	forecast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forecastQuery = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(historicalResponse(forecastTimes, []*float64{f(60), f(61)}))
+	}))
+	defer forecast.Close()
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		archiveQuery = r.URL.Query()
+		_ = json.NewEncoder(w).Encode(historicalResponse(archiveTimes, []*float64{f(50), nil, f(52), f(99)}))
+	}))
+	defer archive.Close()
+
+	defer SetForecastBaseURLForTest(forecast.URL)()
+	originalArchive := openMeteoHistoricalURL
+	openMeteoHistoricalURL = archive.URL
+	defer func() { openMeteoHistoricalURL = originalArchive }()
+
+	data, err := NewOpenMeteoClient().GetHistoricalWeather(47.0, -121.0, 120)
+	if err != nil {
+		t.Fatalf("GetHistoricalWeather() error = %v", err)
+	}
+
+	if got := forecastQuery.Get("past_days"); got != fmt.Sprint(maxForecastPastDays) {
+		t.Errorf("forecast past_days = %s, want %d", got, maxForecastPastDays)
+	}
+	if got, want := archiveQuery.Get("start_date"), time.Now().UTC().AddDate(0, 0, -120).Format("2006-01-02"); got != want {
+		t.Errorf("archive start_date = %s, want %s", got, want)
+	}
+	if got, want := archiveQuery.Get("end_date"), windowStart.Format("2006-01-02"); got != want {
+		t.Errorf("archive end_date = %s, want %s", got, want)
+	}
+
+	var temps []float64
+	for i, hour := range data {
+		temps = append(temps, hour.Temperature)
+		if i > 0 && !hour.Timestamp.After(data[i-1].Timestamp) {
+			t.Errorf("hour %d (%s) is not after hour %d (%s)", i, hour.Timestamp, i-1, data[i-1].Timestamp)
+		}
+	}
+	if fmt.Sprint(temps) != "[50 52 60 61]" {
+		t.Errorf("temperatures = %v, want [50 52 60 61] (null archive hour skipped, forecast wins the overlap)", temps)
+	}
+}
+
+// TestGetHistoricalWeather_RecentOnlySkipsArchive verifies ranges inside the
+// forecast window never touch the archive.
+func TestGetHistoricalWeather_RecentOnlySkipsArchive(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	hour := time.Now().UTC().Add(-time.Hour).Truncate(time.Hour)
+
+	forecast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(historicalResponse([]time.Time{hour}, []*float64{f(55)}))
+	}))
+	defer forecast.Close()
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("archive should not be requested for ranges inside the forecast window")
+	}))
+	defer archive.Close()
+
+	defer SetForecastBaseURLForTest(forecast.URL)()
+	originalArchive := openMeteoHistoricalURL
+	openMeteoHistoricalURL = archive.URL
+	defer func() { openMeteoHistoricalURL = originalArchive }()
+
+	data, err := NewOpenMeteoClient().GetHistoricalWeather(47.0, -121.0, maxForecastPastDays)
+	if err != nil {
+		t.Fatalf("GetHistoricalWeather() error = %v", err)
+	}
+	if len(data) != 1 || data[0].Temperature != 55 {
+		t.Errorf("data = %+v, want one forecast hour at 55°F", data)
+	}
+}
+
+// fieldByJSONTag returns the struct field of t whose json tag name is tag.
+func fieldByJSONTag(t reflect.Type, tag string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.Split(f.Tag.Get("json"), ",")[0] == tag {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// jsonTags returns the json tag names of a struct (or pointer to struct) type.
+func jsonTags(t reflect.Type) map[string]bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	tags := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		tags[strings.Split(t.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+	return tags
+}
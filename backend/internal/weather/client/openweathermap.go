@@ -1,12 +1,12 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/alexscott64/woulder/backend/internal/httpx"
 	"github.com/alexscott64/woulder/backend/internal/models"
 )
 
@@ -88,21 +88,10 @@ func (c *OpenWeatherMapClient) GetCurrentWeather(lat, lon float64) (*models.Weat
 	url := fmt.Sprintf("%s/weather?lat=%.8f&lon=%.8f&appid=%s&units=imperial",
 		openWeatherMapBaseURL, lat, lon, c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	data, err := httpx.GetJSON[owmCurrentResponse](context.Background(), c.httpClient, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch current weather: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data owmCurrentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
 
 	weather := &models.WeatherData{
 		Timestamp:     time.Unix(data.Dt, 0),
@@ -114,6 +103,7 @@ func (c *OpenWeatherMapClient) GetCurrentWeather(lat, lon float64) (*models.Weat
 		WindDirection: data.Wind.Deg,
 		CloudCover:    data.Clouds.All,
 		Pressure:      data.Main.Pressure,
+		PrecipType:    classifyPrecipType(data.Rain.OneH/25.4, 0, data.Main.Temp, true),
 	}
 
 	if len(data.Weather) > 0 {
@@ -124,29 +114,24 @@ func (c *OpenWeatherMapClient) GetCurrentWeather(lat, lon float64) (*models.Weat
 	return weather, nil
 }
 
-// GetForecast fetches 5-day/3-hour forecast for a location
-func (c *OpenWeatherMapClient) GetForecast(lat, lon float64) ([]models.WeatherData, error) {
+// GetForecast fetches the 5-day/3-hour forecast for a location, cut to the
+// next days days
+func (c *OpenWeatherMapClient) GetForecast(lat, lon float64, days int) ([]models.WeatherData, error) {
 	url := fmt.Sprintf("%s/forecast?lat=%.8f&lon=%.8f&appid=%s&units=imperial",
 		openWeatherMapBaseURL, lat, lon, c.apiKey)
 
-	resp, err := c.httpClient.Get(url)
+	data, err := httpx.GetJSON[owmResponse](context.Background(), c.httpClient, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch forecast: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var data owmResponse
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
 
+	cutoff := time.Now().Add(time.Duration(days) * 24 * time.Hour)
 	var forecast []models.WeatherData
 	for _, item := range data.List {
+		if time.Unix(item.Dt, 0).After(cutoff) {
+			break
+		}
+
 		// Combine rain and snow into total precipitation
 		totalPrecip := (item.Rain.ThreeH + item.Snow.ThreeH) / 25.4 // Convert mm to inches
 
@@ -160,6 +145,7 @@ func (c *OpenWeatherMapClient) GetForecast(lat, lon float64) ([]models.WeatherDa
 			WindDirection: item.Wind.Deg,
 			CloudCover:    item.Clouds.All,
 			Pressure:      item.Main.Pressure,
+			PrecipType:    classifyPrecipType(totalPrecip, item.Snow.ThreeH/25.4, item.Main.Temp, true),
 		}
 
 		if len(item.Weather) > 0 {
@@ -0,0 +1,75 @@
+package client
+
+import "github.com/alexscott64/woulder/backend/internal/models"
+
+const (
+	// precipTypeThresholdInches is the hourly precipitation below which an
+	// hour is classified as PrecipTypeNone. Matches
+	// weather.DefaultRainThresholdInches (not imported: weather imports this
+	// package).
+	precipTypeThresholdInches = 0.01
+
+	// openMeteoSnowToWaterRatio converts Open-Meteo snowfall (depth of fresh
+	// snow) to water equivalent: 7 units of snow per unit of water.
+	openMeteoSnowToWaterRatio = 7.0
+
+	// mixedSnowShareMin and mixedSnowShareMax bound the snow share of an
+	// hour's precipitation classified as mixed. Below is rain, above is snow.
+	mixedSnowShareMin = 0.2
+	mixedSnowShareMax = 0.8
+
+	// Without a rain/snow split, precipitation at or below snowMaxTempF is
+	// snow, at or above rainMinTempF is rain, and mixed in between. Same band
+	// as the snow accumulation model.
+	snowMaxTempF = 30.0
+	rainMinTempF = 34.0
+)
+
+// classifyPrecipType classifies an hour's precipitation as none, rain, snow
+// or mixed. totalInches is the hour's total precipitation and snowWaterInches
+// the water equivalent of its snowfall, both in inches. When splitKnown is
+// false (the provider returned no rain/snow split) the air temperature
+// decides instead.
+func classifyPrecipType(totalInches, snowWaterInches, tempF float64, splitKnown bool) string {
+	if totalInches < precipTypeThresholdInches {
+		return models.PrecipTypeNone
+	}
+
+	if !splitKnown {
+		switch {
+		case tempF <= snowMaxTempF:
+			return models.PrecipTypeSnow
+		case tempF >= rainMinTempF:
+			return models.PrecipTypeRain
+		default:
+			return models.PrecipTypeMixed
+		}
+	}
+
+	// Rounding in the provider's per-variable values can push the snow
+	// share slightly past 1.
+	snowShare := snowWaterInches / totalInches
+	switch {
+	case snowShare >= mixedSnowShareMax:
+		return models.PrecipTypeSnow
+	case snowShare <= mixedSnowShareMin:
+		return models.PrecipTypeRain
+	default:
+		return models.PrecipTypeMixed
+	}
+}
+
+// hourlyPrecipType classifies hour i of an Open-Meteo hourly response at air
+// temperature tempF. The split comes from the snowfall variable; rain alone
+// would miss showers, which Open-Meteo reports separately.
+func hourlyPrecipType(data *openMeteoResponse, i int, tempF float64) string {
+	if i >= len(data.Hourly.Precipitation) {
+		return ""
+	}
+	splitKnown := i < len(data.Hourly.Snowfall)
+	snowWater := 0.0
+	if splitKnown {
+		snowWater = data.Hourly.Snowfall[i] / openMeteoSnowToWaterRatio
+	}
+	return classifyPrecipType(data.Hourly.Precipitation[i], snowWater, tempF, splitKnown)
+}
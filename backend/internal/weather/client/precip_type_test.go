@@ -0,0 +1,55 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestClassifyPrecipType(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      float64
+		snowWater  float64
+		tempF      float64
+		splitKnown bool
+		want       string
+	}{
+		{"dry", 0, 0, 50, true, models.PrecipTypeNone},
+		{"trace", 0.005, 0.005, 20, true, models.PrecipTypeNone},
+		{"all rain", 0.1, 0, 50, true, models.PrecipTypeRain},
+		{"all snow", 0.1, 0.1, 25, true, models.PrecipTypeSnow},
+		{"rounding past total", 0.1, 0.11, 25, true, models.PrecipTypeSnow},
+		{"mostly snow", 0.1, 0.085, 31, true, models.PrecipTypeSnow},
+		{"mostly rain", 0.1, 0.015, 35, true, models.PrecipTypeRain},
+		{"half and half", 0.1, 0.05, 33, true, models.PrecipTypeMixed},
+		{"split beats temperature", 0.1, 0, 20, true, models.PrecipTypeRain},
+		{"no split cold", 0.1, 0, 30, false, models.PrecipTypeSnow},
+		{"no split warm", 0.1, 0, 34, false, models.PrecipTypeRain},
+		{"no split near freezing", 0.1, 0, 32, false, models.PrecipTypeMixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyPrecipType(tt.total, tt.snowWater, tt.tempF, tt.splitKnown); got != tt.want {
+				t.Errorf("classifyPrecipType(%v, %v, %v, %v) = %q, want %q", tt.total, tt.snowWater, tt.tempF, tt.splitKnown, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHourlyPrecipType(t *testing.T) {
+	data := &openMeteoResponse{}
+	data.Hourly.Precipitation = []float64{0.07, 0.07}
+	data.Hourly.Snowfall = []float64{0.49} // 0.07in of water
+
+	if got := hourlyPrecipType(data, 0, 40); got != models.PrecipTypeSnow {
+		t.Errorf("hour 0 = %q, want snow from the snowfall split", got)
+	}
+	if got := hourlyPrecipType(data, 1, 40); got != models.PrecipTypeRain {
+		t.Errorf("hour 1 = %q, want rain from temperature without a split", got)
+	}
+	if got := hourlyPrecipType(data, 2, 40); got != "" {
+		t.Errorf("hour 2 = %q, want unknown past the precipitation array", got)
+	}
+}
@@ -0,0 +1,68 @@
+package weather
+
+import (
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// Forecast confidence levels reported on models.WeatherData.Confidence.
+//
+// Open-Meteo's default (best_match) model blends high-resolution regional
+// models (HRRR/NAM over the US) for roughly the first 60 hours and then falls
+// back to global models (GFS/ECMWF) out to 16 days. best_match does not report
+// which model produced a given hour, so the model is inferred from lead time:
+//
+//	high   - observed, past, or lead <= 60h (regional model)
+//	medium - lead 60h to 7 days (global model, still useful for planning)
+//	low    - lead beyond 7 days (trend only)
+//
+// Lead time is measured from when the forecast was issued (fetched), not from
+// when it is served, so stale cached rows are graded by their real horizon.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+const (
+	// regionalModelHorizon is how far out the high-resolution regional models
+	// cover before best_match switches to a global model.
+	regionalModelHorizon = 60 * time.Hour
+
+	// mediumConfidenceHorizon is where global model forecasts stop being more
+	// than a general trend.
+	mediumConfidenceHorizon = 7 * 24 * time.Hour
+)
+
+// ForecastConfidence returns the confidence level for a forecast valid at ts
+// that was issued at issuedAt. Timestamps at or before issuedAt are high.
+func ForecastConfidence(ts, issuedAt time.Time) string {
+	lead := ts.Sub(issuedAt)
+	switch {
+	case lead <= regionalModelHorizon:
+		return ConfidenceHigh
+	case lead <= mediumConfidenceHorizon:
+		return ConfidenceMedium
+	default:
+		return ConfidenceLow
+	}
+}
+
+// ApplyForecastConfidence sets Confidence on every hour in hourly. Rows loaded
+// from the cache are graded from their CreatedAt (fetch time); freshly fetched
+// rows have no CreatedAt and are graded from now. Hours already in the past
+// relative to now are always high.
+func ApplyForecastConfidence(hourly []models.WeatherData, now time.Time) {
+	for i := range hourly {
+		if !hourly[i].Timestamp.After(now) {
+			hourly[i].Confidence = ConfidenceHigh
+			continue
+		}
+		issuedAt := now
+		if !hourly[i].CreatedAt.IsZero() && hourly[i].CreatedAt.Before(now) {
+			issuedAt = hourly[i].CreatedAt
+		}
+		hourly[i].Confidence = ForecastConfidence(hourly[i].Timestamp, issuedAt)
+	}
+}
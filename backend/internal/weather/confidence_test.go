@@ -0,0 +1,77 @@
+package weather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestForecastConfidence(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		lead time.Duration
+		want string
+	}{
+		{"past hour", -3 * time.Hour, ConfidenceHigh},
+		{"now", 0, ConfidenceHigh},
+		{"hour 1", time.Hour, ConfidenceHigh},
+		{"regional model edge", 60 * time.Hour, ConfidenceHigh},
+		{"just past regional model", 61 * time.Hour, ConfidenceMedium},
+		{"day 7", 7 * 24 * time.Hour, ConfidenceMedium},
+		{"day 8", 8 * 24 * time.Hour, ConfidenceLow},
+		{"hour 500", 500 * time.Hour, ConfidenceLow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ForecastConfidence(now.Add(tt.lead), now); got != tt.want {
+				t.Errorf("ForecastConfidence(+%v) = %q, want %q", tt.lead, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyForecastConfidence(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hourly := []models.WeatherData{
+		{Timestamp: now.Add(2 * time.Hour)},
+		{Timestamp: now.Add(72 * time.Hour)},
+		{Timestamp: now.Add(240 * time.Hour)},
+	}
+
+	ApplyForecastConfidence(hourly, now)
+
+	want := []string{ConfidenceHigh, ConfidenceMedium, ConfidenceLow}
+	for i, w := range want {
+		if hourly[i].Confidence != w {
+			t.Errorf("hourly[%d].Confidence = %q, want %q", i, hourly[i].Confidence, w)
+		}
+	}
+}
+
+func TestApplyForecastConfidence_StaleCachedRows(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	fetchedAt := now.Add(-72 * time.Hour)
+	hourly := []models.WeatherData{
+		// Valid 50h from now, but issued 3 days ago: real lead is ~122h.
+		{Timestamp: now.Add(50 * time.Hour), CreatedAt: fetchedAt},
+		// Valid 5 days from now, issued 3 days ago: real lead is 8 days.
+		{Timestamp: now.Add(5 * 24 * time.Hour), CreatedAt: fetchedAt},
+		// Already past: high regardless of age.
+		{Timestamp: now.Add(-time.Hour), CreatedAt: fetchedAt},
+		// Cached recently: graded from its own fetch time.
+		{Timestamp: now.Add(10 * time.Hour), CreatedAt: now.Add(-time.Hour)},
+	}
+
+	ApplyForecastConfidence(hourly, now)
+
+	want := []string{ConfidenceMedium, ConfidenceLow, ConfidenceHigh, ConfidenceHigh}
+	for i, w := range want {
+		if hourly[i].Confidence != w {
+			t.Errorf("hourly[%d].Confidence = %q, want %q", i, hourly[i].Confidence, w)
+		}
+	}
+}
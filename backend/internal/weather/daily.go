@@ -0,0 +1,73 @@
+package weather
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// AggregateDaily rolls hourly weather up into one WeatherDailyAggregate per
+// local date in loc, sorted by date. Temperature, precipitation, humidity,
+// wind and snow estimate are computed the same way as the stored
+// weather_daily_aggregates rollup, so days built from hourly rows can stand
+// in for stored ones. Sunrise and sunset are left unset. A nil loc means UTC.
+func AggregateDaily(hourly []models.WeatherData, loc *time.Location) []models.WeatherDailyAggregate {
+	if len(hourly) == 0 {
+		return nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	byDay := make(map[string]*models.WeatherDailyAggregate)
+	humiditySum := make(map[string]float64)
+	for _, h := range hourly {
+		date := h.Timestamp.In(loc).Format("2006-01-02")
+		day, ok := byDay[date]
+		if !ok {
+			day = &models.WeatherDailyAggregate{
+				LocationID:     h.LocationID,
+				LocalDate:      date,
+				MinTemperature: h.Temperature,
+				MaxTemperature: h.Temperature,
+			}
+			byDay[date] = day
+		}
+
+		day.MinTemperature = min(day.MinTemperature, h.Temperature)
+		day.MaxTemperature = max(day.MaxTemperature, h.Temperature)
+		day.AvgTemperature += h.Temperature
+		day.TotalPrecipitation += h.Precipitation
+		day.AvgWindSpeed += h.WindSpeed
+		day.SnowEstimateInches += hourlySnowEstimate(h.Temperature, h.Precipitation)
+		day.SourceHourCount++
+		humiditySum[date] += float64(h.Humidity)
+	}
+
+	out := make([]models.WeatherDailyAggregate, 0, len(byDay))
+	for date, day := range byDay {
+		n := float64(day.SourceHourCount)
+		day.AvgTemperature /= n
+		day.AvgWindSpeed /= n
+		day.AvgHumidity = humiditySum[date] / n
+		out = append(out, *day)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].LocalDate < out[j].LocalDate })
+	return out
+}
+
+// hourlySnowEstimate is the snowfall (inches) one hour of precipitation adds
+// at tempF: full 10:1 snow at or below 30°F, none at or above 34°F, and a
+// linear blend in between.
+func hourlySnowEstimate(tempF, precipInches float64) float64 {
+	switch {
+	case tempF <= 30:
+		return precipInches * 10
+	case tempF >= 34:
+		return 0
+	default:
+		return precipInches * ((34 - tempF) / 4) * 10
+	}
+}
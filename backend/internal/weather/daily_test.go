@@ -0,0 +1,74 @@
+package weather
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestAggregateDaily(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("timezone data unavailable")
+	}
+
+	hour := func(day, h int, temp, precip float64, humidity int, wind float64) models.WeatherData {
+		return models.WeatherData{
+			LocationID:    2,
+			Timestamp:     time.Date(2025, 1, day, h, 0, 0, 0, pacific).UTC(),
+			Temperature:   temp,
+			Precipitation: precip,
+			Humidity:      humidity,
+			WindSpeed:     wind,
+		}
+	}
+
+	// Out of order, and 11pm Pacific on the 10th is already the 11th in UTC.
+	hourly := []models.WeatherData{
+		hour(11, 9, 40, 0, 70, 2),
+		hour(10, 23, 28, 0.1, 90, 4),
+		hour(10, 14, 36, 0.2, 80, 6),
+		hour(10, 6, 32, 0.1, 100, 8),
+	}
+
+	days := AggregateDaily(hourly, pacific)
+	if len(days) != 2 {
+		t.Fatalf("AggregateDaily() returned %d days, want 2", len(days))
+	}
+
+	first := days[0]
+	if first.LocalDate != "2025-01-10" || first.LocationID != 2 || first.SourceHourCount != 3 {
+		t.Errorf("first day = %s (location %d, %d hours), want 2025-01-10 (location 2, 3 hours)",
+			first.LocalDate, first.LocationID, first.SourceHourCount)
+	}
+	checks := []struct {
+		name      string
+		got, want float64
+	}{
+		{"min temperature", first.MinTemperature, 28},
+		{"max temperature", first.MaxTemperature, 36},
+		{"avg temperature", first.AvgTemperature, 32},
+		{"total precipitation", first.TotalPrecipitation, 0.4},
+		{"avg humidity", first.AvgHumidity, 90},
+		{"avg wind speed", first.AvgWindSpeed, 6},
+		// 28°F: 0.1*10 = 1.0; 32°F: 0.1*0.5*10 = 0.5; 36°F: 0
+		{"snow estimate", first.SnowEstimateInches, 1.5},
+	}
+	for _, c := range checks {
+		if math.Abs(c.got-c.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	if days[1].LocalDate != "2025-01-11" || days[1].SourceHourCount != 1 {
+		t.Errorf("second day = %s (%d hours), want 2025-01-11 (1 hour)", days[1].LocalDate, days[1].SourceHourCount)
+	}
+}
+
+func TestAggregateDaily_Empty(t *testing.T) {
+	if days := AggregateDaily(nil, nil); days != nil {
+		t.Errorf("AggregateDaily(nil) = %v, want nil", days)
+	}
+}
@@ -0,0 +1,102 @@
+package weather
+
+import (
+	"time"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// DryStreakCriteria decides which forecast days count toward a dry streak.
+type DryStreakCriteria struct {
+	MaxPrecipInches float64 // Daily total must be below this
+	MinHighF        float64 // Daily high must be at least this...
+	MaxHighF        float64 // ...and at most this
+	// MinHours is the fewest forecast hours a day needs to be judged. The
+	// first day is exempt, since the forecast only covers its remaining
+	// hours; a short day anywhere else (usually the end of the forecast)
+	// ends the streak rather than extending it on partial data.
+	MinHours int
+}
+
+// DefaultDryStreakCriteria treats a day as dry when it has under a tenth
+// of an inch of rain in total and a high between 40°F and 90°F.
+var DefaultDryStreakCriteria = DryStreakCriteria{
+	MaxPrecipInches: 0.1,
+	MinHighF:        40,
+	MaxHighF:        90,
+	MinHours:        12,
+}
+
+// FindDryStreaks returns every run of consecutive days from today onward
+// that meet criteria, soonest first. days must be sorted by date, as
+// AggregateDaily returns them; a missing date ends a streak. All runs are
+// returned, so "rain tomorrow, then it clears" still reports the dry spell
+// after the rain.
+func FindDryStreaks(days []models.WeatherDailyAggregate, today string, criteria DryStreakCriteria) []models.DryStreak {
+	streaks := []models.DryStreak{}
+	var current *models.DryStreak
+	prevDate := ""
+
+	for _, day := range days {
+		if day.LocalDate < today {
+			continue
+		}
+
+		if current != nil && !isNextDate(prevDate, day.LocalDate) {
+			streaks = append(streaks, *current)
+			current = nil
+		}
+		prevDate = day.LocalDate
+
+		if !isDryDay(day, day.LocalDate == today, criteria) {
+			if current != nil {
+				streaks = append(streaks, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &models.DryStreak{StartDate: day.LocalDate}
+		}
+		current.EndDate = day.LocalDate
+		current.Days++
+		current.TotalPrecipitation += day.TotalPrecipitation
+	}
+	if current != nil {
+		streaks = append(streaks, *current)
+	}
+
+	return streaks
+}
+
+// LongestDryStreak returns the earliest of the longest streaks, or nil if
+// there are none.
+func LongestDryStreak(streaks []models.DryStreak) *models.DryStreak {
+	var longest *models.DryStreak
+	for i := range streaks {
+		if longest == nil || streaks[i].Days > longest.Days {
+			longest = &streaks[i]
+		}
+	}
+	return longest
+}
+
+func isDryDay(day models.WeatherDailyAggregate, isToday bool, criteria DryStreakCriteria) bool {
+	if !isToday && day.SourceHourCount < criteria.MinHours {
+		return false
+	}
+	return day.TotalPrecipitation < criteria.MaxPrecipInches &&
+		day.MaxTemperature >= criteria.MinHighF &&
+		day.MaxTemperature <= criteria.MaxHighF
+}
+
+// isNextDate reports whether next is the calendar day after prev (both
+// YYYY-MM-DD).
+func isNextDate(prev, next string) bool {
+	p, err := time.Parse("2006-01-02", prev)
+	if err != nil {
+		return false
+	}
+	return p.AddDate(0, 0, 1).Format("2006-01-02") == next
+}
@@ -0,0 +1,65 @@
+package weather
+
+import (
+	"math"
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestFindDryStreaks(t *testing.T) {
+	day := func(date string, high, precip float64, hours int) models.WeatherDailyAggregate {
+		return models.WeatherDailyAggregate{LocalDate: date, MaxTemperature: high, TotalPrecipitation: precip, SourceHourCount: hours}
+	}
+
+	days := []models.WeatherDailyAggregate{
+		day("2026-05-09", 60, 0, 24),   // yesterday: ignored
+		day("2026-05-10", 58, 0, 6),    // today: only the remaining hours, still judged
+		day("2026-05-11", 52, 0.4, 24), // rain tomorrow...
+		day("2026-05-12", 55, 0.05, 24),
+		day("2026-05-13", 61, 0, 24),
+		day("2026-05-14", 64, 0, 24),
+		day("2026-05-15", 95, 0, 24), // too hot
+		day("2026-05-16", 70, 0, 24),
+		day("2026-05-18", 70, 0, 24), // gap after the 16th
+		day("2026-05-19", 70, 0, 5),  // partial last day of the forecast
+	}
+
+	streaks := FindDryStreaks(days, "2026-05-10", DefaultDryStreakCriteria)
+
+	want := []models.DryStreak{
+		{StartDate: "2026-05-10", EndDate: "2026-05-10", Days: 1},
+		{StartDate: "2026-05-12", EndDate: "2026-05-14", Days: 3, TotalPrecipitation: 0.05},
+		{StartDate: "2026-05-16", EndDate: "2026-05-16", Days: 1},
+		{StartDate: "2026-05-18", EndDate: "2026-05-18", Days: 1},
+	}
+	if len(streaks) != len(want) {
+		t.Fatalf("FindDryStreaks() returned %d streaks, want %d: %+v", len(streaks), len(want), streaks)
+	}
+	for i, w := range want {
+		got := streaks[i]
+		if got.StartDate != w.StartDate || got.EndDate != w.EndDate || got.Days != w.Days ||
+			math.Abs(got.TotalPrecipitation-w.TotalPrecipitation) > 1e-9 {
+			t.Errorf("streak %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	longest := LongestDryStreak(streaks)
+	if longest == nil || longest.StartDate != "2026-05-12" {
+		t.Errorf("LongestDryStreak() = %+v, want the streak starting 2026-05-12", longest)
+	}
+}
+
+func TestFindDryStreaks_NoneQualify(t *testing.T) {
+	days := []models.WeatherDailyAggregate{
+		{LocalDate: "2026-05-10", MaxTemperature: 50, TotalPrecipitation: 0.8, SourceHourCount: 24},
+	}
+
+	streaks := FindDryStreaks(days, "2026-05-10", DefaultDryStreakCriteria)
+	if streaks == nil || len(streaks) != 0 {
+		t.Errorf("FindDryStreaks() = %#v, want an empty slice", streaks)
+	}
+	if LongestDryStreak(streaks) != nil {
+		t.Error("LongestDryStreak() of no streaks should be nil")
+	}
+}
@@ -0,0 +1,61 @@
+package weather
+
+import (
+	"errors"
+	"sync"
+)
+
+// errFlightPanicked is what callers waiting on a call get if it panicked.
+var errFlightPanicked = errors.New("in-flight call panicked")
+
+// flightGroup collapses concurrent calls with the same key into one: the
+// first caller runs fn and later callers with that key wait for it and get
+// its result. Once the call returns the key is forgotten, so this only
+// de-duplicates calls that overlap in time; it is not a cache.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done    chan struct{}
+	val     T
+	err     error
+	waiters int // Callers sharing this call's result; guarded by flightGroup.mu
+}
+
+// do runs fn for key unless a call for key is already in flight, in which
+// case it waits for that call. shared reports whether the result came from
+// another caller's call. The value is shared, so callers that modify it
+// must copy it first.
+func (g *flightGroup[T]) do(key string, fn func() (T, error)) (val T, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.waiters++
+		g.mu.Unlock()
+		<-c.done
+		return c.val, c.err, true
+	}
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	c := &flightCall[T]{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	// Release waiters even if fn panics; the panic still propagates here.
+	completed := false
+	defer func() {
+		if !completed {
+			c.err = errFlightPanicked
+		}
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+
+	c.val, c.err = fn()
+	completed = true
+	return c.val, c.err, false
+}
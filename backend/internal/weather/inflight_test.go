@@ -0,0 +1,106 @@
+package weather
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestFlightGroup_CollapsesConcurrentCalls(t *testing.T) {
+	var g flightGroup[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	started := make(chan struct{})
+
+	fn := func() (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]int, callers)
+	var sharedCount atomic.Int32
+
+	// Start the first call and wait until it is in flight, then pile on.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _, _ = g.do("47.6,-121.6", fn)
+	}()
+	<-started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var shared bool
+			results[i], _, shared = g.do("47.6,-121.6", fn)
+			if shared {
+				sharedCount.Add(1)
+			}
+		}(i)
+	}
+
+	// Let every waiter join the call before it returns.
+	for {
+		g.mu.Lock()
+		waiters := g.calls["47.6,-121.6"].waiters
+		g.mu.Unlock()
+		if waiters == callers-1 {
+			break
+		}
+		runtime.Gosched()
+	}
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fn ran %d times, want 1", got)
+	}
+	if got := sharedCount.Load(); got != callers-1 {
+		t.Errorf("%d callers shared the result, want %d", got, callers-1)
+	}
+	for i, r := range results {
+		if r != 42 {
+			t.Errorf("caller %d got %d, want 42", i, r)
+		}
+	}
+}
+
+func TestFlightGroup_SeparateKeysAndSequentialCalls(t *testing.T) {
+	var g flightGroup[string]
+	calls := 0
+	fn := func() (string, error) {
+		calls++
+		return "ok", nil
+	}
+
+	g.do("a", fn)
+	g.do("b", fn)
+	_, _, shared := g.do("a", fn)
+
+	if calls != 3 {
+		t.Errorf("fn ran %d times, want 3 (results are not cached)", calls)
+	}
+	if shared {
+		t.Error("a sequential call should not report a shared result")
+	}
+}
+
+func TestFlightGroup_SharesErrors(t *testing.T) {
+	var g flightGroup[int]
+	wantErr := errors.New("upstream 429")
+
+	_, err, _ := g.do("k", func() (int, error) { return 0, wantErr })
+	if !errors.Is(err, wantErr) {
+		t.Errorf("do() error = %v, want %v", err, wantErr)
+	}
+	if len(g.calls) != 0 {
+		t.Errorf("finished call left %d keys in flight", len(g.calls))
+	}
+}
@@ -0,0 +1,90 @@
+package weather
+
+import (
+	"strings"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+// DefaultRainThresholdInches is the hourly precipitation at or above which
+// precipitation counts as falling. Smaller amounts are trace readings or dew.
+const DefaultRainThresholdInches = 0.01
+
+// freezingF is the air temperature at or below which precipitation without a
+// weather code is assumed to be snow.
+const freezingF = 32.0
+
+// precipKind classifies what a weather code says is falling.
+type precipKind int
+
+const (
+	precipUnknown precipKind = iota // no weather code recorded
+	precipNone                      // clear, cloudy, or fog
+	precipRain                      // drizzle, rain, showers, thunderstorm
+	precipSnow                      // snow, snow grains, snow showers
+)
+
+// precipKindFromIcon reads the weather code from the OpenWeatherMap-style
+// icon both providers are normalized to (e.g. "10d", "13n").
+func precipKindFromIcon(icon string) precipKind {
+	if len(icon) < 2 {
+		return precipUnknown
+	}
+	switch icon[:2] {
+	case "09", "10", "11":
+		return precipRain
+	case "13":
+		return precipSnow
+	default:
+		return precipNone
+	}
+}
+
+// IsPrecipitating reports whether w is raining and/or snowing. It takes both
+// the measured amount and the weather code: the amount must reach
+// thresholdInches and the code must describe precipitation, so trace amounts
+// and dew under clear or cloudy skies do not count. When no code is recorded
+// the hour's PrecipType decides, then the air temperature if that is unknown.
+// A non-positive threshold uses DefaultRainThresholdInches.
+func IsPrecipitating(w *models.WeatherData, thresholdInches float64) (raining, snowing bool) {
+	if w == nil {
+		return false, false
+	}
+	if thresholdInches <= 0 {
+		thresholdInches = DefaultRainThresholdInches
+	}
+	if w.Precipitation < thresholdInches {
+		return false, false
+	}
+
+	switch precipKindFromIcon(strings.TrimSpace(w.Icon)) {
+	case precipRain:
+		return true, false
+	case precipSnow:
+		return false, true
+	case precipUnknown:
+		switch w.PrecipType {
+		case models.PrecipTypeRain:
+			return true, false
+		case models.PrecipTypeSnow:
+			return false, true
+		case models.PrecipTypeMixed:
+			return true, true
+		}
+		if w.Temperature <= freezingF {
+			return false, true
+		}
+		return true, false
+	default:
+		return false, false
+	}
+}
+
+// ApplyPrecipitationState sets IsRaining and IsSnowing on w (see
+// IsPrecipitating).
+func ApplyPrecipitationState(w *models.WeatherData, thresholdInches float64) {
+	if w == nil {
+		return
+	}
+	w.IsRaining, w.IsSnowing = IsPrecipitating(w, thresholdInches)
+}
@@ -0,0 +1,51 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestIsPrecipitating(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        models.WeatherData
+		threshold   float64
+		wantRaining bool
+		wantSnowing bool
+	}{
+		{"rain", models.WeatherData{Precipitation: 0.1, Icon: "10d", Temperature: 50}, 0.01, true, false},
+		{"drizzle at threshold", models.WeatherData{Precipitation: 0.01, Icon: "09n", Temperature: 50}, 0.01, true, false},
+		{"thunderstorm", models.WeatherData{Precipitation: 0.3, Icon: "11d", Temperature: 70}, 0.01, true, false},
+		{"snow", models.WeatherData{Precipitation: 0.05, Icon: "13d", Temperature: 28}, 0.01, false, true},
+		{"trace rain", models.WeatherData{Precipitation: 0.004, Icon: "10d", Temperature: 50}, 0.01, false, false},
+		{"dew under clear sky", models.WeatherData{Precipitation: 0.02, Icon: "01n", Temperature: 45}, 0.01, false, false},
+		{"below custom threshold", models.WeatherData{Precipitation: 0.03, Icon: "10d", Temperature: 50}, 0.05, false, false},
+		{"no code warm", models.WeatherData{Precipitation: 0.1, Temperature: 40}, 0.01, true, false},
+		{"no code freezing", models.WeatherData{Precipitation: 0.1, Temperature: 30}, 0.01, false, true},
+		{"no code, snow type when warm", models.WeatherData{Precipitation: 0.1, Temperature: 36, PrecipType: models.PrecipTypeSnow}, 0.01, false, true},
+		{"no code, rain type when freezing", models.WeatherData{Precipitation: 0.1, Temperature: 30, PrecipType: models.PrecipTypeRain}, 0.01, true, false},
+		{"no code, mixed type", models.WeatherData{Precipitation: 0.1, Temperature: 33, PrecipType: models.PrecipTypeMixed}, 0.01, true, true},
+		{"code beats type", models.WeatherData{Precipitation: 0.1, Icon: "10d", Temperature: 30, PrecipType: models.PrecipTypeSnow}, 0.01, true, false},
+		{"non-positive threshold uses default", models.WeatherData{Precipitation: 0.005, Icon: "10d"}, 0, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raining, snowing := IsPrecipitating(&tt.data, tt.threshold)
+			if raining != tt.wantRaining || snowing != tt.wantSnowing {
+				t.Errorf("IsPrecipitating() = (%v, %v), want (%v, %v)", raining, snowing, tt.wantRaining, tt.wantSnowing)
+			}
+		})
+	}
+}
+
+func TestApplyPrecipitationState(t *testing.T) {
+	w := &models.WeatherData{Precipitation: 0.2, Icon: "13n", Temperature: 25}
+	ApplyPrecipitationState(w, DefaultRainThresholdInches)
+	if w.IsRaining || !w.IsSnowing {
+		t.Errorf("got IsRaining=%v IsSnowing=%v, want snowing only", w.IsRaining, w.IsSnowing)
+	}
+
+	ApplyPrecipitationState(nil, DefaultRainThresholdInches) // must not panic
+}
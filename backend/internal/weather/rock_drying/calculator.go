@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
+	"github.com/alexscott64/woulder/backend/internal/weather/calculator"
 )
 
 // rainEventThresholdInches is the minimum hourly precipitation (in inches) that
@@ -311,70 +312,14 @@ func (c *Calculator) handleCurrentRain(
 
 // findLastRainEvent finds the most recent rain event and calculates its characteristics
 func findLastRainEvent(historical []models.WeatherData, current *models.WeatherData) *models.RainEvent {
-	if len(historical) == 0 && current == nil {
-		return nil
-	}
-
-	var event *models.RainEvent
-	var totalRain float64
-	var maxRate float64
-	var rainHours int
-	var startTime, endTime time.Time
-
-	// Check current weather first (if it's raining NOW)
-	if current != nil && current.Precipitation >= rainEventThresholdInches {
-		event = &models.RainEvent{
-			StartTime: current.Timestamp,
-			EndTime:   current.Timestamp,
-		}
-		endTime = current.Timestamp
-		startTime = current.Timestamp
-		totalRain = current.Precipitation
-		rainHours = 1
-		maxRate = current.Precipitation
+	data := historical
+	if current != nil {
+		data = append(append([]models.WeatherData(nil), historical...), *current)
 	}
 
-	// Check historical data (reversed to go from most recent to oldest)
-	for i := len(historical) - 1; i >= 0; i-- {
-		h := historical[i]
-		if h.Precipitation >= rainEventThresholdInches {
-			// Part of rain event
-			if event == nil {
-				// Start of new rain event
-				event = &models.RainEvent{
-					StartTime: h.Timestamp,
-					EndTime:   h.Timestamp,
-				}
-				endTime = h.Timestamp
-			}
-			startTime = h.Timestamp
-			totalRain += h.Precipitation
-			rainHours++
-
-			// Track max hourly rate
-			if h.Precipitation > maxRate {
-				maxRate = h.Precipitation
-			}
-		} else if event != nil {
-			// End of rain event (found dry period)
-			break
-		}
-	}
-
-	if event == nil {
+	events := calculator.DetectRainEvents(data, rainEventThresholdInches, 0)
+	if len(events) == 0 {
 		return nil
 	}
-
-	// Finalize rain event
-	event.StartTime = startTime
-	event.EndTime = endTime
-	event.TotalRain = totalRain
-	event.Duration = endTime.Sub(startTime).Hours()
-	event.MaxHourlyRate = maxRate
-
-	if rainHours > 0 {
-		event.AvgHourlyRate = totalRain / float64(rainHours)
-	}
-
-	return event
+	return &events[len(events)-1]
 }
@@ -1,6 +1,7 @@
 package weather
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/alexscott64/woulder/backend/internal/models"
@@ -11,7 +12,27 @@ import (
 type WeatherService struct {
 	openMeteo       *client.OpenMeteoClient
 	openWeatherMap  *client.OpenWeatherMapClient
+	nws             *client.NWSClient // nil until EnableAlerts
 	preferOpenMeteo bool
+
+	// Concurrent identical fetches (same coordinates and window) share one
+	// upstream request, so a burst of cache misses for a location doesn't
+	// fan out into a burst of identical API calls.
+	currentAndForecastCalls flightGroup[currentAndForecast]
+	forecastCalls           flightGroup[[]models.WeatherData]
+	historicalCalls         flightGroup[[]models.WeatherData]
+}
+
+// currentAndForecast is the result of one GetCurrentAndForecast fetch.
+type currentAndForecast struct {
+	current  *models.WeatherData
+	forecast []models.WeatherData
+	sunTimes *client.SunTimes
+}
+
+// fetchKey identifies an upstream fetch for de-duplication.
+func fetchKey(lat, lon float64, days ...int) string {
+	return fmt.Sprintf("%.6f,%.6f/%v", lat, lon, days)
 }
 
 // NewWeatherService creates a new weather service with both providers
@@ -23,10 +44,47 @@ func NewWeatherService(openWeatherMapAPIKey string) *WeatherService {
 	}
 }
 
-// GetCurrentAndForecast fetches both current weather and forecast in a single API call
-func (s *WeatherService) GetCurrentAndForecast(lat, lon float64) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
+// EnableAlerts turns on active-alert lookups through the National Weather
+// Service, which requires a User-Agent identifying the app and a contact.
+func (s *WeatherService) EnableAlerts(userAgent string) {
+	s.nws = client.NewNWSClient(userAgent)
+}
+
+// GetActiveAlerts fetches weather alerts in effect at a point. It returns no
+// alerts and no error when alerts are not enabled.
+func (s *WeatherService) GetActiveAlerts(lat, lon float64) ([]models.WeatherAlert, error) {
+	if s.nws == nil {
+		return nil, nil
+	}
+	return s.nws.GetActiveAlerts(lat, lon)
+}
+
+// GetSunTimes fetches daily sunrise/sunset for the next `days` days. Only
+// Open-Meteo provides this, so there is no fallback provider.
+func (s *WeatherService) GetSunTimes(lat, lon float64, days int) (*client.SunTimes, error) {
+	return s.openMeteo.GetSunTimes(lat, lon, days)
+}
+
+// GetCurrentAndForecast fetches both current weather and forecast in a single
+// API call. See OpenMeteoClient.GetCurrentAndForecast for the day counts.
+//
+// Concurrent calls for the same coordinates and days share one fetch; each
+// caller gets its own copy of the current and forecast data.
+func (s *WeatherService) GetCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
+	result, err, _ := s.currentAndForecastCalls.do(fetchKey(lat, lon, forecastDays, pastDays), func() (currentAndForecast, error) {
+		current, forecast, sunTimes, err := s.fetchCurrentAndForecast(lat, lon, forecastDays, pastDays)
+		return currentAndForecast{current: current, forecast: forecast, sunTimes: sunTimes}, err
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	current := *result.current
+	return &current, cloneWeatherData(result.forecast), result.sunTimes, nil
+}
+
+func (s *WeatherService) fetchCurrentAndForecast(lat, lon float64, forecastDays, pastDays int) (*models.WeatherData, []models.WeatherData, *client.SunTimes, error) {
 	if s.preferOpenMeteo {
-		current, forecast, sunTimes, err := s.openMeteo.GetCurrentAndForecast(lat, lon)
+		current, forecast, sunTimes, err := s.openMeteo.GetCurrentAndForecast(lat, lon, forecastDays, pastDays)
 		if err == nil {
 			log.Printf("Successfully fetched current + forecast from Open-Meteo for (%.6f, %.6f) - %d hours", lat, lon, len(forecast))
 			return current, forecast, sunTimes, nil
@@ -39,7 +97,7 @@ func (s *WeatherService) GetCurrentAndForecast(lat, lon float64) (*models.Weathe
 	if err != nil {
 		return nil, nil, nil, err
 	}
-	forecast, err := s.GetForecast(lat, lon)
+	forecast, err := s.GetForecast(lat, lon, forecastDays)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -65,10 +123,18 @@ func (s *WeatherService) GetCurrentWeather(lat, lon float64) (*models.WeatherDat
 	return data, nil
 }
 
-// GetForecast fetches forecast with fallback
-func (s *WeatherService) GetForecast(lat, lon float64) ([]models.WeatherData, error) {
+// GetForecast fetches the next days days of forecast with fallback.
+// Concurrent identical calls share one fetch.
+func (s *WeatherService) GetForecast(lat, lon float64, days int) ([]models.WeatherData, error) {
+	data, err, _ := s.forecastCalls.do(fetchKey(lat, lon, days), func() ([]models.WeatherData, error) {
+		return s.fetchForecast(lat, lon, days)
+	})
+	return cloneWeatherData(data), err
+}
+
+func (s *WeatherService) fetchForecast(lat, lon float64, days int) ([]models.WeatherData, error) {
 	if s.preferOpenMeteo {
-		data, err := s.openMeteo.GetForecast(lat, lon)
+		data, err := s.openMeteo.GetForecast(lat, lon, days)
 		if err == nil {
 			log.Printf("Successfully fetched forecast from Open-Meteo for (%.6f, %.6f) - %d hours", lat, lon, len(data))
 			return data, nil
@@ -76,7 +142,7 @@ func (s *WeatherService) GetForecast(lat, lon float64) ([]models.WeatherData, er
 		log.Printf("Open-Meteo failed for forecast (%.6f, %.6f): %v, falling back to OpenWeatherMap", lat, lon, err)
 	}
 
-	data, err := s.openWeatherMap.GetForecast(lat, lon)
+	data, err := s.openWeatherMap.GetForecast(lat, lon, days)
 	if err != nil {
 		return nil, err
 	}
@@ -84,8 +150,16 @@ func (s *WeatherService) GetForecast(lat, lon float64) ([]models.WeatherData, er
 	return data, nil
 }
 
-// GetHistoricalWeather fetches historical weather (Open-Meteo only, no fallback needed)
+// GetHistoricalWeather fetches historical weather (Open-Meteo only, no fallback needed).
+// Concurrent identical calls share one fetch.
 func (s *WeatherService) GetHistoricalWeather(lat, lon float64, days int) ([]models.WeatherData, error) {
+	data, err, _ := s.historicalCalls.do(fetchKey(lat, lon, days), func() ([]models.WeatherData, error) {
+		return s.fetchHistoricalWeather(lat, lon, days)
+	})
+	return cloneWeatherData(data), err
+}
+
+func (s *WeatherService) fetchHistoricalWeather(lat, lon float64, days int) ([]models.WeatherData, error) {
 	// Open-Meteo has true historical data, so we use it exclusively for this
 	data, err := s.openMeteo.GetHistoricalWeather(lat, lon, days)
 	if err != nil {
@@ -95,3 +169,12 @@ func (s *WeatherService) GetHistoricalWeather(lat, lon float64, days int) ([]mod
 	log.Printf("Successfully fetched historical weather from Open-Meteo for (%.6f, %.6f) - %d hours", lat, lon, len(data))
 	return data, nil
 }
+
+// cloneWeatherData copies a shared fetch result so each caller can modify
+// its own rows.
+func cloneWeatherData(data []models.WeatherData) []models.WeatherData {
+	if data == nil {
+		return nil
+	}
+	return append([]models.WeatherData(nil), data...)
+}
@@ -0,0 +1,127 @@
+package sun
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+const (
+	// dominantAspectMinPercent is the share a single face needs before the
+	// location is described by that face rather than as "mixed".
+	dominantAspectMinPercent = 40.0
+
+	// Shade score cut-offs for ShadeRating.
+	sunnyMaxShadeScore = 35
+	shadyMinShadeScore = 60
+)
+
+// SourceSunExposureProfile is the SunExposureSummary.Source for summaries
+// built from the curated location_sun_exposure profile.
+//
+// Mountain Project boulder aspects are deliberately not used as a fallback:
+// mp_routes.aspect is derived from a boulder's position within its cluster
+// (and defaults to "S" for lone boulders), not from which way the rock faces.
+const SourceSunExposureProfile = "sun_exposure_profile"
+
+// faceSunWeight is how much direct sun each face receives relative to a
+// south face in the northern hemisphere (S=1, E/W=0.5, N=0).
+var faceSunWeight = map[string]float64{
+	"N": 0.0,
+	"E": 0.5,
+	"S": 1.0,
+	"W": 0.5,
+}
+
+// SummarizeExposureProfile derives a location-level aspect and shade rating
+// from a curated sun exposure profile. Tree coverage pushes the shade score
+// toward full shade. Returns nil when se is nil or has no face data.
+func SummarizeExposureProfile(se *models.LocationSunExposure) *models.SunExposureSummary {
+	if se == nil {
+		return nil
+	}
+
+	faces := map[string]float64{
+		"N": se.NorthFacingPercent,
+		"E": se.EastFacingPercent,
+		"S": se.SouthFacingPercent,
+		"W": se.WestFacingPercent,
+	}
+
+	summary := summarizeFaces(faces, se.TreeCoveragePercent)
+	if summary == nil {
+		return nil
+	}
+	summary.Source = SourceSunExposureProfile
+	return summary
+}
+
+// summarizeFaces builds the summary from N/E/S/W percentages (not required
+// to sum to 100) and a 0-100 tree coverage percentage.
+func summarizeFaces(faces map[string]float64, treeCoveragePercent float64) *models.SunExposureSummary {
+	total := 0.0
+	for _, pct := range faces {
+		if pct > 0 {
+			total += pct
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	// Pick the largest face; iterate in a fixed order so ties are stable.
+	dominant, dominantPct := "", 0.0
+	sunFraction := 0.0
+	for _, face := range []string{"S", "W", "E", "N"} {
+		pct := math.Max(faces[face], 0) / total * 100
+		if pct > dominantPct {
+			dominant, dominantPct = face, pct
+		}
+		sunFraction += pct / 100 * faceSunWeight[face]
+	}
+	if dominantPct < dominantAspectMinPercent {
+		dominant = "mixed"
+	}
+
+	// Trees shade whatever the aspect leaves exposed.
+	tree := math.Max(0, math.Min(treeCoveragePercent, 100)) / 100
+	shade := (1 - sunFraction) + sunFraction*tree
+	shadeScore := int(math.Round(shade * 100))
+
+	rating := "mixed"
+	switch {
+	case shadeScore <= sunnyMaxShadeScore:
+		rating = "sunny"
+	case shadeScore >= shadyMinShadeScore:
+		rating = "shady"
+	}
+
+	return &models.SunExposureSummary{
+		DominantAspect:        dominant,
+		DominantAspectPercent: math.Round(dominantPct*10) / 10,
+		ShadeScore:            shadeScore,
+		ShadeRating:           rating,
+		Description:           describeExposure(dominant, rating),
+	}
+}
+
+// describeExposure returns a short user-facing description, e.g.
+// "Mostly south-facing, sunny - good cold-weather choice".
+func describeExposure(dominant, rating string) string {
+	names := map[string]string{"N": "north", "E": "east", "S": "south", "W": "west"}
+
+	facing := "Mixed aspects"
+	if name, ok := names[dominant]; ok {
+		facing = fmt.Sprintf("Mostly %s-facing", name)
+	}
+
+	switch rating {
+	case "sunny":
+		return facing + ", sunny - good cold-weather choice"
+	case "shady":
+		return facing + ", shady - good hot-weather choice"
+	default:
+		return facing + ", mix of sun and shade"
+	}
+}
@@ -0,0 +1,78 @@
+package sun
+
+import (
+	"testing"
+
+	"github.com/alexscott64/woulder/backend/internal/models"
+)
+
+func TestSummarizeExposureProfile_SouthFacingOpen(t *testing.T) {
+	se := &models.LocationSunExposure{
+		SouthFacingPercent: 70,
+		WestFacingPercent:  15,
+		EastFacingPercent:  10,
+		NorthFacingPercent: 5,
+	}
+
+	summary := SummarizeExposureProfile(se)
+	if summary == nil {
+		t.Fatal("expected summary, got nil")
+	}
+
+	if summary.DominantAspect != "S" {
+		t.Errorf("DominantAspect = %q, want S", summary.DominantAspect)
+	}
+	if summary.ShadeRating != "sunny" {
+		t.Errorf("ShadeRating = %q, want sunny (score %d)", summary.ShadeRating, summary.ShadeScore)
+	}
+	if summary.Source != SourceSunExposureProfile {
+		t.Errorf("Source = %q, want %q", summary.Source, SourceSunExposureProfile)
+	}
+}
+
+func TestSummarizeExposureProfile_TreesMakeItShady(t *testing.T) {
+	// Same aspects as the open south-facing crag, but under heavy forest.
+	se := &models.LocationSunExposure{
+		SouthFacingPercent:  70,
+		WestFacingPercent:   15,
+		EastFacingPercent:   10,
+		NorthFacingPercent:  5,
+		TreeCoveragePercent: 80,
+	}
+
+	summary := SummarizeExposureProfile(se)
+	if summary == nil {
+		t.Fatal("expected summary, got nil")
+	}
+
+	if summary.ShadeRating != "shady" {
+		t.Errorf("ShadeRating = %q, want shady (score %d)", summary.ShadeRating, summary.ShadeScore)
+	}
+}
+
+func TestSummarizeExposureProfile_Mixed(t *testing.T) {
+	se := &models.LocationSunExposure{
+		SouthFacingPercent: 30,
+		WestFacingPercent:  25,
+		EastFacingPercent:  25,
+		NorthFacingPercent: 20,
+	}
+
+	summary := SummarizeExposureProfile(se)
+	if summary == nil {
+		t.Fatal("expected summary, got nil")
+	}
+
+	if summary.DominantAspect != "mixed" {
+		t.Errorf("DominantAspect = %q, want mixed", summary.DominantAspect)
+	}
+}
+
+func TestSummarizeExposureProfile_NoData(t *testing.T) {
+	if got := SummarizeExposureProfile(nil); got != nil {
+		t.Errorf("SummarizeExposureProfile(nil) = %+v, want nil", got)
+	}
+	if got := SummarizeExposureProfile(&models.LocationSunExposure{}); got != nil {
+		t.Errorf("SummarizeExposureProfile(empty) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,138 @@
+package sun
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	// shadeClockStep is the sampling interval for ComputeShadeClock.
+	shadeClockStep = 10 * time.Minute
+
+	// maxSunIncidence is the largest angle between the sun's azimuth and
+	// the face's aspect at which the face counts as sunlit. Matches the
+	// ±90° half-plane used by CalculateSunExposure.
+	maxSunIncidence = 90.0
+
+	// sunEdgeTolerance is how close an interval must start to sunrise (or
+	// end at sunset) to be described as "from sunrise" / "until sunset".
+	sunEdgeTolerance = 30 * time.Minute
+)
+
+// SunInterval is a stretch of the day when a face is in direct sun.
+type SunInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ShadeClock is a heuristic sun/shade timeline for one face over one day.
+// It considers only the sun's position relative to the face's aspect; terrain,
+// trees, and the face's steepness are ignored.
+type ShadeClock struct {
+	Date         string        `json:"date"` // YYYY-MM-DD in the location's timezone
+	Aspect       string        `json:"aspect"`
+	Sunrise      time.Time     `json:"sunrise"`
+	Sunset       time.Time     `json:"sunset"`
+	SunIntervals []SunInterval `json:"sun_intervals"`
+	SunHours     float64       `json:"sun_hours"`
+	Summary      string        `json:"summary"` // e.g. "Shaded until 1pm, then sun"
+}
+
+// ComputeShadeClock estimates when a face with the given aspect is in sun on
+// the local calendar day containing day (in loc). The sun's position is
+// sampled every shadeClockStep; the face is sunlit while the sun is above the
+// horizon and within maxSunIncidence of the aspect. Unknown aspects are
+// treated as south (see aspectToDegrees).
+func ComputeShadeClock(lat, lon float64, aspect string, day time.Time, loc *time.Location) *ShadeClock {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := day.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 0, 1)
+	aspectDeg := aspectToDegrees(aspect)
+
+	clock := &ShadeClock{
+		Date:         start.Format("2006-01-02"),
+		Aspect:       aspect,
+		SunIntervals: []SunInterval{},
+	}
+
+	var current *SunInterval
+	for t := start; t.Before(end); t = t.Add(shadeClockStep) {
+		pos := Calculate(lat, lon, t)
+		if pos.IsAboveHorizon() {
+			if clock.Sunrise.IsZero() {
+				clock.Sunrise = t
+			}
+			clock.Sunset = t.Add(shadeClockStep)
+		}
+
+		lit := pos.IsAboveHorizon() && angleDifference(pos.Azimuth, aspectDeg) <= maxSunIncidence
+		switch {
+		case lit && current == nil:
+			current = &SunInterval{Start: t}
+		case !lit && current != nil:
+			current.End = t
+			clock.SunIntervals = append(clock.SunIntervals, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		current.End = end
+		clock.SunIntervals = append(clock.SunIntervals, *current)
+	}
+
+	var sunTime time.Duration
+	for _, iv := range clock.SunIntervals {
+		sunTime += iv.End.Sub(iv.Start)
+	}
+	clock.SunHours = math.Round(sunTime.Hours()*10) / 10
+	clock.Summary = summarizeShadeClock(clock)
+	return clock
+}
+
+// summarizeShadeClock describes a shade clock in a short phrase such as
+// "Sun until 11am, then shaded" or "Sun 5am–8am and 6pm–9pm".
+func summarizeShadeClock(c *ShadeClock) string {
+	if c.Sunrise.IsZero() {
+		return "No sun today"
+	}
+	if len(c.SunIntervals) == 0 {
+		return "Shaded all day"
+	}
+
+	if len(c.SunIntervals) == 1 {
+		iv := c.SunIntervals[0]
+		fromSunrise := iv.Start.Sub(c.Sunrise) <= sunEdgeTolerance
+		untilSunset := c.Sunset.Sub(iv.End) <= sunEdgeTolerance
+		switch {
+		case fromSunrise && untilSunset:
+			return "Sun all day"
+		case fromSunrise:
+			return fmt.Sprintf("Sun until %s, then shaded", clockTime(iv.End))
+		case untilSunset:
+			return fmt.Sprintf("Shaded until %s, then sun", clockTime(iv.Start))
+		default:
+			return fmt.Sprintf("Sun %s–%s", clockTime(iv.Start), clockTime(iv.End))
+		}
+	}
+
+	ranges := make([]string, len(c.SunIntervals))
+	for i, iv := range c.SunIntervals {
+		ranges[i] = clockTime(iv.Start) + "–" + clockTime(iv.End)
+	}
+	return "Sun " + strings.Join(ranges, " and ")
+}
+
+// clockTime formats t as a short 12-hour time ("1pm", "10:30am"), rounded
+// to the nearest half hour since the model is only approximate.
+func clockTime(t time.Time) string {
+	t = t.Round(30 * time.Minute)
+	if t.Minute() == 0 {
+		return t.Format("3pm")
+	}
+	return t.Format("3:04pm")
+}
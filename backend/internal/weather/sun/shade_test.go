@@ -0,0 +1,91 @@
+package sun
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeShadeClock(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// Gold Bar, WA
+	const lat, lon = 47.85, -121.69
+	summer := time.Date(2026, 6, 21, 12, 0, 0, 0, pacific)
+	winter := time.Date(2026, 12, 21, 12, 0, 0, 0, pacific)
+
+	tests := []struct {
+		name          string
+		aspect        string
+		day           time.Time
+		wantIntervals int
+		wantSummary   string
+		minHours      float64
+		maxHours      float64
+	}{
+		{"east face summer", "E", summer, 1, "Sun until 1pm, then shaded", 7, 9},
+		{"west face summer", "W", summer, 1, "Shaded until 1pm, then sun", 7, 9},
+		{"south face summer", "S", summer, 1, "Sun 9am–5:30pm", 8, 10},
+		{"north face summer", "N", summer, 2, "Sun 5:30am–9am and 5:30pm–9pm", 6, 8},
+		{"south face winter", "S", winter, 1, "Sun all day", 8, 9},
+		{"north face winter", "N", winter, 0, "Shaded all day", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := ComputeShadeClock(lat, lon, tt.aspect, tt.day, pacific)
+
+			if c.Date != tt.day.Format("2006-01-02") {
+				t.Errorf("Date = %s", c.Date)
+			}
+			if len(c.SunIntervals) != tt.wantIntervals {
+				t.Fatalf("got %d intervals, want %d: %v", len(c.SunIntervals), tt.wantIntervals, c.SunIntervals)
+			}
+			if c.Summary != tt.wantSummary {
+				t.Errorf("Summary = %q, want %q", c.Summary, tt.wantSummary)
+			}
+			if c.SunHours < tt.minHours || c.SunHours > tt.maxHours {
+				t.Errorf("SunHours = %.1f, want %.0f-%.0f", c.SunHours, tt.minHours, tt.maxHours)
+			}
+			for _, iv := range c.SunIntervals {
+				if iv.Start.Before(c.Sunrise) || iv.End.After(c.Sunset) {
+					t.Errorf("interval %v outside daylight %v-%v", iv, c.Sunrise, c.Sunset)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeShadeClock_Daylight(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	c := ComputeShadeClock(47.85, -121.69, "S", time.Date(2026, 6, 21, 3, 0, 0, 0, pacific), pacific)
+
+	// Sunrise ~5:11am, sunset ~9:10pm PDT; sampling is every 10 minutes
+	if c.Sunrise.Hour() != 5 || c.Sunset.Hour() != 21 {
+		t.Errorf("sunrise/sunset = %s / %s", c.Sunrise.Format("15:04"), c.Sunset.Format("15:04"))
+	}
+	if c.Sunrise.Location() != pacific {
+		t.Errorf("times should be in the location's timezone, got %v", c.Sunrise.Location())
+	}
+}
+
+func TestClockTime(t *testing.T) {
+	tests := []struct {
+		in   time.Time
+		want string
+	}{
+		{time.Date(2026, 6, 21, 13, 0, 0, 0, time.UTC), "1pm"},
+		{time.Date(2026, 6, 21, 13, 10, 0, 0, time.UTC), "1pm"},
+		{time.Date(2026, 6, 21, 9, 20, 0, 0, time.UTC), "9:30am"},
+		{time.Date(2026, 6, 21, 0, 5, 0, 0, time.UTC), "12am"},
+	}
+	for _, tt := range tests {
+		if got := clockTime(tt.in); got != tt.want {
+			t.Errorf("clockTime(%s) = %q, want %q", tt.in.Format("15:04"), got, tt.want)
+		}
+	}
+}
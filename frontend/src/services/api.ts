@@ -1,5 +1,5 @@
 import axios from 'axios';
-import { Location, WeatherForecast, AllWeatherResponse, AreaActivitySummary, RouteActivitySummary, ClimbHistoryEntry, SearchResult, BoulderDryingStatus, AreaDryingStats, KayaAscentEntry, UnifiedRouteActivitySummary } from '../types/weather';
+import { Location, WeatherForecast, AllWeatherResponse, AreaActivitySummary, AreaTreeResponse, UserTickSyncResult, UserTickedRoutesResponse, RouteActivitySummary, ClimbHistoryEntry, SearchResult, BoulderDryingStatus, AreaDryingStats, DailySunTimes, ConditionsHistory, KayaAscentEntry, KayaRouteMatch, DiscoveredRoute, FeaturedRoute, SimilarRoute, UnifiedRouteActivitySummary } from '../types/weather';
 import { Area, AreaWithLocations } from '../types/area';
 import { HeatMapActivityResponse, AreaActivityDetail, RoutesResponse, RouteTicksResponse, GeoBounds } from '../types/heatmap';
 
@@ -17,12 +17,34 @@ export const weatherApi = {
     return response.data;
   },
 
+  // Get a single location, including Kaya access/approach beta when available
+  getLocation: async (locationId: number): Promise<Location> => {
+    const response = await api.get(`/locations/${locationId}`);
+    return response.data;
+  },
+
   // Get weather for specific location
   getWeatherForLocation: async (locationId: number): Promise<WeatherForecast> => {
     const response = await api.get(`/weather/${locationId}`);
     return response.data;
   },
 
+  // Get daily sunrise/sunset for a location without the full forecast (1-16 days)
+  getSunTimesForLocation: async (locationId: number, days = 1): Promise<DailySunTimes[]> => {
+    const response = await api.get(`/locations/${locationId}/suntimes`, {
+      params: { days }
+    });
+    return response.data;
+  },
+
+  // Get daily weather paired with daily tick counts for the last N days (1-365)
+  getConditionsHistory: async (locationId: number, days = 30): Promise<ConditionsHistory> => {
+    const response = await api.get(`/locations/${locationId}/conditions-history`, {
+      params: { days }
+    });
+    return response.data;
+  },
+
   // Get weather for all locations (optionally filtered by area)
   getAllWeather: async (areaId?: number | null): Promise<AllWeatherResponse> => {
     const params = areaId ? { area_id: areaId } : {};
@@ -71,6 +93,12 @@ export const climbActivityApi = {
     return response.data;
   },
 
+  // Get the full area hierarchy for a location in one request
+  getAreaTree: async (locationId: number): Promise<AreaTreeResponse> => {
+    const response = await api.get(`/locations/${locationId}/areas/tree`);
+    return response.data;
+  },
+
   // Get routes in an area ordered by recent activity
   getRoutesOrderedByActivity: async (locationId: number, areaId: number, limit = 200): Promise<RouteActivitySummary[]> => {
     const response = await api.get(`/climbs/location/${locationId}/areas/${areaId}/routes`, {
@@ -95,6 +123,28 @@ export const climbActivityApi = {
     return response.data;
   },
 
+  // Import a Mountain Project user's public tick list (requires sign-in). The
+  // ticks are stored under the username MP reports for the user ID.
+  syncUserTicks: async (mpUserId: number): Promise<UserTickSyncResult> => {
+    // Loaded lazily: auth.ts imports API_BASE_URL from this module
+    const { authApiClient } = await import('./auth');
+    const response = await authApiClient.post<UserTickSyncResult>('/climbs/user-ticks/sync', {
+      mp_user_id: mpUserId,
+    });
+    return response.data;
+  },
+
+  // Get which of the given routes a user has ticked
+  getUserTickedRoutes: async (username: string, routeIds: number[]): Promise<number[]> => {
+    if (routeIds.length === 0) {
+      return [];
+    }
+    const response = await api.get<UserTickedRoutesResponse>(`/climbs/users/${encodeURIComponent(username)}/ticked-routes`, {
+      params: { route_ids: routeIds.join(',') }
+    });
+    return response.data.ticked_route_ids;
+  },
+
   // Search all areas and routes in a location by name
   searchInLocation: async (locationId: number, searchQuery: string, limit = 50): Promise<SearchResult[]> => {
     const response = await api.get(`/climbs/location/${locationId}/search-all`, {
@@ -154,6 +204,38 @@ export const climbActivityApi = {
     });
     return response.data;
   },
+
+  // Get approved Kaya climbs matched to an MP route
+  getKayaMatchesForRoute: async (routeId: number, verifiedOnly = false): Promise<KayaRouteMatch[]> => {
+    const response = await api.get(`/routes/${routeId}/kaya-matches`, {
+      params: verifiedOnly ? { verified_only: true } : undefined
+    });
+    return response.data.matches;
+  },
+
+  // Get routes recently found by the new-route sweep
+  getNewRoutes: async (days = 7, limit = 20): Promise<DiscoveredRoute[]> => {
+    const response = await api.get('/routes/new', {
+      params: { days, limit }
+    });
+    return response.data.routes;
+  },
+
+  // Get the featured route of the day, optionally for one location
+  getFeaturedRoute: async (locationId?: number): Promise<FeaturedRoute> => {
+    const response = await api.get('/routes/featured', {
+      params: locationId !== undefined ? { location_id: locationId } : undefined
+    });
+    return response.data;
+  },
+
+  // Get routes similar to a route, most similar first
+  getSimilarRoutes: async (routeId: number, limit: number = 10): Promise<SimilarRoute[]> => {
+    const response = await api.get(`/routes/${routeId}/similar`, {
+      params: { limit }
+    });
+    return response.data.routes;
+  },
 };
 
 export const heatMapApi = {
@@ -61,6 +61,8 @@ export interface AreaActivityDetail {
   recent_comments: CommentSummary[];
   activity_timeline: DailyActivity[];
   top_routes: TopRouteSummary[];
+  conditions_beta?: string[]; // Tags extracted from area comments (e.g. "seeps", "morning_sun")
+  last_synced_at?: string; // ISO 8601 timestamp of the last MP sync; omitted if never synced
 }
 
 export interface RouteActivity {
@@ -135,7 +135,7 @@ export interface MoneyPermissions { can_read: boolean; can_write: boolean; is_ad
 export interface MoneyProjectResponse { project: MoneyProject; user: MoneyCurrentUser; permissions: MoneyPermissions; }
 export interface MoneySnapshot { project: MoneyProject; features: MoneyFeature[]; note_counts: Record<string, number>; primary_uploads: Record<string, MoneyUpload>; }
 export interface MoneyCragNode { feature: MoneyFeature; children?: MoneyCragNode[] | null; boulders?: MoneyCragNode[] | null; problems?: MoneyCragNode[] | null; }
-export interface MoneyCragSnapshot { project: MoneyProject; root: MoneyCragNode | null; trails?: MoneyCragNode[] | null; notes?: MoneyNote[] | null; uploads?: MoneyUpload[] | null; }
+export interface MoneyCragSnapshot { project: MoneyProject; root?: MoneyCragNode | null; trails?: MoneyCragNode[] | null; notes?: MoneyNote[] | null; uploads?: MoneyUpload[] | null; }
 export interface MoneyFeatureDetail { feature: MoneyFeature; notes: MoneyNote[] | null; uploads: MoneyUpload[] | null; }
 export interface MoneyTrashItem { id: string; title: string; feature_type: MoneyFeatureType; parent_feature_id?: string; path: string[]; deleted_at: string; updated_at: string; descendant_count: number; }
 export interface MoneyTrashResponse { items: MoneyTrashItem[]; }
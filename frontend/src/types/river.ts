@@ -5,7 +5,7 @@ export interface River {
   river_name: string;
   safe_crossing_cfs: number;
   caution_crossing_cfs: number;
-  description: string | null;
+  description?: string;
   created_at: string;
   updated_at: string;
 }
@@ -8,8 +8,44 @@ export interface Location {
   timezone: string; // IANA name (e.g. "America/Los_Angeles"); used by frontend for "Now" detection and climbing-hour windows
   created_at: string;
   updated_at: string;
+  access?: LocationAccessInfo; // Only present on the location detail endpoint
+  sync_state?: LocationSyncState; // Set on the location list/detail/nearby endpoints
+  last_synced_at?: string; // Latest route tick sync; absent until one completes
+  dry_streaks?: DryStreakOutlook; // Only present on the location detail endpoint
 }
 
+// A run of consecutive forecast days that are dry with an acceptable high
+export interface DryStreak {
+  start_date: string; // YYYY-MM-DD, local to the location
+  end_date: string; // Inclusive
+  days: number;
+  total_precipitation: number; // Inches over the whole streak
+}
+
+// Upcoming dry streaks from the cached forecast
+export interface DryStreakOutlook {
+  longest?: DryStreak; // Earliest of the longest streaks; absent if no day qualifies
+  streaks: DryStreak[]; // Every streak, soonest first
+  forecast_through: string; // Last forecast day considered
+}
+
+// Whether Mountain Project routes have been imported for a location.
+// never_synced: no routes yet; syncing: routes imported, ticks not yet synced.
+export type LocationSyncState = 'never_synced' | 'syncing' | 'synced';
+
+// Approach/access beta from the matched Kaya destination (plain text)
+export interface LocationAccessInfo {
+  short_description?: string;
+  approach?: string;
+  access_notes?: string;
+  is_access_sensitive: boolean;
+  is_closed: boolean;
+  source: 'kaya';
+  kaya_location_slug: string;
+}
+
+export type PrecipType = 'none' | 'rain' | 'snow' | 'mixed';
+
 export interface WeatherData {
   id?: number;
   location_id?: number;
@@ -17,6 +53,9 @@ export interface WeatherData {
   temperature: number;
   feels_like: number;
   precipitation: number;
+  is_raining?: boolean; // Current weather only: precipitation above threshold with a rain weather code
+  is_snowing?: boolean; // Current weather only: precipitation above threshold with a snow weather code
+  precip_type?: PrecipType; // Rain/snow split for the hour; absent when unknown
   humidity: number;
   wind_speed: number;
   wind_direction: number;
@@ -33,6 +72,30 @@ export interface DailySunTimes {
   sunset: string;  // Sunset time (ISO 8601)
 }
 
+export interface DailyWeatherSummary {
+  min_temperature: number;
+  max_temperature: number;
+  avg_temperature: number;
+  total_precipitation: number;
+  avg_humidity: number;
+  avg_wind_speed: number;
+  snow_estimate_inches: number;
+  hour_count: number; // Hours of data behind the summary
+}
+
+export interface ConditionsHistoryDay {
+  date: string;                        // YYYY-MM-DD
+  weather?: DailyWeatherSummary;       // Omitted when no weather is stored for the day
+  tick_count: number;
+  route_count: number;                 // Distinct routes ticked
+}
+
+export interface ConditionsHistory {
+  location_id: number;
+  timezone: string;             // Days are local dates in this zone
+  days: ConditionsHistoryDay[]; // Oldest first, one entry per day
+}
+
 export interface RockType {
   id: number;
   name: string;
@@ -150,6 +213,68 @@ export interface KayaAscentEntry {
   source: string;            // Always "kaya"
 }
 
+// Kaya climb matched to an MP route
+export interface KayaRouteMatch {
+  kaya_climb_id: string;     // Kaya climb slug
+  kaya_climb_name: string;
+  kaya_grade?: string;
+  kaya_area_name?: string;
+  mp_route_id: number;
+  mp_route_name: string;
+  confidence: number;        // 0-1
+  match_type: string;        // exact_name, fuzzy_name, location_name, gps_proximity, manual
+  name_similarity?: number;
+  distance_km?: number;
+  is_verified: boolean;
+}
+
+// Route recently found by the new-route sweep
+export interface DiscoveredRoute {
+  mp_route_id: number;
+  name: string;
+  rating: string;
+  route_type: string;
+  mp_area_id: number;
+  area_name: string;
+  location_id?: number;
+  location_name?: string;
+  discovered_at: string;     // ISO 8601 timestamp
+}
+
+// Route of the day: the same pick all (Pacific) day for a given scope
+export interface FeaturedRoute {
+  date: string;              // YYYY-MM-DD
+  location_id?: number;      // Requested scope (absent for all locations)
+  route: {
+    mp_route_id: number;
+    name: string;
+    rating: string;
+    route_type: string;
+    mp_area_id: number;
+    area_name: string;
+    location_id: number;
+    mp_rating?: number;      // Star rating (0-4)
+    recent_ticks: number;    // Ticks in the last 30 days
+  };
+  recent_ticks: ClimbHistoryEntry[];
+  drying_status: BoulderDryingStatus | null;
+}
+
+// Route recommended alongside another, most similar first
+export interface SimilarRoute {
+  mp_route_id: number;
+  name: string;
+  rating: string;
+  route_type: string;
+  mp_area_id: number;
+  area_name: string;
+  location_id?: number;
+  mp_rating?: number;        // Star rating (0-4)
+  distance_km?: number;      // From the reference route (absent without GPS)
+  tick_count: number;        // Ticks in the last year
+  score: number;             // Similarity (0-1)
+}
+
 // Unified climb history entry that can be either MP or Kaya
 export type UnifiedClimbEntry = ClimbHistoryEntry | (Omit<KayaAscentEntry, 'route_grade' | 'kaya_ascent_id' | 'kaya_climb_slug'> & {
   route_rating: string;
@@ -171,6 +296,29 @@ export interface AreaActivitySummary {
   drying_stats?: AreaDryingStats; // Area-level drying statistics
 }
 
+export interface AreaTreeNode extends AreaActivitySummary {
+  subareas: AreaTreeNode[];
+}
+
+export interface AreaTreeResponse {
+  location_id: number;
+  areas: AreaTreeNode[];
+  count: number;
+}
+
+export interface UserTickSyncResult {
+  user_name: string;
+  fetched: number;
+  imported: number;              // Includes ticks that were already stored
+  skipped_unknown_route: number; // Ticks on routes that are not synced
+  skipped_invalid: number;       // Unparseable or future dates
+}
+
+export interface UserTickedRoutesResponse {
+  user_name: string;
+  ticked_route_ids: number[];
+}
+
 export interface RouteActivitySummary {
   mp_route_id: number;
   name: string;
@@ -181,6 +329,8 @@ export interface RouteActivitySummary {
   recent_ticks?: ClimbHistoryEntry[];
   days_since_climb: number;
   latest_source?: 'mp' | 'kaya'; // Which source has the most recent activity (for merged entries)
+  conditions_beta?: string[];    // Tags extracted from route comments (e.g. "seeps", "morning_sun")
+  last_tick_sync_at?: string;    // ISO 8601 timestamp of the last MP tick sync; omitted if never synced
 }
 
 // Kaya ascent summary (simplified for route lists)
@@ -207,6 +357,7 @@ export interface UnifiedRouteActivitySummary {
   mp_route_id?: number;
   mp_area_id?: number;
   most_recent_tick?: ClimbHistoryEntry;
+  conditions_beta?: string[]; // Tags extracted from MP route comments
   
   // Kaya-specific (null for MP)
   kaya_climb_slug?: string;
@@ -256,6 +407,21 @@ export interface WeatherForecast {
   rock_temperature_status?: RockTemperatureStatus; // Rock surface temperature, friction, and condensation (current/today, ~24h hourly)
   last_climbed_info?: LastClimbedInfo; // DEPRECATED: Most recent climb (use climb_history instead)
   climb_history?: ClimbHistoryEntry[]; // Recent climb history at this location (from Mountain Project)
+  alerts?: WeatherAlert[]; // Active weather alerts (US National Weather Service; omitted when none)
+}
+
+export type WeatherAlertSeverity = 'Extreme' | 'Severe' | 'Moderate' | 'Minor' | 'Unknown';
+
+export interface WeatherAlert {
+  id: string;
+  event: string; // e.g. "Severe Thunderstorm Watch"
+  severity: WeatherAlertSeverity; // Severe/Extreme downgrade today_condition
+  urgency: string;
+  headline?: string;
+  description?: string;
+  onset?: string; // ISO 8601 timestamp
+  expires?: string; // ISO 8601 timestamp
+  source: string; // e.g. "nws"
 }
 
 export interface AllWeatherResponse {
@@ -292,6 +458,7 @@ export interface DryingForecastPeriod {
 
 export interface BoulderDryingStatus {
   mp_route_id: number;
+  source?: 'profiled' | 'estimated'; // 'estimated' when the route has no drying profile yet
   is_wet: boolean;
   is_safe: boolean;
   hours_until_dry: number;
@@ -303,9 +470,23 @@ export interface BoulderDryingStatus {
   tree_coverage_percent: number;
   rock_type: string;
   aspect: string; // N, NE, E, SE, S, SW, W, NW
+  aspect_source?: 'computed' | 'comment'; // 'comment' when taken from sun beta in route comments; omitted for fallback aspects
   latitude: number;
   longitude: number;
   forecast?: DryingForecastPeriod[]; // 6-day dry/wet forecast
+  shade_clock?: ShadeClock; // Today's sun/shade timeline (single-route detail only)
+}
+
+// Heuristic sun/shade timeline for a face over one day, from its aspect and
+// the sun's path. Ignores terrain and trees.
+export interface ShadeClock {
+  date: string; // YYYY-MM-DD in the location's timezone
+  aspect: string;
+  sunrise: string;
+  sunset: string;
+  sun_intervals: { start: string; end: string }[];
+  sun_hours: number;
+  summary: string; // e.g. "Shaded until 1pm, then sun"
 }
 
 export interface AreaDryingStats {